@@ -5,6 +5,8 @@ import (
 
 	"github.com/teabranch/matlas-cli/cmd/atlas/alerts"
 	"github.com/teabranch/matlas-cli/cmd/atlas/clusters"
+	"github.com/teabranch/matlas-cli/cmd/atlas/federation"
+	"github.com/teabranch/matlas-cli/cmd/atlas/integrations"
 	"github.com/teabranch/matlas-cli/cmd/atlas/network"
 	networkcontainers "github.com/teabranch/matlas-cli/cmd/atlas/network-containers"
 	networkpeering "github.com/teabranch/matlas-cli/cmd/atlas/network-peering"
@@ -34,6 +36,8 @@ func NewAtlasCmd() *cobra.Command {
 	cmd.AddCommand(search.NewSearchCmd())
 	cmd.AddCommand(alerts.NewAlertsCmd())
 	cmd.AddCommand(alerts.NewAlertConfigurationsCmd())
+	cmd.AddCommand(federation.NewFederationCmd())
+	cmd.AddCommand(integrations.NewIntegrationsCmd())
 
 	return cmd
 }
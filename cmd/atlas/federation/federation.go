@@ -0,0 +1,481 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+
+	"github.com/teabranch/matlas-cli/internal/cli"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/services/atlas"
+	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/ui"
+	"github.com/teabranch/matlas-cli/internal/validation"
+)
+
+// NewFederationCmd creates the federation command with its subcommands
+func NewFederationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "federation",
+		Aliases: []string{"federated-auth"},
+		Short:   "Manage federated authentication",
+		Long: `Inspect federation settings and OIDC identity providers, and manage the role
+mappings that grant identity provider groups Atlas organization and project roles.
+
+Use FederationSettings and RoleMapping kinds with 'matlas infra' to manage these declaratively.`,
+	}
+
+	cmd.AddCommand(newGetCmd())
+	cmd.AddCommand(newIdentityProvidersCmd())
+	cmd.AddCommand(newRoleMappingsCmd())
+
+	return cmd
+}
+
+func newGetCmd() *cobra.Command {
+	var orgID string
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the federation settings of an organization",
+		Long:  "Show the federation settings the organization is connected to, including federated domains",
+		Example: `  # Show federation settings
+  matlas atlas federation get --org-id 5f1d7f4b3a1e2c0012345678`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGetSettings(cmd, orgID)
+		},
+	}
+
+	cmd.Flags().StringVar(&orgID, "org-id", "", "Organization ID (can be set via ATLAS_ORG_ID env var)")
+
+	return cmd
+}
+
+func newIdentityProvidersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "identity-providers",
+		Aliases: []string{"idps"},
+		Short:   "Inspect OIDC identity providers",
+	}
+
+	var orgID string
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List OIDC identity providers",
+		Long:    "List the OIDC identity providers of the federation the organization is connected to",
+		Example: `  # List identity providers
+  matlas atlas federation identity-providers list --org-id 5f1d7f4b3a1e2c0012345678`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListIdentityProviders(cmd, orgID)
+		},
+	}
+	listCmd.Flags().StringVar(&orgID, "org-id", "", "Organization ID (can be set via ATLAS_ORG_ID env var)")
+
+	cmd.AddCommand(listCmd)
+	return cmd
+}
+
+func newRoleMappingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "role-mappings",
+		Aliases: []string{"role-mapping"},
+		Short:   "Manage role mappings",
+		Long:    "Map identity provider groups to Atlas organization and project roles",
+	}
+
+	cmd.AddCommand(newRoleMappingsListCmd())
+	cmd.AddCommand(newRoleMappingsCreateCmd())
+	cmd.AddCommand(newRoleMappingsDeleteCmd())
+
+	return cmd
+}
+
+func newRoleMappingsListCmd() *cobra.Command {
+	var orgID string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List role mappings",
+		Long:    "List the role mappings of a connected organization",
+		Example: `  # List role mappings
+  matlas atlas federation role-mappings list --org-id 5f1d7f4b3a1e2c0012345678`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListRoleMappings(cmd, orgID)
+		},
+	}
+
+	cmd.Flags().StringVar(&orgID, "org-id", "", "Organization ID (can be set via ATLAS_ORG_ID env var)")
+
+	return cmd
+}
+
+func newRoleMappingsCreateCmd() *cobra.Command {
+	var orgID string
+	var orgRoles []string
+	var projectRoles []string
+
+	cmd := &cobra.Command{
+		Use:   "create <external-group>",
+		Short: "Map an identity provider group to Atlas roles",
+		Long: `Create a role mapping that grants members of an identity provider group
+organization roles (ORG_*) and project roles (GROUP_*).`,
+		Args: cobra.ExactArgs(1),
+		Example: `  # Grant the okta-dbas group org membership and ownership of one project
+  matlas atlas federation role-mappings create okta-dbas --org-id 5f1d7f4b3a1e2c0012345678 \
+    --org-role ORG_MEMBER --project-role GROUP_OWNER:6a1d7f4b3a1e2c0012345678`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreateRoleMapping(cmd, orgID, args[0], orgRoles, projectRoles)
+		},
+	}
+
+	cmd.Flags().StringVar(&orgID, "org-id", "", "Organization ID (can be set via ATLAS_ORG_ID env var)")
+	cmd.Flags().StringSliceVar(&orgRoles, "org-role", []string{}, "Organization role to grant (e.g., ORG_MEMBER); repeatable")
+	cmd.Flags().StringSliceVar(&projectRoles, "project-role", []string{}, "Project role to grant as ROLE:PROJECT_ID (e.g., GROUP_READ_ONLY:6a1d...); repeatable")
+
+	return cmd
+}
+
+func newRoleMappingsDeleteCmd() *cobra.Command {
+	var orgID string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:     "delete <external-group>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a role mapping",
+		Long:    "Delete the role mapping of an identity provider group, revoking the roles it granted",
+		Args:    cobra.ExactArgs(1),
+		Example: `  # Delete the role mapping of a group
+  matlas atlas federation role-mappings delete okta-legacy --org-id 5f1d7f4b3a1e2c0012345678 --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeleteRoleMapping(cmd, orgID, args[0], yes)
+		},
+	}
+
+	cmd.Flags().StringVar(&orgID, "org-id", "", "Organization ID (can be set via ATLAS_ORG_ID env var)")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func runGetSettings(cmd *cobra.Command, orgID string) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	orgID = cfg.ResolveOrgID(orgID)
+	if err := validation.ValidateOrganizationID(orgID); err != nil {
+		return cli.FormatValidationError("org-id", orgID, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner(fmt.Sprintf("Fetching federation settings for organization '%s'...", orgID))
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	settings, err := atlas.NewFederationService(client).GetSettings(ctx, orgID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch federation settings")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner("Federation settings retrieved successfully")
+
+	formatter := output.NewFormatter(cfg.Output, os.Stdout)
+	return formatter.Format(settings)
+}
+
+func runListIdentityProviders(cmd *cobra.Command, orgID string) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	orgID = cfg.ResolveOrgID(orgID)
+	if err := validation.ValidateOrganizationID(orgID); err != nil {
+		return cli.FormatValidationError("org-id", orgID, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner("Fetching identity providers...")
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	service := atlas.NewFederationService(client)
+	federationSettingsID, err := resolveFederationSettingsID(ctx, service, orgID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch federation settings")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	providers, err := service.ListIdentityProviders(ctx, federationSettingsID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch identity providers")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner("Identity providers retrieved successfully")
+
+	formatter := output.NewFormatter(cfg.Output, os.Stdout)
+	return output.FormatList(formatter, providers,
+		[]string{"ID", "DISPLAY NAME", "TYPE", "ISSUER", "AUTHORIZATION"},
+		func(item interface{}) []string {
+			idp := item.(admin.FederationIdentityProvider)
+			return []string{
+				idp.GetId(),
+				idp.GetDisplayName(),
+				idp.GetIdpType(),
+				idp.GetIssuerUri(),
+				idp.GetAuthorizationType(),
+			}
+		})
+}
+
+func runListRoleMappings(cmd *cobra.Command, orgID string) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	orgID = cfg.ResolveOrgID(orgID)
+	if err := validation.ValidateOrganizationID(orgID); err != nil {
+		return cli.FormatValidationError("org-id", orgID, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner("Fetching role mappings...")
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	service := atlas.NewFederationService(client)
+	federationSettingsID, err := resolveFederationSettingsID(ctx, service, orgID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch federation settings")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	mappings, err := service.ListRoleMappings(ctx, federationSettingsID, orgID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch role mappings")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner("Role mappings retrieved successfully")
+
+	formatter := output.NewFormatter(cfg.Output, os.Stdout)
+	return output.FormatList(formatter, mappings,
+		[]string{"ID", "EXTERNAL GROUP", "ROLES"},
+		func(item interface{}) []string {
+			mapping := item.(admin.AuthFederationRoleMapping)
+			spec := atlas.ConvertRoleMappingFromAtlas(mapping, orgID)
+			roles := make([]string, 0, len(spec.RoleAssignments))
+			for _, ra := range spec.RoleAssignments {
+				if ra.ProjectID != "" {
+					roles = append(roles, ra.Role+"@"+ra.ProjectID)
+				} else {
+					roles = append(roles, ra.Role)
+				}
+			}
+			return []string{mapping.GetId(), mapping.ExternalGroupName, strings.Join(roles, ", ")}
+		})
+}
+
+func runCreateRoleMapping(cmd *cobra.Command, orgID, externalGroup string, orgRoles, projectRoles []string) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	orgID = cfg.ResolveOrgID(orgID)
+	if err := validation.ValidateOrganizationID(orgID); err != nil {
+		return cli.FormatValidationError("org-id", orgID, err.Error())
+	}
+	if strings.TrimSpace(externalGroup) == "" {
+		return cli.FormatValidationError("external-group", externalGroup, "external group name cannot be empty")
+	}
+
+	assignments, err := parseRoleAssignments(orgRoles, projectRoles)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner(fmt.Sprintf("Creating role mapping for group '%s'...", externalGroup))
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	service := atlas.NewFederationService(client)
+	federationSettingsID, err := resolveFederationSettingsID(ctx, service, orgID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch federation settings")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	spec := &types.RoleMappingSpec{OrgID: orgID, ExternalGroupName: externalGroup, RoleAssignments: assignments}
+	mapping, err := service.CreateRoleMapping(ctx, federationSettingsID, spec)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to create role mapping")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner("")
+
+	formatter := output.NewCreateResultFormatter(cfg.Output, os.Stdout)
+	return formatter.FormatCreateResult(mapping, "role mapping")
+}
+
+func runDeleteRoleMapping(cmd *cobra.Command, orgID, externalGroup string, yes bool) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	orgID = cfg.ResolveOrgID(orgID)
+	if err := validation.ValidateOrganizationID(orgID); err != nil {
+		return cli.FormatValidationError("org-id", orgID, err.Error())
+	}
+	if strings.TrimSpace(externalGroup) == "" {
+		return cli.FormatValidationError("external-group", externalGroup, "external group name cannot be empty")
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	if !yes {
+		confirmPrompt := ui.NewConfirmationPrompt(false, false)
+		confirmed, err := confirmPrompt.Confirm(fmt.Sprintf("Are you sure you want to delete the role mapping for group '%s'? Members will lose the roles it grants.", externalGroup))
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Role mapping deletion cancelled.")
+			return nil
+		}
+	}
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner(fmt.Sprintf("Deleting role mapping for group '%s'...", externalGroup))
+
+	service := atlas.NewFederationService(client)
+	federationSettingsID, err := resolveFederationSettingsID(ctx, service, orgID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch federation settings")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	mappings, err := service.ListRoleMappings(ctx, federationSettingsID, orgID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch role mappings")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	mappingID := ""
+	for _, mapping := range mappings {
+		if mapping.ExternalGroupName == externalGroup {
+			mappingID = mapping.GetId()
+			break
+		}
+	}
+	if mappingID == "" {
+		progress.StopSpinnerWithError("Role mapping not found")
+		return fmt.Errorf("no role mapping found for group '%s' in organization '%s'", externalGroup, orgID)
+	}
+
+	if err := service.DeleteRoleMapping(ctx, federationSettingsID, mappingID, orgID); err != nil {
+		progress.StopSpinnerWithError("Failed to delete role mapping")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner(fmt.Sprintf("Role mapping for group '%s' deleted successfully", externalGroup))
+	return nil
+}
+
+// resolveFederationSettingsID returns the ID of the federation the organization is connected to
+func resolveFederationSettingsID(ctx context.Context, service *atlas.FederationService, orgID string) (string, error) {
+	settings, err := service.GetSettings(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+	if settings.GetId() == "" {
+		return "", fmt.Errorf("organization '%s' is not connected to a federation", orgID)
+	}
+	return settings.GetId(), nil
+}
+
+// parseRoleAssignments builds role assignments from --org-role and --project-role ROLE:PROJECT_ID values
+func parseRoleAssignments(orgRoles, projectRoles []string) ([]types.RoleAssignmentConfig, error) {
+	var assignments []types.RoleAssignmentConfig
+	for _, role := range orgRoles {
+		role = strings.ToUpper(strings.TrimSpace(role))
+		if !strings.HasPrefix(role, "ORG_") {
+			return nil, cli.FormatValidationError("org-role", role, "organization roles must start with ORG_")
+		}
+		assignments = append(assignments, types.RoleAssignmentConfig{Role: role})
+	}
+	for _, value := range projectRoles {
+		role, projectID, found := strings.Cut(value, ":")
+		role = strings.ToUpper(strings.TrimSpace(role))
+		if !found || !strings.HasPrefix(role, "GROUP_") {
+			return nil, cli.FormatValidationError("project-role", value, "project roles must be in the form GROUP_<ROLE>:<project-id>")
+		}
+		if err := validation.ValidateProjectID(projectID); err != nil {
+			return nil, cli.FormatValidationError("project-role", value, err.Error())
+		}
+		assignments = append(assignments, types.RoleAssignmentConfig{Role: role, ProjectID: projectID})
+	}
+	if len(assignments) == 0 {
+		return nil, fmt.Errorf("at least one --org-role or --project-role must be specified")
+	}
+	return assignments, nil
+}
@@ -0,0 +1,65 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFederationCmd(t *testing.T) {
+	cmd := NewFederationCmd()
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "federation", cmd.Use)
+
+	commandNames := make([]string, 0, len(cmd.Commands()))
+	for _, subcmd := range cmd.Commands() {
+		commandNames = append(commandNames, subcmd.Use)
+	}
+	assert.Contains(t, commandNames, "get")
+	assert.Contains(t, commandNames, "identity-providers")
+	assert.Contains(t, commandNames, "role-mappings")
+}
+
+func TestNewRoleMappingsCmd(t *testing.T) {
+	cmd := newRoleMappingsCmd()
+
+	require.NotNil(t, cmd)
+	commandNames := make([]string, 0, len(cmd.Commands()))
+	for _, subcmd := range cmd.Commands() {
+		commandNames = append(commandNames, subcmd.Use)
+	}
+	assert.Contains(t, commandNames, "list")
+	assert.Contains(t, commandNames, "create <external-group>")
+	assert.Contains(t, commandNames, "delete <external-group>")
+
+	createCmd := newRoleMappingsCreateCmd()
+	require.NotNil(t, createCmd.Flags().Lookup("org-id"))
+	require.NotNil(t, createCmd.Flags().Lookup("org-role"))
+	require.NotNil(t, createCmd.Flags().Lookup("project-role"))
+
+	deleteCmd := newRoleMappingsDeleteCmd()
+	require.NotNil(t, deleteCmd.Flags().Lookup("yes"))
+}
+
+func TestParseRoleAssignments(t *testing.T) {
+	assignments, err := parseRoleAssignments([]string{"org_member"}, []string{"GROUP_OWNER:6a1d7f4b3a1e2c0012345678"})
+	require.NoError(t, err)
+	require.Len(t, assignments, 2)
+	assert.Equal(t, "ORG_MEMBER", assignments[0].Role)
+	assert.Equal(t, "GROUP_OWNER", assignments[1].Role)
+	assert.Equal(t, "6a1d7f4b3a1e2c0012345678", assignments[1].ProjectID)
+
+	_, err = parseRoleAssignments(nil, nil)
+	assert.Error(t, err)
+
+	_, err = parseRoleAssignments([]string{"GROUP_OWNER"}, nil)
+	assert.Error(t, err)
+
+	_, err = parseRoleAssignments(nil, []string{"GROUP_OWNER"})
+	assert.Error(t, err)
+
+	_, err = parseRoleAssignments(nil, []string{"GROUP_OWNER:not-a-project"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,388 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/teabranch/matlas-cli/internal/cli"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/services/atlas"
+	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/ui"
+	"github.com/teabranch/matlas-cli/internal/validation"
+)
+
+// NewIntegrationsCmd creates the integrations command with its subcommands
+func NewIntegrationsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "integrations",
+		Aliases: []string{"integration", "third-party-integrations"},
+		Short:   "Manage third-party service integrations",
+		Long: `Configure the project-level third-party integrations Atlas sends alerts and metrics to:
+Datadog, PagerDuty, Slack, Microsoft Teams, Prometheus, Opsgenie, VictorOps and generic webhooks.
+
+Credentials are write-only: Atlas returns them redacted and matlas masks them in all output.
+Use the Integration kind with 'matlas infra' to manage integrations declaratively.`,
+	}
+
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newSetCmd())
+	cmd.AddCommand(newDeleteCmd())
+
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	var projectID string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List third-party integrations",
+		Long:    "List the third-party integrations configured for a project with their credentials masked",
+		Example: `  # List integrations
+  matlas atlas integrations list --project-id 507f1f77bcf86cd799439011`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListIntegrations(cmd, projectID)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID (can be set via ATLAS_PROJECT_ID env var)")
+
+	return cmd
+}
+
+func newSetCmd() *cobra.Command {
+	var projectID string
+	var spec types.IntegrationSpec
+	var enabled bool
+	var sendCollectionLatencyMetrics bool
+	var sendDatabaseMetrics bool
+
+	cmd := &cobra.Command{
+		Use:   "set <type>",
+		Short: "Create or replace a third-party integration",
+		Long: fmt.Sprintf(`Create the integration of the given type, or replace its settings if it already exists.
+Atlas allows one integration per type in a project.
+
+Supported types: %s
+
+The primary credential of the type (API key, service key, token or password) is prompted
+for when its flag is omitted, keeping it out of shell history.`, strings.Join(atlas.SupportedIntegrationTypes, ", ")),
+		Args: cobra.ExactArgs(1),
+		Example: `  # Send metrics to Datadog EU (the API key is prompted for)
+  matlas atlas integrations set datadog --project-id 507f1f77bcf86cd799439011 --region EU
+
+  # Route alerts to PagerDuty
+  matlas atlas integrations set pager-duty --project-id 507f1f77bcf86cd799439011 --service-key "$PD_SERVICE_KEY"
+
+  # Enable Prometheus HTTP service discovery
+  matlas atlas integrations set prometheus --project-id 507f1f77bcf86cd799439011 \
+    --username prom --service-discovery http
+
+  # Post alerts to a signed webhook
+  matlas atlas integrations set webhook --project-id 507f1f77bcf86cd799439011 \
+    --url https://hooks.example.com/atlas --secret "$WEBHOOK_SECRET"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spec.Type = atlas.NormalizeIntegrationType(args[0])
+			if cmd.Flags().Changed("enabled") {
+				spec.Enabled = &enabled
+			}
+			if cmd.Flags().Changed("send-collection-latency-metrics") {
+				spec.SendCollectionLatencyMetrics = &sendCollectionLatencyMetrics
+			}
+			if cmd.Flags().Changed("send-database-metrics") {
+				spec.SendDatabaseMetrics = &sendDatabaseMetrics
+			}
+			return runSetIntegration(cmd, projectID, &spec)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID (can be set via ATLAS_PROJECT_ID env var)")
+	cmd.Flags().StringVar(&spec.APIKey, "api-key", "", "API key (DATADOG, OPS_GENIE, VICTOR_OPS)")
+	cmd.Flags().StringVar(&spec.Region, "region", "", "Service region (DATADOG: US, EU, US3, US5, AP1, US1_FED; PAGER_DUTY, OPS_GENIE: US, EU)")
+	cmd.Flags().StringVar(&spec.ServiceKey, "service-key", "", "Service key (PAGER_DUTY)")
+	cmd.Flags().StringVar(&spec.APIToken, "api-token", "", "API token (SLACK)")
+	cmd.Flags().StringVar(&spec.ChannelName, "channel-name", "", "Channel name (SLACK)")
+	cmd.Flags().StringVar(&spec.TeamName, "team-name", "", "Team name (SLACK)")
+	cmd.Flags().StringVar(&spec.RoutingKey, "routing-key", "", "Routing key (VICTOR_OPS)")
+	cmd.Flags().StringVar(&spec.MicrosoftTeamsWebhookURL, "teams-webhook-url", "", "Incoming webhook URL (MICROSOFT_TEAMS)")
+	cmd.Flags().StringVar(&spec.URL, "url", "", "Endpoint URL (WEBHOOK)")
+	cmd.Flags().StringVar(&spec.Secret, "secret", "", "Signing secret (WEBHOOK)")
+	cmd.Flags().StringVar(&spec.Username, "username", "", "Username (PROMETHEUS)")
+	cmd.Flags().StringVar(&spec.Password, "password", "", "Password (PROMETHEUS)")
+	cmd.Flags().StringVar(&spec.ServiceDiscovery, "service-discovery", "", "Service discovery method: http or file (PROMETHEUS)")
+	cmd.Flags().BoolVar(&enabled, "enabled", true, "Enable the integration (PROMETHEUS)")
+	cmd.Flags().BoolVar(&sendCollectionLatencyMetrics, "send-collection-latency-metrics", false, "Send collection latency metrics (DATADOG)")
+	cmd.Flags().BoolVar(&sendDatabaseMetrics, "send-database-metrics", false, "Send database metrics (DATADOG)")
+
+	return cmd
+}
+
+func newDeleteCmd() *cobra.Command {
+	var projectID string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:     "delete <type>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a third-party integration",
+		Long:    "Remove the integration of the given type from a project",
+		Args:    cobra.ExactArgs(1),
+		Example: `  # Remove the Slack integration
+  matlas atlas integrations delete slack --project-id 507f1f77bcf86cd799439011 --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeleteIntegration(cmd, projectID, atlas.NormalizeIntegrationType(args[0]), yes)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID (can be set via ATLAS_PROJECT_ID env var)")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func runListIntegrations(cmd *cobra.Command, projectID string) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	projectID = cfg.ResolveProjectID(projectID)
+	if err := validation.ValidateProjectID(projectID); err != nil {
+		return cli.FormatValidationError("project-id", projectID, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner("Fetching third-party integrations...")
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	integrations, err := atlas.NewIntegrationsService(client).List(ctx, projectID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch third-party integrations")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner("Third-party integrations retrieved successfully")
+
+	// Atlas only partially redacts some credentials; mask everything before output
+	specs := make([]types.IntegrationSpec, 0, len(integrations))
+	for _, integration := range integrations {
+		specs = append(specs, *atlas.MaskIntegrationSecrets(atlas.ConvertIntegrationFromAtlas(integration, projectID)))
+	}
+
+	formatter := output.NewFormatter(cfg.Output, os.Stdout)
+	return output.FormatList(formatter, specs,
+		[]string{"TYPE", "SETTINGS", "CREDENTIALS"},
+		func(item interface{}) []string {
+			spec := item.(types.IntegrationSpec)
+			return []string{spec.Type, integrationSettings(&spec), integrationCredentials(&spec)}
+		})
+}
+
+func runSetIntegration(cmd *cobra.Command, projectID string, spec *types.IntegrationSpec) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	projectID = cfg.ResolveProjectID(projectID)
+	if err := validation.ValidateProjectID(projectID); err != nil {
+		return cli.FormatValidationError("project-id", projectID, err.Error())
+	}
+	if err := validation.ValidateEnum(spec.Type, "type", atlas.SupportedIntegrationTypes); err != nil {
+		return cli.FormatValidationError("type", spec.Type, err.Error())
+	}
+
+	if flag, target := primaryCredential(spec); target != nil && *target == "" {
+		secret, err := readSecret(fmt.Sprintf("Enter %s for the %s integration: ", flag, spec.Type))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", flag, err)
+		}
+		if secret == "" {
+			return cli.FormatValidationError(flag, "", fmt.Sprintf("%s is required for %s integrations", flag, spec.Type))
+		}
+		*target = secret
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner(fmt.Sprintf("Configuring %s integration...", spec.Type))
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	service := atlas.NewIntegrationsService(client)
+	existing, err := service.List(ctx, projectID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch third-party integrations")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	exists := false
+	for _, integration := range existing {
+		if strings.EqualFold(integration.GetType(), spec.Type) {
+			exists = true
+			break
+		}
+	}
+
+	if exists {
+		err = service.Update(ctx, projectID, spec)
+	} else {
+		err = service.Create(ctx, projectID, spec)
+	}
+	if err != nil {
+		progress.StopSpinnerWithError(fmt.Sprintf("Failed to configure %s integration", spec.Type))
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	action := "created"
+	if exists {
+		action = "updated"
+	}
+	progress.StopSpinner(fmt.Sprintf("%s integration %s successfully", spec.Type, action))
+
+	masked := atlas.MaskIntegrationSecrets(spec)
+	masked.ProjectName = projectID
+	if cfg.Output == config.OutputJSON || cfg.Output == config.OutputYAML {
+		return output.NewFormatter(cfg.Output, os.Stdout).Format(masked)
+	}
+	return nil
+}
+
+func runDeleteIntegration(cmd *cobra.Command, projectID, integrationType string, yes bool) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	projectID = cfg.ResolveProjectID(projectID)
+	if err := validation.ValidateProjectID(projectID); err != nil {
+		return cli.FormatValidationError("project-id", projectID, err.Error())
+	}
+	if err := validation.ValidateEnum(integrationType, "type", atlas.SupportedIntegrationTypes); err != nil {
+		return cli.FormatValidationError("type", integrationType, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	if !yes {
+		confirmPrompt := ui.NewConfirmationPrompt(false, false)
+		confirmed, err := confirmPrompt.Confirm(fmt.Sprintf("Are you sure you want to delete the %s integration? Alerts and metrics sent through it will stop.", integrationType))
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Integration deletion cancelled.")
+			return nil
+		}
+	}
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner(fmt.Sprintf("Deleting %s integration...", integrationType))
+
+	if err := atlas.NewIntegrationsService(client).Delete(ctx, projectID, integrationType); err != nil {
+		progress.StopSpinnerWithError(fmt.Sprintf("Failed to delete %s integration", integrationType))
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner(fmt.Sprintf("%s integration deleted successfully", integrationType))
+	return nil
+}
+
+// primaryCredential returns the flag name and field of the credential an integration type requires
+func primaryCredential(spec *types.IntegrationSpec) (string, *string) {
+	switch spec.Type {
+	case atlas.IntegrationTypeDatadog, atlas.IntegrationTypeOpsGenie, atlas.IntegrationTypeVictorOps:
+		return "api-key", &spec.APIKey
+	case atlas.IntegrationTypePagerDuty:
+		return "service-key", &spec.ServiceKey
+	case atlas.IntegrationTypeSlack:
+		return "api-token", &spec.APIToken
+	case atlas.IntegrationTypeMicrosoftTeams:
+		return "teams-webhook-url", &spec.MicrosoftTeamsWebhookURL
+	case atlas.IntegrationTypePrometheus:
+		return "password", &spec.Password
+	default:
+		return "", nil
+	}
+}
+
+// integrationSettings summarizes the non-secret settings of an integration
+func integrationSettings(spec *types.IntegrationSpec) string {
+	var settings []string
+	add := func(name, value string) {
+		if value != "" {
+			settings = append(settings, name+"="+value)
+		}
+	}
+	add("region", spec.Region)
+	add("channel", spec.ChannelName)
+	add("team", spec.TeamName)
+	add("username", spec.Username)
+	add("serviceDiscovery", spec.ServiceDiscovery)
+	if spec.Enabled != nil {
+		add("enabled", fmt.Sprintf("%t", *spec.Enabled))
+	}
+	return strings.Join(settings, ", ")
+}
+
+// integrationCredentials lists the (already masked) credentials of an integration
+func integrationCredentials(spec *types.IntegrationSpec) string {
+	var credentials []string
+	add := func(name, value string) {
+		if value != "" {
+			credentials = append(credentials, name+"="+value)
+		}
+	}
+	add("apiKey", spec.APIKey)
+	add("serviceKey", spec.ServiceKey)
+	add("apiToken", spec.APIToken)
+	add("routingKey", spec.RoutingKey)
+	add("webhookUrl", spec.MicrosoftTeamsWebhookURL)
+	add("url", spec.URL)
+	add("secret", spec.Secret)
+	add("password", spec.Password)
+	return strings.Join(credentials, ", ")
+}
+
+func readSecret(prompt string) (string, error) {
+	fmt.Print(prompt)
+	secret, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println() // Add newline after secret input
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,51 @@
+package integrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func TestNewIntegrationsCmd(t *testing.T) {
+	cmd := NewIntegrationsCmd()
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "integrations", cmd.Use)
+
+	commandNames := make([]string, 0, len(cmd.Commands()))
+	for _, subcmd := range cmd.Commands() {
+		commandNames = append(commandNames, subcmd.Use)
+	}
+	assert.Contains(t, commandNames, "list")
+	assert.Contains(t, commandNames, "set <type>")
+	assert.Contains(t, commandNames, "delete <type>")
+
+	setCmd := newSetCmd()
+	for _, flag := range []string{"project-id", "api-key", "region", "service-key", "api-token", "channel-name", "url", "secret", "username", "password", "service-discovery"} {
+		require.NotNil(t, setCmd.Flags().Lookup(flag), "missing flag %s", flag)
+	}
+
+	deleteCmd := newDeleteCmd()
+	require.NotNil(t, deleteCmd.Flags().Lookup("yes"))
+}
+
+func TestPrimaryCredential(t *testing.T) {
+	spec := &types.IntegrationSpec{Type: "PAGER_DUTY"}
+	flag, target := primaryCredential(spec)
+	assert.Equal(t, "service-key", flag)
+	require.NotNil(t, target)
+	*target = "key"
+	assert.Equal(t, "key", spec.ServiceKey)
+
+	_, target = primaryCredential(&types.IntegrationSpec{Type: "WEBHOOK"})
+	assert.Nil(t, target)
+}
+
+func TestIntegrationCredentials(t *testing.T) {
+	spec := &types.IntegrationSpec{Type: "WEBHOOK", URL: "https://hooks.example.com/***", Secret: "***"}
+	assert.Equal(t, "url=https://hooks.example.com/***, secret=***", integrationCredentials(spec))
+	assert.Empty(t, integrationSettings(spec))
+}
@@ -3,7 +3,12 @@ package network
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
@@ -26,6 +31,8 @@ func NewNetworkCmd() *cobra.Command {
 	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newGetCmd())
 	cmd.AddCommand(newCreateCmd())
+	cmd.AddCommand(newAddMyIPCmd())
+	cmd.AddCommand(newAuditCmd())
 	cmd.AddCommand(newDeleteCmd())
 
 	return cmd
@@ -77,15 +84,19 @@ func runListNetworkAccess(cmd *cobra.Command, projectID string) error {
 	formatter := output.NewFormatter(cfg.Output, os.Stdout)
 
 	return output.FormatList(formatter, entries,
-		[]string{"IP ADDRESS", "CIDR BLOCK", "AWS SECURITY GROUP", "COMMENT"},
+		[]string{"IP ADDRESS", "CIDR BLOCK", "AWS SECURITY GROUP", "COMMENT", "EXPIRES"},
 		func(item interface{}) []string {
 			entry := item.(admin.NetworkPermissionEntry)
 			ipAddress := getStringValue(entry.IpAddress)
 			cidrBlock := getStringValue(entry.CidrBlock)
 			awsSecurityGroup := getStringValue(entry.AwsSecurityGroup)
 			comment := getStringValue(entry.Comment)
+			expires := ""
+			if entry.DeleteAfterDate != nil {
+				expires = entry.DeleteAfterDate.UTC().Format(time.RFC3339)
+			}
 
-			return []string{ipAddress, cidrBlock, awsSecurityGroup, comment}
+			return []string{ipAddress, cidrBlock, awsSecurityGroup, comment, expires}
 		})
 }
 
@@ -140,7 +151,7 @@ func runGetNetworkAccess(cmd *cobra.Command, projectID, ipAddress string) error
 	return formatter.Format(entry)
 }
 
-func runCreateNetworkAccess(cmd *cobra.Command, projectID, ipAddress, cidrBlock, awsSecurityGroup, comment string) error {
+func runCreateNetworkAccess(cmd *cobra.Command, projectID, ipAddress, cidrBlock, awsSecurityGroup, comment, ttl, expiresAt string) error {
 	// Get configuration first to resolve project ID if not provided
 	cfg, err := config.Load(cmd, "")
 	if err != nil {
@@ -174,6 +185,11 @@ func runCreateNetworkAccess(cmd *cobra.Command, projectID, ipAddress, cidrBlock,
 		return fmt.Errorf("only one of --ip-address, --cidr-block, or --aws-security-group can be specified")
 	}
 
+	expiry, err := resolveEntryExpiry(ttl, expiresAt)
+	if err != nil {
+		return err
+	}
+
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
 	defer cancel()
@@ -207,6 +223,7 @@ func runCreateNetworkAccess(cmd *cobra.Command, projectID, ipAddress, cidrBlock,
 	if comment != "" {
 		entry.Comment = &comment
 	}
+	entry.DeleteAfterDate = expiry
 
 	// Create the entry
 	result, err := service.Create(ctx, projectID, []admin.NetworkPermissionEntry{entry})
@@ -223,6 +240,220 @@ func runCreateNetworkAccess(cmd *cobra.Command, projectID, ipAddress, cidrBlock,
 	return formatter.FormatCreateResult(result, "network access entry")
 }
 
+func runAddMyIP(cmd *cobra.Command, projectID, ttl, comment, resolverURL string) error {
+	// Get configuration first to resolve project ID if not provided
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Resolve project ID from flag or config/env
+	projectID = cfg.ResolveProjectID(projectID)
+
+	// Validate inputs
+	if err := validation.ValidateProjectID(projectID); err != nil {
+		return cli.FormatValidationError("project-id", projectID, err.Error())
+	}
+
+	expiry, err := resolveEntryExpiry(ttl, "")
+	if err != nil {
+		return err
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	// Create progress indicator
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	resolverURL = cfg.ResolveIPResolverURL(resolverURL)
+	progress.StartSpinner(fmt.Sprintf("Detecting public IP address via %s...", resolverURL))
+
+	ipAddress, err := detectPublicIP(ctx, http.DefaultClient, resolverURL)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to detect public IP address")
+		return cli.WrapWithSuggestion(err, "Pass --resolver-url (or set ATLAS_IP_RESOLVER_URL) to use another resolver, or add the entry with 'matlas atlas network create --ip-address'")
+	}
+	progress.StopSpinner(fmt.Sprintf("Detected public IP address %s", ipAddress))
+
+	// Create Atlas client
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	service := atlas.NewNetworkAccessListsService(client)
+
+	entry := admin.NetworkPermissionEntry{IpAddress: &ipAddress, DeleteAfterDate: expiry}
+	if comment != "" {
+		entry.Comment = &comment
+	}
+
+	progress.StartSpinner(fmt.Sprintf("Creating network access entry for IP address '%s'...", ipAddress))
+	result, err := service.Create(ctx, projectID, []admin.NetworkPermissionEntry{entry})
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to create network access entry")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner("")
+
+	// Display created entry details with prettier formatting
+	formatter := output.NewCreateResultFormatter(cfg.Output, os.Stdout)
+	return formatter.FormatCreateResult(result, "network access entry")
+}
+
+func runAuditNetworkAccess(cmd *cobra.Command, projectID string, days, minPrefix int, flaggedOnly bool) error {
+	// Get configuration first to resolve project ID if not provided
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Resolve project ID from flag or config/env
+	projectID = cfg.ResolveProjectID(projectID)
+
+	// Validate inputs
+	if err := validation.ValidateProjectID(projectID); err != nil {
+		return cli.FormatValidationError("project-id", projectID, err.Error())
+	}
+	if days <= 0 {
+		return cli.FormatValidationError("days", fmt.Sprintf("%d", days), "must be a positive number of days")
+	}
+	if minPrefix < 1 || minPrefix > 32 {
+		return cli.FormatValidationError("min-prefix", fmt.Sprintf("%d", minPrefix), "must be between 1 and 32")
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	// Create progress indicator
+	verbose := cmd.Flag("verbose").Changed
+	progress := ui.NewProgressIndicator(verbose, false)
+	progress.StartSpinner("Auditing network access entries...")
+
+	// Create Atlas client
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	entries, err := atlas.NewNetworkAccessListsService(client).List(ctx, projectID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch network access entries")
+		errorFormatter := cli.NewErrorFormatter(verbose)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	clusters, err := atlas.NewClustersService(client).List(ctx, projectID)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch clusters")
+		errorFormatter := cli.NewErrorFormatter(verbose)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	// Collect successful connections from every cluster that keeps access history. Usage is
+	// only complete, and entries only reported as unused, when every cluster has history.
+	since := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	tracking := atlas.NewAccessTrackingService(client)
+	var logs []admin.MongoDBAccessLogs
+	var unavailable []string
+	for _, cluster := range clusters {
+		clusterLogs, err := tracking.ListClusterAccessLogs(ctx, projectID, cluster.GetName(), since)
+		if err != nil {
+			unavailable = append(unavailable, cluster.GetName())
+			continue
+		}
+		logs = append(logs, clusterLogs...)
+	}
+	historyAvailable := len(clusters) > 0 && len(unavailable) == 0
+
+	results := atlas.AuditNetworkAccess(entries, logs, atlas.NetworkAccessAuditOptions{
+		Since:            since,
+		MinPrefixLength:  minPrefix,
+		HistoryAvailable: historyAvailable,
+	})
+	if flaggedOnly {
+		flagged := make([]atlas.NetworkAccessAuditEntry, 0, len(results))
+		for _, r := range results {
+			if len(r.Findings) > 0 {
+				flagged = append(flagged, r)
+			}
+		}
+		results = flagged
+	}
+
+	progress.StopSpinner(fmt.Sprintf("Audited %d network access entries against %d access log records", len(entries), len(logs)))
+
+	if len(unavailable) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: access history unavailable for clusters: %s\n", strings.Join(unavailable, ", "))
+	}
+	if !historyAvailable {
+		fmt.Fprintln(os.Stderr, "Warning: access history is partial; connection counts are incomplete and unused entries are not flagged")
+	}
+
+	// Format and display output
+	formatter := output.NewFormatter(cfg.Output, os.Stdout)
+	return output.FormatList(formatter, results,
+		[]string{"ENTRY", "COMMENT", "CONNECTIONS", "LAST SEEN", "FINDINGS", "COVERED BY"},
+		func(item interface{}) []string {
+			r := item.(atlas.NetworkAccessAuditEntry)
+			lastSeen := ""
+			if r.LastSeen != nil {
+				lastSeen = r.LastSeen.Format(time.RFC3339)
+			}
+			return []string{r.Entry, r.Comment, fmt.Sprintf("%d", r.Connections), lastSeen, strings.Join(r.Findings, ","), r.CoveredBy}
+		})
+}
+
+// resolveEntryExpiry turns the --ttl and --expires-at flags into the entry's deleteAfterDate
+func resolveEntryExpiry(ttl, expiresAt string) (*time.Time, error) {
+	if ttl != "" && expiresAt != "" {
+		return nil, fmt.Errorf("only one of --ttl or --expires-at can be specified")
+	}
+	expiry, err := atlas.ResolveNetworkAccessExpiry("", expiresAt, ttl, time.Now())
+	if err != nil {
+		if ttl != "" {
+			return nil, cli.FormatValidationError("ttl", ttl, err.Error())
+		}
+		return nil, cli.FormatValidationError("expires-at", expiresAt, err.Error())
+	}
+	if expiry != nil && time.Until(*expiry) > atlas.MaxNetworkAccessTTL {
+		return nil, cli.FormatValidationError("expires-at", expiresAt, fmt.Sprintf("must be within %s from now", atlas.MaxNetworkAccessTTL))
+	}
+	return expiry, nil
+}
+
+// detectPublicIP asks the resolver, which must answer with the caller's IP as plain text,
+// for the address Atlas will see connections come from.
+func detectPublicIP(ctx context.Context, client *http.Client, resolverURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resolverURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid resolver URL %q: %w", resolverURL, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query IP resolver: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("IP resolver returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", fmt.Errorf("failed to read IP resolver response: %w", err)
+	}
+	ip := strings.TrimSpace(string(body))
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("IP resolver returned %q, which is not an IP address", ip)
+	}
+	return ip, nil
+}
+
 func runDeleteNetworkAccess(cmd *cobra.Command, projectID, ipAddress string, yes bool) error {
 	// Get configuration first to resolve project ID if not provided
 	cfg, err := config.Load(cmd, "")
@@ -351,6 +582,8 @@ func newCreateCmd() *cobra.Command {
 	var cidrBlock string
 	var awsSecurityGroup string
 	var comment string
+	var ttl string
+	var expiresAt string
 
 	cmd := &cobra.Command{
 		Use:   "create",
@@ -366,9 +599,15 @@ Only one type of access can be specified per entry.`,
   matlas atlas network create --project-id 507f1f77bcf86cd799439011 --cidr-block 192.168.1.0/24 --comment "Office subnet"
 
   # Allow access from AWS security group
-  matlas atlas network create --project-id 507f1f77bcf86cd799439011 --aws-security-group sg-12345678 --comment "Production servers"`,
+  matlas atlas network create --project-id 507f1f77bcf86cd799439011 --aws-security-group sg-12345678 --comment "Production servers"
+
+  # Give a contractor temporary access for 8 hours
+  matlas atlas network create --project-id 507f1f77bcf86cd799439011 --ip-address 203.0.113.10 --ttl 8h --comment "Contractor"
+
+  # Temporary access until a fixed time
+  matlas atlas network create --project-id 507f1f77bcf86cd799439011 --cidr-block 203.0.113.0/28 --expires-at 2026-10-20T18:00:00Z`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCreateNetworkAccess(cmd, projectID, ipAddress, cidrBlock, awsSecurityGroup, comment)
+			return runCreateNetworkAccess(cmd, projectID, ipAddress, cidrBlock, awsSecurityGroup, comment, ttl, expiresAt)
 		},
 	}
 
@@ -377,6 +616,81 @@ Only one type of access can be specified per entry.`,
 	cmd.Flags().StringVar(&cidrBlock, "cidr-block", "", "CIDR block to allow access from")
 	cmd.Flags().StringVar(&awsSecurityGroup, "aws-security-group", "", "AWS security group to allow access from")
 	cmd.Flags().StringVar(&comment, "comment", "", "Optional comment for the network access entry")
+	cmd.Flags().StringVar(&ttl, "ttl", "", "Make the entry temporary: remove it after this duration (e.g. 8h, 90m, 2d; max 7d)")
+	cmd.Flags().StringVar(&expiresAt, "expires-at", "", "Make the entry temporary: remove it at this RFC3339 time")
+
+	return cmd
+}
+
+func newAddMyIPCmd() *cobra.Command {
+	var projectID string
+	var ttl string
+	var comment string
+	var resolverURL string
+
+	cmd := &cobra.Command{
+		Use:   "add-my-ip",
+		Short: "Allow access from your current public IP",
+		Long: `Detect your current public IP address and add it to the project's network access list.
+
+The address is looked up through a resolver that answers with the caller's IP as plain text
+(default ` + config.DefaultIPResolverURL + `). Point --resolver-url, the ATLAS_IP_RESOLVER_URL
+environment variable or the ipResolverUrl config key at another resolver when that one is
+unreachable, for example behind a corporate proxy.`,
+		Example: `  # Allow your current IP for 4 hours
+  matlas atlas network add-my-ip --project-id 507f1f77bcf86cd799439011 --ttl 4h
+
+  # Use an internal resolver
+  matlas atlas network add-my-ip --project-id 507f1f77bcf86cd799439011 --ttl 8h --resolver-url https://ip.example.internal`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAddMyIP(cmd, projectID, ttl, comment, resolverURL)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID (can be set via ATLAS_PROJECT_ID env var)")
+	cmd.Flags().StringVar(&ttl, "ttl", "", "Make the entry temporary: remove it after this duration (e.g. 4h, 90m, 2d; max 7d)")
+	cmd.Flags().StringVar(&comment, "comment", "Added by matlas add-my-ip", "Comment for the network access entry")
+	cmd.Flags().StringVar(&resolverURL, "resolver-url", "", "URL that returns the caller's public IP as plain text (can be set via ATLAS_IP_RESOLVER_URL env var)")
+
+	return cmd
+}
+
+func newAuditCmd() *cobra.Command {
+	var projectID string
+	var days int
+	var minPrefix int
+	var flaggedOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit network access entries for unused and overly broad rules",
+		Long: `Audit the project's network access list against database access history.
+
+Each entry is reported with the number of successful connections from its addresses and when
+it was last seen. Entries are flagged with:
+
+  UNUSED         no successful connection within --days
+  OPEN_TO_WORLD  0.0.0.0/0 or ::/0
+  BROAD_CIDR     an IPv4 CIDR wider than --min-prefix
+  REDUNDANT      already covered by a wider CIDR (see COVERED BY)
+
+Access history is read from every cluster in the project. Clusters that do not keep access
+history (for example free and flex tiers) are skipped, and AWS security group entries cannot be
+matched against connections.`,
+		Example: `  # Audit the last 7 days of connections
+  matlas atlas network audit --project-id 507f1f77bcf86cd799439011
+
+  # Only show flagged entries from the last 3 days, as JSON
+  matlas atlas network audit --project-id 507f1f77bcf86cd799439011 --days 3 --flagged-only --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuditNetworkAccess(cmd, projectID, days, minPrefix, flaggedOnly)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID (can be set via ATLAS_PROJECT_ID env var)")
+	cmd.Flags().IntVar(&days, "days", 7, "Flag entries with no successful connections in this many days")
+	cmd.Flags().IntVar(&minPrefix, "min-prefix", atlas.DefaultAuditMinPrefixLength, "Flag IPv4 CIDRs with a shorter prefix than this as overly broad")
+	cmd.Flags().BoolVar(&flaggedOnly, "flagged-only", false, "Only show entries with findings")
 
 	return cmd
 }
@@ -1,6 +1,9 @@
 package network
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -25,6 +28,8 @@ func TestNewNetworkCmd(t *testing.T) {
 	assert.Contains(t, commandNames, "list")
 	assert.Contains(t, commandNames, "get <ip-address>")
 	assert.Contains(t, commandNames, "create")
+	assert.Contains(t, commandNames, "add-my-ip")
+	assert.Contains(t, commandNames, "audit")
 	assert.Contains(t, commandNames, "delete <ip-address>")
 }
 
@@ -76,6 +81,79 @@ func TestNewCreateCmd(t *testing.T) {
 
 	commentFlag := cmd.Flags().Lookup("comment")
 	require.NotNil(t, commentFlag)
+
+	require.NotNil(t, cmd.Flags().Lookup("ttl"))
+	require.NotNil(t, cmd.Flags().Lookup("expires-at"))
+}
+
+func TestNewAddMyIPCmd(t *testing.T) {
+	cmd := newAddMyIPCmd()
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "add-my-ip", cmd.Use)
+	assert.Equal(t, "Allow access from your current public IP", cmd.Short)
+
+	for _, name := range []string{"project-id", "ttl", "comment", "resolver-url"} {
+		require.NotNil(t, cmd.Flags().Lookup(name), "missing flag %s", name)
+	}
+}
+
+func TestNewAuditCmd(t *testing.T) {
+	cmd := newAuditCmd()
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "audit", cmd.Use)
+
+	daysFlag := cmd.Flags().Lookup("days")
+	require.NotNil(t, daysFlag)
+	assert.Equal(t, "7", daysFlag.DefValue)
+
+	minPrefixFlag := cmd.Flags().Lookup("min-prefix")
+	require.NotNil(t, minPrefixFlag)
+	assert.Equal(t, "16", minPrefixFlag.DefValue)
+
+	require.NotNil(t, cmd.Flags().Lookup("flagged-only"))
+	require.NotNil(t, cmd.Flags().Lookup("project-id"))
+}
+
+func TestDetectPublicIP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ip":
+			_, _ = w.Write([]byte("203.0.113.7\n"))
+		case "/html":
+			_, _ = w.Write([]byte("<html>blocked</html>"))
+		default:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ip, err := detectPublicIP(context.Background(), server.Client(), server.URL+"/ip")
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.7", ip)
+
+	_, err = detectPublicIP(context.Background(), server.Client(), server.URL+"/html")
+	assert.Error(t, err)
+
+	_, err = detectPublicIP(context.Background(), server.Client(), server.URL+"/down")
+	assert.Error(t, err)
+}
+
+func TestResolveEntryExpiry(t *testing.T) {
+	expiry, err := resolveEntryExpiry("", "")
+	require.NoError(t, err)
+	assert.Nil(t, expiry)
+
+	expiry, err = resolveEntryExpiry("4h", "")
+	require.NoError(t, err)
+	require.NotNil(t, expiry)
+
+	_, err = resolveEntryExpiry("4h", "2026-10-20T00:00:00Z")
+	assert.Error(t, err)
+
+	_, err = resolveEntryExpiry("30d", "")
+	assert.Error(t, err)
 }
 
 func TestNewDeleteCmd(t *testing.T) {
@@ -0,0 +1,193 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+
+	"github.com/teabranch/matlas-cli/internal/cli"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/fileutil"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/services/atlas"
+	"github.com/teabranch/matlas-cli/internal/ui"
+	"github.com/teabranch/matlas-cli/internal/validation"
+)
+
+func newCertsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "certs",
+		Aliases: []string{"cert", "certificates"},
+		Short:   "Manage X.509 certificates for database users",
+		Long: `Issue and list Atlas-managed X.509 certificates for database users.
+
+Certificates can only be issued for users created with --auth-type X509_MANAGED.
+The private key is returned once at issue time and cannot be retrieved again.`,
+	}
+
+	cmd.AddCommand(newCertsCreateCmd())
+	cmd.AddCommand(newCertsListCmd())
+
+	return cmd
+}
+
+func newCertsCreateCmd() *cobra.Command {
+	var projectID string
+	var months int
+	var outputFile string
+
+	cmd := &cobra.Command{
+		Use:   "create <username>",
+		Short: "Issue an X.509 certificate for a database user",
+		Long: `Issue a new Atlas-managed X.509 certificate for a database user.
+
+The PEM bundle holds the certificate and its private key, so it is only written to
+--output-file, with owner-only permissions, and never printed.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  # Issue a certificate valid for 3 months and save it
+  matlas atlas users certs create app-cert --project-id 507f1f77bcf86cd799439011 --months 3 --output-file ./app-cert.pem`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreateUserCert(cmd, projectID, args[0], months, outputFile)
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID (can be set via ATLAS_PROJECT_ID env var)")
+	cmd.Flags().IntVar(&months, "months", 3, fmt.Sprintf("Months until the certificate expires (1-%d)", atlas.MaxX509CertificateMonths))
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "File the PEM bundle is written to (required)")
+	mustMarkFlagRequired(cmd, "output-file")
+
+	return cmd
+}
+
+func newCertsListCmd() *cobra.Command {
+	var projectID string
+
+	cmd := &cobra.Command{
+		Use:     "list <username>",
+		Aliases: []string{"ls"},
+		Short:   "List X.509 certificates for a database user",
+		Long:    "List the unexpired Atlas-managed X.509 certificates issued for a database user",
+		Args:    cobra.ExactArgs(1),
+		Example: `  # List certificates for a user
+  matlas atlas users certs list app-cert --project-id 507f1f77bcf86cd799439011`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListUserCerts(cmd, projectID, args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Project ID (can be set via ATLAS_PROJECT_ID env var)")
+
+	return cmd
+}
+
+func runCreateUserCert(cmd *cobra.Command, projectID, username string, months int, outputFile string) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	projectID = cfg.ResolveProjectID(projectID)
+
+	if err := validation.ValidateProjectID(projectID); err != nil {
+		return cli.FormatValidationError("project-id", projectID, err.Error())
+	}
+
+	if err := validation.ValidateUsername(username); err != nil {
+		return cli.FormatValidationError("username", username, err.Error())
+	}
+
+	if outputFile == "" {
+		return cli.FormatValidationError("output-file", outputFile, "a file is required; the private key is never printed")
+	}
+
+	if months < 1 || months > atlas.MaxX509CertificateMonths {
+		return cli.FormatValidationError("months", strconv.Itoa(months), fmt.Sprintf("must be between 1 and %d", atlas.MaxX509CertificateMonths))
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner(fmt.Sprintf("Issuing X.509 certificate for '%s'...", username))
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	service := atlas.NewX509AuthenticationService(client)
+
+	pem, err := service.CreateUserCertificate(ctx, projectID, username, months)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to issue X.509 certificate")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	if err := fileutil.NewSecureFileWriter().WriteFile(outputFile, []byte(pem)); err != nil {
+		progress.StopSpinnerWithError("Failed to write certificate")
+		return fmt.Errorf("failed to write certificate to %s: %w", outputFile, err)
+	}
+
+	progress.StopSpinner(fmt.Sprintf("Certificate for '%s' written to %s (expires in %d months)", username, outputFile, months))
+	return nil
+}
+
+func runListUserCerts(cmd *cobra.Command, projectID, username string) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	projectID = cfg.ResolveProjectID(projectID)
+
+	if err := validation.ValidateProjectID(projectID); err != nil {
+		return cli.FormatValidationError("project-id", projectID, err.Error())
+	}
+
+	if err := validation.ValidateUsername(username); err != nil {
+		return cli.FormatValidationError("username", username, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+	progress.StartSpinner(fmt.Sprintf("Fetching certificates for '%s'...", username))
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to initialize Atlas client")
+		return cli.WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+
+	service := atlas.NewX509AuthenticationService(client)
+
+	certs, err := service.ListUserCertificates(ctx, projectID, username)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to fetch certificates")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner("Certificates retrieved successfully")
+
+	formatter := output.NewFormatter(cfg.Output, os.Stdout)
+	return output.FormatList(formatter, certs,
+		[]string{"ID", "SUBJECT", "CREATED", "EXPIRES"},
+		func(item interface{}) []string {
+			cert := item.(admin.UserCert)
+			return []string{
+				strconv.FormatInt(cert.GetId(), 10),
+				cert.GetSubject(),
+				cert.GetCreatedAt().Format(time.RFC3339),
+				cert.GetNotAfter().Format(time.RFC3339),
+			}
+		})
+}
@@ -37,6 +37,7 @@ For database-specific users with custom roles, use 'matlas database users' comma
 	cmd.AddCommand(newCreateCmd())
 	cmd.AddCommand(newUpdateCmd())
 	cmd.AddCommand(newDeleteCmd())
+	cmd.AddCommand(newCertsCmd())
 
 	return cmd
 }
@@ -109,6 +110,7 @@ func newCreateCmd() *cobra.Command {
 	var password string
 	var roles []string
 	var showPassword bool
+	var authType string
 
 	cmd := &cobra.Command{
 		Use:   "create",
@@ -121,9 +123,15 @@ func newCreateCmd() *cobra.Command {
   matlas atlas users create --project-id 507f1f77bcf86cd799439011 --username myuser --database-name admin --roles read@mydb,readWrite@anotherdb
 
   # Create user with password prompt and show the password
-  matlas atlas users create --project-id 507f1f77bcf86cd799439011 --username myuser --database-name admin --roles readWriteAnyDatabase@admin --show-password`,
+  matlas atlas users create --project-id 507f1f77bcf86cd799439011 --username myuser --database-name admin --roles readWriteAnyDatabase@admin --show-password
+
+  # Create a user that authenticates with an Atlas-managed X.509 certificate
+  matlas atlas users create --project-id 507f1f77bcf86cd799439011 --username app-cert --auth-type X509_MANAGED --roles readWrite@mydb
+
+  # Create an LDAP group mapped to Atlas roles
+  matlas atlas users create --project-id 507f1f77bcf86cd799439011 --username "CN=dbas,OU=groups,DC=example,DC=com" --auth-type LDAP_GROUP --roles atlasAdmin@admin`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCreateUser(cmd, projectID, databaseName, username, password, roles, showPassword)
+			return runCreateUser(cmd, projectID, databaseName, username, password, roles, showPassword, authType)
 		},
 	}
 
@@ -133,6 +141,7 @@ func newCreateCmd() *cobra.Command {
 	cmd.Flags().StringVar(&password, "password", "", "Database password (will prompt if not provided)")
 	cmd.Flags().StringSliceVar(&roles, "roles", []string{}, "Database roles in format roleName@databaseName (required)")
 	cmd.Flags().BoolVar(&showPassword, "show-password", false, "Print the user password after creation")
+	cmd.Flags().StringVar(&authType, "auth-type", validation.AuthTypeSCRAM, "Authentication type: "+strings.Join(validation.ValidAuthTypes, ", "))
 
 	mustMarkFlagRequired(cmd, "username")
 	mustMarkFlagRequired(cmd, "roles")
@@ -299,7 +308,7 @@ func runGetUser(cmd *cobra.Command, projectID, databaseName, username string) er
 		return cli.FormatValidationError("project-id", projectID, err.Error())
 	}
 
-	if err := validation.ValidateUsername(username); err != nil {
+	if err := validation.ValidateDatabaseUsername(username); err != nil {
 		return cli.FormatValidationError("username", username, err.Error())
 	}
 
@@ -336,7 +345,7 @@ func runGetUser(cmd *cobra.Command, projectID, databaseName, username string) er
 	return formatter.Format(user)
 }
 
-func runCreateUser(cmd *cobra.Command, projectID, databaseName, username, password string, roles []string, showPassword bool) error {
+func runCreateUser(cmd *cobra.Command, projectID, databaseName, username, password string, roles []string, showPassword bool, authType string) error {
 	// Get configuration first to resolve project ID if not provided
 	cfg, err := config.Load(cmd, "")
 	if err != nil {
@@ -351,7 +360,18 @@ func runCreateUser(cmd *cobra.Command, projectID, databaseName, username, passwo
 		return cli.FormatValidationError("project-id", projectID, err.Error())
 	}
 
-	if err := validation.ValidateUsername(username); err != nil {
+	if err := validation.ValidateAuthType(authType); err != nil {
+		return cli.FormatValidationError("auth-type", authType, err.Error())
+	}
+	authType = validation.NormalizeAuthType(authType)
+	externalAuth := validation.IsExternalAuthType(authType)
+
+	// External users live in the auth database Atlas requires for their type
+	if externalAuth && !cmd.Flags().Changed("database-name") {
+		databaseName = validation.AuthDatabaseForType(authType)
+	}
+
+	if err := validation.ValidateUsernameForAuthType(username, authType); err != nil {
 		return cli.FormatValidationError("username", username, err.Error())
 	}
 
@@ -359,6 +379,15 @@ func runCreateUser(cmd *cobra.Command, projectID, databaseName, username, passwo
 		return cli.FormatValidationError("database-name", databaseName, "database name cannot be empty")
 	}
 
+	if externalAuth {
+		if password != "" {
+			return cli.FormatValidationError("password", "", fmt.Sprintf("password is not supported for %s users", authType))
+		}
+		if expected := validation.AuthDatabaseForType(authType); databaseName != expected {
+			return cli.FormatValidationError("database-name", databaseName, fmt.Sprintf("%s users must use authentication database %q", authType, expected))
+		}
+	}
+
 	if len(roles) == 0 {
 		return cli.FormatValidationError("roles", "", "at least one role must be specified")
 	}
@@ -369,8 +398,8 @@ func runCreateUser(cmd *cobra.Command, projectID, databaseName, username, passwo
 		return cli.FormatValidationError("roles", strings.Join(roles, ","), err.Error())
 	}
 
-	// Handle password (prompt if not provided)
-	if password == "" {
+	// Handle password (prompt if not provided); external users have no password
+	if password == "" && !externalAuth {
 		password, err = readPassword("Enter password for database user: ")
 		if err != nil {
 			return fmt.Errorf("failed to read password: %w", err)
@@ -401,9 +430,15 @@ func runCreateUser(cmd *cobra.Command, projectID, databaseName, username, passwo
 	user := &admin.CloudDatabaseUser{
 		Username:     username,
 		DatabaseName: databaseName,
-		Password:     admin.PtrString(password),
 		Roles:        &parsedRoles,
 	}
+	if password != "" {
+		user.Password = admin.PtrString(password)
+	}
+	if err := atlas.ApplyAuthType(user, authType); err != nil {
+		progress.StopSpinnerWithError("Failed to create database user")
+		return cli.FormatValidationError("auth-type", authType, err.Error())
+	}
 
 	// Create the user
 	createdUser, err := service.Create(ctx, projectID, user)
@@ -417,7 +452,7 @@ func runCreateUser(cmd *cobra.Command, projectID, databaseName, username, passwo
 
 	// Display created user details with prettier formatting
 	formatter := output.NewCreateResultFormatter(cfg.Output, os.Stdout)
-	if showPassword {
+	if showPassword && !externalAuth {
 		return formatter.FormatCreateResultWithPassword(createdUser, "database user", password)
 	}
 	return formatter.FormatCreateResult(createdUser, "database user")
@@ -438,7 +473,7 @@ func runUpdateUser(cmd *cobra.Command, projectID, databaseName, username, passwo
 		return cli.FormatValidationError("project-id", projectID, err.Error())
 	}
 
-	if err := validation.ValidateUsername(username); err != nil {
+	if err := validation.ValidateDatabaseUsername(username); err != nil {
 		return cli.FormatValidationError("username", username, err.Error())
 	}
 
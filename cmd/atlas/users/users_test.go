@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -30,6 +31,7 @@ func TestNewUsersCmd(t *testing.T) {
 	assert.Contains(t, commandNames, "create")
 	assert.Contains(t, commandNames, "update <username>")
 	assert.Contains(t, commandNames, "delete <username>")
+	assert.Contains(t, commandNames, "certs")
 }
 
 func TestNewCreateCmd(t *testing.T) {
@@ -55,6 +57,32 @@ func TestNewCreateCmd(t *testing.T) {
 	databaseFlag := cmd.Flags().Lookup("database-name")
 	require.NotNil(t, databaseFlag)
 	assert.Equal(t, "admin", databaseFlag.DefValue)
+
+	authTypeFlag := cmd.Flags().Lookup("auth-type")
+	require.NotNil(t, authTypeFlag)
+	assert.Equal(t, "SCRAM", authTypeFlag.DefValue)
+}
+
+func TestNewCertsCmd(t *testing.T) {
+	cmd := newCertsCmd()
+
+	require.NotNil(t, cmd)
+	assert.Equal(t, "certs", cmd.Use)
+
+	createCmd, _, err := cmd.Find([]string{"create"})
+	require.NoError(t, err)
+	assert.Equal(t, "create <username>", createCmd.Use)
+
+	monthsFlag := createCmd.Flags().Lookup("months")
+	require.NotNil(t, monthsFlag)
+	assert.Equal(t, "3", monthsFlag.DefValue)
+	outputFlag := createCmd.Flags().Lookup("output-file")
+	require.NotNil(t, outputFlag)
+	assert.Equal(t, []string{"true"}, outputFlag.Annotations[cobra.BashCompOneRequiredFlag], "the private key is never printed to stdout")
+
+	listCmd, _, err := cmd.Find([]string{"list"})
+	require.NoError(t, err)
+	assert.Equal(t, "list <username>", listCmd.Use)
 }
 
 func TestNewUpdateCmd(t *testing.T) {
@@ -2,12 +2,14 @@ package collections
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
 
 	"github.com/teabranch/matlas-cli/internal/cli"
 	"github.com/teabranch/matlas-cli/internal/config"
@@ -32,6 +34,7 @@ func NewCollectionsCmd() *cobra.Command {
 	cmd.AddCommand(newCreateCmd())
 	cmd.AddCommand(newDeleteCmd())
 	cmd.AddCommand(newIndexesCmd())
+	cmd.AddCommand(newValidatorCmd())
 
 	return cmd
 }
@@ -90,20 +93,58 @@ func newCreateCmd() *cobra.Command {
 	var capped bool
 	var size int64
 	var maxDocuments int64
+	var timeField, metaField, granularity string
+	var bucketMaxSpan, bucketRounding, expireAfter int64
+	var clustered bool
 
 	cmd := &cobra.Command{
 		Use:   "create <collection-name>",
 		Short: "Create a collection",
-		Long:  "Create a new MongoDB collection",
-		Args:  cobra.ExactArgs(1),
+		Long: `Create a new MongoDB collection.
+
+--timeseries-time-field creates a time series collection, which stores measurements in
+buckets per --meta-field value. Buckets are sized by --granularity (seconds, minutes or
+hours; default seconds), or by --bucket-max-span-seconds and --bucket-rounding-seconds,
+which must be equal. --clustered-index creates a collection clustered on _id.
+--expire-after removes documents of time series and clustered collections once they are
+older than the given number of seconds. These options can't be changed after creation,
+except --expire-after.`,
+		Args: cobra.ExactArgs(1),
 		Example: `  # Create a regular collection
   matlas database collections create mycollection --database mydb --connection-string "mongodb+srv://..."
 
   # Create a capped collection
-  matlas database collections create mycappedcoll --database mydb --connection-string "mongodb+srv://..." --capped --size 1048576 --max-documents 1000`,
+  matlas database collections create mycappedcoll --database mydb --connection-string "mongodb+srv://..." --capped --size 1048576 --max-documents 1000
+
+  # Create a time series collection of sensor readings kept for 30 days
+  matlas database collections create readings --timeseries-time-field ts --meta-field sensor --granularity minutes --expire-after 2592000 --database metrics --connection-string "mongodb+srv://..."
+
+  # Create a clustered collection
+  matlas database collections create events --clustered-index --database mydb --connection-string "mongodb+srv://..."`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			collectionName := args[0]
-			return runCreateCollection(cmd, connectionString, clusterName, projectID, databaseName, collectionName, capped, size, maxDocuments)
+			opts := &types.CollectionOptions{Capped: capped, Size: size, Max: maxDocuments}
+			if timeField != "" {
+				opts.TimeSeries = &types.TimeSeriesOptions{TimeField: timeField, MetaField: metaField, Granularity: granularity}
+				if cmd.Flags().Changed("bucket-max-span-seconds") {
+					opts.TimeSeries.BucketMaxSpanSeconds = &bucketMaxSpan
+				}
+				if cmd.Flags().Changed("bucket-rounding-seconds") {
+					opts.TimeSeries.BucketRoundingSeconds = &bucketRounding
+				}
+			} else if metaField != "" || granularity != "" || cmd.Flags().Changed("bucket-max-span-seconds") || cmd.Flags().Changed("bucket-rounding-seconds") {
+				return fmt.Errorf("--meta-field, --granularity and bucket settings require --timeseries-time-field")
+			}
+			if clustered {
+				opts.ClusteredIndex = &types.ClusteredIndexOptions{}
+			}
+			if cmd.Flags().Changed("expire-after") {
+				opts.ExpireAfterSeconds = &expireAfter
+			}
+			if err := types.ValidateCollectionOptions(opts); err != nil {
+				return err
+			}
+			return runCreateCollection(cmd, connectionString, clusterName, projectID, databaseName, collectionName, opts)
 		},
 	}
 
@@ -114,6 +155,13 @@ func newCreateCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&capped, "capped", false, "Create a capped collection")
 	cmd.Flags().Int64Var(&size, "size", 0, "Maximum size in bytes for capped collection")
 	cmd.Flags().Int64Var(&maxDocuments, "max-documents", 0, "Maximum number of documents for capped collection")
+	cmd.Flags().StringVar(&timeField, "timeseries-time-field", "", "Field holding the date of each measurement; creates a time series collection")
+	cmd.Flags().StringVar(&metaField, "meta-field", "", "Field identifying the source of a measurement, such as a sensor ID (time series)")
+	cmd.Flags().StringVar(&granularity, "granularity", "", "Interval between measurements of a source: seconds, minutes or hours (time series)")
+	cmd.Flags().Int64Var(&bucketMaxSpan, "bucket-max-span-seconds", 0, "Maximum time span of a bucket, instead of --granularity (time series)")
+	cmd.Flags().Int64Var(&bucketRounding, "bucket-rounding-seconds", 0, "Rounding of bucket start times; must equal --bucket-max-span-seconds (time series)")
+	cmd.Flags().Int64Var(&expireAfter, "expire-after", 0, "Seconds after which documents are removed (time series or clustered collections)")
+	cmd.Flags().BoolVar(&clustered, "clustered-index", false, "Create a collection clustered on _id")
 
 	// At least one connection method is required
 	mustMarkFlagsOneRequired(cmd, "connection-string", "cluster")
@@ -175,6 +223,7 @@ func newIndexesCmd() *cobra.Command {
 	cmd.AddCommand(newListIndexesCmd())
 	cmd.AddCommand(newCreateIndexCmd())
 	cmd.AddCommand(newDeleteIndexCmd())
+	cmd.AddCommand(newIndexBuildStatusCmd())
 
 	return cmd
 }
@@ -222,24 +271,45 @@ including index names, keys, and options.`,
 	return cmd
 }
 
+// createIndexFlags holds the index options of the create command
+type createIndexFlags struct {
+	name               string
+	unique             bool
+	sparse             bool
+	background         bool
+	hidden             bool
+	partialFilter      string
+	expireAfterSeconds int32
+	collation          string
+	wildcardProjection string
+	commitQuorum       string
+	watch              bool
+}
+
 func newCreateIndexCmd() *cobra.Command {
 	var connectionString string
 	var clusterName string
 	var projectID string
 	var databaseName string
 	var collectionName string
-	var indexName string
-	var unique bool
-	var sparse bool
-	var background bool
+	flags := &createIndexFlags{}
 
 	cmd := &cobra.Command{
 		Use:   "create <field:order> [field:order...]",
 		Short: "Create an index",
 		Long: `Create an index on a collection.
 
-Specify index keys as field:order pairs where order is 1 for ascending or -1 for descending.
-Multiple fields can be specified to create a compound index.`,
+Specify index keys as field:order pairs where order is 1 for ascending or -1 for descending,
+or field:type where type is text, 2dsphere, 2d or hashed. Multiple fields create a compound
+index with the keys in the order given. Use $** or path.$** as the field for a wildcard index.
+
+Document options (--partial-filter, --collation, --wildcard-projection) take MongoDB
+Extended JSON.
+
+--watch shows the progress of the build from $currentOp and waits for it regardless of
+--timeout. --commit-quorum sets how many replica set members must finish building before
+the index is committed. Pressing Ctrl+C while the index builds drops it, which aborts
+the build on every member.`,
 		Args: cobra.MinimumNArgs(1),
 		Example: `  # Create a simple ascending index
   matlas database collections indexes create username:1 --database mydb --collection users --connection-string "mongodb+srv://..."
@@ -248,9 +318,26 @@ Multiple fields can be specified to create a compound index.`,
   matlas database collections indexes create category:1 createdAt:-1 --database mydb --collection posts --connection-string "mongodb+srv://..."
 
   # Create a unique index with a custom name
-  matlas database collections indexes create email:1 --database mydb --collection users --connection-string "mongodb+srv://..." --unique --name email_unique_idx`,
+  matlas database collections indexes create email:1 --database mydb --collection users --connection-string "mongodb+srv://..." --unique --name email_unique_idx
+
+  # Create text, geospatial and hashed indexes
+  matlas database collections indexes create title:text body:text --database mydb --collection posts --connection-string "mongodb+srv://..."
+  matlas database collections indexes create location:2dsphere --database mydb --collection places --connection-string "mongodb+srv://..."
+  matlas database collections indexes create userId:hashed --database mydb --collection events --connection-string "mongodb+srv://..."
+
+  # Create a TTL index that expires sessions after an hour
+  matlas database collections indexes create createdAt:1 --expire-after-seconds 3600 --database mydb --collection sessions --connection-string "mongodb+srv://..."
+
+  # Create a partial, case-insensitive index
+  matlas database collections indexes create email:1 --partial-filter '{"active": true}' --collation '{"locale": "en", "strength": 2}' --database mydb --collection users --connection-string "mongodb+srv://..."
+
+  # Create a wildcard index on all fields except one
+  matlas database collections indexes create '$**:1' --wildcard-projection '{"payload": 0}' --database mydb --collection events --connection-string "mongodb+srv://..."
+
+  # Build a large index, showing progress, once a majority of members has built it
+  matlas database collections indexes create customerId:1 createdAt:-1 --watch --commit-quorum majority --database shop --collection orders --connection-string "mongodb+srv://..."`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCreateIndex(cmd, connectionString, clusterName, projectID, databaseName, collectionName, args, indexName, unique, sparse, background)
+			return runCreateIndex(cmd, connectionString, clusterName, projectID, databaseName, collectionName, args, flags)
 		},
 	}
 
@@ -259,10 +346,17 @@ Multiple fields can be specified to create a compound index.`,
 	cmd.Flags().StringVar(&projectID, "project-id", "", "Atlas project ID (used with --cluster)")
 	cmd.Flags().StringVar(&databaseName, "database", "", "Database name (required)")
 	cmd.Flags().StringVar(&collectionName, "collection", "", "Collection name (required)")
-	cmd.Flags().StringVar(&indexName, "name", "", "Custom index name")
-	cmd.Flags().BoolVar(&unique, "unique", false, "Create a unique index")
-	cmd.Flags().BoolVar(&sparse, "sparse", false, "Create a sparse index")
-	cmd.Flags().BoolVar(&background, "background", false, "Create index in background")
+	cmd.Flags().StringVar(&flags.name, "name", "", "Custom index name")
+	cmd.Flags().BoolVar(&flags.unique, "unique", false, "Create a unique index")
+	cmd.Flags().BoolVar(&flags.sparse, "sparse", false, "Create a sparse index")
+	cmd.Flags().BoolVar(&flags.background, "background", false, "Create index in background")
+	cmd.Flags().BoolVar(&flags.hidden, "hidden", false, "Create the index hidden from the query planner")
+	cmd.Flags().StringVar(&flags.partialFilter, "partial-filter", "", "Only index documents matching this filter (JSON)")
+	cmd.Flags().Int32Var(&flags.expireAfterSeconds, "expire-after-seconds", 0, "Create a TTL index that deletes documents this many seconds after the indexed date")
+	cmd.Flags().StringVar(&flags.collation, "collation", "", "Index collation (JSON), for example '{\"locale\": \"en\", \"strength\": 2}'")
+	cmd.Flags().StringVar(&flags.wildcardProjection, "wildcard-projection", "", "Fields to include (1) or exclude (0) from a $** wildcard index (JSON)")
+	cmd.Flags().StringVar(&flags.commitQuorum, "commit-quorum", "", "Members that must finish the build before it commits: a number, majority, votingMembers or a replica set tag")
+	cmd.Flags().BoolVar(&flags.watch, "watch", false, "Show build progress and wait for the build to finish")
 
 	// At least one connection method is required
 	mustMarkFlagsOneRequired(cmd, "connection-string", "cluster")
@@ -273,6 +367,50 @@ Multiple fields can be specified to create a compound index.`,
 	return cmd
 }
 
+// indexOptions converts the flags into index options
+func (f *createIndexFlags) indexOptions(cmd *cobra.Command) (*types.IndexOptions, error) {
+	opts := &types.IndexOptions{
+		Name:         f.name,
+		Unique:       f.unique,
+		Sparse:       f.sparse,
+		Background:   f.background,
+		Hidden:       f.hidden,
+		CommitQuorum: f.commitQuorum,
+	}
+	if cmd.Flags().Changed("expire-after-seconds") {
+		seconds := f.expireAfterSeconds
+		opts.ExpireAfterSeconds = &seconds
+	}
+
+	var err error
+	if opts.PartialFilterExpression, err = parseDocumentFlag("partial-filter", f.partialFilter); err != nil {
+		return nil, err
+	}
+	if opts.WildcardProjection, err = parseDocumentFlag("wildcard-projection", f.wildcardProjection); err != nil {
+		return nil, err
+	}
+	if f.collation != "" {
+		collation := &types.IndexCollation{}
+		if err := json.Unmarshal([]byte(f.collation), collation); err != nil {
+			return nil, fmt.Errorf("invalid --collation: %w", err)
+		}
+		opts.Collation = collation
+	}
+	return opts, nil
+}
+
+// parseDocumentFlag parses a flag holding a MongoDB Extended JSON document
+func parseDocumentFlag(name, value string) (map[string]interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var doc bson.M
+	if err := bson.UnmarshalExtJSON([]byte(value), false, &doc); err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", name, err)
+	}
+	return doc, nil
+}
+
 func newDeleteIndexCmd() *cobra.Command {
 	var connectionString string
 	var clusterName string
@@ -317,6 +455,44 @@ Make sure you understand the impact before deleting production indexes.`,
 	return cmd
 }
 
+func newIndexBuildStatusCmd() *cobra.Command {
+	var connectionString string
+	var clusterName string
+	var projectID string
+	var databaseName string
+	var collectionName string
+
+	cmd := &cobra.Command{
+		Use:     "build-status",
+		Aliases: []string{"builds"},
+		Short:   "List index builds in progress",
+		Long: `List the index builds in progress on a cluster.
+
+Builds are read from $currentOp, which requires the inprog privilege (for example the
+clusterMonitor role). Each build shows its current phase and the progress of that phase.`,
+		Example: `  # List every index build on a cluster
+  matlas database collections indexes build-status --cluster MyCluster --project-id 507f1f77bcf86cd799439011
+
+  # List the builds of one collection
+  matlas database collections indexes build-status --connection-string "mongodb+srv://..." --database shop --collection orders`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIndexBuildStatus(cmd, connectionString, clusterName, projectID, databaseName, collectionName)
+		},
+	}
+
+	cmd.Flags().StringVar(&connectionString, "connection-string", "", "MongoDB connection string")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "Atlas cluster name (requires --project-id)")
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Atlas project ID (used with --cluster)")
+	cmd.Flags().StringVar(&databaseName, "database", "", "Only list builds in this database")
+	cmd.Flags().StringVar(&collectionName, "collection", "", "Only list builds on this collection (requires --database)")
+
+	// At least one connection method is required
+	mustMarkFlagsOneRequired(cmd, "connection-string", "cluster")
+	mustMarkFlagsRequiredTogether(cmd, "cluster", "project-id")
+
+	return cmd
+}
+
 // Implementation functions
 
 func runListCollections(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName string, paginationFlags *cli.PaginationFlags) error {
@@ -402,7 +578,7 @@ func runListCollections(cmd *cobra.Command, connectionString, clusterName, proje
 	formatter := output.NewFormatter(cfg.Output, os.Stdout)
 
 	return output.FormatList(formatter, collections,
-		[]string{"NAME", "TYPE", "COUNT", "SIZE", "AVG_OBJ_SIZE"},
+		[]string{"NAME", "TYPE", "COUNT", "SIZE", "AVG_OBJ_SIZE", "OPTIONS"},
 		func(item interface{}) []string {
 			coll := item.(types.CollectionInfo)
 			sizeStr := fmt.Sprintf("%.2f MB", float64(coll.Info.Size)/(1024*1024))
@@ -414,11 +590,46 @@ func runListCollections(cmd *cobra.Command, connectionString, clusterName, proje
 				fmt.Sprintf("%d", coll.Info.Count),
 				sizeStr,
 				avgSizeStr,
+				collectionOptionsSummary(coll.Options),
 			}
 		})
 }
 
-func runCreateCollection(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName string, capped bool, size, maxDocuments int64) error {
+// collectionOptionsSummary describes the capped, time series and clustered options of a
+// collection in one line
+func collectionOptionsSummary(raw map[string]interface{}) string {
+	opts, err := types.ParseCollectionOptions(raw)
+	if err != nil {
+		return ""
+	}
+	var parts []string
+	if opts.Capped {
+		parts = append(parts, fmt.Sprintf("capped size=%d", opts.Size))
+		if opts.Max > 0 {
+			parts = append(parts, fmt.Sprintf("max=%d", opts.Max))
+		}
+	}
+	if ts := opts.TimeSeries; ts != nil {
+		parts = append(parts, "timeField="+ts.TimeField)
+		if ts.MetaField != "" {
+			parts = append(parts, "metaField="+ts.MetaField)
+		}
+		if ts.Granularity != "" {
+			parts = append(parts, "granularity="+ts.Granularity)
+		} else if ts.BucketMaxSpanSeconds != nil {
+			parts = append(parts, fmt.Sprintf("bucketMaxSpan=%ds", *ts.BucketMaxSpanSeconds))
+		}
+	}
+	if opts.ClusteredIndex != nil {
+		parts = append(parts, "clustered")
+	}
+	if opts.ExpireAfterSeconds != nil {
+		parts = append(parts, fmt.Sprintf("expireAfter=%ds", *opts.ExpireAfterSeconds))
+	}
+	return strings.Join(parts, " ")
+}
+
+func runCreateCollection(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName string, opts *types.CollectionOptions) error {
 	if databaseName == "" {
 		return fmt.Errorf("database name is required")
 	}
@@ -445,20 +656,6 @@ func runCreateCollection(cmd *cobra.Command, connectionString, clusterName, proj
 		return err
 	}
 
-	// Prepare collection options
-	var opts map[string]interface{}
-	if capped {
-		opts = map[string]interface{}{
-			"capped": true,
-		}
-		if size > 0 {
-			opts["size"] = size
-		}
-		if maxDocuments > 0 {
-			opts["max"] = maxDocuments
-		}
-	}
-
 	progress.StartSpinner(fmt.Sprintf("Creating collection '%s'...", collectionName))
 
 	// Create database service
@@ -471,7 +668,7 @@ func runCreateCollection(cmd *cobra.Command, connectionString, clusterName, proj
 	}()
 
 	// Create collection
-	err = dbService.CreateCollection(ctx, connInfo, databaseName, collectionName, opts)
+	err = dbService.CreateCollectionWithOptions(ctx, connInfo, databaseName, collectionName, opts)
 	if err != nil {
 		progress.StopSpinnerWithError("Failed to create collection")
 		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
@@ -597,19 +794,16 @@ func runListIndexes(cmd *cobra.Command, connectionString, clusterName, projectID
 	formatter := output.NewFormatter(cfg.Output, os.Stdout)
 
 	return output.FormatList(formatter, indexes,
-		[]string{"NAME", "KEYS", "UNIQUE", "SPARSE", "VERSION"},
+		[]string{"NAME", "KEYS", "UNIQUE", "SPARSE", "OPTIONS", "VERSION"},
 		func(item interface{}) []string {
 			index := item.(types.IndexInfo)
 
-			// Format keys
-			var keysStr string
-			if len(index.Keys) > 0 {
-				keyParts := make([]string, 0, len(index.Keys))
-				for field, order := range index.Keys {
-					keyParts = append(keyParts, fmt.Sprintf("%s:%v", field, order))
-				}
-				keysStr = strings.Join(keyParts, ", ")
+			// Format keys in index order
+			keyParts := make([]string, 0, len(index.Keys))
+			for _, key := range index.Keys {
+				keyParts = append(keyParts, key.String())
 			}
+			keysStr := strings.Join(keyParts, ", ")
 
 			uniqueStr := "false"
 			if index.Unique {
@@ -626,12 +820,34 @@ func runListIndexes(cmd *cobra.Command, connectionString, clusterName, projectID
 				keysStr,
 				uniqueStr,
 				sparseStr,
+				formatIndexOptions(index),
 				fmt.Sprintf("%d", index.Version),
 			}
 		})
 }
 
-func runCreateIndex(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName string, keySpecs []string, indexName string, unique, sparse, background bool) error {
+// formatIndexOptions summarizes the options that change what an index covers
+func formatIndexOptions(index types.IndexInfo) string {
+	var parts []string
+	if index.ExpireAfterSeconds != nil {
+		parts = append(parts, fmt.Sprintf("ttl=%ds", *index.ExpireAfterSeconds))
+	}
+	if index.PartialFilterExpression != nil {
+		parts = append(parts, "partial")
+	}
+	if index.Collation != nil {
+		parts = append(parts, "collation="+index.Collation.Locale)
+	}
+	if index.WildcardProjection != nil {
+		parts = append(parts, "projection")
+	}
+	if index.Hidden {
+		parts = append(parts, "hidden")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runCreateIndex(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName string, keySpecs []string, flags *createIndexFlags) error {
 	if databaseName == "" {
 		return fmt.Errorf("database name is required")
 	}
@@ -642,27 +858,156 @@ func runCreateIndex(cmd *cobra.Command, connectionString, clusterName, projectID
 		return fmt.Errorf("at least one index key specification is required")
 	}
 
-	// Parse key specifications
-	keys := make(map[string]int)
+	// Parse key specifications, keeping their order
+	keys := make([]types.IndexKey, 0, len(keySpecs))
 	for _, keySpec := range keySpecs {
-		parts := strings.Split(keySpec, ":")
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid key specification '%s': expected format 'field:order'", keySpec)
+		key, err := types.ParseIndexKey(keySpec)
+		if err != nil {
+			return err
 		}
+		keys = append(keys, key)
+	}
 
-		field := parts[0]
-		orderStr := parts[1]
+	opts, err := flags.indexOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if err := types.ValidateIndex(keys, opts); err != nil {
+		return err
+	}
 
-		order, err := strconv.Atoi(orderStr)
-		if err != nil {
-			return fmt.Errorf("invalid order value '%s' in key specification '%s': must be 1 or -1", orderStr, keySpec)
+	// Get configuration
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create context with timeout; a watched build is waited for however long it takes
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if flags.watch {
+		ctx, cancel = context.WithCancel(cmd.Context())
+	} else {
+		ctx, cancel = context.WithTimeout(cmd.Context(), cfg.Timeout)
+	}
+	defer cancel()
+
+	// Create progress indicator
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+
+	// Resolve connection info
+	connInfo, err := resolveConnectionInfo(ctx, cfg, connectionString, clusterName, projectID, false, "", progress)
+	if err != nil {
+		return err
+	}
+
+	// Create database service
+	logger := logging.Default()
+	dbService := database.NewService(logger)
+	defer func() {
+		if err := dbService.Close(ctx); err != nil {
+			fmt.Printf("Warning: Failed to close database service: %v\n", err)
 		}
+	}()
 
-		if order != 1 && order != -1 {
-			return fmt.Errorf("invalid order value %d in key specification '%s': must be 1 (ascending) or -1 (descending)", order, keySpec)
+	indexName := opts.Name
+	if indexName == "" {
+		indexName = types.IndexKeys(keys).DefaultName()
+	}
+
+	// Drop the index if the build is interrupted, unless it existed before
+	existing, err := dbService.ListIndexes(ctx, connInfo, databaseName, collectionName)
+	if err != nil {
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+	if handler := cli.DefaultSignalHandler(); handler != nil && !indexExists(existing, indexName) {
+		unregister := handler.RegisterScopedCleanup(func(cleanupCtx context.Context) error {
+			fmt.Fprintf(os.Stderr, "Dropping index '%s' to abort the build...\n", indexName)
+			return dbService.DropIndex(cleanupCtx, connInfo, databaseName, collectionName, indexName)
+		})
+		defer unregister()
+	}
+
+	progress.StartSpinner(fmt.Sprintf("Creating index '%s' on collection '%s.%s'...", indexName, databaseName, collectionName))
+
+	// Create index, following the build from $currentOp when watching
+	var createdIndexName string
+	if flags.watch {
+		createdIndexName, err = watchIndexBuild(ctx, dbService, connInfo, databaseName, collectionName, indexName, progress, func() (string, error) {
+			return dbService.CreateIndex(ctx, connInfo, databaseName, collectionName, keys, opts)
+		})
+	} else {
+		createdIndexName, err = dbService.CreateIndex(ctx, connInfo, databaseName, collectionName, keys, opts)
+	}
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to create index")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner(fmt.Sprintf("Index '%s' created successfully", createdIndexName))
+	return nil
+}
+
+// indexBuildPollInterval is how often --watch reads the progress of an index build
+const indexBuildPollInterval = 2 * time.Second
+
+// watchIndexBuild runs create and shows the progress of the build from $currentOp until it
+// returns. Progress is best effort: if $currentOp can't be read the spinner keeps running.
+func watchIndexBuild(ctx context.Context, dbService *database.Service, connInfo *types.ConnectionInfo, databaseName, collectionName, indexName string, progress *ui.ProgressIndicator, create func() (string, error)) (string, error) {
+	type result struct {
+		name string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		name, err := create()
+		done <- result{name, err}
+	}()
+
+	ticker := time.NewTicker(indexBuildPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case res := <-done:
+			return res.name, res.err
+		case <-ticker.C:
+			builds, err := dbService.IndexBuilds(ctx, connInfo, databaseName, collectionName)
+			if err != nil {
+				progress.PrintVerbose(fmt.Sprintf("\nCannot read build progress: %v", err))
+				ticker.Stop()
+				continue
+			}
+			for _, build := range builds {
+				if !build.Builds(indexName) || build.Total == 0 {
+					continue
+				}
+				message := fmt.Sprintf("Building index '%s'", indexName)
+				if build.Phase != "" {
+					message += ": " + build.Phase
+				}
+				progress.UpdateProgress(message, build.Percent())
+				break
+			}
 		}
+	}
+}
 
-		keys[field] = order
+// indexExists reports whether indexes contains an index with the given name
+func indexExists(indexes []types.IndexInfo, name string) bool {
+	for _, index := range indexes {
+		if index.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func runIndexBuildStatus(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName string) error {
+	if collectionName != "" && databaseName == "" {
+		return fmt.Errorf("--collection requires --database")
 	}
 
 	// Get configuration
@@ -684,22 +1029,7 @@ func runCreateIndex(cmd *cobra.Command, connectionString, clusterName, projectID
 		return err
 	}
 
-	// Prepare index options
-	opts := make(map[string]interface{})
-	if indexName != "" {
-		opts["name"] = indexName
-	}
-	if unique {
-		opts["unique"] = true
-	}
-	if sparse {
-		opts["sparse"] = true
-	}
-	if background {
-		opts["background"] = true
-	}
-
-	progress.StartSpinner(fmt.Sprintf("Creating index on collection '%s.%s'...", databaseName, collectionName))
+	progress.StartSpinner("Reading index builds in progress...")
 
 	// Create database service
 	logger := logging.Default()
@@ -710,16 +1040,37 @@ func runCreateIndex(cmd *cobra.Command, connectionString, clusterName, projectID
 		}
 	}()
 
-	// Create index
-	createdIndexName, err := dbService.CreateIndex(ctx, connInfo, databaseName, collectionName, keys, opts)
+	builds, err := dbService.IndexBuilds(ctx, connInfo, databaseName, collectionName)
 	if err != nil {
-		progress.StopSpinnerWithError("Failed to create index")
+		progress.StopSpinnerWithError("Failed to read index builds")
 		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
 		return fmt.Errorf("%s", errorFormatter.Format(err))
 	}
 
-	progress.StopSpinner(fmt.Sprintf("Index '%s' created successfully", createdIndexName))
-	return nil
+	progress.StopSpinner(fmt.Sprintf("Found %d index build(s) in progress", len(builds)))
+
+	// Format and display output
+	formatter := output.NewFormatter(cfg.Output, os.Stdout)
+
+	return output.FormatList(formatter, builds,
+		[]string{"NAMESPACE", "INDEXES", "PHASE", "PROGRESS", "RUNNING", "HOST"},
+		func(item interface{}) []string {
+			build := item.(types.IndexBuild)
+
+			progressStr := "-"
+			if build.Total > 0 {
+				progressStr = fmt.Sprintf("%.1f%% (%d/%d)", build.Percent(), build.Done, build.Total)
+			}
+
+			return []string{
+				build.Namespace,
+				strings.Join(build.Indexes, ", "),
+				build.Phase,
+				progressStr,
+				(time.Duration(build.SecsRunning) * time.Second).String(),
+				build.Host,
+			}
+		})
 }
 
 func runDeleteIndex(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName, indexName string, yes bool) error {
@@ -0,0 +1,525 @@
+package collections
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/cli"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/logging"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/ui"
+)
+
+func newValidatorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "validator",
+		Short:   "Manage collection schema validation",
+		Long:    "Show, set, test and infer the $jsonSchema validators of MongoDB collections",
+		Aliases: []string{"validation", "schema"},
+	}
+
+	cmd.AddCommand(newGetValidatorCmd())
+	cmd.AddCommand(newSetValidatorCmd())
+	cmd.AddCommand(newTestValidatorCmd())
+	cmd.AddCommand(newInferValidatorCmd())
+
+	return cmd
+}
+
+func newGetValidatorCmd() *cobra.Command {
+	var connectionString string
+	var clusterName string
+	var projectID string
+	var databaseName string
+	var collectionName string
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the validator of a collection",
+		Long: `Show the validator, validation level and validation action of a collection.
+
+JSON and YAML output can be edited and passed back to 'validator set --schema-file'.`,
+		Example: `  # Show the validator of a collection
+  matlas database collections validator get --connection-string "mongodb+srv://..." --database shop --collection orders
+
+  # Save the validator for editing
+  matlas database collections validator get --cluster MyCluster --project-id 507f1f77bcf86cd799439011 --database shop --collection orders --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGetValidator(cmd, connectionString, clusterName, projectID, databaseName, collectionName)
+		},
+	}
+
+	cmd.Flags().StringVar(&connectionString, "connection-string", "", "MongoDB connection string")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "Atlas cluster name (requires --project-id)")
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Atlas project ID (used with --cluster)")
+	cmd.Flags().StringVar(&databaseName, "database", "", "Database name (required)")
+	cmd.Flags().StringVar(&collectionName, "collection", "", "Collection name (required)")
+
+	// At least one connection method is required
+	mustMarkFlagsOneRequired(cmd, "connection-string", "cluster")
+	mustMarkFlagsRequiredTogether(cmd, "cluster", "project-id")
+	mustMarkFlagRequired(cmd, "database")
+	mustMarkFlagRequired(cmd, "collection")
+
+	return cmd
+}
+
+func newSetValidatorCmd() *cobra.Command {
+	var connectionString string
+	var clusterName string
+	var projectID string
+	var databaseName string
+	var collectionName string
+	var schemaFile string
+	var validationLevel string
+	var validationAction string
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the validator of a collection",
+		Long: `Set the validator, validation level or validation action of a collection.
+
+--schema-file reads a JSON (MongoDB Extended JSON) or YAML file holding either a bare
+$jsonSchema or a full validator such as {"$jsonSchema": {...}}. A document whose top-level
+keys are query operators is used as the validator as is; any other document is wrapped in
+$jsonSchema. Settings that are not given are left unchanged.
+
+Run 'validator test' first to see which existing documents a new validator would reject.`,
+		Example: `  # Enforce a schema
+  matlas database collections validator set --schema-file orders.schema.json --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Only warn about invalid documents while the schema is rolled out
+  matlas database collections validator set --schema-file orders.schema.yaml --validation-action warn --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Enforce the schema for inserts and for updates of valid documents only
+  matlas database collections validator set --validation-level moderate --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Turn validation off
+  matlas database collections validator set --validation-level off --database shop --collection orders --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSetValidator(cmd, connectionString, clusterName, projectID, databaseName, collectionName, schemaFile, validationLevel, validationAction)
+		},
+	}
+
+	cmd.Flags().StringVar(&connectionString, "connection-string", "", "MongoDB connection string")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "Atlas cluster name (requires --project-id)")
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Atlas project ID (used with --cluster)")
+	cmd.Flags().StringVar(&databaseName, "database", "", "Database name (required)")
+	cmd.Flags().StringVar(&collectionName, "collection", "", "Collection name (required)")
+	cmd.Flags().StringVar(&schemaFile, "schema-file", "", "JSON or YAML file holding the $jsonSchema or validator")
+	cmd.Flags().StringVar(&validationLevel, "validation-level", "", "Documents to validate: strict, moderate or off")
+	cmd.Flags().StringVar(&validationAction, "validation-action", "", "What to do with invalid documents: error or warn")
+
+	// At least one connection method is required
+	mustMarkFlagsOneRequired(cmd, "connection-string", "cluster")
+	mustMarkFlagsRequiredTogether(cmd, "cluster", "project-id")
+	mustMarkFlagRequired(cmd, "database")
+	mustMarkFlagRequired(cmd, "collection")
+	cmd.MarkFlagsOneRequired("schema-file", "validation-level", "validation-action")
+
+	return cmd
+}
+
+func newTestValidatorCmd() *cobra.Command {
+	var connectionString string
+	var clusterName string
+	var projectID string
+	var databaseName string
+	var collectionName string
+	var schemaFile string
+	var sampleSize int64
+	var limit int64
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Test a validator against existing documents",
+		Long: `Match a random sample of existing documents against a validator and report those it
+would reject.
+
+The validator is read from --schema-file, or is the current validator of the collection
+when no file is given. Use it before setting a validator or switching the validation level
+to strict. --sample 0 tests every document in the collection.`,
+		Example: `  # Test a new schema against 1000 random documents
+  matlas database collections validator test --schema-file orders.schema.json --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Test the current validator against the whole collection
+  matlas database collections validator test --sample 0 --database shop --collection orders --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTestValidator(cmd, connectionString, clusterName, projectID, databaseName, collectionName, schemaFile, sampleSize, limit)
+		},
+	}
+
+	cmd.Flags().StringVar(&connectionString, "connection-string", "", "MongoDB connection string")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "Atlas cluster name (requires --project-id)")
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Atlas project ID (used with --cluster)")
+	cmd.Flags().StringVar(&databaseName, "database", "", "Database name (required)")
+	cmd.Flags().StringVar(&collectionName, "collection", "", "Collection name (required)")
+	cmd.Flags().StringVar(&schemaFile, "schema-file", "", "JSON or YAML file holding the $jsonSchema or validator (default: the current validator)")
+	cmd.Flags().Int64Var(&sampleSize, "sample", 1000, "Number of random documents to test (0 tests every document)")
+	cmd.Flags().Int64Var(&limit, "limit", 20, "Maximum number of rejected documents to show")
+
+	// At least one connection method is required
+	mustMarkFlagsOneRequired(cmd, "connection-string", "cluster")
+	mustMarkFlagsRequiredTogether(cmd, "cluster", "project-id")
+	mustMarkFlagRequired(cmd, "database")
+	mustMarkFlagRequired(cmd, "collection")
+
+	return cmd
+}
+
+func newInferValidatorCmd() *cobra.Command {
+	var connectionString string
+	var clusterName string
+	var projectID string
+	var databaseName string
+	var collectionName string
+	var sampleSize int64
+
+	cmd := &cobra.Command{
+		Use:   "infer",
+		Short: "Draft a $jsonSchema from existing documents",
+		Long: `Draft a $jsonSchema validator from the documents of a collection.
+
+The first --sample documents are read. Each field gets the BSON types seen for it, and
+fields present in every sampled document are required. Review the draft, for example by
+running 'validator test' with it, before setting it.`,
+		Example: `  # Draft a schema from 100 documents and save it
+  matlas database collections validator infer --database shop --collection orders --connection-string "mongodb+srv://..." > orders.schema.json
+
+  # Draft from a larger sample as YAML
+  matlas database collections validator infer --sample 1000 --output yaml --database shop --collection orders --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInferValidator(cmd, connectionString, clusterName, projectID, databaseName, collectionName, sampleSize)
+		},
+	}
+
+	cmd.Flags().StringVar(&connectionString, "connection-string", "", "MongoDB connection string")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "Atlas cluster name (requires --project-id)")
+	cmd.Flags().StringVar(&projectID, "project-id", "", "Atlas project ID (used with --cluster)")
+	cmd.Flags().StringVar(&databaseName, "database", "", "Database name (required)")
+	cmd.Flags().StringVar(&collectionName, "collection", "", "Collection name (required)")
+	cmd.Flags().Int64Var(&sampleSize, "sample", 100, "Number of random documents to infer the schema from")
+
+	// At least one connection method is required
+	mustMarkFlagsOneRequired(cmd, "connection-string", "cluster")
+	mustMarkFlagsRequiredTogether(cmd, "cluster", "project-id")
+	mustMarkFlagRequired(cmd, "database")
+	mustMarkFlagRequired(cmd, "collection")
+
+	return cmd
+}
+
+func runGetValidator(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName string) error {
+	// Get configuration
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	// Create progress indicator
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+
+	// Resolve connection info
+	connInfo, err := resolveConnectionInfo(ctx, cfg, connectionString, clusterName, projectID, false, "", progress)
+	if err != nil {
+		return err
+	}
+
+	progress.StartSpinner(fmt.Sprintf("Reading validator of '%s.%s'...", databaseName, collectionName))
+
+	// Create database service
+	logger := logging.Default()
+	dbService := database.NewService(logger)
+	defer func() {
+		if err := dbService.Close(ctx); err != nil {
+			fmt.Printf("Warning: Failed to close database service: %v\n", err)
+		}
+	}()
+
+	validator, err := dbService.GetValidator(ctx, connInfo, databaseName, collectionName)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to read validator")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner("Validator retrieved")
+
+	if validator.Validator, err = output.ExtendedJSONDocument(validator.Validator); err != nil {
+		return err
+	}
+
+	switch cfg.Output {
+	case config.OutputJSON, config.OutputYAML:
+		return output.NewFormatter(cfg.Output, os.Stdout).Format(validator)
+	}
+
+	fmt.Printf("Validation level:  %s\n", validator.ValidationLevel)
+	fmt.Printf("Validation action: %s\n", validator.ValidationAction)
+	if len(validator.Validator) == 0 {
+		fmt.Println("Validator:         none")
+		return nil
+	}
+	data, err := json.MarshalIndent(validator.Validator, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode validator: %w", err)
+	}
+	fmt.Printf("Validator:\n%s\n", data)
+	return nil
+}
+
+func runSetValidator(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName, schemaFile, validationLevel, validationAction string) error {
+	opts := &types.CollectionOptions{
+		ValidationLevel:  validationLevel,
+		ValidationAction: validationAction,
+	}
+	if schemaFile != "" {
+		validator, err := loadValidatorFile(schemaFile)
+		if err != nil {
+			return err
+		}
+		opts.Validator = validator
+	}
+	if err := types.ValidateCollectionOptions(opts); err != nil {
+		return err
+	}
+
+	// Get configuration
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	// Create progress indicator
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+
+	// Resolve connection info
+	connInfo, err := resolveConnectionInfo(ctx, cfg, connectionString, clusterName, projectID, false, "", progress)
+	if err != nil {
+		return err
+	}
+
+	progress.StartSpinner(fmt.Sprintf("Setting validator of '%s.%s'...", databaseName, collectionName))
+
+	// Create database service
+	logger := logging.Default()
+	dbService := database.NewService(logger)
+	defer func() {
+		if err := dbService.Close(ctx); err != nil {
+			fmt.Printf("Warning: Failed to close database service: %v\n", err)
+		}
+	}()
+
+	if err := dbService.ModifyCollection(ctx, connInfo, databaseName, collectionName, opts); err != nil {
+		progress.StopSpinnerWithError("Failed to set validator")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner(fmt.Sprintf("Validator of '%s.%s' updated successfully", databaseName, collectionName))
+	return nil
+}
+
+func runTestValidator(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName, schemaFile string, sampleSize, limit int64) error {
+	if sampleSize < 0 {
+		return fmt.Errorf("--sample must not be negative")
+	}
+
+	var validator map[string]interface{}
+	if schemaFile != "" {
+		var err error
+		if validator, err = loadValidatorFile(schemaFile); err != nil {
+			return err
+		}
+	}
+
+	// Get configuration
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	// Create progress indicator
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+
+	// Resolve connection info
+	connInfo, err := resolveConnectionInfo(ctx, cfg, connectionString, clusterName, projectID, false, "", progress)
+	if err != nil {
+		return err
+	}
+
+	progress.StartSpinner(fmt.Sprintf("Testing validator against '%s.%s'...", databaseName, collectionName))
+
+	// Create database service
+	logger := logging.Default()
+	dbService := database.NewService(logger)
+	defer func() {
+		if err := dbService.Close(ctx); err != nil {
+			fmt.Printf("Warning: Failed to close database service: %v\n", err)
+		}
+	}()
+	docService := database.NewDocumentService(dbService, logger)
+	errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+
+	if validator == nil {
+		current, err := dbService.GetValidator(ctx, connInfo, databaseName, collectionName)
+		if err != nil {
+			progress.StopSpinnerWithError("Failed to read validator")
+			return fmt.Errorf("%s", errorFormatter.Format(err))
+		}
+		if len(current.Validator) == 0 {
+			progress.StopSpinnerWithError("No validator to test")
+			return fmt.Errorf("collection '%s.%s' has no validator; use --schema-file to test one", databaseName, collectionName)
+		}
+		validator = current.Validator
+	}
+
+	result, err := docService.TestValidator(ctx, connInfo, databaseName, collectionName, validator, sampleSize, limit)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to test validator")
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+
+	progress.StopSpinner(fmt.Sprintf("Tested %d document(s): %d would be rejected", result.Sampled, result.Rejected))
+
+	for i, doc := range result.RejectedDocuments {
+		if result.RejectedDocuments[i], err = output.ExtendedJSONDocument(doc); err != nil {
+			return err
+		}
+	}
+
+	switch cfg.Output {
+	case config.OutputJSON, config.OutputYAML:
+		return output.NewFormatter(cfg.Output, os.Stdout).Format(result)
+	}
+
+	if result.Rejected == 0 {
+		fmt.Printf("All %d sampled document(s) match the validator\n", result.Sampled)
+		return nil
+	}
+	fmt.Printf("%d of %d sampled document(s) would be rejected", result.Rejected, result.Sampled)
+	if int64(len(result.RejectedDocuments)) < result.Rejected {
+		fmt.Printf(", showing %d", len(result.RejectedDocuments))
+	}
+	fmt.Println(":")
+	for _, doc := range result.RejectedDocuments {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("failed to encode document: %w", err)
+		}
+		fmt.Println(string(data))
+	}
+	return nil
+}
+
+func runInferValidator(cmd *cobra.Command, connectionString, clusterName, projectID, databaseName, collectionName string, sampleSize int64) error {
+	if sampleSize <= 0 {
+		return fmt.Errorf("--sample must be greater than 0")
+	}
+
+	// Get configuration
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(cmd.Context(), cfg.Timeout)
+	defer cancel()
+
+	// Create progress indicator
+	progress := ui.NewProgressIndicator(cmd.Flag("verbose").Changed, false)
+
+	// Resolve connection info
+	connInfo, err := resolveConnectionInfo(ctx, cfg, connectionString, clusterName, projectID, false, "", progress)
+	if err != nil {
+		return err
+	}
+
+	progress.StartSpinner(fmt.Sprintf("Sampling documents of '%s.%s'...", databaseName, collectionName))
+
+	// Create database service
+	logger := logging.Default()
+	dbService := database.NewService(logger)
+	defer func() {
+		if err := dbService.Close(ctx); err != nil {
+			fmt.Printf("Warning: Failed to close database service: %v\n", err)
+		}
+	}()
+	docService := database.NewDocumentService(dbService, logger)
+
+	documents, err := docService.Find(ctx, connInfo, databaseName, collectionName, nil, &database.FindOptions{Sample: sampleSize})
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to sample documents")
+		errorFormatter := cli.NewErrorFormatter(cmd.Flag("verbose").Changed)
+		return fmt.Errorf("%s", errorFormatter.Format(err))
+	}
+	if len(documents) == 0 {
+		progress.StopSpinnerWithError("No documents to infer from")
+		return fmt.Errorf("collection '%s.%s' has no documents to infer a schema from", databaseName, collectionName)
+	}
+
+	progress.StopSpinner(fmt.Sprintf("Inferred schema from %d document(s)", len(documents)))
+
+	validator := map[string]interface{}{"$jsonSchema": database.InferJSONSchema(documents)}
+	if cfg.Output == config.OutputYAML {
+		return output.NewFormatter(cfg.Output, os.Stdout).Format(validator)
+	}
+	return output.NewFormatter(config.OutputJSON, os.Stdout).Format(validator)
+}
+
+// loadValidatorFile reads a validator from a JSON or YAML file. A bare schema is wrapped in
+// $jsonSchema; a document of query operators such as {"$jsonSchema": ...} is used as is.
+func loadValidatorFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // reading a user-specified schema file is intended
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	var doc map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid schema file %s: %w", path, err)
+		}
+	default:
+		var extDoc bson.M
+		if err := bson.UnmarshalExtJSON(data, false, &extDoc); err != nil {
+			return nil, fmt.Errorf("invalid schema file %s: %w", path, err)
+		}
+		doc = extDoc
+	}
+	if len(doc) == 0 {
+		return nil, fmt.Errorf("schema file %s is empty", path)
+	}
+
+	for key := range doc {
+		if strings.HasPrefix(key, "$") {
+			return doc, nil
+		}
+	}
+	return map[string]interface{}{"$jsonSchema": doc}, nil
+}
@@ -13,8 +13,11 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/teabranch/matlas-cli/cmd/database/collections"
+	"github.com/teabranch/matlas-cli/cmd/database/documents"
 	"github.com/teabranch/matlas-cli/cmd/database/roles"
+	"github.com/teabranch/matlas-cli/cmd/database/transfer"
 	"github.com/teabranch/matlas-cli/cmd/database/users"
+	"github.com/teabranch/matlas-cli/cmd/database/views"
 	"github.com/teabranch/matlas-cli/internal/cli"
 	"github.com/teabranch/matlas-cli/internal/config"
 	"github.com/teabranch/matlas-cli/internal/logging"
@@ -41,7 +44,13 @@ and collections, supporting both Atlas clusters and direct connection strings.`,
 	cmd.AddCommand(newListDatabasesCmd())
 	cmd.AddCommand(newCreateDatabaseCmd())
 	cmd.AddCommand(newDeleteDatabaseCmd())
+	cmd.AddCommand(newCompareCmd())
 	cmd.AddCommand(collections.NewCollectionsCmd())
+	cmd.AddCommand(documents.NewDocumentsCmd())
+	cmd.AddCommand(documents.NewAggregateCmd())
+	cmd.AddCommand(views.NewViewsCmd())
+	cmd.AddCommand(transfer.NewExportCmd())
+	cmd.AddCommand(transfer.NewImportCmd())
 	cmd.AddCommand(roles.NewRolesCmd())
 	cmd.AddCommand(users.NewUsersCmd())
 
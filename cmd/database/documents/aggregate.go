@@ -0,0 +1,225 @@
+package documents
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/cli"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// errDisplayLimit stops an aggregation once enough results are read for display
+var errDisplayLimit = errors.New("display limit reached")
+
+// NewAggregateCmd creates the aggregate command, which runs or explains a saved pipeline
+func NewAggregateCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var pipelinePath, explain, out string
+	var varFiles, vars []string
+	var limit int64
+	var batchSize int32
+	var allowDiskUse bool
+
+	cmd := &cobra.Command{
+		Use:   "aggregate",
+		Short: "Run or explain an aggregation pipeline",
+		Long: `Run an aggregation pipeline saved in a file on a collection.
+
+The pipeline file holds an array of stages as MongoDB Extended JSON, or as YAML when it
+ends in .yaml or .yml. Use '-' to read a JSON pipeline from stdin. ${NAME} placeholders are
+substituted before the pipeline is parsed, from --var-file, then --var, then environment
+variables; ${NAME:-default} gives a default. A placeholder without a value is an error.
+Quote placeholders that stand for strings, for example "${STATUS}".
+
+--explain shows how the server runs the pipeline instead of its results: the plan tree with
+the indexes used, collection scans and, with executionStats, the documents and keys
+examined against the documents returned. executionStats runs the pipeline, but $out and
+$merge don't write.
+
+--out writes every result to a file as NDJSON (relaxed Extended JSON, one document per
+line). Otherwise up to --limit results are shown.`,
+		Example: `  # Run a saved pipeline
+  matlas database aggregate --pipeline pipelines/revenue-by-customer.json --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Substitute parameters and write all results as NDJSON
+  matlas database aggregate --pipeline pipelines/orders-since.yaml --var SINCE=2026-01-01T00:00:00Z --var-file vars/prod.yaml --out orders.ndjson --database shop --collection orders --cluster MyCluster --project-id 507f1f77bcf86cd799439011 --use-temp-user
+
+  # Check which indexes the pipeline uses and how many documents it reads
+  matlas database aggregate --pipeline pipelines/revenue-by-customer.json --explain executionStats --database shop --collection orders --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if explain != "" && explain != types.ExplainQueryPlanner && explain != types.ExplainExecutionStats {
+				return fmt.Errorf("invalid --explain %q: use %s or %s", explain, types.ExplainQueryPlanner, types.ExplainExecutionStats)
+			}
+			if explain != "" && out != "" {
+				return fmt.Errorf("--explain and --out cannot be used together")
+			}
+			if limit < 0 {
+				return fmt.Errorf("--limit must not be negative")
+			}
+			pipeline, err := LoadPipeline(pipelinePath, varFiles, vars, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			opts := &database.AggregateOptions{AllowDiskUse: allowDiskUse, BatchSize: batchSize}
+
+			return conn.withSession(cmd, func(s *session) error {
+				switch {
+				case explain != "":
+					return runExplain(s, pipeline, explain)
+				case out != "":
+					return runAggregateToFile(s, pipeline, opts, out)
+				default:
+					return runAggregate(s, pipeline, opts, limit)
+				}
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVar(&pipelinePath, "pipeline", "", "File holding the pipeline as an Extended JSON or YAML array of stages, or '-' for stdin (required)")
+	cmd.Flags().StringSliceVar(&varFiles, "var-file", []string{}, "YAML or JSON files of pipeline variables; later files win (repeatable)")
+	cmd.Flags().StringArrayVar(&vars, "var", []string{}, "Pipeline variable as NAME=VALUE, overriding variable files (repeatable)")
+	cmd.Flags().StringVar(&explain, "explain", "", "Explain the pipeline instead of running it: queryPlanner or executionStats")
+	cmd.Flags().Lookup("explain").NoOptDefVal = types.ExplainQueryPlanner
+	cmd.Flags().StringVar(&out, "out", "", "Write all results to this file as NDJSON")
+	cmd.Flags().Int64Var(&limit, "limit", 20, "Maximum number of results to show (0 for no limit); ignored with --out")
+	cmd.Flags().Int32Var(&batchSize, "batch-size", 0, "Number of results the server returns per batch (0 for the server default)")
+	cmd.Flags().BoolVar(&allowDiskUse, "allow-disk-use", false, "Let stages such as $sort and $group use temporary files when they exceed their memory limit")
+	cli.MustMarkFlagRequired(cmd, "pipeline")
+
+	return cmd
+}
+
+func runAggregate(s *session, pipeline bson.A, opts *database.AggregateOptions, limit int64) error {
+	s.Progress.StartSpinner(fmt.Sprintf("Running aggregation on '%s'...", s.namespace()))
+	results := []bson.D{}
+	_, err := s.documents.Aggregate(s.Context, s.ConnInfo, s.database, s.collection, pipeline, opts, func(raw bson.Raw) error {
+		if limit > 0 && int64(len(results)) == limit {
+			return errDisplayLimit
+		}
+		var doc bson.D
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return fmt.Errorf("failed to decode result: %w", err)
+		}
+		results = append(results, doc)
+		return nil
+	})
+	truncated := errors.Is(err, errDisplayLimit)
+	if err != nil && !truncated {
+		return s.Fail("Aggregation failed", err)
+	}
+	if truncated {
+		s.Progress.StopSpinner(fmt.Sprintf("Showing the first %d result(s); use --limit 0 or --out for all", len(results)))
+	} else {
+		s.Progress.StopSpinner(fmt.Sprintf("Aggregation returned %d result(s)", len(results)))
+	}
+	return output.FormatDocuments(s.Formatter, results)
+}
+
+func runAggregateToFile(s *session, pipeline bson.A, opts *database.AggregateOptions, path string) error {
+	file, err := os.Create(path) //nolint:gosec // writing a user-specified output file is intended
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+	writer := bufio.NewWriter(file)
+
+	s.Progress.StartSpinner(fmt.Sprintf("Writing aggregation results of '%s' to %s...", s.namespace(), path))
+	count, err := s.documents.Aggregate(s.Context, s.ConnInfo, s.database, s.collection, pipeline, opts, func(raw bson.Raw) error {
+		return writeNDJSONLine(writer, raw)
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		return s.Fail("Aggregation failed", err)
+	}
+	s.Progress.StopSpinner(fmt.Sprintf("Wrote %d result(s) to %s", count, path))
+	return nil
+}
+
+func runExplain(s *session, pipeline bson.A, verbosity string) error {
+	s.Progress.StartSpinner(fmt.Sprintf("Explaining aggregation on '%s'...", s.namespace()))
+	explain, err := s.documents.ExplainAggregate(s.Context, s.ConnInfo, s.database, s.collection, pipeline, verbosity)
+	if err != nil {
+		return s.Fail("Failed to explain aggregation", err)
+	}
+	s.Progress.StopSpinner("Aggregation explained")
+	return output.FormatExplain(s.Formatter, database.SummarizeExplain(explain, verbosity))
+}
+
+// writeNDJSONLine writes a document as one line of relaxed Extended JSON
+func writeNDJSONLine(w io.Writer, doc bson.Raw) error {
+	data, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return fmt.Errorf("failed to encode result as Extended JSON: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+// LoadPipeline reads a pipeline file, or stdin for '-', substitutes its ${NAME}
+// placeholders and parses it
+func LoadPipeline(path string, varFiles, assignments []string, stdin io.Reader) (bson.A, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path) //nolint:gosec // reading a user-specified pipeline file is intended
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline: %w", err)
+	}
+
+	vars, err := apply.LoadVariableFiles(varFiles)
+	if err != nil {
+		return nil, err
+	}
+	flagVars, err := apply.ParseVariableAssignments(assignments)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range flagVars {
+		vars[name] = value
+	}
+
+	result := apply.NewTemplateProcessor().WithStrictMode(true).WithVariables(vars).SubstituteEnvVars(string(data))
+	if len(result.Errors) > 0 {
+		messages := make([]string, 0, len(result.Errors))
+		for _, substitutionErr := range result.Errors {
+			messages = append(messages, substitutionErr.Error())
+		}
+		return nil, fmt.Errorf("failed to substitute pipeline variables: %s", strings.Join(messages, "; "))
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	pipeline, err := database.ParsePipeline([]byte(result.Content), ext == ".yaml" || ext == ".yml")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pipelineSource(path), err)
+	}
+	return pipeline, nil
+}
+
+func pipelineSource(path string) string {
+	if path == "-" {
+		return "stdin"
+	}
+	return path
+}
@@ -0,0 +1,66 @@
+package documents
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLoadPipeline_SubstitutesVariables(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "orders.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+- $match:
+    status: "${STATUS}"
+    region: "${REGION:-eu}"
+- $limit: ${LIMIT}
+`), 0o600))
+	varFile := filepath.Join(dir, "vars.yaml")
+	require.NoError(t, os.WriteFile(varFile, []byte("STATUS: pending\nLIMIT: 5\n"), 0o600))
+
+	pipeline, err := LoadPipeline(path, []string{varFile}, []string{"STATUS=open"}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "status", Value: "open"}, {Key: "region", Value: "eu"}}}},
+		bson.D{{Key: "$limit", Value: int32(5)}},
+	}, pipeline)
+}
+
+func TestLoadPipeline_Errors(t *testing.T) {
+	_, err := LoadPipeline("-", nil, nil, strings.NewReader(`[{"$match": {"status": "${AGGREGATE_TEST_UNSET_STATUS}"}}]`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AGGREGATE_TEST_UNSET_STATUS")
+
+	_, err = LoadPipeline("-", nil, nil, strings.NewReader(`{"$match": {}}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stdin")
+
+	_, err = LoadPipeline("-", nil, []string{"no-assignment"}, strings.NewReader(`[]`))
+	assert.Error(t, err)
+}
+
+func TestWriteNDJSONLine(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{Key: "_id", Value: int32(1)}, {Key: "total", Value: 9.5}})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeNDJSONLine(&buf, raw))
+	assert.Equal(t, "{\"_id\":1,\"total\":9.5}\n", buf.String())
+}
+
+func TestNewAggregateCmd_Flags(t *testing.T) {
+	cmd := NewAggregateCmd()
+
+	assert.Equal(t, "aggregate", cmd.Use)
+	for _, name := range []string{"pipeline", "var-file", "var", "explain", "out", "limit", "allow-disk-use"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), "flag %s", name)
+	}
+	assert.Equal(t, "queryPlanner", cmd.Flags().Lookup("explain").NoOptDefVal)
+}
@@ -0,0 +1,587 @@
+package documents
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/teabranch/matlas-cli/internal/cli"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/ui"
+)
+
+// maxLineSize bounds an NDJSON line, which holds at most one 16MB BSON document
+const maxLineSize = 32 * 1024 * 1024
+
+// NewDocumentsCmd creates the documents command with all its subcommands
+func NewDocumentsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "documents",
+		Short:   "Query and modify documents",
+		Long:    "Find, insert, update, delete and count documents in MongoDB collections",
+		Aliases: []string{"docs", "doc"},
+	}
+
+	cmd.AddCommand(newFindCmd())
+	cmd.AddCommand(newGetCmd())
+	cmd.AddCommand(newInsertCmd())
+	cmd.AddCommand(newUpdateCmd())
+	cmd.AddCommand(newDeleteCmd())
+	cmd.AddCommand(newCountCmd())
+
+	return cmd
+}
+
+// connectionFlags holds the flags selecting the collection a command works on
+type connectionFlags struct {
+	cli.DatabaseConnectionFlags
+	databaseName   string
+	collectionName string
+}
+
+func (f *connectionFlags) register(cmd *cobra.Command) {
+	cli.AddDatabaseConnectionFlags(cmd, &f.DatabaseConnectionFlags)
+	cmd.Flags().StringVar(&f.databaseName, "database", "", "Database name (required)")
+	cmd.Flags().StringVar(&f.collectionName, "collection", "", "Collection name (required)")
+	cli.MustMarkFlagRequired(cmd, "database")
+	cli.MustMarkFlagRequired(cmd, "collection")
+}
+
+// session is an open connection to the collection of a command
+type session struct {
+	*cli.DatabaseSession
+	documents  *database.DocumentService
+	database   string
+	collection string
+}
+
+// withSession connects and runs fn, closing the connection and removing any temporary
+// user afterwards
+func (f *connectionFlags) withSession(cmd *cobra.Command, fn func(s *session) error) error {
+	return f.WithSession(cmd, func(s *cli.DatabaseSession) error {
+		return fn(&session{
+			DatabaseSession: s,
+			documents:       database.NewDocumentService(s.Service, s.Logger),
+			database:        f.databaseName,
+			collection:      f.collectionName,
+		})
+	})
+}
+
+func (s *session) namespace() string {
+	return s.database + "." + s.collection
+}
+
+func newFindCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var filter, projection, sort string
+	var limit, skip int64
+
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find documents",
+		Long: `Find the documents of a collection matching a filter.
+
+--filter, --projection and --sort take MongoDB Extended JSON, so ObjectIDs and dates can be
+written as {"$oid": "..."} and {"$date": "..."}. Sort keys apply in the order given. Table
+output has one column per top-level field; use --output json for complete documents.`,
+		Example: `  # Find the 20 most recent orders of a customer
+  matlas database documents find --filter '{"customerId": 42}' --sort '{"createdAt": -1}' --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Page through results, returning selected fields
+  matlas database documents find --projection '{"email": 1, "status": 1}' --skip 100 --limit 50 --database shop --collection users --connection-string "mongodb+srv://..."
+
+  # Export matching documents as JSON through a temporary user
+  matlas database documents find --filter '{"createdAt": {"$gte": {"$date": "2026-01-01T00:00:00Z"}}}' --limit 0 --output json --cluster MyCluster --project-id 507f1f77bcf86cd799439011 --use-temp-user --database shop --collection orders`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filterDoc, err := parseDocumentFlag("filter", filter)
+			if err != nil {
+				return err
+			}
+			projectionDoc, err := parseDocumentFlag("projection", projection)
+			if err != nil {
+				return err
+			}
+			sortDoc, err := parseSortFlag(sort)
+			if err != nil {
+				return err
+			}
+			if limit < 0 || skip < 0 {
+				return fmt.Errorf("--limit and --skip must not be negative")
+			}
+			opts := &database.FindOptions{Projection: projectionDoc, Sort: sortDoc, Limit: limit, Skip: skip}
+
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Finding documents in '%s'...", s.namespace()))
+				docs, err := s.documents.Find(s.Context, s.ConnInfo, s.database, s.collection, filterDoc, opts)
+				if err != nil {
+					return s.Fail("Failed to find documents", err)
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("Found %d document(s)", len(docs)))
+				return output.FormatDocuments(s.Formatter, docs)
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVar(&filter, "filter", "", "Query filter (Extended JSON)")
+	cmd.Flags().StringVar(&projection, "projection", "", "Fields to include (1) or exclude (0) (Extended JSON)")
+	cmd.Flags().StringVar(&sort, "sort", "", "Sort order, for example '{\"createdAt\": -1}' (Extended JSON)")
+	cmd.Flags().Int64Var(&limit, "limit", 20, "Maximum number of documents to return (0 for no limit)")
+	cmd.Flags().Int64Var(&skip, "skip", 0, "Number of matching documents to skip")
+
+	return cmd
+}
+
+func newGetCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var projection string
+
+	cmd := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get a document by _id",
+		Long: `Get a single document by its _id.
+
+A 24-character hex id is read as an ObjectID. Other ids are read as Extended JSON values,
+such as 42 or {"$uuid": "..."}, and otherwise as strings.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  # Get a document by ObjectID
+  matlas database documents get 65f1c2a9e4b0a1b2c3d4e5f6 --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Get a document with a numeric _id as JSON
+  matlas database documents get 42 --output json --database shop --collection counters --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectionDoc, err := parseDocumentFlag("projection", projection)
+			if err != nil {
+				return err
+			}
+			filter := map[string]interface{}{"_id": parseID(args[0])}
+
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Getting document '%s'...", args[0]))
+				docs, err := s.documents.Find(s.Context, s.ConnInfo, s.database, s.collection, filter, &database.FindOptions{Projection: projectionDoc, Limit: 1})
+				if err != nil {
+					return s.Fail("Failed to get document", err)
+				}
+				if len(docs) == 0 {
+					s.Progress.StopSpinnerWithError("Document not found")
+					return fmt.Errorf("document with _id %s not found in '%s'", args[0], s.namespace())
+				}
+				s.Progress.StopSpinner("Document retrieved")
+				return output.FormatDocument(s.Formatter, docs[0])
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVar(&projection, "projection", "", "Fields to include (1) or exclude (0) (Extended JSON)")
+
+	return cmd
+}
+
+// insertResult is the output of the insert command
+type insertResult struct {
+	Inserted    int                      `json:"inserted" yaml:"inserted"`
+	InsertedIDs []map[string]interface{} `json:"insertedIds,omitempty" yaml:"insertedIds,omitempty"`
+}
+
+func newInsertCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var file string
+	var batchSize int
+
+	cmd := &cobra.Command{
+		Use:   "insert [document]",
+		Short: "Insert documents",
+		Long: `Insert a document given as Extended JSON, or the documents of an NDJSON file.
+
+--file reads one Extended JSON document per line; blank lines are skipped and '-' reads
+standard input. Documents are inserted in order in batches of --batch-size. If a batch
+fails, the documents inserted before the failure are reported and the rest of the file is
+not inserted.`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  # Insert one document
+  matlas database documents insert '{"email": "ada@example.com", "createdAt": {"$date": "2026-10-18T09:00:00Z"}}' --database shop --collection users --connection-string "mongodb+srv://..."
+
+  # Insert the documents of an NDJSON file
+  matlas database documents insert --file users.ndjson --database shop --collection users --connection-string "mongodb+srv://..."
+
+  # Insert documents from another command
+  cat orders.ndjson | matlas database documents insert --file - --database shop --collection orders --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(args) == 0) == (file == "") {
+				return fmt.Errorf("provide either a document argument or --file")
+			}
+			if batchSize <= 0 {
+				return fmt.Errorf("--batch-size must be greater than 0")
+			}
+
+			var docs []interface{}
+			if file != "" {
+				var err error
+				if docs, err = readNDJSONFile(file, cmd.InOrStdin()); err != nil {
+					return err
+				}
+				if len(docs) == 0 {
+					return fmt.Errorf("no documents found in %s", file)
+				}
+			} else {
+				doc, err := parseDocument(args[0])
+				if err != nil {
+					return fmt.Errorf("invalid document: %w", err)
+				}
+				docs = append(docs, doc)
+			}
+
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Inserting %d document(s) into '%s'...", len(docs), s.namespace()))
+				var ids []interface{}
+				for start := 0; start < len(docs); start += batchSize {
+					end := min(start+batchSize, len(docs))
+					inserted, err := s.documents.InsertDocuments(s.Context, s.ConnInfo, s.database, s.collection, docs[start:end])
+					ids = append(ids, inserted...)
+					if err != nil {
+						return s.Fail(fmt.Sprintf("Inserted %d of %d document(s) before failing", len(ids), len(docs)), err)
+					}
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("Inserted %d document(s) into '%s'", len(ids), s.namespace()))
+
+				result := insertResult{Inserted: len(ids)}
+				// Listing thousands of ids is not useful; report them for small inserts only
+				if len(ids) <= 100 {
+					for _, id := range ids {
+						converted, err := output.ExtendedJSONDocument(bson.D{{Key: "_id", Value: id}})
+						if err != nil {
+							return err
+						}
+						result.InsertedIDs = append(result.InsertedIDs, converted)
+					}
+				}
+				if s.Config.Output == config.OutputJSON || s.Config.Output == config.OutputYAML {
+					return s.Formatter.Format(result)
+				}
+				for _, id := range result.InsertedIDs {
+					fmt.Printf("%v\n", formatID(id["_id"]))
+				}
+				return nil
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVarP(&file, "file", "f", "", "NDJSON file of documents to insert ('-' for standard input)")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 1000, "Number of documents inserted per request")
+
+	return cmd
+}
+
+func newUpdateCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var filter, update string
+	var many, upsert bool
+
+	cmd := &cobra.Command{
+		Use:   "update [id]",
+		Short: "Update documents",
+		Long: `Update the document with the given _id, or the documents matching --filter.
+
+--update takes an Extended JSON update document with operators such as $set, $inc or
+$unset; a document without operators is applied as $set. Without --many only the first
+matching document is updated.`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  # Update a document by _id
+  matlas database documents update 65f1c2a9e4b0a1b2c3d4e5f6 --update '{"$set": {"status": "shipped"}}' --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Update every matching document
+  matlas database documents update --filter '{"status": "pending"}' --update '{"$set": {"status": "cancelled"}}' --many --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Insert the document if none matches
+  matlas database documents update --filter '{"sku": "A-1"}' --update '{"$inc": {"stock": 10}}' --upsert --database shop --collection inventory --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filterDoc, err := targetFilter(args, filter)
+			if err != nil {
+				return err
+			}
+			updateDoc, err := parseDocumentFlag("update", update)
+			if err != nil {
+				return err
+			}
+
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Updating documents in '%s'...", s.namespace()))
+				result, err := s.documents.UpdateDocuments(s.Context, s.ConnInfo, s.database, s.collection, filterDoc, updateDoc, many, upsert)
+				if err != nil {
+					return s.Fail("Failed to update documents", err)
+				}
+				message := fmt.Sprintf("Matched %d and modified %d document(s)", result.Matched, result.Modified)
+				if result.UpsertedID != nil {
+					message += fmt.Sprintf(", inserted %v", formatID(result.UpsertedID))
+				}
+				s.Progress.StopSpinner(message)
+
+				if s.Config.Output == config.OutputJSON || s.Config.Output == config.OutputYAML {
+					if result.UpsertedID != nil {
+						converted, err := output.ExtendedJSONDocument(bson.D{{Key: "_id", Value: result.UpsertedID}})
+						if err != nil {
+							return err
+						}
+						result.UpsertedID = converted["_id"]
+					}
+					return s.Formatter.Format(result)
+				}
+				return nil
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVar(&filter, "filter", "", "Query filter selecting the documents to update (Extended JSON)")
+	cmd.Flags().StringVar(&update, "update", "", "Update document, for example '{\"$set\": {\"status\": \"shipped\"}}' (Extended JSON)")
+	cmd.Flags().BoolVar(&many, "many", false, "Update every matching document instead of the first")
+	cmd.Flags().BoolVar(&upsert, "upsert", false, "Insert a document when none matches")
+	cli.MustMarkFlagRequired(cmd, "update")
+
+	return cmd
+}
+
+func newDeleteCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var filter string
+	var many, yes bool
+
+	cmd := &cobra.Command{
+		Use:     "delete [id]",
+		Aliases: []string{"del", "rm", "remove"},
+		Short:   "Delete documents",
+		Long: `Delete the document with the given _id, or the documents matching --filter.
+
+Without --many only the first matching document is deleted. With --many the matching
+documents are counted and the deletion must be confirmed unless --yes is given; an empty
+filter ('{}') deletes every document of the collection.`,
+		Args: cobra.MaximumNArgs(1),
+		Example: `  # Delete a document by _id
+  matlas database documents delete 65f1c2a9e4b0a1b2c3d4e5f6 --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Delete every matching document (with confirmation)
+  matlas database documents delete --filter '{"status": "cancelled"}' --many --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Delete without confirmation in scripts
+  matlas database documents delete --filter '{"expiresAt": {"$lt": {"$date": "2026-01-01T00:00:00Z"}}}' --many --yes --database shop --collection sessions --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filterDoc, err := targetFilter(args, filter)
+			if err != nil {
+				return err
+			}
+
+			return conn.withSession(cmd, func(s *session) error {
+				if many {
+					s.Progress.StartSpinner(fmt.Sprintf("Counting matching documents in '%s'...", s.namespace()))
+					count, err := s.documents.CountDocuments(s.Context, s.ConnInfo, s.database, s.collection, filterDoc)
+					if err != nil {
+						return s.Fail("Failed to count documents", err)
+					}
+					s.Progress.StopSpinner(fmt.Sprintf("%d document(s) match", count))
+					if count == 0 {
+						fmt.Println("No documents to delete")
+						return nil
+					}
+
+					confirm := ui.NewConfirmationPrompt(yes, false)
+					confirmed, err := confirm.ConfirmDestructiveAction("delete", fmt.Sprintf("%d document(s) from '%s'", count, s.namespace()))
+					if err != nil {
+						return err
+					}
+					if !confirmed {
+						fmt.Println("Operation cancelled")
+						return nil
+					}
+				}
+
+				s.Progress.StartSpinner(fmt.Sprintf("Deleting documents from '%s'...", s.namespace()))
+				deleted, err := s.documents.DeleteDocuments(s.Context, s.ConnInfo, s.database, s.collection, filterDoc, many)
+				if err != nil {
+					return s.Fail("Failed to delete documents", err)
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("Deleted %d document(s)", deleted))
+
+				if s.Config.Output == config.OutputJSON || s.Config.Output == config.OutputYAML {
+					return s.Formatter.Format(map[string]int64{"deleted": deleted})
+				}
+				return nil
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVar(&filter, "filter", "", "Query filter selecting the documents to delete (Extended JSON)")
+	cmd.Flags().BoolVar(&many, "many", false, "Delete every matching document instead of the first")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func newCountCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var filter string
+
+	cmd := &cobra.Command{
+		Use:   "count",
+		Short: "Count documents",
+		Long:  "Count the documents of a collection matching an Extended JSON filter.",
+		Example: `  # Count every document
+  matlas database documents count --database shop --collection orders --connection-string "mongodb+srv://..."
+
+  # Count matching documents
+  matlas database documents count --filter '{"status": "pending"}' --database shop --collection orders --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filterDoc, err := parseDocumentFlag("filter", filter)
+			if err != nil {
+				return err
+			}
+
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Counting documents in '%s'...", s.namespace()))
+				count, err := s.documents.CountDocuments(s.Context, s.ConnInfo, s.database, s.collection, filterDoc)
+				if err != nil {
+					return s.Fail("Failed to count documents", err)
+				}
+				s.Progress.StopSpinner("Documents counted")
+
+				if s.Config.Output == config.OutputJSON || s.Config.Output == config.OutputYAML {
+					return s.Formatter.Format(map[string]int64{"count": count})
+				}
+				fmt.Println(count)
+				return nil
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVar(&filter, "filter", "", "Query filter (Extended JSON)")
+
+	return cmd
+}
+
+// targetFilter returns the filter selecting documents by the _id argument or --filter,
+// requiring exactly one of them so an omitted filter never matches every document
+func targetFilter(args []string, filter string) (map[string]interface{}, error) {
+	switch {
+	case len(args) == 1 && filter != "":
+		return nil, fmt.Errorf("provide either an id argument or --filter, not both")
+	case len(args) == 1:
+		return map[string]interface{}{"_id": parseID(args[0])}, nil
+	case filter == "":
+		return nil, fmt.Errorf("provide an id argument or --filter ('{}' matches every document)")
+	}
+	doc, err := parseDocumentFlag("filter", filter)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	return doc, nil
+}
+
+// parseID reads a document _id: an ObjectID hex string, an Extended JSON value or a string
+func parseID(value string) interface{} {
+	if id, err := primitive.ObjectIDFromHex(value); err == nil {
+		return id
+	}
+	var doc bson.D
+	if err := bson.UnmarshalExtJSON([]byte(`{"_id":`+value+`}`), false, &doc); err == nil && len(doc) == 1 {
+		return doc[0].Value
+	}
+	return value
+}
+
+// formatID renders an _id for text output
+func formatID(id interface{}) string {
+	switch v := id.(type) {
+	case primitive.ObjectID:
+		return v.Hex()
+	case map[string]interface{}:
+		if oid, ok := v["$oid"].(string); ok {
+			return oid
+		}
+	}
+	return fmt.Sprintf("%v", id)
+}
+
+// parseDocument parses an Extended JSON document, keeping the order of its fields
+func parseDocument(value string) (bson.D, error) {
+	var doc bson.D
+	if err := bson.UnmarshalExtJSON([]byte(value), false, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// parseDocumentFlag parses a flag holding a MongoDB Extended JSON document
+func parseDocumentFlag(name, value string) (map[string]interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var doc bson.M
+	if err := bson.UnmarshalExtJSON([]byte(value), false, &doc); err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", name, err)
+	}
+	return doc, nil
+}
+
+// parseSortFlag parses --sort, keeping the order of its keys
+func parseSortFlag(value string) (bson.D, error) {
+	if value == "" {
+		return nil, nil
+	}
+	doc, err := parseDocument(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --sort: %w", err)
+	}
+	return doc, nil
+}
+
+// readNDJSONFile reads one Extended JSON document per line from a file, or from stdin
+// when the path is '-'
+func readNDJSONFile(path string, stdin io.Reader) ([]interface{}, error) {
+	reader := stdin
+	if path != "-" {
+		file, err := os.Open(path) //nolint:gosec // reading a user-specified input file is intended
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer func() { _ = file.Close() }()
+		reader = file
+	}
+	return readNDJSON(reader)
+}
+
+func readNDJSON(reader io.Reader) ([]interface{}, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var docs []interface{}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		doc, err := parseDocument(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid document on line %d: %w", line, err)
+		}
+		docs = append(docs, doc)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read documents: %w", err)
+	}
+	return docs, nil
+}
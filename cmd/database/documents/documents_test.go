@@ -0,0 +1,58 @@
+package documents
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseID(t *testing.T) {
+	oid := primitive.NewObjectID()
+
+	assert.Equal(t, oid, parseID(oid.Hex()))
+	assert.Equal(t, int32(42), parseID("42"))
+	assert.Equal(t, oid, parseID(`{"$oid": "`+oid.Hex()+`"}`))
+	assert.Equal(t, "order-1", parseID("order-1"))
+	assert.Equal(t, "quoted", parseID(`"quoted"`))
+}
+
+func TestTargetFilter(t *testing.T) {
+	_, err := targetFilter(nil, "")
+	assert.Error(t, err, "an omitted filter must not match every document")
+
+	_, err = targetFilter([]string{"42"}, `{"a": 1}`)
+	assert.Error(t, err)
+
+	filter, err := targetFilter(nil, "{}")
+	require.NoError(t, err)
+	assert.Empty(t, filter)
+	assert.NotNil(t, filter)
+
+	filter, err = targetFilter([]string{"42"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"_id": int32(42)}, filter)
+}
+
+func TestParseSortFlag_KeepsKeyOrder(t *testing.T) {
+	sort, err := parseSortFlag(`{"createdAt": -1, "_id": 1}`)
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{{Key: "createdAt", Value: int32(-1)}, {Key: "_id", Value: int32(1)}}, sort)
+
+	_, err = parseSortFlag("{createdAt}")
+	assert.Error(t, err)
+}
+
+func TestReadNDJSON(t *testing.T) {
+	docs, err := readNDJSON(strings.NewReader("{\"a\": 1}\n\n  {\"b\": {\"$date\": \"2026-10-18T00:00:00Z\"}}\n"))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.IsType(t, primitive.DateTime(0), docs[1].(bson.D)[0].Value)
+
+	_, err = readNDJSON(strings.NewReader("{\"a\": 1}\nnot json\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
@@ -0,0 +1,366 @@
+package transfer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/teabranch/matlas-cli/internal/cli"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/services/database"
+)
+
+// NewExportCmd creates the database export command
+func NewExportCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var collections, fields []string
+	var dir, format, filter, projection string
+	var batchSize, parallel int
+	var resume bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export collections to files",
+		Long: `Export the collections of a database to a directory, one file per collection.
+
+Formats:
+  ndjson   relaxed Extended JSON, one document per line (default)
+  extjson  canonical Extended JSON, one document per line, keeping every BSON type
+  csv      comma-separated values of the --fields columns, with a header row
+  bson     concatenated BSON documents, as in mongodump .bson files
+
+Collections are exported --parallel at a time in _id order. A checkpoint is saved in the
+directory after every batch; if the export stops, run it again with --resume to continue
+each collection after the last exported document.`,
+		Example: `  # Export every collection of a database
+  matlas database export --database shop --dir ./shop-export --connection-string "mongodb+srv://..."
+
+  # Export active users without their password hashes
+  matlas database export --database shop --collection users --filter '{"status": "active"}' --projection '{"passwordHash": 0}' --dir ./export --cluster MyCluster --project-id 507f1f77bcf86cd799439011 --use-temp-user
+
+  # Export selected fields as CSV
+  matlas database export --database shop --collection orders --format csv --fields _id,customerId,total,shipping.city --dir ./export --connection-string "mongodb+srv://..."
+
+  # Continue an interrupted export
+  matlas database export --database shop --dir ./shop-export --resume --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			transferFormat, err := database.ParseTransferFormat(format)
+			if err != nil {
+				return err
+			}
+			if transferFormat == database.FormatCSV && len(fields) == 0 {
+				return fmt.Errorf("--fields is required with --format csv")
+			}
+			if resume && len(collections) > 0 {
+				return fmt.Errorf("--collection cannot be combined with --resume; the checkpoint records the collections")
+			}
+			if batchSize <= 0 || parallel <= 0 {
+				return fmt.Errorf("--batch-size and --parallel must be greater than 0")
+			}
+			filterDoc, err := parseDocumentFlag("filter", filter)
+			if err != nil {
+				return err
+			}
+			projectionDoc, err := parseDocumentFlag("projection", projection)
+			if err != nil {
+				return err
+			}
+			opts := database.ExportOptions{
+				Database:    conn.databaseName,
+				Collections: collections,
+				Dir:         dir,
+				Format:      transferFormat,
+				Filter:      filterDoc,
+				Projection:  projectionDoc,
+				Fields:      fields,
+				BatchSize:   batchSize,
+				Parallel:    parallel,
+				Resume:      resume,
+			}
+
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Exporting database '%s' to %s...", conn.databaseName, dir))
+				opts.Progress = func(done, total int64) {
+					s.Progress.UpdateProgress(fmt.Sprintf("Exported %d of %d document(s)", done, total), percent(done, total))
+				}
+				results, err := s.documents.Export(s.Context, s.ConnInfo, opts)
+				if err != nil {
+					s.Progress.StopSpinnerWithError("Export did not complete")
+					if results != nil {
+						_ = formatResults(s.Formatter, s.Config.Output, results)
+					}
+					return fmt.Errorf("%s", s.Errors.Format(err))
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("Exported %d collection(s) to %s", len(results), dir))
+				return formatResults(s.Formatter, s.Config.Output, results)
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringSliceVar(&collections, "collection", nil, "Collections to export (default: every collection except views)")
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory to write the files to (required)")
+	cmd.Flags().StringVar(&format, "format", string(database.FormatNDJSON), "File format: ndjson, extjson, csv or bson")
+	cmd.Flags().StringVar(&filter, "filter", "", "Query filter as Extended JSON")
+	cmd.Flags().StringVar(&projection, "projection", "", "Projection as Extended JSON")
+	cmd.Flags().StringSliceVar(&fields, "fields", nil, "CSV columns as dotted field paths (required with --format csv)")
+	cmd.Flags().IntVar(&batchSize, "batch-size", database.DefaultTransferBatchSize, "Documents read per batch and between checkpoints")
+	cmd.Flags().IntVar(&parallel, "parallel", database.DefaultTransferParallel, "Collections exported at the same time")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Continue the export recorded in the directory's checkpoint")
+	cli.MustMarkFlagRequired(cmd, "dir")
+
+	return cmd
+}
+
+// NewImportCmd creates the database import command
+func NewImportCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var collection, format string
+	var columnSpecs []string
+	var batchSize, parallel int
+	var resume, drop, upsert bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file|directory>",
+		Short: "Import files into collections",
+		Long: `Import a file, or every file of a directory, into collections of a database.
+
+A file is imported into the collection named after it, or into --collection. Its format is
+taken from the extension (.ndjson, .jsonl and .json for Extended JSON lines, .csv, .bson)
+unless --format is given. Both relaxed and canonical Extended JSON are read.
+
+CSV headers become field names and cells are read as numbers, booleans or strings.
+--column maps a header to a field and a type as header=field[:type] or header:type, with
+types auto, string, int, long, double, bool, date, objectId and json. Dotted fields create
+nested documents; empty cells are left out.
+
+Files are imported --parallel at a time in ordered batches. A checkpoint is saved after
+every batch; if the import stops, fix the cause and run it again with --resume to continue
+with the document that failed.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  # Import a directory written by export
+  matlas database import ./shop-export --database shop --connection-string "mongodb+srv://..."
+
+  # Replace the contents of a collection
+  matlas database import users.ndjson --database shop --drop --connection-string "mongodb+srv://..."
+
+  # Import a CSV file with typed columns
+  matlas database import customers.csv --database shop --collection customers --column id=_id:objectId --column signup:date --column city=address.city --cluster MyCluster --project-id 507f1f77bcf86cd799439011 --use-temp-user
+
+  # Continue an interrupted import, replacing documents imported twice
+  matlas database import ./shop-export --database shop --resume --upsert --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if resume && drop {
+				return fmt.Errorf("--drop cannot be combined with --resume")
+			}
+			if batchSize <= 0 || parallel <= 0 {
+				return fmt.Errorf("--batch-size and --parallel must be greater than 0")
+			}
+			var forced database.TransferFormat
+			if format != "" {
+				var err error
+				if forced, err = database.ParseTransferFormat(format); err != nil {
+					return err
+				}
+			}
+			columns := make([]database.CSVColumn, 0, len(columnSpecs))
+			for _, spec := range columnSpecs {
+				column, err := database.ParseCSVColumn(spec)
+				if err != nil {
+					return err
+				}
+				columns = append(columns, column)
+			}
+			files, statePath, err := importFiles(args[0], collection, forced)
+			if err != nil {
+				return err
+			}
+			opts := database.ImportOptions{
+				Database:  conn.databaseName,
+				Files:     files,
+				StatePath: statePath,
+				Columns:   columns,
+				BatchSize: batchSize,
+				Parallel:  parallel,
+				Resume:    resume,
+				Drop:      drop,
+				Upsert:    upsert,
+			}
+
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Importing %d file(s) into database '%s'...", len(files), conn.databaseName))
+				opts.Progress = func(done, total int64) {
+					s.Progress.UpdateProgress(fmt.Sprintf("Imported %s of %s", formatBytes(done), formatBytes(total)), percent(done, total))
+				}
+				results, err := s.documents.Import(s.Context, s.ConnInfo, opts)
+				if err != nil {
+					s.Progress.StopSpinnerWithError("Import did not complete")
+					if results != nil {
+						_ = formatResults(s.Formatter, s.Config.Output, results)
+					}
+					return fmt.Errorf("%s", s.Errors.Format(err))
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("Imported %d file(s) into database '%s'", len(results), conn.databaseName))
+				return formatResults(s.Formatter, s.Config.Output, results)
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVar(&collection, "collection", "", "Target collection of a single file (default: the file name)")
+	cmd.Flags().StringVar(&format, "format", "", "File format: ndjson, extjson, csv or bson (default: from the file extension)")
+	cmd.Flags().StringArrayVar(&columnSpecs, "column", nil, "CSV column mapping header=field[:type] or header:type (repeatable)")
+	cmd.Flags().IntVar(&batchSize, "batch-size", database.DefaultTransferBatchSize, "Documents written per batch and between checkpoints")
+	cmd.Flags().IntVar(&parallel, "parallel", database.DefaultTransferParallel, "Files imported at the same time")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Continue the import recorded in the checkpoint")
+	cmd.Flags().BoolVar(&drop, "drop", false, "Drop each target collection before importing")
+	cmd.Flags().BoolVar(&upsert, "upsert", false, "Replace documents with the same _id instead of failing on duplicates")
+
+	return cmd
+}
+
+// importFiles lists the files to import from a file or directory and returns where the
+// checkpoint of the import is kept
+func importFiles(path, collection string, forced database.TransferFormat) ([]database.ImportFile, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		format, ok := forced, forced != ""
+		if !ok {
+			if format, ok = database.TransferFormatFromPath(path); !ok {
+				return nil, "", fmt.Errorf("cannot tell the format of %s from its extension; use --format", path)
+			}
+		}
+		if collection == "" {
+			collection = collectionFromFile(path)
+		}
+		return []database.ImportFile{{Path: path, Collection: collection, Format: format}}, path + database.ImportStateFile, nil
+	}
+
+	if collection != "" {
+		return nil, "", fmt.Errorf("--collection applies to a single file; files of a directory are imported into the collections they are named after")
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var files []database.ImportFile
+	for _, entry := range entries {
+		// Skip subdirectories and hidden files such as checkpoints
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		format, ok := forced, forced != ""
+		if !ok {
+			if format, ok = database.TransferFormatFromPath(entry.Name()); !ok {
+				continue
+			}
+		}
+		file := filepath.Join(path, entry.Name())
+		files = append(files, database.ImportFile{Path: file, Collection: collectionFromFile(file), Format: format})
+	}
+	if len(files) == 0 {
+		return nil, "", fmt.Errorf("no .ndjson, .jsonl, .json, .csv or .bson files found in %s", path)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, filepath.Join(path, database.ImportStateFile), nil
+}
+
+// collectionFromFile names a collection after a file, without its extension
+func collectionFromFile(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// formatResults outputs a summary of the transfers
+func formatResults(formatter *output.Formatter, format config.OutputFormat, results []database.CollectionTransfer) error {
+	if format == config.OutputJSON || format == config.OutputYAML {
+		return formatter.Format(results)
+	}
+	table := output.TableData{Headers: []string{"COLLECTION", "FILE", "DOCUMENTS", "STATUS"}}
+	for _, result := range results {
+		table.Rows = append(table.Rows, []string{result.Collection, result.File, fmt.Sprintf("%d", result.Documents), transferStatus(result)})
+	}
+	return formatter.Format(table)
+}
+
+func transferStatus(result database.CollectionTransfer) string {
+	switch {
+	case result.Done:
+		return "done"
+	case result.Error != "":
+		return "failed: " + result.Error
+	}
+	return "incomplete"
+}
+
+func percent(done, total int64) float64 {
+	if total <= 0 {
+		return 100
+	}
+	return float64(done) / float64(total) * 100
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// connectionFlags holds the flags selecting the database a transfer works on
+type connectionFlags struct {
+	cli.DatabaseConnectionFlags
+	databaseName string
+}
+
+func (f *connectionFlags) register(cmd *cobra.Command) {
+	cli.AddDatabaseConnectionFlags(cmd, &f.DatabaseConnectionFlags)
+	cmd.Flags().StringVar(&f.databaseName, "database", "", "Database name (required)")
+	cli.MustMarkFlagRequired(cmd, "database")
+}
+
+// session is an open connection to the database of a transfer
+type session struct {
+	*cli.DatabaseSession
+	documents *database.DocumentService
+}
+
+// withSession connects and runs fn, closing the connection and removing any temporary user
+// afterwards. Transfers are not bound by the configured timeout; an interrupted transfer is
+// resumed from its checkpoint.
+func (f *connectionFlags) withSession(cmd *cobra.Command, fn func(s *session) error) error {
+	return f.WithUnboundedSession(cmd, func(s *cli.DatabaseSession) error {
+		return fn(&session{
+			DatabaseSession: s,
+			documents:       database.NewDocumentService(s.Service, s.Logger),
+		})
+	})
+}
+
+// parseDocumentFlag parses a flag holding an Extended JSON document
+func parseDocumentFlag(name, value string) (map[string]interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var doc bson.M
+	if err := bson.UnmarshalExtJSON([]byte(value), false, &doc); err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", name, err)
+	}
+	return doc, nil
+}
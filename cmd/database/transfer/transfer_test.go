@@ -0,0 +1,77 @@
+package transfer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teabranch/matlas-cli/internal/services/database"
+)
+
+func TestNewExportCmd_Flags(t *testing.T) {
+	cmd := NewExportCmd()
+
+	for _, name := range []string{"database", "collection", "dir", "format", "filter", "projection", "fields", "batch-size", "parallel", "resume", "use-temp-user"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), name)
+	}
+	assert.Equal(t, "ndjson", cmd.Flags().Lookup("format").DefValue)
+	assert.Equal(t, "4", cmd.Flags().Lookup("parallel").DefValue)
+}
+
+func TestNewImportCmd_Flags(t *testing.T) {
+	cmd := NewImportCmd()
+
+	assert.Equal(t, "import <file|directory>", cmd.Use)
+	for _, name := range []string{"database", "collection", "format", "column", "batch-size", "parallel", "resume", "drop", "upsert"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), name)
+	}
+	assert.Error(t, cmd.Args(cmd, nil))
+}
+
+func TestImportFiles_Directory(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"users.ndjson", "orders.csv", "events.bson", "notes.txt", database.ImportStateFile} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0o750))
+
+	files, statePath, err := importFiles(dir, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, database.ImportStateFile), statePath)
+	assert.Equal(t, []database.ImportFile{
+		{Path: filepath.Join(dir, "events.bson"), Collection: "events", Format: database.FormatBSON},
+		{Path: filepath.Join(dir, "orders.csv"), Collection: "orders", Format: database.FormatCSV},
+		{Path: filepath.Join(dir, "users.ndjson"), Collection: "users", Format: database.FormatNDJSON},
+	}, files)
+
+	_, _, err = importFiles(dir, "users", "")
+	assert.Error(t, err, "--collection applies to single files only")
+}
+
+func TestImportFiles_SingleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customers.txt")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	_, _, err := importFiles(path, "", "")
+	assert.Error(t, err, "the format of .txt files cannot be detected")
+
+	files, statePath, err := importFiles(path, "people", database.FormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, []database.ImportFile{{Path: path, Collection: "people", Format: database.FormatCSV}}, files)
+	assert.Equal(t, path+database.ImportStateFile, statePath)
+}
+
+func TestTransferStatus(t *testing.T) {
+	assert.Equal(t, "done", transferStatus(database.CollectionTransfer{Done: true}))
+	assert.Equal(t, "failed: boom", transferStatus(database.CollectionTransfer{Error: "boom"}))
+	assert.Equal(t, "incomplete", transferStatus(database.CollectionTransfer{}))
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "2.0 GiB", formatBytes(2<<30))
+}
@@ -0,0 +1,360 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/teabranch/matlas-cli/cmd/database/documents"
+	"github.com/teabranch/matlas-cli/internal/cli"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/ui"
+)
+
+// NewViewsCmd creates the views command with all its subcommands
+func NewViewsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "views",
+		Short: "Manage views and materialized views",
+		Long: `Create, list, refresh and delete the views of a database.
+
+A view is a read-only collection that runs its pipeline on another collection each time it
+is queried. A materialized view runs its pipeline on demand and merges the results into a
+regular collection with $merge; its definition is kept in the ` + database.MaterializedViewsCollection + `
+collection of the database so it can be refreshed by name.`,
+		Aliases: []string{"view"},
+	}
+
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newGetCmd())
+	cmd.AddCommand(newCreateCmd())
+	cmd.AddCommand(newRefreshCmd())
+	cmd.AddCommand(newDeleteCmd())
+
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	conn := &connectionFlags{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List views",
+		Long:    "List the views and materialized views of a database with their source collection and number of stages.",
+		Example: `  # List the views of a database
+  matlas database views list --database shop --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Listing views of '%s'...", s.database))
+				views, err := s.Service.ListViews(s.Context, s.ConnInfo, s.database)
+				if err != nil {
+					return s.Fail("Failed to list views", err)
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("Found %d view(s)", len(views)))
+
+				return output.FormatList(s.Formatter, views,
+					[]string{"NAME", "TYPE", "SOURCE", "STAGES", "REFRESHED"},
+					func(item interface{}) []string {
+						view := item.(types.ViewInfo)
+						return []string{view.Name, view.Type, view.Source, fmt.Sprintf("%d", len(view.Pipeline)), refreshedAt(view)}
+					})
+			})
+		},
+	}
+
+	conn.register(cmd)
+
+	return cmd
+}
+
+func newGetCmd() *cobra.Command {
+	conn := &connectionFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Show a view",
+		Long:  "Show the source collection, pipeline and options of a view or materialized view.",
+		Args:  cobra.ExactArgs(1),
+		Example: `  # Show the pipeline of a view
+  matlas database views get open_orders --database shop --connection-string "mongodb+srv://..."
+
+  # Show a materialized view as YAML
+  matlas database views get revenue_by_customer --output yaml --database shop --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Reading view '%s.%s'...", s.database, args[0]))
+				view, err := s.Service.GetView(s.Context, s.ConnInfo, s.database, args[0])
+				if err != nil {
+					return s.Fail("Failed to read view", err)
+				}
+				s.Progress.StopSpinner("View retrieved")
+
+				switch s.Config.Output {
+				case config.OutputJSON, config.OutputYAML:
+					return s.Formatter.Format(view)
+				}
+
+				fmt.Printf("Name:      %s\n", view.Name)
+				fmt.Printf("Type:      %s\n", view.Type)
+				fmt.Printf("Source:    %s\n", view.Source)
+				if view.Collation != nil {
+					fmt.Printf("Collation: %s\n", view.Collation.Locale)
+				}
+				if view.Merge != nil {
+					merge := view.Merge.WithDefaults()
+					on := "_id"
+					if len(merge.On) > 0 {
+						on = strings.Join(merge.On, ", ")
+					}
+					fmt.Printf("Merge on:  %s (whenMatched: %s, whenNotMatched: %s)\n", on, merge.WhenMatched, merge.WhenNotMatched)
+					fmt.Printf("Refreshed: %s\n", refreshedAt(*view))
+				}
+				data, err := json.MarshalIndent(view.Pipeline, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to encode pipeline: %w", err)
+				}
+				fmt.Printf("Pipeline:\n%s\n", data)
+				return nil
+			})
+		},
+	}
+
+	conn.register(cmd)
+
+	return cmd
+}
+
+func newCreateCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var viewOn, pipelinePath string
+	var varFiles, vars, on []string
+	var materialized bool
+	var whenMatched, whenNotMatched string
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a view or materialized view",
+		Long: `Create a view that runs a saved pipeline on a collection.
+
+The pipeline file holds an array of stages as MongoDB Extended JSON, or as YAML when it
+ends in .yaml or .yml, with ${NAME} placeholders substituted as in 'matlas database
+aggregate'. Views can't contain $out or $merge stages.
+
+With --materialized the pipeline runs once on --view-on and its results are merged into
+the collection <name>; run 'matlas database views refresh' to run it again. --on,
+--when-matched and --when-not-matched set how $merge matches results to existing
+documents (default: on _id, merge matched documents, insert the others).`,
+		Args: cobra.ExactArgs(1),
+		Example: `  # Create a view of open orders
+  matlas database views create open_orders --view-on orders --pipeline pipelines/open-orders.json --database shop --connection-string "mongodb+srv://..."
+
+  # Create a materialized view of revenue per customer, replacing the previous totals
+  matlas database views create revenue_by_customer --materialized --view-on orders --pipeline pipelines/revenue-by-customer.yaml --when-matched replace --database shop --cluster MyCluster --project-id 507f1f77bcf86cd799439011 --use-temp-user`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if name == viewOn {
+				return fmt.Errorf("a view cannot be created on itself")
+			}
+			merge := &types.MergeOptions{On: on, WhenMatched: whenMatched, WhenNotMatched: whenNotMatched}
+			if !materialized && (len(on) > 0 || whenMatched != "" || whenNotMatched != "") {
+				return fmt.Errorf("--on, --when-matched and --when-not-matched require --materialized")
+			}
+			if err := types.ValidateMergeOptions(merge); err != nil {
+				return err
+			}
+			pipeline, err := documents.LoadPipeline(pipelinePath, varFiles, vars, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			if err := database.ValidateViewPipeline(pipeline); err != nil {
+				return err
+			}
+
+			return conn.withSession(cmd, func(s *session) error {
+				if !materialized {
+					s.Progress.StartSpinner(fmt.Sprintf("Creating view '%s.%s'...", s.database, name))
+					if err := s.Service.CreateView(s.Context, s.ConnInfo, s.database, name, viewOn, pipeline, nil); err != nil {
+						return s.Fail("Failed to create view", err)
+					}
+					s.Progress.StopSpinner(fmt.Sprintf("View '%s.%s' created on '%s'", s.database, name, viewOn))
+					return nil
+				}
+
+				existing, err := s.Service.GetMaterializedView(s.Context, s.ConnInfo, s.database, name)
+				if err != nil {
+					return s.Fail("Failed to read materialized view", err)
+				}
+				if existing != nil {
+					return fmt.Errorf("materialized view '%s' already exists in database '%s'; use 'matlas database views refresh' to run it again", name, s.database)
+				}
+
+				view := &database.MaterializedView{Name: name, Source: viewOn, Pipeline: pipeline}
+				view.SetMergeOptions(merge)
+				s.Progress.StartSpinner(fmt.Sprintf("Creating materialized view '%s.%s'...", s.database, name))
+				if err := s.Service.RefreshMaterializedView(s.Context, s.ConnInfo, s.database, view); err != nil {
+					return s.Fail("Failed to create materialized view", err)
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("Materialized view '%s.%s' created from '%s'", s.database, name, viewOn))
+				return nil
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().StringVar(&viewOn, "view-on", "", "Collection or view the pipeline runs on (required)")
+	cmd.Flags().StringVar(&pipelinePath, "pipeline", "", "File holding the pipeline as an Extended JSON or YAML array of stages, or '-' for stdin (required)")
+	cmd.Flags().StringSliceVar(&varFiles, "var-file", []string{}, "YAML or JSON files of pipeline variables; later files win (repeatable)")
+	cmd.Flags().StringArrayVar(&vars, "var", []string{}, "Pipeline variable as NAME=VALUE, overriding variable files (repeatable)")
+	cmd.Flags().BoolVar(&materialized, "materialized", false, "Create an on-demand materialized view that merges the pipeline results into a collection")
+	cmd.Flags().StringSliceVar(&on, "on", []string{}, "Fields identifying a result document in the materialized view (default _id)")
+	cmd.Flags().StringVar(&whenMatched, "when-matched", "", "Action for results matching a document: replace, keepExisting, merge or fail (default merge)")
+	cmd.Flags().StringVar(&whenNotMatched, "when-not-matched", "", "Action for results matching no document: insert, discard or fail (default insert)")
+	cli.MustMarkFlagRequired(cmd, "view-on")
+	cli.MustMarkFlagRequired(cmd, "pipeline")
+
+	return cmd
+}
+
+func newRefreshCmd() *cobra.Command {
+	conn := &connectionFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "refresh <name>",
+		Short: "Refresh a materialized view",
+		Long: `Run the pipeline of a materialized view on its source and merge the results into its
+collection. Documents that the pipeline no longer returns are kept.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  # Refresh a materialized view
+  matlas database views refresh revenue_by_customer --database shop --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return conn.withSession(cmd, func(s *session) error {
+				view, err := s.Service.GetMaterializedView(s.Context, s.ConnInfo, s.database, args[0])
+				if err != nil {
+					return s.Fail("Failed to read materialized view", err)
+				}
+				if view == nil {
+					return fmt.Errorf("materialized view '%s' not found in database '%s'", args[0], s.database)
+				}
+
+				s.Progress.StartSpinner(fmt.Sprintf("Refreshing materialized view '%s.%s'...", s.database, view.Name))
+				if err := s.Service.RefreshMaterializedView(s.Context, s.ConnInfo, s.database, view); err != nil {
+					return s.Fail("Failed to refresh materialized view", err)
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("Materialized view '%s.%s' refreshed from '%s'", s.database, view.Name, view.Source))
+				return nil
+			})
+		},
+	}
+
+	conn.register(cmd)
+
+	return cmd
+}
+
+func newDeleteCmd() *cobra.Command {
+	conn := &connectionFlags{}
+	var yes, keepData bool
+
+	cmd := &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm", "drop"},
+		Short:   "Delete a view",
+		Long: `Delete a view, or a materialized view with its collection.
+
+--keep-data removes only the definition of a materialized view and keeps the collection
+its results were merged into.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  # Delete a view
+  matlas database views delete open_orders --database shop --connection-string "mongodb+srv://..."
+
+  # Stop refreshing a materialized view but keep its collection
+  matlas database views delete revenue_by_customer --keep-data --yes --database shop --connection-string "mongodb+srv://..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if !yes {
+				confirm := ui.NewConfirmationPrompt(false, false)
+				confirmed, err := confirm.ConfirmDeletion("view", fmt.Sprintf("%s.%s", conn.databaseName, name))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("Operation cancelled")
+					return nil
+				}
+			}
+
+			return conn.withSession(cmd, func(s *session) error {
+				s.Progress.StartSpinner(fmt.Sprintf("Deleting view '%s.%s'...", s.database, name))
+				view, err := s.Service.GetView(s.Context, s.ConnInfo, s.database, name)
+				if err != nil {
+					return s.Fail("Failed to delete view", err)
+				}
+
+				if view.Type == types.ViewTypeMaterialized {
+					err = s.Service.DeleteMaterializedView(s.Context, s.ConnInfo, s.database, name, keepData)
+				} else if keepData {
+					s.Progress.StopSpinnerWithError("Failed to delete view")
+					return fmt.Errorf("--keep-data applies only to materialized views")
+				} else {
+					err = s.Service.DropCollection(s.Context, s.ConnInfo, s.database, name)
+				}
+				if err != nil {
+					return s.Fail("Failed to delete view", err)
+				}
+				s.Progress.StopSpinner(fmt.Sprintf("View '%s.%s' deleted", s.database, name))
+				return nil
+			})
+		},
+	}
+
+	conn.register(cmd)
+	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&keepData, "keep-data", false, "Keep the collection of a materialized view")
+
+	return cmd
+}
+
+// refreshedAt formats the last refresh of a materialized view
+func refreshedAt(view types.ViewInfo) string {
+	switch {
+	case view.Type != types.ViewTypeMaterialized:
+		return "-"
+	case view.RefreshedAt == nil:
+		return "never"
+	default:
+		return view.RefreshedAt.Local().Format(time.RFC3339)
+	}
+}
+
+// connectionFlags holds the flags selecting the database a command works on
+type connectionFlags struct {
+	cli.DatabaseConnectionFlags
+	databaseName string
+}
+
+func (f *connectionFlags) register(cmd *cobra.Command) {
+	cli.AddDatabaseConnectionFlags(cmd, &f.DatabaseConnectionFlags)
+	cmd.Flags().StringVar(&f.databaseName, "database", "", "Database name (required)")
+	cli.MustMarkFlagRequired(cmd, "database")
+}
+
+// session is an open connection to the database of a command
+type session struct {
+	*cli.DatabaseSession
+	database string
+}
+
+// withSession connects and runs fn, closing the connection and removing any temporary
+// user afterwards
+func (f *connectionFlags) withSession(cmd *cobra.Command, fn func(s *session) error) error {
+	return f.WithSession(cmd, func(s *cli.DatabaseSession) error {
+		return fn(&session{DatabaseSession: s, database: f.databaseName})
+	})
+}
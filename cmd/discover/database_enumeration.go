@@ -14,6 +14,7 @@ import (
 
 	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
 	"github.com/teabranch/matlas-cli/internal/services/atlas"
+	dbservice "github.com/teabranch/matlas-cli/internal/services/database"
 	"github.com/teabranch/matlas-cli/internal/types"
 )
 
@@ -292,8 +293,8 @@ func (e *DatabaseEnumerator) getDatabaseInfo(ctx context.Context, client *mongo.
 		}
 	}
 
-	// Get collections
-	collections, err := e.getCollectionInfo(ctx, database)
+	// Get collections and views
+	collections, views, err := e.getCollectionInfo(ctx, database)
 	if err != nil {
 		return DatabaseInfo{}, fmt.Errorf("failed to get collections for database %s: %w", dbName, err)
 	}
@@ -312,30 +313,54 @@ func (e *DatabaseEnumerator) getDatabaseInfo(ctx context.Context, client *mongo.
 		Name:        dbName,
 		SizeOnDisk:  sizeOnDisk,
 		Collections: collections,
+		Views:       views,
 	}
 
 	return dbInfo, nil
 }
 
-// getCollectionInfo gets information about all collections in a database
-func (e *DatabaseEnumerator) getCollectionInfo(ctx context.Context, database *mongo.Database) ([]CollectionInfo, error) {
+// getCollectionInfo gets information about all collections and views in a database
+func (e *DatabaseEnumerator) getCollectionInfo(ctx context.Context, database *mongo.Database) ([]CollectionInfo, []types.ViewInfo, error) {
 	// List collections
 	listCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	collectionNames, err := database.ListCollectionNames(listCtx, bson.D{})
+	specs, err := database.ListCollectionSpecifications(listCtx, bson.D{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list collections: %w", err)
+		return nil, nil, fmt.Errorf("failed to list collections: %w", err)
 	}
 
 	var collections []CollectionInfo
+	var views []types.ViewInfo
 
 	// Get detailed info for each collection
-	for _, collName := range collectionNames {
+	for _, spec := range specs {
+		collName := spec.Name
 		if e.isSystemCollection(collName) {
 			continue // Skip system collections
 		}
 
+		// Views have no stats or indexes of their own
+		if spec.Type == "view" {
+			view, err := viewFromSpecification(spec)
+			if err != nil {
+				if e.verbose {
+					fmt.Printf("        Warning: Failed to read view %s: %v\n", collName, err)
+				}
+				view = types.ViewInfo{Name: collName, Type: types.ViewTypeStandard}
+			}
+			views = append(views, view)
+			continue
+		}
+		if collName == dbservice.MaterializedViewsCollection {
+			materialized, err := e.getMaterializedViews(ctx, database)
+			if err != nil && e.verbose {
+				fmt.Printf("        Warning: Failed to read materialized views: %v\n", err)
+			}
+			views = append(views, materialized...)
+			continue
+		}
+
 		collInfo, err := e.getDetailedCollectionInfo(ctx, database, collName)
 		if err != nil {
 			if e.verbose {
@@ -344,16 +369,79 @@ func (e *DatabaseEnumerator) getCollectionInfo(ctx context.Context, database *mo
 			// Add basic collection info even if detailed info fails
 			collInfo = CollectionInfo{Name: collName}
 		}
+		if err := applyCollectionOptions(&collInfo, spec); err != nil && e.verbose {
+			fmt.Printf("        Warning: Failed to read options of collection %s: %v\n", collName, err)
+		}
 
 		collections = append(collections, collInfo)
 	}
 
-	// Sort collections by name for consistent output
+	// Sort collections and views by name for consistent output
 	sort.Slice(collections, func(i, j int) bool {
 		return collections[i].Name < collections[j].Name
 	})
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+
+	return collections, views, nil
+}
+
+// applyCollectionOptions records the type and the time series and clustered options of a
+// collection from its listCollections entry
+func applyCollectionOptions(collInfo *CollectionInfo, spec *mongo.CollectionSpecification) error {
+	collInfo.Type = spec.Type
+	if len(spec.Options) == 0 {
+		return nil
+	}
+	var raw map[string]interface{}
+	if err := bson.Unmarshal(spec.Options, &raw); err != nil {
+		return fmt.Errorf("failed to decode collection options: %w", err)
+	}
+	opts, err := types.ParseCollectionOptions(raw)
+	if err != nil {
+		return err
+	}
+	collInfo.TimeSeries = opts.TimeSeries
+	collInfo.ClusteredIndex = opts.ClusteredIndex
+	collInfo.ExpireAfterSeconds = opts.ExpireAfterSeconds
+	return nil
+}
+
+// viewFromSpecification describes a view from its listCollections entry
+func viewFromSpecification(spec *mongo.CollectionSpecification) (types.ViewInfo, error) {
+	var options map[string]interface{}
+	if len(spec.Options) > 0 {
+		if err := bson.Unmarshal(spec.Options, &options); err != nil {
+			return types.ViewInfo{}, fmt.Errorf("failed to decode view options: %w", err)
+		}
+	}
+	return dbservice.ViewInfoFromCollection(types.CollectionInfo{Name: spec.Name, Type: spec.Type, Options: options})
+}
+
+// getMaterializedViews reads the definitions of the materialized views of a database
+func (e *DatabaseEnumerator) getMaterializedViews(ctx context.Context, database *mongo.Database) ([]types.ViewInfo, error) {
+	findCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
 
-	return collections, nil
+	cursor, err := database.Collection(dbservice.MaterializedViewsCollection).Find(findCtx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list materialized views: %w", err)
+	}
+	var definitions []dbservice.MaterializedView
+	if err := cursor.All(findCtx, &definitions); err != nil {
+		return nil, fmt.Errorf("failed to read materialized views: %w", err)
+	}
+
+	views := make([]types.ViewInfo, 0, len(definitions))
+	for i := range definitions {
+		view, err := definitions[i].Info()
+		if err != nil {
+			return views, fmt.Errorf("materialized view %s: %w", definitions[i].Name, err)
+		}
+		views = append(views, view)
+	}
+	return views, nil
 }
 
 // getDetailedCollectionInfo gets detailed information about a single collection
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/teabranch/matlas-cli/internal/types"
 )
@@ -404,6 +406,64 @@ func TestIndexInfo(t *testing.T) {
 	}
 }
 
+// TestApplyCollectionOptions tests reading time series and clustered options from
+// listCollections entries
+func TestApplyCollectionOptions(t *testing.T) {
+	timeseries, err := bson.Marshal(bson.D{
+		{Key: "timeseries", Value: bson.D{
+			{Key: "timeField", Value: "ts"},
+			{Key: "metaField", Value: "sensor"},
+			{Key: "granularity", Value: "minutes"},
+			{Key: "bucketMaxSpanSeconds", Value: int32(86400)},
+		}},
+		{Key: "expireAfterSeconds", Value: int64(2592000)},
+		{Key: "clusteredIndex", Value: true},
+	})
+	require.NoError(t, err)
+
+	readings := CollectionInfo{Name: "readings"}
+	require.NoError(t, applyCollectionOptions(&readings, &mongo.CollectionSpecification{Name: "readings", Type: "timeseries", Options: timeseries}))
+	assert.Equal(t, "timeseries", readings.Type)
+	require.NotNil(t, readings.TimeSeries)
+	assert.Equal(t, "ts", readings.TimeSeries.TimeField)
+	assert.Equal(t, "sensor", readings.TimeSeries.MetaField)
+	assert.Equal(t, "minutes", readings.TimeSeries.Granularity)
+	require.NotNil(t, readings.ExpireAfterSeconds)
+	assert.Equal(t, int64(2592000), *readings.ExpireAfterSeconds)
+	assert.Nil(t, readings.ClusteredIndex, "time series buckets are clustered internally")
+
+	clustered, err := bson.Marshal(bson.D{
+		{Key: "clusteredIndex", Value: bson.D{{Key: "v", Value: int32(2)}, {Key: "key", Value: bson.D{{Key: "_id", Value: int32(1)}}}, {Key: "name", Value: "_id_"}, {Key: "unique", Value: true}}},
+	})
+	require.NoError(t, err)
+
+	events := CollectionInfo{Name: "events"}
+	require.NoError(t, applyCollectionOptions(&events, &mongo.CollectionSpecification{Name: "events", Type: "collection", Options: clustered}))
+	assert.Equal(t, &types.ClusteredIndexOptions{Name: "_id_"}, events.ClusteredIndex)
+	assert.Nil(t, events.TimeSeries)
+}
+
+// TestViewFromSpecification tests reading a view from its listCollections entry
+func TestViewFromSpecification(t *testing.T) {
+	options, err := bson.Marshal(bson.D{
+		{Key: "viewOn", Value: "orders"},
+		{Key: "pipeline", Value: bson.A{bson.D{{Key: "$match", Value: bson.D{{Key: "status", Value: "open"}}}}}},
+		{Key: "collation", Value: bson.D{{Key: "locale", Value: "en"}, {Key: "strength", Value: int32(2)}}},
+	})
+	require.NoError(t, err)
+
+	view, err := viewFromSpecification(&mongo.CollectionSpecification{Name: "open_orders", Type: "view", Options: options})
+	require.NoError(t, err)
+
+	assert.Equal(t, "open_orders", view.Name)
+	assert.Equal(t, types.ViewTypeStandard, view.Type)
+	assert.Equal(t, "orders", view.Source)
+	assert.Equal(t, []interface{}{map[string]interface{}{"$match": map[string]interface{}{"status": "open"}}}, view.Pipeline)
+	require.NotNil(t, view.Collation)
+	assert.Equal(t, "en", view.Collation.Locale)
+	assert.Equal(t, 2, view.Collation.Strength)
+}
+
 // TestClusterDatabaseResult tests the result structure
 func TestClusterDatabaseResult(t *testing.T) {
 	result := clusterDatabaseResult{
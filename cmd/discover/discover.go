@@ -82,6 +82,7 @@ type DiscoveryStats struct {
 	NetworkEntriesFound int           `yaml:"networkEntriesFound" json:"networkEntriesFound"`
 	DatabasesFound      int           `yaml:"databasesFound" json:"databasesFound"`
 	CollectionsFound    int           `yaml:"collectionsFound" json:"collectionsFound"`
+	ViewsFound          int           `yaml:"viewsFound" json:"viewsFound"`
 	Duration            time.Duration `yaml:"duration" json:"duration"`
 	CacheHit            bool          `yaml:"cacheHit" json:"cacheHit"`
 }
@@ -92,18 +93,26 @@ type DatabaseInfo struct {
 	ClusterName string           `yaml:"clusterName" json:"clusterName"`
 	SizeOnDisk  int64            `yaml:"sizeOnDisk,omitempty" json:"sizeOnDisk,omitempty"`
 	Collections []CollectionInfo `yaml:"collections,omitempty" json:"collections,omitempty"`
-	Indexes     []IndexInfo      `yaml:"indexes,omitempty" json:"indexes,omitempty"`
+	// Views lists views and materialized views. Views are not listed in Collections; the
+	// collections materialized views merge into are.
+	Views   []types.ViewInfo `yaml:"views,omitempty" json:"views,omitempty"`
+	Indexes []IndexInfo      `yaml:"indexes,omitempty" json:"indexes,omitempty"`
 }
 
 // CollectionInfo contains information about discovered collections
 type CollectionInfo struct {
-	Name           string      `yaml:"name" json:"name"`
-	DocumentCount  int64       `yaml:"documentCount,omitempty" json:"documentCount,omitempty"`
-	StorageSize    int64       `yaml:"storageSize,omitempty" json:"storageSize,omitempty"`
-	IndexCount     int         `yaml:"indexCount,omitempty" json:"indexCount,omitempty"`
-	Indexes        []IndexInfo `yaml:"indexes,omitempty" json:"indexes,omitempty"`
-	ShardKey       interface{} `yaml:"shardKey,omitempty" json:"shardKey,omitempty"`
-	ValidationRule interface{} `yaml:"validationRule,omitempty" json:"validationRule,omitempty"`
+	Name string `yaml:"name" json:"name"`
+	// Type is collection or timeseries
+	Type               string                       `yaml:"type,omitempty" json:"type,omitempty"`
+	TimeSeries         *types.TimeSeriesOptions     `yaml:"timeseries,omitempty" json:"timeseries,omitempty"`
+	ClusteredIndex     *types.ClusteredIndexOptions `yaml:"clusteredIndex,omitempty" json:"clusteredIndex,omitempty"`
+	ExpireAfterSeconds *int64                       `yaml:"expireAfterSeconds,omitempty" json:"expireAfterSeconds,omitempty"`
+	DocumentCount      int64                        `yaml:"documentCount,omitempty" json:"documentCount,omitempty"`
+	StorageSize        int64                        `yaml:"storageSize,omitempty" json:"storageSize,omitempty"`
+	IndexCount         int                          `yaml:"indexCount,omitempty" json:"indexCount,omitempty"`
+	Indexes            []IndexInfo                  `yaml:"indexes,omitempty" json:"indexes,omitempty"`
+	ShardKey           interface{}                  `yaml:"shardKey,omitempty" json:"shardKey,omitempty"`
+	ValidationRule     interface{}                  `yaml:"validationRule,omitempty" json:"validationRule,omitempty"`
 }
 
 // IndexInfo contains information about discovered indexes
@@ -373,12 +382,14 @@ func runDiscover(cmd *cobra.Command, opts *DiscoverOptions) error {
 			result.Databases = databases
 			result.Metadata.Stats.DatabasesFound = len(databases)
 
-			// Count total collections
-			totalCollections := 0
+			// Count total collections and views
+			totalCollections, totalViews := 0, 0
 			for _, db := range databases {
 				totalCollections += len(db.Collections)
+				totalViews += len(db.Views)
 			}
 			result.Metadata.Stats.CollectionsFound = totalCollections
+			result.Metadata.Stats.ViewsFound = totalViews
 		}
 
 		// Cleanup temporary user if created
@@ -11,6 +11,7 @@ import (
 	"github.com/teabranch/matlas-cli/internal/apply"
 	"github.com/teabranch/matlas-cli/internal/apply/dag"
 	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/types"
 )
 
 // AnalyzeOptions contains the options for the analyze command
@@ -25,6 +26,9 @@ type AnalyzeOptions struct {
 	Timeout      time.Duration
 	ShowCycles   bool
 	ShowRisk     bool
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewAnalyzeCmd creates the analyze subcommand
@@ -62,6 +66,7 @@ func NewAnalyzeCmd() *cobra.Command {
 
 	// File input flags
 	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to analyze (supports glob patterns)")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
 
 	// Output flags
 	cmd.Flags().StringVar(&opts.OutputFormat, "format", "text", "Report format: text, markdown, json")
@@ -84,12 +89,12 @@ func runAnalyze(cmd *cobra.Command, opts *AnalyzeOptions) error {
 	defer cancel()
 
 	// Validate options
-	if len(opts.Files) == 0 {
-		return fmt.Errorf("no configuration files specified (use -f or provide files as arguments)")
+	if len(opts.Files) == 0 && opts.Overlay == "" {
+		return fmt.Errorf("no configuration files specified (use -f, --overlay or provide files as arguments)")
 	}
 
 	// Expand file patterns
-	files, err := expandFilePatterns(opts.Files)
+	files, err := opts.configFiles(opts.Files)
 	if err != nil {
 		return fmt.Errorf("failed to expand file patterns: %w", err)
 	}
@@ -107,8 +112,9 @@ func runAnalyze(cmd *cobra.Command, opts *AnalyzeOptions) error {
 
 	// Load configurations
 	configs, err := loadConfigurations(files, &ApplyOptions{
-		StrictEnv: opts.StrictEnv,
-		Verbose:   opts.Verbose,
+		ConfigInputs: opts.ConfigInputs,
+		StrictEnv:    opts.StrictEnv,
+		Verbose:      opts.Verbose,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to load configurations: %w", err)
@@ -199,6 +205,15 @@ func buildGraphFromPlan(plan *apply.Plan) *dag.Graph {
 			props.EstimatedDuration = 30 * time.Second
 		}
 
+		// Prefer the plan's estimates, which use recorded history where available
+		if op.Impact != nil && op.Impact.EstimatedDuration > 0 {
+			props.EstimatedDuration = op.Impact.EstimatedDuration
+			if op.Impact.DurationSamples > 0 {
+				props.MinDuration = op.Impact.EstimatedDuration
+				props.MaxDuration = op.Impact.EstimatedDurationP90
+			}
+		}
+
 		// Determine risk level
 		switch op.Type {
 		case apply.OperationDelete:
@@ -222,6 +237,11 @@ func buildGraphFromPlan(plan *apply.Plan) *dag.Graph {
 		}
 	}
 
+	// Add hooks as nodes around their operations
+	for _, op := range plan.Operations {
+		addHookNodes(graph, op)
+	}
+
 	// Add dependencies as edges
 	for _, op := range plan.Operations {
 		for _, depID := range op.Dependencies {
@@ -241,3 +261,44 @@ func buildGraphFromPlan(plan *apply.Plan) *dag.Graph {
 
 	return graph
 }
+
+// hookResourceType is the node type of operation hooks in plan graphs
+const hookResourceType types.ResourceKind = "Hook"
+
+// addHookNodes adds a node per hook of an operation. preApply hooks run before the
+// operation, postApply hooks after it, and onFailure hooks only when it fails.
+func addHookNodes(graph *dag.Graph, op apply.PlannedOperation) {
+	if op.Type == apply.OperationNoChange {
+		return
+	}
+	phases := []apply.HookPhase{apply.HookPhasePreApply, apply.HookPhasePostApply, apply.HookPhaseOnFailure}
+	for _, phase := range phases {
+		for i, hook := range apply.HooksFor(op.Hooks, phase, op.Type) {
+			node := &dag.Node{
+				ID:           fmt.Sprintf("%s-%s-%d", op.ID, phase, i),
+				Name:         apply.HookDisplayName(hook),
+				ResourceType: hookResourceType,
+				Properties: dag.NodeProperties{
+					EstimatedDuration: time.Second,
+					RiskLevel:         dag.RiskLevelLow,
+				},
+				Labels: map[string]string{"hook.phase": string(phase), "hook.operation": op.ID},
+			}
+			if err := graph.AddNode(node); err != nil {
+				continue
+			}
+
+			// Edge direction: From=dependent, To=dependency
+			var edge *dag.Edge
+			switch phase {
+			case apply.HookPhasePreApply:
+				edge = &dag.Edge{From: op.ID, To: node.ID, Type: dag.DependencyTypeHard, Weight: 1.0, Reason: "preApply hook"}
+			case apply.HookPhasePostApply:
+				edge = &dag.Edge{From: node.ID, To: op.ID, Type: dag.DependencyTypeHard, Weight: 1.0, Reason: "postApply hook"}
+			default:
+				edge = &dag.Edge{From: node.ID, To: op.ID, Type: dag.DependencyTypeConditional, Weight: 0.5, Reason: "onFailure hook, runs only when the operation fails"}
+			}
+			_ = graph.AddEdge(edge)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package infra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+const hooksDocument = `apiVersion: matlas.mongodb.com/v1
+kind: ApplyDocument
+metadata:
+  name: hooks
+hooks:
+  preApply:
+    - name: change-window
+      command: ./scripts/check-change-window.sh
+resources:
+  - apiVersion: matlas.mongodb.com/v1
+    kind: Cluster
+    metadata:
+      name: main
+    spec:
+      provider: AWS
+      region: US_EAST_1
+      instanceSize: M10
+    hooks:
+      postApply:
+        - webhook:
+            url: https://hooks.example.com/deploy
+      onFailure:
+        - command: ./scripts/page-oncall.sh
+`
+
+func TestBuildGraphFromPlan_HookNodes(t *testing.T) {
+	var doc types.ApplyDocument
+	require.NoError(t, yaml.Unmarshal([]byte(hooksDocument), &doc))
+
+	state, err := buildDesiredState([]*apply.LoadResult{{Config: &doc}})
+	require.NoError(t, err)
+
+	plan, err := apply.NewPlanBuilder("proj").
+		WithHooks(state.Hooks).
+		AddOperation(apply.Operation{Type: apply.OperationCreate, ResourceType: types.KindCluster, ResourceName: "main", Desired: &state.Clusters[0]}).
+		AddOperation(apply.Operation{Type: apply.OperationNoChange, ResourceType: types.KindNetworkAccess, ResourceName: "office"}).
+		Build()
+	require.NoError(t, err)
+
+	hooks := plan.Operations[0].Hooks
+	require.NotNil(t, hooks)
+	assert.Len(t, hooks.PreApply, 1)
+	assert.Len(t, hooks.PostApply, 1)
+	assert.Len(t, hooks.OnFailure, 1)
+	assert.Nil(t, plan.Operations[1].Hooks, "operations without changes run no hooks")
+
+	graph := buildGraphFromPlan(plan)
+	assert.Equal(t, 5, graph.NodeCount())
+
+	pre := graph.Nodes["op-0-preApply-0"]
+	require.NotNil(t, pre)
+	assert.Equal(t, "change-window", pre.Name)
+	assert.Equal(t, types.ResourceKind("Hook"), pre.ResourceType)
+	assert.Equal(t, "preApply", pre.Labels["hook.phase"])
+
+	// The operation waits for its preApply hook; postApply and onFailure hooks wait for the operation
+	assert.Contains(t, graph.GetDependencies("op-0"), "op-0-preApply-0")
+	assert.Contains(t, graph.GetDependencies("op-0-postApply-0"), "op-0")
+	assert.Equal(t, "webhook", graph.Nodes["op-0-postApply-0"].Name, "webhook URLs are not shown")
+
+	onFailure := graph.Nodes["op-0-onFailure-0"]
+	require.NotNil(t, onFailure)
+	require.Len(t, onFailure.Dependencies, 1)
+	assert.Equal(t, dag.DependencyTypeConditional, onFailure.Dependencies[0].Type)
+}
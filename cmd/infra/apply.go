@@ -2,6 +2,7 @@ package infra
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,6 +13,8 @@ import (
 	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
 
 	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+	"github.com/teabranch/matlas-cli/internal/apply/policy"
 	"github.com/teabranch/matlas-cli/internal/config"
 	"github.com/teabranch/matlas-cli/internal/logging"
 	"github.com/teabranch/matlas-cli/internal/output"
@@ -36,6 +39,19 @@ type ApplyOptions struct {
 	Watch            bool
 	WatchInterval    time.Duration
 	PreserveExisting bool
+	WaitForClusters  bool
+	Resume           string
+	Policies         []string
+
+	// inputs are the expanded configuration files, recorded so the execution can be resumed
+	inputs []string
+	// resumeState is the interrupted execution being resumed
+	resumeState *dag.ExecutionState
+	// policyEngine holds the compiled --policy rules enforced before execution
+	policyEngine *policy.Engine
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewInfraCmd creates the infra command for declarative configuration
@@ -72,7 +88,10 @@ It supports dry-run mode to preview changes before applying them.`,
   matlas infra -f config.yaml --dry-run --output json
 
   # Watch mode for continuous reconciliation
-  matlas infra -f config.yaml --watch`,
+  matlas infra -f config.yaml --watch
+
+  # Resume an interrupted apply
+  matlas infra apply --resume exec-20261018-093000-4f2a1c`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Support positional arguments as files if no --file flag provided
 			if len(opts.Files) == 0 && len(args) > 0 {
@@ -84,6 +103,7 @@ It supports dry-run mode to preview changes before applying them.`,
 
 	// File input flags
 	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to apply (supports glob patterns and stdin with '-')")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
 
 	// Dry run flags
 	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be applied without making changes")
@@ -92,7 +112,7 @@ It supports dry-run mode to preview changes before applying them.`,
 	// Output and behavior flags
 	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, summary, detailed")
 	cmd.Flags().BoolVar(&opts.AutoApprove, "auto-approve", false, "Skip interactive approval prompts")
-	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "Timeout for the apply operation")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "Timeout for the apply operation (not applied with --wait unless set)")
 	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Enable verbose output")
 	cmd.Flags().BoolVar(&opts.NoColor, "no-color", false, "Disable colored output")
 
@@ -104,6 +124,9 @@ It supports dry-run mode to preview changes before applying them.`,
 
 	// Safety flags
 	cmd.Flags().BoolVar(&opts.PreserveExisting, "preserve-existing", false, "Only add new resources, never delete existing ones")
+	cmd.Flags().BoolVar(&opts.WaitForClusters, "wait", false, "Wait for cluster changes to finish provisioning (records their duration for plan ETAs)")
+	cmd.Flags().StringVar(&opts.Resume, "resume", "", "Resume an interrupted execution, skipping operations that already completed")
+	cmd.Flags().StringSliceVar(&opts.Policies, "policy", []string{}, "Policy files or directories; deny findings stop the apply before any change (repeatable)")
 
 	// Watch mode flags
 	cmd.Flags().BoolVar(&opts.Watch, "watch", false, "Enable watch mode for continuous reconciliation")
@@ -118,24 +141,39 @@ It supports dry-run mode to preview changes before applying them.`,
 	cmd.AddCommand(NewAnalyzeCmd())
 	cmd.AddCommand(NewVisualizeCmd())
 	cmd.AddCommand(NewOptimizeCmd())
+	cmd.AddCommand(NewExecutionsCmd())
+	cmd.AddCommand(NewRollbackCmd())
+	cmd.AddCommand(NewOutputCmd())
 
 	return cmd
 }
 
 func runApply(cmd *cobra.Command, opts *ApplyOptions) error {
-	ctx, cancel := context.WithTimeout(cmd.Context(), opts.Timeout)
+	ctx, cancel := applyContext(cmd, opts)
 	defer cancel()
 
+	// Resuming fills in the files and project recorded by the interrupted execution
+	if opts.Resume != "" {
+		if err := prepareResume(opts); err != nil {
+			return err
+		}
+	}
+
 	// Validate options
 	if err := validateApplyOptions(opts); err != nil {
 		return fmt.Errorf("invalid options: %w", err)
 	}
 
 	// Expand file patterns and handle stdin
-	files, err := expandFilePatterns(opts.Files)
+	files, err := opts.configFiles(opts.Files)
 	if err != nil {
 		return fmt.Errorf("failed to expand file patterns: %w", err)
 	}
+	opts.inputs = files
+
+	if opts.policyEngine, err = loadPolicyEngine(opts.Policies); err != nil {
+		return err
+	}
 
 	// Load and parse configuration files first
 	configs, err := loadConfigurations(files, opts)
@@ -177,6 +215,9 @@ func loadConfigurations(files []string, opts *ApplyOptions) ([]*apply.LoadResult
 		AllowStdin:   true,
 		MaxFileSize:  10 * 1024 * 1024, // 10MB
 	}
+	if err := opts.loaderOptions(loaderOpts); err != nil {
+		return nil, err
+	}
 
 	loader := apply.NewConfigurationLoader(loaderOpts)
 
@@ -206,6 +247,10 @@ func loadConfigurations(files []string, opts *ApplyOptions) ([]*apply.LoadResult
 		}
 	}
 
+	if err := checkOverlayMatched(loaderOpts.Overlay); err != nil {
+		return nil, err
+	}
+
 	return configs, nil
 }
 
@@ -216,6 +261,9 @@ type ServiceClients struct {
 	ProjectsService      *atlas.ProjectsService
 	SearchService        *atlas.SearchService
 	VPCEndpointsService  *atlas.VPCEndpointsService
+	LDAPService          *atlas.LDAPConfigurationService
+	IntegrationsService  *atlas.IntegrationsService
+	FederationService    *atlas.FederationService
 	DatabaseService      *database.Service
 }
 
@@ -233,6 +281,9 @@ func initializeServices(cfg *config.Config) (*ServiceClients, error) {
 	projectsService := atlas.NewProjectsService(atlasClient)
 	searchService := atlas.NewSearchService(atlasClient)
 	vpcEndpointsService := atlas.NewVPCEndpointsService(atlasClient)
+	ldapService := atlas.NewLDAPConfigurationService(atlasClient)
+	integrationsService := atlas.NewIntegrationsService(atlasClient)
+	federationService := atlas.NewFederationService(atlasClient)
 
 	// Initialize database service with standardized logger
 	logger := logging.Default()
@@ -245,16 +296,52 @@ func initializeServices(cfg *config.Config) (*ServiceClients, error) {
 		ProjectsService:      projectsService,
 		SearchService:        searchService,
 		VPCEndpointsService:  vpcEndpointsService,
+		LDAPService:          ldapService,
+		IntegrationsService:  integrationsService,
+		FederationService:    federationService,
 		DatabaseService:      databaseService,
 	}, nil
 }
 
+// newStatusFetcher reads the status of referenced resources and data sources in a project
+func newStatusFetcher(services *ServiceClients, projectID string) *apply.AtlasStatusFetcher {
+	return &apply.AtlasStatusFetcher{
+		ProjectID:     projectID,
+		Clusters:      services.ClustersService,
+		Users:         services.UsersService,
+		NetworkAccess: services.NetworkAccessService,
+		Projects:      services.ProjectsService,
+		VPCEndpoints:  services.VPCEndpointsService,
+	}
+}
+
+// resolveDataSources looks up the data sources of a desired state; a missing object fails planning
+func resolveDataSources(ctx context.Context, services *ServiceClients, projectID string, state *apply.ProjectState) ([]apply.DataSource, error) {
+	if len(state.DataSources) == 0 {
+		return nil, nil
+	}
+	sources, err := apply.ResolveDataSources(ctx, newStatusFetcher(services, projectID), state.DataSources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve data sources: %w", err)
+	}
+	return sources, nil
+}
+
 func performApply(ctx context.Context, configs []*apply.LoadResult, services *ServiceClients, cfg *config.Config, opts *ApplyOptions) error {
 	// Build desired state from configurations first
 	desiredState, err := buildDesiredState(configs)
 	if err != nil {
 		return fmt.Errorf("failed to build desired state: %w", err)
 	}
+	configHash, err := apply.ConfigurationHash(desiredState)
+	if err != nil {
+		return err
+	}
+	if opts.resumeState != nil {
+		if err := apply.CheckResumeConfiguration(opts.resumeState, configHash); err != nil {
+			return err
+		}
+	}
 
 	projectNameOrID := getProjectID(configs, opts)
 
@@ -291,30 +378,34 @@ func performApply(ctx context.Context, configs []*apply.LoadResult, services *Se
 	diffEngine := apply.NewDiffEngine()
 	diffEngine.PreserveExisting = opts.PreserveExisting
 
-	planOptimizer := apply.NewPlanOptimizer()
+	durationHistory := loadDurationHistory()
+	planOptimizer := apply.NewPlanOptimizer().WithDurationHistory(durationHistory)
 
 	// Create enhanced executor
 	// Propagate preserve-existing intent into executor config for typed conflict handling
 	enhancedCfg := apply.DefaultEnhancedExecutorConfig()
 	enhancedCfg.BaseConfig.PreserveExisting = opts.PreserveExisting
+	enhancedCfg.BaseConfig.WaitForClusters = opts.WaitForClusters
 
-	enhancedExecutor := apply.NewEnhancedExecutor(
-		services.ClustersService,
-		services.UsersService,
-		services.NetworkAccessService,
-		services.ProjectsService,
-		services.SearchService,
-		services.VPCEndpointsService,
-		services.DatabaseService,
-		enhancedCfg,
-	)
+	enhancedExecutor := newInfraExecutor(services, enhancedCfg)
+	enhancedExecutor.SetDurationHistory(durationHistory)
+
+	// Journal the execution so it can be resumed or rolled back
+	journal := newExecutionJournal()
+	enhancedExecutor.SetJournal(journal)
 
 	// Discover current state
 	if opts.Verbose {
 		fmt.Printf("Discovering current state for project %s (resolved from '%s')...\n", resolvedProjectID, projectNameOrID)
 	}
 
-	currentState, err := discoveryService.DiscoverProject(ctx, resolvedProjectID)
+	if databaseClient, cleanup := newInfraDatabaseClient(services, resolvedProjectID, desiredState); databaseClient != nil {
+		defer cleanup()
+		discoveryService.SetDatabaseClient(databaseClient)
+		enhancedExecutor.SetDatabaseClient(databaseClient)
+	}
+
+	currentState, err := discoverTargetState(ctx, discoveryService, resolvedProjectID, desiredState)
 	if err != nil {
 		return fmt.Errorf("failed to discover current state: %w", err)
 	}
@@ -329,8 +420,13 @@ func performApply(ctx context.Context, configs []*apply.LoadResult, services *Se
 		return fmt.Errorf("failed to compute diff: %w", err)
 	}
 
+	dataSources, err := resolveDataSources(ctx, services, resolvedProjectID, desiredState)
+	if err != nil {
+		return err
+	}
+
 	// Create execution plan
-	planBuilder := apply.NewPlanBuilder(resolvedProjectID)
+	planBuilder := apply.NewPlanBuilder(resolvedProjectID).WithDurationHistory(durationHistory).WithHooks(desiredState.Hooks).WithDataSources(dataSources)
 
 	// Add operations from diff
 	planBuilder.AddOperations(diff.Operations)
@@ -348,6 +444,29 @@ func performApply(ctx context.Context, configs []*apply.LoadResult, services *Se
 
 	optimizedPlan := optimizationResult.OptimizedPlan
 
+	// Align the plan with the interrupted execution: completed operations confirmed by
+	// live state are skipped and the rest keep their original stage order
+	if opts.resumeState != nil {
+		summary := apply.PrepareResume(optimizedPlan, opts.resumeState)
+		if err := displayResumeSummary(opts.resumeState.ExecutionID, summary); err != nil {
+			return err
+		}
+	}
+
+	// Enforce policies before anything is changed
+	report, err := evaluatePolicies(opts.policyEngine, optimizedPlan)
+	if err != nil {
+		return err
+	}
+	if report != nil {
+		if err := displayPolicyReport(report, opts.OutputFormat); err != nil {
+			return err
+		}
+		if report.Denied() {
+			return policyDenyError(report)
+		}
+	}
+
 	// Show plan summary and get approval
 	if !opts.AutoApprove && optimizedPlan.Summary.RequiresApproval {
 		if err := showPlanAndGetApproval(optimizedPlan, opts); err != nil {
@@ -360,15 +479,80 @@ func performApply(ctx context.Context, configs []*apply.LoadResult, services *Se
 		fmt.Println("Executing plan...")
 	}
 
+	if opts.resumeState != nil {
+		if err := journal.Resume(opts.resumeState, optimizedPlan); err != nil {
+			return fmt.Errorf("failed to resume execution %s: %w", opts.resumeState.ExecutionID, err)
+		}
+	} else if optimizedPlan.Summary.TotalOperations > optimizedPlan.Summary.OperationsByType[apply.OperationNoChange] {
+		if err := journal.Begin(apply.NewExecutionID(time.Now()), optimizedPlan, opts.inputs, configHash); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record execution, it cannot be resumed: %v\n", err)
+		}
+	}
+	if executionID := journal.ExecutionID(); executionID != "" {
+		fmt.Printf("Execution ID: %s\n", executionID)
+	}
+
 	result, err := enhancedExecutor.Execute(ctx, optimizedPlan)
+	saveDurationHistory(durationHistory)
 	if err != nil {
 		return fmt.Errorf("failed to execute plan: %w", err)
 	}
 
+	if (result.Status != apply.PlanStatusCompleted || ctx.Err() != nil) && journal.ExecutionID() != "" {
+		defer fmt.Fprintf(os.Stderr, "Resume with: matlas infra apply --resume %s\n", journal.ExecutionID())
+	}
+	if !opts.WaitForClusters && hasClusterChanges(optimizedPlan) {
+		defer fmt.Fprintln(os.Stderr, "Note: cluster changes were not waited for, so their provisioning time was not recorded for plan ETAs; apply with --wait to record it")
+	}
+
 	// Display results
 	return displayExecutionResults(result, opts)
 }
 
+// applyContext bounds an apply by --timeout. With --wait and no explicit --timeout the
+// apply is left unbounded: cluster changes can take longer than the default, and each
+// cluster wait has its own limit.
+func applyContext(cmd *cobra.Command, opts *ApplyOptions) (context.Context, context.CancelFunc) {
+	if opts.WaitForClusters && !cmd.Flags().Changed("timeout") {
+		return context.WithCancel(cmd.Context())
+	}
+	return context.WithTimeout(cmd.Context(), opts.Timeout)
+}
+
+// hasClusterChanges reports whether a plan creates, updates or deletes a cluster
+func hasClusterChanges(plan *apply.Plan) bool {
+	for _, op := range plan.Operations {
+		if op.ResourceType == types.KindCluster && op.Type != apply.OperationNoChange {
+			return true
+		}
+	}
+	return false
+}
+
+// newInfraExecutor creates an enhanced executor wired to every service the apply engine uses
+func newInfraExecutor(services *ServiceClients, executorCfg apply.EnhancedExecutorConfig) *apply.EnhancedExecutor {
+	executor := apply.NewEnhancedExecutor(
+		services.ClustersService,
+		services.UsersService,
+		services.NetworkAccessService,
+		services.ProjectsService,
+		services.SearchService,
+		services.VPCEndpointsService,
+		services.DatabaseService,
+		executorCfg,
+	)
+	executor.SetLDAPConfigurationService(services.LDAPService)
+	executor.SetIntegrationsService(services.IntegrationsService)
+	executor.SetFederationService(services.FederationService)
+	return executor
+}
+
+// newExecutionJournal creates a journal backed by the local state, checkpoint and rollback stores
+func newExecutionJournal() *apply.ExecutionJournal {
+	return apply.NewExecutionJournal(dag.NewStateManager(""), dag.NewCheckpointManager(dag.CheckpointConfig{})).
+		WithRollbackStore(apply.NewRollbackStore(""))
+}
+
 func runWatchMode(ctx context.Context, configs []*apply.LoadResult, services *ServiceClients, cfg *config.Config, opts *ApplyOptions) error {
 	fmt.Printf("Starting watch mode with %d minute intervals...\n", int(opts.WatchInterval.Minutes()))
 
@@ -457,6 +641,14 @@ func getProjectID(configs []*apply.LoadResult, opts *ApplyOptions) string {
 						}
 					}
 				}
+				// Also check Integration resources for project information
+				if resource.Kind == types.KindIntegration {
+					if spec, ok := resource.Spec.(map[string]interface{}); ok {
+						if projectName, ok := spec["projectName"].(string); ok && projectName != "" && projectName != "your-project-id" {
+							return projectName
+						}
+					}
+				}
 				// Also check Cluster resources for project information
 				if resource.Kind == types.KindCluster {
 					if spec, ok := resource.Spec.(map[string]interface{}); ok {
@@ -550,6 +742,60 @@ func getOrganizationID(configs []*apply.LoadResult) string {
 	return ""
 }
 
+// discoverTargetState discovers the project state and the organization-scoped resources
+// (federation settings and role mappings) referenced by the desired state. Documents that
+// only declare organization-scoped resources don't need a project.
+func discoverTargetState(ctx context.Context, discovery *apply.AtlasStateDiscovery, projectID string, desired *apply.ProjectState) (*apply.ProjectState, error) {
+	var current *apply.ProjectState
+	if projectID == "" && isOrganizationScoped(desired) {
+		current = &apply.ProjectState{}
+	} else {
+		var err error
+		current, err = discovery.DiscoverProject(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := discovery.DiscoverOrganizationResources(ctx, desired, current); err != nil {
+		return nil, err
+	}
+	if err := discovery.DiscoverDatabaseResources(ctx, desired, current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// newInfraDatabaseClient creates the client used to discover and manage Collection, Index,
+// View and MaterializedView resources when the desired state declares any. Connections go
+// through temporary database users that the returned cleanup function removes.
+func newInfraDatabaseClient(services *ServiceClients, projectID string, desired *apply.ProjectState) (*apply.ClusterDatabaseClient, func()) {
+	if desired == nil || !desired.HasDatabaseResources() {
+		return nil, func() {}
+	}
+	connector := database.NewClusterConnector(services.DatabaseService, services.ClustersService, services.UsersService, projectID)
+	cleanup := func() {
+		// Use a fresh context so temporary users are removed even after cancellation
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := connector.Close(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return apply.NewClusterDatabaseClient(services.DatabaseService, connector), cleanup
+}
+
+// isOrganizationScoped reports whether the desired state only declares organization-scoped resources
+func isOrganizationScoped(state *apply.ProjectState) bool {
+	if state == nil || (len(state.FederationSettings) == 0 && len(state.RoleMappings) == 0) {
+		return false
+	}
+	return state.Project == nil && len(state.Clusters) == 0 && len(state.DatabaseUsers) == 0 &&
+		len(state.DatabaseRoles) == 0 && len(state.NetworkAccess) == 0 && len(state.SearchIndexes) == 0 &&
+		len(state.VPCEndpoints) == 0 && len(state.LDAPConfigurations) == 0 && len(state.Integrations) == 0 &&
+		!state.HasDatabaseResources()
+}
+
 func buildDesiredState(configs []*apply.LoadResult) (*apply.ProjectState, error) {
 	state := &apply.ProjectState{
 		Project:       nil,
@@ -630,6 +876,7 @@ func buildDesiredState(configs []*apply.LoadResult) (*apply.ProjectState, error)
 				Password:     user.Password,
 				Roles:        user.Roles,
 				AuthDatabase: user.AuthDatabase,
+				AuthType:     user.AuthType,
 				Scopes:       user.Scopes,
 			}
 			manifest := types.DatabaseUserManifest{
@@ -653,6 +900,8 @@ func buildDesiredState(configs []*apply.LoadResult) (*apply.ProjectState, error)
 				AWSSecurityGroup: access.AWSSecurityGroup,
 				Comment:          access.Comment,
 				DeleteAfterDate:  access.DeleteAfterDate,
+				ExpiresAt:        access.ExpiresAt,
+				TTL:              access.TTL,
 			}
 			manifest := types.NetworkAccessManifest{
 				APIVersion: types.APIVersionV1,
@@ -669,6 +918,20 @@ func buildDesiredState(configs []*apply.LoadResult) (*apply.ProjectState, error)
 
 // mergeApplyDocumentToState merges an ApplyDocument into the project state
 func mergeApplyDocumentToState(state *apply.ProjectState, applyDoc *types.ApplyDocument) error {
+	if state.Hooks == nil {
+		state.Hooks = apply.NewOperationHooks()
+	}
+	state.Hooks.AddDocument(applyDoc)
+	for name, expression := range applyDoc.Outputs {
+		if existing, ok := state.Outputs[name]; ok && existing != expression {
+			return fmt.Errorf("output %s is declared more than once", name)
+		}
+		if state.Outputs == nil {
+			state.Outputs = make(map[string]string)
+		}
+		state.Outputs[name] = expression
+	}
+
 	for _, resource := range applyDoc.Resources {
 		switch resource.Kind {
 		case types.KindProject:
@@ -803,11 +1066,152 @@ func mergeApplyDocumentToState(state *apply.ProjectState, applyDoc *types.ApplyD
 				Spec:       spec,
 			}
 			state.VPCEndpoints = append(state.VPCEndpoints, manifest)
+
+		case types.KindLDAPConfiguration:
+			ldapSpec, ok := resource.Spec.(types.LDAPConfigurationSpec)
+			if !ok {
+				if err := decodeResourceSpec(resource.Spec, &ldapSpec); err != nil {
+					return fmt.Errorf("invalid LDAPConfiguration spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.LDAPConfigurations = append(state.LDAPConfigurations, types.LDAPConfigurationManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       ldapSpec,
+			})
+
+		case types.KindIntegration:
+			integrationSpec, ok := resource.Spec.(types.IntegrationSpec)
+			if !ok {
+				if err := decodeResourceSpec(resource.Spec, &integrationSpec); err != nil {
+					return fmt.Errorf("invalid Integration spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.Integrations = append(state.Integrations, types.IntegrationManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       integrationSpec,
+			})
+
+		case types.KindCollection:
+			collectionSpec, ok := resource.Spec.(types.CollectionSpec)
+			if !ok {
+				if err := decodeResourceSpec(resource.Spec, &collectionSpec); err != nil {
+					return fmt.Errorf("invalid Collection spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.Collections = append(state.Collections, types.CollectionManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       collectionSpec,
+			})
+
+		case types.KindIndex:
+			indexSpec, ok := resource.Spec.(types.IndexSpec)
+			if !ok {
+				// Generic specs are maps, so an object of several keys has already lost its order
+				if raw, isMap := resource.Spec.(map[string]interface{}); isMap {
+					if keys, isObject := raw["keys"].(map[string]interface{}); isObject && len(keys) > 1 {
+						return fmt.Errorf("index keys for %s must be a list to preserve their order, e.g. [\"field:1\", \"other:-1\"]", resource.Metadata.Name)
+					}
+				}
+				if err := decodeResourceSpec(resource.Spec, &indexSpec); err != nil {
+					return fmt.Errorf("invalid Index spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.Indexes = append(state.Indexes, types.IndexManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       indexSpec,
+			})
+
+		case types.KindView:
+			viewSpec, ok := resource.Spec.(types.ViewSpec)
+			if !ok {
+				if err := decodeResourceSpec(resource.Spec, &viewSpec); err != nil {
+					return fmt.Errorf("invalid View spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.Views = append(state.Views, types.ViewManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       viewSpec,
+			})
+
+		case types.KindMaterializedView:
+			materializedSpec, ok := resource.Spec.(types.MaterializedViewSpec)
+			if !ok {
+				if err := decodeResourceSpec(resource.Spec, &materializedSpec); err != nil {
+					return fmt.Errorf("invalid MaterializedView spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.MaterializedViews = append(state.MaterializedViews, types.MaterializedViewManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       materializedSpec,
+			})
+
+		case types.KindFederationSettings:
+			federationSpec, ok := resource.Spec.(types.FederationSettingsSpec)
+			if !ok {
+				if err := decodeResourceSpec(resource.Spec, &federationSpec); err != nil {
+					return fmt.Errorf("invalid FederationSettings spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.FederationSettings = append(state.FederationSettings, types.FederationSettingsManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       federationSpec,
+			})
+
+		case types.KindRoleMapping:
+			mappingSpec, ok := resource.Spec.(types.RoleMappingSpec)
+			if !ok {
+				if err := decodeResourceSpec(resource.Spec, &mappingSpec); err != nil {
+					return fmt.Errorf("invalid RoleMapping spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.RoleMappings = append(state.RoleMappings, types.RoleMappingManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       mappingSpec,
+			})
+
+		case types.KindDataSource:
+			dataSourceSpec, ok := resource.Spec.(types.DataSourceSpec)
+			if !ok {
+				if err := decodeResourceSpec(resource.Spec, &dataSourceSpec); err != nil {
+					return fmt.Errorf("invalid DataSource spec for %s: %w", resource.Metadata.Name, err)
+				}
+			}
+			state.DataSources = append(state.DataSources, types.DataSourceManifest{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Metadata:   resource.Metadata,
+				Spec:       dataSourceSpec,
+			})
 		}
 	}
 	return nil
 }
 
+// decodeResourceSpec converts a generic (map-based) resource spec into a typed spec
+func decodeResourceSpec(spec interface{}, out interface{}) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
 // Helper functions to convert specs from generic interface{} to typed specs
 func convertToClusterSpec(spec interface{}) types.ClusterSpec {
 	// Complete conversion for all ClusterSpec fields
@@ -1112,6 +1516,9 @@ func convertToDatabaseUserSpec(spec interface{}) types.DatabaseUserSpec {
 		if authDatabase, ok := specMap["authDatabase"].(string); ok {
 			userSpec.AuthDatabase = authDatabase
 		}
+		if authType, ok := specMap["authType"].(string); ok {
+			userSpec.AuthType = authType
+		}
 		// Convert roles array
 		if rolesRaw, ok := specMap["roles"].([]interface{}); ok {
 			for _, roleRaw := range rolesRaw {
@@ -1279,7 +1686,9 @@ func performDryRunOnly(ctx context.Context, configs []*apply.LoadResult, opts *A
 func performDryRun(ctx context.Context, desiredState *apply.ProjectState, projectID string, opts *ApplyOptions) error {
 	// For dry run, we create a plan based only on the desired state
 	// without needing to discover current state from Atlas
-	planBuilder := apply.NewPlanBuilder(projectID)
+	durationHistory := loadDurationHistory()
+	planBuilder := apply.NewPlanBuilder(projectID).WithDurationHistory(durationHistory).WithHooks(desiredState.Hooks).
+		WithDataSources(apply.UnresolvedDataSources(desiredState.DataSources))
 
 	// Convert desired state to operations (assuming everything is a create operation for dry run)
 	operations := []apply.Operation{}
@@ -1341,10 +1750,10 @@ func performDryRun(ctx context.Context, desiredState *apply.ProjectState, projec
 	}
 
 	// Run the dry-run executor
-	return runDryRun(ctx, plan, opts)
+	return runDryRun(ctx, plan, durationHistory, opts)
 }
 
-func runDryRun(ctx context.Context, plan *apply.Plan, opts *ApplyOptions) error {
+func runDryRun(ctx context.Context, plan *apply.Plan, durationHistory *apply.DurationHistory, opts *ApplyOptions) error {
 	// Parse dry-run mode
 	var mode apply.DryRunMode
 	switch strings.ToLower(opts.DryRunMode) {
@@ -1360,6 +1769,9 @@ func runDryRun(ctx context.Context, plan *apply.Plan, opts *ApplyOptions) error
 
 	// Create dry-run executor
 	executor := apply.NewDryRunExecutor(mode)
+	if durationHistory != nil {
+		executor.WithTimingEstimator(apply.NewHistoricalTimingEstimator(durationHistory, apply.NewDefaultTimingEstimator()))
+	}
 
 	// Execute dry-run
 	result, err := executor.Execute(ctx, plan)
@@ -1393,6 +1805,17 @@ func runDryRun(ctx context.Context, plan *apply.Plan, opts *ApplyOptions) error
 
 	fmt.Print(output)
 
+	report, err := evaluatePolicies(opts.policyEngine, plan)
+	if err != nil {
+		return err
+	}
+	if err := displayPolicyReport(report, opts.OutputFormat); err != nil {
+		return err
+	}
+	if report != nil && report.Denied() {
+		return policyDenyError(report)
+	}
+
 	// Return error if any operations would fail
 	if result.Summary.OperationsWouldFail > 0 || len(result.Errors) > 0 {
 		return fmt.Errorf("dry-run completed with %d operations that would fail and %d errors",
@@ -1403,8 +1826,8 @@ func runDryRun(ctx context.Context, plan *apply.Plan, opts *ApplyOptions) error
 }
 
 func validateApplyOptions(opts *ApplyOptions) error {
-	if len(opts.Files) == 0 {
-		return fmt.Errorf("at least one configuration file must be specified with --file")
+	if err := opts.requireFiles(opts.Files); err != nil {
+		return err
 	}
 
 	// Validate timeout only if explicitly set to negative (zero means use default)
@@ -1412,6 +1835,11 @@ func validateApplyOptions(opts *ApplyOptions) error {
 		return fmt.Errorf("timeout must be positive")
 	}
 
+	// Resume continues a recorded execution, it cannot preview or reconcile
+	if opts.Resume != "" && (opts.Watch || opts.DryRun) {
+		return fmt.Errorf("--resume cannot be used with --watch or --dry-run")
+	}
+
 	// Watch mode is incompatible with dry-run (check conflict first)
 	if opts.Watch && opts.DryRun {
 		return fmt.Errorf("watch mode cannot be used with dry-run")
@@ -2,6 +2,7 @@ package infra
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/types"
 )
 
 func TestNewInfraCmd(t *testing.T) {
@@ -103,6 +105,16 @@ func TestApplyOptionsValidation(t *testing.T) {
 			expectError: true,
 			errorMsg:    "watch mode cannot be used with dry-run",
 		},
+		{
+			name: "resume with dry-run",
+			opts: &ApplyOptions{
+				Files:  []string{"config.yaml"},
+				Resume: "exec-20261018-093000",
+				DryRun: true,
+			},
+			expectError: true,
+			errorMsg:    "--resume cannot be used with --watch or --dry-run",
+		},
 	}
 
 	for _, tt := range tests {
@@ -414,3 +426,72 @@ func TestGetProjectID(t *testing.T) {
 	projectID = getProjectID(configs, opts)
 	assert.Equal(t, "", projectID)
 }
+
+func TestBuildDesiredState_DataSourcesAreNeverDiffed(t *testing.T) {
+	doc := &types.ApplyDocument{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindApplyDocument,
+		Resources: []types.ResourceManifest{{
+			APIVersion: types.APIVersionV1,
+			Kind:       types.KindDataSource,
+			Metadata:   types.ResourceMetadata{Name: "shared"},
+			Spec:       map[string]interface{}{"type": "Project", "name": "shared-prod"},
+		}},
+	}
+
+	state, err := buildDesiredState([]*apply.LoadResult{{Config: doc}})
+	require.NoError(t, err)
+	require.Len(t, state.DataSources, 1)
+	assert.Equal(t, types.KindProject, state.DataSources[0].Spec.Type)
+	assert.Nil(t, state.Project, "a Project data source is not a Project resource")
+
+	diff, err := apply.NewDiffEngine().ComputeProjectDiff(state, &apply.ProjectState{})
+	require.NoError(t, err)
+	assert.Empty(t, diff.Operations)
+}
+
+func TestHasClusterChanges(t *testing.T) {
+	op := func(opType apply.OperationType, kind types.ResourceKind) apply.PlannedOperation {
+		return apply.PlannedOperation{Operation: apply.Operation{Type: opType, ResourceType: kind}}
+	}
+
+	assert.True(t, hasClusterChanges(&apply.Plan{Operations: []apply.PlannedOperation{
+		op(apply.OperationCreate, types.KindDatabaseUser),
+		op(apply.OperationUpdate, types.KindCluster),
+	}}))
+	assert.False(t, hasClusterChanges(&apply.Plan{Operations: []apply.PlannedOperation{
+		op(apply.OperationNoChange, types.KindCluster),
+		op(apply.OperationDelete, types.KindNetworkAccess),
+	}}))
+}
+
+func TestApplyContext(t *testing.T) {
+	newCmd := func(args ...string) (*cobra.Command, *ApplyOptions) {
+		opts := &ApplyOptions{}
+		cmd := &cobra.Command{Use: "apply"}
+		cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "")
+		cmd.Flags().BoolVar(&opts.WaitForClusters, "wait", false, "")
+		require.NoError(t, cmd.ParseFlags(args))
+		cmd.SetContext(context.Background())
+		return cmd, opts
+	}
+
+	cmd, opts := newCmd()
+	ctx, cancel := applyContext(cmd, opts)
+	_, hasDeadline := ctx.Deadline()
+	cancel()
+	assert.True(t, hasDeadline, "applies are bounded by the default timeout")
+
+	cmd, opts = newCmd("--wait")
+	ctx, cancel = applyContext(cmd, opts)
+	_, hasDeadline = ctx.Deadline()
+	cancel()
+	assert.False(t, hasDeadline, "--wait lifts the default timeout")
+
+	cmd, opts = newCmd("--wait", "--timeout", "2h")
+	ctx, cancel = applyContext(cmd, opts)
+	deadline, hasDeadline := ctx.Deadline()
+	cancel()
+	assert.True(t, hasDeadline, "an explicit timeout is kept with --wait")
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), deadline, time.Minute)
+}
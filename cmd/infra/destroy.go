@@ -30,6 +30,9 @@ type DestroyOptions struct {
 	DeleteSnapshots bool
 	TargetResource  string
 	DiscoveryOnly   bool
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewDestroyCmd creates the destroy subcommand
@@ -78,6 +81,7 @@ unless --auto-approve is used.`,
 
 	// File input flags
 	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files defining resources to destroy")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
 
 	// Output flags
 	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, summary")
@@ -89,7 +93,7 @@ unless --auto-approve is used.`,
 	cmd.Flags().BoolVar(&opts.Force, "force", false, "Force deletion even with dependencies or protection")
 	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show what would be destroyed without actually deleting")
 	cmd.Flags().BoolVar(&opts.DeleteSnapshots, "delete-snapshots", false, "Also delete any cluster snapshots")
-	cmd.Flags().StringVar(&opts.TargetResource, "target", "", "Only destroy specific resource type: clusters, users, network-access")
+	cmd.Flags().StringVar(&opts.TargetResource, "target", "", "Only destroy specific resource type: clusters, users, network-access, integrations")
 	cmd.Flags().BoolVar(&opts.DiscoveryOnly, "discovery-only", false, "Destroy all discovered resources, regardless of configuration files")
 
 	// Configuration flags
@@ -119,7 +123,7 @@ func runDestroy(cmd *cobra.Command, opts *DestroyOptions) error {
 	var files []string
 	if !opts.DiscoveryOnly {
 		var err error
-		files, err = expandFilePatterns(opts.Files)
+		files, err = opts.configFiles(opts.Files)
 		if err != nil {
 			return fmt.Errorf("failed to expand file patterns: %w", err)
 		}
@@ -140,8 +144,9 @@ func runDestroy(cmd *cobra.Command, opts *DestroyOptions) error {
 	var configs []*apply.LoadResult
 	if !opts.DiscoveryOnly {
 		configs, err = loadConfigurations(files, &ApplyOptions{
-			StrictEnv: opts.StrictEnv,
-			Verbose:   opts.Verbose,
+			ConfigInputs: opts.ConfigInputs,
+			StrictEnv:    opts.StrictEnv,
+			Verbose:      opts.Verbose,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to load configurations: %w", err)
@@ -446,6 +451,39 @@ func createDestroyPlan(ctx context.Context, configs []*apply.LoadResult, service
 				operations = append(operations, op)
 			}
 		}
+
+		// Add third-party integration destroy operations (matched by integration type)
+		for _, integration := range desiredState.Integrations {
+			integrationType := strings.ToUpper(integration.Spec.Type)
+			var currentIntegration *types.IntegrationManifest
+			for i := range currentState.Integrations {
+				if strings.ToUpper(currentState.Integrations[i].Spec.Type) == integrationType {
+					currentIntegration = &currentState.Integrations[i]
+					break
+				}
+			}
+			if currentIntegration != nil {
+				op := apply.PlannedOperation{
+					Operation: apply.Operation{
+						Type:         apply.OperationDelete,
+						ResourceType: types.KindIntegration,
+						ResourceName: integration.Metadata.Name,
+						Current:      currentIntegration,
+						Impact: &apply.OperationImpact{
+							IsDestructive:     false,
+							RequiresDowntime:  false,
+							EstimatedDuration: 30 * time.Second,
+							RiskLevel:         apply.RiskLevelMedium,
+							Warnings:          []string{"Alert notifications and metrics sent through this integration will stop"},
+						},
+					},
+					ID:       fmt.Sprintf("destroy-integration-%s", strings.ToLower(integrationType)),
+					Priority: 150,
+					Status:   apply.OperationStatusPending,
+				}
+				operations = append(operations, op)
+			}
+		}
 	}
 
 	// Set up dependencies (delete users and network access before clusters)
@@ -504,6 +542,8 @@ func filterDesiredStateByTarget(state *apply.ProjectState, target string) *apply
 		filtered.DatabaseUsers = state.DatabaseUsers
 	case "network-access", "network":
 		filtered.NetworkAccess = state.NetworkAccess
+	case "integrations", "integration":
+		filtered.Integrations = state.Integrations
 	default:
 		// Invalid target, return empty state
 		return filtered
@@ -648,19 +688,13 @@ func executeDestroyPlan(ctx context.Context, plan *apply.Plan, services *Service
 	fmt.Printf("Executing destroy plan...\n")
 
 	// Create enhanced executor
-	enhancedExecutor := apply.NewEnhancedExecutor(
-		services.ClustersService,
-		services.UsersService,
-		services.NetworkAccessService,
-		services.ProjectsService,
-		services.SearchService,
-		services.VPCEndpointsService,
-		services.DatabaseService,
-		apply.DefaultEnhancedExecutorConfig(),
-	)
+	enhancedExecutor := newInfraExecutor(services, apply.DefaultEnhancedExecutorConfig())
+	durationHistory := loadDurationHistory()
+	enhancedExecutor.SetDurationHistory(durationHistory)
 
 	// Execute the plan
 	result, err := enhancedExecutor.Execute(ctx, plan)
+	saveDurationHistory(durationHistory)
 	if err != nil {
 		return fmt.Errorf("failed to execute destroy plan: %w", err)
 	}
@@ -687,8 +721,8 @@ func executeDestroyPlan(ctx context.Context, plan *apply.Plan, services *Service
 }
 
 func validateDestroyOptions(opts *DestroyOptions) error {
-	if len(opts.Files) == 0 && !opts.DiscoveryOnly {
-		return fmt.Errorf("at least one configuration file must be specified with --file (or use --discovery-only)")
+	if len(opts.Files) == 0 && opts.Overlay == "" && !opts.DiscoveryOnly {
+		return fmt.Errorf("at least one configuration file must be specified with --file or --overlay (or use --discovery-only)")
 	}
 
 	if opts.DiscoveryOnly && opts.ProjectID == "" {
@@ -703,9 +737,9 @@ func validateDestroyOptions(opts *DestroyOptions) error {
 
 	// Validate target resource
 	if opts.TargetResource != "" {
-		validTargets := []string{"clusters", "cluster", "users", "user", "database-users", "network-access", "network"}
+		validTargets := []string{"clusters", "cluster", "users", "user", "database-users", "network-access", "network", "integrations", "integration"}
 		if !contains(validTargets, strings.ToLower(opts.TargetResource)) {
-			return fmt.Errorf("invalid target resource: %s (valid options: clusters, users, network-access)", opts.TargetResource)
+			return fmt.Errorf("invalid target resource: %s (valid options: clusters, users, network-access, integrations)", opts.TargetResource)
 		}
 	}
 
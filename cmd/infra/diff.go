@@ -27,6 +27,9 @@ type DiffOptions struct {
 	Detailed         bool
 	PreserveExisting bool
 	NoTruncate       bool
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewDiffCmd creates the diff subcommand
@@ -65,6 +68,7 @@ It's useful for quickly understanding what resources would be affected.`,
 
 	// File input flags
 	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to diff (supports glob patterns)")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
 
 	// Output flags
 	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, unified, json, yaml, summary")
@@ -93,7 +97,7 @@ func runDiff(cmd *cobra.Command, opts *DiffOptions) error {
 	}
 
 	// Expand file patterns
-	files, err := expandFilePatterns(opts.Files)
+	files, err := opts.configFiles(opts.Files)
 	if err != nil {
 		return fmt.Errorf("failed to expand file patterns: %w", err)
 	}
@@ -111,8 +115,9 @@ func runDiff(cmd *cobra.Command, opts *DiffOptions) error {
 
 	// Load configurations
 	configs, err := loadConfigurations(files, &ApplyOptions{
-		StrictEnv: opts.StrictEnv,
-		Verbose:   opts.Verbose,
+		ConfigInputs: opts.ConfigInputs,
+		StrictEnv:    opts.StrictEnv,
+		Verbose:      opts.Verbose,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to load configurations: %w", err)
@@ -150,6 +155,12 @@ func computeDifferences(ctx context.Context, configs []*apply.LoadResult, servic
 	}
 	diffEngine.PreserveExisting = opts.PreserveExisting
 
+	// Build desired state
+	desiredState, err := buildDesiredState(configs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build desired state: %w", err)
+	}
+
 	// Discover current state
 	if opts.Verbose {
 		fmt.Println("Discovering current state...")
@@ -168,15 +179,14 @@ func computeDifferences(ctx context.Context, configs []*apply.LoadResult, servic
 		resolvedProjectID = id
 	}
 
-	currentState, err := discoveryService.DiscoverProject(ctx, resolvedProjectID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover current state: %w", err)
+	if databaseClient, cleanup := newInfraDatabaseClient(services, resolvedProjectID, desiredState); databaseClient != nil {
+		defer cleanup()
+		discoveryService.SetDatabaseClient(databaseClient)
 	}
 
-	// Build desired state
-	desiredState, err := buildDesiredState(configs)
+	currentState, err := discoverTargetState(ctx, discoveryService, resolvedProjectID, desiredState)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build desired state: %w", err)
+		return nil, fmt.Errorf("failed to discover current state: %w", err)
 	}
 
 	// Compute diff
@@ -226,12 +236,12 @@ func displayDifferences(diff *apply.Diff, opts *DiffOptions) error {
 
 func displayDiffJSON(diff *apply.Diff) error {
 	formatter := output.NewFormatter(config.OutputJSON, os.Stdout)
-	return formatter.Format(diff)
+	return formatter.Format(apply.RedactDiff(diff))
 }
 
 func displayDiffYAML(diff *apply.Diff) error {
 	formatter := output.NewFormatter(config.OutputYAML, os.Stdout)
-	return formatter.Format(diff)
+	return formatter.Format(apply.RedactDiff(diff))
 }
 
 func displayDiffSummary(diff *apply.Diff, opts *DiffOptions) error {
@@ -368,8 +378,8 @@ func displayDiffUnified(diff *apply.Diff, opts *DiffOptions) error {
 }
 
 func validateDiffOptions(opts *DiffOptions) error {
-	if len(opts.Files) == 0 {
-		return fmt.Errorf("at least one configuration file must be specified with --file")
+	if err := opts.requireFiles(opts.Files); err != nil {
+		return err
 	}
 
 	// Validate output format
@@ -0,0 +1,354 @@
+package infra
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+)
+
+// ExecutionsOptions contains the options for the executions commands
+type ExecutionsOptions struct {
+	OutputFormat  string
+	ResumableOnly bool
+}
+
+// NewExecutionsCmd creates the executions subcommand
+func NewExecutionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "executions",
+		Short: "List, inspect, resume or abandon apply executions",
+		Long: `Manage the executions recorded by 'matlas infra apply'.
+
+Every apply records its progress under ~/.matlas/state and checkpoints each completed
+stage under ~/.matlas/checkpoints. An execution interrupted by Ctrl-C, a CI timeout or a
+network failure can be resumed: the plan is regenerated from the same configuration files,
+completed operations are verified against live state and skipped, and pending operations
+continue in their original stage order.`,
+		Example: `  # List recorded executions
+  matlas infra executions list
+
+  # Show the operations of an execution
+  matlas infra executions show exec-20261018-093000-4f2a1c
+
+  # Resume an interrupted execution
+  matlas infra executions resume exec-20261018-093000-4f2a1c
+
+  # Give up on an interrupted execution
+  matlas infra executions abandon exec-20261018-093000-4f2a1c`,
+	}
+
+	cmd.AddCommand(newExecutionsListCmd())
+	cmd.AddCommand(newExecutionsShowCmd())
+	cmd.AddCommand(newExecutionsResumeCmd())
+	cmd.AddCommand(newExecutionsAbandonCmd())
+
+	return cmd
+}
+
+func newExecutionsListCmd() *cobra.Command {
+	opts := &ExecutionsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded apply executions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListExecutions(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, json, yaml")
+	cmd.Flags().BoolVar(&opts.ResumableOnly, "resumable", false, "Only list executions that can be resumed")
+
+	return cmd
+}
+
+func newExecutionsShowCmd() *cobra.Command {
+	opts := &ExecutionsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "show <execution-id>",
+		Short: "Show the operations and checkpoints of an execution",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShowExecution(args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, json, yaml")
+
+	return cmd
+}
+
+func newExecutionsResumeCmd() *cobra.Command {
+	opts := &ApplyOptions{OutputFormat: "table"}
+
+	cmd := &cobra.Command{
+		Use:   "resume <execution-id>",
+		Short: "Resume an interrupted apply execution",
+		Long: `Resume an interrupted apply execution. Equivalent to 'matlas infra apply --resume <execution-id>'.
+
+The plan is regenerated from the configuration files recorded by the execution (or those
+given with --file). Operations that completed before the interruption are verified against
+live state and skipped; any that have drifted are applied again.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Resume = args[0]
+			return runApply(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to use instead of those recorded by the execution")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
+	cmd.Flags().StringVar(&opts.ProjectID, "project-id", "", "Atlas project ID (overrides the recorded project)")
+	cmd.Flags().BoolVar(&opts.AutoApprove, "auto-approve", false, "Skip interactive approval prompts")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "Timeout for the resumed apply")
+	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Enable verbose output")
+	cmd.Flags().BoolVar(&opts.StrictEnv, "strict-env", false, "Fail on undefined environment variables")
+	cmd.Flags().BoolVar(&opts.WaitForClusters, "wait", false, "Wait for cluster changes to finish provisioning")
+
+	return cmd
+}
+
+func newExecutionsAbandonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "abandon <execution-id>",
+		Short: "Mark an interrupted execution as abandoned and delete its checkpoints",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAbandonExecution(args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runListExecutions(opts *ExecutionsOptions) error {
+	states, err := listExecutionStates(dag.NewStateManager(""))
+	if err != nil {
+		return err
+	}
+
+	if opts.ResumableOnly {
+		filtered := states[:0]
+		for _, state := range states {
+			if apply.IsResumable(state) {
+				filtered = append(filtered, state)
+			}
+		}
+		states = filtered
+	}
+
+	if len(states) == 0 && strings.EqualFold(opts.OutputFormat, "table") {
+		fmt.Println("No executions recorded")
+		return nil
+	}
+
+	formatter := output.NewFormatter(config.OutputFormat(strings.ToLower(opts.OutputFormat)), os.Stdout)
+	return output.FormatList(formatter, states,
+		[]string{"EXECUTION ID", "STATUS", "PROJECT", "PROGRESS", "STAGE", "STARTED", "UPDATED"},
+		func(item interface{}) []string {
+			state := item.(*dag.ExecutionState)
+			return []string{
+				state.ExecutionID,
+				string(state.Status),
+				state.ProjectID,
+				fmt.Sprintf("%d/%d", state.CompletedOps, state.TotalOps),
+				fmt.Sprintf("%d/%d", state.CurrentStage+1, state.TotalStages),
+				state.StartedAt.Format(time.RFC3339),
+				state.UpdatedAt.Format(time.RFC3339),
+			}
+		})
+}
+
+func runShowExecution(executionID string, opts *ExecutionsOptions) error {
+	state, err := loadExecutionState(executionID)
+	if err != nil {
+		return err
+	}
+
+	format := config.OutputFormat(strings.ToLower(opts.OutputFormat))
+	formatter := output.NewFormatter(format, os.Stdout)
+	if format == config.OutputJSON || format == config.OutputYAML {
+		return formatter.Format(state)
+	}
+
+	rows := [][]string{
+		{"Execution ID", state.ExecutionID},
+		{"Status", string(state.Status)},
+		{"Project ID", state.ProjectID},
+		{"Plan ID", state.PlanID},
+		{"Started", state.StartedAt.Format(time.RFC3339)},
+		{"Updated", state.UpdatedAt.Format(time.RFC3339)},
+		{"Progress", fmt.Sprintf("%d/%d completed, %d failed", state.CompletedOps, state.TotalOps, state.FailedOps)},
+		{"Inputs", strings.Join(state.Inputs, ", ")},
+	}
+	if checkpoints, err := dag.NewCheckpointManager(dag.CheckpointConfig{}).ListCheckpoints(executionID); err == nil {
+		rows = append(rows, []string{"Checkpoints", fmt.Sprintf("%d", len(checkpoints))})
+	}
+	if state.LastCheckpoint != nil {
+		rows = append(rows, []string{"Last Checkpoint", fmt.Sprintf("%s (stage %d)", state.LastCheckpoint.CheckpointID, state.LastCheckpoint.Stage)})
+	}
+	if apply.IsResumable(state) {
+		rows = append(rows, []string{"Resume", "matlas infra apply --resume " + state.ExecutionID})
+	}
+	if rollbackLog, err := apply.NewRollbackStore("").Load(executionID); err == nil {
+		if rollbackLog.RolledBackBy != "" {
+			rows = append(rows, []string{"Rolled Back By", rollbackLog.RolledBackBy})
+		} else {
+			rows = append(rows, []string{"Rollback", "matlas infra rollback " + state.ExecutionID})
+		}
+	}
+	if err := formatter.Format(output.TableData{Headers: []string{"Field", "Value"}, Rows: rows}); err != nil {
+		return err
+	}
+
+	ops := make([]*dag.OperationState, 0, len(state.Operations))
+	for _, op := range state.Operations {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Stage != ops[j].Stage {
+			return ops[i].Stage < ops[j].Stage
+		}
+		return ops[i].OperationID < ops[j].OperationID
+	})
+
+	opRows := make([][]string, 0, len(ops))
+	for _, op := range ops {
+		duration := ""
+		if op.Duration > 0 {
+			duration = op.Duration.Truncate(time.Second).String()
+		}
+		opRows = append(opRows, []string{op.OperationID, op.Type, fmt.Sprintf("%d", op.Stage), string(op.Status), duration, op.Error})
+	}
+	fmt.Println()
+	return formatter.Format(output.TableData{
+		Headers: []string{"RESOURCE", "OPERATION", "STAGE", "STATUS", "DURATION", "ERROR"},
+		Rows:    opRows,
+	})
+}
+
+func runAbandonExecution(executionID string) error {
+	stateManager := dag.NewStateManager("")
+	state, err := stateManager.LoadState(executionID)
+	if err != nil {
+		return err
+	}
+	if state.Status == dag.ExecutionStatusCompleted {
+		return fmt.Errorf("execution %s already completed", executionID)
+	}
+
+	state.SetStatus(dag.ExecutionStatusAbandoned)
+	if err := stateManager.SaveState(state); err != nil {
+		return err
+	}
+	if err := dag.NewCheckpointManager(dag.CheckpointConfig{}).DeleteAllCheckpoints(executionID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Execution %s abandoned\n", executionID)
+	return nil
+}
+
+// loadExecutionState loads a recorded execution
+func loadExecutionState(executionID string) (*dag.ExecutionState, error) {
+	return dag.NewStateManager("").LoadState(executionID)
+}
+
+// listExecutionStates loads every recorded execution, newest first
+func listExecutionStates(stateManager *dag.StateManager) ([]*dag.ExecutionState, error) {
+	ids, err := stateManager.ListExecutions()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*dag.ExecutionState, 0, len(ids))
+	for _, id := range ids {
+		state, err := stateManager.LoadState(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping execution %s: %v\n", id, err)
+			continue
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].StartedAt.After(states[j].StartedAt) })
+
+	return states, nil
+}
+
+// prepareResume loads the execution to resume from its last checkpoint and fills in the
+// configuration files and project it was started with, unless they were given explicitly
+func prepareResume(opts *ApplyOptions) error {
+	state, err := loadExecutionState(opts.Resume)
+	if err != nil {
+		return err
+	}
+	if !apply.IsResumable(state) {
+		return fmt.Errorf("execution %s is %s and cannot be resumed", state.ExecutionID, state.Status)
+	}
+	state = restoreFromCheckpoint(dag.NewCheckpointManager(dag.CheckpointConfig{}), state)
+
+	if len(opts.Files) == 0 {
+		if len(state.Inputs) == 0 {
+			return fmt.Errorf("execution %s was not started from configuration files; pass them with --file", state.ExecutionID)
+		}
+		for _, input := range state.Inputs {
+			if input == "-" {
+				return fmt.Errorf("execution %s read its configuration from stdin; pass the files again with --file", state.ExecutionID)
+			}
+		}
+		opts.Files = state.Inputs
+	}
+	if opts.ProjectID == "" {
+		opts.ProjectID = state.ProjectID
+	}
+	opts.resumeState = state
+
+	return nil
+}
+
+// restoreFromCheckpoint returns the execution state saved by its latest checkpoint, the
+// last point at which every operation of a stage had finished. Operations completed after
+// it are confirmed against live state by PrepareResume. The recorded state is used when
+// the execution has no usable checkpoint.
+func restoreFromCheckpoint(checkpoints *dag.CheckpointManager, state *dag.ExecutionState) *dag.ExecutionState {
+	if state.LastCheckpoint == nil {
+		return state
+	}
+	restored, _, err := checkpoints.RestoreFromCheckpoint(state.LastCheckpoint.CheckpointID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: resuming execution %s from its recorded state: %v\n", state.ExecutionID, err)
+		return state
+	}
+	return restored
+}
+
+// displayResumeSummary reports how the regenerated plan lines up with the interrupted execution
+func displayResumeSummary(executionID string, summary *apply.ResumeSummary) error {
+	fmt.Printf("Resuming execution %s\n", executionID)
+	rows := [][]string{
+		{"Completed (verified, skipped)", fmt.Sprintf("%d", len(summary.Verified))},
+		{"Completed (drifted, re-applied)", fmt.Sprintf("%d", len(summary.Reapplied))},
+		{"Pending", fmt.Sprintf("%d", len(summary.Pending))},
+	}
+	if len(summary.Skipped) > 0 {
+		rows = append(rows, []string{"Not part of the execution (skipped)", fmt.Sprintf("%d", len(summary.Skipped))})
+	}
+	for _, key := range summary.Reapplied {
+		fmt.Fprintf(os.Stderr, "Warning: %s completed before the interruption but no longer matches live state; applying it again\n", key)
+	}
+	for _, key := range summary.Skipped {
+		fmt.Fprintf(os.Stderr, "Warning: %s needs a change that was not part of execution %s; run apply again once it has finished\n", key, executionID)
+	}
+	return output.NewFormatter(config.OutputTable, os.Stdout).Format(output.TableData{Headers: []string{"Operations", "Count"}, Rows: rows})
+}
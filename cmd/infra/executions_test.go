@@ -0,0 +1,111 @@
+package infra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+)
+
+func saveTestExecution(t *testing.T, id string, status dag.ExecutionStatus, inputs ...string) {
+	t.Helper()
+	state := dag.NewExecutionState(id, "plan-1", "5f1d7c0a1b2c3d4e5f6a7b8c", 2, 1)
+	state.Inputs = inputs
+	state.SetStatus(status)
+	require.NoError(t, dag.NewStateManager("").SaveState(state))
+}
+
+func TestNewExecutionsCmd(t *testing.T) {
+	cmd := NewExecutionsCmd()
+
+	assert.Equal(t, "executions", cmd.Use)
+	for _, name := range []string{"list", "show", "resume", "abandon"} {
+		subCmd, _, err := cmd.Find([]string{name})
+		require.NoError(t, err)
+		assert.Equal(t, name, subCmd.Name())
+	}
+
+	resume, _, _ := cmd.Find([]string{"resume"})
+	for _, flag := range []string{"file", "project-id", "auto-approve", "timeout", "wait"} {
+		assert.NotNil(t, resume.Flags().Lookup(flag), "resume should have --%s", flag)
+	}
+	assert.NotNil(t, NewInfraCmd().Flags().Lookup("resume"))
+}
+
+func TestPrepareResume(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saveTestExecution(t, "exec-running", dag.ExecutionStatusRunning, "/configs/project.yaml")
+	saveTestExecution(t, "exec-stdin", dag.ExecutionStatusFailed, "-")
+	saveTestExecution(t, "exec-done", dag.ExecutionStatusCompleted, "/configs/project.yaml")
+
+	opts := &ApplyOptions{Resume: "exec-running"}
+	require.NoError(t, prepareResume(opts))
+	assert.Equal(t, []string{"/configs/project.yaml"}, opts.Files)
+	assert.Equal(t, "5f1d7c0a1b2c3d4e5f6a7b8c", opts.ProjectID)
+	assert.NotNil(t, opts.resumeState)
+
+	// Explicit files and project take precedence over the recorded ones
+	opts = &ApplyOptions{Resume: "exec-running", Files: []string{"other.yaml"}, ProjectID: "other"}
+	require.NoError(t, prepareResume(opts))
+	assert.Equal(t, []string{"other.yaml"}, opts.Files)
+	assert.Equal(t, "other", opts.ProjectID)
+
+	err := prepareResume(&ApplyOptions{Resume: "exec-stdin"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pass the files again with --file")
+
+	err = prepareResume(&ApplyOptions{Resume: "exec-done"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be resumed")
+
+	assert.Error(t, prepareResume(&ApplyOptions{Resume: "exec-missing"}))
+}
+
+func TestListAndAbandonExecutions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saveTestExecution(t, "exec-running", dag.ExecutionStatusRunning, "/configs/project.yaml")
+	saveTestExecution(t, "exec-done", dag.ExecutionStatusCompleted, "/configs/project.yaml")
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runListExecutions(&ExecutionsOptions{OutputFormat: "table", ResumableOnly: true}))
+	})
+	assert.Contains(t, out, "exec-running")
+	assert.NotContains(t, out, "exec-done")
+
+	out = captureStdout(t, func() {
+		require.NoError(t, runAbandonExecution("exec-running"))
+	})
+	assert.Contains(t, out, "Execution exec-running abandoned")
+
+	state, err := dag.NewStateManager("").LoadState("exec-running")
+	require.NoError(t, err)
+	assert.Equal(t, dag.ExecutionStatusAbandoned, state.Status)
+
+	assert.Error(t, runAbandonExecution("exec-done"))
+}
+
+func TestRestoreFromCheckpoint(t *testing.T) {
+	checkpoints := dag.NewCheckpointManager(dag.CheckpointConfig{CheckpointDir: t.TempDir()})
+	state := dag.NewExecutionState("exec-1", "plan-1", "5f1d7c0a1b2c3d4e5f6a7b8c", 2, 2)
+	state.Inputs = []string{"/configs/project.yaml"}
+	state.Operations["Cluster/main"] = &dag.OperationState{OperationID: "Cluster/main", Stage: 0, Status: dag.OpStatusPending}
+	state.Operations["DatabaseUser/app"] = &dag.OperationState{OperationID: "DatabaseUser/app", Stage: 1, Status: dag.OpStatusPending}
+	state.UpdateOperationState("Cluster/main", dag.OpStatusCompleted, nil)
+	_, err := checkpoints.CreateCheckpoint("exec-1", "plan-1", state, nil, 0, "", "stage 0 finished")
+	require.NoError(t, err)
+
+	// Progress recorded after the checkpoint is not trusted on resume
+	state.UpdateOperationState("DatabaseUser/app", dag.OpStatusCompleted, nil)
+	state.SetStatus(dag.ExecutionStatusFailed)
+
+	restored := restoreFromCheckpoint(checkpoints, state)
+	assert.Equal(t, dag.OpStatusCompleted, restored.Operations["Cluster/main"].Status)
+	assert.Equal(t, dag.OpStatusPending, restored.Operations["DatabaseUser/app"].Status)
+	assert.Equal(t, []string{"/configs/project.yaml"}, restored.Inputs)
+
+	// Without a usable checkpoint the recorded state is resumed
+	state.LastCheckpoint = &dag.CheckpointInfo{CheckpointID: "missing"}
+	assert.Same(t, state, restoreFromCheckpoint(checkpoints, state))
+}
@@ -0,0 +1,91 @@
+package infra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+)
+
+// ConfigInputs holds the flags that shape how configuration files are rendered: template
+// variables and an environment overlay
+type ConfigInputs struct {
+	VarFiles []string
+	Vars     []string
+	Overlay  string
+}
+
+func addConfigInputFlags(cmd *cobra.Command, inputs *ConfigInputs) {
+	cmd.Flags().StringSliceVar(&inputs.VarFiles, "var-file", []string{}, "YAML or JSON files of template variables; later files win (repeatable)")
+	cmd.Flags().StringArrayVar(&inputs.Vars, "var", []string{}, "Template variable as NAME=VALUE, overriding variable files (repeatable)")
+	cmd.Flags().StringVar(&inputs.Overlay, "overlay", "", "Overlay directory whose overlay.yaml patches the configuration; its bases are used when no --file is given")
+}
+
+// requireFiles checks that configuration comes from --file or an overlay's bases
+func (in ConfigInputs) requireFiles(files []string) error {
+	if len(files) == 0 && in.Overlay == "" {
+		return fmt.Errorf("at least one configuration file must be specified with --file or --overlay")
+	}
+	return nil
+}
+
+// configFiles expands the --file patterns, falling back to the overlay's bases
+func (in ConfigInputs) configFiles(patterns []string) ([]string, error) {
+	if len(patterns) == 0 && in.Overlay != "" {
+		overlay, err := apply.LoadOverlay(in.Overlay)
+		if err != nil {
+			return nil, err
+		}
+		patterns = overlay.Bases()
+	}
+	return expandFilePatterns(patterns)
+}
+
+// loaderOptions sets the variables and overlay on loader options. Variables are layered
+// from lowest to highest precedence: the overlay's variable files, its inline variables,
+// --var-file, then --var; environment variables only fill in what none of them set.
+func (in ConfigInputs) loaderOptions(opts *apply.LoaderOptions) error {
+	vars := make(map[string]string)
+	if in.Overlay != "" {
+		overlay, err := apply.LoadOverlay(in.Overlay)
+		if err != nil {
+			return err
+		}
+		overlayVars, err := overlay.Variables()
+		if err != nil {
+			return fmt.Errorf("overlay %s: %w", in.Overlay, err)
+		}
+		for name, value := range overlayVars {
+			vars[name] = value
+		}
+		opts.Overlay = overlay
+	}
+
+	fileVars, err := apply.LoadVariableFiles(in.VarFiles)
+	if err != nil {
+		return err
+	}
+	flagVars, err := apply.ParseVariableAssignments(in.Vars)
+	if err != nil {
+		return err
+	}
+	for _, layer := range []map[string]string{fileVars, flagVars} {
+		for name, value := range layer {
+			vars[name] = value
+		}
+	}
+	if len(vars) > 0 {
+		opts.Variables = vars
+	}
+	return nil
+}
+
+// checkOverlayMatched reports overlay patches whose target was not found in any file
+func checkOverlayMatched(overlay *apply.Overlay) error {
+	if unmatched := overlay.Unmatched(); len(unmatched) > 0 {
+		return fmt.Errorf("overlay patches matched no resource: %s", strings.Join(unmatched, ", "))
+	}
+	return nil
+}
@@ -22,6 +22,9 @@ type OptimizeOptions struct {
 	ProjectID    string
 	Timeout      time.Duration
 	Strategy     string
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewOptimizeCmd creates the optimize subcommand
@@ -60,6 +63,7 @@ The command will analyze the dependency graph and provide actionable recommendat
 
 	// File input flags
 	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to analyze (supports glob patterns)")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
 
 	// Output flags
 	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "text", "Output format: text, markdown, json")
@@ -80,12 +84,12 @@ func runOptimize(cmd *cobra.Command, opts *OptimizeOptions) error {
 	defer cancel()
 
 	// Validate options
-	if len(opts.Files) == 0 {
-		return fmt.Errorf("no configuration files specified (use -f or provide files as arguments)")
+	if len(opts.Files) == 0 && opts.Overlay == "" {
+		return fmt.Errorf("no configuration files specified (use -f, --overlay or provide files as arguments)")
 	}
 
 	// Expand file patterns
-	files, err := expandFilePatterns(opts.Files)
+	files, err := opts.configFiles(opts.Files)
 	if err != nil {
 		return fmt.Errorf("failed to expand file patterns: %w", err)
 	}
@@ -103,8 +107,9 @@ func runOptimize(cmd *cobra.Command, opts *OptimizeOptions) error {
 
 	// Load configurations
 	configs, err := loadConfigurations(files, &ApplyOptions{
-		StrictEnv: opts.StrictEnv,
-		Verbose:   opts.Verbose,
+		ConfigInputs: opts.ConfigInputs,
+		StrictEnv:    opts.StrictEnv,
+		Verbose:      opts.Verbose,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to load configurations: %w", err)
@@ -0,0 +1,148 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/config"
+)
+
+// OutputOptions contains the options for the output command
+type OutputOptions struct {
+	Files       []string
+	ProjectID   string
+	StrictEnv   bool
+	Raw         bool
+	ShowSecrets bool
+	Timeout     time.Duration
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
+}
+
+// NewOutputCmd creates the output subcommand
+func NewOutputCmd() *cobra.Command {
+	opts := &OutputOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "output [name]",
+		Short: "Print the outputs declared in configuration files",
+		Long: `Print the outputs declared by ApplyDocuments as JSON.
+
+Outputs are resolved against the applied resources: ${ref:Kind/name.status...}
+expressions read the live state of the resource from Atlas, while metadata and spec
+paths read the configuration. Given a name, only that output's value is printed.
+Outputs holding credentials are masked unless --show-secrets is set.`,
+		Example: `  # Print all outputs as a JSON object
+  matlas infra output -f config.yaml
+
+  # Print a single output without JSON quoting
+  matlas infra output -f config.yaml connectionString --raw
+
+  # Print an output referencing a password without masking it
+  matlas infra output -f config.yaml appUri --raw --show-secrets`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			}
+			return runOutput(cmd, opts, name)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files declaring outputs (supports glob patterns)")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
+	cmd.Flags().StringVar(&opts.ProjectID, "project-id", "", "Atlas project ID (overrides config)")
+	cmd.Flags().BoolVar(&opts.StrictEnv, "strict-env", false, "Fail on undefined environment variables")
+	cmd.Flags().BoolVar(&opts.Raw, "raw", false, "Print a single string output without JSON quoting")
+	cmd.Flags().BoolVar(&opts.ShowSecrets, "show-secrets", false, "Do not mask outputs holding credentials")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 2*time.Minute, "Timeout for resolving outputs")
+
+	return cmd
+}
+
+func runOutput(cmd *cobra.Command, opts *OutputOptions, name string) error {
+	ctx, cancel := context.WithTimeout(cmd.Context(), opts.Timeout)
+	defer cancel()
+
+	if err := opts.requireFiles(opts.Files); err != nil {
+		return err
+	}
+	if opts.Raw && name == "" {
+		return fmt.Errorf("--raw requires an output name")
+	}
+
+	files, err := opts.configFiles(opts.Files)
+	if err != nil {
+		return fmt.Errorf("failed to expand file patterns: %w", err)
+	}
+	configs, err := loadConfigurations(files, &ApplyOptions{
+		ConfigInputs: opts.ConfigInputs,
+		StrictEnv:    opts.StrictEnv,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load configurations: %w", err)
+	}
+	desiredState, err := buildDesiredState(configs)
+	if err != nil {
+		return fmt.Errorf("failed to build desired state: %w", err)
+	}
+
+	outputs := desiredState.Outputs
+	if name != "" {
+		expression, ok := outputs[name]
+		if !ok {
+			return fmt.Errorf("output %s is not declared", name)
+		}
+		outputs = map[string]string{name: expression}
+	}
+
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	services, err := initializeServices(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize services: %w", err)
+	}
+	projectNameOrID := getProjectID(configs, &ApplyOptions{ProjectID: opts.ProjectID})
+	projectID, err := resolveProjectID(ctx, projectNameOrID, services.ProjectsService, getOrganizationID(configs))
+	if err != nil {
+		return fmt.Errorf("failed to resolve project ID for '%s': %w", projectNameOrID, err)
+	}
+
+	resolver := apply.NewReferenceResolver(newStatusFetcher(services, projectID))
+	resolver.DeclareState(desiredState)
+	values, err := resolver.ResolveOutputs(ctx, outputs)
+	if err != nil {
+		return err
+	}
+	if !opts.ShowSecrets {
+		values = apply.RedactOutputs(outputs, values)
+	}
+
+	if name != "" {
+		return writeOutputValue(os.Stdout, values[name], opts.Raw)
+	}
+	return writeOutputValue(os.Stdout, values, false)
+}
+
+// writeOutputValue prints a value as indented JSON, or a string as is when raw
+func writeOutputValue(w io.Writer, value interface{}, raw bool) error {
+	if s, ok := value.(string); ok && raw {
+		_, err := fmt.Fprintln(w, s)
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -14,8 +14,10 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/apply/policy"
 	"github.com/teabranch/matlas-cli/internal/config"
 	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/types"
 )
 
 // PlanOptions contains the options for the plan command
@@ -30,6 +32,10 @@ type PlanOptions struct {
 	Timeout          time.Duration
 	PlanMode         string
 	PreserveExisting bool
+	Policies         []string
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewPlanCmd creates the plan subcommand
@@ -53,7 +59,10 @@ their dependencies, estimated durations, and risk levels. Plans can be saved for
   matlas infra plan -f config.yaml --plan-mode detailed --verbose
 
   # Generate plan for specific project
-  matlas infra plan -f config.yaml --project-id 507f1f77bcf86cd799439011`,
+  matlas infra plan -f config.yaml --project-id 507f1f77bcf86cd799439011
+
+  # Check the plan against policies and write SARIF for code scanning
+  matlas infra plan -f config.yaml --policy ./policies/ -o sarif > policy.sarif`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Support positional arguments as files if no --file flag provided
 			if len(opts.Files) == 0 && len(args) > 0 {
@@ -65,11 +74,12 @@ their dependencies, estimated durations, and risk levels. Plans can be saved for
 
 	// File input flags
 	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to plan (supports glob patterns)")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
 
 	// Output flags
-	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, summary")
+	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, summary, sarif (policy results only)")
 	// Alias --format to --output for ergonomics (binds to same variable)
-	cmd.Flags().StringVar(&opts.OutputFormat, "format", "table", "Output format (alias for --output): table, json, yaml, summary")
+	cmd.Flags().StringVar(&opts.OutputFormat, "format", "table", "Output format (alias for --output): table, json, yaml, summary, sarif")
 	cmd.Flags().StringVar(&opts.OutputFile, "output-file", "", "Save plan to file (format determined by extension)")
 	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Enable verbose output")
 	cmd.Flags().BoolVar(&opts.NoColor, "no-color", false, "Disable colored output")
@@ -80,6 +90,7 @@ their dependencies, estimated durations, and risk levels. Plans can be saved for
 	cmd.Flags().StringVar(&opts.ProjectID, "project-id", "", "Atlas project ID (overrides config)")
 	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 10*time.Minute, "Timeout for plan generation")
 	cmd.Flags().BoolVar(&opts.PreserveExisting, "preserve-existing", false, "Only plan additions and updates, exclude deletions")
+	cmd.Flags().StringSliceVar(&opts.Policies, "policy", []string{}, "Policy files or directories to evaluate against the plan (repeatable)")
 
 	return cmd
 }
@@ -94,11 +105,17 @@ func runPlan(cmd *cobra.Command, opts *PlanOptions) error {
 	}
 
 	// Expand file patterns
-	files, err := expandFilePatterns(opts.Files)
+	files, err := opts.configFiles(opts.Files)
 	if err != nil {
 		return fmt.Errorf("failed to expand file patterns: %w", err)
 	}
 
+	// Compile policies before doing any work so mistakes in them surface first
+	policyEngine, err := loadPolicyEngine(opts.Policies)
+	if err != nil {
+		return err
+	}
+
 	// Initialize services
 	cfg, err := config.Load(cmd, "")
 	if err != nil {
@@ -112,8 +129,9 @@ func runPlan(cmd *cobra.Command, opts *PlanOptions) error {
 
 	// Load configurations
 	configs, err := loadConfigurations(files, &ApplyOptions{
-		StrictEnv: opts.StrictEnv,
-		Verbose:   opts.Verbose,
+		ConfigInputs: opts.ConfigInputs,
+		StrictEnv:    opts.StrictEnv,
+		Verbose:      opts.Verbose,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to load configurations: %w", err)
@@ -135,8 +153,41 @@ func runPlan(cmd *cobra.Command, opts *PlanOptions) error {
 		_ = formatter.Format(output.TableData{Headers: []string{"Info"}, Rows: [][]string{{"Plan saved to " + opts.OutputFile}}})
 	}
 
+	report, err := evaluatePolicies(policyEngine, plan)
+	if err != nil {
+		return err
+	}
+
 	// Display plan
-	return displayPlan(plan, opts)
+	if err := displayPlanWithPolicies(plan, report, files, opts); err != nil {
+		return err
+	}
+	if report != nil && report.Denied() {
+		return policyDenyError(report)
+	}
+	return nil
+}
+
+// displayPlanWithPolicies renders the plan followed by policy results. SARIF output
+// contains only the policy results.
+func displayPlanWithPolicies(plan *apply.Plan, report *policy.Report, files []string, opts *PlanOptions) error {
+	if report == nil {
+		return displayPlan(plan, opts)
+	}
+
+	switch strings.ToLower(opts.OutputFormat) {
+	case "sarif":
+		return displaySARIF(report, files)
+	case "json":
+		return output.NewFormatter(config.OutputJSON, os.Stdout).Format(planPolicyOutput{Plan: *apply.RedactPlan(plan), Policy: report})
+	case "yaml":
+		return output.NewFormatter(config.OutputYAML, os.Stdout).Format(planPolicyOutput{Plan: *apply.RedactPlan(plan), Policy: report})
+	default:
+		if err := displayPlan(plan, opts); err != nil {
+			return err
+		}
+		return displayPolicyReport(report, opts.OutputFormat)
+	}
 }
 
 func generateExecutionPlan(ctx context.Context, configs []*apply.LoadResult, services *ServiceClients, cfg *config.Config, opts *PlanOptions) (*apply.Plan, error) {
@@ -156,15 +207,25 @@ func generateExecutionPlan(ctx context.Context, configs []*apply.LoadResult, ser
 	diffEngine := apply.NewDiffEngine()
 	diffEngine.PreserveExisting = opts.PreserveExisting
 
-	planOptimizer := apply.NewPlanOptimizer()
+	durationHistory := loadDurationHistory()
+	planOptimizer := apply.NewPlanOptimizer().WithDurationHistory(durationHistory)
+
+	// Build desired state
+	desiredState, err := buildDesiredState(configs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build desired state: %w", err)
+	}
 
 	// Get project name or ID and resolve to project ID
 	projectNameOrID := getProjectID(configs, &ApplyOptions{ProjectID: opts.ProjectID})
 	orgID := getOrganizationID(configs)
 
-	resolvedProjectID, err := resolveProjectID(ctx, projectNameOrID, services.ProjectsService, orgID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve project ID for '%s': %w", projectNameOrID, err)
+	resolvedProjectID := projectNameOrID
+	if projectNameOrID != "" || !isOrganizationScoped(desiredState) {
+		resolvedProjectID, err = resolveProjectID(ctx, projectNameOrID, services.ProjectsService, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve project ID for '%s': %w", projectNameOrID, err)
+		}
 	}
 
 	// Discover current state
@@ -172,15 +233,14 @@ func generateExecutionPlan(ctx context.Context, configs []*apply.LoadResult, ser
 		fmt.Printf("Discovering current state for project %s (resolved from '%s')...\n", resolvedProjectID, projectNameOrID)
 	}
 
-	currentState, err := discoveryService.DiscoverProject(ctx, resolvedProjectID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover current state: %w", err)
+	if databaseClient, cleanup := newInfraDatabaseClient(services, resolvedProjectID, desiredState); databaseClient != nil {
+		defer cleanup()
+		discoveryService.SetDatabaseClient(databaseClient)
 	}
 
-	// Build desired state
-	desiredState, err := buildDesiredState(configs)
+	currentState, err := discoverTargetState(ctx, discoveryService, resolvedProjectID, desiredState)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build desired state: %w", err)
+		return nil, fmt.Errorf("failed to discover current state: %w", err)
 	}
 
 	// Compute diff
@@ -193,8 +253,13 @@ func generateExecutionPlan(ctx context.Context, configs []*apply.LoadResult, ser
 		return nil, fmt.Errorf("failed to compute diff: %w", err)
 	}
 
+	dataSources, err := resolveDataSources(ctx, services, resolvedProjectID, desiredState)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create execution plan
-	planBuilder := apply.NewPlanBuilder(resolvedProjectID)
+	planBuilder := apply.NewPlanBuilder(resolvedProjectID).WithDurationHistory(durationHistory).WithHooks(desiredState.Hooks).WithDataSources(dataSources)
 	planBuilder.AddOperations(diff.Operations)
 
 	plan, err := planBuilder.Build()
@@ -256,12 +321,12 @@ func displayPlan(plan *apply.Plan, opts *PlanOptions) error {
 
 func displayPlanJSON(plan *apply.Plan) error {
 	formatter := output.NewFormatter(config.OutputJSON, os.Stdout)
-	return formatter.Format(plan)
+	return formatter.Format(apply.RedactPlan(plan))
 }
 
 func displayPlanYAML(plan *apply.Plan) error {
 	formatter := output.NewFormatter(config.OutputYAML, os.Stdout)
-	return formatter.Format(plan)
+	return formatter.Format(apply.RedactPlan(plan))
 }
 
 func displayPlanSummary(plan *apply.Plan, opts *PlanOptions) error {
@@ -273,6 +338,7 @@ func displayPlanSummary(plan *apply.Plan, opts *PlanOptions) error {
 		{"Total Operations", fmt.Sprintf("%d", plan.Summary.TotalOperations)},
 		{"Stages", fmt.Sprintf("%d", len(plan.Summary.OperationsByStage))},
 		{"Estimated Duration", plan.Summary.EstimatedDuration.String()},
+		{"ETA", formatPlanETA(plan.Summary)},
 		{"Highest Risk Level", string(plan.Summary.HighestRiskLevel)},
 		{"Parallelization Factor", fmt.Sprintf("%.2f", plan.Summary.ParallelizationFactor)},
 		{"Destructive Operations", fmt.Sprintf("%d", plan.Summary.DestructiveOperations)},
@@ -307,6 +373,15 @@ func displayPlanTable(plan *apply.Plan, opts *PlanOptions) error {
 	// Sort stages for stable output
 	sort.Ints(stages)
 
+	// Plans that use modules show which instance declared each resource
+	showModules := false
+	for _, op := range plan.Operations {
+		if op.Module != "" {
+			showModules = true
+			break
+		}
+	}
+
 	// Render each stage as a table via formatter
 	for _, stage := range stages {
 		ops := stageToOperations[stage]
@@ -330,18 +405,23 @@ func displayPlanTable(plan *apply.Plan, opts *PlanOptions) error {
 				}
 			}
 
-			rows = append(rows, []string{
+			row := []string{
 				string(op.ResourceType),
 				string(op.Type),
 				op.ResourceName,
-				riskLevel,
-				duration,
-				dependencies,
-			})
+			}
+			if showModules {
+				row = append(row, op.Module)
+			}
+			rows = append(rows, append(row, riskLevel, duration, dependencies))
 		}
 
+		headers := []string{"Resource Type", "Operation", "Resource Name", "Risk", "Duration", "Dependencies"}
+		if showModules {
+			headers = []string{"Resource Type", "Operation", "Resource Name", "Module", "Risk", "Duration", "Dependencies"}
+		}
 		table := output.TableData{
-			Headers: []string{"Resource Type", "Operation", "Resource Name", "Risk", "Duration", "Dependencies"},
+			Headers: headers,
 			Rows:    rows,
 		}
 		if err := output.NewFormatter(config.OutputTable, os.Stdout).Format(table); err != nil {
@@ -349,6 +429,22 @@ func displayPlanTable(plan *apply.Plan, opts *PlanOptions) error {
 		}
 	}
 
+	if err := displayRoleMappingChanges(plan); err != nil {
+		return err
+	}
+
+	if err := displaySensitiveValues(plan); err != nil {
+		return err
+	}
+
+	if err := displayExpiringNetworkAccess(plan, time.Now()); err != nil {
+		return err
+	}
+
+	if err := displayDataSources(plan); err != nil {
+		return err
+	}
+
 	// Optional verbose configuration block
 	if opts.Verbose {
 		cfgRows := [][]string{{"Require Approval", fmt.Sprintf("%t", plan.Config.RequireApproval)}}
@@ -372,6 +468,7 @@ func displayPlanTable(plan *apply.Plan, opts *PlanOptions) error {
 		sumRows = append(sumRows, []string{string(opType) + " operations", fmt.Sprintf("%d", count)})
 	}
 	sumRows = append(sumRows, []string{"Estimated Duration", plan.Summary.EstimatedDuration.String()})
+	sumRows = append(sumRows, []string{"ETA", formatPlanETA(plan.Summary)})
 	sumRows = append(sumRows, []string{"Highest Risk Level", string(plan.Summary.HighestRiskLevel)})
 	if plan.Summary.DestructiveOperations > 0 {
 		sumRows = append(sumRows, []string{"Destructive Operations", fmt.Sprintf("%d", plan.Summary.DestructiveOperations)})
@@ -386,16 +483,124 @@ func displayPlanTable(plan *apply.Plan, opts *PlanOptions) error {
 	return nil
 }
 
+// displayRoleMappingChanges lists the role assignments each RoleMapping operation grants (+) and revokes (-)
+func displayRoleMappingChanges(plan *apply.Plan) error {
+	var rows [][]string
+	for _, op := range plan.Operations {
+		if op.ResourceType != types.KindRoleMapping {
+			continue
+		}
+		var desired, current *types.RoleMappingSpec
+		if m, ok := op.Desired.(*types.RoleMappingManifest); ok && m != nil {
+			desired = &m.Spec
+		}
+		if m, ok := op.Current.(*types.RoleMappingManifest); ok && m != nil {
+			current = &m.Spec
+		}
+		added, removed := apply.RoleMappingAssignmentChanges(desired, current)
+		for _, assignment := range added {
+			rows = append(rows, []string{op.ResourceName, "+", assignment})
+		}
+		for _, assignment := range removed {
+			rows = append(rows, []string{op.ResourceName, "-", assignment})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(os.Stdout, "\nRole mapping changes\n\n"); err != nil {
+		return err
+	}
+	return output.NewFormatter(config.OutputTable, os.Stdout).Format(output.TableData{
+		Headers: []string{"Role Mapping", "Change", "Role Assignment"},
+		Rows:    rows,
+	})
+}
+
+// displaySensitiveValues lists the credentials each create or update operation will send, masked
+func displaySensitiveValues(plan *apply.Plan) error {
+	var rows [][]string
+	for _, op := range plan.Operations {
+		if op.Type != apply.OperationCreate && op.Type != apply.OperationUpdate {
+			continue
+		}
+		for _, value := range apply.SensitiveValues(op.Desired) {
+			rows = append(rows, []string{string(op.ResourceType), op.ResourceName, value.Path, fmt.Sprintf("%v", value.Value)})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(os.Stdout, "\nSensitive values (masked)\n\n"); err != nil {
+		return err
+	}
+	return output.NewFormatter(config.OutputTable, os.Stdout).Format(output.TableData{
+		Headers: []string{"Resource Type", "Resource Name", "Field", "Value"},
+		Rows:    rows,
+	})
+}
+
+// displayExpiringNetworkAccess lists temporary network access entries that expire within a day
+func displayExpiringNetworkAccess(plan *apply.Plan, now time.Time) error {
+	entries := apply.ExpiringNetworkAccessEntries(plan, now, apply.NetworkAccessExpiryWindow)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		expiresIn := entry.ExpiresIn.String()
+		if entry.ExpiresIn <= 0 {
+			expiresIn = "expired"
+		}
+		rows = append(rows, []string{entry.ResourceName, entry.Address, entry.ExpiresAt.UTC().Format(time.RFC3339), expiresIn})
+	}
+
+	if _, err := fmt.Fprintf(os.Stdout, "\nNetwork access expiring soon\n\n"); err != nil {
+		return err
+	}
+	return output.NewFormatter(config.OutputTable, os.Stdout).Format(output.TableData{
+		Headers: []string{"Resource Name", "Address", "Expires At", "Expires In"},
+		Rows:    rows,
+	})
+}
+
+// displayDataSources lists the existing objects the plan reads but never changes
+func displayDataSources(plan *apply.Plan) error {
+	if len(plan.DataSources) == 0 {
+		return nil
+	}
+
+	rows := make([][]string, 0, len(plan.DataSources))
+	for _, source := range plan.DataSources {
+		spec := source.Manifest.Spec
+		rows = append(rows, []string{source.Manifest.Metadata.Name, string(spec.Type), spec.Name})
+	}
+
+	if _, err := fmt.Fprintf(os.Stdout, "\nData sources (read only)\n\n"); err != nil {
+		return err
+	}
+	return output.NewFormatter(config.OutputTable, os.Stdout).Format(output.TableData{
+		Headers: []string{"Data Source", "Type", "Name"},
+		Rows:    rows,
+	})
+}
+
 func validatePlanOptions(opts *PlanOptions) error {
-	if len(opts.Files) == 0 {
-		return fmt.Errorf("at least one configuration file must be specified with --file")
+	if err := opts.requireFiles(opts.Files); err != nil {
+		return err
 	}
 
 	// Validate output format
-	validOutputFormats := []string{"table", "json", "yaml", "summary"}
+	validOutputFormats := []string{"table", "json", "yaml", "summary", "sarif"}
 	if !contains(validOutputFormats, strings.ToLower(opts.OutputFormat)) {
 		return fmt.Errorf("invalid output format: %s (valid options: %s)", opts.OutputFormat, strings.Join(validOutputFormats, ", "))
 	}
+	if strings.EqualFold(opts.OutputFormat, "sarif") && len(opts.Policies) == 0 {
+		return fmt.Errorf("sarif output requires --policy")
+	}
 
 	// Validate plan mode
 	validPlanModes := []string{"quick", "standard", "detailed"}
@@ -418,3 +623,33 @@ func validatePlanOptions(opts *PlanOptions) error {
 
 	return nil
 }
+
+// formatPlanETA renders the plan's ETA range and how much of it comes from recorded history
+func formatPlanETA(summary apply.PlanSummary) string {
+	if summary.HistoricalEstimates == 0 {
+		return fmt.Sprintf("%s (default estimates, no recorded history)", summary.EstimatedDurationLow)
+	}
+	eta := summary.EstimatedDurationLow.String()
+	if summary.EstimatedDurationHigh > summary.EstimatedDurationLow {
+		eta += " - " + summary.EstimatedDurationHigh.String()
+	}
+	return fmt.Sprintf("%s (p50-p90, %d/%d operations from history)", eta, summary.HistoricalEstimates, summary.TotalOperations)
+}
+
+// loadDurationHistory loads the operation durations recorded by previous applies.
+// Estimates fall back to defaults when the history cannot be read.
+func loadDurationHistory() *apply.DurationHistory {
+	history, err := apply.LoadDurationHistory(apply.DefaultDurationHistoryPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring duration history: %v\n", err)
+		return nil
+	}
+	return history
+}
+
+// saveDurationHistory persists durations recorded during execution
+func saveDurationHistory(history *apply.DurationHistory) {
+	if err := history.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save duration history: %v\n", err)
+	}
+}
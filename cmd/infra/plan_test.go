@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/apply/policy"
 	"github.com/teabranch/matlas-cli/internal/types"
 )
 
@@ -83,6 +84,136 @@ func TestDisplayPlanTable_UsesFormatterAndPrintsStages(t *testing.T) {
 	assert.Contains(t, output, "Update operations   1")
 }
 
+func TestDisplayPlanTable_ShowsModules(t *testing.T) {
+	plan := &apply.Plan{
+		ID: "plan-123",
+		Operations: []apply.PlannedOperation{
+			{Operation: apply.Operation{Type: apply.OperationCreate, ResourceType: types.KindCluster, ResourceName: "payments-cluster"}, ID: "op-0", Module: "payments"},
+			{Operation: apply.Operation{Type: apply.OperationCreate, ResourceType: types.KindDatabaseUser, ResourceName: "reporting"}, ID: "op-1"},
+		},
+		Summary: apply.PlanSummary{OperationsByType: map[apply.OperationType]int{apply.OperationCreate: 2}},
+	}
+
+	output := captureStdout(t, func() {
+		_ = displayPlanTable(plan, &PlanOptions{NoColor: true})
+	})
+
+	assert.Contains(t, output, "Resource Type  Operation  Resource Name     Module    Risk")
+	assert.Contains(t, output, "Cluster        Create     payments-cluster  payments  N/A")
+	assert.Contains(t, output, "DatabaseUser   Create     reporting                   N/A")
+}
+
+func TestDisplayPlanTable_ShowsRoleMappingChanges(t *testing.T) {
+	const orgID = "5f1d7f4b3a1e2c0012345678"
+	desired := &types.RoleMappingManifest{Spec: types.RoleMappingSpec{
+		OrgID:             orgID,
+		ExternalGroupName: "okta-dbas",
+		RoleAssignments:   []types.RoleAssignmentConfig{{Role: "ORG_OWNER"}},
+	}}
+	current := &types.RoleMappingManifest{Spec: types.RoleMappingSpec{
+		OrgID:             orgID,
+		ExternalGroupName: "okta-dbas",
+		RoleAssignments:   []types.RoleAssignmentConfig{{Role: "ORG_MEMBER", OrgID: orgID}},
+	}}
+	plan := &apply.Plan{
+		ID:        "plan-456",
+		CreatedAt: time.Now(),
+		Operations: []apply.PlannedOperation{
+			{
+				Operation: apply.Operation{
+					Type:         apply.OperationUpdate,
+					ResourceType: types.KindRoleMapping,
+					ResourceName: "dbas",
+					Desired:      desired,
+					Current:      current,
+				},
+				ID: "op-0",
+			},
+		},
+		Summary: apply.PlanSummary{OperationsByType: map[apply.OperationType]int{apply.OperationUpdate: 1}},
+	}
+
+	output := captureStdout(t, func() {
+		_ = displayPlanTable(plan, &PlanOptions{NoColor: true})
+	})
+
+	assert.Contains(t, output, "Role mapping changes")
+	assert.Contains(t, output, "dbas          +       ORG_OWNER on org "+orgID)
+	assert.Contains(t, output, "dbas          -       ORG_MEMBER on org "+orgID)
+}
+
+func TestDisplayPlanTable_MasksSensitiveValues(t *testing.T) {
+	const apiKey = "0123456789abcdef0123456789abcdef"
+	plan := &apply.Plan{
+		ID:        "plan-789",
+		CreatedAt: time.Now(),
+		Operations: []apply.PlannedOperation{
+			{
+				Operation: apply.Operation{
+					Type:         apply.OperationCreate,
+					ResourceType: types.KindIntegration,
+					ResourceName: "datadog",
+					Desired: &types.IntegrationManifest{Spec: types.IntegrationSpec{
+						Type:   "DATADOG",
+						APIKey: apiKey,
+					}},
+				},
+				ID: "op-0",
+			},
+		},
+		Summary: apply.PlanSummary{OperationsByType: map[apply.OperationType]int{apply.OperationCreate: 1}},
+	}
+
+	output := captureStdout(t, func() {
+		_ = displayPlanTable(plan, &PlanOptions{NoColor: true})
+	})
+	assert.Contains(t, output, "Sensitive values (masked)")
+	assert.Contains(t, output, "Spec.APIKey")
+	assert.NotContains(t, output, apiKey)
+
+	output = captureStdout(t, func() {
+		_ = displayPlanJSON(plan)
+	})
+	assert.NotContains(t, output, apiKey)
+}
+
+func TestDisplayPlanTable_ShowsExpiringNetworkAccess(t *testing.T) {
+	expiry := time.Now().Add(3 * time.Hour).UTC().Truncate(time.Second)
+	plan := &apply.Plan{
+		ID:        "plan-790",
+		CreatedAt: time.Now(),
+		Operations: []apply.PlannedOperation{
+			{
+				Operation: apply.Operation{
+					Type:         apply.OperationCreate,
+					ResourceType: types.KindNetworkAccess,
+					ResourceName: "contractor",
+					Desired: &types.NetworkAccessManifest{Spec: types.NetworkAccessSpec{
+						IPAddress:       "203.0.113.10",
+						DeleteAfterDate: expiry.Format(time.RFC3339),
+					}},
+				},
+				ID: "op-0",
+			},
+		},
+		Summary: apply.PlanSummary{OperationsByType: map[apply.OperationType]int{apply.OperationCreate: 1}},
+	}
+
+	output := captureStdout(t, func() {
+		_ = displayPlanTable(plan, &PlanOptions{NoColor: true})
+	})
+	assert.Contains(t, output, "Network access expiring soon")
+	assert.Contains(t, output, "203.0.113.10")
+	assert.Contains(t, output, expiry.Format(time.RFC3339))
+}
+
+func TestFormatPlanETA(t *testing.T) {
+	assert.Equal(t, "1m30s (default estimates, no recorded history)",
+		formatPlanETA(apply.PlanSummary{TotalOperations: 2, EstimatedDurationLow: 90 * time.Second, EstimatedDurationHigh: 90 * time.Second}))
+	assert.Equal(t, "12m0s - 31m0s (p50-p90, 1/3 operations from history)",
+		formatPlanETA(apply.PlanSummary{TotalOperations: 3, EstimatedDurationLow: 12 * time.Minute, EstimatedDurationHigh: 31 * time.Minute, HistoricalEstimates: 1}))
+}
+
 func TestValidatePlanOptions(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -90,6 +221,7 @@ func TestValidatePlanOptions(t *testing.T) {
 		output     string
 		planMode   string
 		outputFile string
+		policies   []string
 		timeoutMs  int
 		expectErr  bool
 	}{
@@ -102,11 +234,13 @@ func TestValidatePlanOptions(t *testing.T) {
 		{name: "valid json", files: []string{"config.yaml"}, output: "json", planMode: "quick", outputFile: "plan.json", expectErr: false},
 		{name: "valid yaml", files: []string{"config.yaml"}, output: "yaml", planMode: "detailed", outputFile: "plan.yaml", expectErr: false},
 		{name: "valid summary", files: []string{"config.yaml"}, output: "summary", planMode: "standard", expectErr: false},
+		{name: "sarif without policies", files: []string{"config.yaml"}, output: "sarif", planMode: "standard", expectErr: true},
+		{name: "valid sarif", files: []string{"config.yaml"}, output: "sarif", planMode: "standard", policies: []string{"policies/"}, expectErr: false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			opts := &PlanOptions{Files: tt.files, OutputFormat: tt.output, PlanMode: tt.planMode, OutputFile: tt.outputFile}
+			opts := &PlanOptions{Files: tt.files, OutputFormat: tt.output, PlanMode: tt.planMode, OutputFile: tt.outputFile, Policies: tt.policies}
 			if tt.timeoutMs != 0 {
 				opts.Timeout = time.Duration(tt.timeoutMs) * time.Millisecond
 			} else {
@@ -121,3 +255,46 @@ func TestValidatePlanOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceLocations(t *testing.T) {
+	dir := t.TempDir()
+	document := dir + "/resources.yaml"
+	project := dir + "/project.yaml"
+	assert.NoError(t, os.WriteFile(document, []byte(`apiVersion: matlas.mongodb.com/v1
+kind: ApplyDocument
+metadata:
+  name: doc
+resources:
+  - kind: Cluster
+    metadata:
+      name: main
+    spec:
+      instanceSize: M10
+  - kind: NetworkAccess
+    metadata:
+      name: office
+---
+kind: DatabaseUser
+metadata:
+  name: app
+`), 0600))
+	assert.NoError(t, os.WriteFile(project, []byte(`apiVersion: matlas.mongodb.com/v1
+kind: Project
+metadata:
+  name: proj
+spec:
+  clusters:
+    - metadata:
+        name: analytics
+`), 0600))
+
+	locations := resourceLocations([]string{document, project, dir + "/missing.yaml", "-"})
+
+	assert.Equal(t, map[string]policy.Location{
+		"Cluster/main":         {File: document, Line: 6},
+		"NetworkAccess/office": {File: document, Line: 11},
+		"DatabaseUser/app":     {File: document, Line: 15},
+		"Project/proj":         {File: project, Line: 1},
+		"Cluster/analytics":    {File: project, Line: 7},
+	}, locations)
+}
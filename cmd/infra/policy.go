@@ -0,0 +1,175 @@
+package infra
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/apply/policy"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// planPolicyOutput is the JSON/YAML plan output when policies were evaluated
+type planPolicyOutput struct {
+	apply.Plan `yaml:",inline"`
+	Policy     *policy.Report `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+// loadPolicyEngine loads and compiles the policies in the given files and directories.
+// It returns nil when no policy paths are given.
+func loadPolicyEngine(paths []string) (*policy.Engine, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	policies, err := policy.Load(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies: %w", err)
+	}
+	engine, err := policy.NewEngine(policies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile policies: %w", err)
+	}
+	return engine, nil
+}
+
+// evaluatePolicies runs the policy engine against a plan, returning nil without an engine
+func evaluatePolicies(engine *policy.Engine, plan *apply.Plan) (*policy.Report, error) {
+	if engine == nil {
+		return nil, nil
+	}
+	report, err := engine.Evaluate(plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate policies: %w", err)
+	}
+	return report, nil
+}
+
+// policyDenyError is returned when a plan violates deny rules
+func policyDenyError(report *policy.Report) error {
+	return fmt.Errorf("plan violates %d deny policy rule(s)", report.Count(policy.LevelDeny))
+}
+
+// displayPolicyReport renders policy findings as a table below the plan. With JSON or
+// YAML output the findings go to stderr instead so stdout stays parseable.
+func displayPolicyReport(report *policy.Report, outputFormat string) error {
+	if report == nil {
+		return nil
+	}
+	switch strings.ToLower(outputFormat) {
+	case "json", "yaml":
+		for _, finding := range report.Findings {
+			fmt.Fprintf(os.Stderr, "Policy %s: %s %s (%s): %s\n", finding.Level, finding.RuleID, finding.Resource(), finding.OperationType, finding.Message)
+		}
+		return nil
+	}
+	fmt.Printf("\nPolicy results (%d rules, %d operations evaluated): %d deny, %d warn, %d advisory\n",
+		len(report.Rules), report.OperationsEvaluated,
+		report.Count(policy.LevelDeny), report.Count(policy.LevelWarn), report.Count(policy.LevelAdvisory))
+	if len(report.Findings) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	rows := make([][]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		rows = append(rows, []string{
+			strings.ToUpper(string(finding.Level)),
+			finding.RuleID,
+			finding.Resource(),
+			string(finding.OperationType),
+			finding.Message,
+		})
+	}
+	return output.NewFormatter(config.OutputTable, os.Stdout).Format(output.TableData{
+		Headers: []string{"LEVEL", "RULE", "RESOURCE", "OPERATION", "MESSAGE"},
+		Rows:    rows,
+	})
+}
+
+// displaySARIF prints a policy report as SARIF 2.1.0
+func displaySARIF(report *policy.Report, files []string) error {
+	return output.NewFormatter(config.OutputJSON, os.Stdout).Format(policy.ToSARIF(report, resourceLocations(files)))
+}
+
+// resourceLocations maps the Kind/Name key of each resource declared in the given
+// configuration files to the file and line of its declaration. Files that cannot be
+// read or parsed are skipped; findings for their resources have no file location.
+func resourceLocations(files []string) map[string]policy.Location {
+	locations := make(map[string]policy.Location)
+	for _, file := range files {
+		if file == "-" {
+			continue
+		}
+		data, err := os.ReadFile(file) // #nosec G304 -- configuration paths are supplied by the user
+		if err != nil {
+			continue
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			var doc yaml.Node
+			if err := decoder.Decode(&doc); err != nil {
+				break
+			}
+			collectResourceLocations(&doc, "", file, locations)
+		}
+	}
+	return locations
+}
+
+// projectSectionKinds maps Project-format list keys to the kind of their entries
+var projectSectionKinds = map[string]types.ResourceKind{
+	"clusters":      types.KindCluster,
+	"databaseUsers": types.KindDatabaseUser,
+	"databaseRoles": types.KindDatabaseRole,
+	"networkAccess": types.KindNetworkAccess,
+}
+
+// collectResourceLocations walks a YAML node tree and records every mapping with a
+// metadata.name. The kind comes from the mapping's kind field, or from the Project-format
+// section the mapping is listed under.
+func collectResourceLocations(node *yaml.Node, section, file string, locations map[string]policy.Location) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			collectResourceLocations(child, section, file, locations)
+		}
+	case yaml.MappingNode:
+		kind := types.ResourceKind(mappingValue(node, "kind"))
+		if kind == "" {
+			kind = projectSectionKinds[section]
+		}
+		if metadata := mappingNode(node, "metadata"); metadata != nil && kind != "" && kind != types.KindApplyDocument {
+			if name := mappingValue(metadata, "name"); name != "" {
+				key := apply.ExecutionOperationKey(kind, name)
+				if _, exists := locations[key]; !exists {
+					locations[key] = policy.Location{File: file, Line: node.Line}
+				}
+			}
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			collectResourceLocations(node.Content[i+1], node.Content[i].Value, file, locations)
+		}
+	}
+}
+
+func mappingNode(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) string {
+	if value := mappingNode(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
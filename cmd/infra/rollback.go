@@ -0,0 +1,218 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/ui"
+)
+
+// RollbackOptions contains the options for the rollback command
+type RollbackOptions struct {
+	OutputFormat    string
+	ProjectID       string
+	AutoApprove     bool
+	DryRun          bool
+	Verbose         bool
+	NoColor         bool
+	WaitForClusters bool
+	Timeout         time.Duration
+}
+
+// NewRollbackCmd creates the rollback subcommand
+func NewRollbackCmd() *cobra.Command {
+	opts := &RollbackOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rollback <execution-id>",
+		Short: "Undo the changes made by an apply execution",
+		Long: `Undo the changes made by an apply execution.
+
+Every apply records the inverse of each operation it completes: resources it created are
+deleted, resources it updated are restored to their previous spec, and resources it deleted
+are recreated where Atlas allows. The rollback command turns that record into a plan, shows
+it for review and runs it through the normal executor.
+
+Some changes cannot be undone: credentials are never recorded, so deleted password users,
+integrations and LDAP configurations cannot be recreated. Recreated clusters are empty; data
+is only recoverable from backup snapshots.`,
+		Example: `  # Review the rollback plan without changing anything
+  matlas infra rollback exec-20261018-093000-4f2a1c --dry-run
+
+  # Roll back an execution
+  matlas infra rollback exec-20261018-093000-4f2a1c
+
+  # Roll back without confirmation
+  matlas infra rollback exec-20261018-093000-4f2a1c --auto-approve`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRollback(cmd, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format for the rollback plan: table, json, yaml, summary")
+	cmd.Flags().StringVar(&opts.ProjectID, "project-id", "", "Atlas project ID (overrides the recorded project)")
+	cmd.Flags().BoolVar(&opts.AutoApprove, "auto-approve", false, "Skip interactive approval prompts")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show the rollback plan without executing it")
+	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Enable verbose output")
+	cmd.Flags().BoolVar(&opts.NoColor, "no-color", false, "Disable colored output")
+	cmd.Flags().BoolVar(&opts.WaitForClusters, "wait", false, "Wait for cluster changes to finish provisioning")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "Timeout for the rollback")
+
+	return cmd
+}
+
+func runRollback(cmd *cobra.Command, executionID string, opts *RollbackOptions) error {
+	if err := validateRollbackOptions(opts); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), opts.Timeout)
+	defer cancel()
+
+	store := apply.NewRollbackStore("")
+	rollbackLog, plan, err := buildRollbackPlan(store, executionID, opts)
+	if err != nil {
+		return err
+	}
+
+	if state, err := dag.NewStateManager("").LoadState(executionID); err == nil && state.Status == dag.ExecutionStatusRunning {
+		fmt.Fprintf(os.Stderr, "Warning: execution %s is still marked running; make sure it is no longer in progress\n", executionID)
+	}
+
+	if err := displayRollbackPlan(executionID, plan, rollbackLog, opts); err != nil {
+		return err
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	if !opts.AutoApprove {
+		confirmed, err := ui.NewConfirmationPrompt(false, false).Confirm(fmt.Sprintf("Roll back execution %s", executionID))
+		if err != nil {
+			return fmt.Errorf("failed to get user confirmation: %w", err)
+		}
+		if !confirmed {
+			return fmt.Errorf("rollback cancelled by user")
+		}
+	}
+
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	services, err := initializeServices(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize services: %w", err)
+	}
+
+	return executeRollbackPlan(ctx, plan, rollbackLog, store, services, opts)
+}
+
+// buildRollbackPlan loads the rollback record of an execution and turns it into a plan
+func buildRollbackPlan(store *apply.RollbackStore, executionID string, opts *RollbackOptions) (*apply.RollbackLog, *apply.Plan, error) {
+	rollbackLog, err := store.Load(executionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rollbackLog.RolledBackBy != "" {
+		return nil, nil, fmt.Errorf("execution %s was already rolled back by %s", executionID, rollbackLog.RolledBackBy)
+	}
+
+	plan, err := apply.BuildRollbackPlan(rollbackLog)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.ProjectID != "" {
+		plan.ProjectID = opts.ProjectID
+	}
+	return rollbackLog, plan, nil
+}
+
+func displayRollbackPlan(executionID string, plan *apply.Plan, rollbackLog *apply.RollbackLog, opts *RollbackOptions) error {
+	format := strings.ToLower(opts.OutputFormat)
+	if format == "table" || format == "summary" {
+		fmt.Printf("Rollback plan for execution %s\n\n", executionID)
+	}
+	if err := displayPlan(plan, &PlanOptions{OutputFormat: opts.OutputFormat, Verbose: opts.Verbose, NoColor: opts.NoColor}); err != nil {
+		return err
+	}
+
+	irreversible := rollbackLog.Irreversible()
+	if len(irreversible) == 0 {
+		return nil
+	}
+	if format != "table" && format != "summary" {
+		for _, entry := range irreversible {
+			fmt.Fprintf(os.Stderr, "Warning: %s (%s) will not be rolled back: %s\n", entry.Key, entry.Applied, entry.Reason)
+		}
+		return nil
+	}
+
+	fmt.Printf("\nThe following changes cannot be rolled back:\n")
+	rows := make([][]string, 0, len(irreversible))
+	for _, entry := range irreversible {
+		rows = append(rows, []string{entry.Key, string(entry.Applied), entry.Reason})
+	}
+	return output.NewFormatter(config.OutputTable, os.Stdout).Format(output.TableData{
+		Headers: []string{"RESOURCE", "APPLIED", "REASON"},
+		Rows:    rows,
+	})
+}
+
+func executeRollbackPlan(ctx context.Context, plan *apply.Plan, rollbackLog *apply.RollbackLog, store *apply.RollbackStore, services *ServiceClients, opts *RollbackOptions) error {
+	executorCfg := apply.DefaultEnhancedExecutorConfig()
+	executorCfg.BaseConfig.WaitForClusters = opts.WaitForClusters
+
+	durationHistory := loadDurationHistory()
+	enhancedExecutor := newInfraExecutor(services, executorCfg)
+	enhancedExecutor.SetDurationHistory(durationHistory)
+
+	// The rollback is an execution of its own, so it can be resumed or rolled back too
+	journal := newExecutionJournal()
+	enhancedExecutor.SetJournal(journal)
+	if err := journal.Begin(apply.NewExecutionID(time.Now()), plan, nil, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record execution: %v\n", err)
+	}
+	if id := journal.ExecutionID(); id != "" {
+		fmt.Printf("Execution ID: %s\n", id)
+	}
+
+	result, err := enhancedExecutor.Execute(ctx, plan)
+	saveDurationHistory(durationHistory)
+	if err != nil {
+		return fmt.Errorf("failed to execute rollback plan: %w", err)
+	}
+
+	if result.Status == apply.PlanStatusCompleted && ctx.Err() == nil {
+		rollbackLog.RolledBackBy = journal.ExecutionID()
+		if rollbackLog.RolledBackBy == "" {
+			rollbackLog.RolledBackBy = plan.ID
+		}
+		if err := store.Save(rollbackLog); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to mark execution %s as rolled back: %v\n", rollbackLog.ExecutionID, err)
+		}
+	}
+
+	return displayExecutionResults(result, &ApplyOptions{OutputFormat: opts.OutputFormat, Verbose: opts.Verbose})
+}
+
+func validateRollbackOptions(opts *RollbackOptions) error {
+	validOutputFormats := []string{"table", "json", "yaml", "summary"}
+	if !contains(validOutputFormats, strings.ToLower(opts.OutputFormat)) {
+		return fmt.Errorf("invalid output format: %s (valid options: %s)", opts.OutputFormat, strings.Join(validOutputFormats, ", "))
+	}
+	if opts.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	return nil
+}
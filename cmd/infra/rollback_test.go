@@ -0,0 +1,60 @@
+package infra
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func TestNewRollbackCmd(t *testing.T) {
+	cmd := NewRollbackCmd()
+
+	assert.Equal(t, "rollback <execution-id>", cmd.Use)
+	for _, flag := range []string{"auto-approve", "dry-run", "output", "project-id", "timeout", "wait"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), "rollback should have --%s", flag)
+	}
+
+	subCmd, _, err := NewInfraCmd().Find([]string{"rollback"})
+	require.NoError(t, err)
+	assert.Equal(t, "rollback", subCmd.Name())
+}
+
+func TestValidateRollbackOptions(t *testing.T) {
+	assert.NoError(t, validateRollbackOptions(&RollbackOptions{OutputFormat: "table", Timeout: time.Minute}))
+	assert.Error(t, validateRollbackOptions(&RollbackOptions{OutputFormat: "xml", Timeout: time.Minute}))
+	assert.Error(t, validateRollbackOptions(&RollbackOptions{OutputFormat: "json"}))
+}
+
+func TestBuildRollbackPlanFromStore(t *testing.T) {
+	store := apply.NewRollbackStore(t.TempDir())
+
+	_, _, err := buildRollbackPlan(store, "exec-missing", &RollbackOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no rollback record")
+
+	log := apply.NewRollbackLog("exec-1", "proj")
+	log.Record(&apply.PlannedOperation{Operation: apply.Operation{
+		Type:         apply.OperationCreate,
+		ResourceType: types.KindNetworkAccess,
+		ResourceName: "office",
+		Desired:      &types.NetworkAccessManifest{Kind: types.KindNetworkAccess, Spec: types.NetworkAccessSpec{IPAddress: "203.0.113.10"}},
+	}}, time.Now())
+	require.NoError(t, store.Save(log))
+
+	_, plan, err := buildRollbackPlan(store, "exec-1", &RollbackOptions{ProjectID: "other"})
+	require.NoError(t, err)
+	assert.Equal(t, "other", plan.ProjectID)
+	require.Len(t, plan.Operations, 1)
+	assert.Equal(t, apply.OperationDelete, plan.Operations[0].Type)
+
+	log.RolledBackBy = "exec-2"
+	require.NoError(t, store.Save(log))
+	_, _, err = buildRollbackPlan(store, "exec-1", &RollbackOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already rolled back by exec-2")
+}
@@ -28,6 +28,12 @@ type ShowOptions struct {
 	ResourceName string
 	ShowSecrets  bool
 	ShowMetadata bool
+
+	// Files are configuration files to display instead of the live Atlas state
+	Files     []string
+	StrictEnv bool
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewShowCmd creates the show subcommand
@@ -41,7 +47,11 @@ func NewShowCmd() *cobra.Command {
 
 This command discovers and displays the current configuration of Atlas resources
 such as clusters, database users, and network access lists. It's useful for
-understanding the current state before making changes.`,
+understanding the current state before making changes.
+
+With --file (or --overlay), the configuration is displayed instead, without contacting
+Atlas: both the compact form as written, where a forEach or count resource is one entry,
+and the expanded form with one entry per generated resource.`,
 		Example: `  # Show all resources in a project
   matlas infra show --project-id 507f1f77bcf86cd799439011
 
@@ -55,14 +65,22 @@ understanding the current state before making changes.`,
   matlas infra show --project-id 507f1f77bcf86cd799439011 --output json
 
   # Show with sensitive information
-  matlas infra show --project-id 507f1f77bcf86cd799439011 --show-secrets`,
+  matlas infra show --project-id 507f1f77bcf86cd799439011 --show-secrets
+
+  # Show the resources a configuration declares, with forEach expanded
+  matlas infra show -f config.yaml`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runShow(cmd, opts)
 		},
 	}
 
 	// Required flags
-	cmd.Flags().StringVar(&opts.ProjectID, "project-id", "", "Atlas project ID (required)")
+	cmd.Flags().StringVar(&opts.ProjectID, "project-id", "", "Atlas project ID (required unless --file is given)")
+
+	// Configuration flags
+	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to display instead of Atlas state (supports glob patterns)")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
+	cmd.Flags().BoolVar(&opts.StrictEnv, "strict-env", false, "Fail on undefined environment variables")
 
 	// Output flags
 	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, summary")
@@ -89,6 +107,9 @@ func runShow(cmd *cobra.Command, opts *ShowOptions) error {
 	if err := validateShowOptions(opts); err != nil {
 		return fmt.Errorf("invalid options: %w", err)
 	}
+	if opts.showsConfiguration() {
+		return runShowConfiguration(opts)
+	}
 
 	// Tighten project-id validation (ObjectID format)
 	if err := validation.ValidateProjectID(opts.ProjectID); err != nil {
@@ -427,7 +448,7 @@ func maskSecrets(state *apply.ProjectState) *apply.ProjectState {
 }
 
 func validateShowOptions(opts *ShowOptions) error {
-	if opts.ProjectID == "" {
+	if opts.ProjectID == "" && !opts.showsConfiguration() {
 		return fmt.Errorf("project ID is required")
 	}
 
@@ -0,0 +1,136 @@
+package infra
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/output"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// configurationView lists the resources of configuration files as written, with each
+// forEach or count resource as one entry, and as expanded when loading
+type configurationView struct {
+	Compact  []compactResourceView  `json:"compact" yaml:"compact"`
+	Expanded []expandedResourceView `json:"expanded" yaml:"expanded"`
+}
+
+type compactResourceView struct {
+	Kind string `json:"kind" yaml:"kind"`
+	Name string `json:"name" yaml:"name"`
+	// Keys are the forEach keys or count indexes the resource was expanded for
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+}
+
+type expandedResourceView struct {
+	Kind    string `json:"kind" yaml:"kind"`
+	Name    string `json:"name" yaml:"name"`
+	ForEach string `json:"forEach,omitempty" yaml:"forEach,omitempty"`
+	Key     string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// showsConfiguration reports whether show displays configuration files rather than Atlas
+func (opts *ShowOptions) showsConfiguration() bool {
+	return len(opts.Files) > 0 || opts.Overlay != ""
+}
+
+func runShowConfiguration(opts *ShowOptions) error {
+	files, err := opts.configFiles(opts.Files)
+	if err != nil {
+		return fmt.Errorf("failed to expand file patterns: %w", err)
+	}
+	configs, err := loadConfigurations(files, &ApplyOptions{
+		ConfigInputs: opts.ConfigInputs,
+		StrictEnv:    opts.StrictEnv,
+		Verbose:      opts.Verbose,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load configurations: %w", err)
+	}
+
+	var resources []types.ResourceManifest
+	for _, cfg := range configs {
+		if doc, ok := cfg.Config.(*types.ApplyDocument); ok {
+			resources = append(resources, doc.Resources...)
+		}
+	}
+	return displayConfigurationView(buildConfigurationView(resources, opts), opts)
+}
+
+// buildConfigurationView groups the resources expanded from the same forEach or count back
+// into the resource as written. Entries keep the order of the configuration.
+func buildConfigurationView(resources []types.ResourceManifest, opts *ShowOptions) *configurationView {
+	view := &configurationView{Compact: []compactResourceView{}, Expanded: []expandedResourceView{}}
+	groups := make(map[string]int)
+	for _, resource := range resources {
+		annotations := resource.Metadata.Annotations
+		template, key := annotations[types.AnnotationForEach], annotations[types.AnnotationForEachKey]
+		if opts.ResourceName != "" && opts.ResourceName != resource.Metadata.Name && opts.ResourceName != template {
+			continue
+		}
+		view.Expanded = append(view.Expanded, expandedResourceView{
+			Kind:    string(resource.Kind),
+			Name:    resource.Metadata.Name,
+			ForEach: template,
+			Key:     key,
+		})
+
+		if template == "" {
+			view.Compact = append(view.Compact, compactResourceView{Kind: string(resource.Kind), Name: resource.Metadata.Name})
+			continue
+		}
+		group := strings.Join([]string{string(resource.Kind), apply.ModuleOf(resource), template}, "\x00")
+		index, ok := groups[group]
+		if !ok {
+			index = len(view.Compact)
+			groups[group] = index
+			view.Compact = append(view.Compact, compactResourceView{Kind: string(resource.Kind), Name: template})
+		}
+		view.Compact[index].Keys = append(view.Compact[index].Keys, key)
+	}
+	return view
+}
+
+func displayConfigurationView(view *configurationView, opts *ShowOptions) error {
+	switch strings.ToLower(opts.OutputFormat) {
+	case "json":
+		return output.NewFormatter(config.OutputJSON, os.Stdout).Format(view)
+	case "yaml":
+		return output.NewFormatter(config.OutputYAML, os.Stdout).Format(view)
+	case "summary":
+		rows := [][]string{
+			{"As written", fmt.Sprintf("%d", len(view.Compact))},
+			{"Expanded", fmt.Sprintf("%d", len(view.Expanded))},
+		}
+		data := output.TableData{Headers: []string{"Resources", "Count"}, Rows: rows}
+		return output.NewFormatter(config.OutputTable, os.Stdout).Format(data)
+	}
+
+	formatter := output.NewFormatter(config.OutputTable, os.Stdout)
+	if _, err := fmt.Fprintf(os.Stdout, "Configuration (%d resources as written)\n\n", len(view.Compact)); err != nil {
+		return err
+	}
+	compactRows := make([][]string, 0, len(view.Compact))
+	for _, r := range view.Compact {
+		instances := "1"
+		if r.Keys != nil {
+			instances = fmt.Sprintf("%d: %s", len(r.Keys), strings.Join(r.Keys, ", "))
+		}
+		compactRows = append(compactRows, []string{r.Kind, r.Name, instances})
+	}
+	if err := formatter.Format(output.TableData{Headers: []string{"KIND", "NAME", "INSTANCES"}, Rows: compactRows}); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(os.Stdout, "\nExpanded (%d resources)\n\n", len(view.Expanded)); err != nil {
+		return err
+	}
+	expandedRows := make([][]string, 0, len(view.Expanded))
+	for _, r := range view.Expanded {
+		expandedRows = append(expandedRows, []string{r.Kind, r.Name, r.ForEach, r.Key})
+	}
+	return formatter.Format(output.TableData{Headers: []string{"KIND", "NAME", "FOREACH", "KEY"}, Rows: expandedRows})
+}
@@ -61,3 +61,32 @@ func TestMaskSecrets(t *testing.T) {
 	// Network access copied unchanged
 	assert.Equal(t, state.NetworkAccess, masked.NetworkAccess)
 }
+
+func TestBuildConfigurationView(t *testing.T) {
+	expanded := func(name, key string) types.ResourceManifest {
+		return types.ResourceManifest{Kind: types.KindDatabaseUser, Metadata: types.ResourceMetadata{Name: name, Annotations: map[string]string{
+			types.AnnotationForEach:    "user-${each.key}",
+			types.AnnotationForEachKey: key,
+		}}}
+	}
+	resources := []types.ResourceManifest{
+		expanded("user-alice", "alice"),
+		{Kind: types.KindCluster, Metadata: types.ResourceMetadata{Name: "app"}},
+		expanded("user-bob", "bob"),
+	}
+
+	view := buildConfigurationView(resources, &ShowOptions{})
+	assert.Equal(t, []compactResourceView{
+		{Kind: "DatabaseUser", Name: "user-${each.key}", Keys: []string{"alice", "bob"}},
+		{Kind: "Cluster", Name: "app"},
+	}, view.Compact)
+	if assert.Len(t, view.Expanded, 3) {
+		assert.Equal(t, expandedResourceView{Kind: "DatabaseUser", Name: "user-bob", ForEach: "user-${each.key}", Key: "bob"}, view.Expanded[2])
+	}
+
+	filtered := buildConfigurationView(resources, &ShowOptions{ResourceName: "user-${each.key}"})
+	assert.Len(t, filtered.Compact, 1)
+	assert.Len(t, filtered.Expanded, 2)
+
+	assert.NoError(t, validateShowOptions(&ShowOptions{Files: []string{"config.yaml"}, OutputFormat: "table", Timeout: time.Minute}))
+}
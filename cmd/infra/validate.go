@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"github.com/teabranch/matlas-cli/internal/apply"
 	"github.com/teabranch/matlas-cli/internal/config"
@@ -27,6 +29,11 @@ type ValidateOptions struct {
 	StrictMode   bool
 	LintRules    bool
 	BatchMode    bool
+	Render       bool
+	ShowSecrets  bool
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewValidateCmd creates the validate subcommand
@@ -55,7 +62,10 @@ It performs syntax checking, schema validation, dependency verification, and opt
 
   # Validate in batch mode (multiple files)
   matlas infra validate -f cluster.yaml -f users.yaml --batch
-  matlas infra validate cluster.yaml users.yaml --batch`,
+  matlas infra validate cluster.yaml users.yaml --batch
+
+  # Show the production configuration after variables, modules and overlay patches
+  matlas infra validate --overlay overlays/prod --render`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Support positional arguments as files if no --file flag provided
 			if len(opts.Files) == 0 && len(args) > 0 {
@@ -67,6 +77,7 @@ It performs syntax checking, schema validation, dependency verification, and opt
 
 	// File input flags
 	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to validate (supports glob patterns)")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
 
 	// Output and behavior flags
 	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, summary")
@@ -79,6 +90,10 @@ It performs syntax checking, schema validation, dependency verification, and opt
 	cmd.Flags().BoolVar(&opts.LintRules, "lint", false, "Enable linting rules for best practices")
 	cmd.Flags().BoolVar(&opts.BatchMode, "batch", false, "Enable batch validation mode for multiple files")
 
+	// Rendering flags
+	cmd.Flags().BoolVar(&opts.Render, "render", false, "Print the fully rendered configuration as YAML once it is valid")
+	cmd.Flags().BoolVar(&opts.ShowSecrets, "show-secrets", false, "Do not mask credentials in --render output")
+
 	return cmd
 }
 
@@ -91,7 +106,7 @@ func runValidate(cmd *cobra.Command, opts *ValidateOptions) error {
 	}
 
 	// Expand file patterns
-	files, err := expandFilePatterns(opts.Files)
+	files, err := opts.configFiles(opts.Files)
 	if err != nil {
 		return fmt.Errorf("failed to expand file patterns: %w", err)
 	}
@@ -104,6 +119,9 @@ func runValidate(cmd *cobra.Command, opts *ValidateOptions) error {
 		AllowStdin:   true,
 		MaxFileSize:  10 * 1024 * 1024, // 10MB
 	}
+	if err := opts.loaderOptions(loaderOpts); err != nil {
+		return err
+	}
 
 	loader := apply.NewConfigurationLoader(loaderOpts)
 
@@ -123,11 +141,69 @@ func runValidate(cmd *cobra.Command, opts *ValidateOptions) error {
 	if err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if err := checkOverlayMatched(loaderOpts.Overlay); err != nil {
+		return err
+	}
+
+	// Rendering shows the configuration itself, unless it needs fixing first
+	if opts.Render && allValid(results) {
+		return renderConfigurations(os.Stdout, results, opts.ShowSecrets)
+	}
 
 	// Display results
 	return displayValidationResults(results, opts)
 }
 
+func allValid(results []*ValidationFileResult) bool {
+	for _, result := range results {
+		if !result.IsValid {
+			return false
+		}
+	}
+	return true
+}
+
+// renderConfigurations writes each loaded configuration as a YAML document, with templates
+// substituted, modules expanded and overlay patches applied
+func renderConfigurations(w io.Writer, results []*ValidationFileResult, showSecrets bool) error {
+	rendered := 0
+	for _, result := range results {
+		if result.LoadResult == nil || result.LoadResult.Config == nil {
+			continue
+		}
+		// Round-trip through generic values so credentials can be found in resource specs
+		var values interface{}
+		data, err := yaml.Marshal(result.LoadResult.Config)
+		if err == nil {
+			err = yaml.Unmarshal(data, &values)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", result.FilePath, err)
+		}
+		if !showSecrets {
+			values = apply.RedactValues(values)
+		}
+
+		separator := "---\n"
+		if rendered == 0 {
+			separator = ""
+		}
+		rendered++
+		if _, err := fmt.Fprintf(w, "%s# Source: %s\n", separator, result.FilePath); err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(values); err != nil {
+			return fmt.Errorf("failed to render %s: %w", result.FilePath, err)
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type ValidationFileResult struct {
 	FilePath          string                          `json:"filePath"`
 	LoadResult        *apply.LoadResult               `json:"loadResult,omitempty"`
@@ -501,8 +577,8 @@ func displayValidationTable(results []*ValidationFileResult, opts *ValidateOptio
 }
 
 func validateValidateOptions(opts *ValidateOptions) error {
-	if len(opts.Files) == 0 {
-		return fmt.Errorf("at least one configuration file must be specified with --file")
+	if err := opts.requireFiles(opts.Files); err != nil {
+		return err
 	}
 
 	// Validate output format
@@ -649,6 +725,8 @@ func convertNetworkSpecToConfig(meta types.ResourceMetadata, spec types.NetworkA
 		AWSSecurityGroup: spec.AWSSecurityGroup,
 		Comment:          spec.Comment,
 		DeleteAfterDate:  spec.DeleteAfterDate,
+		ExpiresAt:        spec.ExpiresAt,
+		TTL:              spec.TTL,
 		DependsOn:        meta.DependsOn,
 	}
 }
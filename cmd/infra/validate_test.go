@@ -1,9 +1,11 @@
 package infra
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/teabranch/matlas-cli/internal/apply"
@@ -178,3 +180,50 @@ func TestBuildDesiredState_IncludesProjectTags(t *testing.T) {
 		t.Fatalf("expected project tag env=staging, got %q", got)
 	}
 }
+
+func TestRenderConfigurations_MasksCredentials(t *testing.T) {
+	doc := &types.ApplyDocument{
+		APIVersion: "matlas.mongodb.com/v1",
+		Kind:       "ApplyDocument",
+		Metadata:   types.MetadataConfig{Name: "prod"},
+		Resources: []types.ResourceManifest{
+			{Kind: types.KindDatabaseUser, Metadata: types.ResourceMetadata{Name: "app"}, Spec: map[string]interface{}{"username": "app", "password": "s3cretpassw0rd"}},
+			{Kind: types.KindIntegration, Metadata: types.ResourceMetadata{Name: "pd"}, Spec: map[string]interface{}{"type": "PAGER_DUTY", "serviceKey": "pagerdutyservicekey"}},
+		},
+	}
+	results := []*ValidationFileResult{
+		{FilePath: "base.yaml", IsValid: true, LoadResult: &apply.LoadResult{Config: doc}},
+		{FilePath: "other.yaml", IsValid: true, LoadResult: &apply.LoadResult{Config: doc}},
+	}
+
+	var masked bytes.Buffer
+	if err := renderConfigurations(&masked, results, false); err != nil {
+		t.Fatalf("renderConfigurations failed: %v", err)
+	}
+	out := masked.String()
+	if !strings.HasPrefix(out, "# Source: base.yaml\n") || !strings.Contains(out, "---\n# Source: other.yaml\n") {
+		t.Errorf("Expected one document per file, got:\n%s", out)
+	}
+	if strings.Contains(out, "s3cretpassw0rd") || strings.Contains(out, "pagerdutyservicekey") || !strings.Contains(out, "username: app") {
+		t.Errorf("Expected only credentials to be masked, got:\n%s", out)
+	}
+
+	var shown bytes.Buffer
+	if err := renderConfigurations(&shown, results[:1], true); err != nil {
+		t.Fatalf("renderConfigurations failed: %v", err)
+	}
+	if !strings.Contains(shown.String(), "password: s3cretpassw0rd") {
+		t.Errorf("Expected --show-secrets to keep credentials, got:\n%s", shown.String())
+	}
+}
+
+func TestValidateValidateOptions_AcceptsOverlay(t *testing.T) {
+	opts := &ValidateOptions{OutputFormat: "table"}
+	if err := validateValidateOptions(opts); err == nil {
+		t.Error("Expected an error without files or an overlay")
+	}
+	opts.Overlay = "overlays/prod"
+	if err := validateValidateOptions(opts); err != nil {
+		t.Errorf("Expected an overlay to supply the files, got %v", err)
+	}
+}
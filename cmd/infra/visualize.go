@@ -27,6 +27,9 @@ type VisualizeOptions struct {
 	ShowLevels            bool
 	CompactMode           bool
 	ColorScheme           string
+
+	// ConfigInputs are the --var-file, --var and --overlay flags
+	ConfigInputs
 }
 
 // NewVisualizeCmd creates the visualize subcommand
@@ -69,6 +72,7 @@ func NewVisualizeCmd() *cobra.Command {
 
 	// File input flags
 	cmd.Flags().StringSliceVarP(&opts.Files, "file", "f", []string{}, "Configuration files to visualize (supports glob patterns)")
+	addConfigInputFlags(cmd, &opts.ConfigInputs)
 
 	// Output flags
 	cmd.Flags().StringVar(&opts.OutputFormat, "format", "ascii", "Visualization format: dot, mermaid, ascii, json")
@@ -96,12 +100,12 @@ func runVisualize(cmd *cobra.Command, opts *VisualizeOptions) error {
 	defer cancel()
 
 	// Validate options
-	if len(opts.Files) == 0 {
-		return fmt.Errorf("no configuration files specified (use -f or provide files as arguments)")
+	if len(opts.Files) == 0 && opts.Overlay == "" {
+		return fmt.Errorf("no configuration files specified (use -f, --overlay or provide files as arguments)")
 	}
 
 	// Expand file patterns
-	files, err := expandFilePatterns(opts.Files)
+	files, err := opts.configFiles(opts.Files)
 	if err != nil {
 		return fmt.Errorf("failed to expand file patterns: %w", err)
 	}
@@ -119,8 +123,9 @@ func runVisualize(cmd *cobra.Command, opts *VisualizeOptions) error {
 
 	// Load configurations
 	configs, err := loadConfigurations(files, &ApplyOptions{
-		StrictEnv: opts.StrictEnv,
-		Verbose:   opts.Verbose,
+		ConfigInputs: opts.ConfigInputs,
+		StrictEnv:    opts.StrictEnv,
+		Verbose:      opts.Verbose,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to load configurations: %w", err)
@@ -76,6 +76,7 @@ var (
 			// 2. Initialize signal handler for graceful shutdown
 			signalHandler = cli.NewSignalHandler(logger, 30) // 30 second timeout
 			signalHandler.Start()
+			cli.SetDefaultSignalHandler(signalHandler)
 
 			// 3. Initialize enhanced error handling
 			errorFormatter = cli.NewEnhancedErrorFormatter(verbose, logger)
@@ -10,17 +10,20 @@ import (
 // GetBuiltinRules returns all built-in dependency rules
 func GetBuiltinRules() []Rule {
 	return []Rule{
-		// High priority: Project dependencies
+		// High priority: Project dependencies and explicit references
 		NewProjectDependencyRule(),
+		NewReferenceDependencyRule(),
 
 		// Medium-high priority: Resource kind dependencies
 		NewClusterDependencyRule(),
 		NewRoleDependencyRule(),
 		NewVPCDependencyRule(),
+		NewDatabaseResourceDependencyRule(),
 
 		// Medium priority: Ordering rules
 		NewNetworkAccessOrderingRule(),
 		NewSearchIndexOrderingRule(),
+		NewDatabaseResourceOrderingRule(),
 
 		// Lower priority: Same-cluster conflict detection
 		NewSameClusterConflictRule(),
@@ -44,6 +47,34 @@ func NewProjectDependencyRule() Rule {
 	)
 }
 
+// MetadataReferences is the operation metadata key listing the Kind/name of each
+// resource the operation's manifest references
+const MetadataReferences = "references"
+
+// NewReferenceDependencyRule creates a rule for ${ref:...} expressions
+// Resources wait for every resource their manifest references
+func NewReferenceDependencyRule() Rule {
+	return NewPropertyBasedRule(
+		"reference_dependency",
+		"Resources referencing another resource wait for it to be applied",
+		190,
+		func(ctx context.Context, from, to *PlannedOperation) (*Edge, error) {
+			refs, _ := from.Metadata[MetadataReferences].([]string)
+			for _, ref := range refs {
+				kind, name, _ := strings.Cut(ref, "/")
+				if name == to.ResourceName && strings.EqualFold(kind, string(to.ResourceType)) {
+					return &Edge{
+						Type:   DependencyTypeHard,
+						Weight: 1.0,
+						Reason: "Resource references " + ref,
+					}, nil
+				}
+			}
+			return nil, nil
+		},
+	)
+}
+
 // NewClusterDependencyRule creates a rule for cluster dependencies
 // Database users, indexes, and roles depend on clusters
 func NewClusterDependencyRule() Rule {
@@ -169,6 +200,78 @@ func NewSearchIndexOrderingRule() Rule {
 	)
 }
 
+// NewDatabaseResourceDependencyRule creates a rule for collection, index and view dependencies
+// Collections, indexes and views require their cluster, indexes require their collection,
+// views require the collection or view they read, and materialized views the indexes of
+// their collection
+func NewDatabaseResourceDependencyRule() Rule {
+	return NewPropertyBasedRule(
+		"database_resource_dependency",
+		"Collections, indexes and views require their cluster, and indexes and views their collections, to exist",
+		148,
+		func(ctx context.Context, from, to *PlannedOperation) (*Edge, error) {
+			if !isDatabaseResource(from.ResourceType) {
+				return nil, nil
+			}
+			fromCluster := extractClusterName(from.Spec)
+			if fromCluster == "" || fromCluster != extractClusterName(to.Spec) {
+				return nil, nil
+			}
+
+			switch {
+			case to.ResourceType == types.KindCluster:
+				return &Edge{
+					Type:   DependencyTypeHard,
+					Weight: 1.0,
+					Reason: "Resource requires cluster to exist",
+				}, nil
+			case from.ResourceType == types.KindIndex && to.ResourceType == types.KindCollection &&
+				extractNamespace(from.Spec) == extractNamespace(to.Spec):
+				return &Edge{
+					Type:   DependencyTypeHard,
+					Weight: 1.0,
+					Reason: "Index requires its collection to exist",
+				}, nil
+			case (from.ResourceType == types.KindView || from.ResourceType == types.KindMaterializedView) &&
+				(to.ResourceType == types.KindCollection || to.ResourceType == types.KindView || to.ResourceType == types.KindMaterializedView) &&
+				extractViewSource(from.Spec) != "" && extractViewSource(from.Spec) == extractNamespace(to.Spec):
+				return &Edge{
+					Type:   DependencyTypeHard,
+					Weight: 1.0,
+					Reason: "View requires the collection it reads to exist",
+				}, nil
+			case from.ResourceType == types.KindMaterializedView && to.ResourceType == types.KindIndex &&
+				extractNamespace(from.Spec) == extractNamespace(to.Spec):
+				return &Edge{
+					Type:   DependencyTypeHard,
+					Weight: 1.0,
+					Reason: "Materialized view requires the indexes of its collection to exist",
+				}, nil
+			}
+			return nil, nil
+		},
+	)
+}
+
+// NewDatabaseResourceOrderingRule creates an ordering rule for collections, indexes and views
+func NewDatabaseResourceOrderingRule() Rule {
+	return NewPropertyBasedRule(
+		"database_resource_ordering",
+		"Collections, indexes and views are applied after the project's database users",
+		44,
+		func(ctx context.Context, from, to *PlannedOperation) (*Edge, error) {
+			if !isDatabaseResource(from.ResourceType) || to.ResourceType != types.KindDatabaseUser {
+				return nil, nil
+			}
+			return &Edge{
+				Type:   DependencyTypeSoft,
+				Weight: 0.5,
+				Reason: "Database resources follow user changes on the cluster",
+			}, nil
+		},
+	)
+}
+
 // NewSameClusterConflictRule creates a mutual exclusion rule for same-cluster modifications
 func NewSameClusterConflictRule() Rule {
 	return NewMutualExclusionRule(
@@ -187,6 +290,15 @@ func NewSameClusterConflictRule() Rule {
 
 // Helper functions to extract information from resource specs
 
+// isDatabaseResource reports whether resources of a kind live inside a cluster's databases
+func isDatabaseResource(kind types.ResourceKind) bool {
+	switch kind {
+	case types.KindCollection, types.KindIndex, types.KindView, types.KindMaterializedView:
+		return true
+	}
+	return false
+}
+
 func extractProjectName(spec interface{}) string {
 	switch s := spec.(type) {
 	case *types.ClusterManifest:
@@ -224,6 +336,42 @@ func extractClusterName(spec interface{}) string {
 		return s.Spec.ClusterName
 	case types.SearchIndexManifest:
 		return s.Spec.ClusterName
+	case *types.CollectionManifest:
+		return s.Spec.ClusterName
+	case *types.IndexManifest:
+		return s.Spec.ClusterName
+	case *types.ViewManifest:
+		return s.Spec.ClusterName
+	case *types.MaterializedViewManifest:
+		return s.Spec.ClusterName
+	default:
+		return ""
+	}
+}
+
+// extractNamespace returns the database.collection a collection, index or view manifest targets
+func extractNamespace(spec interface{}) string {
+	switch s := spec.(type) {
+	case *types.CollectionManifest:
+		return s.Spec.DatabaseName + "." + s.Spec.Name
+	case *types.IndexManifest:
+		return s.Spec.DatabaseName + "." + s.Spec.CollectionName
+	case *types.ViewManifest:
+		return s.Spec.DatabaseName + "." + s.Spec.Name
+	case *types.MaterializedViewManifest:
+		return s.Spec.DatabaseName + "." + s.Spec.Name
+	default:
+		return ""
+	}
+}
+
+// extractViewSource returns the database.collection a view or materialized view manifest reads
+func extractViewSource(spec interface{}) string {
+	switch s := spec.(type) {
+	case *types.ViewManifest:
+		return s.Spec.DatabaseName + "." + s.Spec.ViewOn
+	case *types.MaterializedViewManifest:
+		return s.Spec.DatabaseName + "." + s.Spec.Source
 	default:
 		return ""
 	}
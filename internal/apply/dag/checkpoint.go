@@ -70,6 +70,21 @@ func NewCheckpointManager(config CheckpointConfig) *CheckpointManager {
 
 // CreateCheckpoint creates a checkpoint of the current execution state
 func (cm *CheckpointManager) CreateCheckpoint(executionID, planID string, state *ExecutionState, graph *Graph, stage int, operationID, reason string) (*Checkpoint, error) {
+	checkpoint, err := cm.createCheckpoint(executionID, planID, state, graph, stage, operationID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Auto-prune old checkpoints once the lock is released; pruning lists and deletes checkpoints
+	if err := cm.pruneOldCheckpoints(executionID); err != nil {
+		// Log warning but don't fail the checkpoint creation
+		fmt.Fprintf(os.Stderr, "Warning: failed to prune old checkpoints: %v\n", err)
+	}
+
+	return checkpoint, nil
+}
+
+func (cm *CheckpointManager) createCheckpoint(executionID, planID string, state *ExecutionState, graph *Graph, stage int, operationID, reason string) (*Checkpoint, error) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
@@ -79,7 +94,7 @@ func (cm *CheckpointManager) CreateCheckpoint(executionID, planID string, state
 	}
 
 	// Generate checkpoint ID
-	checkpointID := fmt.Sprintf("cp-%s-%d-%d", executionID, stage, time.Now().Unix())
+	checkpointID := fmt.Sprintf("cp-%s-%d-%d", executionID, stage, time.Now().UnixNano())
 
 	// Clone state to avoid concurrent modifications
 	stateSnapshot := state.Clone()
@@ -113,12 +128,6 @@ func (cm *CheckpointManager) CreateCheckpoint(executionID, planID string, state
 		return nil, fmt.Errorf("failed to write checkpoint: %w", err)
 	}
 
-	// Auto-prune old checkpoints if enabled
-	if err := cm.pruneOldCheckpoints(executionID); err != nil {
-		// Log warning but don't fail the checkpoint creation
-		fmt.Fprintf(os.Stderr, "Warning: failed to prune old checkpoints: %v\n", err)
-	}
-
 	return checkpoint, nil
 }
 
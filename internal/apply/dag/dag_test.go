@@ -1,6 +1,7 @@
 package dag
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -317,3 +318,51 @@ func TestValidation(t *testing.T) {
 		t.Error("Expected validation to fail for graph with cycle")
 	}
 }
+
+func TestReferenceDependencyRule(t *testing.T) {
+	rule := NewReferenceDependencyRule()
+	cluster := &PlannedOperation{ID: "op-0", ResourceType: types.KindCluster, ResourceName: "app-prod"}
+	user := &PlannedOperation{
+		ID:           "op-1",
+		ResourceType: types.KindDatabaseUser,
+		ResourceName: "app",
+		Metadata:     map[string]interface{}{MetadataReferences: []string{"cluster/app-prod"}},
+	}
+
+	edge, err := rule.Evaluate(context.Background(), user, cluster)
+	if err != nil || edge == nil || edge.Type != DependencyTypeHard {
+		t.Fatalf("Expected a hard edge from the user to the cluster, got %+v: %v", edge, err)
+	}
+	if edge, _ := rule.Evaluate(context.Background(), cluster, user); edge != nil {
+		t.Errorf("Expected no edge from an operation without references, got %+v", edge)
+	}
+	other := &PlannedOperation{ID: "op-2", ResourceType: types.KindCluster, ResourceName: "App-Prod"}
+	if edge, _ := rule.Evaluate(context.Background(), user, other); edge != nil {
+		t.Errorf("Expected resource names to match exactly, got %+v", edge)
+	}
+}
+
+func TestDatabaseResourceDependencyRule(t *testing.T) {
+	rule := NewDatabaseResourceDependencyRule()
+	cluster := &PlannedOperation{ID: "op-0", ResourceType: types.KindCluster, ResourceName: "cluster0",
+		Spec: &types.ClusterManifest{Metadata: types.ResourceMetadata{Name: "cluster0"}}}
+	collection := &PlannedOperation{ID: "op-1", ResourceType: types.KindCollection, ResourceName: "orders",
+		Spec: &types.CollectionManifest{Spec: types.CollectionSpec{ClusterName: "cluster0", DatabaseName: "app", Name: "orders"}}}
+	index := &PlannedOperation{ID: "op-2", ResourceType: types.KindIndex, ResourceName: "orders-email",
+		Spec: &types.IndexManifest{Spec: types.IndexSpec{ClusterName: "cluster0", DatabaseName: "app", CollectionName: "orders"}}}
+	otherIndex := &PlannedOperation{ID: "op-3", ResourceType: types.KindIndex, ResourceName: "users-email",
+		Spec: &types.IndexManifest{Spec: types.IndexSpec{ClusterName: "cluster0", DatabaseName: "app", CollectionName: "users"}}}
+
+	if edge, err := rule.Evaluate(context.Background(), collection, cluster); err != nil || edge == nil || edge.Type != DependencyTypeHard {
+		t.Fatalf("Expected a hard edge from the collection to its cluster, got %+v: %v", edge, err)
+	}
+	if edge, _ := rule.Evaluate(context.Background(), index, collection); edge == nil || edge.Type != DependencyTypeHard {
+		t.Errorf("Expected a hard edge from the index to its collection, got %+v", edge)
+	}
+	if edge, _ := rule.Evaluate(context.Background(), otherIndex, collection); edge != nil {
+		t.Errorf("Expected no edge to a collection in another namespace, got %+v", edge)
+	}
+	if edge, _ := rule.Evaluate(context.Background(), cluster, collection); edge != nil {
+		t.Errorf("Expected no edge from the cluster, got %+v", edge)
+	}
+}
@@ -19,6 +19,13 @@ type ExecutionState struct {
 	UpdatedAt   time.Time  `json:"updatedAt"`
 	CompletedAt *time.Time `json:"completedAt,omitempty"`
 
+	// Inputs are the configuration files the plan was generated from; resuming
+	// regenerates the plan from them
+	Inputs []string `json:"inputs,omitempty"`
+	// ConfigHash identifies the configuration the plan was generated from; an execution
+	// is only resumed with the same configuration
+	ConfigHash string `json:"configHash,omitempty"`
+
 	// Status
 	Status       ExecutionStatus `json:"status"`
 	CurrentStage int             `json:"currentStage"`
@@ -54,12 +61,17 @@ const (
 	ExecutionStatusFailed    ExecutionStatus = "failed"
 	ExecutionStatusCancelled ExecutionStatus = "cancelled"
 	ExecutionStatusPaused    ExecutionStatus = "paused"
+	ExecutionStatusAbandoned ExecutionStatus = "abandoned"
 )
 
 // OperationState tracks the state of a single operation
 type OperationState struct {
 	OperationID  string          `json:"operationId"`
 	NodeID       string          `json:"nodeId"`
+	ResourceKind string          `json:"resourceKind,omitempty"`
+	ResourceName string          `json:"resourceName,omitempty"`
+	Type         string          `json:"type,omitempty"`
+	Stage        int             `json:"stage"`
 	Status       OperationStatus `json:"status"`
 	StartedAt    *time.Time      `json:"startedAt,omitempty"`
 	CompletedAt  *time.Time      `json:"completedAt,omitempty"`
@@ -288,6 +300,33 @@ func (state *ExecutionState) UpdateOperationState(opID string, status OperationS
 	state.UpdatedAt = now
 }
 
+// ResetOperation returns an operation to pending so a resumed execution runs it again
+func (state *ExecutionState) ResetOperation(opID string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	opState, exists := state.Operations[opID]
+	if !exists {
+		return
+	}
+
+	switch opState.Status {
+	case OpStatusCompleted:
+		state.CompletedOps--
+	case OpStatusFailed:
+		state.FailedOps--
+	case OpStatusSkipped:
+		state.SkippedOps--
+	}
+
+	opState.Status = OpStatusPending
+	opState.StartedAt = nil
+	opState.CompletedAt = nil
+	opState.Duration = 0
+	opState.Error = ""
+	state.UpdatedAt = time.Now()
+}
+
 // SetStage updates the current stage
 func (state *ExecutionState) SetStage(stage int) {
 	state.mu.Lock()
@@ -376,6 +415,8 @@ func (state *ExecutionState) Clone() *ExecutionState {
 		StartedAt:      state.StartedAt,
 		UpdatedAt:      state.UpdatedAt,
 		CompletedAt:    state.CompletedAt,
+		Inputs:         append([]string(nil), state.Inputs...),
+		ConfigHash:     state.ConfigHash,
 		Status:         state.Status,
 		CurrentStage:   state.CurrentStage,
 		TotalStages:    state.TotalStages,
@@ -394,6 +435,10 @@ func (state *ExecutionState) Clone() *ExecutionState {
 		clone.Operations[opID] = &OperationState{
 			OperationID:  opState.OperationID,
 			NodeID:       opState.NodeID,
+			ResourceKind: opState.ResourceKind,
+			ResourceName: opState.ResourceName,
+			Type:         opState.Type,
+			Stage:        opState.Stage,
 			Status:       opState.Status,
 			StartedAt:    opState.StartedAt,
 			CompletedAt:  opState.CompletedAt,
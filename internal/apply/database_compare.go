@@ -0,0 +1,363 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// Changes reported by CompareDatabaseSnapshots
+const (
+	// DatabaseChangeMissing is in the source but not in the target
+	DatabaseChangeMissing = "missing"
+	// DatabaseChangeExtra is in the target but not in the source
+	DatabaseChangeExtra = "extra"
+	// DatabaseChangeChanged differs between the source and the target
+	DatabaseChangeChanged = "changed"
+)
+
+// DatabaseDifference is one difference between the database schema of a source and a target
+type DatabaseDifference struct {
+	Database   string `json:"database" yaml:"database"`
+	Collection string `json:"collection" yaml:"collection"`
+	// Kind is collection, view, validator, options or index
+	Kind string `json:"kind" yaml:"kind"`
+	// Index names the index of index differences
+	Index  string `json:"index,omitempty" yaml:"index,omitempty"`
+	Change string `json:"change" yaml:"change"`
+	// Fields lists what differs in a changed item
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// CompareDatabaseSnapshots lists the collections, views and indexes missing from or extra
+// in the target, and the validators, collection options, view definitions and index
+// definitions that differ. Options are compared the way plan compares Collection and
+// Index resources, so server defaults don't show up as differences. The _id index is
+// not compared.
+func CompareDatabaseSnapshots(source, target *types.DatabaseSnapshot) []DatabaseDifference {
+	sourceCollections, targetCollections := snapshotCollections(source), snapshotCollections(target)
+
+	var differences []DatabaseDifference
+	for _, databaseName := range unionKeys(sourceCollections, targetCollections) {
+		sourceDB, targetDB := sourceCollections[databaseName], targetCollections[databaseName]
+		for _, name := range unionKeys(sourceDB, targetDB) {
+			src, inSource := sourceDB[name]
+			tgt, inTarget := targetDB[name]
+			switch {
+			case !inTarget:
+				differences = append(differences, DatabaseDifference{Database: databaseName, Collection: name, Kind: collectionKind(src), Change: DatabaseChangeMissing})
+			case !inSource:
+				differences = append(differences, DatabaseDifference{Database: databaseName, Collection: name, Kind: collectionKind(tgt), Change: DatabaseChangeExtra})
+			default:
+				differences = append(differences, compareCollectionSchemas(databaseName, src, tgt)...)
+			}
+		}
+	}
+	return differences
+}
+
+func compareCollectionSchemas(databaseName string, src, tgt types.CollectionSchema) []DatabaseDifference {
+	changed := func(kind string, fields []string) DatabaseDifference {
+		return DatabaseDifference{Database: databaseName, Collection: src.Name, Kind: kind, Change: DatabaseChangeChanged, Fields: fields}
+	}
+
+	// A collection and a view, or a regular and a time series collection, share nothing else
+	if src.Type != tgt.Type {
+		return []DatabaseDifference{changed("options", []string{"type"})}
+	}
+	if src.Type == "view" {
+		fields := changedFields(map[string][2]interface{}{
+			"viewOn":    {src.ViewOn, tgt.ViewOn},
+			"pipeline":  {src.Pipeline, tgt.Pipeline},
+			"collation": {src.Options.Collation, tgt.Options.Collation},
+		})
+		if len(fields) > 0 {
+			return []DatabaseDifference{changed("view", fields)}
+		}
+		return nil
+	}
+
+	var differences []DatabaseDifference
+	want := normalizeCollectionSpec(types.CollectionSpec{CollectionOptions: src.Options})
+	have := normalizeCollectionSpec(types.CollectionSpec{CollectionOptions: tgt.Options})
+	if fields := changedFields(map[string][2]interface{}{
+		"validator":        {want.Validator, have.Validator},
+		"validationLevel":  {want.ValidationLevel, have.ValidationLevel},
+		"validationAction": {want.ValidationAction, have.ValidationAction},
+	}); len(fields) > 0 {
+		differences = append(differences, changed("validator", fields))
+	}
+	fields := CollectionImmutableChanges(&want, &have)
+	if !jsonEqual(want.ExpireAfterSeconds, have.ExpireAfterSeconds) {
+		fields = append(fields, "expireAfterSeconds")
+	}
+	if len(fields) > 0 {
+		sort.Strings(fields)
+		differences = append(differences, changed("options", fields))
+	}
+
+	sourceIndexes, targetIndexes := indexesByName(src.Indexes), indexesByName(tgt.Indexes)
+	for _, name := range unionKeys(sourceIndexes, targetIndexes) {
+		difference := DatabaseDifference{Database: databaseName, Collection: src.Name, Kind: "index", Index: name}
+		srcIndex, inSource := sourceIndexes[name]
+		tgtIndex, inTarget := targetIndexes[name]
+		switch {
+		case !inTarget:
+			difference.Change = DatabaseChangeMissing
+		case !inSource:
+			difference.Change = DatabaseChangeExtra
+		default:
+			if difference.Fields = indexChanges(srcIndex, tgtIndex); len(difference.Fields) == 0 {
+				continue
+			}
+			difference.Change = DatabaseChangeChanged
+		}
+		differences = append(differences, difference)
+	}
+	return differences
+}
+
+// indexChanges lists the index definition fields that differ
+func indexChanges(src, tgt types.IndexInfo) []string {
+	want := normalizeIndexSpec(indexManifestFromInfo("", "", "", src).Spec)
+	have := normalizeIndexSpec(indexManifestFromInfo("", "", "", tgt).Spec)
+	return changedFields(map[string][2]interface{}{
+		"keys":                    {want.Keys, have.Keys},
+		"unique":                  {want.Unique, have.Unique},
+		"sparse":                  {want.Sparse, have.Sparse},
+		"hidden":                  {want.Hidden, have.Hidden},
+		"partialFilterExpression": {want.PartialFilterExpression, have.PartialFilterExpression},
+		"expireAfterSeconds":      {want.ExpireAfterSeconds, have.ExpireAfterSeconds},
+		"collation":               {want.Collation, have.Collation},
+		"wildcardProjection":      {want.WildcardProjection, have.WildcardProjection},
+		"weights":                 {src.Options["weights"], tgt.Options["weights"]},
+	})
+}
+
+// changedFields returns the sorted names of the pairs whose values differ
+func changedFields(pairs map[string][2]interface{}) []string {
+	var fields []string
+	for name, pair := range pairs {
+		if !jsonEqual(pair[0], pair[1]) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// DatabaseSyncDocument generates an ApplyDocument that brings the collections and indexes
+// of the target in line with the source. Every source collection with a difference is
+// declared together with all its indexes, since apply deletes undeclared indexes on the
+// collections it manages. Views that differ are declared as View resources. Apply never
+// drops collections, so collections that exist only in the target are returned as skipped.
+func DatabaseSyncDocument(source *types.DatabaseSnapshot, differences []DatabaseDifference, projectName, clusterName string) (*types.ApplyDocument, []string) {
+	doc := &types.ApplyDocument{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindApplyDocument,
+		Metadata: types.MetadataConfig{
+			Name: resourceName(nil, "sync", clusterName),
+			Annotations: map[string]string{
+				"description": "Generated by matlas database compare",
+			},
+		},
+		Resources: []types.ResourceManifest{},
+	}
+
+	sourceCollections := snapshotCollections(source)
+	names := make(map[string]bool)
+	declared := make(map[string]bool)
+	var skipped []string
+	for _, difference := range differences {
+		namespace := difference.Database + "." + difference.Collection
+		collection, ok := sourceCollections[difference.Database][difference.Collection]
+		switch {
+		case !ok:
+			skipped = append(skipped, fmt.Sprintf("%s exists only in the target; apply never drops collections", namespace))
+			continue
+		case declared[namespace]:
+			continue
+		}
+		declared[namespace] = true
+
+		if collection.Type == types.ViewTypeStandard {
+			pipeline, err := database.PipelineValues(collection.Pipeline)
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("%s is a view whose pipeline cannot be generated: %v", namespace, err))
+				continue
+			}
+			doc.Resources = append(doc.Resources, types.ResourceManifest{
+				APIVersion: types.APIVersionV1,
+				Kind:       types.KindView,
+				Metadata:   types.ResourceMetadata{Name: resourceName(names, difference.Database, collection.Name)},
+				Spec: types.ViewSpec{
+					ProjectName:  projectName,
+					ClusterName:  clusterName,
+					DatabaseName: difference.Database,
+					Name:         collection.Name,
+					ViewOn:       collection.ViewOn,
+					Pipeline:     pipeline,
+					Collation:    collection.Options.Collation,
+				},
+			})
+			continue
+		}
+
+		doc.Resources = append(doc.Resources, types.ResourceManifest{
+			APIVersion: types.APIVersionV1,
+			Kind:       types.KindCollection,
+			Metadata:   types.ResourceMetadata{Name: resourceName(names, difference.Database, collection.Name)},
+			Spec: types.CollectionSpec{
+				ProjectName:       projectName,
+				ClusterName:       clusterName,
+				DatabaseName:      difference.Database,
+				Name:              collection.Name,
+				CollectionOptions: creatableCollectionOptions(collection.Options),
+			},
+		})
+		for _, index := range collection.Indexes {
+			if index.Name == "_id_" {
+				continue
+			}
+			manifest := indexManifestFromInfo(clusterName, difference.Database, collection.Name, index)
+			manifest.Spec.ProjectName = projectName
+			manifest.Spec.Keys = creatableIndexKeys(index)
+			doc.Resources = append(doc.Resources, types.ResourceManifest{
+				APIVersion: types.APIVersionV1,
+				Kind:       types.KindIndex,
+				Metadata:   types.ResourceMetadata{Name: resourceName(names, difference.Database, collection.Name, index.Name)},
+				Spec:       manifest.Spec,
+			})
+		}
+	}
+	return doc, skipped
+}
+
+// creatableCollectionOptions drops the bucket sizes MongoDB reports beside a time series
+// granularity, which cannot be given together when creating the collection
+func creatableCollectionOptions(opts types.CollectionOptions) types.CollectionOptions {
+	if ts := opts.TimeSeries; ts != nil && ts.Granularity != "" {
+		trimmed := *ts
+		trimmed.BucketMaxSpanSeconds, trimmed.BucketRoundingSeconds = nil, nil
+		opts.TimeSeries = &trimmed
+	}
+	return opts
+}
+
+// creatableIndexKeys replaces the _fts and _ftsx keys listIndexes reports for a text index
+// with the text fields of its weights, in field order. Weights other than 1 are not kept.
+func creatableIndexKeys(index types.IndexInfo) types.IndexKeys {
+	keys := make(types.IndexKeys, 0, len(index.Keys))
+	for _, key := range index.Keys {
+		switch key.Field {
+		case "_fts":
+			weights := documentFields(index.Options["weights"])
+			fields := make([]string, 0, len(weights))
+			for field := range weights {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				keys = append(keys, types.IndexKey{Field: field, Type: types.IndexTypeText})
+			}
+		case "_ftsx":
+		default:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// documentFields decodes a BSON document of any map type, such as bson.M
+func documentFields(value interface{}) map[string]interface{} {
+	var fields map[string]interface{}
+	if data, err := json.Marshal(value); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// resourceName builds a unique resource name from parts, keeping the lowercase letters,
+// digits, hyphens and underscores resource names allow
+func resourceName(used map[string]bool, parts ...string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteByte('-')
+		}
+		for _, r := range strings.ToLower(part) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte('-')
+			}
+		}
+	}
+	name := b.String()
+	if len(name) > 64 {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(name))
+		name = fmt.Sprintf("%s-%08x", name[:55], hash.Sum32())
+	}
+	if used == nil {
+		return name
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+func collectionKind(collection types.CollectionSchema) string {
+	if collection.Type == "view" {
+		return "view"
+	}
+	return "collection"
+}
+
+func snapshotCollections(snapshot *types.DatabaseSnapshot) map[string]map[string]types.CollectionSchema {
+	collections := make(map[string]map[string]types.CollectionSchema)
+	if snapshot == nil {
+		return collections
+	}
+	for _, database := range snapshot.Databases {
+		byName := make(map[string]types.CollectionSchema, len(database.Collections))
+		for _, collection := range database.Collections {
+			byName[collection.Name] = collection
+		}
+		collections[database.Name] = byName
+	}
+	return collections
+}
+
+func indexesByName(indexes []types.IndexInfo) map[string]types.IndexInfo {
+	byName := make(map[string]types.IndexInfo, len(indexes))
+	for _, index := range indexes {
+		if index.Name != "_id_" {
+			byName[index.Name] = index
+		}
+	}
+	return byName
+}
+
+// unionKeys returns the keys of both maps, sorted
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,166 @@
+package apply
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func compareSnapshots() (*types.DatabaseSnapshot, *types.DatabaseSnapshot) {
+	validator := map[string]interface{}{"$jsonSchema": map[string]interface{}{"required": []interface{}{"total"}}}
+	source := &types.DatabaseSnapshot{Databases: []types.DatabaseSchema{{
+		Name: "shop",
+		Collections: []types.CollectionSchema{
+			{Name: "active_orders", Type: "view", ViewOn: "orders", Pipeline: []interface{}{map[string]interface{}{"$match": map[string]interface{}{"status": "open"}}}},
+			{Name: "customers", Type: "collection", Indexes: []types.IndexInfo{
+				{Name: "_id_", Keys: types.IndexKeys{{Field: "_id", Order: 1}}},
+				{Name: "bio_text", Keys: types.IndexKeys{{Field: "_fts", Type: "text"}, {Field: "_ftsx", Order: 1}}, Options: map[string]interface{}{"weights": map[string]interface{}{"name": 1, "bio": 1}}},
+			}},
+			{Name: "orders", Type: "collection", Options: types.CollectionOptions{Validator: validator}, Indexes: []types.IndexInfo{
+				{Name: "_id_", Keys: types.IndexKeys{{Field: "_id", Order: 1}}},
+				{Name: "createdAt_1", Keys: types.IndexKeys{{Field: "createdAt", Order: 1}}, ExpireAfterSeconds: int32Ptr(3600)},
+				{Name: "customerId_1", Keys: types.IndexKeys{{Field: "customerId", Order: 1}}, Unique: true},
+			}},
+			{Name: "events", Type: "timeseries", Options: types.CollectionOptions{TimeSeries: &types.TimeSeriesOptions{TimeField: "ts", Granularity: "minutes", BucketMaxSpanSeconds: int64Ptr(86400)}}},
+		},
+	}}}
+	target := &types.DatabaseSnapshot{Databases: []types.DatabaseSchema{{
+		Name: "shop",
+		Collections: []types.CollectionSchema{
+			{Name: "active_orders", Type: "view", ViewOn: "orders", Pipeline: []interface{}{map[string]interface{}{"$match": map[string]interface{}{"status": "open"}}}},
+			{Name: "customers", Type: "collection", Indexes: []types.IndexInfo{
+				{Name: "_id_", Keys: types.IndexKeys{{Field: "_id", Order: 1}}},
+				{Name: "bio_text", Keys: types.IndexKeys{{Field: "_fts", Type: "text"}, {Field: "_ftsx", Order: 1}}, Options: map[string]interface{}{"weights": map[string]interface{}{"bio": 1, "name": 1}}},
+			}},
+			{Name: "legacy", Type: "collection"},
+			{Name: "orders", Type: "collection", Options: types.CollectionOptions{ValidationLevel: types.ValidationLevelModerate}, Indexes: []types.IndexInfo{
+				{Name: "_id_", Keys: types.IndexKeys{{Field: "_id", Order: 1}}},
+				{Name: "customerId_1", Keys: types.IndexKeys{{Field: "customerId", Order: 1}}},
+				{Name: "status_1", Keys: types.IndexKeys{{Field: "status", Order: 1}}},
+			}},
+		},
+	}}}
+	return source, target
+}
+
+func TestCompareDatabaseSnapshots(t *testing.T) {
+	source, target := compareSnapshots()
+
+	got := CompareDatabaseSnapshots(source, target)
+	want := []DatabaseDifference{
+		{Database: "shop", Collection: "events", Kind: "collection", Change: DatabaseChangeMissing},
+		{Database: "shop", Collection: "legacy", Kind: "collection", Change: DatabaseChangeExtra},
+		{Database: "shop", Collection: "orders", Kind: "validator", Change: DatabaseChangeChanged, Fields: []string{"validationLevel", "validator"}},
+		{Database: "shop", Collection: "orders", Kind: "index", Index: "createdAt_1", Change: DatabaseChangeMissing},
+		{Database: "shop", Collection: "orders", Kind: "index", Index: "customerId_1", Change: DatabaseChangeChanged, Fields: []string{"unique"}},
+		{Database: "shop", Collection: "orders", Kind: "index", Index: "status_1", Change: DatabaseChangeExtra},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("differences = %+v, want %+v", got, want)
+	}
+}
+
+func TestCompareDatabaseSnapshots_ViewsAndTypes(t *testing.T) {
+	source := &types.DatabaseSnapshot{Databases: []types.DatabaseSchema{{Name: "app", Collections: []types.CollectionSchema{
+		{Name: "recent", Type: "view", ViewOn: "events"},
+		{Name: "metrics", Type: "timeseries", Options: types.CollectionOptions{TimeSeries: &types.TimeSeriesOptions{TimeField: "ts"}}},
+	}}}}
+	target := &types.DatabaseSnapshot{Databases: []types.DatabaseSchema{{Name: "app", Collections: []types.CollectionSchema{
+		{Name: "recent", Type: "view", ViewOn: "events_v2"},
+		{Name: "metrics", Type: "collection"},
+	}}}}
+
+	got := CompareDatabaseSnapshots(source, target)
+	want := []DatabaseDifference{
+		{Database: "app", Collection: "metrics", Kind: "options", Change: DatabaseChangeChanged, Fields: []string{"type"}},
+		{Database: "app", Collection: "recent", Kind: "view", Change: DatabaseChangeChanged, Fields: []string{"viewOn"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("differences = %+v, want %+v", got, want)
+	}
+
+	if got := CompareDatabaseSnapshots(source, source); len(got) != 0 {
+		t.Fatalf("a snapshot compared with itself has differences: %+v", got)
+	}
+}
+
+func TestDatabaseSyncDocument(t *testing.T) {
+	source, target := compareSnapshots()
+	differences := CompareDatabaseSnapshots(source, target)
+	differences = append(differences, DatabaseDifference{Database: "shop", Collection: "active_orders", Kind: "view", Change: DatabaseChangeChanged, Fields: []string{"pipeline"}})
+
+	doc, skipped := DatabaseSyncDocument(source, differences, "My Project", "app-staging")
+
+	var names []string
+	for _, resource := range doc.Resources {
+		names = append(names, string(resource.Kind)+"/"+resource.Metadata.Name)
+	}
+	wantNames := []string{
+		"Collection/shop-events",
+		"Collection/shop-orders",
+		"Index/shop-orders-createdat_1",
+		"Index/shop-orders-customerid_1",
+		"View/shop-active_orders",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("resources = %v, want %v", names, wantNames)
+	}
+	if len(skipped) != 1 {
+		t.Fatalf("skipped = %v, want the extra collection", skipped)
+	}
+
+	view := doc.Resources[4].Spec.(types.ViewSpec)
+	if view.ViewOn != "orders" || len(view.Pipeline) != 1 || view.ProjectName != "My Project" {
+		t.Errorf("Expected the view on orders with its pipeline, got %+v", view)
+	}
+
+	events := doc.Resources[0].Spec.(types.CollectionSpec)
+	if events.TimeSeries.BucketMaxSpanSeconds != nil {
+		t.Errorf("bucket spans reported beside a granularity must not be generated")
+	}
+
+	// The generated document is valid once written and loaded back
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded types.ApplyDocument
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if result := ValidateApplyDocument(&loaded, nil); !result.Valid {
+		t.Fatalf("generated document is invalid: %+v", result.Errors)
+	}
+}
+
+func TestCreatableIndexKeys_Text(t *testing.T) {
+	index := types.IndexInfo{
+		Keys:    types.IndexKeys{{Field: "tenant", Order: 1}, {Field: "_fts", Type: "text"}, {Field: "_ftsx", Order: 1}},
+		Options: map[string]interface{}{"weights": map[string]interface{}{"title": 10, "body": 1}},
+	}
+	want := types.IndexKeys{{Field: "tenant", Order: 1}, {Field: "body", Type: "text"}, {Field: "title", Type: "text"}}
+	if got := creatableIndexKeys(index); !reflect.DeepEqual(got, want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	used := make(map[string]bool)
+	if got := resourceName(used, "Shop", "order.items"); got != "shop-order-items" {
+		t.Errorf("resourceName = %q", got)
+	}
+	if got := resourceName(used, "shop", "order-items"); got != "shop-order-items-2" {
+		t.Errorf("duplicate resourceName = %q", got)
+	}
+	long := resourceName(nil, "analytics", "a_very_long_collection_name_for_events", "a_very_long_index_name_1")
+	if len(long) > 64 {
+		t.Errorf("resourceName is %d characters long", len(long))
+	}
+}
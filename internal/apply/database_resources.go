@@ -0,0 +1,471 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// DatabaseClient manages the collections, indexes and views of the clusters in a project
+type DatabaseClient interface {
+	ListCollections(ctx context.Context, clusterName, databaseName string) ([]types.CollectionInfo, error)
+	CreateCollection(ctx context.Context, clusterName, databaseName, collectionName string, opts *types.CollectionOptions) error
+	ModifyCollection(ctx context.Context, clusterName, databaseName, collectionName string, opts *types.CollectionOptions) error
+	ListIndexes(ctx context.Context, clusterName, databaseName, collectionName string) ([]types.IndexInfo, error)
+	CreateIndex(ctx context.Context, clusterName, databaseName, collectionName string, keys []types.IndexKey, opts *types.IndexOptions) (string, error)
+	ModifyIndex(ctx context.Context, clusterName, databaseName, collectionName, indexName string, hidden *bool, expireAfterSeconds *int32) error
+	DropIndex(ctx context.Context, clusterName, databaseName, collectionName, indexName string) error
+	CreateView(ctx context.Context, clusterName, databaseName, viewName, viewOn string, pipeline bson.A, collation *types.IndexCollation) error
+	ModifyView(ctx context.Context, clusterName, databaseName, viewName, viewOn string, pipeline bson.A) error
+	GetMaterializedView(ctx context.Context, clusterName, databaseName, viewName string) (*database.MaterializedView, error)
+	RefreshMaterializedView(ctx context.Context, clusterName, databaseName string, view *database.MaterializedView) error
+}
+
+// ClusterDatabaseClient implements DatabaseClient with the database service, connecting to
+// each cluster through the temporary users of a ClusterConnector
+type ClusterDatabaseClient struct {
+	service   *database.Service
+	connector *database.ClusterConnector
+}
+
+// NewClusterDatabaseClient creates a DatabaseClient for the clusters reached by connector
+func NewClusterDatabaseClient(service *database.Service, connector *database.ClusterConnector) *ClusterDatabaseClient {
+	return &ClusterDatabaseClient{service: service, connector: connector}
+}
+
+// ListCollections lists the collections of a database
+func (c *ClusterDatabaseClient) ListCollections(ctx context.Context, clusterName, databaseName string) ([]types.CollectionInfo, error) {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return c.service.ListCollections(ctx, connInfo, databaseName)
+}
+
+// CreateCollection creates a collection
+func (c *ClusterDatabaseClient) CreateCollection(ctx context.Context, clusterName, databaseName, collectionName string, opts *types.CollectionOptions) error {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	return c.service.CreateCollectionWithOptions(ctx, connInfo, databaseName, collectionName, opts)
+}
+
+// ModifyCollection changes the validator and expiry of a collection
+func (c *ClusterDatabaseClient) ModifyCollection(ctx context.Context, clusterName, databaseName, collectionName string, opts *types.CollectionOptions) error {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	return c.service.ModifyCollection(ctx, connInfo, databaseName, collectionName, opts)
+}
+
+// ListIndexes lists the indexes of a collection
+func (c *ClusterDatabaseClient) ListIndexes(ctx context.Context, clusterName, databaseName, collectionName string) ([]types.IndexInfo, error) {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return c.service.ListIndexes(ctx, connInfo, databaseName, collectionName)
+}
+
+// CreateIndex creates an index
+func (c *ClusterDatabaseClient) CreateIndex(ctx context.Context, clusterName, databaseName, collectionName string, keys []types.IndexKey, opts *types.IndexOptions) (string, error) {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return "", err
+	}
+	return c.service.CreateIndex(ctx, connInfo, databaseName, collectionName, keys, opts)
+}
+
+// ModifyIndex hides or unhides an index or changes its TTL
+func (c *ClusterDatabaseClient) ModifyIndex(ctx context.Context, clusterName, databaseName, collectionName, indexName string, hidden *bool, expireAfterSeconds *int32) error {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	return c.service.ModifyIndex(ctx, connInfo, databaseName, collectionName, indexName, hidden, expireAfterSeconds)
+}
+
+// DropIndex drops an index
+func (c *ClusterDatabaseClient) DropIndex(ctx context.Context, clusterName, databaseName, collectionName, indexName string) error {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	return c.service.DropIndex(ctx, connInfo, databaseName, collectionName, indexName)
+}
+
+// CreateView creates a view
+func (c *ClusterDatabaseClient) CreateView(ctx context.Context, clusterName, databaseName, viewName, viewOn string, pipeline bson.A, collation *types.IndexCollation) error {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	return c.service.CreateView(ctx, connInfo, databaseName, viewName, viewOn, pipeline, collation)
+}
+
+// ModifyView replaces the source and pipeline of a view
+func (c *ClusterDatabaseClient) ModifyView(ctx context.Context, clusterName, databaseName, viewName, viewOn string, pipeline bson.A) error {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	return c.service.ModifyView(ctx, connInfo, databaseName, viewName, viewOn, pipeline)
+}
+
+// GetMaterializedView returns the definition of a materialized view, or nil when it isn't defined
+func (c *ClusterDatabaseClient) GetMaterializedView(ctx context.Context, clusterName, databaseName, viewName string) (*database.MaterializedView, error) {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return c.service.GetMaterializedView(ctx, connInfo, databaseName, viewName)
+}
+
+// RefreshMaterializedView runs the pipeline of a materialized view and saves its definition
+func (c *ClusterDatabaseClient) RefreshMaterializedView(ctx context.Context, clusterName, databaseName string, view *database.MaterializedView) error {
+	connInfo, err := c.connector.Connect(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	return c.service.RefreshMaterializedView(ctx, connInfo, databaseName, view)
+}
+
+// SetDatabaseClient configures the client used to discover Collection, Index, View and
+// MaterializedView resources
+func (d *AtlasStateDiscovery) SetDatabaseClient(client DatabaseClient) {
+	d.databaseClient = client
+}
+
+// DiscoverDatabaseResources records in current the collections, indexes and views that the
+// desired state manages. Only declared collections and views are discovered, so others are
+// never dropped. Every index of a collection targeted by an Index resource is discovered
+// except _id_, so undeclared indexes on that collection are planned for deletion. Clusters
+// that don't exist yet are skipped.
+func (d *AtlasStateDiscovery) DiscoverDatabaseResources(ctx context.Context, desired, current *ProjectState) error {
+	if desired == nil || current == nil || !desired.HasDatabaseResources() {
+		return nil
+	}
+	if d.databaseClient == nil {
+		return fmt.Errorf("a database client is required to discover Collection, Index, View and MaterializedView resources")
+	}
+	return discoverDatabaseResources(ctx, d.databaseClient, desired, current)
+}
+
+func discoverDatabaseResources(ctx context.Context, client DatabaseClient, desired, current *ProjectState) error {
+	clusters := make(map[string]bool, len(current.Clusters))
+	for _, cluster := range current.Clusters {
+		clusters[cluster.Metadata.Name] = true
+	}
+
+	// Collections per database, keyed by cluster/database
+	listed := make(map[string]map[string]types.CollectionInfo)
+	listCollections := func(clusterName, databaseName string) (map[string]types.CollectionInfo, error) {
+		key := clusterName + "/" + databaseName
+		if collections, ok := listed[key]; ok {
+			return collections, nil
+		}
+		infos, err := client.ListCollections(ctx, clusterName, databaseName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collections of %s on cluster %s: %w", databaseName, clusterName, err)
+		}
+		collections := make(map[string]types.CollectionInfo, len(infos))
+		for _, info := range infos {
+			collections[info.Name] = info
+		}
+		listed[key] = collections
+		return collections, nil
+	}
+
+	for _, collection := range desired.Collections {
+		spec := collection.Spec
+		if !clusters[spec.ClusterName] {
+			continue
+		}
+		collections, err := listCollections(spec.ClusterName, spec.DatabaseName)
+		if err != nil {
+			return err
+		}
+		info, ok := collections[spec.Name]
+		if !ok {
+			continue
+		}
+		opts, err := types.ParseCollectionOptions(info.Options)
+		if err != nil {
+			return fmt.Errorf("collection %s.%s: %w", spec.DatabaseName, spec.Name, err)
+		}
+		current.Collections = append(current.Collections, types.CollectionManifest{
+			APIVersion: types.APIVersionV1,
+			Kind:       types.KindCollection,
+			Metadata:   types.ResourceMetadata{Name: collection.Metadata.Name},
+			Spec: types.CollectionSpec{
+				ClusterName:       spec.ClusterName,
+				DatabaseName:      spec.DatabaseName,
+				Name:              spec.Name,
+				CollectionOptions: *opts,
+			},
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, index := range desired.Indexes {
+		spec := index.Spec
+		key := collectionKey(spec.ClusterName, spec.DatabaseName, spec.CollectionName)
+		if seen[key] || !clusters[spec.ClusterName] {
+			continue
+		}
+		seen[key] = true
+		collections, err := listCollections(spec.ClusterName, spec.DatabaseName)
+		if err != nil {
+			return err
+		}
+		if _, ok := collections[spec.CollectionName]; !ok {
+			continue
+		}
+		infos, err := client.ListIndexes(ctx, spec.ClusterName, spec.DatabaseName, spec.CollectionName)
+		if err != nil {
+			return fmt.Errorf("failed to list indexes of %s.%s on cluster %s: %w", spec.DatabaseName, spec.CollectionName, spec.ClusterName, err)
+		}
+		for _, info := range infos {
+			if info.Name == "_id_" {
+				continue
+			}
+			current.Indexes = append(current.Indexes, indexManifestFromInfo(spec.ClusterName, spec.DatabaseName, spec.CollectionName, info))
+		}
+	}
+
+	for _, view := range desired.Views {
+		spec := view.Spec
+		if !clusters[spec.ClusterName] {
+			continue
+		}
+		collections, err := listCollections(spec.ClusterName, spec.DatabaseName)
+		if err != nil {
+			return err
+		}
+		info, ok := collections[spec.Name]
+		if !ok || !info.IsView() {
+			continue
+		}
+		discovered, err := database.ViewInfoFromCollection(info)
+		if err != nil {
+			return fmt.Errorf("view %s.%s: %w", spec.DatabaseName, spec.Name, err)
+		}
+		current.Views = append(current.Views, types.ViewManifest{
+			APIVersion: types.APIVersionV1,
+			Kind:       types.KindView,
+			Metadata:   types.ResourceMetadata{Name: view.Metadata.Name},
+			Spec: types.ViewSpec{
+				ClusterName:  spec.ClusterName,
+				DatabaseName: spec.DatabaseName,
+				Name:         spec.Name,
+				ViewOn:       discovered.Source,
+				Pipeline:     discovered.Pipeline,
+				Collation:    discovered.Collation,
+			},
+		})
+	}
+
+	for _, view := range desired.MaterializedViews {
+		spec := view.Spec
+		if !clusters[spec.ClusterName] {
+			continue
+		}
+		definition, err := client.GetMaterializedView(ctx, spec.ClusterName, spec.DatabaseName, spec.Name)
+		if err != nil {
+			return fmt.Errorf("failed to read materialized view %s.%s on cluster %s: %w", spec.DatabaseName, spec.Name, spec.ClusterName, err)
+		}
+		if definition == nil {
+			continue
+		}
+		discovered, err := definition.Info()
+		if err != nil {
+			return fmt.Errorf("materialized view %s.%s: %w", spec.DatabaseName, spec.Name, err)
+		}
+		current.MaterializedViews = append(current.MaterializedViews, types.MaterializedViewManifest{
+			APIVersion: types.APIVersionV1,
+			Kind:       types.KindMaterializedView,
+			Metadata:   types.ResourceMetadata{Name: view.Metadata.Name},
+			Spec: types.MaterializedViewSpec{
+				ClusterName:  spec.ClusterName,
+				DatabaseName: spec.DatabaseName,
+				Name:         spec.Name,
+				Source:       discovered.Source,
+				Pipeline:     discovered.Pipeline,
+				Merge:        discovered.Merge,
+			},
+		})
+	}
+	return nil
+}
+
+// HasDatabaseResources reports whether the state declares collections, indexes or views,
+// which are managed through a connection to their cluster
+func (s *ProjectState) HasDatabaseResources() bool {
+	return len(s.Collections) > 0 || len(s.Indexes) > 0 || len(s.Views) > 0 || len(s.MaterializedViews) > 0
+}
+
+// indexManifestFromInfo converts an index returned by listIndexes, named after the index
+func indexManifestFromInfo(clusterName, databaseName, collectionName string, info types.IndexInfo) types.IndexManifest {
+	return types.IndexManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindIndex,
+		Metadata:   types.ResourceMetadata{Name: info.Name},
+		Spec: types.IndexSpec{
+			ClusterName:    clusterName,
+			DatabaseName:   databaseName,
+			CollectionName: collectionName,
+			Keys:           info.Keys,
+			IndexOptions: types.IndexOptions{
+				Name:                    info.Name,
+				Unique:                  info.Unique,
+				Sparse:                  info.Sparse,
+				Hidden:                  info.Hidden,
+				PartialFilterExpression: info.PartialFilterExpression,
+				ExpireAfterSeconds:      info.ExpireAfterSeconds,
+				Collation:               info.Collation,
+				WildcardProjection:      info.WildcardProjection,
+			},
+		},
+	}
+}
+
+func collectionKey(clusterName, databaseName, collectionName string) string {
+	return fmt.Sprintf("%s/%s/%s", clusterName, databaseName, collectionName)
+}
+
+// timeSeriesBucketSpans are the bucketMaxSpanSeconds MongoDB uses for each granularity
+var timeSeriesBucketSpans = map[string]int64{"seconds": 3600, "minutes": 86400, "hours": 2592000}
+
+// normalizeCollectionSpec fills in the defaults MongoDB reports for omitted options and drops
+// fields that don't describe the collection itself
+func normalizeCollectionSpec(spec types.CollectionSpec) types.CollectionSpec {
+	spec.ProjectName = ""
+	spec.DependsOn = nil
+	if spec.ValidationLevel == "" {
+		spec.ValidationLevel = types.ValidationLevelStrict
+	}
+	if spec.ValidationAction == "" {
+		spec.ValidationAction = types.ValidationActionError
+	}
+	if spec.TimeSeries != nil {
+		ts := *spec.TimeSeries
+		if ts.Granularity == "" && ts.BucketMaxSpanSeconds == nil {
+			ts.Granularity = "seconds"
+		}
+		if ts.Granularity != "" {
+			span := timeSeriesBucketSpans[ts.Granularity]
+			ts.BucketMaxSpanSeconds = &span
+			ts.BucketRoundingSeconds = nil
+		}
+		spec.TimeSeries = &ts
+	}
+	if spec.ClusteredIndex != nil && spec.ClusteredIndex.Name == "" {
+		spec.ClusteredIndex = &types.ClusteredIndexOptions{Name: "_id_"}
+	}
+	return spec
+}
+
+// normalizeIndexSpec names the index and replaces text keys with the _fts and _ftsx keys
+// listIndexes reports in their place. The text fields themselves are not compared.
+func normalizeIndexSpec(spec types.IndexSpec) types.IndexSpec {
+	spec.Name = spec.IndexName()
+	spec.ProjectName = ""
+	spec.DependsOn = nil
+	// background and commitQuorum are build options listIndexes doesn't report
+	spec.Background = false
+	spec.CommitQuorum = ""
+
+	keys := make(types.IndexKeys, 0, len(spec.Keys))
+	text := false
+	for _, key := range spec.Keys {
+		if key.Type == types.IndexTypeText || key.Field == "_fts" || key.Field == "_ftsx" {
+			if !text {
+				keys = append(keys, types.IndexKey{Field: "_fts", Type: types.IndexTypeText}, types.IndexKey{Field: "_ftsx", Order: 1})
+				text = true
+			}
+			continue
+		}
+		keys = append(keys, key)
+	}
+	spec.Keys = keys
+	return spec
+}
+
+// alignCollation returns current restricted to the collation fields desired sets; MongoDB
+// reports every field of a collation
+func alignCollation(desired, current *types.IndexCollation) *types.IndexCollation {
+	if desired == nil || current == nil {
+		return current
+	}
+	aligned := types.IndexCollation{Locale: current.Locale}
+	if desired.CaseLevel {
+		aligned.CaseLevel = current.CaseLevel
+	}
+	if desired.CaseFirst != "" {
+		aligned.CaseFirst = current.CaseFirst
+	}
+	if desired.Strength != 0 {
+		aligned.Strength = current.Strength
+	}
+	if desired.NumericOrdering {
+		aligned.NumericOrdering = current.NumericOrdering
+	}
+	if desired.Alternate != "" {
+		aligned.Alternate = current.Alternate
+	}
+	if desired.MaxVariable != "" {
+		aligned.MaxVariable = current.MaxVariable
+	}
+	if desired.Backwards {
+		aligned.Backwards = current.Backwards
+	}
+	return &aligned
+}
+
+// CollectionImmutableChanges lists the options that differ between two collections and can
+// only be set when a collection is created
+func CollectionImmutableChanges(desired, current *types.CollectionSpec) []string {
+	want, have := normalizeCollectionSpec(*desired), normalizeCollectionSpec(*current)
+	have.Collation = alignCollation(want.Collation, have.Collation)
+	fields := []struct {
+		name       string
+		want, have interface{}
+	}{
+		{"capped", []interface{}{want.Capped, want.Size, want.Max}, []interface{}{have.Capped, have.Size, have.Max}},
+		{"timeseries", want.TimeSeries, have.TimeSeries},
+		{"clusteredIndex", want.ClusteredIndex, have.ClusteredIndex},
+		{"collation", want.Collation, have.Collation},
+	}
+	var changed []string
+	for _, field := range fields {
+		if !jsonEqual(field.want, field.have) {
+			changed = append(changed, field.name)
+		}
+	}
+	return changed
+}
+
+// IndexRequiresRebuild reports whether an index has to be dropped and recreated to match
+// desired; only hidden and expireAfterSeconds can be changed in place
+func IndexRequiresRebuild(desired, current *types.IndexSpec) bool {
+	want, have := normalizeIndexSpec(*desired), normalizeIndexSpec(*current)
+	have.Collation = alignCollation(want.Collation, have.Collation)
+	want.Hidden, have.Hidden = false, false
+	if (want.ExpireAfterSeconds == nil) == (have.ExpireAfterSeconds == nil) {
+		want.ExpireAfterSeconds, have.ExpireAfterSeconds = nil, nil
+	}
+	return !jsonEqual(want, have)
+}
+
+func jsonEqual(a, b interface{}) bool {
+	left, err1 := json.Marshal(a)
+	right, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && string(left) == string(right)
+}
@@ -0,0 +1,263 @@
+package apply
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+type fakeDatabaseClient struct {
+	collections  map[string][]types.CollectionInfo
+	indexes      map[string][]types.IndexInfo
+	materialized map[string]*database.MaterializedView
+	listed       []string
+	refreshed    []*database.MaterializedView
+}
+
+func (f *fakeDatabaseClient) ListCollections(ctx context.Context, clusterName, databaseName string) ([]types.CollectionInfo, error) {
+	f.listed = append(f.listed, clusterName+"/"+databaseName)
+	return f.collections[clusterName+"/"+databaseName], nil
+}
+
+func (f *fakeDatabaseClient) CreateCollection(ctx context.Context, clusterName, databaseName, collectionName string, opts *types.CollectionOptions) error {
+	return nil
+}
+
+func (f *fakeDatabaseClient) ModifyCollection(ctx context.Context, clusterName, databaseName, collectionName string, opts *types.CollectionOptions) error {
+	return nil
+}
+
+func (f *fakeDatabaseClient) ListIndexes(ctx context.Context, clusterName, databaseName, collectionName string) ([]types.IndexInfo, error) {
+	return f.indexes[collectionKey(clusterName, databaseName, collectionName)], nil
+}
+
+func (f *fakeDatabaseClient) CreateIndex(ctx context.Context, clusterName, databaseName, collectionName string, keys []types.IndexKey, opts *types.IndexOptions) (string, error) {
+	return opts.Name, nil
+}
+
+func (f *fakeDatabaseClient) ModifyIndex(ctx context.Context, clusterName, databaseName, collectionName, indexName string, hidden *bool, expireAfterSeconds *int32) error {
+	return nil
+}
+
+func (f *fakeDatabaseClient) DropIndex(ctx context.Context, clusterName, databaseName, collectionName, indexName string) error {
+	return nil
+}
+
+func (f *fakeDatabaseClient) CreateView(ctx context.Context, clusterName, databaseName, viewName, viewOn string, pipeline bson.A, collation *types.IndexCollation) error {
+	return nil
+}
+
+func (f *fakeDatabaseClient) ModifyView(ctx context.Context, clusterName, databaseName, viewName, viewOn string, pipeline bson.A) error {
+	return nil
+}
+
+func (f *fakeDatabaseClient) GetMaterializedView(ctx context.Context, clusterName, databaseName, viewName string) (*database.MaterializedView, error) {
+	return f.materialized[collectionKey(clusterName, databaseName, viewName)], nil
+}
+
+func (f *fakeDatabaseClient) RefreshMaterializedView(ctx context.Context, clusterName, databaseName string, view *database.MaterializedView) error {
+	f.refreshed = append(f.refreshed, view)
+	return nil
+}
+
+func collectionManifest(name, cluster, database, collection string, opts types.CollectionOptions) types.CollectionManifest {
+	return types.CollectionManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindCollection,
+		Metadata:   types.ResourceMetadata{Name: name},
+		Spec: types.CollectionSpec{
+			ClusterName: cluster, DatabaseName: database, Name: collection, CollectionOptions: opts,
+		},
+	}
+}
+
+func indexManifest(name, cluster, database, collection string, keys types.IndexKeys, opts types.IndexOptions) types.IndexManifest {
+	return types.IndexManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindIndex,
+		Metadata:   types.ResourceMetadata{Name: name},
+		Spec: types.IndexSpec{
+			ClusterName: cluster, DatabaseName: database, CollectionName: collection, Keys: keys, IndexOptions: opts,
+		},
+	}
+}
+
+func TestDiscoverDatabaseResources(t *testing.T) {
+	client := &fakeDatabaseClient{
+		collections: map[string][]types.CollectionInfo{
+			"cluster0/app": {
+				{Name: "orders", Options: map[string]interface{}{"validationLevel": "moderate"}},
+				{Name: "events"},
+				{Name: "unmanaged"},
+			},
+		},
+		indexes: map[string][]types.IndexInfo{
+			"cluster0/app/events": {
+				{Name: "_id_", Keys: types.IndexKeys{{Field: "_id", Order: 1}}},
+				{Name: "ts_-1", Keys: types.IndexKeys{{Field: "ts", Order: -1}}, Hidden: true},
+			},
+		},
+	}
+	desired := &ProjectState{
+		Collections: []types.CollectionManifest{
+			collectionManifest("orders-collection", "cluster0", "app", "orders", types.CollectionOptions{}),
+			collectionManifest("missing", "cluster0", "app", "missing", types.CollectionOptions{}),
+			collectionManifest("pending", "cluster1", "app", "orders", types.CollectionOptions{}),
+		},
+		Indexes: []types.IndexManifest{
+			indexManifest("events-ts", "cluster0", "app", "events", types.IndexKeys{{Field: "ts", Order: -1}}, types.IndexOptions{}),
+		},
+	}
+	current := &ProjectState{Clusters: []types.ClusterManifest{{Metadata: types.ResourceMetadata{Name: "cluster0"}}}}
+
+	if err := discoverDatabaseResources(context.Background(), client, desired, current); err != nil {
+		t.Fatalf("discoverDatabaseResources failed: %v", err)
+	}
+	if len(current.Collections) != 1 || current.Collections[0].Metadata.Name != "orders-collection" ||
+		current.Collections[0].Spec.ValidationLevel != types.ValidationLevelModerate {
+		t.Errorf("Expected only the declared existing collection with its options, got %+v", current.Collections)
+	}
+	if len(current.Indexes) != 1 || current.Indexes[0].Metadata.Name != "ts_-1" || !current.Indexes[0].Spec.Hidden {
+		t.Errorf("Expected every index but _id_ to be discovered, got %+v", current.Indexes)
+	}
+	if len(client.listed) != 1 {
+		t.Errorf("Expected one listCollections call for cluster0/app, got %v", client.listed)
+	}
+
+	discovery := &AtlasStateDiscovery{}
+	if err := discovery.DiscoverDatabaseResources(context.Background(), desired, &ProjectState{}); err == nil {
+		t.Error("Expected discovery without a database client to fail")
+	}
+}
+
+func TestComputeProjectDiff_CollectionsAndIndexes(t *testing.T) {
+	ttl := int32(3600)
+	desired := &ProjectState{
+		Collections: []types.CollectionManifest{
+			collectionManifest("orders", "cluster0", "app", "orders", types.CollectionOptions{}),
+			collectionManifest("events", "cluster0", "app", "events", types.CollectionOptions{
+				TimeSeries: &types.TimeSeriesOptions{TimeField: "ts"},
+			}),
+		},
+		Indexes: []types.IndexManifest{
+			indexManifest("orders-email", "cluster0", "app", "orders", types.IndexKeys{{Field: "email", Order: 1}}, types.IndexOptions{Unique: true}),
+			indexManifest("orders-created", "cluster0", "app", "orders", types.IndexKeys{{Field: "createdAt", Order: 1}}, types.IndexOptions{ExpireAfterSeconds: &ttl}),
+			indexManifest("orders-search", "cluster0", "app", "orders", types.IndexKeys{{Field: "title", Type: types.IndexTypeText}}, types.IndexOptions{Name: "search"}),
+		},
+	}
+	current := &ProjectState{
+		Collections: []types.CollectionManifest{
+			collectionManifest("orders", "cluster0", "app", "orders", types.CollectionOptions{
+				ValidationLevel: types.ValidationLevelStrict, ValidationAction: types.ValidationActionError,
+			}),
+		},
+		Indexes: []types.IndexManifest{
+			indexManifest("email_1", "cluster0", "app", "orders", types.IndexKeys{{Field: "email", Order: 1}}, types.IndexOptions{Name: "email_1", Unique: true}),
+			indexManifest("createdAt_1", "cluster0", "app", "orders", types.IndexKeys{{Field: "createdAt", Order: 1}},
+				types.IndexOptions{Name: "createdAt_1", ExpireAfterSeconds: &ttl, Hidden: true}),
+			indexManifest("search", "cluster0", "app", "orders", types.IndexKeys{{Field: "_fts", Type: types.IndexTypeText}, {Field: "_ftsx", Order: 1}},
+				types.IndexOptions{Name: "search"}),
+			indexManifest("legacy_1", "cluster0", "app", "orders", types.IndexKeys{{Field: "legacy", Order: 1}}, types.IndexOptions{Name: "legacy_1"}),
+		},
+	}
+
+	diff, err := NewDiffEngine().ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("ComputeProjectDiff failed: %v", err)
+	}
+
+	ops := make(map[string]Operation)
+	for _, op := range diff.Operations {
+		ops[string(op.ResourceType)+"/"+op.ResourceName] = op
+	}
+	expected := map[string]OperationType{
+		"Collection/orders":    OperationNoChange,
+		"Collection/events":    OperationCreate,
+		"Index/orders-email":   OperationNoChange,
+		"Index/orders-created": OperationUpdate,
+		"Index/orders-search":  OperationNoChange,
+		"Index/legacy_1":       OperationDelete,
+	}
+	for key, want := range expected {
+		op, ok := ops[key]
+		if !ok {
+			t.Errorf("Expected an operation for %s", key)
+			continue
+		}
+		if op.Type != want {
+			t.Errorf("Expected %s for %s, got %s", want, key, op.Type)
+		}
+	}
+
+	update := ops["Index/orders-created"]
+	if IndexRequiresRebuild(&update.Desired.(*types.IndexManifest).Spec, &update.Current.(*types.IndexManifest).Spec) {
+		t.Error("Expected a hidden-only change to be applied without rebuilding the index")
+	}
+
+	duplicate := &ProjectState{Indexes: []types.IndexManifest{desired.Indexes[0], desired.Indexes[0]}}
+	if _, err := NewDiffEngine().ComputeProjectDiff(duplicate, &ProjectState{}); err == nil {
+		t.Error("Expected an index declared twice to be rejected")
+	}
+}
+
+func TestCollectionImmutableChanges(t *testing.T) {
+	current := types.CollectionSpec{Name: "events", CollectionOptions: types.CollectionOptions{
+		TimeSeries: &types.TimeSeriesOptions{TimeField: "ts", Granularity: "seconds"},
+	}}
+	desired := current
+	desired.Validator = map[string]interface{}{"$jsonSchema": map[string]interface{}{"required": []interface{}{"ts"}}}
+	if changed := CollectionImmutableChanges(&desired, &current); len(changed) != 0 {
+		t.Errorf("Expected a validator change to be mutable, got %v", changed)
+	}
+
+	desired.TimeSeries = &types.TimeSeriesOptions{TimeField: "ts", Granularity: "hours"}
+	desired.Capped, desired.Size = true, 1024
+	changed := CollectionImmutableChanges(&desired, &current)
+	if len(changed) != 2 || changed[0] != "capped" || changed[1] != "timeseries" {
+		t.Errorf("Expected capped and timeseries to be reported, got %v", changed)
+	}
+}
+
+func TestPlanBuilder_DatabaseResourceDependencies(t *testing.T) {
+	cluster := Operation{Type: OperationCreate, ResourceType: types.KindCluster, ResourceName: "cluster0",
+		Desired: &types.ClusterManifest{Metadata: types.ResourceMetadata{Name: "cluster0"}}}
+	otherCluster := Operation{Type: OperationCreate, ResourceType: types.KindCluster, ResourceName: "cluster1",
+		Desired: &types.ClusterManifest{Metadata: types.ResourceMetadata{Name: "cluster1"}}}
+	collection := collectionManifest("orders", "cluster0", "app", "orders", types.CollectionOptions{})
+	index := indexManifest("orders-email", "cluster0", "app", "orders", types.IndexKeys{{Field: "email", Order: 1}}, types.IndexOptions{})
+
+	plan, err := NewPlanBuilder("proj").AddOperations([]Operation{
+		cluster,
+		otherCluster,
+		{Type: OperationCreate, ResourceType: types.KindCollection, ResourceName: "orders", Desired: &collection},
+		{Type: OperationCreate, ResourceType: types.KindIndex, ResourceName: "orders-email", Desired: &index},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	ids := make(map[string]string)
+	for _, op := range plan.Operations {
+		ids[op.ResourceName] = op.ID
+	}
+	for _, op := range plan.Operations {
+		deps := make(map[string]bool)
+		for _, dep := range op.Dependencies {
+			deps[dep] = true
+		}
+		switch op.ResourceName {
+		case "orders":
+			if !deps[ids["cluster0"]] || deps[ids["cluster1"]] {
+				t.Errorf("Expected the collection to depend only on its cluster, got %v", op.Dependencies)
+			}
+		case "orders-email":
+			if !deps[ids["cluster0"]] || !deps[ids["orders"]] || deps[ids["cluster1"]] {
+				t.Errorf("Expected the index to depend on its cluster and collection, got %v", op.Dependencies)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// specPipelineValues converts a declared pipeline to the plain JSON values discovery reports,
+// so Extended JSON values, number types and $sort lists compare equal to what the server
+// returns. A pipeline that can't be converted is returned unchanged; validation reports it.
+func specPipelineValues(stages []interface{}) []interface{} {
+	pipeline, err := database.PipelineFromSpec(stages)
+	if err != nil {
+		return stages
+	}
+	values, err := database.PipelineValues(pipeline)
+	if err != nil {
+		return stages
+	}
+	return values
+}
+
+// normalizeViewSpec converts the pipeline to plain JSON values and drops fields that don't
+// describe the view itself
+func normalizeViewSpec(spec types.ViewSpec) types.ViewSpec {
+	spec.ProjectName = ""
+	spec.DependsOn = nil
+	spec.Pipeline = specPipelineValues(spec.Pipeline)
+	return spec
+}
+
+// normalizeMaterializedViewSpec converts the pipeline to plain JSON values, fills in the
+// default $merge actions and drops fields that don't describe the view itself
+func normalizeMaterializedViewSpec(spec types.MaterializedViewSpec) types.MaterializedViewSpec {
+	spec.ProjectName = ""
+	spec.DependsOn = nil
+	spec.Pipeline = specPipelineValues(spec.Pipeline)
+	merge := spec.Merge.WithDefaults()
+	if len(merge.On) == 0 {
+		merge.On = nil
+	}
+	spec.Merge = &merge
+	return spec
+}
+
+// ViewImmutableChanges lists the options that differ between two views and can only be set
+// when a view is created
+func ViewImmutableChanges(desired, current *types.ViewSpec) []string {
+	if !jsonEqual(desired.Collation, alignCollation(desired.Collation, current.Collation)) {
+		return []string{"collation"}
+	}
+	return nil
+}
+
+// viewFieldChanges reports view and materialized view updates field by field, with the
+// pipeline compared stage by stage
+func viewFieldChanges(desired, current interface{}) []FieldChange {
+	var changes []FieldChange
+	field := func(path string, want, have interface{}) {
+		if !jsonEqual(want, have) {
+			changes = append(changes, FieldChange{Path: path, OldValue: have, NewValue: want, Type: ChangeTypeModify})
+		}
+	}
+
+	switch want := desired.(type) {
+	case *types.ViewManifest:
+		have, ok := current.(*types.ViewManifest)
+		if !ok || want == nil || have == nil {
+			return nil
+		}
+		wantSpec, haveSpec := normalizeViewSpec(want.Spec), normalizeViewSpec(have.Spec)
+		field("spec.viewOn", wantSpec.ViewOn, haveSpec.ViewOn)
+		changes = append(changes, pipelineFieldChanges(wantSpec.Pipeline, haveSpec.Pipeline)...)
+		field("spec.collation", wantSpec.Collation, haveSpec.Collation)
+	case *types.MaterializedViewManifest:
+		have, ok := current.(*types.MaterializedViewManifest)
+		if !ok || want == nil || have == nil {
+			return nil
+		}
+		wantSpec, haveSpec := normalizeMaterializedViewSpec(want.Spec), normalizeMaterializedViewSpec(have.Spec)
+		field("spec.source", wantSpec.Source, haveSpec.Source)
+		changes = append(changes, pipelineFieldChanges(wantSpec.Pipeline, haveSpec.Pipeline)...)
+		field("spec.merge.on", wantSpec.Merge.On, haveSpec.Merge.On)
+		field("spec.merge.whenMatched", wantSpec.Merge.WhenMatched, haveSpec.Merge.WhenMatched)
+		field("spec.merge.whenNotMatched", wantSpec.Merge.WhenNotMatched, haveSpec.Merge.WhenNotMatched)
+	}
+	return changes
+}
+
+// pipelineFieldChanges compares two pipelines stage by stage, matching the stages they have
+// in common so an inserted or removed stage is reported on its own. A stage removed where
+// another is added is reported as a modified stage. Stages are shown as compact JSON;
+// paths index the desired pipeline, or the current one for removed stages.
+func pipelineFieldChanges(desired, current []interface{}) []FieldChange {
+	want := stageStrings(desired)
+	have := stageStrings(current)
+
+	// common[i][j] is the length of the longest common subsequence of have[i:] and want[j:]
+	common := make([][]int, len(have)+1)
+	for i := range common {
+		common[i] = make([]int, len(want)+1)
+	}
+	for i := len(have) - 1; i >= 0; i-- {
+		for j := len(want) - 1; j >= 0; j-- {
+			if have[i] == want[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var changes []FieldChange
+	i, j := 0, 0
+	for i < len(have) || j < len(want) {
+		switch {
+		case i < len(have) && j < len(want) && have[i] == want[j]:
+			i++
+			j++
+		case j == len(want) || (i < len(have) && common[i+1][j] >= common[i][j+1]):
+			changes = append(changes, FieldChange{Path: fmt.Sprintf("spec.pipeline[%d]", i), OldValue: have[i], Type: ChangeTypeRemove})
+			i++
+		default:
+			path := fmt.Sprintf("spec.pipeline[%d]", j)
+			if n := len(changes); n > 0 && changes[n-1].Type == ChangeTypeRemove {
+				changes[n-1] = FieldChange{Path: path, OldValue: changes[n-1].OldValue, NewValue: want[j], Type: ChangeTypeModify}
+			} else {
+				changes = append(changes, FieldChange{Path: path, NewValue: want[j], Type: ChangeTypeAdd})
+			}
+			j++
+		}
+	}
+	return changes
+}
+
+// stageStrings encodes each stage of a pipeline as compact JSON
+func stageStrings(pipeline []interface{}) []string {
+	stages := make([]string, len(pipeline))
+	for i, stage := range pipeline {
+		data, err := json.Marshal(stage)
+		if err != nil {
+			stages[i] = fmt.Sprint(stage)
+			continue
+		}
+		stages[i] = string(data)
+	}
+	return stages
+}
@@ -0,0 +1,204 @@
+package apply
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func viewManifest(name, cluster, database, view, viewOn string, pipeline ...interface{}) types.ViewManifest {
+	return types.ViewManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindView,
+		Metadata:   types.ResourceMetadata{Name: name},
+		Spec: types.ViewSpec{
+			ClusterName: cluster, DatabaseName: database, Name: view, ViewOn: viewOn, Pipeline: pipeline,
+		},
+	}
+}
+
+func materializedViewManifest(name, cluster, database, view, source string, pipeline ...interface{}) types.MaterializedViewManifest {
+	return types.MaterializedViewManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindMaterializedView,
+		Metadata:   types.ResourceMetadata{Name: name},
+		Spec: types.MaterializedViewSpec{
+			ClusterName: cluster, DatabaseName: database, Name: view, Source: source, Pipeline: pipeline,
+		},
+	}
+}
+
+func TestDiscoverDatabaseResources_Views(t *testing.T) {
+	client := &fakeDatabaseClient{
+		collections: map[string][]types.CollectionInfo{
+			"cluster0/app": {
+				{Name: "orders"},
+				{Name: "open_orders", Type: "view", Options: map[string]interface{}{
+					"viewOn":   "orders",
+					"pipeline": bson.A{bson.M{"$match": bson.M{"status": "open"}}},
+				}},
+			},
+		},
+		materialized: map[string]*database.MaterializedView{
+			"cluster0/app/revenue": {Name: "revenue", Source: "orders", Pipeline: bson.A{
+				bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$customerId"}}}},
+			}, WhenMatched: types.MergeWhenMatchedReplace},
+		},
+	}
+	desired := &ProjectState{
+		Views: []types.ViewManifest{
+			viewManifest("open-orders", "cluster0", "app", "open_orders", "orders"),
+			viewManifest("collection-not-view", "cluster0", "app", "orders", "other"),
+		},
+		MaterializedViews: []types.MaterializedViewManifest{
+			materializedViewManifest("revenue", "cluster0", "app", "revenue", "orders"),
+			materializedViewManifest("missing", "cluster0", "app", "missing", "orders"),
+		},
+	}
+	current := &ProjectState{Clusters: []types.ClusterManifest{{Metadata: types.ResourceMetadata{Name: "cluster0"}}}}
+
+	if err := discoverDatabaseResources(context.Background(), client, desired, current); err != nil {
+		t.Fatalf("discoverDatabaseResources failed: %v", err)
+	}
+	if len(current.Views) != 1 || current.Views[0].Metadata.Name != "open-orders" || current.Views[0].Spec.ViewOn != "orders" {
+		t.Fatalf("Expected only the declared existing view, got %+v", current.Views)
+	}
+	wantPipeline := []interface{}{map[string]interface{}{"$match": map[string]interface{}{"status": "open"}}}
+	if !reflect.DeepEqual(current.Views[0].Spec.Pipeline, wantPipeline) {
+		t.Errorf("Expected the view pipeline as plain JSON values, got %#v", current.Views[0].Spec.Pipeline)
+	}
+	if len(current.MaterializedViews) != 1 || current.MaterializedViews[0].Spec.Source != "orders" ||
+		current.MaterializedViews[0].Spec.Merge.WhenMatched != types.MergeWhenMatchedReplace {
+		t.Errorf("Expected only the defined materialized view, got %+v", current.MaterializedViews)
+	}
+}
+
+func TestComputeProjectDiff_Views(t *testing.T) {
+	desired := &ProjectState{
+		Views: []types.ViewManifest{
+			viewManifest("open-orders", "cluster0", "app", "open_orders", "orders",
+				map[string]interface{}{"$match": map[string]interface{}{"status": "open"}},
+				map[string]interface{}{"$sort": []interface{}{"createdAt:-1"}},
+				map[string]interface{}{"$limit": 100}),
+			viewManifest("big-orders", "cluster0", "app", "big_orders", "orders",
+				map[string]interface{}{"$match": map[string]interface{}{"total": map[string]interface{}{"$gte": 1000}}},
+				map[string]interface{}{"$project": map[string]interface{}{"total": 1}}),
+		},
+		MaterializedViews: []types.MaterializedViewManifest{
+			materializedViewManifest("revenue", "cluster0", "app", "revenue", "orders",
+				map[string]interface{}{"$group": map[string]interface{}{"_id": "$customerId"}}),
+		},
+	}
+	// Discovered pipelines hold the numbers the server returns as plain JSON values
+	current := &ProjectState{
+		Views: []types.ViewManifest{
+			viewManifest("open_orders", "cluster0", "app", "open_orders", "orders",
+				map[string]interface{}{"$match": map[string]interface{}{"status": "open"}},
+				map[string]interface{}{"$sort": map[string]interface{}{"createdAt": float64(-1)}},
+				map[string]interface{}{"$limit": float64(100)}),
+			viewManifest("big_orders", "cluster0", "app", "big_orders", "orders",
+				map[string]interface{}{"$match": map[string]interface{}{"total": map[string]interface{}{"$gte": float64(500)}}}),
+		},
+	}
+
+	diff, err := NewDiffEngine().ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("ComputeProjectDiff failed: %v", err)
+	}
+	ops := make(map[string]Operation)
+	for _, op := range diff.Operations {
+		ops[string(op.ResourceType)+"/"+op.ResourceName] = op
+	}
+	expected := map[string]OperationType{
+		"View/open-orders":         OperationNoChange,
+		"View/big-orders":          OperationUpdate,
+		"MaterializedView/revenue": OperationCreate,
+	}
+	for key, want := range expected {
+		if op, ok := ops[key]; !ok || op.Type != want {
+			t.Errorf("Expected %s for %s, got %+v", want, key, ops[key])
+		}
+	}
+
+	changes := ops["View/big-orders"].FieldChanges
+	if len(changes) != 2 {
+		t.Fatalf("Expected a modified and an added stage, got %+v", changes)
+	}
+	if changes[0].Path != "spec.pipeline[0]" || changes[0].Type != ChangeTypeModify || changes[0].NewValue != `{"$match":{"total":{"$gte":1000}}}` {
+		t.Errorf("Unexpected first change %+v", changes[0])
+	}
+	if changes[1].Path != "spec.pipeline[1]" || changes[1].Type != ChangeTypeAdd {
+		t.Errorf("Unexpected second change %+v", changes[1])
+	}
+}
+
+func TestPipelineFieldChanges(t *testing.T) {
+	stage := func(name string) interface{} { return map[string]interface{}{name: map[string]interface{}{}} }
+
+	changes := pipelineFieldChanges(
+		[]interface{}{stage("$match"), stage("$unwind"), stage("$group"), stage("$sort")},
+		[]interface{}{stage("$match"), stage("$group"), stage("$limit")},
+	)
+	want := []FieldChange{
+		{Path: "spec.pipeline[1]", NewValue: `{"$unwind":{}}`, Type: ChangeTypeAdd},
+		{Path: "spec.pipeline[3]", OldValue: `{"$limit":{}}`, NewValue: `{"$sort":{}}`, Type: ChangeTypeModify},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Expected %+v, got %+v", want, changes)
+	}
+
+	removed := pipelineFieldChanges([]interface{}{stage("$match")}, []interface{}{stage("$match"), stage("$limit")})
+	if len(removed) != 1 || removed[0].Type != ChangeTypeRemove || removed[0].Path != "spec.pipeline[1]" {
+		t.Errorf("Expected the trailing stage to be removed, got %+v", removed)
+	}
+}
+
+func TestViewImmutableChanges(t *testing.T) {
+	current := types.ViewSpec{Name: "v", ViewOn: "orders", Collation: &types.IndexCollation{Locale: "en", Strength: 3}}
+	desired := types.ViewSpec{Name: "v", ViewOn: "events", Collation: &types.IndexCollation{Locale: "en"}}
+	if changed := ViewImmutableChanges(&desired, &current); len(changed) != 0 {
+		t.Errorf("Expected only collation fields that are set to be compared, got %v", changed)
+	}
+	desired.Collation = &types.IndexCollation{Locale: "fr"}
+	if changed := ViewImmutableChanges(&desired, &current); len(changed) != 1 || changed[0] != "collation" {
+		t.Errorf("Expected a collation change to be reported, got %v", changed)
+	}
+}
+
+func TestPlanBuilder_ViewDependencies(t *testing.T) {
+	collection := collectionManifest("orders", "cluster0", "app", "orders", types.CollectionOptions{})
+	index := indexManifest("revenue-customer", "cluster0", "app", "revenue", types.IndexKeys{{Field: "customerId", Order: 1}}, types.IndexOptions{Unique: true})
+	view := viewManifest("open-orders", "cluster0", "app", "open_orders", "orders")
+	materialized := materializedViewManifest("revenue", "cluster0", "app", "revenue", "orders")
+
+	plan, err := NewPlanBuilder("proj").AddOperations([]Operation{
+		{Type: OperationCreate, ResourceType: types.KindCollection, ResourceName: "orders", Desired: &collection},
+		{Type: OperationCreate, ResourceType: types.KindIndex, ResourceName: "revenue-customer", Desired: &index},
+		{Type: OperationCreate, ResourceType: types.KindView, ResourceName: "open-orders", Desired: &view},
+		{Type: OperationCreate, ResourceType: types.KindMaterializedView, ResourceName: "revenue", Desired: &materialized},
+	}).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	ids := make(map[string]string)
+	deps := make(map[string]map[string]bool)
+	for _, op := range plan.Operations {
+		ids[op.ResourceName] = op.ID
+		deps[op.ResourceName] = make(map[string]bool)
+		for _, dep := range op.Dependencies {
+			deps[op.ResourceName][dep] = true
+		}
+	}
+	if !deps["open-orders"][ids["orders"]] || len(deps["open-orders"]) != 1 {
+		t.Errorf("Expected the view to depend on its source collection only, got %v", deps["open-orders"])
+	}
+	if !deps["revenue"][ids["orders"]] || !deps["revenue"][ids["revenue-customer"]] {
+		t.Errorf("Expected the materialized view to depend on its source and its indexes, got %v", deps["revenue"])
+	}
+}
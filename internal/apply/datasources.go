@@ -0,0 +1,214 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/validation"
+)
+
+// DataSource is a DataSource manifest with the Atlas object it was resolved to. The
+// object is exposed to references as status.
+type DataSource struct {
+	Manifest *types.DataSourceManifest `json:"manifest"`
+	Status   interface{}               `json:"status,omitempty"`
+}
+
+// Key identifies the data source as DataSource/name
+func (d DataSource) Key() string {
+	return ExecutionOperationKey(types.KindDataSource, d.Manifest.Metadata.Name)
+}
+
+// DataSourceLookup finds the Atlas object a data source names
+type DataSourceLookup interface {
+	LookupDataSource(ctx context.Context, spec types.DataSourceSpec) (interface{}, error)
+}
+
+// UnresolvedDataSources wraps data source manifests without looking them up, for dry runs
+func UnresolvedDataSources(manifests []types.DataSourceManifest) []DataSource {
+	sources := make([]DataSource, len(manifests))
+	for i := range manifests {
+		sources[i] = DataSource{Manifest: &manifests[i]}
+	}
+	return sources
+}
+
+// ResolveDataSources looks up every data source, failing on the first one that is not found
+func ResolveDataSources(ctx context.Context, lookup DataSourceLookup, manifests []types.DataSourceManifest) ([]DataSource, error) {
+	sources := UnresolvedDataSources(manifests)
+	seen := make(map[string]bool)
+	for i := range sources {
+		source := &sources[i]
+		key := source.Key()
+		if seen[key] {
+			return nil, fmt.Errorf("%s is declared more than once", key)
+		}
+		seen[key] = true
+
+		spec := source.Manifest.Spec
+		object, err := lookup.LookupDataSource(ctx, spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s %s not found: %w", key, spec.Type, spec.Name, err)
+		}
+		if source.Status, err = toJSONValues(object); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return sources, nil
+}
+
+// withoutDataSourceTargets returns current without the clusters, database users and access
+// list entries that the data sources of desired read, so a lookup of an object that is not
+// also declared as a resource never plans its deletion
+func withoutDataSourceTargets(desired, current *ProjectState) *ProjectState {
+	if desired == nil || current == nil || len(desired.DataSources) == 0 {
+		return current
+	}
+
+	targets := make(map[string]bool)
+	for _, source := range desired.DataSources {
+		spec := source.Spec
+		if !dataSourceInProject(spec.ProjectName, desired, current) {
+			continue
+		}
+		switch spec.Type {
+		case types.KindCluster, types.KindNetworkAccess:
+			targets[string(spec.Type)+"/"+spec.Name] = true
+		case types.KindDatabaseUser:
+			authDatabase := spec.AuthDatabase
+			if authDatabase == "" {
+				authDatabase = "admin"
+			}
+			targets[string(spec.Type)+"/"+authDatabase+"/"+spec.Name] = true
+		}
+	}
+	// Declared objects are diffed as usual
+	for _, cluster := range desired.Clusters {
+		delete(targets, string(types.KindCluster)+"/"+cluster.Metadata.Name)
+	}
+	for _, user := range desired.DatabaseUsers {
+		authDatabase := user.Spec.AuthDatabase
+		if authDatabase == "" {
+			authDatabase = "admin"
+		}
+		delete(targets, string(types.KindDatabaseUser)+"/"+authDatabase+"/"+user.Spec.Username)
+	}
+	for _, entry := range desired.NetworkAccess {
+		delete(targets, string(types.KindNetworkAccess)+"/"+networkAccessAddress(entry.Spec))
+	}
+	if len(targets) == 0 {
+		return current
+	}
+
+	filtered := *current
+	filtered.Clusters = make([]types.ClusterManifest, 0, len(current.Clusters))
+	for _, cluster := range current.Clusters {
+		if !targets[string(types.KindCluster)+"/"+cluster.Metadata.Name] {
+			filtered.Clusters = append(filtered.Clusters, cluster)
+		}
+	}
+	filtered.DatabaseUsers = make([]types.DatabaseUserManifest, 0, len(current.DatabaseUsers))
+	for _, user := range current.DatabaseUsers {
+		authDatabase := user.Spec.AuthDatabase
+		if authDatabase == "" {
+			authDatabase = "admin"
+		}
+		if !targets[string(types.KindDatabaseUser)+"/"+authDatabase+"/"+user.Spec.Username] {
+			filtered.DatabaseUsers = append(filtered.DatabaseUsers, user)
+		}
+	}
+	filtered.NetworkAccess = make([]types.NetworkAccessManifest, 0, len(current.NetworkAccess))
+	for _, entry := range current.NetworkAccess {
+		if !targets[string(types.KindNetworkAccess)+"/"+networkAccessAddress(entry.Spec)] {
+			filtered.NetworkAccess = append(filtered.NetworkAccess, entry)
+		}
+	}
+	return &filtered
+}
+
+// dataSourceInProject reports whether a data source's project (name or ID) is the project
+// being diffed. A data source without a project reads the configuration's project, and when
+// the project can't be identified the data source is assumed to read it.
+func dataSourceInProject(projectName string, states ...*ProjectState) bool {
+	if projectName == "" {
+		return true
+	}
+	known := false
+	for _, state := range states {
+		if state == nil || state.Project == nil {
+			continue
+		}
+		project := state.Project
+		known = true
+		if projectName == project.Spec.Name || projectName == project.Metadata.Name ||
+			projectName == project.Metadata.Labels["atlas.mongodb.com/project-id"] {
+			return true
+		}
+	}
+	return !known
+}
+
+// LookupDataSource implements DataSourceLookup
+func (f *AtlasStatusFetcher) LookupDataSource(ctx context.Context, spec types.DataSourceSpec) (interface{}, error) {
+	if spec.Type == types.KindProject {
+		if f.Projects == nil {
+			return nil, fmt.Errorf("project service not available")
+		}
+		projectID, err := f.projectID(ctx, spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		return f.Projects.Get(ctx, projectID)
+	}
+
+	projectID, err := f.projectID(ctx, spec.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	fetcher := *f
+	fetcher.ProjectID = projectID
+
+	var declared interface{}
+	switch spec.Type {
+	case types.KindDatabaseUser:
+		declared = &types.DatabaseUserManifest{Spec: types.DatabaseUserSpec{Username: spec.Name, AuthDatabase: spec.AuthDatabase}}
+	case types.KindNetworkAccess:
+		declared = &types.NetworkAccessManifest{Spec: types.NetworkAccessSpec{IPAddress: spec.Name}}
+	case types.KindCluster:
+	default:
+		return nil, fmt.Errorf("unsupported data source type %s", spec.Type)
+	}
+	return fetcher.FetchStatus(ctx, spec.Type, spec.Name, declared)
+}
+
+// projectID resolves a project name or ID, defaulting to the fetcher's project
+func (f *AtlasStatusFetcher) projectID(ctx context.Context, nameOrID string) (string, error) {
+	if nameOrID == "" {
+		return f.ProjectID, nil
+	}
+	if f.Projects == nil {
+		return "", fmt.Errorf("project service not available")
+	}
+	if validation.ValidateProjectID(nameOrID) == nil {
+		return nameOrID, nil
+	}
+	projects, err := f.Projects.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, project := range projects {
+		if project.GetName() == nameOrID {
+			matches = append(matches, project.GetId())
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("project %s not found", nameOrID)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("project name %s is ambiguous; use the project ID", nameOrID)
+	}
+}
@@ -0,0 +1,164 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+type fakeDataSourceLookup map[string]interface{}
+
+func (f fakeDataSourceLookup) LookupDataSource(ctx context.Context, spec types.DataSourceSpec) (interface{}, error) {
+	object, ok := f[string(spec.Type)+"/"+spec.Name]
+	if !ok {
+		return nil, fmt.Errorf("404 not found")
+	}
+	return object, nil
+}
+
+func dataSource(name string, kind types.ResourceKind, object string) types.DataSourceManifest {
+	return types.DataSourceManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindDataSource,
+		Metadata:   types.ResourceMetadata{Name: name},
+		Spec:       types.DataSourceSpec{Type: kind, Name: object},
+	}
+}
+
+func TestResolveDataSources(t *testing.T) {
+	type group struct {
+		ID    string `json:"id"`
+		OrgID string `json:"orgId"`
+	}
+	lookup := fakeDataSourceLookup{"Project/shared-prod": &group{ID: "6a1d7f4b3a1e2c0012345678", OrgID: "5f1d7f4b3a1e2c0012345678"}}
+
+	sources, err := ResolveDataSources(context.Background(), lookup, []types.DataSourceManifest{dataSource("shared", types.KindProject, "shared-prod")})
+	if err != nil {
+		t.Fatalf("ResolveDataSources failed: %v", err)
+	}
+	status, _ := sources[0].Status.(map[string]interface{})
+	if status["id"] != "6a1d7f4b3a1e2c0012345678" {
+		t.Errorf("Expected the status to hold the object by its API field names, got %#v", sources[0].Status)
+	}
+
+	_, err = ResolveDataSources(context.Background(), lookup, []types.DataSourceManifest{dataSource("analytics", types.KindCluster, "analytics")})
+	if err == nil || !strings.Contains(err.Error(), "DataSource/analytics: Cluster analytics not found") {
+		t.Errorf("Expected a missing data source to fail, got %v", err)
+	}
+	duplicate := dataSource("shared", types.KindProject, "shared-prod")
+	if _, err := ResolveDataSources(context.Background(), lookup, []types.DataSourceManifest{duplicate, duplicate}); err == nil {
+		t.Error("Expected duplicate data sources to be rejected")
+	}
+}
+
+func TestPlanBuilder_DataSourceReferences(t *testing.T) {
+	manifest := dataSource("analytics", types.KindCluster, "analytics")
+	sources := []DataSource{{Manifest: &manifest, Status: map[string]interface{}{"name": "analytics-cluster"}}}
+	user := Operation{Type: OperationCreate, ResourceType: types.KindDatabaseUser, ResourceName: "reporter",
+		Desired: referencingUser("reporter", "${ref:DataSource/analytics.status.name}")}
+
+	plan, err := NewPlanBuilder("proj").WithDataSources(sources).AddOperations([]Operation{user}).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(plan.Operations) != 1 || len(plan.Operations[0].Dependencies) != 0 {
+		t.Errorf("Expected a single operation without dependencies, got %+v", plan.Operations)
+	}
+	if len(plan.DataSources) != 1 {
+		t.Errorf("Expected the plan to carry its data sources, got %v", plan.DataSources)
+	}
+
+	fetcher := &fakeStatusFetcher{}
+	resolver := NewReferenceResolver(fetcher)
+	resolver.DeclarePlan(plan)
+	resolved, err := resolver.ResolveResource(context.Background(), plan.Operations[0].Desired)
+	if err != nil {
+		t.Fatalf("ResolveResource failed: %v", err)
+	}
+	if name := resolved.(*types.DatabaseUserManifest).Spec.Scopes[0].Name; name != "analytics-cluster" || fetcher.calls != 0 {
+		t.Errorf("Expected the planned status to be used without fetching, got %q after %d calls", name, fetcher.calls)
+	}
+
+	if _, err := NewPlanBuilder("proj").AddOperations([]Operation{user}).Build(); err == nil || !strings.Contains(err.Error(), "not declared") {
+		t.Errorf("Expected an undeclared data source to fail, got %v", err)
+	}
+}
+
+func TestComputeProjectDiff_DataSourceTargetsAreNotDeleted(t *testing.T) {
+	readerSource := dataSource("reader", types.KindDatabaseUser, "reader")
+	desired := &ProjectState{DataSources: []types.DataSourceManifest{
+		dataSource("analytics", types.KindCluster, "analytics"),
+		readerSource,
+		dataSource("office", types.KindNetworkAccess, "10.0.0.0/24"),
+	}}
+	current := &ProjectState{
+		Clusters: []types.ClusterManifest{
+			{Kind: types.KindCluster, Metadata: types.ResourceMetadata{Name: "analytics"}},
+			{Kind: types.KindCluster, Metadata: types.ResourceMetadata{Name: "legacy"}},
+		},
+		DatabaseUsers: []types.DatabaseUserManifest{
+			{Kind: types.KindDatabaseUser, Metadata: types.ResourceMetadata{Name: "reader"}, Spec: types.DatabaseUserSpec{Username: "reader", AuthDatabase: "admin"}},
+		},
+		NetworkAccess: []types.NetworkAccessManifest{
+			{Kind: types.KindNetworkAccess, Metadata: types.ResourceMetadata{Name: "office"}, Spec: types.NetworkAccessSpec{CIDR: "10.0.0.0/24"}},
+		},
+	}
+
+	diff, err := NewDiffEngine().ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("ComputeProjectDiff failed: %v", err)
+	}
+	plan, err := NewPlanBuilder("proj").AddOperations(diff.Operations).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	var deleted []string
+	for _, op := range plan.Operations {
+		if op.Type == OperationDelete {
+			deleted = append(deleted, string(op.ResourceType)+"/"+op.ResourceName)
+		}
+	}
+	if len(deleted) != 1 || deleted[0] != "Cluster/legacy" {
+		t.Errorf("Expected only the undeclared cluster no data source reads to be deleted, got %v", deleted)
+	}
+	if len(current.Clusters) != 2 {
+		t.Errorf("Expected the current state to be left unchanged, got %d clusters", len(current.Clusters))
+	}
+
+	// An object that is also declared is diffed as usual
+	desired.Clusters = []types.ClusterManifest{{Kind: types.KindCluster, Metadata: types.ResourceMetadata{Name: "analytics"}}}
+	if filtered := withoutDataSourceTargets(desired, current); len(filtered.Clusters) != 2 {
+		t.Errorf("Expected a declared cluster to stay in the current state, got %+v", filtered.Clusters)
+	}
+	desired.Clusters = nil
+
+	// A data source of another project doesn't protect an object of the same name
+	desired.Project = &types.ProjectManifest{Spec: types.ProjectConfig{Name: "app-prod"}}
+	desired.DataSources = []types.DataSourceManifest{dataSource("analytics", types.KindCluster, "analytics")}
+	desired.DataSources[0].Spec.ProjectName = "shared-prod"
+	if filtered := withoutDataSourceTargets(desired, current); len(filtered.Clusters) != 2 {
+		t.Errorf("Expected a data source of another project to be ignored, got %+v", filtered.Clusters)
+	}
+}
+
+func TestAtlasStatusFetcher_MissingServices(t *testing.T) {
+	fetcher := &AtlasStatusFetcher{ProjectID: "6a1d7f4b3a1e2c0012345678"}
+
+	for _, spec := range []types.DataSourceSpec{
+		{Type: types.KindProject, Name: "6a1d7f4b3a1e2c0012345678"},
+		{Type: types.KindProject},
+		{Type: types.KindCluster, Name: "analytics"},
+		{Type: types.KindDatabaseUser, Name: "reader"},
+		{Type: types.KindNetworkAccess, Name: "10.0.0.0/24"},
+	} {
+		if _, err := fetcher.LookupDataSource(context.Background(), spec); err == nil || !strings.Contains(err.Error(), "not available") {
+			t.Errorf("Expected %s lookup without a service to fail, got %v", spec.Type, err)
+		}
+	}
+	if _, err := fetcher.FetchStatus(context.Background(), types.KindProject, "proj", nil); err == nil {
+		t.Error("Expected project status without a project service to fail")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/teabranch/matlas-cli/internal/services/atlas"
 	"github.com/teabranch/matlas-cli/internal/types"
 )
 
@@ -46,6 +47,8 @@ type FieldChange struct {
 	OldValue interface{} `json:"oldValue,omitempty"`
 	NewValue interface{} `json:"newValue,omitempty"`
 	Type     ChangeType  `json:"type"`
+	// Sensitive is set for credential fields; OldValue and NewValue are then masked
+	Sensitive bool `json:"sensitive,omitempty"`
 }
 
 // ChangeType represents the type of field change
@@ -64,6 +67,11 @@ type OperationImpact struct {
 	EstimatedDuration time.Duration `json:"estimatedDuration"`
 	RiskLevel         RiskLevel     `json:"riskLevel"`
 	Warnings          []string      `json:"warnings,omitempty"`
+
+	// Set when EstimatedDuration comes from recorded history: EstimatedDuration is then the
+	// median and EstimatedDurationP90 the 90th percentile of DurationSamples observed runs
+	EstimatedDurationP90 time.Duration `json:"estimatedDurationP90,omitempty"`
+	DurationSamples      int           `json:"durationSamples,omitempty"`
 }
 
 // RiskLevel represents the risk level of an operation
@@ -114,6 +122,9 @@ func (d *DiffEngine) ComputeProjectDiff(desired *ProjectState, current *ProjectS
 		GeneratedAt: time.Now().UTC(),
 	}
 
+	// Objects read by data sources are never deleted
+	current = withoutDataSourceTargets(desired, current)
+
 	// Compute diffs for each resource type
 	if err := d.computeProjectSettingsDiff(desired, current, diff); err != nil {
 		return nil, fmt.Errorf("failed to compute project settings diff: %w", err)
@@ -143,6 +154,38 @@ func (d *DiffEngine) ComputeProjectDiff(desired *ProjectState, current *ProjectS
 		return nil, fmt.Errorf("failed to compute VPC endpoints diff: %w", err)
 	}
 
+	if err := d.computeLDAPConfigurationDiff(desired, current, diff); err != nil {
+		return nil, fmt.Errorf("failed to compute LDAP configuration diff: %w", err)
+	}
+
+	if err := d.computeIntegrationsDiff(desired, current, diff); err != nil {
+		return nil, fmt.Errorf("failed to compute integrations diff: %w", err)
+	}
+
+	if err := d.computeFederationSettingsDiff(desired, current, diff); err != nil {
+		return nil, fmt.Errorf("failed to compute federation settings diff: %w", err)
+	}
+
+	if err := d.computeRoleMappingsDiff(desired, current, diff); err != nil {
+		return nil, fmt.Errorf("failed to compute role mappings diff: %w", err)
+	}
+
+	if err := d.computeCollectionsDiff(desired, current, diff); err != nil {
+		return nil, fmt.Errorf("failed to compute collections diff: %w", err)
+	}
+
+	if err := d.computeIndexesDiff(desired, current, diff); err != nil {
+		return nil, fmt.Errorf("failed to compute indexes diff: %w", err)
+	}
+
+	if err := d.computeViewsDiff(desired, current, diff); err != nil {
+		return nil, fmt.Errorf("failed to compute views diff: %w", err)
+	}
+
+	if err := d.computeMaterializedViewsDiff(desired, current, diff); err != nil {
+		return nil, fmt.Errorf("failed to compute materialized views diff: %w", err)
+	}
+
 	// Compute summary
 	diff.Summary = d.computeSummary(diff.Operations)
 
@@ -311,18 +354,22 @@ func (d *DiffEngine) computeDatabaseRolesDiff(desired *ProjectState, current *Pr
 func (d *DiffEngine) computeNetworkAccessDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
 	desiredMap := make(map[string]interface{})
 	currentMap := make(map[string]interface{})
-
-	if desired != nil {
-		for i := range desired.NetworkAccess {
-			entry := &desired.NetworkAccess[i]
-			desiredMap[entry.Metadata.Name] = entry
-		}
-	}
+	currentByAddress := make(map[string]*types.NetworkAccessManifest)
 
 	if current != nil {
 		for i := range current.NetworkAccess {
 			entry := &current.NetworkAccess[i]
 			currentMap[entry.Metadata.Name] = entry
+			currentByAddress[networkAccessAddress(entry.Spec)] = entry
+		}
+	}
+
+	if desired != nil {
+		now := time.Now()
+		for i := range desired.NetworkAccess {
+			// Work on a copy so ttl/expiresAt resolution does not leak into the caller's state
+			entry := pinNetworkAccessExpiry(desired.NetworkAccess[i], currentByAddress[networkAccessAddress(desired.NetworkAccess[i].Spec)], now)
+			desiredMap[entry.Metadata.Name] = &entry
 		}
 	}
 
@@ -404,6 +451,490 @@ func (d *DiffEngine) computeVPCEndpointsDiff(desired *ProjectState, current *Pro
 	return nil
 }
 
+// computeLDAPConfigurationDiff computes diffs for the project LDAP configuration.
+// LDAP settings are a project singleton, so they are only diffed when the desired
+// state declares them; omitting the kind never disables LDAP.
+func (d *DiffEngine) computeLDAPConfigurationDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
+	if desired == nil || len(desired.LDAPConfigurations) == 0 {
+		return nil
+	}
+	if len(desired.LDAPConfigurations) > 1 {
+		return fmt.Errorf("only one LDAPConfiguration is allowed per project, found %d", len(desired.LDAPConfigurations))
+	}
+
+	desiredConfig := &desired.LDAPConfigurations[0]
+	var currentConfig *types.LDAPConfigurationManifest
+	if current != nil && len(current.LDAPConfigurations) > 0 {
+		existing := current.LDAPConfigurations[0]
+		// Discovery can't know the user-chosen resource name; align it with the desired one
+		existing.Metadata.Name = desiredConfig.Metadata.Name
+		// Undeclared user to DN mappings are left as configured, so they are not compared
+		if len(desiredConfig.Spec.UserToDNMapping) == 0 {
+			existing.Spec.UserToDNMapping = nil
+		}
+		currentConfig = &existing
+	}
+
+	op := d.computeResourceDiff(types.KindLDAPConfiguration, desiredConfig.Metadata.Name, desiredConfig, currentConfig)
+	if op != nil && op.Type == OperationNoChange && desiredConfig.Spec.BindPassword != "" {
+		// Atlas never returns the bind password, so a declared one can't be compared and
+		// is applied every time; otherwise a password-only change would never be applied
+		op.Type = OperationUpdate
+		op.FieldChanges = []FieldChange{{Path: "Spec.BindPassword", NewValue: maskSensitive(desiredConfig.Spec.BindPassword), Type: ChangeTypeModify, Sensitive: true}}
+		op.Impact = d.computeOperationImpact(op)
+	}
+	if op != nil {
+		diff.Operations = append(diff.Operations, *op)
+	}
+	return nil
+}
+
+// computeIntegrationsDiff computes diffs for project third-party integrations.
+// Atlas holds at most one integration per type, so integrations are matched by type.
+// Only declared types are diffed; integrations configured elsewhere (for example a
+// Slack OAuth integration set up in the UI) are never removed by apply.
+func (d *DiffEngine) computeIntegrationsDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
+	if desired == nil || len(desired.Integrations) == 0 {
+		return nil
+	}
+
+	currentByType := make(map[string]types.IntegrationManifest)
+	if current != nil {
+		for _, integration := range current.Integrations {
+			currentByType[strings.ToUpper(integration.Spec.Type)] = integration
+		}
+	}
+
+	seen := make(map[string]string)
+	for i := range desired.Integrations {
+		desiredIntegration := &desired.Integrations[i]
+		integrationType := strings.ToUpper(desiredIntegration.Spec.Type)
+		if other, exists := seen[integrationType]; exists {
+			return fmt.Errorf("only one %s integration is allowed per project, found %q and %q", integrationType, other, desiredIntegration.Metadata.Name)
+		}
+		seen[integrationType] = desiredIntegration.Metadata.Name
+
+		var currentIntegration *types.IntegrationManifest
+		if existing, ok := currentByType[integrationType]; ok {
+			// Discovery names integrations after their type; align with the declared name
+			existing.Metadata.Name = desiredIntegration.Metadata.Name
+			currentIntegration = &existing
+		}
+
+		op := d.computeResourceDiff(types.KindIntegration, desiredIntegration.Metadata.Name, desiredIntegration, currentIntegration)
+		if op != nil {
+			diff.Operations = append(diff.Operations, *op)
+		}
+	}
+	return nil
+}
+
+// computeFederationSettingsDiff computes diffs for organization federation settings.
+// Settings are only diffed when declared, and only the identity providers and connected
+// organization fields the desired state sets are compared; federation settings are never deleted.
+func (d *DiffEngine) computeFederationSettingsDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
+	if desired == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for i := range desired.FederationSettings {
+		desiredSettings := &desired.FederationSettings[i]
+		orgID := desiredSettings.Spec.OrgID
+		if seen[orgID] {
+			return fmt.Errorf("only one FederationSettings is allowed per organization, found duplicate for %s", orgID)
+		}
+		seen[orgID] = true
+
+		var currentSettings *types.FederationSettingsManifest
+		if current != nil {
+			for j := range current.FederationSettings {
+				if current.FederationSettings[j].Spec.OrgID == orgID {
+					currentSettings = alignFederationSettings(desiredSettings, &current.FederationSettings[j])
+					break
+				}
+			}
+		}
+
+		op := d.computeResourceDiff(types.KindFederationSettings, desiredSettings.Metadata.Name, desiredSettings, currentSettings)
+		if op != nil {
+			diff.Operations = append(diff.Operations, *op)
+		}
+	}
+	return nil
+}
+
+// alignFederationSettings returns a copy of the discovered settings restricted to what the
+// desired manifest manages: declared identity providers and connected organization fields.
+func alignFederationSettings(desired, current *types.FederationSettingsManifest) *types.FederationSettingsManifest {
+	aligned := *current
+	aligned.Metadata = desired.Metadata
+	if desired.Spec.FederationSettingsID == "" {
+		aligned.Spec.FederationSettingsID = ""
+	}
+
+	discovered := make(map[string]types.IdentityProviderConfig, len(current.Spec.IdentityProviders))
+	for _, idp := range current.Spec.IdentityProviders {
+		discovered[idp.DisplayName] = idp
+	}
+	aligned.Spec.IdentityProviders = nil
+	for _, want := range desired.Spec.IdentityProviders {
+		have, ok := discovered[want.DisplayName]
+		if !ok {
+			continue
+		}
+		// Optional fields omitted from the desired state are left to Atlas defaults
+		if want.Description == "" {
+			have.Description = ""
+		}
+		if want.ClientID == "" {
+			have.ClientID = ""
+		}
+		if want.AuthorizationType == "" {
+			have.AuthorizationType = ""
+		}
+		if want.GroupsClaim == "" {
+			have.GroupsClaim = ""
+		}
+		if want.UserClaim == "" {
+			have.UserClaim = ""
+		}
+		if want.RequestedScopes == nil {
+			have.RequestedScopes = nil
+		}
+		if want.AssociatedDomains == nil {
+			have.AssociatedDomains = nil
+		}
+		aligned.Spec.IdentityProviders = append(aligned.Spec.IdentityProviders, have)
+	}
+
+	if desired.Spec.ConnectedOrg == nil {
+		aligned.Spec.ConnectedOrg = nil
+	} else if current.Spec.ConnectedOrg != nil {
+		connected := *current.Spec.ConnectedOrg
+		if desired.Spec.ConnectedOrg.IdentityProviderID == "" {
+			connected.IdentityProviderID = ""
+		}
+		if desired.Spec.ConnectedOrg.PostAuthRoleGrants == nil {
+			connected.PostAuthRoleGrants = nil
+		}
+		aligned.Spec.ConnectedOrg = &connected
+	}
+	return &aligned
+}
+
+// computeCollectionsDiff computes diffs for collections, keyed by cluster, database and
+// collection name. Collections are never deleted: only declared collections are discovered.
+func (d *DiffEngine) computeCollectionsDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
+	if desired == nil {
+		return nil
+	}
+
+	currentCollections := make(map[string]*types.CollectionManifest)
+	if current != nil {
+		for i := range current.Collections {
+			spec := &current.Collections[i].Spec
+			currentCollections[collectionKey(spec.ClusterName, spec.DatabaseName, spec.Name)] = &current.Collections[i]
+		}
+	}
+
+	for i := range desired.Collections {
+		desiredCollection := &desired.Collections[i]
+		spec := &desiredCollection.Spec
+		var currentCollection *types.CollectionManifest
+		if existing, ok := currentCollections[collectionKey(spec.ClusterName, spec.DatabaseName, spec.Name)]; ok {
+			aligned := *existing
+			aligned.Metadata = desiredCollection.Metadata
+			aligned.Spec.Collation = alignCollation(spec.Collation, existing.Spec.Collation)
+			currentCollection = &aligned
+		}
+
+		op := d.computeResourceDiff(types.KindCollection, desiredCollection.Metadata.Name, desiredCollection, currentCollection)
+		if op != nil {
+			diff.Operations = append(diff.Operations, *op)
+		}
+	}
+	return nil
+}
+
+// computeIndexesDiff computes diffs for indexes, keyed by cluster, database, collection and
+// index name. Discovery lists every index of a targeted collection, so undeclared indexes
+// on it are planned for deletion.
+func (d *DiffEngine) computeIndexesDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
+	desiredIndexes := make(map[string]*types.IndexManifest)
+	currentIndexes := make(map[string]*types.IndexManifest)
+
+	indexKey := func(spec *types.IndexSpec) string {
+		return collectionKey(spec.ClusterName, spec.DatabaseName, spec.CollectionName) + "/" + spec.IndexName()
+	}
+	if desired != nil {
+		for i := range desired.Indexes {
+			key := indexKey(&desired.Indexes[i].Spec)
+			if _, exists := desiredIndexes[key]; exists {
+				return fmt.Errorf("index %s is declared more than once", key)
+			}
+			desiredIndexes[key] = &desired.Indexes[i]
+		}
+	}
+	if current != nil {
+		for i := range current.Indexes {
+			currentIndexes[indexKey(&current.Indexes[i].Spec)] = &current.Indexes[i]
+		}
+	}
+
+	allKeys := make(map[string]bool)
+	for key := range desiredIndexes {
+		allKeys[key] = true
+	}
+	for key := range currentIndexes {
+		allKeys[key] = true
+	}
+
+	for key := range allKeys {
+		desiredIndex := desiredIndexes[key]
+		currentIndex := currentIndexes[key]
+
+		var resourceName string
+		if desiredIndex != nil {
+			resourceName = desiredIndex.Metadata.Name
+			if currentIndex != nil {
+				aligned := *currentIndex
+				aligned.Metadata = desiredIndex.Metadata
+				aligned.Spec.Collation = alignCollation(desiredIndex.Spec.Collation, currentIndex.Spec.Collation)
+				currentIndex = &aligned
+			}
+		} else if currentIndex != nil {
+			resourceName = currentIndex.Metadata.Name
+		}
+
+		op := d.computeResourceDiff(types.KindIndex, resourceName, desiredIndex, currentIndex)
+		if op != nil {
+			diff.Operations = append(diff.Operations, *op)
+		}
+	}
+	return nil
+}
+
+// computeViewsDiff computes diffs for views, keyed by cluster, database and view name. Like
+// collections, views are never deleted: only declared views are discovered.
+func (d *DiffEngine) computeViewsDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
+	if desired == nil {
+		return nil
+	}
+
+	currentViews := make(map[string]*types.ViewManifest)
+	if current != nil {
+		for i := range current.Views {
+			spec := &current.Views[i].Spec
+			currentViews[collectionKey(spec.ClusterName, spec.DatabaseName, spec.Name)] = &current.Views[i]
+		}
+	}
+
+	for i := range desired.Views {
+		desiredView := &desired.Views[i]
+		spec := &desiredView.Spec
+		var currentView *types.ViewManifest
+		if existing, ok := currentViews[collectionKey(spec.ClusterName, spec.DatabaseName, spec.Name)]; ok {
+			aligned := *existing
+			aligned.Metadata = desiredView.Metadata
+			aligned.Spec.Collation = alignCollation(spec.Collation, existing.Spec.Collation)
+			currentView = &aligned
+		}
+
+		op := d.computeResourceDiff(types.KindView, desiredView.Metadata.Name, desiredView, currentView)
+		if op != nil {
+			diff.Operations = append(diff.Operations, *op)
+		}
+	}
+	return nil
+}
+
+// computeMaterializedViewsDiff computes diffs for materialized views, keyed by cluster,
+// database and view name. Materialized views are never deleted.
+func (d *DiffEngine) computeMaterializedViewsDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
+	if desired == nil {
+		return nil
+	}
+
+	currentViews := make(map[string]*types.MaterializedViewManifest)
+	if current != nil {
+		for i := range current.MaterializedViews {
+			spec := &current.MaterializedViews[i].Spec
+			currentViews[collectionKey(spec.ClusterName, spec.DatabaseName, spec.Name)] = &current.MaterializedViews[i]
+		}
+	}
+
+	for i := range desired.MaterializedViews {
+		desiredView := &desired.MaterializedViews[i]
+		spec := &desiredView.Spec
+		var currentView *types.MaterializedViewManifest
+		if existing, ok := currentViews[collectionKey(spec.ClusterName, spec.DatabaseName, spec.Name)]; ok {
+			aligned := *existing
+			aligned.Metadata = desiredView.Metadata
+			currentView = &aligned
+		}
+
+		op := d.computeResourceDiff(types.KindMaterializedView, desiredView.Metadata.Name, desiredView, currentView)
+		if op != nil {
+			diff.Operations = append(diff.Operations, *op)
+		}
+	}
+	return nil
+}
+
+// computeRoleMappingsDiff computes diffs for federated role mappings, keyed by organization
+// and external group name. Only mappings of organizations declared in the desired state are
+// considered, so undeclared mappings in those organizations are planned for deletion.
+func (d *DiffEngine) computeRoleMappingsDiff(desired *ProjectState, current *ProjectState, diff *Diff) error {
+	if desired == nil {
+		return nil
+	}
+
+	managedOrgs := make(map[string]bool)
+	for _, settings := range desired.FederationSettings {
+		managedOrgs[settings.Spec.OrgID] = true
+	}
+
+	desiredMappings := make(map[string]*types.RoleMappingManifest)
+	for i := range desired.RoleMappings {
+		mapping := &desired.RoleMappings[i]
+		key := roleMappingKey(&mapping.Spec)
+		if _, exists := desiredMappings[key]; exists {
+			return fmt.Errorf("duplicate RoleMapping for group %q in organization %s", mapping.Spec.ExternalGroupName, mapping.Spec.OrgID)
+		}
+		desiredMappings[key] = mapping
+		managedOrgs[mapping.Spec.OrgID] = true
+	}
+
+	currentMappings := make(map[string]*types.RoleMappingManifest)
+	if current != nil {
+		for i := range current.RoleMappings {
+			mapping := &current.RoleMappings[i]
+			if managedOrgs[mapping.Spec.OrgID] {
+				currentMappings[roleMappingKey(&mapping.Spec)] = mapping
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(desiredMappings)+len(currentMappings))
+	for key := range desiredMappings {
+		keys = append(keys, key)
+	}
+	for key := range currentMappings {
+		if _, ok := desiredMappings[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		desiredMapping := desiredMappings[key]
+		currentMapping := currentMappings[key]
+
+		resourceName := ""
+		if desiredMapping != nil {
+			resourceName = desiredMapping.Metadata.Name
+		} else {
+			resourceName = currentMapping.Metadata.Name
+		}
+
+		op := d.computeResourceDiff(types.KindRoleMapping, resourceName, desiredMapping, currentMapping)
+		if op != nil {
+			diff.Operations = append(diff.Operations, *op)
+		}
+	}
+	return nil
+}
+
+// RoleMappingAssignmentChanges returns the role assignments a change from current to desired
+// grants and revokes, formatted for display (e.g. "GROUP_OWNER on project 6a1d..."). Either
+// spec may be nil for creates and deletes.
+func RoleMappingAssignmentChanges(desired, current *types.RoleMappingSpec) (added, removed []string) {
+	desiredSet := make(map[string]bool)
+	currentSet := make(map[string]bool)
+	if desired != nil {
+		for _, ra := range normalizeRoleAssignments(desired) {
+			desiredSet[formatRoleAssignment(ra)] = true
+		}
+	}
+	if current != nil {
+		for _, ra := range normalizeRoleAssignments(current) {
+			currentSet[formatRoleAssignment(ra)] = true
+		}
+	}
+	for assignment := range desiredSet {
+		if !currentSet[assignment] {
+			added = append(added, assignment)
+		}
+	}
+	for assignment := range currentSet {
+		if !desiredSet[assignment] {
+			removed = append(removed, assignment)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// roleMappingSpecs extracts the role mapping specs of an operation's desired and current values
+func roleMappingSpecs(desired, current interface{}) (*types.RoleMappingSpec, *types.RoleMappingSpec) {
+	var desiredSpec, currentSpec *types.RoleMappingSpec
+	if m, ok := desired.(*types.RoleMappingManifest); ok && m != nil {
+		desiredSpec = &m.Spec
+	}
+	if m, ok := current.(*types.RoleMappingManifest); ok && m != nil {
+		currentSpec = &m.Spec
+	}
+	return desiredSpec, currentSpec
+}
+
+// roleMappingFieldChanges reports role mapping updates as granted and revoked role assignments
+func roleMappingFieldChanges(desired, current interface{}) []FieldChange {
+	added, removed := RoleMappingAssignmentChanges(roleMappingSpecs(desired, current))
+	changes := make([]FieldChange, 0, len(added)+len(removed))
+	for _, assignment := range added {
+		changes = append(changes, FieldChange{Path: "spec.roleAssignments", NewValue: assignment, Type: ChangeTypeAdd})
+	}
+	for _, assignment := range removed {
+		changes = append(changes, FieldChange{Path: "spec.roleAssignments", OldValue: assignment, Type: ChangeTypeRemove})
+	}
+	return changes
+}
+
+func roleMappingKey(spec *types.RoleMappingSpec) string {
+	return spec.OrgID + "/" + spec.ExternalGroupName
+}
+
+// normalizeRoleAssignments defaults organization roles to the mapping's organization and sorts them
+func normalizeRoleAssignments(spec *types.RoleMappingSpec) []types.RoleAssignmentConfig {
+	assignments := make([]types.RoleAssignmentConfig, len(spec.RoleAssignments))
+	for i, ra := range spec.RoleAssignments {
+		if ra.ProjectID == "" && ra.OrgID == "" {
+			ra.OrgID = spec.OrgID
+		}
+		assignments[i] = ra
+	}
+	atlas.SortRoleAssignments(assignments)
+	return assignments
+}
+
+func formatRoleAssignment(ra types.RoleAssignmentConfig) string {
+	if ra.ProjectID != "" {
+		return fmt.Sprintf("%s on project %s", ra.Role, ra.ProjectID)
+	}
+	return fmt.Sprintf("%s on org %s", ra.Role, ra.OrgID)
+}
+
+func sortedStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
 // computeDiffFromNamedMaps computes diffs given name-indexed desired and current maps
 func (d *DiffEngine) computeDiffFromNamedMaps(resourceType types.ResourceKind, desiredMap, currentMap map[string]interface{}, diff *Diff) {
 	// Find all unique names
@@ -456,6 +987,38 @@ func (d *DiffEngine) computeResourceDiff(resourceType types.ResourceKind, resour
 			if v == nil {
 				desired = nil
 			}
+		case *types.LDAPConfigurationManifest:
+			if v == nil {
+				desired = nil
+			}
+		case *types.IntegrationManifest:
+			if v == nil {
+				desired = nil
+			}
+		case *types.FederationSettingsManifest:
+			if v == nil {
+				desired = nil
+			}
+		case *types.RoleMappingManifest:
+			if v == nil {
+				desired = nil
+			}
+		case *types.CollectionManifest:
+			if v == nil {
+				desired = nil
+			}
+		case *types.IndexManifest:
+			if v == nil {
+				desired = nil
+			}
+		case *types.ViewManifest:
+			if v == nil {
+				desired = nil
+			}
+		case *types.MaterializedViewManifest:
+			if v == nil {
+				desired = nil
+			}
 		}
 	}
 
@@ -485,6 +1048,38 @@ func (d *DiffEngine) computeResourceDiff(resourceType types.ResourceKind, resour
 			if v == nil {
 				current = nil
 			}
+		case *types.LDAPConfigurationManifest:
+			if v == nil {
+				current = nil
+			}
+		case *types.IntegrationManifest:
+			if v == nil {
+				current = nil
+			}
+		case *types.FederationSettingsManifest:
+			if v == nil {
+				current = nil
+			}
+		case *types.RoleMappingManifest:
+			if v == nil {
+				current = nil
+			}
+		case *types.CollectionManifest:
+			if v == nil {
+				current = nil
+			}
+		case *types.IndexManifest:
+			if v == nil {
+				current = nil
+			}
+		case *types.ViewManifest:
+			if v == nil {
+				current = nil
+			}
+		case *types.MaterializedViewManifest:
+			if v == nil {
+				current = nil
+			}
 		}
 	}
 
@@ -510,12 +1105,21 @@ func (d *DiffEngine) computeResourceDiff(resourceType types.ResourceKind, resour
 		op.Type = OperationCreate
 	} else {
 		// Compare the resources to see if they're different
-		if d.resourcesEqual(desired, current) {
+		// Module and forEach annotations only record where a resource was declared, and
+		// references are only resolved at execution time
+		compared := withReferencesFrom(withoutGeneratedAnnotations(desired), current)
+		if d.resourcesEqual(compared, current) {
 			op.Type = OperationNoChange
 		} else {
 			op.Type = OperationUpdate
 			// Compute field-level changes
-			op.FieldChanges = d.computeFieldChanges(desired, current)
+			op.FieldChanges = d.computeFieldChanges(compared, current)
+			switch resourceType {
+			case types.KindRoleMapping:
+				op.FieldChanges = roleMappingFieldChanges(desired, current)
+			case types.KindView, types.KindMaterializedView:
+				op.FieldChanges = viewFieldChanges(desired, current)
+			}
 		}
 	}
 
@@ -589,6 +1193,7 @@ func (d *DiffEngine) normalizeForComparison(resource interface{}) interface{} {
 		}
 		normalized := *v
 		normalized.Status = nil
+		normalized.Spec.DeleteAfterDate = normalizeExpiry(normalized.Spec.DeleteAfterDate)
 		return normalized
 	case *types.ProjectManifest:
 		if v == nil {
@@ -611,6 +1216,131 @@ func (d *DiffEngine) normalizeForComparison(resource interface{}) interface{} {
 		normalized := *v
 		normalized.Status = nil
 		return normalized
+	case *types.LDAPConfigurationManifest:
+		if v == nil {
+			return nil
+		}
+		normalized := *v
+		normalized.Status = nil
+		// Atlas never returns the bind password, and discovery reports the project ID
+		normalized.Spec.BindPassword = ""
+		normalized.Spec.ProjectName = ""
+		normalized.Spec.DependsOn = nil
+		// Apply Atlas defaults so omitted fields don't show as drift
+		if normalized.Spec.Port == 0 {
+			normalized.Spec.Port = 636
+		}
+		authn, authz := true, false
+		if normalized.Spec.AuthenticationEnabled != nil {
+			authn = *normalized.Spec.AuthenticationEnabled
+		}
+		if normalized.Spec.AuthorizationEnabled != nil {
+			authz = *normalized.Spec.AuthorizationEnabled
+		}
+		normalized.Spec.AuthenticationEnabled = &authn
+		normalized.Spec.AuthorizationEnabled = &authz
+		return normalized
+	case *types.IntegrationManifest:
+		if v == nil {
+			return nil
+		}
+		// Only the spec is compared. Atlas returns credentials redacted, so secrets
+		// can't be compared and are blanked; discovery reports the project ID.
+		spec := v.Spec
+		spec.ProjectName = ""
+		spec.DependsOn = nil
+		spec.Type = strings.ToUpper(spec.Type)
+		spec.APIKey = ""
+		spec.ServiceKey = ""
+		spec.APIToken = ""
+		spec.RoutingKey = ""
+		spec.MicrosoftTeamsWebhookURL = ""
+		spec.URL = ""
+		spec.Secret = ""
+		spec.Password = ""
+		// Apply Atlas defaults so omitted fields don't show as drift
+		switch spec.Type {
+		case atlas.IntegrationTypePrometheus:
+			if spec.Enabled == nil {
+				enabled := true
+				spec.Enabled = &enabled
+			}
+		case atlas.IntegrationTypeDatadog:
+			latency, database := false, false
+			if spec.SendCollectionLatencyMetrics != nil {
+				latency = *spec.SendCollectionLatencyMetrics
+			}
+			if spec.SendDatabaseMetrics != nil {
+				database = *spec.SendDatabaseMetrics
+			}
+			spec.SendCollectionLatencyMetrics = &latency
+			spec.SendDatabaseMetrics = &database
+		}
+		if spec.Region == "" && (spec.Type == atlas.IntegrationTypeDatadog || spec.Type == atlas.IntegrationTypePagerDuty || spec.Type == atlas.IntegrationTypeOpsGenie) {
+			spec.Region = "US"
+		}
+		return spec
+	case *types.FederationSettingsManifest:
+		if v == nil {
+			return nil
+		}
+		// Only the spec is compared; federationSettingsId is resolved from orgId
+		spec := v.Spec
+		spec.FederationSettingsID = ""
+		spec.DependsOn = nil
+		idps := make([]types.IdentityProviderConfig, len(spec.IdentityProviders))
+		for i, idp := range spec.IdentityProviders {
+			idp.ID = ""
+			idp.IdpType = strings.ToUpper(idp.IdpType)
+			if idp.IdpType == "" {
+				idp.IdpType = "WORKFORCE"
+			}
+			idp.RequestedScopes = sortedStrings(idp.RequestedScopes)
+			idp.AssociatedDomains = sortedStrings(idp.AssociatedDomains)
+			idps[i] = idp
+		}
+		sort.Slice(idps, func(i, j int) bool { return idps[i].DisplayName < idps[j].DisplayName })
+		spec.IdentityProviders = idps
+		if spec.ConnectedOrg != nil {
+			connected := *spec.ConnectedOrg
+			connected.DataAccessIdentityProviders = sortedStrings(connected.DataAccessIdentityProviders)
+			connected.DomainAllowList = sortedStrings(connected.DomainAllowList)
+			connected.PostAuthRoleGrants = sortedStrings(connected.PostAuthRoleGrants)
+			spec.ConnectedOrg = &connected
+		}
+		return spec
+	case *types.RoleMappingManifest:
+		if v == nil {
+			return nil
+		}
+		// Only the spec is compared; discovery labels carry Atlas IDs
+		spec := v.Spec
+		spec.DependsOn = nil
+		spec.RoleAssignments = normalizeRoleAssignments(&v.Spec)
+		return spec
+	case *types.CollectionManifest:
+		if v == nil {
+			return nil
+		}
+		// Only the spec is compared; MongoDB reports defaults for omitted options
+		return normalizeCollectionSpec(v.Spec)
+	case *types.IndexManifest:
+		if v == nil {
+			return nil
+		}
+		// Only the spec is compared; discovered indexes are named after the index
+		return normalizeIndexSpec(v.Spec)
+	case *types.ViewManifest:
+		if v == nil {
+			return nil
+		}
+		// Only the spec is compared; pipelines are compared as the server reports them
+		return normalizeViewSpec(v.Spec)
+	case *types.MaterializedViewManifest:
+		if v == nil {
+			return nil
+		}
+		return normalizeMaterializedViewSpec(v.Spec)
 	default:
 		return resource
 	}
@@ -719,7 +1449,11 @@ func (d *DiffEngine) compareStructFields(path string, desired, current reflect.V
 		desiredField := desired.Field(i)
 		currentField := current.Field(i)
 
+		start := len(*changes)
 		d.compareFields(fieldPath, desiredField, currentField, changes)
+		if isSensitiveField(field) {
+			markSensitive((*changes)[start:])
+		}
 	}
 }
 
@@ -967,12 +1701,64 @@ func (d *DiffEngine) assessCreateImpact(op *Operation, impact *OperationImpact)
 	case types.KindNetworkAccess:
 		impact.EstimatedDuration = time.Second * 10
 		impact.RiskLevel = RiskLevelLow
+		d.appendNetworkAccessExpiryWarnings(op, impact)
 
 	case types.KindSearchIndex:
 		impact.EstimatedDuration = time.Minute * 2 // Search indexes can take time to build
 		impact.RiskLevel = RiskLevelLow
 		impact.Warnings = append(impact.Warnings, "Search index creation may take several minutes for large collections")
+
+	case types.KindLDAPConfiguration:
+		impact.EstimatedDuration = time.Minute * 2
+		impact.RiskLevel = RiskLevelMedium
+		impact.Warnings = append(impact.Warnings, "LDAP settings apply to every cluster in the project")
+
+	case types.KindIntegration:
+		impact.EstimatedDuration = time.Second * 30
+		impact.RiskLevel = RiskLevelLow
+
+	case types.KindFederationSettings:
+		impact.EstimatedDuration = time.Minute * 1
+		impact.RiskLevel = RiskLevelMedium
+		impact.Warnings = append(impact.Warnings, "Federation settings affect sign-in for every user of the organization")
+
+	case types.KindRoleMapping:
+		impact.EstimatedDuration = time.Second * 15
+		impact.RiskLevel = RiskLevelLow
+		d.appendRoleMappingWarnings(op, impact)
+
+	case types.KindCollection:
+		impact.EstimatedDuration = time.Second * 10
+		impact.RiskLevel = RiskLevelLow
+
+	case types.KindIndex:
+		impact.EstimatedDuration = time.Minute * 1
+		impact.RiskLevel = RiskLevelLow
+		impact.Warnings = append(impact.Warnings, "Index builds on large collections add load to the cluster")
+
+	case types.KindView:
+		impact.EstimatedDuration = time.Second * 10
+		impact.RiskLevel = RiskLevelLow
+
+	case types.KindMaterializedView:
+		impact.EstimatedDuration = time.Minute * 1
+		impact.RiskLevel = RiskLevelLow
+		impact.Warnings = append(impact.Warnings, "The pipeline runs on the whole source collection to fill the materialized view")
+	}
+}
+
+// appendNetworkAccessExpiryWarnings notes when the entry being written is temporary
+func (d *DiffEngine) appendNetworkAccessExpiryWarnings(op *Operation, impact *OperationImpact) {
+	_, expiry, ok := networkAccessExpiry(op.Desired)
+	if !ok {
+		return
 	}
+	remaining := time.Until(expiry).Truncate(time.Minute)
+	if remaining <= NetworkAccessExpiryWindow {
+		impact.Warnings = append(impact.Warnings, fmt.Sprintf("Temporary access entry expires in %s (%s)", remaining, expiry.UTC().Format(time.RFC3339)))
+		return
+	}
+	impact.Warnings = append(impact.Warnings, fmt.Sprintf("Temporary access entry expires at %s", expiry.UTC().Format(time.RFC3339)))
 }
 
 // assessUpdateImpact assesses the impact of update operations
@@ -993,11 +1779,75 @@ func (d *DiffEngine) assessUpdateImpact(op *Operation, impact *OperationImpact)
 	case types.KindNetworkAccess:
 		impact.EstimatedDuration = time.Second * 15
 		impact.RiskLevel = RiskLevelLow
+		impact.Warnings = append(impact.Warnings, "Atlas cannot edit access list entries; the entry is removed and re-added")
+		d.appendNetworkAccessExpiryWarnings(op, impact)
 
 	case types.KindSearchIndex:
 		impact.EstimatedDuration = time.Minute * 3 // Search index updates may require rebuild
 		impact.RiskLevel = RiskLevelMedium
 		impact.Warnings = append(impact.Warnings, "Search index updates may cause temporary query disruption")
+
+	case types.KindLDAPConfiguration:
+		impact.EstimatedDuration = time.Minute * 2
+		impact.RiskLevel = RiskLevelMedium
+		impact.Warnings = append(impact.Warnings, "LDAP changes may interrupt authentication for LDAP users")
+		if ldap, ok := op.Desired.(*types.LDAPConfigurationManifest); ok && ldap != nil && ldap.Spec.BindPassword != "" {
+			impact.Warnings = append(impact.Warnings, "bindPassword cannot be compared with Atlas, so the declared password is applied on every apply")
+		}
+
+	case types.KindIntegration:
+		impact.EstimatedDuration = time.Second * 30
+		impact.RiskLevel = RiskLevelLow
+		impact.Warnings = append(impact.Warnings, "Integration credentials are write-only; the stored values are replaced with the declared ones")
+
+	case types.KindFederationSettings:
+		impact.EstimatedDuration = time.Minute * 1
+		impact.RiskLevel = RiskLevelMedium
+		impact.Warnings = append(impact.Warnings, "Identity provider changes may interrupt federated sign-in")
+
+	case types.KindRoleMapping:
+		impact.EstimatedDuration = time.Second * 15
+		impact.RiskLevel = RiskLevelMedium
+		d.appendRoleMappingWarnings(op, impact)
+
+	case types.KindCollection:
+		impact.EstimatedDuration = time.Second * 10
+		impact.RiskLevel = RiskLevelMedium
+		desired, desiredOK := op.Desired.(*types.CollectionManifest)
+		current, currentOK := op.Current.(*types.CollectionManifest)
+		if desiredOK && currentOK {
+			if changed := CollectionImmutableChanges(&desired.Spec, &current.Spec); len(changed) > 0 {
+				impact.RiskLevel = RiskLevelHigh
+				impact.Warnings = append(impact.Warnings, fmt.Sprintf("%s cannot be changed on an existing collection; the update will fail", strings.Join(changed, ", ")))
+			}
+		}
+
+	case types.KindIndex:
+		impact.EstimatedDuration = time.Minute * 1
+		impact.RiskLevel = RiskLevelLow
+		desired, desiredOK := op.Desired.(*types.IndexManifest)
+		current, currentOK := op.Current.(*types.IndexManifest)
+		if desiredOK && currentOK && IndexRequiresRebuild(&desired.Spec, &current.Spec) {
+			impact.RiskLevel = RiskLevelMedium
+			impact.Warnings = append(impact.Warnings, "The index is dropped and rebuilt; queries using it are slower until the build completes")
+		}
+
+	case types.KindView:
+		impact.EstimatedDuration = time.Second * 10
+		impact.RiskLevel = RiskLevelLow
+		desired, desiredOK := op.Desired.(*types.ViewManifest)
+		current, currentOK := op.Current.(*types.ViewManifest)
+		if desiredOK && currentOK {
+			if changed := ViewImmutableChanges(&desired.Spec, &current.Spec); len(changed) > 0 {
+				impact.RiskLevel = RiskLevelHigh
+				impact.Warnings = append(impact.Warnings, fmt.Sprintf("%s cannot be changed on an existing view; the update will fail", strings.Join(changed, ", ")))
+			}
+		}
+
+	case types.KindMaterializedView:
+		impact.EstimatedDuration = time.Minute * 1
+		impact.RiskLevel = RiskLevelMedium
+		impact.Warnings = append(impact.Warnings, "The pipeline is rerun and merged into the existing results; documents it no longer produces are kept")
 	}
 }
 
@@ -1035,6 +1885,40 @@ func (d *DiffEngine) assessDeleteImpact(op *Operation, impact *OperationImpact)
 		impact.EstimatedDuration = time.Minute * 1
 		impact.RiskLevel = RiskLevelMedium
 		impact.Warnings = append(impact.Warnings, "Search index deletion will permanently remove search capabilities")
+
+	case types.KindLDAPConfiguration:
+		impact.IsDestructive = true
+		impact.EstimatedDuration = time.Minute * 2
+		impact.RiskLevel = RiskLevelHigh
+		impact.Warnings = append(impact.Warnings, "Disabling LDAP will block all LDAP-authenticated users")
+
+	case types.KindIntegration:
+		impact.EstimatedDuration = time.Second * 30
+		impact.RiskLevel = RiskLevelMedium
+		impact.Warnings = append(impact.Warnings, "Alert notifications and metrics sent through this integration will stop")
+
+	case types.KindRoleMapping:
+		impact.IsDestructive = true
+		impact.EstimatedDuration = time.Second * 15
+		impact.RiskLevel = RiskLevelHigh
+		d.appendRoleMappingWarnings(op, impact)
+
+	case types.KindIndex:
+		impact.IsDestructive = true
+		impact.EstimatedDuration = time.Second * 15
+		impact.RiskLevel = RiskLevelMedium
+		impact.Warnings = append(impact.Warnings, "Queries and unique constraints relying on this index are affected")
+	}
+}
+
+// appendRoleMappingWarnings lists the role assignments a role mapping operation grants and revokes
+func (d *DiffEngine) appendRoleMappingWarnings(op *Operation, impact *OperationImpact) {
+	added, removed := RoleMappingAssignmentChanges(roleMappingSpecs(op.Desired, op.Current))
+	if len(added) > 0 {
+		impact.Warnings = append(impact.Warnings, "Grants: "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		impact.Warnings = append(impact.Warnings, "Revokes: "+strings.Join(removed, ", "))
 	}
 }
 
@@ -118,7 +118,7 @@ func (f *DiffFormatter) FormatUnified(diff *Diff) string {
 
 // FormatJSON formats the diff as JSON
 func (f *DiffFormatter) FormatJSON(diff *Diff) (string, error) {
-	data, err := json.MarshalIndent(diff, "", "  ")
+	data, err := json.MarshalIndent(RedactDiff(diff), "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal diff to JSON: %w", err)
 	}
@@ -127,7 +127,7 @@ func (f *DiffFormatter) FormatJSON(diff *Diff) (string, error) {
 
 // FormatYAML formats the diff as YAML
 func (f *DiffFormatter) FormatYAML(diff *Diff) (string, error) {
-	data, err := yaml.Marshal(diff)
+	data, err := yaml.Marshal(RedactDiff(diff))
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal diff to YAML: %w", err)
 	}
@@ -257,15 +257,16 @@ func (f *DiffFormatter) formatFieldChanges(changes []FieldChange) string {
 
 	for _, change := range changes {
 		prefix := "    "
+		suffix := sensitiveSuffix(change)
 		switch change.Type {
 		case ChangeTypeAdd:
-			line := fmt.Sprintf("%s+ %s: %v", prefix, change.Path, change.NewValue)
+			line := fmt.Sprintf("%s+ %s: %v%s", prefix, change.Path, change.NewValue, suffix)
 			output.WriteString(f.colorize(line, ColorGreen))
 		case ChangeTypeRemove:
-			line := fmt.Sprintf("%s- %s: %v", prefix, change.Path, change.OldValue)
+			line := fmt.Sprintf("%s- %s: %v%s", prefix, change.Path, change.OldValue, suffix)
 			output.WriteString(f.colorize(line, ColorRed))
 		case ChangeTypeModify:
-			line := fmt.Sprintf("%s~ %s: %v → %v", prefix, change.Path, change.OldValue, change.NewValue)
+			line := fmt.Sprintf("%s~ %s: %v → %v%s", prefix, change.Path, change.OldValue, change.NewValue, suffix)
 			output.WriteString(f.colorize(line, ColorYellow))
 		}
 		output.WriteString("\n")
@@ -274,6 +275,14 @@ func (f *DiffFormatter) formatFieldChanges(changes []FieldChange) string {
 	return output.String()
 }
 
+// sensitiveSuffix annotates changes to credential fields, whose values are already masked
+func sensitiveSuffix(change FieldChange) string {
+	if change.Sensitive {
+		return " (sensitive)"
+	}
+	return ""
+}
+
 func (f *DiffFormatter) formatWarnings(warnings []string) string {
 	var output strings.Builder
 
@@ -299,15 +308,16 @@ func (f *DiffFormatter) formatUnifiedOperation(op Operation) string {
 		// Show field changes
 		for _, change := range op.FieldChanges {
 			output.WriteString("\n")
+			suffix := sensitiveSuffix(change)
 			switch change.Type {
 			case ChangeTypeAdd:
-				output.WriteString(f.colorize(fmt.Sprintf("+    %s: %v", change.Path, change.NewValue), ColorGreen))
+				output.WriteString(f.colorize(fmt.Sprintf("+    %s: %v%s", change.Path, change.NewValue, suffix), ColorGreen))
 			case ChangeTypeRemove:
-				output.WriteString(f.colorize(fmt.Sprintf("-    %s: %v", change.Path, change.OldValue), ColorRed))
+				output.WriteString(f.colorize(fmt.Sprintf("-    %s: %v%s", change.Path, change.OldValue, suffix), ColorRed))
 			case ChangeTypeModify:
-				output.WriteString(f.colorize(fmt.Sprintf("-    %s: %v", change.Path, change.OldValue), ColorRed))
+				output.WriteString(f.colorize(fmt.Sprintf("-    %s: %v%s", change.Path, change.OldValue, suffix), ColorRed))
 				output.WriteString("\n")
-				output.WriteString(f.colorize(fmt.Sprintf("+    %s: %v", change.Path, change.NewValue), ColorGreen))
+				output.WriteString(f.colorize(fmt.Sprintf("+    %s: %v%s", change.Path, change.NewValue, suffix), ColorGreen))
 			}
 		}
 	case OperationNoChange:
@@ -1,6 +1,7 @@
 package apply
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -501,6 +502,167 @@ func TestDiffEngine_NetworkAccess(t *testing.T) {
 	}
 }
 
+func TestDiffEngine_LDAPConfiguration(t *testing.T) {
+	engine := NewDiffEngine()
+
+	desired := &ProjectState{
+		LDAPConfigurations: []types.LDAPConfigurationManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "corp-ldap"},
+				Spec: types.LDAPConfigurationSpec{
+					ProjectName:  "my-project",
+					Hostname:     "ldap.example.com",
+					BindUsername: "CN=svc,DC=example,DC=com",
+					BindPassword: "secret",
+				},
+			},
+		},
+	}
+
+	// Not configured in Atlas yet
+	diff, err := engine.ComputeProjectDiff(desired, &ProjectState{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationCreate {
+		t.Fatalf("Expected 1 CREATE operation, got %+v", diff.Operations)
+	}
+
+	// Discovered settings with Atlas defaults match, but the declared bind password can't
+	// be compared, so it is always applied
+	enabled, disabled := true, false
+	current := &ProjectState{
+		LDAPConfigurations: []types.LDAPConfigurationManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "ldap"},
+				Spec: types.LDAPConfigurationSpec{
+					ProjectName:           "5f1d7a2b3c4d5e6f7a8b9c0d",
+					Hostname:              "ldap.example.com",
+					Port:                  636,
+					BindUsername:          "CN=svc,DC=example,DC=com",
+					AuthenticationEnabled: &enabled,
+					AuthorizationEnabled:  &disabled,
+					UserToDNMapping:       []types.UserToDNMappingConfig{{Match: "(.+)", Substitution: "CN={0},DC=example,DC=com"}},
+				},
+			},
+		},
+	}
+	diff, err = engine.ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationUpdate {
+		t.Fatalf("Expected 1 UPDATE operation for the bind password, got %+v", diff.Operations)
+	}
+	op := diff.Operations[0]
+	if len(op.FieldChanges) != 1 || op.FieldChanges[0].Path != "Spec.BindPassword" || !op.FieldChanges[0].Sensitive || op.FieldChanges[0].NewValue == "secret" {
+		t.Errorf("Expected a single masked bind password change, got %+v", op.FieldChanges)
+	}
+	if op.Impact == nil || !strings.Contains(strings.Join(op.Impact.Warnings, "\n"), "bindPassword cannot be compared") {
+		t.Errorf("Expected a warning that the bind password cannot be compared, got %+v", op.Impact)
+	}
+
+	// Without a declared password the discovered settings show no drift, and mappings
+	// configured in Atlas but not declared are left alone
+	withoutPassword := *desired
+	withoutPassword.LDAPConfigurations = []types.LDAPConfigurationManifest{desired.LDAPConfigurations[0]}
+	withoutPassword.LDAPConfigurations[0].Spec.BindPassword = ""
+	diff, err = engine.ComputeProjectDiff(&withoutPassword, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationNoChange {
+		t.Fatalf("Expected 1 NO_CHANGE operation, got %+v", diff.Operations)
+	}
+
+	// Omitting the kind from the desired state never disables LDAP
+	diff, err = engine.ComputeProjectDiff(&ProjectState{}, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 0 {
+		t.Errorf("Expected 0 operations, got %d", len(diff.Operations))
+	}
+}
+
+func TestDiffEngine_Integrations(t *testing.T) {
+	engine := NewDiffEngine()
+
+	desired := &ProjectState{
+		Integrations: []types.IntegrationManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "datadog-eu"},
+				Spec: types.IntegrationSpec{
+					ProjectName: "my-project",
+					Type:        "DATADOG",
+					APIKey:      "0123456789abcdef0123456789abcdef",
+					Region:      "EU",
+				},
+			},
+		},
+	}
+
+	// Atlas returns the API key redacted; matching settings should not show drift
+	current := &ProjectState{
+		Integrations: []types.IntegrationManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "datadog"},
+				Spec: types.IntegrationSpec{
+					ProjectName: "5f1d7a2b3c4d5e6f7a8b9c0d",
+					Type:        "DATADOG",
+					APIKey:      "****************************cdef",
+					Region:      "EU",
+				},
+			},
+			{
+				// Integrations of undeclared types are never removed
+				Metadata: types.ResourceMetadata{Name: "slack"},
+				Spec:     types.IntegrationSpec{Type: "SLACK", ChannelName: "#alerts"},
+			},
+		},
+	}
+	diff, err := engine.ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationNoChange {
+		t.Fatalf("Expected 1 NO_CHANGE operation, got %+v", diff.Operations)
+	}
+
+	// A region change updates the integration and reports the key as a masked, sensitive change
+	current.Integrations[0].Spec.Region = "US"
+	diff, err = engine.ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationUpdate {
+		t.Fatalf("Expected 1 UPDATE operation, got %+v", diff.Operations)
+	}
+	var sawKey bool
+	for _, change := range diff.Operations[0].FieldChanges {
+		if change.Path != "Spec.APIKey" {
+			continue
+		}
+		sawKey = true
+		if !change.Sensitive {
+			t.Errorf("Expected API key change to be marked sensitive")
+		}
+		if change.NewValue == desired.Integrations[0].Spec.APIKey {
+			t.Errorf("Expected API key to be masked, got %v", change.NewValue)
+		}
+	}
+	if !sawKey {
+		t.Errorf("Expected a Spec.APIKey field change, got %+v", diff.Operations[0].FieldChanges)
+	}
+
+	// Two integrations of the same type are rejected
+	desired.Integrations = append(desired.Integrations, desired.Integrations[0])
+	desired.Integrations[1].Metadata.Name = "datadog-us"
+	if _, err := engine.ComputeProjectDiff(desired, current); err == nil {
+		t.Errorf("Expected error for duplicate integration type")
+	}
+}
+
 func TestDiffEngine_RiskLevelComparison(t *testing.T) {
 	engine := NewDiffEngine()
 
@@ -521,3 +683,217 @@ func TestDiffEngine_RiskLevelComparison(t *testing.T) {
 		}
 	}
 }
+
+func TestDiffEngine_RoleMappings(t *testing.T) {
+	const orgID = "5f1d7f4b3a1e2c0012345678"
+	const projectID = "6a1d7f4b3a1e2c0012345678"
+	engine := NewDiffEngine()
+
+	desired := &ProjectState{
+		RoleMappings: []types.RoleMappingManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "dbas"},
+				Spec: types.RoleMappingSpec{
+					OrgID:             orgID,
+					ExternalGroupName: "okta-dbas",
+					RoleAssignments: []types.RoleAssignmentConfig{
+						{Role: "ORG_MEMBER"},
+						{Role: "GROUP_OWNER", ProjectID: projectID},
+					},
+				},
+			},
+		},
+	}
+	current := &ProjectState{
+		RoleMappings: []types.RoleMappingManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "okta-dbas", Labels: map[string]string{RoleMappingIDLabel: "rm1"}},
+				Spec: types.RoleMappingSpec{
+					OrgID:             orgID,
+					ExternalGroupName: "okta-dbas",
+					RoleAssignments: []types.RoleAssignmentConfig{
+						{Role: "GROUP_READ_ONLY", ProjectID: projectID},
+						{Role: "ORG_MEMBER", OrgID: orgID},
+					},
+				},
+			},
+			{
+				Metadata: types.ResourceMetadata{Name: "okta-legacy"},
+				Spec: types.RoleMappingSpec{
+					OrgID:             orgID,
+					ExternalGroupName: "okta-legacy",
+					RoleAssignments:   []types.RoleAssignmentConfig{{Role: "ORG_OWNER", OrgID: orgID}},
+				},
+			},
+			{
+				// Mappings of undeclared organizations are never touched
+				Metadata: types.ResourceMetadata{Name: "other-org"},
+				Spec: types.RoleMappingSpec{
+					OrgID:             "7b1d7f4b3a1e2c0012345678",
+					ExternalGroupName: "other",
+					RoleAssignments:   []types.RoleAssignmentConfig{{Role: "ORG_MEMBER"}},
+				},
+			},
+		},
+	}
+
+	diff, err := engine.ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 2 {
+		t.Fatalf("Expected 2 operations, got %+v", diff.Operations)
+	}
+
+	update := diff.Operations[0]
+	if update.Type != OperationUpdate || update.ResourceName != "dbas" {
+		t.Fatalf("Expected UPDATE of dbas, got %s %s", update.Type, update.ResourceName)
+	}
+	if len(update.FieldChanges) != 2 {
+		t.Fatalf("Expected one granted and one revoked assignment, got %+v", update.FieldChanges)
+	}
+	if update.FieldChanges[0].Type != ChangeTypeAdd || update.FieldChanges[0].NewValue != "GROUP_OWNER on project "+projectID {
+		t.Errorf("Unexpected grant: %+v", update.FieldChanges[0])
+	}
+	if update.FieldChanges[1].Type != ChangeTypeRemove || update.FieldChanges[1].OldValue != "GROUP_READ_ONLY on project "+projectID {
+		t.Errorf("Unexpected revoke: %+v", update.FieldChanges[1])
+	}
+
+	remove := diff.Operations[1]
+	if remove.Type != OperationDelete || remove.ResourceName != "okta-legacy" {
+		t.Fatalf("Expected DELETE of okta-legacy, got %s %s", remove.Type, remove.ResourceName)
+	}
+	if !remove.Impact.IsDestructive || len(remove.Impact.Warnings) != 1 || remove.Impact.Warnings[0] != "Revokes: ORG_OWNER on org "+orgID {
+		t.Errorf("Unexpected delete impact: %+v", remove.Impact)
+	}
+
+	// Same assignments in a different order and with the implied orgId are unchanged
+	current.RoleMappings[0].Spec.RoleAssignments[0].Role = "GROUP_OWNER"
+	engine.PreserveExisting = true
+	diff, err = engine.ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationNoChange {
+		t.Fatalf("Expected 1 NO_CHANGE operation, got %+v", diff.Operations)
+	}
+}
+
+func TestDiffEngine_FederationSettings(t *testing.T) {
+	const orgID = "5f1d7f4b3a1e2c0012345678"
+	engine := NewDiffEngine()
+
+	desired := &ProjectState{
+		FederationSettings: []types.FederationSettingsManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "corp-sso"},
+				Spec: types.FederationSettingsSpec{
+					OrgID: orgID,
+					IdentityProviders: []types.IdentityProviderConfig{
+						{DisplayName: "Okta", IssuerURI: "https://example.okta.com", Audience: "atlas", ClientID: "abc"},
+					},
+				},
+			},
+		},
+	}
+	// Discovery reports extra providers and Atlas-populated fields that aren't managed
+	current := &ProjectState{
+		FederationSettings: []types.FederationSettingsManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "corp-sso", Labels: map[string]string{FederationSettingsIDLabel: "fed1"}},
+				Spec: types.FederationSettingsSpec{
+					OrgID:                orgID,
+					FederationSettingsID: "fed1",
+					IdentityProviders: []types.IdentityProviderConfig{
+						{DisplayName: "Okta", ID: "idp1", IdpType: "WORKFORCE", IssuerURI: "https://example.okta.com", Audience: "atlas", ClientID: "abc", UserClaim: "sub"},
+						{DisplayName: "Legacy SAML", ID: "idp2"},
+					},
+					ConnectedOrg: &types.ConnectedOrgSettings{DomainAllowList: []string{"example.com"}},
+				},
+			},
+		},
+	}
+
+	diff, err := engine.ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationNoChange {
+		t.Fatalf("Expected 1 NO_CHANGE operation, got %+v", diff.Operations)
+	}
+
+	desired.FederationSettings[0].Spec.IdentityProviders[0].Audience = "atlas-prod"
+	diff, err = engine.ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationUpdate {
+		t.Fatalf("Expected 1 UPDATE operation, got %+v", diff.Operations)
+	}
+
+	// Omitting the kind never changes federation settings
+	diff, err = engine.ComputeProjectDiff(&ProjectState{}, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 0 {
+		t.Errorf("Expected 0 operations, got %d", len(diff.Operations))
+	}
+}
+
+func TestDiffEngine_NetworkAccessTTL(t *testing.T) {
+	engine := NewDiffEngine()
+
+	desired := &ProjectState{
+		NetworkAccess: []types.NetworkAccessManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "203.0.113.10"},
+				Spec:     types.NetworkAccessSpec{IPAddress: "203.0.113.10", Comment: "contractor", TTL: "8h"},
+			},
+		},
+	}
+
+	// A new entry with a ttl is created with a concrete expiry about 8 hours out
+	diff, err := engine.ComputeProjectDiff(desired, &ProjectState{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationCreate {
+		t.Fatalf("Expected 1 CREATE operation, got %+v", diff.Operations)
+	}
+	created := diff.Operations[0].Desired.(*types.NetworkAccessManifest)
+	expiry, err := time.Parse(time.RFC3339, created.Spec.DeleteAfterDate)
+	if err != nil {
+		t.Fatalf("Expected a resolved deleteAfterDate, got %q", created.Spec.DeleteAfterDate)
+	}
+	if d := time.Until(expiry); d < 7*time.Hour || d > 8*time.Hour {
+		t.Errorf("Expected expiry about 8h out, got %s", d)
+	}
+	if desired.NetworkAccess[0].Spec.TTL != "8h" {
+		t.Error("Desired state should not be modified by ttl resolution")
+	}
+	if diff.Operations[0].Impact == nil || len(diff.Operations[0].Impact.Warnings) == 0 {
+		t.Error("Expected an expiry warning on the create impact")
+	}
+
+	// An existing temporary entry keeps its expiry instead of drifting on every plan
+	current := &ProjectState{
+		NetworkAccess: []types.NetworkAccessManifest{
+			{
+				Metadata: types.ResourceMetadata{Name: "203.0.113.10"},
+				Spec: types.NetworkAccessSpec{
+					IPAddress:       "203.0.113.10",
+					Comment:         "contractor",
+					DeleteAfterDate: time.Now().Add(3 * time.Hour).In(time.FixedZone("CEST", 2*3600)).Format(time.RFC3339),
+				},
+			},
+		},
+	}
+	diff, err = engine.ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationNoChange {
+		t.Fatalf("Expected 1 NO_CHANGE operation, got %+v", diff.Operations)
+	}
+}
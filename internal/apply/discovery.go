@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -45,8 +46,30 @@ type ProjectState struct {
 	NetworkAccess []types.NetworkAccessManifest `json:"networkAccess"`
 	SearchIndexes []types.SearchIndexManifest   `json:"searchIndexes"`
 	VPCEndpoints  []types.VPCEndpointManifest   `json:"vpcEndpoints"`
-	Fingerprint   string                        `json:"fingerprint"`
-	DiscoveredAt  time.Time                     `json:"discoveredAt"`
+	// LDAPConfigurations holds the project LDAP settings (at most one entry)
+	LDAPConfigurations []types.LDAPConfigurationManifest `json:"ldapConfigurations,omitempty"`
+	// Integrations holds the project third-party service integrations (one per type)
+	Integrations []types.IntegrationManifest `json:"integrations,omitempty"`
+	// FederationSettings and RoleMappings are organization-scoped and only populated
+	// for organizations declared in the desired state (see DiscoverOrganizationResources)
+	FederationSettings []types.FederationSettingsManifest `json:"federationSettings,omitempty"`
+	RoleMappings       []types.RoleMappingManifest        `json:"roleMappings,omitempty"`
+	Fingerprint        string                             `json:"fingerprint"`
+	DiscoveredAt       time.Time                          `json:"discoveredAt"`
+	// Collections and Indexes are only populated for collections declared in the desired
+	// state (see DiscoverDatabaseResources)
+	Collections []types.CollectionManifest `json:"collections,omitempty"`
+	Indexes     []types.IndexManifest      `json:"indexes,omitempty"`
+	// Views and MaterializedViews are likewise only populated for views declared in the
+	// desired state
+	Views             []types.ViewManifest             `json:"views,omitempty"`
+	MaterializedViews []types.MaterializedViewManifest `json:"materializedViews,omitempty"`
+	// DataSources holds the read-only lookups declared in a desired state; they are never diffed
+	DataSources []types.DataSourceManifest `json:"dataSources,omitempty"`
+	// Hooks holds the hooks declared in the ApplyDocuments of a desired state
+	Hooks *OperationHooks `json:"-"`
+	// Outputs holds the outputs declared in the ApplyDocuments of a desired state
+	Outputs map[string]string `json:"-"`
 }
 
 // AtlasStateDiscovery implements StateDiscovery using Atlas services
@@ -58,6 +81,10 @@ type AtlasStateDiscovery struct {
 	networkService   *atlas.NetworkAccessListsService
 	searchService    *atlas.SearchService
 	vpcService       *atlas.VPCEndpointsService
+	ldapService      *atlas.LDAPConfigurationService
+	integrations     *atlas.IntegrationsService
+	federation       *atlas.FederationService
+	databaseClient   DatabaseClient
 	rateLimiter      *RateLimiter
 	maxConcurrentOps int
 }
@@ -72,6 +99,9 @@ func NewAtlasStateDiscovery(client *atlasclient.Client) *AtlasStateDiscovery {
 		networkService:   atlas.NewNetworkAccessListsService(client),
 		searchService:    atlas.NewSearchService(client),
 		vpcService:       atlas.NewVPCEndpointsService(client),
+		ldapService:      atlas.NewLDAPConfigurationService(client),
+		integrations:     atlas.NewIntegrationsService(client),
+		federation:       atlas.NewFederationService(client),
 		rateLimiter:      NewRateLimiter(10, time.Second), // 10 requests per second
 		maxConcurrentOps: 5,                               // Maximum 5 concurrent API calls
 	}
@@ -105,6 +135,8 @@ func (d *AtlasStateDiscovery) DiscoverProject(ctx context.Context, projectID str
 	networkCh := make(chan result, 1)
 	searchCh := make(chan result, 1)
 	vpceCh := make(chan result, 1)
+	ldapCh := make(chan result, 1)
+	integrationsCh := make(chan result, 1)
 
 	// Discover project settings first
 	wg.Add(1)
@@ -169,6 +201,28 @@ func (d *AtlasStateDiscovery) DiscoverProject(ctx context.Context, projectID str
 		vpceCh <- result{data: manifests, err: err}
 	}()
 
+	// Discover LDAP configuration
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		semaphore <- struct{}{}
+		defer func() { <-semaphore }()
+
+		ldapConfigs, err := d.DiscoverLDAPConfiguration(ctx, projectID)
+		ldapCh <- result{data: ldapConfigs, err: err}
+	}()
+
+	// Discover third-party integrations
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		semaphore <- struct{}{}
+		defer func() { <-semaphore }()
+
+		integrations, err := d.DiscoverIntegrations(ctx, projectID)
+		integrationsCh <- result{data: integrations, err: err}
+	}()
+
 	// Wait for project discovery to complete first
 	wg.Wait()
 	close(projectCh)
@@ -207,6 +261,8 @@ func (d *AtlasStateDiscovery) DiscoverProject(ctx context.Context, projectID str
 	close(networkCh)
 	close(searchCh)
 	close(vpceCh)
+	close(ldapCh)
+	close(integrationsCh)
 
 	// Clusters
 	clustersResult := <-clustersCh
@@ -248,6 +304,20 @@ func (d *AtlasStateDiscovery) DiscoverProject(ctx context.Context, projectID str
 		projectState.VPCEndpoints = vpceResult.data.([]types.VPCEndpointManifest)
 	}
 
+	// LDAP configuration is optional; projects without LDAP access (or on tiers
+	// that don't support it) should not fail discovery
+	ldapResult := <-ldapCh
+	if ldapResult.err == nil && ldapResult.data != nil {
+		projectState.LDAPConfigurations = ldapResult.data.([]types.LDAPConfigurationManifest)
+	}
+
+	// Third-party integrations are optional as well; API keys without integration
+	// access should not fail discovery
+	integrationsResult := <-integrationsCh
+	if integrationsResult.err == nil && integrationsResult.data != nil {
+		projectState.Integrations = integrationsResult.data.([]types.IntegrationManifest)
+	}
+
 	// Return aggregated errors if any
 	if len(errors) > 0 {
 		return projectState, &DiscoveryError{
@@ -450,6 +520,169 @@ func (d *AtlasStateDiscovery) DiscoverVPCEndpoints(ctx context.Context, projectI
 	return manifests, nil
 }
 
+// DiscoverLDAPConfiguration fetches the project LDAP configuration. A project without
+// LDAP configured yields no manifests.
+func (d *AtlasStateDiscovery) DiscoverLDAPConfiguration(ctx context.Context, projectID string) ([]types.LDAPConfigurationManifest, error) {
+	if d.ldapService == nil {
+		return nil, nil
+	}
+	if err := d.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit exceeded: %w", err)
+	}
+
+	security, err := d.ldapService.Get(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch LDAP configuration: %w", err)
+	}
+	if security == nil || security.Ldap == nil || security.Ldap.GetHostname() == "" {
+		return nil, nil
+	}
+
+	spec := atlas.ConvertLDAPFromAtlas(security.Ldap, projectID)
+	manifest := types.LDAPConfigurationManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindLDAPConfiguration,
+		Metadata:   types.ResourceMetadata{Name: "ldap"},
+		Spec:       *spec,
+	}
+	return []types.LDAPConfigurationManifest{manifest}, nil
+}
+
+// DiscoverIntegrations fetches the project third-party service integrations. Each
+// integration is named after its type; credentials are returned redacted by Atlas.
+func (d *AtlasStateDiscovery) DiscoverIntegrations(ctx context.Context, projectID string) ([]types.IntegrationManifest, error) {
+	if d.integrations == nil {
+		return nil, nil
+	}
+	if err := d.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit exceeded: %w", err)
+	}
+
+	integrations, err := d.integrations.List(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list third-party integrations: %w", err)
+	}
+
+	manifests := make([]types.IntegrationManifest, 0, len(integrations))
+	for _, integration := range integrations {
+		spec := atlas.ConvertIntegrationFromAtlas(integration, projectID)
+		manifests = append(manifests, types.IntegrationManifest{
+			APIVersion: types.APIVersionV1,
+			Kind:       types.KindIntegration,
+			Metadata:   types.ResourceMetadata{Name: strings.ToLower(spec.Type)},
+			Spec:       *spec,
+		})
+	}
+	return manifests, nil
+}
+
+// RoleMappingIDLabel is the label carrying the Atlas ID of a discovered role mapping.
+const RoleMappingIDLabel = "atlas.mongodb.com/role-mapping-id"
+
+// FederationSettingsIDLabel is the label carrying the federation settings ID of a discovered organization resource.
+const FederationSettingsIDLabel = "atlas.mongodb.com/federation-settings-id"
+
+// DiscoverOrganizationResources fetches the federation settings and role mappings of every
+// organization referenced by FederationSettings or RoleMapping resources in the desired state
+// and records them in current. Organizations that are not declared are never inspected.
+func (d *AtlasStateDiscovery) DiscoverOrganizationResources(ctx context.Context, desired, current *ProjectState) error {
+	if desired == nil || current == nil || d.federation == nil {
+		return nil
+	}
+
+	settingsByOrg := make(map[string]*types.FederationSettingsManifest)
+	var orgIDs []string
+	seen := make(map[string]bool)
+	addOrg := func(orgID string) {
+		if orgID != "" && !seen[orgID] {
+			seen[orgID] = true
+			orgIDs = append(orgIDs, orgID)
+		}
+	}
+	for i := range desired.FederationSettings {
+		settingsByOrg[desired.FederationSettings[i].Spec.OrgID] = &desired.FederationSettings[i]
+		addOrg(desired.FederationSettings[i].Spec.OrgID)
+	}
+	for _, mapping := range desired.RoleMappings {
+		addOrg(mapping.Spec.OrgID)
+	}
+
+	for _, orgID := range orgIDs {
+		if err := d.rateLimiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limit exceeded: %w", err)
+		}
+		settings, err := d.federation.GetSettings(ctx, orgID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch federation settings for organization %s: %w", orgID, err)
+		}
+		federationSettingsID := settings.GetId()
+		if federationSettingsID == "" {
+			return fmt.Errorf("organization %s is not connected to a federation", orgID)
+		}
+
+		if desiredSettings, ok := settingsByOrg[orgID]; ok {
+			manifest, err := d.discoverFederationSettings(ctx, federationSettingsID, orgID, desiredSettings.Metadata.Name)
+			if err != nil {
+				return err
+			}
+			current.FederationSettings = append(current.FederationSettings, *manifest)
+		}
+
+		mappings, err := d.federation.ListRoleMappings(ctx, federationSettingsID, orgID)
+		if err != nil {
+			return fmt.Errorf("failed to list role mappings for organization %s: %w", orgID, err)
+		}
+		for _, mapping := range mappings {
+			current.RoleMappings = append(current.RoleMappings, types.RoleMappingManifest{
+				APIVersion: types.APIVersionV1,
+				Kind:       types.KindRoleMapping,
+				Metadata: types.ResourceMetadata{
+					Name: mapping.ExternalGroupName,
+					Labels: map[string]string{
+						RoleMappingIDLabel:        mapping.GetId(),
+						FederationSettingsIDLabel: federationSettingsID,
+					},
+				},
+				Spec: atlas.ConvertRoleMappingFromAtlas(mapping, orgID),
+			})
+		}
+	}
+
+	return nil
+}
+
+// discoverFederationSettings fetches the identity providers and connected organization
+// configuration of a federation, named after the desired manifest.
+func (d *AtlasStateDiscovery) discoverFederationSettings(ctx context.Context, federationSettingsID, orgID, name string) (*types.FederationSettingsManifest, error) {
+	providers, err := d.federation.ListIdentityProviders(ctx, federationSettingsID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identity providers: %w", err)
+	}
+	spec := types.FederationSettingsSpec{OrgID: orgID, FederationSettingsID: federationSettingsID}
+	names := make(map[string]string, len(providers))
+	for _, provider := range providers {
+		idp := atlas.ConvertIdentityProviderFromAtlas(provider)
+		names[idp.ID] = idp.DisplayName
+		spec.IdentityProviders = append(spec.IdentityProviders, idp)
+	}
+
+	connected, err := d.federation.GetConnectedOrg(ctx, federationSettingsID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch connected organization %s: %w", orgID, err)
+	}
+	spec.ConnectedOrg = atlas.ConvertConnectedOrgFromAtlas(connected, names)
+
+	return &types.FederationSettingsManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindFederationSettings,
+		Metadata: types.ResourceMetadata{
+			Name:   name,
+			Labels: map[string]string{FederationSettingsIDLabel: federationSettingsID},
+		},
+		Spec: spec,
+	}, nil
+}
+
 // GenerateStateFingerprint generates a SHA256 hash of the project state for change detection
 func GenerateStateFingerprint(state *ProjectState) (string, error) {
 	// Create a copy of the state without the fingerprint and timestamp for consistent hashing
@@ -137,6 +137,12 @@ func NewDryRunExecutor(mode DryRunMode) *DryRunExecutor {
 	}
 }
 
+// WithTimingEstimator replaces the estimator used for operation durations
+func (dre *DryRunExecutor) WithTimingEstimator(estimator TimingEstimator) *DryRunExecutor {
+	dre.timingEstimator = estimator
+	return dre
+}
+
 // Execute performs a dry-run simulation of the plan
 func (dre *DryRunExecutor) Execute(ctx context.Context, plan *Plan) (*DryRunResult, error) {
 	startTime := time.Now()
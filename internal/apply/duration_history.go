@@ -0,0 +1,307 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// DefaultMaxDurationSamples is how many recent samples are kept per duration key
+const DefaultMaxDurationSamples = 50
+
+// durationHistoryVersion is the on-disk format version of the duration history file
+const durationHistoryVersion = 1
+
+// DurationKey identifies a class of operations expected to take a similar amount of time
+type DurationKey struct {
+	Kind      types.ResourceKind `json:"kind"`
+	Operation OperationType      `json:"operation"`
+	Provider  string             `json:"provider,omitempty"`
+	Region    string             `json:"region,omitempty"`
+	Tier      string             `json:"tier,omitempty"`
+}
+
+// String renders the key as Kind/Operation[/Provider/Region/Tier]
+func (k DurationKey) String() string {
+	parts := []string{string(k.Kind), string(k.Operation)}
+	if k.Provider != "" || k.Region != "" || k.Tier != "" {
+		parts = append(parts, k.Provider, k.Region, k.Tier)
+	}
+	return strings.Join(parts, "/")
+}
+
+// fallbacks returns the key followed by progressively coarser keys: without tier,
+// without region and finally kind and operation only
+func (k DurationKey) fallbacks() []DurationKey {
+	keys := []DurationKey{k}
+	if k.Tier != "" {
+		keys = append(keys, DurationKey{Kind: k.Kind, Operation: k.Operation, Provider: k.Provider, Region: k.Region})
+	}
+	if k.Region != "" {
+		keys = append(keys, DurationKey{Kind: k.Kind, Operation: k.Operation, Provider: k.Provider})
+	}
+	if k.Provider != "" {
+		keys = append(keys, DurationKey{Kind: k.Kind, Operation: k.Operation})
+	}
+	return keys
+}
+
+// matches reports whether a recorded key falls under this (possibly coarser) key
+func (k DurationKey) matches(recorded DurationKey) bool {
+	return k.Kind == recorded.Kind && k.Operation == recorded.Operation &&
+		(k.Provider == "" || k.Provider == recorded.Provider) &&
+		(k.Region == "" || k.Region == recorded.Region) &&
+		(k.Tier == "" || k.Tier == recorded.Tier)
+}
+
+// DurationKeyFor derives the duration key of a planned operation. Clusters are keyed by
+// provider, region and tier, which dominate how long they take to provision.
+func DurationKeyFor(op Operation) DurationKey {
+	key := DurationKey{Kind: op.ResourceType, Operation: op.Type}
+	resource := op.Desired
+	if resource == nil {
+		resource = op.Current
+	}
+	if cluster, ok := resource.(*types.ClusterManifest); ok && cluster != nil {
+		key.Provider = strings.ToUpper(cluster.Spec.Provider)
+		key.Region = strings.ToUpper(cluster.Spec.Region)
+		key.Tier = strings.ToUpper(cluster.Spec.InstanceSize)
+	}
+	return key
+}
+
+// DurationSample is one observed operation duration
+type DurationSample struct {
+	Duration   time.Duration `json:"duration"`
+	RecordedAt time.Time     `json:"recordedAt"`
+}
+
+// DurationEstimate summarizes the recorded durations for a key
+type DurationEstimate struct {
+	Key     DurationKey   `json:"key"`
+	P50     time.Duration `json:"p50"`
+	P90     time.Duration `json:"p90"`
+	Min     time.Duration `json:"min"`
+	Max     time.Duration `json:"max"`
+	Samples int           `json:"samples"`
+}
+
+type durationSeries struct {
+	Key     DurationKey      `json:"key"`
+	Samples []DurationSample `json:"samples"`
+}
+
+type durationHistoryFile struct {
+	Version int               `json:"version"`
+	Series  []*durationSeries `json:"series"`
+}
+
+// DurationHistory is a local store of real operation durations recorded by the executor.
+// It is safe for concurrent use.
+type DurationHistory struct {
+	path       string
+	maxSamples int
+
+	mu     sync.RWMutex
+	series map[string]*durationSeries
+	dirty  bool
+}
+
+// DefaultDurationHistoryPath returns ~/.matlas/history/durations.json
+func DefaultDurationHistoryPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".matlas", "history", "durations.json")
+}
+
+// NewDurationHistory creates an empty history that saves to path
+func NewDurationHistory(path string) *DurationHistory {
+	return &DurationHistory{
+		path:       path,
+		maxSamples: DefaultMaxDurationSamples,
+		series:     make(map[string]*durationSeries),
+	}
+}
+
+// LoadDurationHistory reads the history at path. A missing file yields an empty history.
+func LoadDurationHistory(path string) (*DurationHistory, error) {
+	history := NewDurationHistory(path)
+	// #nosec G304 -- path is the history file location chosen by the CLI
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, fmt.Errorf("failed to read duration history: %w", err)
+	}
+
+	var file durationHistoryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse duration history %s: %w", path, err)
+	}
+	if file.Version > durationHistoryVersion {
+		return nil, fmt.Errorf("duration history %s has unsupported version %d", path, file.Version)
+	}
+	for _, s := range file.Series {
+		if s != nil {
+			history.series[s.Key.String()] = s
+		}
+	}
+	return history, nil
+}
+
+// Record adds an observed duration, keeping only the most recent samples per key
+func (h *DurationHistory) Record(key DurationKey, duration time.Duration, at time.Time) {
+	if h == nil || duration <= 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key.String()]
+	if !ok {
+		s = &durationSeries{Key: key}
+		h.series[key.String()] = s
+	}
+	s.Samples = append(s.Samples, DurationSample{Duration: duration, RecordedAt: at.UTC()})
+	if len(s.Samples) > h.maxSamples {
+		s.Samples = s.Samples[len(s.Samples)-h.maxSamples:]
+	}
+	h.dirty = true
+}
+
+// Estimate returns percentile estimates for a key. When the exact key has no samples it
+// falls back to coarser keys (dropping tier, then region, then provider).
+func (h *DurationHistory) Estimate(key DurationKey) (DurationEstimate, bool) {
+	if h == nil {
+		return DurationEstimate{}, false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, candidate := range key.fallbacks() {
+		var durations []time.Duration
+		for _, s := range h.series {
+			if candidate.matches(s.Key) {
+				for _, sample := range s.Samples {
+					durations = append(durations, sample.Duration)
+				}
+			}
+		}
+		if len(durations) == 0 {
+			continue
+		}
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		return DurationEstimate{
+			Key:     candidate,
+			P50:     percentile(durations, 50),
+			P90:     percentile(durations, 90),
+			Min:     durations[0],
+			Max:     durations[len(durations)-1],
+			Samples: len(durations),
+		}, true
+	}
+	return DurationEstimate{}, false
+}
+
+// Estimates returns the estimate of every recorded key, sorted by key
+func (h *DurationHistory) Estimates() []DurationEstimate {
+	h.mu.RLock()
+	keys := make([]DurationKey, 0, len(h.series))
+	for _, s := range h.series {
+		keys = append(keys, s.Key)
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	estimates := make([]DurationEstimate, 0, len(keys))
+	for _, key := range keys {
+		if estimate, ok := h.Estimate(key); ok {
+			estimates = append(estimates, estimate)
+		}
+	}
+	return estimates
+}
+
+// Save writes the history to disk if it changed since it was loaded
+func (h *DurationHistory) Save() error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.dirty {
+		return nil
+	}
+
+	file := durationHistoryFile{Version: durationHistoryVersion}
+	for _, s := range h.series {
+		file.Series = append(file.Series, s)
+	}
+	sort.Slice(file.Series, func(i, j int) bool { return file.Series[i].Key.String() < file.Series[j].Key.String() })
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal duration history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0750); err != nil {
+		return fmt.Errorf("failed to create duration history directory: %w", err)
+	}
+	// Write to a temporary file first so a crash never leaves a truncated history
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write duration history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("failed to write duration history: %w", err)
+	}
+	h.dirty = false
+	return nil
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// HistoricalTimingEstimator estimates operation durations from recorded history and
+// falls back to another estimator for operations that have never been observed.
+type HistoricalTimingEstimator struct {
+	history  *DurationHistory
+	fallback TimingEstimator
+}
+
+// NewHistoricalTimingEstimator creates a timing estimator backed by duration history
+func NewHistoricalTimingEstimator(history *DurationHistory, fallback TimingEstimator) *HistoricalTimingEstimator {
+	if fallback == nil {
+		fallback = NewDefaultTimingEstimator()
+	}
+	return &HistoricalTimingEstimator{history: history, fallback: fallback}
+}
+
+// EstimateOperationDuration returns the median recorded duration, or the fallback estimate
+func (e *HistoricalTimingEstimator) EstimateOperationDuration(operation PlannedOperation) time.Duration {
+	if estimate, ok := e.history.Estimate(DurationKeyFor(operation.Operation)); ok {
+		return estimate.P50
+	}
+	return e.fallback.EstimateOperationDuration(operation)
+}
+
+// EstimateTotalDuration sums the per-operation estimates
+func (e *HistoricalTimingEstimator) EstimateTotalDuration(operations []PlannedOperation) time.Duration {
+	var total time.Duration
+	for _, op := range operations {
+		total += e.EstimateOperationDuration(op)
+	}
+	return total
+}
@@ -0,0 +1,208 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func clusterCreate(name, provider, region, tier string) Operation {
+	return Operation{
+		Type:         OperationCreate,
+		ResourceType: types.KindCluster,
+		ResourceName: name,
+		Desired: &types.ClusterManifest{
+			Metadata: types.ResourceMetadata{Name: name},
+			Spec:     types.ClusterSpec{Provider: provider, Region: region, InstanceSize: tier},
+		},
+		Impact: &OperationImpact{EstimatedDuration: 10 * time.Minute, RiskLevel: RiskLevelMedium},
+	}
+}
+
+func TestDurationHistory_EstimatePercentiles(t *testing.T) {
+	history := NewDurationHistory(filepath.Join(t.TempDir(), "durations.json"))
+	key := DurationKeyFor(clusterCreate("c", "aws", "us_east_1", "m10"))
+	if key.String() != "Cluster/Create/AWS/US_EAST_1/M10" {
+		t.Fatalf("Unexpected key %s", key)
+	}
+
+	now := time.Now()
+	for i := 1; i <= 10; i++ {
+		history.Record(key, time.Duration(i)*time.Minute, now)
+	}
+
+	estimate, ok := history.Estimate(key)
+	if !ok {
+		t.Fatal("Expected an estimate")
+	}
+	if estimate.P50 != 5*time.Minute || estimate.P90 != 9*time.Minute || estimate.Samples != 10 {
+		t.Errorf("Unexpected estimate: %+v", estimate)
+	}
+	if estimate.Min != time.Minute || estimate.Max != 10*time.Minute {
+		t.Errorf("Unexpected min/max: %+v", estimate)
+	}
+}
+
+func TestDurationHistory_FallsBackToCoarserKeys(t *testing.T) {
+	history := NewDurationHistory(filepath.Join(t.TempDir(), "durations.json"))
+	now := time.Now()
+	history.Record(DurationKeyFor(clusterCreate("a", "AWS", "US_EAST_1", "M10")), 8*time.Minute, now)
+	history.Record(DurationKeyFor(clusterCreate("b", "AWS", "EU_WEST_1", "M30")), 20*time.Minute, now)
+
+	// Same provider and region, different tier: falls back to the region
+	estimate, ok := history.Estimate(DurationKeyFor(clusterCreate("c", "AWS", "US_EAST_1", "M40")))
+	if !ok || estimate.P50 != 8*time.Minute || estimate.Key.Tier != "" || estimate.Key.Region != "US_EAST_1" {
+		t.Errorf("Expected region fallback, got %+v", estimate)
+	}
+
+	// Different provider: falls back to every recorded cluster create
+	estimate, ok = history.Estimate(DurationKeyFor(clusterCreate("d", "GCP", "CENTRAL_US", "M10")))
+	if !ok || estimate.Samples != 2 || estimate.Key.Provider != "" {
+		t.Errorf("Expected kind fallback, got %+v", estimate)
+	}
+
+	// Nothing recorded for deletes
+	if _, ok := history.Estimate(DurationKey{Kind: types.KindCluster, Operation: OperationDelete}); ok {
+		t.Error("Expected no estimate for an unrecorded operation")
+	}
+}
+
+func TestDurationHistory_KeepsRecentSamples(t *testing.T) {
+	history := NewDurationHistory(filepath.Join(t.TempDir(), "durations.json"))
+	key := DurationKey{Kind: types.KindDatabaseUser, Operation: OperationCreate}
+	for i := 1; i <= DefaultMaxDurationSamples+10; i++ {
+		history.Record(key, time.Duration(i)*time.Second, time.Now())
+	}
+	history.Record(key, 0, time.Now())
+
+	estimate, _ := history.Estimate(key)
+	if estimate.Samples != DefaultMaxDurationSamples || estimate.Min != 11*time.Second {
+		t.Errorf("Expected only the most recent samples, got %+v", estimate)
+	}
+}
+
+func TestDurationHistory_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "durations.json")
+
+	history, err := LoadDurationHistory(path)
+	if err != nil {
+		t.Fatalf("Expected missing history to load empty, got %v", err)
+	}
+	key := DurationKey{Kind: types.KindNetworkAccess, Operation: OperationCreate}
+	history.Record(key, 3*time.Second, time.Now())
+	if err := history.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected history file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected history file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	loaded, err := LoadDurationHistory(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if estimate, ok := loaded.Estimate(key); !ok || estimate.P50 != 3*time.Second {
+		t.Errorf("Unexpected loaded estimate: %+v", estimate)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDurationHistory(path); err == nil {
+		t.Error("Expected an error for a corrupt history file")
+	}
+}
+
+func TestHistoricalTimingEstimator(t *testing.T) {
+	history := NewDurationHistory(filepath.Join(t.TempDir(), "durations.json"))
+	op := clusterCreate("c", "AWS", "US_EAST_1", "M10")
+	estimator := NewHistoricalTimingEstimator(history, NewDefaultTimingEstimator())
+
+	fallback := NewDefaultTimingEstimator().EstimateOperationDuration(PlannedOperation{Operation: op})
+	if got := estimator.EstimateOperationDuration(PlannedOperation{Operation: op}); got != fallback {
+		t.Errorf("Expected fallback estimate %v without history, got %v", fallback, got)
+	}
+
+	history.Record(DurationKeyFor(op), 22*time.Minute, time.Now())
+	if got := estimator.EstimateOperationDuration(PlannedOperation{Operation: op}); got != 22*time.Minute {
+		t.Errorf("Expected historical estimate, got %v", got)
+	}
+}
+
+func TestPlanBuilder_WithDurationHistory(t *testing.T) {
+	history := NewDurationHistory(filepath.Join(t.TempDir(), "durations.json"))
+	small := clusterCreate("small", "AWS", "US_EAST_1", "M10")
+	large := clusterCreate("large", "AWS", "US_EAST_1", "M40")
+	for _, d := range []time.Duration{7, 8, 9, 30} {
+		history.Record(DurationKeyFor(small), d*time.Minute, time.Now())
+	}
+	history.Record(DurationKeyFor(large), 35*time.Minute, time.Now())
+	user := Operation{Type: OperationCreate, ResourceType: types.KindDatabaseUser, ResourceName: "app",
+		Impact: &OperationImpact{EstimatedDuration: 30 * time.Second, RiskLevel: RiskLevelLow}}
+
+	plan, err := NewPlanBuilder("p").WithDurationHistory(history).AddOperations([]Operation{small, large, user}).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if small.Impact.EstimatedDuration != 10*time.Minute {
+		t.Error("Expected the diff operation's impact to be left untouched")
+	}
+	got := plan.Operations[0].Impact
+	if got.EstimatedDuration != 8*time.Minute || got.EstimatedDurationP90 != 30*time.Minute || got.DurationSamples != 4 {
+		t.Errorf("Unexpected historical impact: %+v", got)
+	}
+
+	// Both clusters run in the first stage; the user follows in the next
+	summary := plan.Summary
+	if summary.HistoricalEstimates != 2 {
+		t.Errorf("Expected 2 historical estimates, got %d", summary.HistoricalEstimates)
+	}
+	if summary.EstimatedDurationLow != 35*time.Minute+30*time.Second || summary.EstimatedDurationHigh != 35*time.Minute+30*time.Second {
+		t.Errorf("Unexpected ETA range %v - %v", summary.EstimatedDurationLow, summary.EstimatedDurationHigh)
+	}
+}
+
+func TestEstimatePlanETA_UsesSlowestOperationPerStage(t *testing.T) {
+	ops := []PlannedOperation{
+		{Stage: 0, Operation: Operation{Impact: &OperationImpact{EstimatedDuration: 10 * time.Minute, EstimatedDurationP90: 25 * time.Minute, DurationSamples: 5}}},
+		{Stage: 0, Operation: Operation{Impact: &OperationImpact{EstimatedDuration: 12 * time.Minute}}},
+		{Stage: 1, Operation: Operation{Impact: &OperationImpact{EstimatedDuration: time.Minute, EstimatedDurationP90: 2 * time.Minute, DurationSamples: 3}}},
+	}
+
+	low, high, historical := EstimatePlanETA(ops)
+	if low != 13*time.Minute || high != 27*time.Minute || historical != 2 {
+		t.Errorf("Unexpected ETA %v - %v (%d historical)", low, high, historical)
+	}
+}
+
+func TestAtlasExecutor_RecordDuration(t *testing.T) {
+	history := NewDurationHistory(filepath.Join(t.TempDir(), "durations.json"))
+	executor := &AtlasExecutor{}
+	executor.SetDurationHistory(history)
+
+	cluster := &PlannedOperation{Operation: clusterCreate("c", "AWS", "US_EAST_1", "M10")}
+	executor.recordDuration(cluster, &OperationResult{Duration: 2 * time.Second, Metadata: map[string]interface{}{}})
+	if _, ok := history.Estimate(DurationKeyFor(cluster.Operation)); ok {
+		t.Error("Expected cluster operations not waited for to be skipped")
+	}
+
+	executor.recordDuration(cluster, &OperationResult{Duration: 14 * time.Minute, Metadata: map[string]interface{}{"waitedForCluster": true}})
+	if estimate, ok := history.Estimate(DurationKeyFor(cluster.Operation)); !ok || estimate.P50 != 14*time.Minute {
+		t.Errorf("Expected the waited cluster duration to be recorded, got %+v", estimate)
+	}
+
+	user := &PlannedOperation{Operation: Operation{Type: OperationCreate, ResourceType: types.KindDatabaseUser}}
+	executor.recordDuration(user, &OperationResult{Duration: 4 * time.Second, Metadata: map[string]interface{}{}})
+	if _, ok := history.Estimate(DurationKeyFor(user.Operation)); !ok {
+		t.Error("Expected database user duration to be recorded")
+	}
+}
@@ -2,9 +2,12 @@ package apply
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
 	"github.com/teabranch/matlas-cli/internal/services/atlas"
 	"github.com/teabranch/matlas-cli/internal/services/database"
 )
@@ -18,6 +21,12 @@ type EnhancedExecutor struct {
 	idempotencyManager *IdempotencyManager
 	recoveryManager    *RecoveryManager
 
+	// Optional journal used to resume interrupted executions
+	journal *ExecutionJournal
+
+	// Runs the preApply/postApply/onFailure hooks declared on operations
+	hookRunner *HookRunner
+
 	// Configuration
 	config EnhancedExecutorConfig
 }
@@ -82,6 +91,7 @@ func NewEnhancedExecutor(
 		baseExecutor:       baseExecutor,
 		idempotencyManager: idempotencyManager,
 		recoveryManager:    recoveryManager,
+		hookRunner:         NewHookRunner(),
 		config:             config,
 	}
 }
@@ -101,6 +111,46 @@ func DefaultEnhancedExecutorConfig() EnhancedExecutorConfig {
 
 // Note: DefaultExecutorConfig is defined in executor.go
 
+// SetLDAPConfigurationService configures the LDAP configuration service on the base executor
+func (e *EnhancedExecutor) SetLDAPConfigurationService(ldapService *atlas.LDAPConfigurationService) {
+	if atlasExecutor, ok := e.baseExecutor.(*AtlasExecutor); ok {
+		atlasExecutor.SetLDAPConfigurationService(ldapService)
+	}
+}
+
+// SetIntegrationsService configures the integrations service on the base executor
+func (e *EnhancedExecutor) SetIntegrationsService(integrationsService *atlas.IntegrationsService) {
+	if atlasExecutor, ok := e.baseExecutor.(*AtlasExecutor); ok {
+		atlasExecutor.SetIntegrationsService(integrationsService)
+	}
+}
+
+// SetFederationService configures the federation service on the base executor
+func (e *EnhancedExecutor) SetFederationService(federationService *atlas.FederationService) {
+	if atlasExecutor, ok := e.baseExecutor.(*AtlasExecutor); ok {
+		atlasExecutor.SetFederationService(federationService)
+	}
+}
+
+// SetDatabaseClient configures the client used for Collection and Index resources on the base executor
+func (e *EnhancedExecutor) SetDatabaseClient(client DatabaseClient) {
+	if atlasExecutor, ok := e.baseExecutor.(*AtlasExecutor); ok {
+		atlasExecutor.SetDatabaseClient(client)
+	}
+}
+
+// SetDurationHistory configures the duration history on the base executor
+func (e *EnhancedExecutor) SetDurationHistory(history *DurationHistory) {
+	if atlasExecutor, ok := e.baseExecutor.(*AtlasExecutor); ok {
+		atlasExecutor.SetDurationHistory(history)
+	}
+}
+
+// SetJournal records execution progress so an interrupted run can be resumed
+func (e *EnhancedExecutor) SetJournal(journal *ExecutionJournal) {
+	e.journal = journal
+}
+
 // Execute runs the entire plan with enhanced features
 func (e *EnhancedExecutor) Execute(ctx context.Context, plan *Plan) (*ExecutionResult, error) {
 	// Start cleanup worker for idempotency manager
@@ -241,6 +291,64 @@ func (e *EnhancedExecutor) enhanceOperation(ctx context.Context, operation *Plan
 }
 
 // executeEnhancedPlan executes an enhanced plan
+// referenceResolver creates the resolver for the plan's ${ref:...} expressions, reading
+// status through the base executor's Atlas services
+func (e *EnhancedExecutor) referenceResolver(plan *Plan) *ReferenceResolver {
+	fetcher := &AtlasStatusFetcher{ProjectID: plan.ProjectID}
+	if atlasExecutor, ok := e.baseExecutor.(*AtlasExecutor); ok {
+		fetcher.Clusters = atlasExecutor.clustersService
+		fetcher.Users = atlasExecutor.usersService
+		fetcher.NetworkAccess = atlasExecutor.networkAccessService
+		fetcher.Projects = atlasExecutor.projectsService
+		fetcher.VPCEndpoints = atlasExecutor.vpcEndpointsService
+	}
+	resolver := NewReferenceResolver(fetcher)
+	resolver.DeclarePlan(plan)
+	if atlasExecutor, ok := e.baseExecutor.(*AtlasExecutor); ok {
+		// Status such as connection strings only appears once a cluster is provisioned,
+		// so references to resources changed by this plan wait for it, with or without --wait
+		timeout := atlasExecutor.config.OperationTimeout
+		if timeout <= 0 {
+			timeout = defaultStatusWaitTimeout
+		}
+		interval := atlasExecutor.clusterPollInterval
+		if interval <= 0 {
+			interval = defaultClusterPollInterval
+		}
+		resolver.WaitForStatus(timeout, interval)
+	}
+	return resolver
+}
+
+// resolveOperationReferences substitutes the references in an operation's desired
+// resource. It returns a failed result when a reference cannot be resolved.
+func resolveOperationReferences(ctx context.Context, resolver *ReferenceResolver, operation *PlannedOperation) (*OperationResult, error) {
+	if len(operation.References) == 0 {
+		return nil, nil
+	}
+	resolved, err := resolver.ResolveResource(ctx, operation.Desired)
+	if err != nil {
+		now := time.Now()
+		return &OperationResult{
+			OperationID: operation.ID,
+			Status:      OperationStatusFailed,
+			StartedAt:   now,
+			CompletedAt: now,
+			Error:       err.Error(),
+		}, err
+	}
+	operation.Desired = resolved
+	return nil, nil
+}
+
+// operationsByStage returns the operations ordered by stage, keeping plan order within a stage
+func operationsByStage(ops []PlannedOperation) []PlannedOperation {
+	ordered := make([]PlannedOperation, len(ops))
+	copy(ordered, ops)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Stage < ordered[j].Stage })
+	return ordered
+}
+
 func (e *EnhancedExecutor) executeEnhancedPlan(ctx context.Context, plan *Plan) (*ExecutionResult, error) {
 	// Set the current plan in the base executor so operations can access project ID
 	if atlasExecutor, ok := e.baseExecutor.(*AtlasExecutor); ok {
@@ -258,15 +366,27 @@ func (e *EnhancedExecutor) executeEnhancedPlan(ctx context.Context, plan *Plan)
 		Errors:           []ExecutionError{},
 	}
 
-	// Execute operations
-	for _, operation := range plan.Operations {
+	resolver := e.referenceResolver(plan)
+
+	// Execute operations stage by stage so referenced resources are applied first
+	for _, operation := range operationsByStage(plan.Operations) {
 		// Skip operations that are already completed
 		if operation.Status == OperationStatusCompleted {
 			result.Summary.CompletedOperations++
+			if e.journal != nil {
+				e.journal.OperationFinished(&operation, nil)
+			}
 			continue
 		}
 
-		operationResult, err := e.executeEnhancedOperation(ctx, &operation)
+		if e.journal != nil {
+			e.journal.OperationStarted(&operation)
+		}
+		operationResult, err := resolveOperationReferences(ctx, resolver, &operation)
+		if err == nil {
+			operationResult, err = e.executeWithHooks(ctx, plan, &operation)
+		}
+		resolver.Forget(operation.ResourceType, operation.ResourceName)
 		result.OperationResults[operation.ID] = operationResult
 
 		if err != nil {
@@ -279,8 +399,10 @@ func (e *EnhancedExecutor) executeEnhancedPlan(ctx context.Context, plan *Plan)
 				Recoverable: true,
 			})
 
-			// Attempt recovery for this operation
-			if e.config.EnableRecovery {
+			// Attempt recovery for this operation; hook and reference failures are final
+			var hookErr *HookError
+			var refErr *ReferenceError
+			if e.config.EnableRecovery && !errors.As(err, &hookErr) && !errors.As(err, &refErr) {
 				recoveryResult, recoveryErr := e.recoveryManager.RecoverFromFailure(ctx, &operation, err)
 				if recoveryErr == nil && recoveryResult.Success {
 					// Recovery successful, update the operation result
@@ -293,6 +415,14 @@ func (e *EnhancedExecutor) executeEnhancedPlan(ctx context.Context, plan *Plan)
 		} else {
 			result.Summary.CompletedOperations++
 		}
+
+		if e.journal != nil {
+			if operationResult != nil && operationResult.Status == OperationStatusCompleted {
+				e.journal.OperationFinished(&operation, nil)
+			} else {
+				e.journal.OperationFinished(&operation, err)
+			}
+		}
 	}
 
 	// Finalize result
@@ -305,6 +435,17 @@ func (e *EnhancedExecutor) executeEnhancedPlan(ctx context.Context, plan *Plan)
 		result.Status = PlanStatusFailed
 	}
 
+	if e.journal != nil {
+		switch {
+		case ctx.Err() != nil:
+			e.journal.Finish(dag.ExecutionStatusCancelled)
+		case result.Status == PlanStatusCompleted:
+			e.journal.Finish(dag.ExecutionStatusCompleted)
+		default:
+			e.journal.Finish(dag.ExecutionStatusFailed)
+		}
+	}
+
 	return result, nil
 }
 
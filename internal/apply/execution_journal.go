@@ -0,0 +1,357 @@
+package apply
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// ExecutionJournal records the progress of a plan execution in the DAG state store and
+// checkpoints every completed stage, so an interrupted apply can be resumed. Operations are
+// tracked by resource (kind and name) because operation IDs change when the plan is
+// regenerated on resume.
+type ExecutionJournal struct {
+	stateManager      *dag.StateManager
+	checkpointManager *dag.CheckpointManager
+	rollbackStore     *RollbackStore
+
+	mu             sync.Mutex
+	state          *dag.ExecutionState
+	stageRemaining map[int]int
+	rollbackLog    *RollbackLog
+}
+
+// NewExecutionJournal creates a journal backed by the given state and checkpoint stores
+func NewExecutionJournal(stateManager *dag.StateManager, checkpointManager *dag.CheckpointManager) *ExecutionJournal {
+	return &ExecutionJournal{
+		stateManager:      stateManager,
+		checkpointManager: checkpointManager,
+	}
+}
+
+// WithRollbackStore records the inverse of every completed operation in store, so the
+// execution can be rolled back
+func (j *ExecutionJournal) WithRollbackStore(store *RollbackStore) *ExecutionJournal {
+	j.rollbackStore = store
+	return j
+}
+
+// NewExecutionID returns an execution ID for a run started at the given time. A random
+// suffix keeps IDs of runs started in the same second apart.
+func NewExecutionID(now time.Time) string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		suffix = []byte{byte(now.Nanosecond() >> 16), byte(now.Nanosecond() >> 8), byte(now.Nanosecond())}
+	}
+	return fmt.Sprintf("exec-%s-%s", now.UTC().Format("20060102-150405"), hex.EncodeToString(suffix))
+}
+
+// ConfigurationHash identifies a desired state, so a resumed execution can check that it
+// applies the configuration it was started with
+func ConfigurationHash(desired *ProjectState) (string, error) {
+	data, err := json.Marshal(desired)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash configuration: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// CheckResumeConfiguration refuses to resume an execution with a configuration other than
+// the one it was started with. Executions recorded without a hash are not checked.
+func CheckResumeConfiguration(state *dag.ExecutionState, configHash string) error {
+	if state.ConfigHash == "" || state.ConfigHash == configHash {
+		return nil
+	}
+	return fmt.Errorf("the configuration has changed since execution %s was started; apply it as a new execution, or abandon %s first", state.ExecutionID, state.ExecutionID)
+}
+
+// ExecutionOperationKey identifies the resource an operation acts on
+func ExecutionOperationKey(kind types.ResourceKind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// IsResumable reports whether an execution was interrupted or failed before finishing.
+// Executions still marked running were killed without recording their outcome.
+func IsResumable(state *dag.ExecutionState) bool {
+	switch state.Status {
+	case dag.ExecutionStatusRunning, dag.ExecutionStatusFailed, dag.ExecutionStatusCancelled, dag.ExecutionStatusPaused:
+		return true
+	default:
+		return false
+	}
+}
+
+// Begin starts journaling a new execution of the plan generated from the configuration
+// identified by configHash
+func (j *ExecutionJournal) Begin(executionID string, plan *Plan, inputs []string, configHash string) error {
+	changes := 0
+	for _, op := range plan.Operations {
+		if op.Type != OperationNoChange {
+			changes++
+		}
+	}
+
+	state := dag.NewExecutionState(executionID, plan.ID, plan.ProjectID, plan.GetMaxStage()+1, changes)
+	state.ConfigHash = configHash
+	for _, input := range inputs {
+		if input == "-" {
+			state.Inputs = append(state.Inputs, input)
+			continue
+		}
+		if abs, err := filepath.Abs(input); err == nil {
+			input = abs
+		}
+		state.Inputs = append(state.Inputs, input)
+	}
+	for _, op := range plan.Operations {
+		if op.Type == OperationNoChange {
+			continue
+		}
+		key := ExecutionOperationKey(op.ResourceType, op.ResourceName)
+		state.Operations[key] = &dag.OperationState{
+			OperationID:  key,
+			NodeID:       op.ID,
+			ResourceKind: string(op.ResourceType),
+			ResourceName: op.ResourceName,
+			Type:         string(op.Type),
+			Stage:        op.Stage,
+			Status:       dag.OpStatusPending,
+		}
+	}
+	state.SetStatus(dag.ExecutionStatusRunning)
+
+	return j.start(state)
+}
+
+// Resume continues journaling an interrupted execution. Operations that are not completed
+// are reset to pending; PrepareResume has already reset completed operations that live
+// state no longer confirms and left operations the execution did not record out of plan.
+func (j *ExecutionJournal) Resume(state *dag.ExecutionState, plan *Plan) error {
+	for key, opState := range state.Operations {
+		if opState.Status != dag.OpStatusCompleted {
+			state.ResetOperation(key)
+		}
+	}
+	for _, op := range plan.Operations {
+		if opState, ok := state.Operations[ExecutionOperationKey(op.ResourceType, op.ResourceName)]; ok {
+			opState.NodeID = op.ID
+		}
+	}
+	state.PlanID = plan.ID
+	state.CompletedAt = nil
+	state.SetStatus(dag.ExecutionStatusRunning)
+
+	return j.start(state)
+}
+
+func (j *ExecutionJournal) start(state *dag.ExecutionState) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.state = state
+	j.rollbackLog = nil
+	if j.rollbackStore != nil {
+		// A resumed execution keeps the inverse operations recorded before the interruption
+		log, err := j.rollbackStore.Load(state.ExecutionID)
+		if err != nil {
+			if !errors.Is(err, ErrNoRollbackLog) {
+				fmt.Fprintf(os.Stderr, "Warning: starting a new rollback record: %v\n", err)
+			}
+			log = NewRollbackLog(state.ExecutionID, state.ProjectID)
+		}
+		j.rollbackLog = log
+	}
+	j.stageRemaining = make(map[int]int)
+	for _, opState := range state.Operations {
+		if opState.Status != dag.OpStatusCompleted {
+			j.stageRemaining[opState.Stage]++
+		}
+	}
+	return j.stateManager.SaveState(state)
+}
+
+// ExecutionID returns the ID of the journaled execution
+func (j *ExecutionJournal) ExecutionID() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.state == nil {
+		return ""
+	}
+	return j.state.ExecutionID
+}
+
+// OperationStarted records that an operation is running
+func (j *ExecutionJournal) OperationStarted(op *PlannedOperation) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key, ok := j.trackedKey(op)
+	if !ok {
+		return
+	}
+	j.state.SetStage(op.Stage)
+	j.state.UpdateOperationState(key, dag.OpStatusRunning, nil)
+	j.save()
+}
+
+// OperationFinished records the outcome of an operation and checkpoints the stage once
+// all of its operations have finished
+func (j *ExecutionJournal) OperationFinished(op *PlannedOperation, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key, ok := j.trackedKey(op)
+	if !ok {
+		return
+	}
+	opState := j.state.Operations[key]
+	if opState.Status == dag.OpStatusCompleted {
+		return
+	}
+
+	if err != nil {
+		j.state.UpdateOperationState(key, dag.OpStatusFailed, err)
+	} else {
+		j.state.UpdateOperationState(key, dag.OpStatusCompleted, nil)
+		j.recordRollback(op)
+	}
+	j.save()
+
+	stage := opState.Stage
+	j.stageRemaining[stage]--
+	if j.stageRemaining[stage] == 0 && j.checkpointManager != nil {
+		reason := fmt.Sprintf("stage %d finished", stage)
+		if _, cpErr := j.checkpointManager.CreateCheckpoint(j.state.ExecutionID, j.state.PlanID, j.state, nil, stage, "", reason); cpErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to checkpoint execution %s: %v\n", j.state.ExecutionID, cpErr)
+			return
+		}
+		j.save()
+	}
+}
+
+// Finish records the final status of the execution
+func (j *ExecutionJournal) Finish(status dag.ExecutionStatus) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.state == nil {
+		return
+	}
+	j.state.SetStatus(status)
+	j.save()
+}
+
+func (j *ExecutionJournal) trackedKey(op *PlannedOperation) (string, bool) {
+	if j.state == nil {
+		return "", false
+	}
+	key := ExecutionOperationKey(op.ResourceType, op.ResourceName)
+	_, ok := j.state.Operations[key]
+	return key, ok
+}
+
+func (j *ExecutionJournal) recordRollback(op *PlannedOperation) {
+	if j.rollbackLog == nil {
+		return
+	}
+	j.rollbackLog.Record(op, time.Now())
+	if err := j.rollbackStore.Save(j.rollbackLog); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save rollback record for execution %s: %v\n", j.state.ExecutionID, err)
+	}
+}
+
+func (j *ExecutionJournal) save() {
+	if err := j.stateManager.SaveState(j.state); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save execution state %s: %v\n", j.state.ExecutionID, err)
+	}
+}
+
+// ResumeSummary describes how a regenerated plan was aligned with an interrupted execution
+type ResumeSummary struct {
+	// Verified operations completed before the interruption and live state still matches
+	Verified []string `json:"verified,omitempty"`
+	// Reapplied operations completed before the interruption but live state has drifted
+	Reapplied []string `json:"reapplied,omitempty"`
+	// Pending operations had not completed and will run now
+	Pending []string `json:"pending,omitempty"`
+	// Skipped operations were not part of the interrupted execution; they are left out of
+	// the resumed plan for a later apply
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// PrepareResume aligns a plan regenerated from live state with an interrupted execution.
+// Only the recorded operations are resumed: completed operations whose resource no longer
+// needs a change are skipped, those that do are run again, and changes the execution did
+// not record are removed from the plan. Remaining operations keep the stage they had in
+// the original plan, and the plan is reordered so stages run in the same order as before.
+func PrepareResume(plan *Plan, state *dag.ExecutionState) *ResumeSummary {
+	summary := &ResumeSummary{}
+	planned := make(map[string]bool)
+
+	operations := plan.Operations[:0]
+	for i := range plan.Operations {
+		op := &plan.Operations[i]
+		key := ExecutionOperationKey(op.ResourceType, op.ResourceName)
+		opState, recorded := state.Operations[key]
+		if !recorded {
+			if op.Type != OperationNoChange {
+				summary.Skipped = append(summary.Skipped, key)
+			}
+			continue
+		}
+		operations = append(operations, *op)
+		op = &operations[len(operations)-1]
+		planned[key] = true
+		op.Stage = opState.Stage
+
+		switch {
+		case op.Type == OperationNoChange:
+			// Live state matches the desired state: the change is done, whatever was recorded
+			if opState.Status != dag.OpStatusCompleted {
+				state.UpdateOperationState(key, dag.OpStatusCompleted, nil)
+			}
+			summary.Verified = append(summary.Verified, key)
+		case opState.Status == dag.OpStatusCompleted:
+			state.ResetOperation(key)
+			summary.Reapplied = append(summary.Reapplied, key)
+		default:
+			summary.Pending = append(summary.Pending, key)
+		}
+	}
+
+	// Recorded operations missing from the new plan need no change at all, e.g. a
+	// delete that went through before the interruption
+	for key, opState := range state.Operations {
+		if planned[key] {
+			continue
+		}
+		if opState.Status != dag.OpStatusCompleted {
+			state.UpdateOperationState(key, dag.OpStatusCompleted, nil)
+		}
+		summary.Verified = append(summary.Verified, key)
+	}
+
+	sort.SliceStable(operations, func(a, b int) bool {
+		return operations[a].Stage < operations[b].Stage
+	})
+	plan.Operations = operations
+	plan.Summary = (&PlanBuilder{}).calculateSummary(operations)
+	sort.Strings(summary.Verified)
+	sort.Strings(summary.Reapplied)
+	sort.Strings(summary.Pending)
+	sort.Strings(summary.Skipped)
+
+	return summary
+}
@@ -0,0 +1,219 @@
+package apply
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func journalOp(id string, opType OperationType, kind types.ResourceKind, name string, stage int) PlannedOperation {
+	return PlannedOperation{
+		ID:        id,
+		Stage:     stage,
+		Operation: Operation{Type: opType, ResourceType: kind, ResourceName: name},
+	}
+}
+
+func newTestJournal(t *testing.T) (*ExecutionJournal, *dag.StateManager, *dag.CheckpointManager) {
+	dir := t.TempDir()
+	stateManager := dag.NewStateManager(filepath.Join(dir, "state"))
+	checkpointManager := dag.NewCheckpointManager(dag.CheckpointConfig{CheckpointDir: filepath.Join(dir, "checkpoints")})
+	return NewExecutionJournal(stateManager, checkpointManager), stateManager, checkpointManager
+}
+
+func TestExecutionJournal_RecordsProgressAndCheckpointsStages(t *testing.T) {
+	journal, stateManager, checkpointManager := newTestJournal(t)
+	plan := &Plan{
+		ID:        "plan-1",
+		ProjectID: "proj",
+		Operations: []PlannedOperation{
+			journalOp("op-1", OperationCreate, types.KindCluster, "main", 0),
+			journalOp("op-2", OperationNoChange, types.KindNetworkAccess, "office", 0),
+			journalOp("op-3", OperationCreate, types.KindDatabaseUser, "app", 1),
+			journalOp("op-4", OperationCreate, types.KindDatabaseUser, "reporting", 1),
+		},
+	}
+
+	if err := journal.Begin("exec-1", plan, []string{"config.yaml", "-"}, "hash-1"); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+
+	journal.OperationStarted(&plan.Operations[0])
+	journal.OperationFinished(&plan.Operations[0], nil)
+	journal.OperationFinished(&plan.Operations[1], nil) // untracked no-op
+	journal.OperationStarted(&plan.Operations[2])
+	journal.OperationFinished(&plan.Operations[2], nil)
+	journal.OperationStarted(&plan.Operations[3])
+	journal.OperationFinished(&plan.Operations[3], errors.New("boom"))
+	journal.Finish(dag.ExecutionStatusFailed)
+
+	state, err := stateManager.LoadState("exec-1")
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if state.Status != dag.ExecutionStatusFailed || state.TotalOps != 3 || state.CompletedOps != 2 || state.FailedOps != 1 {
+		t.Errorf("Unexpected state: status=%s total=%d completed=%d failed=%d", state.Status, state.TotalOps, state.CompletedOps, state.FailedOps)
+	}
+	if len(state.Inputs) != 2 || !filepath.IsAbs(state.Inputs[0]) || state.Inputs[1] != "-" {
+		t.Errorf("Unexpected inputs: %v", state.Inputs)
+	}
+	if state.ConfigHash != "hash-1" {
+		t.Errorf("Expected the configuration hash to be recorded, got %q", state.ConfigHash)
+	}
+	if op := state.Operations["DatabaseUser/reporting"]; op == nil || op.Status != dag.OpStatusFailed || op.Error != "boom" {
+		t.Errorf("Expected failed operation to be recorded, got %+v", op)
+	}
+
+	// Both stages finished, so each was checkpointed
+	checkpoints, err := checkpointManager.ListCheckpoints("exec-1")
+	if err != nil {
+		t.Fatalf("ListCheckpoints failed: %v", err)
+	}
+	if len(checkpoints) != 2 {
+		t.Errorf("Expected 2 stage checkpoints, got %d", len(checkpoints))
+	}
+	if state.LastCheckpoint == nil || state.LastCheckpoint.Stage != 1 {
+		t.Errorf("Expected last checkpoint for stage 1, got %+v", state.LastCheckpoint)
+	}
+}
+
+func TestPrepareResume_AlignsPlanWithInterruptedExecution(t *testing.T) {
+	state := dag.NewExecutionState("exec-1", "plan-1", "proj", 3, 4)
+	record := func(key string, stage int, status dag.OperationStatus) {
+		state.Operations[key] = &dag.OperationState{OperationID: key, Stage: stage, Status: dag.OpStatusPending}
+		if status != dag.OpStatusPending {
+			state.UpdateOperationState(key, status, nil)
+		}
+	}
+	record("Cluster/main", 0, dag.OpStatusCompleted)
+	record("DatabaseUser/app", 1, dag.OpStatusCompleted)
+	record("DatabaseUser/reporting", 2, dag.OpStatusRunning)
+	record("NetworkAccess/old", 2, dag.OpStatusPending)
+
+	// Regenerated plan: the cluster is in place, the app user drifted, the reporting
+	// user is still missing, the network entry is already gone and a user that was not
+	// part of the execution drifted
+	plan := &Plan{Operations: []PlannedOperation{
+		journalOp("a", OperationCreate, types.KindDatabaseUser, "reporting", 0),
+		journalOp("b", OperationUpdate, types.KindDatabaseUser, "app", 0),
+		journalOp("c", OperationNoChange, types.KindCluster, "main", 0),
+		journalOp("d", OperationCreate, types.KindDatabaseUser, "new", 1),
+	}}
+
+	summary := PrepareResume(plan, state)
+
+	if len(summary.Verified) != 2 || summary.Verified[0] != "Cluster/main" || summary.Verified[1] != "NetworkAccess/old" {
+		t.Errorf("Unexpected verified operations: %v", summary.Verified)
+	}
+	if len(summary.Reapplied) != 1 || summary.Reapplied[0] != "DatabaseUser/app" {
+		t.Errorf("Unexpected re-applied operations: %v", summary.Reapplied)
+	}
+	if len(summary.Pending) != 1 || summary.Pending[0] != "DatabaseUser/reporting" {
+		t.Errorf("Unexpected pending operations: %v", summary.Pending)
+	}
+	if len(summary.Skipped) != 1 || summary.Skipped[0] != "DatabaseUser/new" {
+		t.Errorf("Unexpected skipped operations: %v", summary.Skipped)
+	}
+	if state.Operations["DatabaseUser/app"].Status != dag.OpStatusPending {
+		t.Error("Expected drifted operation to be reset to pending")
+	}
+
+	// Only recorded operations are resumed, keeping their original stages, in order
+	var order []string
+	for _, op := range plan.Operations {
+		order = append(order, op.ID)
+	}
+	if len(order) != 3 || order[0] != "c" || order[1] != "b" || order[2] != "a" {
+		t.Errorf("Expected recorded operations in original stage order, got %v", order)
+	}
+	if plan.Summary.TotalOperations != 3 {
+		t.Errorf("Expected the plan summary to count resumed operations only, got %d", plan.Summary.TotalOperations)
+	}
+}
+
+func TestExecutionJournal_ResumeResetsUnfinishedOperations(t *testing.T) {
+	journal, stateManager, _ := newTestJournal(t)
+	state := dag.NewExecutionState("exec-1", "plan-1", "proj", 2, 2)
+	state.Operations["Cluster/main"] = &dag.OperationState{OperationID: "Cluster/main", Stage: 0, Status: dag.OpStatusPending}
+	state.Operations["DatabaseUser/app"] = &dag.OperationState{OperationID: "DatabaseUser/app", Stage: 1, Status: dag.OpStatusPending}
+	state.UpdateOperationState("Cluster/main", dag.OpStatusCompleted, nil)
+	state.UpdateOperationState("DatabaseUser/app", dag.OpStatusFailed, errors.New("boom"))
+	state.SetStatus(dag.ExecutionStatusFailed)
+
+	plan := &Plan{ID: "plan-2", Operations: []PlannedOperation{
+		journalOp("x", OperationCreate, types.KindDatabaseUser, "app", 1),
+	}}
+	if err := journal.Resume(state, plan); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if state.FailedOps != 0 || state.CompletedOps != 1 || state.Operations["DatabaseUser/app"].Error != "" {
+		t.Errorf("Expected failed operation to be reset: failed=%d completed=%d", state.FailedOps, state.CompletedOps)
+	}
+
+	journal.OperationFinished(&plan.Operations[0], nil)
+	journal.Finish(dag.ExecutionStatusCompleted)
+
+	loaded, err := stateManager.LoadState("exec-1")
+	if err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if loaded.Status != dag.ExecutionStatusCompleted || loaded.PlanID != "plan-2" || loaded.CompletedOps != 2 {
+		t.Errorf("Unexpected resumed state: status=%s plan=%s completed=%d", loaded.Status, loaded.PlanID, loaded.CompletedOps)
+	}
+	if IsResumable(loaded) {
+		t.Error("Expected completed execution not to be resumable")
+	}
+}
+
+func TestIsResumable(t *testing.T) {
+	for status, want := range map[dag.ExecutionStatus]bool{
+		dag.ExecutionStatusRunning:   true,
+		dag.ExecutionStatusFailed:    true,
+		dag.ExecutionStatusCancelled: true,
+		dag.ExecutionStatusCompleted: false,
+		dag.ExecutionStatusAbandoned: false,
+	} {
+		if got := IsResumable(&dag.ExecutionState{Status: status}); got != want {
+			t.Errorf("IsResumable(%s) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestNewExecutionID(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	first, second := NewExecutionID(now), NewExecutionID(now)
+	if !strings.HasPrefix(first, "exec-20261018-093000-") {
+		t.Errorf("Unexpected execution ID %s", first)
+	}
+	if first == second {
+		t.Errorf("Expected executions started in the same second to get distinct IDs, got %s twice", first)
+	}
+}
+
+func TestCheckResumeConfiguration(t *testing.T) {
+	desired := &ProjectState{Clusters: []types.ClusterManifest{{Metadata: types.ResourceMetadata{Name: "main"}}}}
+	hash, err := ConfigurationHash(desired)
+	if err != nil {
+		t.Fatalf("ConfigurationHash failed: %v", err)
+	}
+	state := dag.NewExecutionState("exec-1", "plan-1", "proj", 1, 1)
+	state.ConfigHash = hash
+
+	if err := CheckResumeConfiguration(state, hash); err != nil {
+		t.Errorf("Expected the same configuration to resume, got %v", err)
+	}
+	desired.Clusters[0].Metadata.Name = "other"
+	changed, _ := ConfigurationHash(desired)
+	if err := CheckResumeConfiguration(state, changed); err == nil || !strings.Contains(err.Error(), "configuration has changed") {
+		t.Errorf("Expected a changed configuration to be refused, got %v", err)
+	}
+	state.ConfigHash = ""
+	if err := CheckResumeConfiguration(state, changed); err != nil {
+		t.Errorf("Expected executions without a hash not to be checked, got %v", err)
+	}
+}
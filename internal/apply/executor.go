@@ -12,6 +12,7 @@ import (
 	"github.com/teabranch/matlas-cli/internal/services/atlas"
 	"github.com/teabranch/matlas-cli/internal/services/database"
 	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/validation"
 	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -42,9 +43,13 @@ type AtlasExecutor struct {
 	projectsService      *atlas.ProjectsService
 	searchService        *atlas.SearchService
 	vpcEndpointsService  *atlas.VPCEndpointsService
+	ldapService          *atlas.LDAPConfigurationService
+	integrationsService  *atlas.IntegrationsService
+	federationService    *atlas.FederationService
 
 	// Database service clients
 	databaseService *database.Service
+	databaseClient  DatabaseClient
 
 	// Execution state
 	mu              sync.RWMutex
@@ -58,6 +63,10 @@ type AtlasExecutor struct {
 	idempotencyManager *IdempotencyManager
 	recoveryManager    *RecoveryManager
 
+	// Recorded operation durations used for future estimates
+	durationHistory     *DurationHistory
+	clusterPollInterval time.Duration
+
 	// Configuration
 	config ExecutorConfig
 }
@@ -83,6 +92,14 @@ type ExecutorConfig struct {
 	// When true, executor treats conflict errors on create as non-fatal for idempotent resources
 	// and preserves existing resources instead of failing hard.
 	PreserveExisting bool `json:"preserveExisting"`
+
+	// WaitForClusters makes cluster operations block until Atlas finishes provisioning
+	// (or removing) the cluster, so their recorded duration is the real provisioning time
+	WaitForClusters bool `json:"waitForClusters"`
+
+	// ClusterWaitTimeout bounds the wait for one cluster. It is separate from
+	// OperationTimeout, which only covers the request that changes the cluster.
+	ClusterWaitTimeout time.Duration `json:"clusterWaitTimeout"`
 }
 
 // ExecutionResult contains the overall result of plan execution
@@ -163,6 +180,31 @@ func NewAtlasExecutor(
 	}
 }
 
+// SetLDAPConfigurationService configures the service used for LDAPConfiguration resources
+func (e *AtlasExecutor) SetLDAPConfigurationService(ldapService *atlas.LDAPConfigurationService) {
+	e.ldapService = ldapService
+}
+
+// SetIntegrationsService configures the service used for Integration resources
+func (e *AtlasExecutor) SetIntegrationsService(integrationsService *atlas.IntegrationsService) {
+	e.integrationsService = integrationsService
+}
+
+// SetFederationService configures the service used for FederationSettings and RoleMapping resources
+func (e *AtlasExecutor) SetFederationService(federationService *atlas.FederationService) {
+	e.federationService = federationService
+}
+
+// SetDatabaseClient configures the client used for Collection and Index resources
+func (e *AtlasExecutor) SetDatabaseClient(client DatabaseClient) {
+	e.databaseClient = client
+}
+
+// SetDurationHistory records the duration of every successful operation into history
+func (e *AtlasExecutor) SetDurationHistory(history *DurationHistory) {
+	e.durationHistory = history
+}
+
 // Execute implements the Executor interface
 func (e *AtlasExecutor) Execute(ctx context.Context, plan *Plan) (*ExecutionResult, error) {
 	e.mu.Lock()
@@ -332,6 +374,9 @@ func (e *AtlasExecutor) ExecuteOperation(ctx context.Context, operation *Planned
 	err := e.retryManager.ExecuteWithRetry(opCtx, operation, func() error {
 		return e.executeOperationInternal(opCtx, operation, result)
 	})
+	if err == nil && e.config.WaitForClusters && operation.ResourceType == types.KindCluster && operation.Type != OperationNoChange {
+		e.waitForClusterChange(ctx, operation, result)
+	}
 
 	// Finalize result
 	result.CompletedAt = time.Now()
@@ -345,11 +390,90 @@ func (e *AtlasExecutor) ExecuteOperation(ctx context.Context, operation *Planned
 	} else {
 		result.Status = OperationStatusCompleted
 		e.updateOperationStatus(operation.ID, OperationStatusCompleted)
+		e.recordDuration(operation, result)
 	}
 
 	return result, err
 }
 
+// recordDuration adds a completed operation's duration to the history. Cluster operations
+// return before Atlas finishes provisioning, so they are only recorded when waited for.
+func (e *AtlasExecutor) recordDuration(operation *PlannedOperation, result *OperationResult) {
+	if e.durationHistory == nil || operation.Type == OperationNoChange {
+		return
+	}
+	if operation.ResourceType == types.KindCluster {
+		if waited, _ := result.Metadata["waitedForCluster"].(bool); !waited {
+			return
+		}
+	}
+	e.durationHistory.Record(DurationKeyFor(operation.Operation), result.Duration, result.CompletedAt)
+}
+
+// defaultClusterPollInterval is how often cluster state is checked while waiting
+const defaultClusterPollInterval = 30 * time.Second
+
+// defaultStatusWaitTimeout bounds how long a status reference waits for a resource
+// changed in the same apply when no operation timeout is configured
+const defaultStatusWaitTimeout = 30 * time.Minute
+
+// defaultClusterWaitTimeout bounds the wait for one cluster when none is configured
+const defaultClusterWaitTimeout = 2 * time.Hour
+
+// waitForClusterChange waits for a cluster change Atlas accepted to finish. The wait is
+// bounded by ClusterWaitTimeout rather than OperationTimeout, since provisioning can take
+// longer than the request itself. The change was made either way, so a wait that times out
+// or fails is reported as a warning and only leaves the duration unrecorded.
+func (e *AtlasExecutor) waitForClusterChange(ctx context.Context, operation *PlannedOperation, result *OperationResult) {
+	timeout := e.config.ClusterWaitTimeout
+	if timeout <= 0 {
+		timeout = defaultClusterWaitTimeout
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := e.waitForCluster(waitCtx, operation)
+	result.Metadata["waitedForCluster"] = err == nil
+	if err != nil {
+		warning := fmt.Sprintf("%v; the change to cluster %s was made but its duration is not recorded", err, operation.ResourceName)
+		result.Metadata["warning"] = warning
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
+
+// waitForCluster polls a cluster until it is IDLE, or gone after a delete
+func (e *AtlasExecutor) waitForCluster(ctx context.Context, operation *PlannedOperation) error {
+	if e.clustersService == nil || e.currentPlan == nil {
+		return fmt.Errorf("clusters service not available")
+	}
+	interval := e.clusterPollInterval
+	if interval <= 0 {
+		interval = defaultClusterPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cluster, err := e.clustersService.Get(ctx, e.currentPlan.ProjectID, operation.ResourceName)
+		switch {
+		case operation.Type == OperationDelete && atlasclient.IsNotFound(err):
+			return nil
+		case err != nil && ctx.Err() != nil:
+			return fmt.Errorf("timed out waiting for cluster %s: %w", operation.ResourceName, ctx.Err())
+		case err != nil:
+			return fmt.Errorf("failed to check cluster %s state: %w", operation.ResourceName, err)
+		case operation.Type != OperationDelete && cluster.GetStateName() == "IDLE":
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for cluster %s: %w", operation.ResourceName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
 // executeOperationInternal performs the actual operation execution
 func (e *AtlasExecutor) executeOperationInternal(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
 	switch operation.Type {
@@ -388,6 +512,22 @@ func (e *AtlasExecutor) executeCreate(ctx context.Context, operation *PlannedOpe
 		return e.executeSearchQueryValidation(ctx, operation, result)
 	case types.KindVPCEndpoint:
 		return e.createVPCEndpoint(ctx, operation, result)
+	case types.KindLDAPConfiguration:
+		return e.applyLDAPConfiguration(ctx, operation, result)
+	case types.KindIntegration:
+		return e.createIntegration(ctx, operation, result)
+	case types.KindFederationSettings:
+		return e.applyFederationSettings(ctx, operation, result)
+	case types.KindRoleMapping:
+		return e.createRoleMapping(ctx, operation, result)
+	case types.KindCollection:
+		return e.createCollection(ctx, operation, result)
+	case types.KindIndex:
+		return e.createIndex(ctx, operation, result)
+	case types.KindView:
+		return e.createView(ctx, operation, result)
+	case types.KindMaterializedView:
+		return e.applyMaterializedView(ctx, operation, result)
 	default:
 		return fmt.Errorf("unsupported resource type for create: %s", operation.ResourceType)
 	}
@@ -458,6 +598,22 @@ func (e *AtlasExecutor) executeUpdate(ctx context.Context, operation *PlannedOpe
 		return e.updateNetworkAccess(ctx, operation, result)
 	case types.KindVPCEndpoint:
 		return e.updateVPCEndpoint(ctx, operation, result)
+	case types.KindLDAPConfiguration:
+		return e.applyLDAPConfiguration(ctx, operation, result)
+	case types.KindIntegration:
+		return e.updateIntegration(ctx, operation, result)
+	case types.KindFederationSettings:
+		return e.applyFederationSettings(ctx, operation, result)
+	case types.KindRoleMapping:
+		return e.updateRoleMapping(ctx, operation, result)
+	case types.KindCollection:
+		return e.updateCollection(ctx, operation, result)
+	case types.KindIndex:
+		return e.updateIndex(ctx, operation, result)
+	case types.KindView:
+		return e.updateView(ctx, operation, result)
+	case types.KindMaterializedView:
+		return e.applyMaterializedView(ctx, operation, result)
 	default:
 		return fmt.Errorf("unsupported resource type for update: %s", operation.ResourceType)
 	}
@@ -476,6 +632,20 @@ func (e *AtlasExecutor) executeDelete(ctx context.Context, operation *PlannedOpe
 		return e.deleteNetworkAccess(ctx, operation, result)
 	case types.KindVPCEndpoint:
 		return e.deleteVPCEndpoint(ctx, operation, result)
+	case types.KindLDAPConfiguration:
+		return e.disableLDAPConfiguration(ctx, operation, result)
+	case types.KindIntegration:
+		return e.deleteIntegration(ctx, operation, result)
+	case types.KindRoleMapping:
+		return e.deleteRoleMapping(ctx, operation, result)
+	case types.KindIndex:
+		return e.deleteIndex(ctx, operation, result)
+	case types.KindFederationSettings:
+		// Federation settings are shared across organizations and never deleted by apply
+		result.Metadata["operation"] = "skipFederationSettingsDelete"
+		result.Metadata["resourceName"] = operation.ResourceName
+		result.Metadata["reason"] = "Federation settings deletion skipped - manage the federation in the Atlas UI"
+		return nil
 	case types.KindProject:
 		// Projects are typically not deleted directly through apply operations
 		// Log this and treat as a no-op for now
@@ -702,6 +872,7 @@ func (e *AtlasExecutor) createDatabaseUser(ctx context.Context, operation *Plann
 			Password:     desired.Spec.Password,
 			Roles:        desired.Spec.Roles,
 			AuthDatabase: desired.Spec.AuthDatabase,
+			AuthType:     desired.Spec.AuthType,
 			Scopes:       desired.Spec.Scopes,
 		}
 	case *types.DatabaseUserConfig:
@@ -775,6 +946,7 @@ func (e *AtlasExecutor) updateDatabaseUser(ctx context.Context, operation *Plann
 			Password:     desired.Spec.Password,
 			Roles:        desired.Spec.Roles,
 			AuthDatabase: desired.Spec.AuthDatabase,
+			AuthType:     desired.Spec.AuthType,
 			Scopes:       desired.Spec.Scopes,
 		}
 	case *types.DatabaseUserConfig:
@@ -799,10 +971,10 @@ func (e *AtlasExecutor) updateDatabaseUser(ctx context.Context, operation *Plann
 		return fmt.Errorf("project ID not available for database user update")
 	}
 
-	// Set auth database default if not specified
+	// Set auth database default for the user's auth type if not specified
 	authDatabase := userSpec.AuthDatabase
 	if authDatabase == "" {
-		authDatabase = "admin"
+		authDatabase = validation.AuthDatabaseForType(userSpec.AuthType)
 	}
 
 	// Update the database user
@@ -948,11 +1120,48 @@ func (e *AtlasExecutor) createNetworkAccess(ctx context.Context, operation *Plan
 }
 
 func (e *AtlasExecutor) updateNetworkAccess(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
-	// Network access entries cannot be updated in Atlas - they must be deleted and recreated
-	// This is a limitation of the Atlas API
+	// Atlas has no update endpoint for access list entries, so a changed comment or expiry
+	// is applied by removing the entry and adding it again with the desired settings.
+	if e.networkAccessService == nil {
+		result.Metadata["operation"] = "updateNetworkAccess"
+		result.Metadata["resourceName"] = operation.ResourceName
+		return fmt.Errorf("network access service not available")
+	}
+
+	networkManifest, ok := operation.Desired.(*types.NetworkAccessManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for network access operation")
+	}
+	entry, err := convertNetworkAccessManifestToEntry(networkManifest)
+	if err != nil {
+		return err
+	}
+
+	projectID := ""
+	if e.currentPlan != nil {
+		projectID = e.currentPlan.ProjectID
+	}
+	if projectID == "" {
+		return fmt.Errorf("project ID not available for network access update")
+	}
+
+	address := networkAccessAddress(networkManifest.Spec)
 	result.Metadata["operation"] = "updateNetworkAccess"
 	result.Metadata["resourceName"] = operation.ResourceName
-	return fmt.Errorf("network access entries cannot be updated - delete and recreate instead")
+	result.Metadata["atlasResourceId"] = address
+
+	if err := e.networkAccessService.Delete(ctx, projectID, address); err != nil && !atlasclient.IsNotFound(err) {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to remove network access entry before update: %w", err)
+	}
+	if _, err := e.networkAccessService.Create(ctx, projectID, []admin.NetworkPermissionEntry{entry}); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to re-create network access entry: %w", err)
+	}
+	if entry.DeleteAfterDate != nil {
+		result.Metadata["deleteAfterDate"] = entry.DeleteAfterDate.Format(time.RFC3339)
+	}
+	return nil
 }
 
 func (e *AtlasExecutor) deleteNetworkAccess(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
@@ -1429,6 +1638,7 @@ func DefaultExecutorConfig() ExecutorConfig {
 			OperationUpdate, // Safe for independent resources
 		},
 		OperationTimeout:       30 * time.Minute,
+		ClusterWaitTimeout:     defaultClusterWaitTimeout,
 		ProgressUpdateInterval: 1 * time.Second,
 		RetryConfig:            DefaultRetryConfig(),
 		VerboseLogging:         false,
@@ -1495,7 +1705,7 @@ func convertDatabaseUserConfigToAtlas(userSpec types.DatabaseUserConfig) (*admin
 
 	authDB := userSpec.AuthDatabase
 	if authDB == "" {
-		authDB = "admin"
+		authDB = validation.AuthDatabaseForType(userSpec.AuthType)
 	}
 
 	atlasUser := &admin.CloudDatabaseUser{
@@ -1503,6 +1713,10 @@ func convertDatabaseUserConfigToAtlas(userSpec types.DatabaseUserConfig) (*admin
 		DatabaseName: authDB,
 	}
 
+	if err := atlas.ApplyAuthType(atlasUser, userSpec.AuthType); err != nil {
+		return nil, fmt.Errorf("database user %s: %w", userSpec.Username, err)
+	}
+
 	if userSpec.Password != "" {
 		atlasUser.Password = admin.PtrString(userSpec.Password)
 	}
@@ -1761,14 +1975,12 @@ func convertNetworkAccessManifestToEntry(manifest *types.NetworkAccessManifest)
 	if manifest.Spec.Comment != "" {
 		entry.Comment = &manifest.Spec.Comment
 	}
-	if manifest.Spec.DeleteAfterDate != "" {
-		// Parse RFC3339 date
-		if t, err := time.Parse(time.RFC3339, manifest.Spec.DeleteAfterDate); err == nil {
-			entry.DeleteAfterDate = &t
-		} else {
-			return admin.NetworkPermissionEntry{}, fmt.Errorf("invalid deleteAfterDate format (must be RFC3339): %w", err)
-		}
+	// Resolve deleteAfterDate, its expiresAt alias or a ttl into the temporary entry's expiry
+	expiry, err := atlas.ResolveNetworkAccessExpiry(manifest.Spec.DeleteAfterDate, manifest.Spec.ExpiresAt, manifest.Spec.TTL, time.Now())
+	if err != nil {
+		return admin.NetworkPermissionEntry{}, fmt.Errorf("invalid network access expiry: %w", err)
 	}
+	entry.DeleteAfterDate = expiry
 	return entry, nil
 }
 
@@ -2328,3 +2540,585 @@ func (e *AtlasExecutor) executeSearchQueryValidation(ctx context.Context, operat
 
 	return nil
 }
+
+// applyLDAPConfiguration creates or updates the project LDAP configuration.
+// LDAP settings are a singleton per project, so create and update share the same call.
+func (e *AtlasExecutor) applyLDAPConfiguration(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "applyLDAPConfiguration"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.ldapService == nil {
+		return fmt.Errorf("LDAP configuration service not available")
+	}
+
+	ldapConfig, ok := operation.Desired.(*types.LDAPConfigurationManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for LDAP configuration operation: expected LDAPConfigurationManifest, got %T", operation.Desired)
+	}
+
+	projectID := ""
+	if e.currentPlan != nil {
+		projectID = e.currentPlan.ProjectID
+	}
+	if projectID == "" {
+		return fmt.Errorf("project ID not available for LDAP configuration")
+	}
+
+	updated, err := e.ldapService.Update(ctx, projectID, &ldapConfig.Spec)
+	if err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to apply LDAP configuration: %w", err)
+	}
+
+	result.Metadata["hostname"] = ldapConfig.Spec.Hostname
+	result.Metadata["atlasResourceId"] = projectID
+	if updated != nil && updated.Ldap != nil {
+		result.Metadata["authenticationEnabled"] = updated.Ldap.GetAuthenticationEnabled()
+		result.Metadata["authorizationEnabled"] = updated.Ldap.GetAuthorizationEnabled()
+	}
+	return nil
+}
+
+// disableLDAPConfiguration turns off LDAP authentication and authorization for the project.
+func (e *AtlasExecutor) disableLDAPConfiguration(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "disableLDAPConfiguration"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.ldapService == nil {
+		return fmt.Errorf("LDAP configuration service not available")
+	}
+
+	projectID := ""
+	if e.currentPlan != nil {
+		projectID = e.currentPlan.ProjectID
+	}
+	if projectID == "" {
+		return fmt.Errorf("project ID not available for LDAP configuration")
+	}
+
+	if err := e.ldapService.Disable(ctx, projectID); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to disable LDAP configuration: %w", err)
+	}
+	result.Metadata["atlasResourceId"] = projectID
+	return nil
+}
+
+// createIntegration configures a new third-party integration for the project
+func (e *AtlasExecutor) createIntegration(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "createIntegration"
+	return e.applyIntegration(ctx, operation, result, false)
+}
+
+// updateIntegration replaces the settings of an existing third-party integration
+func (e *AtlasExecutor) updateIntegration(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "updateIntegration"
+	return e.applyIntegration(ctx, operation, result, true)
+}
+
+func (e *AtlasExecutor) applyIntegration(ctx context.Context, operation *PlannedOperation, result *OperationResult, update bool) error {
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.integrationsService == nil {
+		return fmt.Errorf("integrations service not available")
+	}
+
+	integration, ok := operation.Desired.(*types.IntegrationManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for integration operation: expected IntegrationManifest, got %T", operation.Desired)
+	}
+
+	projectID := ""
+	if e.currentPlan != nil {
+		projectID = e.currentPlan.ProjectID
+	}
+	if projectID == "" {
+		return fmt.Errorf("project ID not available for integration")
+	}
+
+	spec := integration.Spec
+	spec.Type = atlas.NormalizeIntegrationType(spec.Type)
+
+	var err error
+	if update {
+		err = e.integrationsService.Update(ctx, projectID, &spec)
+	} else {
+		err = e.integrationsService.Create(ctx, projectID, &spec)
+	}
+	if err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to apply %s integration: %w", spec.Type, err)
+	}
+
+	result.Metadata["integrationType"] = spec.Type
+	result.Metadata["atlasResourceId"] = spec.Type
+	return nil
+}
+
+// deleteIntegration removes a third-party integration from the project
+func (e *AtlasExecutor) deleteIntegration(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "deleteIntegration"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.integrationsService == nil {
+		return fmt.Errorf("integrations service not available")
+	}
+
+	integration, ok := operation.Current.(*types.IntegrationManifest)
+	if !ok || integration == nil {
+		return fmt.Errorf("invalid resource type for integration deletion: expected IntegrationManifest, got %T", operation.Current)
+	}
+
+	projectID := ""
+	if e.currentPlan != nil {
+		projectID = e.currentPlan.ProjectID
+	}
+	if projectID == "" {
+		return fmt.Errorf("project ID not available for integration")
+	}
+
+	integrationType := atlas.NormalizeIntegrationType(integration.Spec.Type)
+	if err := e.integrationsService.Delete(ctx, projectID, integrationType); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to delete %s integration: %w", integrationType, err)
+	}
+	result.Metadata["integrationType"] = integrationType
+	result.Metadata["atlasResourceId"] = integrationType
+	return nil
+}
+
+// applyFederationSettings creates or updates the identity providers and connected organization
+// configuration of an organization's federation. Identity providers are matched by display name.
+func (e *AtlasExecutor) applyFederationSettings(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "applyFederationSettings"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.federationService == nil {
+		return fmt.Errorf("federation service not available")
+	}
+
+	settings, ok := operation.Desired.(*types.FederationSettingsManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for federation settings operation: expected FederationSettingsManifest, got %T", operation.Desired)
+	}
+
+	orgID := settings.Spec.OrgID
+	federationSettingsID, err := e.resolveFederationSettingsID(ctx, orgID, settings.Spec.FederationSettingsID)
+	if err != nil {
+		result.Metadata["error"] = err.Error()
+		return err
+	}
+
+	existing, err := e.federationService.ListIdentityProviders(ctx, federationSettingsID)
+	if err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to list identity providers: %w", err)
+	}
+	providerIDs := make(map[string]string, len(existing))
+	for _, provider := range existing {
+		providerIDs[provider.GetDisplayName()] = provider.GetId()
+	}
+
+	for i := range settings.Spec.IdentityProviders {
+		idp := &settings.Spec.IdentityProviders[i]
+		if id, found := providerIDs[idp.DisplayName]; found {
+			if _, err := e.federationService.UpdateIdentityProvider(ctx, federationSettingsID, id, idp); err != nil {
+				result.Metadata["error"] = err.Error()
+				return fmt.Errorf("failed to update identity provider %q: %w", idp.DisplayName, err)
+			}
+			continue
+		}
+		created, err := e.federationService.CreateIdentityProvider(ctx, federationSettingsID, idp)
+		if err != nil {
+			result.Metadata["error"] = err.Error()
+			return fmt.Errorf("failed to create identity provider %q: %w", idp.DisplayName, err)
+		}
+		providerIDs[idp.DisplayName] = created.GetId()
+	}
+
+	if settings.Spec.ConnectedOrg != nil {
+		current, err := e.federationService.GetConnectedOrg(ctx, federationSettingsID, orgID)
+		if err != nil {
+			result.Metadata["error"] = err.Error()
+			return fmt.Errorf("failed to fetch connected organization: %w", err)
+		}
+		config, err := atlas.ConvertConnectedOrgToAtlas(orgID, settings.Spec.ConnectedOrg, providerIDs)
+		if err != nil {
+			result.Metadata["error"] = err.Error()
+			return err
+		}
+		// Keep settings the manifest doesn't manage
+		if config.IdentityProviderId == nil && current != nil {
+			config.IdentityProviderId = current.IdentityProviderId
+		}
+		if config.PostAuthRoleGrants == nil && current != nil {
+			config.PostAuthRoleGrants = current.PostAuthRoleGrants
+		}
+		if _, err := e.federationService.UpdateConnectedOrg(ctx, federationSettingsID, orgID, config); err != nil {
+			result.Metadata["error"] = err.Error()
+			return fmt.Errorf("failed to update connected organization: %w", err)
+		}
+	}
+
+	result.Metadata["atlasResourceId"] = federationSettingsID
+	result.Metadata["identityProviders"] = len(settings.Spec.IdentityProviders)
+	return nil
+}
+
+// createRoleMapping maps an external group to organization and project roles
+func (e *AtlasExecutor) createRoleMapping(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "createRoleMapping"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.federationService == nil {
+		return fmt.Errorf("federation service not available")
+	}
+
+	mapping, ok := operation.Desired.(*types.RoleMappingManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for role mapping operation: expected RoleMappingManifest, got %T", operation.Desired)
+	}
+
+	federationSettingsID, err := e.resolveFederationSettingsID(ctx, mapping.Spec.OrgID, "")
+	if err != nil {
+		result.Metadata["error"] = err.Error()
+		return err
+	}
+
+	created, err := e.federationService.CreateRoleMapping(ctx, federationSettingsID, &mapping.Spec)
+	if err != nil {
+		if e.shouldIgnoreConflictError(err) {
+			result.Metadata["skipped"] = "role mapping already exists"
+			return nil
+		}
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to create role mapping for group %q: %w", mapping.Spec.ExternalGroupName, err)
+	}
+	if created != nil {
+		result.Metadata["atlasResourceId"] = created.GetId()
+	}
+	return nil
+}
+
+// updateRoleMapping replaces the role assignments of an existing role mapping
+func (e *AtlasExecutor) updateRoleMapping(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "updateRoleMapping"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.federationService == nil {
+		return fmt.Errorf("federation service not available")
+	}
+
+	mapping, ok := operation.Desired.(*types.RoleMappingManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for role mapping operation: expected RoleMappingManifest, got %T", operation.Desired)
+	}
+
+	federationSettingsID, mappingID, err := e.resolveRoleMapping(ctx, &mapping.Spec, operation.Current)
+	if err != nil {
+		result.Metadata["error"] = err.Error()
+		return err
+	}
+
+	if _, err := e.federationService.UpdateRoleMapping(ctx, federationSettingsID, mappingID, &mapping.Spec); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to update role mapping for group %q: %w", mapping.Spec.ExternalGroupName, err)
+	}
+	result.Metadata["atlasResourceId"] = mappingID
+	return nil
+}
+
+// deleteRoleMapping removes a role mapping, revoking the roles it granted
+func (e *AtlasExecutor) deleteRoleMapping(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "deleteRoleMapping"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.federationService == nil {
+		return fmt.Errorf("federation service not available")
+	}
+
+	mapping, ok := operation.Current.(*types.RoleMappingManifest)
+	if !ok {
+		mapping, ok = operation.Desired.(*types.RoleMappingManifest)
+	}
+	if !ok || mapping == nil {
+		return fmt.Errorf("invalid resource type for role mapping operation: expected RoleMappingManifest, got %T", operation.Current)
+	}
+
+	federationSettingsID, mappingID, err := e.resolveRoleMapping(ctx, &mapping.Spec, operation.Current)
+	if err != nil {
+		result.Metadata["error"] = err.Error()
+		return err
+	}
+
+	if err := e.federationService.DeleteRoleMapping(ctx, federationSettingsID, mappingID, mapping.Spec.OrgID); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to delete role mapping for group %q: %w", mapping.Spec.ExternalGroupName, err)
+	}
+	result.Metadata["atlasResourceId"] = mappingID
+	return nil
+}
+
+// resolveFederationSettingsID returns the federation settings ID, looking it up from the organization when not known
+func (e *AtlasExecutor) resolveFederationSettingsID(ctx context.Context, orgID, federationSettingsID string) (string, error) {
+	if federationSettingsID != "" {
+		return federationSettingsID, nil
+	}
+	settings, err := e.federationService.GetSettings(ctx, orgID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch federation settings for organization %s: %w", orgID, err)
+	}
+	if settings.GetId() == "" {
+		return "", fmt.Errorf("organization %s is not connected to a federation", orgID)
+	}
+	return settings.GetId(), nil
+}
+
+// resolveRoleMapping returns the federation settings and role mapping IDs for a mapping, preferring
+// the IDs recorded by discovery and falling back to a lookup by external group name
+func (e *AtlasExecutor) resolveRoleMapping(ctx context.Context, spec *types.RoleMappingSpec, current interface{}) (string, string, error) {
+	var labels map[string]string
+	if discovered, ok := current.(*types.RoleMappingManifest); ok && discovered != nil {
+		labels = discovered.Metadata.Labels
+	}
+
+	federationSettingsID, err := e.resolveFederationSettingsID(ctx, spec.OrgID, labels[FederationSettingsIDLabel])
+	if err != nil {
+		return "", "", err
+	}
+	if mappingID := labels[RoleMappingIDLabel]; mappingID != "" {
+		return federationSettingsID, mappingID, nil
+	}
+
+	mappings, err := e.federationService.ListRoleMappings(ctx, federationSettingsID, spec.OrgID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list role mappings: %w", err)
+	}
+	for _, mapping := range mappings {
+		if mapping.ExternalGroupName == spec.ExternalGroupName {
+			return federationSettingsID, mapping.GetId(), nil
+		}
+	}
+	return "", "", fmt.Errorf("role mapping for group %q not found in organization %s", spec.ExternalGroupName, spec.OrgID)
+}
+
+// createCollection creates a collection with its options
+func (e *AtlasExecutor) createCollection(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "createCollection"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.databaseClient == nil {
+		return fmt.Errorf("database client not available")
+	}
+
+	collection, ok := operation.Desired.(*types.CollectionManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for collection operation: expected CollectionManifest, got %T", operation.Desired)
+	}
+	spec := collection.Spec
+	if err := e.databaseClient.CreateCollection(ctx, spec.ClusterName, spec.DatabaseName, spec.Name, &spec.CollectionOptions); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to create collection %s.%s: %w", spec.DatabaseName, spec.Name, err)
+	}
+	result.Metadata["atlasResourceId"] = spec.DatabaseName + "." + spec.Name
+	return nil
+}
+
+// updateCollection changes the validator and expiry of a collection. Other options can only
+// be set when the collection is created.
+func (e *AtlasExecutor) updateCollection(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "updateCollection"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.databaseClient == nil {
+		return fmt.Errorf("database client not available")
+	}
+
+	collection, ok := operation.Desired.(*types.CollectionManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for collection operation: expected CollectionManifest, got %T", operation.Desired)
+	}
+	spec := collection.Spec
+	if current, ok := operation.Current.(*types.CollectionManifest); ok && current != nil {
+		if changed := CollectionImmutableChanges(&spec, &current.Spec); len(changed) > 0 {
+			return fmt.Errorf("%s cannot be changed on existing collection %s.%s", strings.Join(changed, ", "), spec.DatabaseName, spec.Name)
+		}
+	}
+
+	// The level and action are always sent so omitted values revert to the defaults, and an
+	// empty validator removes validation
+	opts := normalizeCollectionSpec(spec).CollectionOptions
+	if opts.Validator == nil {
+		opts.Validator = map[string]interface{}{}
+	}
+	if err := e.databaseClient.ModifyCollection(ctx, spec.ClusterName, spec.DatabaseName, spec.Name, &opts); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to update collection %s.%s: %w", spec.DatabaseName, spec.Name, err)
+	}
+	result.Metadata["atlasResourceId"] = spec.DatabaseName + "." + spec.Name
+	return nil
+}
+
+// createIndex builds an index on a collection
+func (e *AtlasExecutor) createIndex(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "createIndex"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.databaseClient == nil {
+		return fmt.Errorf("database client not available")
+	}
+
+	index, ok := operation.Desired.(*types.IndexManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for index operation: expected IndexManifest, got %T", operation.Desired)
+	}
+	spec := index.Spec
+	opts := spec.IndexOptions
+	opts.Name = spec.IndexName()
+	name, err := e.databaseClient.CreateIndex(ctx, spec.ClusterName, spec.DatabaseName, spec.CollectionName, spec.Keys, &opts)
+	if err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to create index %s on %s.%s: %w", opts.Name, spec.DatabaseName, spec.CollectionName, err)
+	}
+	result.Metadata["indexName"] = name
+	result.Metadata["atlasResourceId"] = name
+	return nil
+}
+
+// updateIndex changes hidden and expireAfterSeconds in place and rebuilds the index for any
+// other change
+func (e *AtlasExecutor) updateIndex(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "updateIndex"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.databaseClient == nil {
+		return fmt.Errorf("database client not available")
+	}
+
+	index, ok := operation.Desired.(*types.IndexManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for index operation: expected IndexManifest, got %T", operation.Desired)
+	}
+	current, ok := operation.Current.(*types.IndexManifest)
+	if !ok || current == nil {
+		return fmt.Errorf("invalid resource type for index update: expected IndexManifest, got %T", operation.Current)
+	}
+	spec := index.Spec
+	name := spec.IndexName()
+
+	if IndexRequiresRebuild(&spec, &current.Spec) {
+		result.Metadata["rebuilt"] = true
+		if err := e.databaseClient.DropIndex(ctx, spec.ClusterName, spec.DatabaseName, spec.CollectionName, name); err != nil {
+			result.Metadata["error"] = err.Error()
+			return fmt.Errorf("failed to drop index %s for rebuild: %w", name, err)
+		}
+		return e.createIndex(ctx, operation, result)
+	}
+
+	hidden := spec.Hidden
+	if err := e.databaseClient.ModifyIndex(ctx, spec.ClusterName, spec.DatabaseName, spec.CollectionName, name, &hidden, spec.ExpireAfterSeconds); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to update index %s on %s.%s: %w", name, spec.DatabaseName, spec.CollectionName, err)
+	}
+	result.Metadata["indexName"] = name
+	result.Metadata["atlasResourceId"] = name
+	return nil
+}
+
+// deleteIndex drops an index that is no longer declared
+func (e *AtlasExecutor) deleteIndex(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "deleteIndex"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.databaseClient == nil {
+		return fmt.Errorf("database client not available")
+	}
+
+	index, ok := operation.Current.(*types.IndexManifest)
+	if !ok || index == nil {
+		return fmt.Errorf("invalid resource type for index deletion: expected IndexManifest, got %T", operation.Current)
+	}
+	spec := index.Spec
+	name := spec.IndexName()
+	if err := e.databaseClient.DropIndex(ctx, spec.ClusterName, spec.DatabaseName, spec.CollectionName, name); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to drop index %s on %s.%s: %w", name, spec.DatabaseName, spec.CollectionName, err)
+	}
+	result.Metadata["indexName"] = name
+	result.Metadata["atlasResourceId"] = name
+	return nil
+}
+
+// createView creates a view
+func (e *AtlasExecutor) createView(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "createView"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.databaseClient == nil {
+		return fmt.Errorf("database client not available")
+	}
+
+	view, ok := operation.Desired.(*types.ViewManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for view operation: expected ViewManifest, got %T", operation.Desired)
+	}
+	spec := view.Spec
+	pipeline, err := database.PipelineFromSpec(spec.Pipeline)
+	if err != nil {
+		return fmt.Errorf("view %s.%s: %w", spec.DatabaseName, spec.Name, err)
+	}
+	if err := e.databaseClient.CreateView(ctx, spec.ClusterName, spec.DatabaseName, spec.Name, spec.ViewOn, pipeline, spec.Collation); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to create view %s.%s: %w", spec.DatabaseName, spec.Name, err)
+	}
+	result.Metadata["atlasResourceId"] = spec.DatabaseName + "." + spec.Name
+	return nil
+}
+
+// updateView replaces the source and pipeline of a view. The collation can only be set when
+// the view is created.
+func (e *AtlasExecutor) updateView(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "updateView"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.databaseClient == nil {
+		return fmt.Errorf("database client not available")
+	}
+
+	view, ok := operation.Desired.(*types.ViewManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for view operation: expected ViewManifest, got %T", operation.Desired)
+	}
+	spec := view.Spec
+	if current, ok := operation.Current.(*types.ViewManifest); ok && current != nil {
+		if changed := ViewImmutableChanges(&spec, &current.Spec); len(changed) > 0 {
+			return fmt.Errorf("%s cannot be changed on existing view %s.%s", strings.Join(changed, ", "), spec.DatabaseName, spec.Name)
+		}
+	}
+	pipeline, err := database.PipelineFromSpec(spec.Pipeline)
+	if err != nil {
+		return fmt.Errorf("view %s.%s: %w", spec.DatabaseName, spec.Name, err)
+	}
+	if err := e.databaseClient.ModifyView(ctx, spec.ClusterName, spec.DatabaseName, spec.Name, spec.ViewOn, pipeline); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to update view %s.%s: %w", spec.DatabaseName, spec.Name, err)
+	}
+	result.Metadata["atlasResourceId"] = spec.DatabaseName + "." + spec.Name
+	return nil
+}
+
+// applyMaterializedView runs the pipeline of a new or changed materialized view, merging its
+// results into the view's collection, and saves the definition
+func (e *AtlasExecutor) applyMaterializedView(ctx context.Context, operation *PlannedOperation, result *OperationResult) error {
+	result.Metadata["operation"] = "refreshMaterializedView"
+	result.Metadata["resourceName"] = operation.ResourceName
+	if e.databaseClient == nil {
+		return fmt.Errorf("database client not available")
+	}
+
+	view, ok := operation.Desired.(*types.MaterializedViewManifest)
+	if !ok {
+		return fmt.Errorf("invalid resource type for materialized view operation: expected MaterializedViewManifest, got %T", operation.Desired)
+	}
+	spec := view.Spec
+	pipeline, err := database.PipelineFromSpec(spec.Pipeline)
+	if err != nil {
+		return fmt.Errorf("materialized view %s.%s: %w", spec.DatabaseName, spec.Name, err)
+	}
+	definition := &database.MaterializedView{Name: spec.Name, Source: spec.Source, Pipeline: pipeline}
+	definition.SetMergeOptions(spec.Merge)
+	if err := e.databaseClient.RefreshMaterializedView(ctx, spec.ClusterName, spec.DatabaseName, definition); err != nil {
+		result.Metadata["error"] = err.Error()
+		return fmt.Errorf("failed to refresh materialized view %s.%s: %w", spec.DatabaseName, spec.Name, err)
+	}
+	result.Metadata["atlasResourceId"] = spec.DatabaseName + "." + spec.Name
+	if definition.RefreshedAt != nil {
+		result.Metadata["refreshedAt"] = definition.RefreshedAt.Format(time.RFC3339)
+	}
+	return nil
+}
@@ -168,3 +168,26 @@ func TestConvertNetworkAccessManifestToEntry_WithDeleteAfterDate(t *testing.T) {
 	require.NotNil(t, entry.DeleteAfterDate)
 	assert.Equal(t, date, entry.DeleteAfterDate.UTC().Format(time.RFC3339))
 }
+
+func TestConvertNetworkAccessManifestToEntry_WithTTL(t *testing.T) {
+	manifest := &types.NetworkAccessManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindNetworkAccess,
+		Metadata:   types.ResourceMetadata{Name: "contractor"},
+		Spec: types.NetworkAccessSpec{
+			ProjectName: "proj",
+			IPAddress:   "1.2.3.4",
+			TTL:         "8h",
+		},
+	}
+
+	before := time.Now()
+	entry, err := convertNetworkAccessManifestToEntry(manifest)
+	require.NoError(t, err)
+	require.NotNil(t, entry.DeleteAfterDate)
+	assert.WithinDuration(t, before.Add(8*time.Hour), *entry.DeleteAfterDate, time.Minute)
+
+	manifest.Spec.ExpiresAt = "2026-10-19T00:00:00Z"
+	_, err = convertNetworkAccessManifestToEntry(manifest)
+	assert.Error(t, err, "ttl and expiresAt are mutually exclusive")
+}
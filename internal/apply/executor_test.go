@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -73,6 +76,10 @@ func TestDefaultExecutorConfig(t *testing.T) {
 		t.Error("OperationTimeout should be positive")
 	}
 
+	if config.ClusterWaitTimeout <= config.OperationTimeout {
+		t.Error("ClusterWaitTimeout should outlast OperationTimeout")
+	}
+
 	if config.ProgressUpdateInterval <= 0 {
 		t.Error("ProgressUpdateInterval should be positive")
 	}
@@ -694,3 +701,42 @@ func runDBUserOpAndAssert(t *testing.T, opType OperationType, password string) {
 	assert.Contains(t, err.Error(), "database user service not available")
 	assert.NotContains(t, err.Error(), "invalid resource type for database user operation")
 }
+
+func TestAtlasExecutor_WaitForClusterChange(t *testing.T) {
+	var polls, idleAfter atomic.Int32
+	idleAfter.Store(3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := "CREATING"
+		if polls.Add(1) >= idleAfter.Load() {
+			state = "IDLE"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"name": "app", "stateName": %q}`, state)
+	}))
+	defer server.Close()
+
+	client, err := atlasclient.NewClient(atlasclient.Config{PublicKey: "public", PrivateKey: "private", BaseURL: server.URL, RetryMax: 1})
+	require.NoError(t, err)
+
+	executor := createTestExecutor()
+	executor.clustersService = atlas.NewClustersService(client)
+	executor.clusterPollInterval = 10 * time.Millisecond
+	executor.config.OperationTimeout = time.Millisecond
+	executor.currentPlan = &Plan{ProjectID: "507f1f77bcf86cd799439011"}
+	operation := &PlannedOperation{Operation: Operation{Type: OperationCreate, ResourceType: types.KindCluster, ResourceName: "app"}}
+
+	// The wait outlasts the operation timeout, which only covers the create request
+	result := &OperationResult{Metadata: map[string]interface{}{}}
+	executor.waitForClusterChange(context.Background(), operation, result)
+	assert.Equal(t, true, result.Metadata["waitedForCluster"])
+	assert.Nil(t, result.Metadata["warning"])
+
+	// A wait that times out is a warning, and the duration is not recorded
+	polls.Store(0)
+	idleAfter.Store(1000)
+	executor.config.ClusterWaitTimeout = 50 * time.Millisecond
+	result = &OperationResult{Metadata: map[string]interface{}{}}
+	executor.waitForClusterChange(context.Background(), operation, result)
+	assert.Equal(t, false, result.Metadata["waitedForCluster"])
+	assert.Contains(t, result.Metadata["warning"], "timed out waiting for cluster app")
+}
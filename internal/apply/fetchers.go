@@ -5,7 +5,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/teabranch/matlas-cli/internal/services/atlas"
 	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/validation"
 	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
 )
 
@@ -105,6 +107,10 @@ func (d *AtlasStateDiscovery) convertUserToManifest(user *admin.CloudDatabaseUse
 		Scopes:       scopes,
 		// Note: Password is not included for security reasons
 	}
+	// Only record external auth types so SCRAM users without authType don't show as drift
+	if authType := atlas.AuthTypeFromUser(user); authType != validation.AuthTypeSCRAM {
+		spec.AuthType = authType
+	}
 
 	return types.DatabaseUserManifest{
 		APIVersion: types.APIVersionV1,
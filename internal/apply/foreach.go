@@ -0,0 +1,227 @@
+package apply
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// eachPattern matches ${each.key}, ${each.value} and ${each.value.<path>}
+var eachPattern = regexp.MustCompile(`\$\{each\.(key|value)((?:\.[A-Za-z0-9_-]+)*)\}`)
+
+// eachItem is one repetition of a forEach or count resource
+type eachItem struct {
+	key   string
+	value interface{}
+}
+
+// ExpandForEach replaces the resources of a document that declare forEach or count with
+// one resource per item, substituting ${each.key} and ${each.value}. Expanded resources
+// follow the order of their keys, so reordering a forEach list does not change the
+// document, and are annotated with the name as written and their key.
+func ExpandForEach(doc *types.ApplyDocument) error {
+	resources := make([]types.ResourceManifest, 0, len(doc.Resources))
+	// names records whether each resource was expanded from a forEach or count
+	names := make(map[string]bool)
+	for _, resource := range doc.Resources {
+		source := ExecutionOperationKey(resource.Kind, resource.Metadata.Name)
+		expanded, err := expandResource(resource)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		// Duplicates among resources as written are reported by validation
+		repeated := resource.ForEach != nil || resource.Count != nil
+		for _, r := range expanded {
+			key := ExecutionOperationKey(r.Kind, r.Metadata.Name)
+			if fromForEach, exists := names[key]; exists && (repeated || fromForEach) {
+				return fmt.Errorf("%s: expands to %s, which is already declared", source, key)
+			}
+			names[key] = repeated
+		}
+		resources = append(resources, expanded...)
+	}
+	doc.Resources = resources
+	return nil
+}
+
+// expandResource returns the resources a manifest stands for
+func expandResource(resource types.ResourceManifest) ([]types.ResourceManifest, error) {
+	if resource.ForEach == nil && resource.Count == nil {
+		if usesEach(resource) {
+			return nil, fmt.Errorf("${each...} can only be used in resources with forEach or count")
+		}
+		return []types.ResourceManifest{resource}, nil
+	}
+	if resource.ForEach != nil && resource.Count != nil {
+		return nil, fmt.Errorf("forEach and count cannot both be set")
+	}
+	if !eachPattern.MatchString(resource.Metadata.Name) {
+		return nil, fmt.Errorf("metadata.name must contain ${each.key} so expanded resources have distinct names")
+	}
+
+	items, err := eachItems(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	template := resource
+	template.ForEach, template.Count = nil, nil
+	values, err := toValues(template)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := make([]types.ResourceManifest, 0, len(items))
+	for _, item := range items {
+		substituted, err := substituteEach(copyValue(values), item)
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := fromValues(substituted, types.ResourceManifest{})
+		if err != nil {
+			return nil, err
+		}
+		manifest := decoded.(types.ResourceManifest)
+		if manifest.Metadata.Annotations == nil {
+			manifest.Metadata.Annotations = make(map[string]string)
+		}
+		manifest.Metadata.Annotations[types.AnnotationForEach] = resource.Metadata.Name
+		manifest.Metadata.Annotations[types.AnnotationForEachKey] = item.key
+		expanded = append(expanded, manifest)
+	}
+	return expanded, nil
+}
+
+// usesEach reports whether a resource contains ${each...} expressions
+func usesEach(resource types.ResourceManifest) bool {
+	values, err := toValues(resource)
+	if err != nil {
+		return false
+	}
+	found := false
+	walkStrings(values, func(s string) {
+		found = found || eachPattern.MatchString(s)
+	})
+	return found
+}
+
+// eachItems lists the items of forEach, sorted by key, or of count, in index order
+func eachItems(resource types.ResourceManifest) ([]eachItem, error) {
+	if resource.Count != nil {
+		if *resource.Count < 0 {
+			return nil, fmt.Errorf("count must not be negative")
+		}
+		items := make([]eachItem, *resource.Count)
+		for i := range items {
+			items[i] = eachItem{key: strconv.Itoa(i), value: i}
+		}
+		return items, nil
+	}
+
+	var items []eachItem
+	switch forEach := resource.ForEach.(type) {
+	case []interface{}:
+		seen := make(map[string]bool)
+		for _, value := range forEach {
+			switch value.(type) {
+			case string, int, float64, bool:
+			default:
+				return nil, fmt.Errorf("forEach list items must be strings, numbers or booleans; use a map for structured values")
+			}
+			key := fmt.Sprint(value)
+			if seen[key] {
+				return nil, fmt.Errorf("forEach lists %q more than once", key)
+			}
+			seen[key] = true
+			items = append(items, eachItem{key: key, value: value})
+		}
+	case map[string]interface{}:
+		for key, value := range forEach {
+			items = append(items, eachItem{key: key, value: value})
+		}
+	default:
+		return nil, fmt.Errorf("forEach must be a list or a map")
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].key < items[j].key })
+	return items, nil
+}
+
+// substituteEach replaces ${each...} expressions in the string leaves of YAML values. A
+// string that is a single expression takes the item's value with its type.
+func substituteEach(value interface{}, item eachItem) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return substituteEachString(v, item)
+	case map[string]interface{}:
+		for key, child := range v {
+			substituted, err := substituteEach(child, item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = substituted
+		}
+	case []interface{}:
+		for i, child := range v {
+			substituted, err := substituteEach(child, item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = substituted
+		}
+	}
+	return value, nil
+}
+
+func substituteEachString(s string, item eachItem) (interface{}, error) {
+	matches := eachPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		return eachValue(s, item)
+	}
+
+	var resolveErr error
+	substituted := eachPattern.ReplaceAllStringFunc(s, func(expression string) string {
+		value, err := eachValue(expression, item)
+		if err == nil {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				err = fmt.Errorf("%s is not a scalar and cannot be embedded in a string", expression)
+			default:
+				return fmt.Sprint(value)
+			}
+		}
+		if resolveErr == nil {
+			resolveErr = err
+		}
+		return expression
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	return substituted, nil
+}
+
+// eachValue evaluates a single ${each...} expression
+func eachValue(expression string, item eachItem) (interface{}, error) {
+	match := eachPattern.FindStringSubmatch(expression)
+	if match[1] == "key" {
+		if match[2] != "" {
+			return nil, fmt.Errorf("%s: each.key has no fields", expression)
+		}
+		return item.key, nil
+	}
+	if match[2] == "" {
+		return item.value, nil
+	}
+	value, err := lookupPath(item.value, strings.TrimPrefix(match[2], "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", expression, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,212 @@
+package apply
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func decodeForEachDocument(t *testing.T, content string) *types.ApplyDocument {
+	t.Helper()
+	var doc types.ApplyDocument
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		t.Fatalf("Failed to parse document: %v", err)
+	}
+	return &doc
+}
+
+func resourceNames(resources []types.ResourceManifest) []string {
+	names := make([]string, len(resources))
+	for i, resource := range resources {
+		names[i] = resource.Metadata.Name
+	}
+	return names
+}
+
+const forEachUsers = `resources:
+  - kind: DatabaseUser
+    metadata:
+      name: user-${each.key}
+    forEach:
+      bob:
+        roles: [read]
+        database: invoices
+      alice:
+        roles: [readWrite, dbAdmin]
+        database: reports
+    spec:
+      username: ${each.key}
+      roles:
+        - roleName: admin-of-${each.value.database}
+          databaseName: ${each.value.database}
+      labels: ${each.value.roles}
+`
+
+func TestExpandForEach_Map(t *testing.T) {
+	doc := decodeForEachDocument(t, forEachUsers)
+	if err := ExpandForEach(doc); err != nil {
+		t.Fatalf("ExpandForEach failed: %v", err)
+	}
+	if names := resourceNames(doc.Resources); !reflect.DeepEqual(names, []string{"user-alice", "user-bob"}) {
+		t.Fatalf("Expected resources ordered by key, got %v", names)
+	}
+
+	alice := doc.Resources[0]
+	if alice.ForEach != nil || alice.Count != nil {
+		t.Error("Expected forEach to be cleared on expanded resources")
+	}
+	if alice.Metadata.Annotations[types.AnnotationForEach] != "user-${each.key}" || alice.Metadata.Annotations[types.AnnotationForEachKey] != "alice" {
+		t.Errorf("Unexpected annotations %v", alice.Metadata.Annotations)
+	}
+	spec := alice.Spec.(map[string]interface{})
+	if spec["username"] != "alice" {
+		t.Errorf("Expected each.key to be substituted, got %v", spec["username"])
+	}
+	role := spec["roles"].([]interface{})[0].(map[string]interface{})
+	if role["roleName"] != "admin-of-reports" || role["databaseName"] != "reports" {
+		t.Errorf("Expected each.value fields to be substituted, got %v", role)
+	}
+	if !reflect.DeepEqual(spec["labels"], []interface{}{"readWrite", "dbAdmin"}) {
+		t.Errorf("Expected a whole ${each.value...} to keep its type, got %#v", spec["labels"])
+	}
+}
+
+func TestExpandForEach_ListOrderIsStable(t *testing.T) {
+	document := func(items string) *types.ApplyDocument {
+		return decodeForEachDocument(t, `resources:
+  - kind: NetworkAccess
+    metadata:
+      name: office-${each.key}
+    forEach: [`+items+`]
+    spec:
+      ipAddress: ${each.value}
+`)
+	}
+	first, second := document("203.0.113.10, 198.51.100.7"), document("198.51.100.7, 203.0.113.10")
+	if err := ExpandForEach(first); err != nil {
+		t.Fatalf("ExpandForEach failed: %v", err)
+	}
+	if err := ExpandForEach(second); err != nil {
+		t.Fatalf("ExpandForEach failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected reordering the list not to change the expansion:\n%+v\n%+v", first.Resources, second.Resources)
+	}
+	if names := resourceNames(first.Resources); !reflect.DeepEqual(names, []string{"office-198.51.100.7", "office-203.0.113.10"}) {
+		t.Errorf("Unexpected names %v", names)
+	}
+}
+
+func TestExpandForEach_Count(t *testing.T) {
+	doc := decodeForEachDocument(t, `resources:
+  - kind: DatabaseUser
+    metadata:
+      name: worker-${each.key}
+    count: 11
+    spec:
+      shard: ${each.value}
+`)
+	if err := ExpandForEach(doc); err != nil {
+		t.Fatalf("ExpandForEach failed: %v", err)
+	}
+	if len(doc.Resources) != 11 || doc.Resources[2].Metadata.Name != "worker-2" || doc.Resources[10].Metadata.Name != "worker-10" {
+		t.Fatalf("Expected resources in index order, got %v", resourceNames(doc.Resources))
+	}
+	if shard := doc.Resources[3].Spec.(map[string]interface{})["shard"]; shard != 3 {
+		t.Errorf("Expected each.value to be the index, got %#v", shard)
+	}
+}
+
+func TestExpandForEach_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		name, fields, want string
+	}{
+		"both":          {"a-${each.key}", "forEach: [x]\ncount: 2", "cannot both be set"},
+		"static name":   {"a", "forEach: [x, y]", "must contain ${each.key}"},
+		"duplicate":     {"a-${each.key}", "forEach: [x, x]", "more than once"},
+		"structured":    {"a-${each.key}", "forEach: [{a: 1}]", "use a map"},
+		"negative":      {"a-${each.key}", "count: -1", "must not be negative"},
+		"scalar":        {"a-${each.key}", "forEach: x", "must be a list or a map"},
+		"unused each":   {"a", "spec: {team: '${each.key}'}", "only be used in resources with forEach"},
+		"missing field": {"a-${each.key}", "forEach: {x: {a: 1}}\nspec: {b: '${each.value.b}'}", "field b not found"},
+		"key field":     {"a-${each.key.x}", "forEach: [x]", "each.key has no fields"},
+		"embedded map":  {"a-${each.key}", "forEach: {x: {a: 1}}\nspec: {b: 'v-${each.value}'}", "not a scalar"},
+	} {
+		resource := "- kind: DatabaseUser\n  metadata: {name: '" + tc.name + "'}\n  " + strings.ReplaceAll(tc.fields, "\n", "\n  ")
+		doc := decodeForEachDocument(t, "resources:\n"+resource+"\n")
+		if err := ExpandForEach(doc); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, tc.want, err)
+		}
+	}
+
+	doc := decodeForEachDocument(t, `resources:
+  - kind: DatabaseUser
+    metadata: {name: a-b}
+  - kind: DatabaseUser
+    metadata: {name: "a-${each.key}"}
+    forEach: [b]
+`)
+	if err := ExpandForEach(doc); err == nil || !strings.Contains(err.Error(), "already declared") {
+		t.Errorf("Expected an expanded name colliding with a declared one to fail, got %v", err)
+	}
+}
+
+func TestConfigurationLoader_ExpandsForEachModuleInstances(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"modules/team/module.yaml":   testModuleManifest,
+		"modules/team/template.yaml": testModuleTemplate,
+		"app.yaml": `apiVersion: matlas.mongodb.com/v1
+kind: ApplyDocument
+metadata:
+  name: app
+resources:
+  - kind: ModuleInstance
+    metadata:
+      name: team-${each.key}
+    forEach: [payments, billing]
+    spec:
+      source: ./modules/team
+      inputs:
+        team: ${each.value}
+`,
+	})
+
+	result, err := NewConfigurationLoader(&LoaderOptions{ModuleCache: t.TempDir(), MaxFileSize: 1 << 20}).LoadApplyDocument(filepath.Join(dir, "app.yaml"))
+	if err != nil {
+		t.Fatalf("LoadApplyDocument failed: %v", err)
+	}
+	doc := result.Config.(*types.ApplyDocument)
+	want := []string{"billing-cluster", "billing-office", "payments-cluster", "payments-office"}
+	if names := resourceNames(doc.Resources); !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected %v, got %v", want, names)
+	}
+	if module := ModuleOf(&doc.Resources[0]); module != "team-billing" {
+		t.Errorf("Expected resources to be annotated with the expanded instance, got %q", module)
+	}
+}
+
+func TestDiffEngine_IgnoresForEachAnnotations(t *testing.T) {
+	expanded := *referencingUser("user-alice", "app")
+	expanded.Metadata.Annotations = map[string]string{
+		types.AnnotationForEach:    "user-${each.key}",
+		types.AnnotationForEachKey: "alice",
+	}
+	current := *referencingUser("user-alice", "app")
+
+	diff, err := NewDiffEngine().ComputeProjectDiff(
+		&ProjectState{DatabaseUsers: []types.DatabaseUserManifest{expanded}},
+		&ProjectState{DatabaseUsers: []types.DatabaseUserManifest{current}},
+	)
+	if err != nil {
+		t.Fatalf("ComputeProjectDiff failed: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationNoChange {
+		t.Fatalf("Expected no change, got %+v", diff.Operations)
+	}
+}
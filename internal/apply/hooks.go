@@ -0,0 +1,356 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// HookPhase identifies when a hook runs relative to its operation
+type HookPhase string
+
+const (
+	HookPhasePreApply  HookPhase = "preApply"
+	HookPhasePostApply HookPhase = "postApply"
+	HookPhaseOnFailure HookPhase = "onFailure"
+)
+
+// DefaultHookTimeout bounds hooks that do not declare a timeout
+const DefaultHookTimeout = 5 * time.Minute
+
+// HookError reports a failed hook. A HookError from the preApply phase means the
+// operation was vetoed and never executed.
+type HookError struct {
+	Phase HookPhase
+	Hook  string
+	Err   error
+}
+
+func (e *HookError) Error() string {
+	if e.Phase == HookPhasePreApply {
+		return fmt.Sprintf("operation vetoed by preApply hook %q: %v", e.Hook, e.Err)
+	}
+	return fmt.Sprintf("%s hook %q failed: %v", e.Phase, e.Hook, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// OperationHooks maps resources to the hooks declared for them in ApplyDocuments.
+// Document-level hooks also run for deletions of resources no document declares.
+type OperationHooks struct {
+	resources map[string]*types.Hooks
+	documents *types.Hooks
+}
+
+// NewOperationHooks creates an empty hook mapping
+func NewOperationHooks() *OperationHooks {
+	return &OperationHooks{resources: make(map[string]*types.Hooks)}
+}
+
+// AddDocument records the hooks of an ApplyDocument and its resources
+func (h *OperationHooks) AddDocument(doc *types.ApplyDocument) {
+	if !doc.Hooks.IsEmpty() {
+		h.documents = types.MergeHooks(h.documents, doc.Hooks)
+	}
+	for _, resource := range doc.Resources {
+		hooks := types.MergeHooks(doc.Hooks, resource.Hooks)
+		if hooks.IsEmpty() {
+			continue
+		}
+		h.resources[ExecutionOperationKey(resource.Kind, resource.Metadata.Name)] = hooks
+	}
+}
+
+// For returns the hooks of a resource, or nil when none apply
+func (h *OperationHooks) For(kind types.ResourceKind, name string) *types.Hooks {
+	if h == nil {
+		return nil
+	}
+	if hooks, ok := h.resources[ExecutionOperationKey(kind, name)]; ok {
+		return hooks
+	}
+	return h.documents
+}
+
+// HooksFor returns the hooks of one phase that apply to the operation type
+func HooksFor(hooks *types.Hooks, phase HookPhase, opType OperationType) []types.Hook {
+	if hooks == nil {
+		return nil
+	}
+	var all []types.Hook
+	switch phase {
+	case HookPhasePreApply:
+		all = hooks.PreApply
+	case HookPhasePostApply:
+		all = hooks.PostApply
+	case HookPhaseOnFailure:
+		all = hooks.OnFailure
+	}
+
+	var matching []types.Hook
+	for _, hook := range all {
+		if hookMatches(hook, opType) {
+			matching = append(matching, hook)
+		}
+	}
+	return matching
+}
+
+func hookMatches(hook types.Hook, opType OperationType) bool {
+	if len(hook.Operations) == 0 {
+		return true
+	}
+	for _, op := range hook.Operations {
+		if OperationType(op) == opType {
+			return true
+		}
+	}
+	return false
+}
+
+// HookDisplayName returns a short label for a hook. Webhook URLs are never shown since
+// they often embed tokens.
+func HookDisplayName(hook types.Hook) string {
+	switch {
+	case hook.Name != "":
+		return hook.Name
+	case hook.Command != "":
+		return hook.Command
+	case hook.Webhook != nil:
+		return "webhook"
+	default:
+		return "hook"
+	}
+}
+
+// redactHooks returns a copy of the hooks with webhook URLs and header values masked
+func redactHooks(hooks *types.Hooks) *types.Hooks {
+	if hooks == nil {
+		return nil
+	}
+	redactPhase := func(phase []types.Hook) []types.Hook {
+		if phase == nil {
+			return nil
+		}
+		redacted := make([]types.Hook, len(phase))
+		for i, hook := range phase {
+			if hook.Webhook != nil {
+				webhook := *hook.Webhook
+				webhook.URL, _ = maskSensitive(webhook.URL).(string)
+				if len(webhook.Headers) > 0 {
+					webhook.Headers = make(map[string]string, len(hook.Webhook.Headers))
+					for key, value := range hook.Webhook.Headers {
+						webhook.Headers[key], _ = maskSensitive(value).(string)
+					}
+				}
+				hook.Webhook = &webhook
+			}
+			redacted[i] = hook
+		}
+		return redacted
+	}
+	return &types.Hooks{
+		PreApply:  redactPhase(hooks.PreApply),
+		PostApply: redactPhase(hooks.PostApply),
+		OnFailure: redactPhase(hooks.OnFailure),
+	}
+}
+
+// HookRunner runs shell command and webhook hooks
+type HookRunner struct {
+	client *http.Client
+	shell  string
+	output io.Writer
+}
+
+// NewHookRunner creates a hook runner that forwards command output to stderr
+func NewHookRunner() *HookRunner {
+	return &HookRunner{
+		client: &http.Client{},
+		shell:  "sh",
+		output: os.Stderr,
+	}
+}
+
+// Run runs the hooks of a phase for an operation, in order, stopping at the first failure.
+// Each hook receives the operation as JSON: on stdin for commands, as the request body
+// for webhooks. opErr is the operation's error for onFailure hooks.
+func (r *HookRunner) Run(ctx context.Context, phase HookPhase, projectID string, op *PlannedOperation, opErr error) error {
+	hooks := HooksFor(op.Hooks, phase, op.Type)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := hookPayload(op, opErr)
+	if err != nil {
+		return fmt.Errorf("failed to encode operation for hooks: %w", err)
+	}
+
+	env := map[string]string{
+		"MATLAS_HOOK_PHASE":     string(phase),
+		"MATLAS_OPERATION_ID":   op.ID,
+		"MATLAS_OPERATION_TYPE": string(op.Type),
+		"MATLAS_RESOURCE_KIND":  string(op.ResourceType),
+		"MATLAS_RESOURCE_NAME":  op.ResourceName,
+		"MATLAS_PROJECT_ID":     projectID,
+	}
+	if opErr != nil {
+		env["MATLAS_OPERATION_ERROR"] = opErr.Error()
+	}
+
+	for _, hook := range hooks {
+		if err := r.runHook(ctx, hook, payload, env); err != nil {
+			return &HookError{Phase: phase, Hook: HookDisplayName(hook), Err: err}
+		}
+	}
+	return nil
+}
+
+func (r *HookRunner) runHook(ctx context.Context, hook types.Hook, payload []byte, env map[string]string) error {
+	timeout := DefaultHookTimeout
+	if hook.Timeout != "" {
+		parsed, err := time.ParseDuration(hook.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", hook.Timeout, err)
+		}
+		timeout = parsed
+	}
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch {
+	case hook.Command != "":
+		return r.runCommand(hookCtx, hook.Command, payload, env)
+	case hook.Webhook != nil:
+		return r.callWebhook(hookCtx, hook.Webhook, payload, env)
+	default:
+		return fmt.Errorf("hook declares neither a command nor a webhook")
+	}
+}
+
+func (r *HookRunner) runCommand(ctx context.Context, command string, payload []byte, env map[string]string) error {
+	cmd := exec.CommandContext(ctx, r.shell, "-c", command) // #nosec G204 -- hooks are user-declared commands
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = r.output
+	cmd.Stderr = r.output
+	// Stop waiting on output of processes the shell left behind once it is killed
+	cmd.WaitDelay = time.Second
+	cmd.Env = os.Environ()
+	for key, value := range env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *HookRunner) callWebhook(ctx context.Context, webhook *types.WebhookHook, payload []byte, env map[string]string) error {
+	method := strings.ToUpper(webhook.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Matlas-Hook-Phase", env["MATLAS_HOOK_PHASE"])
+	req.Header.Set("X-Matlas-Project-Id", env["MATLAS_PROJECT_ID"])
+	for key, value := range webhook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		// Errors from the HTTP client include the URL, which may carry a token
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("webhook timed out")
+		}
+		return fmt.Errorf("webhook request failed")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// hookPayload encodes the operation given to hooks with credentials removed. The hooks
+// themselves are left out so webhook credentials are not passed around.
+func hookPayload(op *PlannedOperation, opErr error) ([]byte, error) {
+	payload := *op
+	payload.Hooks = nil
+	payload.Desired = WithoutCredentials(op.Desired)
+	payload.Current = WithoutCredentials(op.Current)
+	if opErr != nil {
+		payload.Status = OperationStatusFailed
+		payload.Error = opErr.Error()
+	}
+	return json.Marshal(payload)
+}
+
+// SetHookRunner replaces the runner used for operation hooks
+func (e *EnhancedExecutor) SetHookRunner(runner *HookRunner) {
+	e.hookRunner = runner
+}
+
+// executeWithHooks runs an operation between its preApply and postApply hooks. A failing
+// preApply hook vetoes the operation and a failing postApply hook fails it; onFailure hooks
+// run for either and for failures of the operation itself, and only produce warnings.
+func (e *EnhancedExecutor) executeWithHooks(ctx context.Context, plan *Plan, operation *PlannedOperation) (*OperationResult, error) {
+	if operation.Hooks.IsEmpty() || operation.Type == OperationNoChange || e.hookRunner == nil {
+		return e.executeEnhancedOperation(ctx, operation)
+	}
+
+	if err := e.hookRunner.Run(ctx, HookPhasePreApply, plan.ProjectID, operation, nil); err != nil {
+		now := time.Now()
+		e.runFailureHooks(ctx, plan, operation, err)
+		return &OperationResult{
+			OperationID: operation.ID,
+			Status:      OperationStatusFailed,
+			StartedAt:   now,
+			CompletedAt: now,
+			Error:       err.Error(),
+		}, err
+	}
+
+	result, err := e.executeEnhancedOperation(ctx, operation)
+	if err == nil {
+		if hookErr := e.hookRunner.Run(ctx, HookPhasePostApply, plan.ProjectID, operation, nil); hookErr != nil {
+			err = hookErr
+			if result != nil {
+				result.Status = OperationStatusFailed
+				result.Error = hookErr.Error()
+			}
+		}
+	}
+	if err != nil {
+		e.runFailureHooks(ctx, plan, operation, err)
+	}
+	return result, err
+}
+
+func (e *EnhancedExecutor) runFailureHooks(ctx context.Context, plan *Plan, operation *PlannedOperation, opErr error) {
+	if err := e.hookRunner.Run(ctx, HookPhaseOnFailure, plan.ProjectID, operation, opErr); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,309 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// recordingExecutor is a base executor that records the operations it runs
+type recordingExecutor struct {
+	executed []string
+	fail     map[string]error
+}
+
+func (r *recordingExecutor) Execute(ctx context.Context, plan *Plan) (*ExecutionResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *recordingExecutor) ExecuteOperation(ctx context.Context, op *PlannedOperation) (*OperationResult, error) {
+	r.executed = append(r.executed, op.ResourceName)
+	if err := r.fail[op.ResourceName]; err != nil {
+		return &OperationResult{OperationID: op.ID, Status: OperationStatusFailed, Error: err.Error()}, err
+	}
+	return &OperationResult{OperationID: op.ID, Status: OperationStatusCompleted}, nil
+}
+
+func (r *recordingExecutor) Cancel() error { return nil }
+
+func (r *recordingExecutor) GetProgress() *ExecutorProgress { return nil }
+
+func testHookRunner(output io.Writer) *HookRunner {
+	runner := NewHookRunner()
+	runner.output = output
+	return runner
+}
+
+func TestOperationHooks_MergesDocumentAndResourceHooks(t *testing.T) {
+	hooks := NewOperationHooks()
+	hooks.AddDocument(&types.ApplyDocument{
+		Hooks: &types.Hooks{PreApply: []types.Hook{{Name: "change-window", Command: "true"}}},
+		Resources: []types.ResourceManifest{
+			{Kind: types.KindCluster, Metadata: types.ResourceMetadata{Name: "main"},
+				Hooks: &types.Hooks{PreApply: []types.Hook{{Name: "backup", Command: "true"}}}},
+			{Kind: types.KindDatabaseUser, Metadata: types.ResourceMetadata{Name: "app"}},
+		},
+	})
+	hooks.AddDocument(&types.ApplyDocument{
+		Resources: []types.ResourceManifest{
+			{Kind: types.KindNetworkAccess, Metadata: types.ResourceMetadata{Name: "office"},
+				Hooks: &types.Hooks{PostApply: []types.Hook{{Name: "notify", Command: "true"}}}},
+		},
+	})
+
+	cluster := hooks.For(types.KindCluster, "main")
+	if cluster == nil || len(cluster.PreApply) != 2 || cluster.PreApply[0].Name != "change-window" || cluster.PreApply[1].Name != "backup" {
+		t.Errorf("Expected document hooks before resource hooks, got %+v", cluster)
+	}
+	if user := hooks.For(types.KindDatabaseUser, "app"); user == nil || len(user.PreApply) != 1 {
+		t.Errorf("Expected document hooks for the user, got %+v", user)
+	}
+	if network := hooks.For(types.KindNetworkAccess, "office"); network == nil || len(network.PreApply) != 0 || len(network.PostApply) != 1 {
+		t.Errorf("Expected only the resource's own hooks, got %+v", network)
+	}
+	// Deletes of undeclared resources fall back to the document hooks
+	if deleted := hooks.For(types.KindDatabaseUser, "legacy"); deleted == nil || deleted.PreApply[0].Name != "change-window" {
+		t.Errorf("Expected document hooks for undeclared resources, got %+v", deleted)
+	}
+
+	var none *OperationHooks
+	if none.For(types.KindCluster, "main") != nil {
+		t.Error("Expected no hooks from a nil mapping")
+	}
+}
+
+func TestHooksFor_FiltersByOperationType(t *testing.T) {
+	hooks := &types.Hooks{PreApply: []types.Hook{
+		{Name: "all", Command: "true"},
+		{Name: "deletes", Command: "true", Operations: []string{"Delete"}},
+	}}
+	if got := HooksFor(hooks, HookPhasePreApply, OperationCreate); len(got) != 1 || got[0].Name != "all" {
+		t.Errorf("Unexpected hooks for create: %+v", got)
+	}
+	if got := HooksFor(hooks, HookPhasePreApply, OperationDelete); len(got) != 2 {
+		t.Errorf("Expected both hooks for delete, got %+v", got)
+	}
+	if got := HooksFor(hooks, HookPhasePostApply, OperationDelete); len(got) != 0 {
+		t.Errorf("Expected no postApply hooks, got %+v", got)
+	}
+}
+
+func TestHookRunner_CommandReceivesOperation(t *testing.T) {
+	dir := t.TempDir()
+	stdinFile := filepath.Join(dir, "stdin.json")
+	envFile := filepath.Join(dir, "env.txt")
+
+	op := rollbackOp(OperationCreate, types.KindDatabaseUser, "app", 0, nil, rollbackUser("app", "s3cret-pass", ""))
+	op.ID = "op-0"
+	op.Hooks = &types.Hooks{PreApply: []types.Hook{{
+		Command: "cat > " + stdinFile + " && echo \"$MATLAS_HOOK_PHASE $MATLAS_OPERATION_TYPE $MATLAS_RESOURCE_KIND/$MATLAS_RESOURCE_NAME $MATLAS_PROJECT_ID\" > " + envFile,
+	}}}
+
+	if err := testHookRunner(io.Discard).Run(context.Background(), HookPhasePreApply, "proj", op, nil); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(stdinFile)
+	if err != nil {
+		t.Fatalf("Expected the hook to write stdin: %v", err)
+	}
+	var received PlannedOperation
+	if err := json.Unmarshal(data, &received); err != nil {
+		t.Fatalf("Expected the operation as JSON on stdin: %v", err)
+	}
+	if received.ID != "op-0" || received.ResourceName != "app" || received.Type != OperationCreate {
+		t.Errorf("Unexpected operation on stdin: %+v", received)
+	}
+	if strings.Contains(string(data), "s3cret-pass") {
+		t.Error("Expected the password to be redacted from the hook payload")
+	}
+
+	env, _ := os.ReadFile(envFile)
+	if got := strings.TrimSpace(string(env)); got != "preApply Create DatabaseUser/app proj" {
+		t.Errorf("Unexpected hook environment: %q", got)
+	}
+}
+
+func TestHookRunner_CommandFailure(t *testing.T) {
+	var output bytes.Buffer
+	op := rollbackOp(OperationDelete, types.KindCluster, "main", 0, rollbackCluster("main", "M10"), nil)
+	op.Hooks = &types.Hooks{PreApply: []types.Hook{{Name: "guard", Command: "echo refusing; exit 3"}}}
+
+	err := testHookRunner(&output).Run(context.Background(), HookPhasePreApply, "proj", op, nil)
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) || hookErr.Phase != HookPhasePreApply || hookErr.Hook != "guard" {
+		t.Fatalf("Expected a preApply HookError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "vetoed") {
+		t.Errorf("Expected a veto message, got %q", err.Error())
+	}
+	if !strings.Contains(output.String(), "refusing") {
+		t.Errorf("Expected hook output to be forwarded, got %q", output.String())
+	}
+
+	op.Hooks.PreApply[0] = types.Hook{Name: "slow", Command: "sleep 5", Timeout: "50ms"}
+	start := time.Now()
+	if err := testHookRunner(io.Discard).Run(context.Background(), HookPhasePreApply, "proj", op, nil); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("Expected the hook to be stopped at its timeout")
+	}
+}
+
+func TestHookRunner_Webhook(t *testing.T) {
+	var body []byte
+	var phase, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		phase = r.Header.Get("X-Matlas-Hook-Phase")
+		auth = r.Header.Get("Authorization")
+		if strings.HasSuffix(r.URL.Path, "/deny") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	op := rollbackOp(OperationUpdate, types.KindCluster, "main", 0, rollbackCluster("main", "M10"), rollbackCluster("main", "M30"))
+	op.Hooks = &types.Hooks{OnFailure: []types.Hook{{
+		Webhook: &types.WebhookHook{URL: server.URL + "/notify", Headers: map[string]string{"Authorization": "Bearer token"}},
+	}}}
+
+	if err := testHookRunner(io.Discard).Run(context.Background(), HookPhaseOnFailure, "proj", op, errors.New("boom")); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	var received PlannedOperation
+	if err := json.Unmarshal(body, &received); err != nil {
+		t.Fatalf("Expected the operation as the request body: %v", err)
+	}
+	if received.Error != "boom" || received.Status != OperationStatusFailed || received.Hooks != nil {
+		t.Errorf("Unexpected webhook payload: %+v", received)
+	}
+	if phase != "onFailure" || auth != "Bearer token" {
+		t.Errorf("Unexpected webhook headers: phase=%q auth=%q", phase, auth)
+	}
+
+	op.Hooks = &types.Hooks{PreApply: []types.Hook{{Webhook: &types.WebhookHook{URL: server.URL + "/deny"}}}}
+	err := testHookRunner(io.Discard).Run(context.Background(), HookPhasePreApply, "proj", op, nil)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected a non-2xx response to veto, got %v", err)
+	}
+	if strings.Contains(err.Error(), server.URL) {
+		t.Error("Expected the webhook URL not to appear in errors")
+	}
+}
+
+func TestEnhancedExecutor_RunsHooksAroundOperations(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "hooks.log")
+	record := func(label string) types.Hook {
+		return types.Hook{Name: label, Command: "echo " + label + " $MATLAS_RESOURCE_NAME >> " + logFile}
+	}
+
+	base := &recordingExecutor{fail: map[string]error{"broken": errors.New("boom")}}
+	executor := &EnhancedExecutor{baseExecutor: base, hookRunner: testHookRunner(io.Discard)}
+
+	plan := &Plan{ProjectID: "proj", Operations: []PlannedOperation{
+		*rollbackOp(OperationCreate, types.KindDatabaseUser, "app", 0, nil, rollbackUser("app", "", "")),
+		*rollbackOp(OperationDelete, types.KindDatabaseUser, "guarded", 0, rollbackUser("guarded", "", ""), nil),
+		*rollbackOp(OperationCreate, types.KindDatabaseUser, "broken", 0, nil, rollbackUser("broken", "", "")),
+	}}
+	plan.Operations[0].Hooks = &types.Hooks{PreApply: []types.Hook{record("pre")}, PostApply: []types.Hook{record("post")}}
+	plan.Operations[1].Hooks = &types.Hooks{
+		PreApply:  []types.Hook{{Name: "no-deletes", Command: "exit 1", Operations: []string{"Delete"}}},
+		OnFailure: []types.Hook{record("failed")},
+	}
+	plan.Operations[2].Hooks = &types.Hooks{PostApply: []types.Hook{record("post")}, OnFailure: []types.Hook{record("failed")}}
+
+	result, err := executor.executeEnhancedPlan(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("executeEnhancedPlan failed: %v", err)
+	}
+
+	if strings.Join(base.executed, ",") != "app,broken" {
+		t.Errorf("Expected the vetoed delete not to run, executed %v", base.executed)
+	}
+	if result.Summary.CompletedOperations != 1 || result.Summary.FailedOperations != 2 {
+		t.Errorf("Unexpected summary: %+v", result.Summary)
+	}
+	if !strings.Contains(result.Errors[0].Message, "no-deletes") {
+		t.Errorf("Expected the veto to be reported, got %q", result.Errors[0].Message)
+	}
+
+	log, _ := os.ReadFile(logFile)
+	want := "pre app\npost app\nfailed guarded\nfailed broken\n"
+	if string(log) != want {
+		t.Errorf("Unexpected hook order:\n%s\nwant:\n%s", log, want)
+	}
+}
+
+func TestValidateApplyDocument_Hooks(t *testing.T) {
+	doc := &types.ApplyDocument{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindApplyDocument,
+		Metadata:   types.MetadataConfig{Name: "hooks"},
+		Resources: []types.ResourceManifest{{
+			APIVersion: types.APIVersionV1,
+			Kind:       types.KindNetworkAccess,
+			Metadata:   types.ResourceMetadata{Name: "office"},
+			Spec:       types.NetworkAccessSpec{IPAddress: "203.0.113.10"},
+			Hooks: &types.Hooks{PostApply: []types.Hook{
+				{Command: "true", Webhook: &types.WebhookHook{URL: "https://example.com"}},
+				{Webhook: &types.WebhookHook{URL: "ftp://example.com/token"}},
+			}},
+		}},
+		Hooks: &types.Hooks{PreApply: []types.Hook{
+			{Command: "true", Operations: []string{"Destroy"}, Timeout: "soon"},
+		}},
+	}
+
+	result := ValidateApplyDocument(doc, nil)
+	var hookErrors []string
+	for _, err := range result.Errors {
+		if err.Code == "INVALID_HOOK" {
+			hookErrors = append(hookErrors, err.Path)
+		}
+	}
+	want := []string{
+		"hooks.preApply[0].operations[0]",
+		"hooks.preApply[0].timeout",
+		"resources[0].hooks.postApply[0]",
+		"resources[0].hooks.postApply[1].webhook.url",
+	}
+	if strings.Join(hookErrors, ",") != strings.Join(want, ",") {
+		t.Errorf("Unexpected hook errors: %v", hookErrors)
+	}
+}
+
+func TestRedactPlan_MasksWebhooks(t *testing.T) {
+	op := rollbackOp(OperationCreate, types.KindCluster, "main", 0, nil, rollbackCluster("main", "M10"))
+	op.Hooks = &types.Hooks{PostApply: []types.Hook{{Webhook: &types.WebhookHook{
+		URL:     "https://hooks.example.com/services/T000/B000/abcdef123456",
+		Headers: map[string]string{"Authorization": "Bearer abcdef123456"},
+	}}}}
+	plan := &Plan{Operations: []PlannedOperation{*op}}
+
+	data, err := json.Marshal(RedactPlan(plan))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "abcdef123456") {
+		t.Errorf("Expected webhook credentials to be masked: %s", data)
+	}
+	if plan.Operations[0].Hooks.PostApply[0].Webhook.Headers["Authorization"] != "Bearer abcdef123456" {
+		t.Error("Expected the original plan to be left untouched")
+	}
+}
@@ -24,6 +24,8 @@ type LoaderOptions struct {
 	Variables    map[string]string // Custom variables
 	AllowStdin   bool              // Allow reading from stdin
 	MaxFileSize  int64             // Maximum file size in bytes
+	ModuleCache  string            // Directory git module sources are cloned into (default ~/.matlas/modules)
+	Overlay      *Overlay          // Overlay whose patches are applied to ApplyDocuments
 }
 
 // DefaultLoaderOptions returns sensible defaults for the loader
@@ -44,6 +46,7 @@ type ConfigurationLoader struct {
 	options   *LoaderOptions
 	processor *TemplateProcessor
 	cache     *templateCache
+	modules   *ModuleResolver
 	mu        sync.RWMutex
 }
 
@@ -94,6 +97,7 @@ func NewConfigurationLoader(opts *LoaderOptions) *ConfigurationLoader {
 		options:   opts,
 		processor: processor,
 		cache:     cache,
+		modules:   NewModuleResolver(opts.ModuleCache),
 	}
 }
 
@@ -157,6 +161,9 @@ func (cl *ConfigurationLoader) LoadApplyConfig(source string) (*LoadResult, erro
 				if err := yaml.Unmarshal([]byte(result.ProcessedContent), &document); err != nil {
 					return result, fmt.Errorf("failed to parse ApplyDocument: %w", err)
 				}
+				if err := cl.resolveDocument(&document, source); err != nil {
+					return result, err
+				}
 				result.Config = &document
 				return result, nil
 			}
@@ -186,11 +193,40 @@ func (cl *ConfigurationLoader) LoadApplyDocument(source string) (*LoadResult, er
 	if err := yaml.Unmarshal([]byte(result.ProcessedContent), &document); err != nil {
 		return result, fmt.Errorf("failed to parse YAML: %w", err)
 	}
+	if err := cl.resolveDocument(&document, source); err != nil {
+		return result, err
+	}
 
 	result.Config = &document
 	return result, nil
 }
 
+// resolveDocument expands the forEach/count and ModuleInstance resources of a document and
+// applies the overlay's patches around the module expansion. Local module sources are
+// relative to the document's directory, or the working directory for stdin.
+func (cl *ConfigurationLoader) resolveDocument(document *types.ApplyDocument, source string) error {
+	baseDir := "."
+	if source != "-" && source != "stdin" {
+		baseDir = filepath.Dir(source)
+	}
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if err := ExpandForEach(document); err != nil {
+		return fmt.Errorf("failed to expand forEach: %w", err)
+	}
+	if err := cl.options.Overlay.Apply(document, true); err != nil {
+		return err
+	}
+	if err := cl.modules.Resolve(document, baseDir); err != nil {
+		return fmt.Errorf("failed to resolve modules: %w", err)
+	}
+	// Module templates may declare forEach resources of their own
+	if err := ExpandForEach(document); err != nil {
+		return fmt.Errorf("failed to expand forEach: %w", err)
+	}
+	return cl.options.Overlay.Apply(document, false)
+}
+
 // LoadMultiDocument loads and parses multiple YAML documents from a file
 func (cl *ConfigurationLoader) LoadMultiDocument(source string) ([]*LoadResult, error) {
 	// Load raw content
@@ -0,0 +1,730 @@
+package apply
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+const (
+	// ModuleManifestFile is the file describing a module at the root of its directory
+	ModuleManifestFile = "module.yaml"
+	// DefaultModuleTemplate is the template file used when a module does not name one
+	DefaultModuleTemplate = "template.yaml"
+
+	// maxModuleDepth bounds how deeply module instances may nest
+	maxModuleDepth = 10
+)
+
+var (
+	moduleInputRef  = regexp.MustCompile(`\$\{inputs\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+	moduleOutputRef = regexp.MustCompile(`\$\{module\.([A-Za-z0-9_/-]+)\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// ModuleResolver expands ModuleInstance resources into the resources their modules produce
+type ModuleResolver struct {
+	cacheDir string
+	gitPath  string
+	modules  map[string]*loadedModule
+}
+
+// loadedModule is a parsed module directory
+type loadedModule struct {
+	dir      string
+	manifest types.ModuleManifest
+	template []byte
+}
+
+// moduleScope tracks the outputs of the module instances resolved so far
+type moduleScope struct {
+	outputs map[string]map[string]interface{}
+}
+
+// NewModuleResolver creates a resolver that clones git module sources into cacheDir,
+// defaulting to ~/.matlas/modules
+func NewModuleResolver(cacheDir string) *ModuleResolver {
+	if cacheDir == "" {
+		homeDir, _ := os.UserHomeDir()
+		cacheDir = filepath.Join(homeDir, ".matlas", "modules")
+	}
+	return &ModuleResolver{
+		cacheDir: cacheDir,
+		gitPath:  "git",
+		modules:  make(map[string]*loadedModule),
+	}
+}
+
+// Resolve replaces the ModuleInstance resources of a document with the resources of their
+// modules and substitutes ${module.<instance>.<output>} references. Relative local sources
+// are resolved against baseDir. Generated resources are annotated with the instance that
+// produced them.
+func (r *ModuleResolver) Resolve(doc *types.ApplyDocument, baseDir string) error {
+	if !hasModuleReferences(doc.Resources) {
+		return nil
+	}
+	scope := &moduleScope{outputs: make(map[string]map[string]interface{})}
+	resources, err := r.expand(doc.Resources, baseDir, "", scope, nil)
+	if err != nil {
+		return err
+	}
+	doc.Resources = resources
+	return nil
+}
+
+func hasModuleReferences(resources []types.ResourceManifest) bool {
+	for _, resource := range resources {
+		if resource.Kind == types.KindModuleInstance {
+			return true
+		}
+	}
+	return false
+}
+
+// expand resolves the module instances in resources. parent is the name of the enclosing
+// instance ("" at the top level) and stack the module directories being expanded.
+func (r *ModuleResolver) expand(resources []types.ResourceManifest, baseDir, parent string, scope *moduleScope, stack []string) ([]types.ResourceManifest, error) {
+	var result []types.ResourceManifest
+	for _, resource := range resources {
+		if resource.Kind != types.KindModuleInstance {
+			result = append(result, resource)
+			continue
+		}
+		if len(stack) >= maxModuleDepth {
+			return nil, fmt.Errorf("module instance %s: modules are nested more than %d levels deep", resource.Metadata.Name, maxModuleDepth)
+		}
+
+		instance := resource.Metadata.Name
+		if instance == "" {
+			return nil, fmt.Errorf("module instance metadata.name is required")
+		}
+		if parent != "" {
+			instance = parent + "/" + instance
+		}
+		if _, exists := scope.outputs[instance]; exists {
+			return nil, fmt.Errorf("duplicate module instance %s", instance)
+		}
+
+		spec, err := decodeModuleInstanceSpec(resource.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("module instance %s: %w", instance, err)
+		}
+		inputs, err := scope.substitute(spec.Inputs, parent, true)
+		if err != nil {
+			return nil, fmt.Errorf("module instance %s: inputs: %w", instance, err)
+		}
+
+		module, err := r.load(spec, baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("module instance %s: %w", instance, err)
+		}
+		for _, dir := range stack {
+			if dir == module.dir {
+				return nil, fmt.Errorf("module instance %s: module %s includes itself", instance, module.manifest.Metadata.Name)
+			}
+		}
+
+		values, err := module.inputValues(inputs.(map[string]interface{}))
+		if err != nil {
+			return nil, fmt.Errorf("module instance %s (%s): %w", instance, module.ref(), err)
+		}
+		generated, err := module.render(values)
+		if err != nil {
+			return nil, fmt.Errorf("module instance %s (%s): %w", instance, module.ref(), err)
+		}
+		outputs, err := module.outputs(values)
+		if err != nil {
+			return nil, fmt.Errorf("module instance %s (%s): %w", instance, module.ref(), err)
+		}
+
+		for i := range generated {
+			annotateModuleResource(&generated[i], resource, instance, module.ref())
+		}
+		nested, err := r.expand(generated, module.dir, instance, scope, append(stack, module.dir))
+		if err != nil {
+			return nil, err
+		}
+		scope.outputs[instance] = outputs
+		result = append(result, nested...)
+	}
+
+	// Resources may reference instances declared after them, so outputs are substituted
+	// once every instance of this level is resolved. References nested modules cannot
+	// resolve are left for the enclosing level.
+	for i := range result {
+		if err := scope.substituteResource(&result[i], parent, parent == ""); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", result[i].Kind, result[i].Metadata.Name, err)
+		}
+	}
+	return result, nil
+}
+
+func decodeModuleInstanceSpec(spec interface{}) (*types.ModuleInstanceSpec, error) {
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	var instance types.ModuleInstanceSpec
+	if err := yaml.Unmarshal(data, &instance); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	if instance.Source == "" {
+		return nil, fmt.Errorf("spec.source is required")
+	}
+	if instance.Inputs == nil {
+		instance.Inputs = make(map[string]interface{})
+	}
+	return &instance, nil
+}
+
+// annotateModuleResource records the instance that produced a resource and applies the
+// instance's hooks, labels and dependencies to it. Nested instances pass them on, so
+// resources are annotated with the innermost instance.
+func annotateModuleResource(resource *types.ResourceManifest, instance types.ResourceManifest, name, ref string) {
+	metadata := &resource.Metadata
+	if metadata.Annotations == nil {
+		metadata.Annotations = make(map[string]string)
+	}
+	metadata.Annotations[types.AnnotationModule] = name
+	metadata.Annotations[types.AnnotationModuleSource] = ref
+	for key, value := range instance.Metadata.Labels {
+		if metadata.Labels == nil {
+			metadata.Labels = make(map[string]string)
+		}
+		if _, exists := metadata.Labels[key]; !exists {
+			metadata.Labels[key] = value
+		}
+	}
+	metadata.DependsOn = append(metadata.DependsOn, instance.Metadata.DependsOn...)
+	resource.Hooks = types.MergeHooks(instance.Hooks, resource.Hooks)
+}
+
+// ModuleOf returns the module instance that produced a resource manifest, if any
+func ModuleOf(resource interface{}) string {
+	value := reflect.ValueOf(resource)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+	field := value.FieldByName("Metadata")
+	if !field.IsValid() {
+		return ""
+	}
+	metadata, ok := field.Interface().(types.ResourceMetadata)
+	if !ok {
+		return ""
+	}
+	return metadata.Annotations[types.AnnotationModule]
+}
+
+// generatedAnnotations are set when loading to record where a resource was declared
+var generatedAnnotations = []string{
+	types.AnnotationModule,
+	types.AnnotationModuleSource,
+	types.AnnotationForEach,
+	types.AnnotationForEachKey,
+}
+
+// withoutGeneratedAnnotations returns a copy of a manifest pointer without the module and
+// forEach annotations, which are not compared when diffing
+func withoutGeneratedAnnotations(resource interface{}) interface{} {
+	value := reflect.ValueOf(resource)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return resource
+	}
+	field := value.Elem().FieldByName("Metadata")
+	if !field.IsValid() {
+		return resource
+	}
+	metadata, ok := field.Interface().(types.ResourceMetadata)
+	if !ok {
+		return resource
+	}
+	generated := false
+	for _, key := range generatedAnnotations {
+		if _, ok := metadata.Annotations[key]; ok {
+			generated = true
+		}
+	}
+	if !generated {
+		return resource
+	}
+
+	annotations := make(map[string]string, len(metadata.Annotations))
+	for key, v := range metadata.Annotations {
+		annotations[key] = v
+	}
+	for _, key := range generatedAnnotations {
+		delete(annotations, key)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	metadata.Annotations = annotations
+
+	clone := reflect.New(value.Elem().Type())
+	clone.Elem().Set(value.Elem())
+	clone.Elem().FieldByName("Metadata").Set(reflect.ValueOf(metadata))
+	return clone.Interface()
+}
+
+// load reads the module a ModuleInstance points to, cloning git sources
+func (r *ModuleResolver) load(spec *types.ModuleInstanceSpec, baseDir string) (*loadedModule, error) {
+	dir, err := r.moduleDir(spec, baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if module, ok := r.modules[dir]; ok {
+		return module, module.checkVersion(spec.Version)
+	}
+
+	// #nosec G304 -- module directories are supplied by the configuration author
+	data, err := os.ReadFile(filepath.Join(dir, ModuleManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module %s: %w", spec.Source, err)
+	}
+	module := &loadedModule{dir: dir}
+	if err := yaml.Unmarshal(data, &module.manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s of module %s: %w", ModuleManifestFile, spec.Source, err)
+	}
+	if err := module.validate(); err != nil {
+		return nil, fmt.Errorf("module %s: %w", spec.Source, err)
+	}
+
+	templatePath := module.manifest.Spec.Template
+	if templatePath == "" {
+		templatePath = DefaultModuleTemplate
+	}
+	if filepath.IsAbs(templatePath) || strings.HasPrefix(filepath.Clean(templatePath), "..") {
+		return nil, fmt.Errorf("module %s: template must be inside the module directory", spec.Source)
+	}
+	// #nosec G304 -- the template path is confined to the module directory
+	if module.template, err = os.ReadFile(filepath.Join(dir, templatePath)); err != nil {
+		return nil, fmt.Errorf("failed to read template of module %s: %w", spec.Source, err)
+	}
+
+	r.modules[dir] = module
+	return module, module.checkVersion(spec.Version)
+}
+
+// moduleDir returns the local directory of a module source
+func (r *ModuleResolver) moduleDir(spec *types.ModuleInstanceSpec, baseDir string) (string, error) {
+	repo, subdir, ref, isGit := parseModuleSource(spec.Source)
+	if !isGit {
+		dir := spec.Source
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		return filepath.Abs(dir)
+	}
+
+	if ref == "" {
+		ref = spec.Version
+	}
+	checkout, err := r.checkout(repo, ref)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(checkout, filepath.FromSlash(subdir))
+	if rel, err := filepath.Rel(checkout, dir); err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("module path %s is outside the repository", subdir)
+	}
+	return dir, nil
+}
+
+// parseModuleSource splits a git module source of the form
+// git::<repository>[//<subdir>][?ref=<ref>] (the git:: prefix is optional for URLs
+// ending in .git). Other sources are local paths.
+func parseModuleSource(source string) (repo, subdir, ref string, isGit bool) {
+	rest, prefixed := strings.CutPrefix(source, "git::")
+	if !prefixed && !strings.Contains(source, ".git") {
+		return "", "", "", false
+	}
+
+	if i := strings.LastIndex(rest, "?ref="); i >= 0 {
+		rest, ref = rest[:i], rest[i+len("?ref="):]
+	}
+	start := 0
+	if i := strings.Index(rest, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	if i := strings.Index(rest[start:], "//"); i >= 0 {
+		rest, subdir = rest[:start+i], rest[start+i+2:]
+	}
+	if !prefixed && !strings.HasSuffix(rest, ".git") {
+		return "", "", "", false
+	}
+	return rest, subdir, ref, true
+}
+
+// checkout clones a repository at a ref into the cache, reusing earlier clones. Refs
+// are expected to be tags or commits; delete the cache directory to refresh a branch.
+func (r *ModuleResolver) checkout(repo, ref string) (string, error) {
+	// git would read a leading dash as an option, such as --upload-pack=<command>
+	if strings.HasPrefix(repo, "-") {
+		return "", fmt.Errorf("invalid module repository %q: must not start with '-'", repo)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid module ref %q: must not start with '-'", ref)
+	}
+
+	sum := sha256.Sum256([]byte(repo + "@" + ref))
+	dir := filepath.Join(r.cacheDir, hex.EncodeToString(sum[:8]))
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(r.cacheDir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create module cache: %w", err)
+	}
+	tmp, err := os.MkdirTemp(r.cacheDir, "clone-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create module cache: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err := r.git("", "clone", "--quiet", "--", repo, tmp); err != nil {
+		return "", fmt.Errorf("failed to clone module repository %s: %w", repo, err)
+	}
+	if ref != "" {
+		if err := r.git(tmp, "checkout", "--quiet", ref); err != nil {
+			return "", fmt.Errorf("failed to check out %s of module repository %s: %w", ref, repo, err)
+		}
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return "", fmt.Errorf("failed to store module checkout: %w", err)
+	}
+	return dir, nil
+}
+
+func (r *ModuleResolver) git(dir string, args ...string) error {
+	cmd := exec.Command(r.gitPath, args...) // #nosec G204 -- arguments are passed directly without a shell
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// ref returns name@version, or the name of unversioned modules
+func (m *loadedModule) ref() string {
+	if m.manifest.Metadata.Version == "" {
+		return m.manifest.Metadata.Name
+	}
+	return m.manifest.Metadata.Name + "@" + m.manifest.Metadata.Version
+}
+
+func (m *loadedModule) validate() error {
+	if m.manifest.Kind != types.KindModule {
+		return fmt.Errorf("%s kind must be '%s', got '%s'", ModuleManifestFile, types.KindModule, m.manifest.Kind)
+	}
+	if m.manifest.Metadata.Name == "" {
+		return fmt.Errorf("metadata.name is required")
+	}
+	seen := make(map[string]bool)
+	for _, input := range m.manifest.Spec.Inputs {
+		if input.Name == "" {
+			return fmt.Errorf("input name is required")
+		}
+		if seen[input.Name] {
+			return fmt.Errorf("duplicate input %s", input.Name)
+		}
+		seen[input.Name] = true
+		switch input.Type {
+		case "", "string", "number", "bool", "list", "map":
+		default:
+			return fmt.Errorf("input %s: type must be string, number, bool, list or map, got '%s'", input.Name, input.Type)
+		}
+		if input.Default != nil {
+			if err := checkInputType(input, input.Default); err != nil {
+				return fmt.Errorf("default of %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkVersion verifies a pinned version against the module's declared version
+func (m *loadedModule) checkVersion(version string) error {
+	declared := m.manifest.Metadata.Version
+	if version == "" || declared == "" {
+		return nil
+	}
+	if strings.TrimPrefix(version, "v") != strings.TrimPrefix(declared, "v") {
+		return fmt.Errorf("module %s is version %s, but version %s is required", m.manifest.Metadata.Name, declared, version)
+	}
+	return nil
+}
+
+// inputValues checks the inputs of an instance against the module's declared inputs and
+// fills in defaults
+func (m *loadedModule) inputValues(inputs map[string]interface{}) (map[string]interface{}, error) {
+	declared := make(map[string]types.ModuleInput, len(m.manifest.Spec.Inputs))
+	for _, input := range m.manifest.Spec.Inputs {
+		declared[input.Name] = input
+	}
+	for name := range inputs {
+		if _, ok := declared[name]; !ok {
+			return nil, fmt.Errorf("unknown input %s", name)
+		}
+	}
+
+	values := make(map[string]interface{}, len(declared))
+	for _, input := range m.manifest.Spec.Inputs {
+		value, ok := inputs[input.Name]
+		if !ok || value == nil {
+			if input.Required {
+				return nil, fmt.Errorf("input %s is required", input.Name)
+			}
+			values[input.Name] = input.Default
+			continue
+		}
+		if err := checkInputType(input, value); err != nil {
+			return nil, err
+		}
+		if input.Type == "" || input.Type == "string" {
+			value = fmt.Sprint(value)
+		}
+		values[input.Name] = value
+	}
+	return values, nil
+}
+
+func checkInputType(input types.ModuleInput, value interface{}) error {
+	ok := true
+	switch input.Type {
+	case "", "string":
+		switch value.(type) {
+		case []interface{}, map[string]interface{}:
+			ok = false
+		}
+	case "number":
+		switch value.(type) {
+		case int, int64, uint64, float64:
+		default:
+			ok = false
+		}
+	case "bool":
+		_, ok = value.(bool)
+	case "list":
+		_, ok = value.([]interface{})
+	case "map":
+		_, ok = value.(map[string]interface{})
+	}
+	if !ok {
+		inputType := input.Type
+		if inputType == "" {
+			inputType = "string"
+		}
+		return fmt.Errorf("input %s must be a %s, got %v", input.Name, inputType, value)
+	}
+	return nil
+}
+
+// render substitutes ${inputs.<name>} placeholders in the module template and returns the
+// resources it declares. A placeholder that is a whole YAML value keeps the input's type.
+func (m *loadedModule) render(values map[string]interface{}) ([]types.ResourceManifest, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(m.template, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := renderNode(&root, values); err != nil {
+		return nil, err
+	}
+
+	var template struct {
+		Resources []types.ResourceManifest `yaml:"resources"`
+	}
+	if err := root.Decode(&template); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered template: %w", err)
+	}
+	if len(template.Resources) == 0 {
+		return nil, fmt.Errorf("template declares no resources")
+	}
+	return template.Resources, nil
+}
+
+func renderNode(node *yaml.Node, values map[string]interface{}) error {
+	if node.Kind != yaml.ScalarNode {
+		for _, child := range node.Content {
+			if err := renderNode(child, values); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !strings.Contains(node.Value, "${inputs.") {
+		return nil
+	}
+
+	rendered, err := renderString(node.Value, func(name string) (interface{}, bool) {
+		value, ok := values[name]
+		return value, ok
+	}, moduleInputRef, "input")
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	var replacement yaml.Node
+	if err := replacement.Encode(rendered); err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	replacement.Line, replacement.Column = node.Line, node.Column
+	*node = replacement
+	return nil
+}
+
+// renderString substitutes the references pattern matches in s. lookup receives the last
+// submatch of pattern. A reference that makes up the whole string is replaced by the
+// value itself; otherwise values are interpolated and must be scalars.
+func renderString(s string, lookup func(string) (interface{}, bool), pattern *regexp.Regexp, what string) (interface{}, error) {
+	if match := pattern.FindStringSubmatch(s); match != nil && match[0] == s {
+		value, ok := lookup(strings.Join(match[1:], "."))
+		if !ok {
+			return nil, fmt.Errorf("unknown %s %s", what, strings.Join(match[1:], "."))
+		}
+		return value, nil
+	}
+
+	var err error
+	rendered := pattern.ReplaceAllStringFunc(s, func(ref string) string {
+		match := pattern.FindStringSubmatch(ref)
+		name := strings.Join(match[1:], ".")
+		value, ok := lookup(name)
+		switch {
+		case !ok:
+			err = fmt.Errorf("unknown %s %s", what, name)
+		case value == nil:
+			return ""
+		default:
+			switch value.(type) {
+			case []interface{}, map[string]interface{}:
+				err = fmt.Errorf("%s %s is not a scalar and can only be used as a whole value", what, name)
+			}
+		}
+		return fmt.Sprint(value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+// outputs renders the module's outputs for an instance
+func (m *loadedModule) outputs(values map[string]interface{}) (map[string]interface{}, error) {
+	outputs := make(map[string]interface{}, len(m.manifest.Spec.Outputs))
+	for name, template := range m.manifest.Spec.Outputs {
+		value, err := renderString(template, func(input string) (interface{}, bool) {
+			value, ok := values[input]
+			return value, ok
+		}, moduleInputRef, "input")
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", name, err)
+		}
+		outputs[name] = value
+	}
+	return outputs, nil
+}
+
+// lookup finds an instance output, preferring instances nested in the current scope
+func (s *moduleScope) lookup(parent, ref string) (interface{}, bool) {
+	i := strings.LastIndex(ref, ".")
+	instance, output := ref[:i], ref[i+1:]
+	candidates := []string{instance}
+	if parent != "" {
+		candidates = []string{parent + "/" + instance, instance}
+	}
+	for _, candidate := range candidates {
+		if outputs, ok := s.outputs[candidate]; ok {
+			value, ok := outputs[output]
+			return value, ok
+		}
+	}
+	return nil, false
+}
+
+// substitute replaces ${module.<instance>.<output>} references in a YAML value. Unknown
+// references are an error when strict and left in place otherwise.
+func (s *moduleScope) substitute(value interface{}, parent string, strict bool) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "${module.") {
+			return v, nil
+		}
+		lookup := func(ref string) (interface{}, bool) {
+			if value, ok := s.lookup(parent, ref); ok {
+				return value, true
+			}
+			if !strict {
+				return "${module." + ref + "}", true
+			}
+			return nil, false
+		}
+		rendered, err := renderString(v, lookup, moduleOutputRef, "module output")
+		if err != nil {
+			return nil, err
+		}
+		return rendered, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			rendered, err := s.substitute(item, parent, strict)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			rendered, err := s.substitute(item, parent, strict)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+// substituteResource replaces module output references in a resource's name,
+// dependencies and spec
+func (s *moduleScope) substituteResource(resource *types.ResourceManifest, parent string, strict bool) error {
+	name, err := s.substitute(resource.Metadata.Name, parent, strict)
+	if err != nil {
+		return err
+	}
+	resource.Metadata.Name = fmt.Sprint(name)
+	for i, dependency := range resource.Metadata.DependsOn {
+		rendered, err := s.substitute(dependency, parent, strict)
+		if err != nil {
+			return err
+		}
+		resource.Metadata.DependsOn[i] = fmt.Sprint(rendered)
+	}
+	resource.Spec, err = s.substitute(resource.Spec, parent, strict)
+	return err
+}
@@ -0,0 +1,358 @@
+package apply
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+const testModuleManifest = `apiVersion: matlas.mongodb.com/v1
+kind: Module
+metadata:
+  name: team
+  version: 1.0.0
+spec:
+  inputs:
+    - name: team
+      required: true
+    - name: backup
+      type: bool
+      default: true
+    - name: cidrs
+      type: list
+      default: []
+  outputs:
+    cluster: ${inputs.team}-cluster
+`
+
+const testModuleTemplate = `resources:
+  - apiVersion: matlas.mongodb.com/v1
+    kind: Cluster
+    metadata:
+      name: ${inputs.team}-cluster
+    spec:
+      projectName: proj
+      instanceSize: M10
+      backupEnabled: ${inputs.backup}
+  - apiVersion: matlas.mongodb.com/v1
+    kind: NetworkAccess
+    metadata:
+      name: ${inputs.team}-office
+    spec:
+      cidrs: ${inputs.cidrs}
+      comment: office of ${inputs.team}
+`
+
+func writeModuleFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+}
+
+func moduleInstance(name, source string, inputs map[string]interface{}) types.ResourceManifest {
+	return types.ResourceManifest{
+		Kind:     types.KindModuleInstance,
+		Metadata: types.ResourceMetadata{Name: name},
+		Spec:     map[string]interface{}{"source": source, "inputs": inputs},
+	}
+}
+
+func TestModuleResolver_ExpandsInstances(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"modules/team/module.yaml":   testModuleManifest,
+		"modules/team/template.yaml": testModuleTemplate,
+	})
+
+	payments := moduleInstance("payments", "./modules/team", map[string]interface{}{"team": "payments", "backup": false, "cidrs": []interface{}{"10.0.0.0/8"}})
+	payments.Metadata.Labels = map[string]string{"cost-center": "42"}
+	payments.Hooks = &types.Hooks{PreApply: []types.Hook{{Name: "check", Command: "true"}}}
+
+	doc := &types.ApplyDocument{Kind: types.KindApplyDocument, Resources: []types.ResourceManifest{
+		// References an instance declared after it
+		{
+			Kind:     types.KindDatabaseUser,
+			Metadata: types.ResourceMetadata{Name: "reporting", DependsOn: []string{"${module.payments.cluster}"}},
+			Spec:     map[string]interface{}{"scopes": []interface{}{map[string]interface{}{"name": "${module.payments.cluster}"}}},
+		},
+		payments,
+		moduleInstance("search", "./modules/team", map[string]interface{}{"team": "${module.payments.cluster}-search"}),
+	}}
+
+	if err := NewModuleResolver(t.TempDir()).Resolve(doc, dir); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	var names []string
+	for _, resource := range doc.Resources {
+		names = append(names, string(resource.Kind)+"/"+resource.Metadata.Name)
+	}
+	want := []string{"DatabaseUser/reporting", "Cluster/payments-cluster", "NetworkAccess/payments-office", "Cluster/payments-cluster-search-cluster", "NetworkAccess/payments-cluster-search-office"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("Unexpected resources: %v", names)
+	}
+
+	reporting := doc.Resources[0]
+	if reporting.Metadata.DependsOn[0] != "payments-cluster" {
+		t.Errorf("Expected the output to be substituted, got %v", reporting.Metadata.DependsOn)
+	}
+	if scope := reporting.Spec.(map[string]interface{})["scopes"].([]interface{})[0].(map[string]interface{}); scope["name"] != "payments-cluster" {
+		t.Errorf("Expected the output to be substituted in the spec, got %v", scope)
+	}
+	if reporting.Metadata.Annotations[types.AnnotationModule] != "" {
+		t.Errorf("Resources declared in the document should not be annotated")
+	}
+
+	cluster := doc.Resources[1]
+	if backup := cluster.Spec.(map[string]interface{})["backupEnabled"]; backup != false {
+		t.Errorf("Expected a whole-value placeholder to keep the bool type, got %#v", backup)
+	}
+	if cluster.Metadata.Annotations[types.AnnotationModule] != "payments" || cluster.Metadata.Annotations[types.AnnotationModuleSource] != "team@1.0.0" {
+		t.Errorf("Unexpected annotations: %v", cluster.Metadata.Annotations)
+	}
+	if cluster.Metadata.Labels["cost-center"] != "42" || cluster.Hooks == nil || len(cluster.Hooks.PreApply) != 1 {
+		t.Errorf("Expected instance labels and hooks on generated resources, got %v %+v", cluster.Metadata.Labels, cluster.Hooks)
+	}
+
+	network := doc.Resources[2].Spec.(map[string]interface{})
+	if !reflect.DeepEqual(network["cidrs"], []interface{}{"10.0.0.0/8"}) || network["comment"] != "office of payments" {
+		t.Errorf("Unexpected network spec: %v", network)
+	}
+	if search := doc.Resources[3].Spec.(map[string]interface{}); search["backupEnabled"] != true {
+		t.Errorf("Expected the default input, got %v", search["backupEnabled"])
+	}
+}
+
+func TestModuleResolver_NestedModules(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"team/module.yaml":   testModuleManifest,
+		"team/template.yaml": testModuleTemplate,
+		"platform/module.yaml": `kind: Module
+metadata:
+  name: platform
+spec:
+  inputs:
+    - name: name
+      required: true
+  outputs:
+    cluster: ${inputs.name}
+`,
+		"platform/template.yaml": `resources:
+  - kind: ModuleInstance
+    metadata:
+      name: core
+    spec:
+      source: ../team
+      inputs:
+        team: ${inputs.name}
+  - kind: DatabaseUser
+    metadata:
+      name: ${inputs.name}-ops
+    spec:
+      scopes:
+        - name: ${module.core.cluster}
+`,
+	})
+
+	doc := &types.ApplyDocument{Resources: []types.ResourceManifest{
+		moduleInstance("platform", "platform", map[string]interface{}{"name": "shared"}),
+	}}
+	if err := NewModuleResolver(t.TempDir()).Resolve(doc, dir); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	modules := make(map[string]string)
+	for _, resource := range doc.Resources {
+		modules[resource.Metadata.Name] = resource.Metadata.Annotations[types.AnnotationModule]
+	}
+	want := map[string]string{"shared-cluster": "platform/core", "shared-office": "platform/core", "shared-ops": "platform"}
+	if !reflect.DeepEqual(modules, want) {
+		t.Errorf("Unexpected modules: %v", modules)
+	}
+	ops := doc.Resources[2].Spec.(map[string]interface{})["scopes"].([]interface{})[0].(map[string]interface{})
+	if ops["name"] != "shared-cluster" {
+		t.Errorf("Expected the nested instance output, got %v", ops["name"])
+	}
+}
+
+func TestModuleResolver_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"team/module.yaml":   testModuleManifest,
+		"team/template.yaml": testModuleTemplate,
+		"loop/module.yaml":   "kind: Module\nmetadata:\n  name: loop\n",
+		"loop/template.yaml": "resources:\n  - kind: ModuleInstance\n    metadata:\n      name: again\n    spec:\n      source: .\n",
+	})
+
+	withVersion := moduleInstance("a", "team", map[string]interface{}{"team": "a"})
+	withVersion.Spec.(map[string]interface{})["version"] = "2.0.0"
+	optionRef := moduleInstance("a", "git::https://github.com/org/modules.git//team", map[string]interface{}{"team": "a"})
+	optionRef.Spec.(map[string]interface{})["version"] = "--output=/tmp/x"
+
+	tests := map[string]struct {
+		resources []types.ResourceManifest
+		want      string
+	}{
+		"missing required input": {[]types.ResourceManifest{moduleInstance("a", "team", nil)}, "input team is required"},
+		"unknown input":          {[]types.ResourceManifest{moduleInstance("a", "team", map[string]interface{}{"team": "a", "size": "M10"})}, "unknown input size"},
+		"wrong type":             {[]types.ResourceManifest{moduleInstance("a", "team", map[string]interface{}{"team": "a", "backup": "yes"})}, "input backup must be a bool"},
+		"version mismatch":       {[]types.ResourceManifest{withVersion}, "version 2.0.0 is required"},
+		"missing module":         {[]types.ResourceManifest{moduleInstance("a", "nowhere", nil)}, "failed to read module nowhere"},
+		"self include":           {[]types.ResourceManifest{moduleInstance("a", "loop", nil)}, "includes itself"},
+		"option as repository":   {[]types.ResourceManifest{moduleInstance("a", "git::--upload-pack=touch /tmp/x", nil)}, "must not start with '-'"},
+		"option as ref":          {[]types.ResourceManifest{optionRef}, "must not start with '-'"},
+		"unknown output": {[]types.ResourceManifest{
+			{Kind: types.KindCluster, Metadata: types.ResourceMetadata{Name: "${module.nope.cluster}"}},
+			moduleInstance("a", "team", map[string]interface{}{"team": "a"}),
+		}, "unknown module output nope.cluster"},
+		"duplicate instance": {[]types.ResourceManifest{
+			moduleInstance("a", "team", map[string]interface{}{"team": "a"}),
+			moduleInstance("a", "team", map[string]interface{}{"team": "b"}),
+		}, "duplicate module instance a"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := NewModuleResolver(t.TempDir()).Resolve(&types.ApplyDocument{Resources: tt.resources}, dir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParseModuleSource(t *testing.T) {
+	tests := []struct {
+		source, repo, subdir, ref string
+		isGit                     bool
+	}{
+		{source: "./modules/team"},
+		{source: "../shared.github/modules"},
+		{source: "git::https://github.com/org/modules.git//team?ref=v1.2.0", repo: "https://github.com/org/modules.git", subdir: "team", ref: "v1.2.0", isGit: true},
+		{source: "https://github.com/org/modules.git", repo: "https://github.com/org/modules.git", isGit: true},
+		{source: "git::git@github.com:org/modules.git//a/b", repo: "git@github.com:org/modules.git", subdir: "a/b", isGit: true},
+		{source: "git::file:///srv/modules?ref=main", repo: "file:///srv/modules", ref: "main", isGit: true},
+	}
+	for _, tt := range tests {
+		repo, subdir, ref, isGit := parseModuleSource(tt.source)
+		if repo != tt.repo || subdir != tt.subdir || ref != tt.ref || isGit != tt.isGit {
+			t.Errorf("parseModuleSource(%q) = %q, %q, %q, %t", tt.source, repo, subdir, ref, isGit)
+		}
+	}
+}
+
+func TestModuleResolver_GitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	writeModuleFiles(t, repo, map[string]string{
+		"team/module.yaml":   testModuleManifest,
+		"team/template.yaml": testModuleTemplate,
+	})
+	resolver := NewModuleResolver(t.TempDir())
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "module"},
+		{"tag", "v1.0.0"},
+	} {
+		if err := resolver.git(repo, args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ { // the second resolution reuses the checkout
+		doc := &types.ApplyDocument{Resources: []types.ResourceManifest{
+			moduleInstance("a", "git::file://"+filepath.ToSlash(repo)+"//team", map[string]interface{}{"team": "a"}),
+		}}
+		doc.Resources[0].Spec.(map[string]interface{})["version"] = "v1.0.0"
+		if err := resolver.Resolve(doc, "."); err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if len(doc.Resources) != 2 || doc.Resources[0].Metadata.Name != "a-cluster" {
+			t.Fatalf("Unexpected resources: %+v", doc.Resources)
+		}
+	}
+	entries, _ := os.ReadDir(resolver.cacheDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected one cached checkout, got %d", len(entries))
+	}
+}
+
+func TestConfigurationLoader_ResolvesModulesRelativeToDocument(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFiles(t, dir, map[string]string{
+		"modules/team/module.yaml":   testModuleManifest,
+		"modules/team/template.yaml": testModuleTemplate,
+		"config/app.yaml": `apiVersion: matlas.mongodb.com/v1
+kind: ApplyDocument
+metadata:
+  name: app
+resources:
+  - kind: ModuleInstance
+    metadata:
+      name: payments
+    spec:
+      source: ../modules/team
+      inputs:
+        team: payments
+`,
+	})
+
+	result, err := NewConfigurationLoader(&LoaderOptions{ModuleCache: t.TempDir(), MaxFileSize: 1 << 20}).LoadApplyConfig(filepath.Join(dir, "config", "app.yaml"))
+	if err != nil {
+		t.Fatalf("LoadApplyConfig failed: %v", err)
+	}
+	doc := result.Config.(*types.ApplyDocument)
+	if len(doc.Resources) != 2 || doc.Resources[0].Kind != types.KindCluster {
+		t.Fatalf("Unexpected resources: %+v", doc.Resources)
+	}
+}
+
+func TestDiffEngine_IgnoresModuleAnnotations(t *testing.T) {
+	annotated := types.ClusterManifest{
+		Kind: types.KindCluster,
+		Metadata: types.ResourceMetadata{Name: "payments-cluster", Annotations: map[string]string{
+			types.AnnotationModule:       "payments",
+			types.AnnotationModuleSource: "team@1.0.0",
+		}},
+		Spec: types.ClusterSpec{InstanceSize: "M10"},
+	}
+	current := annotated
+	current.Metadata.Annotations = nil
+
+	diff, err := NewDiffEngine().ComputeProjectDiff(
+		&ProjectState{Clusters: []types.ClusterManifest{annotated}},
+		&ProjectState{Clusters: []types.ClusterManifest{current}},
+	)
+	if err != nil {
+		t.Fatalf("ComputeProjectDiff failed: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type != OperationNoChange {
+		t.Fatalf("Expected no change, got %+v", diff.Operations)
+	}
+	if annotated.Metadata.Annotations[types.AnnotationModule] != "payments" {
+		t.Errorf("The desired manifest must not be modified")
+	}
+
+	plan, err := NewPlanBuilder("proj").AddOperations(diff.Operations).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if plan.Operations[0].Module != "payments" {
+		t.Errorf("Expected the plan to record the module, got %q", plan.Operations[0].Module)
+	}
+}
@@ -0,0 +1,112 @@
+package apply
+
+import (
+	"sort"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/services/atlas"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// NetworkAccessExpiryWindow is how far ahead plans flag temporary network access entries
+const NetworkAccessExpiryWindow = 24 * time.Hour
+
+// ExpiringNetworkAccess is a temporary network access entry that expires within the window
+type ExpiringNetworkAccess struct {
+	ResourceName string        `json:"resourceName"`
+	Address      string        `json:"address"`
+	ExpiresAt    time.Time     `json:"expiresAt"`
+	ExpiresIn    time.Duration `json:"expiresIn"`
+}
+
+// networkAccessAddress returns the IP address, CIDR block or AWS security group an entry allows
+func networkAccessAddress(spec types.NetworkAccessSpec) string {
+	switch {
+	case spec.IPAddress != "":
+		return spec.IPAddress
+	case spec.CIDR != "":
+		return spec.CIDR
+	default:
+		return spec.AWSSecurityGroup
+	}
+}
+
+// pinNetworkAccessExpiry resolves expiresAt and ttl on a desired entry into a concrete
+// deleteAfterDate so plans show, and the executor sends, a fixed expiry. A ttl only
+// applies when the entry is created: an existing temporary entry keeps its expiry
+// instead of being pushed back on every plan.
+func pinNetworkAccessExpiry(entry types.NetworkAccessManifest, current *types.NetworkAccessManifest, now time.Time) types.NetworkAccessManifest {
+	spec := entry.Spec
+	if spec.TTL == "" && spec.ExpiresAt == "" {
+		return entry
+	}
+	if spec.TTL != "" && current != nil && current.Spec.DeleteAfterDate != "" && spec.DeleteAfterDate == "" && spec.ExpiresAt == "" {
+		entry.Spec.DeleteAfterDate = current.Spec.DeleteAfterDate
+		entry.Spec.TTL = ""
+		return entry
+	}
+	expiry, err := atlas.ResolveNetworkAccessExpiry(spec.DeleteAfterDate, spec.ExpiresAt, spec.TTL, now)
+	if err != nil || expiry == nil {
+		// Invalid combinations are reported by validation; leave the entry untouched
+		return entry
+	}
+	entry.Spec.DeleteAfterDate = expiry.UTC().Format(time.RFC3339)
+	entry.Spec.ExpiresAt = ""
+	entry.Spec.TTL = ""
+	return entry
+}
+
+// normalizeExpiry renders an RFC3339 timestamp in UTC so equal instants compare equal
+func normalizeExpiry(value string) string {
+	if value == "" {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// networkAccessExpiry returns the expiry of a network access resource, if it is temporary
+func networkAccessExpiry(resource interface{}) (*types.NetworkAccessManifest, time.Time, bool) {
+	manifest, ok := resource.(*types.NetworkAccessManifest)
+	if !ok || manifest == nil || manifest.Spec.DeleteAfterDate == "" {
+		return nil, time.Time{}, false
+	}
+	expiry, err := time.Parse(time.RFC3339, manifest.Spec.DeleteAfterDate)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	return manifest, expiry, true
+}
+
+// ExpiringNetworkAccessEntries lists the network access entries in a plan that expire
+// within the given window, soonest first. Entries being deleted are skipped.
+func ExpiringNetworkAccessEntries(plan *Plan, now time.Time, window time.Duration) []ExpiringNetworkAccess {
+	if plan == nil {
+		return nil
+	}
+	var entries []ExpiringNetworkAccess
+	for _, op := range plan.Operations {
+		if op.ResourceType != types.KindNetworkAccess || op.Type == OperationDelete {
+			continue
+		}
+		resource := op.Desired
+		if resource == nil {
+			resource = op.Current
+		}
+		manifest, expiry, ok := networkAccessExpiry(resource)
+		if !ok || expiry.Sub(now) > window {
+			continue
+		}
+		entries = append(entries, ExpiringNetworkAccess{
+			ResourceName: op.ResourceName,
+			Address:      networkAccessAddress(manifest.Spec),
+			ExpiresAt:    expiry,
+			ExpiresIn:    expiry.Sub(now).Truncate(time.Minute),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ExpiresAt.Before(entries[j].ExpiresAt) })
+	return entries
+}
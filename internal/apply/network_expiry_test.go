@@ -0,0 +1,38 @@
+package apply
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func TestExpiringNetworkAccessEntries(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	entry := func(address string, expiresIn time.Duration) *types.NetworkAccessManifest {
+		spec := types.NetworkAccessSpec{IPAddress: address}
+		if expiresIn != 0 {
+			spec.DeleteAfterDate = now.Add(expiresIn).Format(time.RFC3339)
+		}
+		return &types.NetworkAccessManifest{Metadata: types.ResourceMetadata{Name: address}, Spec: spec}
+	}
+
+	plan := &Plan{Operations: []PlannedOperation{
+		{Operation: Operation{Type: OperationCreate, ResourceType: types.KindNetworkAccess, ResourceName: "later", Desired: entry("203.0.113.1", 6*time.Hour)}},
+		{Operation: Operation{Type: OperationNoChange, ResourceType: types.KindNetworkAccess, ResourceName: "soon", Current: entry("203.0.113.2", 90*time.Minute)}},
+		{Operation: Operation{Type: OperationNoChange, ResourceType: types.KindNetworkAccess, ResourceName: "next-week", Current: entry("203.0.113.3", 72*time.Hour)}},
+		{Operation: Operation{Type: OperationNoChange, ResourceType: types.KindNetworkAccess, ResourceName: "permanent", Current: entry("203.0.113.4", 0)}},
+		{Operation: Operation{Type: OperationDelete, ResourceType: types.KindNetworkAccess, ResourceName: "removed", Current: entry("203.0.113.5", time.Hour)}},
+	}}
+
+	entries := ExpiringNetworkAccessEntries(plan, now, NetworkAccessExpiryWindow)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 expiring entries, got %+v", entries)
+	}
+	if entries[0].ResourceName != "soon" || entries[0].Address != "203.0.113.2" || entries[0].ExpiresIn != 90*time.Minute {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].ResourceName != "later" {
+		t.Errorf("Expected entries sorted by expiry, got %+v", entries)
+	}
+}
@@ -10,7 +10,8 @@ import (
 
 // PlanOptimizer optimizes execution plans for efficiency and resource utilization
 type PlanOptimizer struct {
-	config OptimizationConfig
+	config  OptimizationConfig
+	history *DurationHistory
 }
 
 // OptimizationConfig contains settings for plan optimization
@@ -136,6 +137,12 @@ func (po *PlanOptimizer) WithConfig(config OptimizationConfig) *PlanOptimizer {
 	return po
 }
 
+// WithDurationHistory uses recorded operation durations to start slow operations first
+func (po *PlanOptimizer) WithDurationHistory(history *DurationHistory) *PlanOptimizer {
+	po.history = history
+	return po
+}
+
 // WithMaxParallelOps sets the maximum parallel operations.
 func (po *PlanOptimizer) WithMaxParallelOps(maxOps int) *PlanOptimizer {
 	po.config.MaxParallelOperations = maxOps
@@ -175,6 +182,10 @@ func (po *PlanOptimizer) OptimizePlan(plan *Plan) (*OptimizationResult, error) {
 	// 4. Stage consolidation
 	stageActions := po.optimizeStages(optimizedPlan)
 	optimizations = append(optimizations, stageActions...)
+	if len(stageActions) > 0 {
+		summary := &optimizedPlan.Summary
+		summary.EstimatedDurationLow, summary.EstimatedDurationHigh, summary.HistoricalEstimates = EstimatePlanETA(optimizedPlan.Operations)
+	}
 
 	// Calculate performance improvements
 	improvements := po.calculatePerformanceGains(plan, optimizedPlan)
@@ -508,6 +519,11 @@ func (po *PlanOptimizer) calculateOperationPriority(op PlannedOperation) float64
 		score -= float64(depCount) * po.config.PriorityWeights.Dependencies
 	}
 
+	// Estimated time weight (longer operations start first so they do not extend the stage)
+	if estimate, ok := po.history.Estimate(DurationKeyFor(op.Operation)); ok {
+		score += estimate.P50.Minutes() * po.config.PriorityWeights.EstimatedTime
+	}
+
 	return score
 }
 
@@ -0,0 +1,523 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// OverlayFile is the file describing an overlay at the root of its directory
+const OverlayFile = "overlay.yaml"
+
+// patchDirective is the strategic merge key that deletes the resource or list item it is set on
+const patchDirective = "$patch"
+
+// Overlay patches the resources of loaded ApplyDocuments, so environments can share one
+// base configuration
+type Overlay struct {
+	Dir      string
+	Manifest types.OverlayManifest
+	patches  []*overlayPatch
+}
+
+type overlayPatch struct {
+	source     string
+	target     types.PatchTarget
+	merge      map[string]interface{}
+	operations []types.JSONPatchOperation
+	matched    bool
+}
+
+// LoadOverlay reads the overlay.yaml of an overlay directory and the patch files it names
+func LoadOverlay(dir string) (*Overlay, error) {
+	// #nosec G304 -- overlay directories are supplied by the user
+	data, err := os.ReadFile(filepath.Join(dir, OverlayFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay: %w", err)
+	}
+	overlay := &Overlay{Dir: dir}
+	if err := yaml.Unmarshal(data, &overlay.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay %s: %w", dir, err)
+	}
+	if overlay.Manifest.Kind != types.KindOverlay {
+		return nil, fmt.Errorf("%s: kind must be '%s', got '%s'", filepath.Join(dir, OverlayFile), types.KindOverlay, overlay.Manifest.Kind)
+	}
+
+	for i, spec := range overlay.Manifest.Spec.Patches {
+		patch, err := overlay.loadPatch(i, spec)
+		if err != nil {
+			return nil, fmt.Errorf("overlay %s: %w", dir, err)
+		}
+		overlay.patches = append(overlay.patches, patch)
+	}
+	return overlay, nil
+}
+
+func (o *Overlay) loadPatch(index int, spec types.OverlayPatch) (*overlayPatch, error) {
+	patch := &overlayPatch{source: fmt.Sprintf("patches[%d]", index), merge: spec.Patch, operations: spec.Operations}
+
+	set := 0
+	for _, present := range []bool{spec.Patch != nil, len(spec.Operations) > 0, spec.Path != ""} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("%s: exactly one of patch, operations or path is required", patch.source)
+	}
+
+	if spec.Path != "" {
+		path := o.path(spec.Path)
+		patch.source = path
+		data, err := os.ReadFile(path) // #nosec G304 -- patch files are named by the overlay
+		if err != nil {
+			return nil, fmt.Errorf("failed to read patch: %w", err)
+		}
+		var content interface{}
+		if err := yaml.Unmarshal(data, &content); err != nil {
+			return nil, fmt.Errorf("failed to parse patch %s: %w", path, err)
+		}
+		// A list is a JSON patch, a mapping a strategic merge patch
+		if _, isList := content.([]interface{}); isList {
+			if err := yaml.Unmarshal(data, &patch.operations); err != nil {
+				return nil, fmt.Errorf("failed to parse JSON patch %s: %w", path, err)
+			}
+		} else if patch.merge, _ = content.(map[string]interface{}); patch.merge == nil {
+			return nil, fmt.Errorf("patch %s must be a mapping or a list of JSON patch operations", path)
+		}
+	}
+
+	switch {
+	case spec.Target != nil:
+		patch.target = *spec.Target
+	case patch.merge != nil:
+		patch.target.Kind = types.ResourceKind(fmt.Sprint(patch.merge["kind"]))
+		if metadata, ok := patch.merge["metadata"].(map[string]interface{}); ok {
+			patch.target.Name = fmt.Sprint(metadata["name"])
+		}
+	}
+	if patch.target.Kind == "" || patch.target.Kind == "<nil>" || patch.target.Name == "" || patch.target.Name == "<nil>" {
+		return nil, fmt.Errorf("%s: target kind and name are required", patch.source)
+	}
+
+	for _, op := range patch.operations {
+		switch op.Op {
+		case "add", "remove", "replace", "move", "copy", "test":
+		default:
+			return nil, fmt.Errorf("%s: unsupported JSON patch operation '%s'", patch.source, op.Op)
+		}
+	}
+	return patch, nil
+}
+
+// path resolves a path relative to the overlay directory
+func (o *Overlay) path(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(o.Dir, path)
+}
+
+// Bases returns the overlay's base configuration files
+func (o *Overlay) Bases() []string {
+	bases := make([]string, 0, len(o.Manifest.Spec.Bases))
+	for _, base := range o.Manifest.Spec.Bases {
+		bases = append(bases, o.path(base))
+	}
+	return bases
+}
+
+// Variables returns the overlay's variables: its variable files, then inline variables
+func (o *Overlay) Variables() (map[string]string, error) {
+	files := make([]string, 0, len(o.Manifest.Spec.VarFiles))
+	for _, file := range o.Manifest.Spec.VarFiles {
+		files = append(files, o.path(file))
+	}
+	vars, err := LoadVariableFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range o.Manifest.Spec.Variables {
+		if err := checkVariableName(name); err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// Apply patches the resources of a document. Patches targeting ModuleInstance resources
+// are applied when moduleInstances is true, before modules are expanded; the others
+// afterwards, so they can patch the resources modules produce.
+func (o *Overlay) Apply(doc *types.ApplyDocument, moduleInstances bool) error {
+	if o == nil {
+		return nil
+	}
+	for _, patch := range o.patches {
+		if (patch.target.Kind == types.KindModuleInstance) != moduleInstances {
+			continue
+		}
+		for i := 0; i < len(doc.Resources); i++ {
+			resource := doc.Resources[i]
+			if resource.Kind != patch.target.Kind || resource.Metadata.Name != patch.target.Name {
+				continue
+			}
+			patch.matched = true
+			patched, deleted, err := patch.apply(resource)
+			if err != nil {
+				return fmt.Errorf("overlay %s: %s: %w", o.Dir, patch.source, err)
+			}
+			if deleted {
+				doc.Resources = append(doc.Resources[:i], doc.Resources[i+1:]...)
+				i--
+				continue
+			}
+			doc.Resources[i] = *patched
+		}
+	}
+	return nil
+}
+
+// Unmatched returns the targets of patches that matched no resource
+func (o *Overlay) Unmatched() []string {
+	if o == nil {
+		return nil
+	}
+	var unmatched []string
+	for _, patch := range o.patches {
+		if !patch.matched {
+			unmatched = append(unmatched, ExecutionOperationKey(patch.target.Kind, patch.target.Name))
+		}
+	}
+	return unmatched
+}
+
+// apply returns the patched resource, or deleted when a merge patch sets $patch: delete
+func (p *overlayPatch) apply(resource types.ResourceManifest) (*types.ResourceManifest, bool, error) {
+	var doc interface{}
+	data, err := yaml.Marshal(resource)
+	if err == nil {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode %s %s: %w", resource.Kind, resource.Metadata.Name, err)
+	}
+
+	if p.merge != nil {
+		if p.merge[patchDirective] == "delete" {
+			return nil, true, nil
+		}
+		doc = StrategicMerge(doc, p.merge)
+	} else if doc, err = ApplyJSONPatch(doc, p.operations); err != nil {
+		return nil, false, err
+	}
+
+	var patched types.ResourceManifest
+	data, err = yaml.Marshal(doc)
+	if err == nil {
+		err = yaml.Unmarshal(data, &patched)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("patched resource is invalid: %w", err)
+	}
+	return &patched, false, nil
+}
+
+// StrategicMerge merges patch into base. Mappings are merged recursively and a null value
+// removes a key. Lists of mappings that all have a name are merged by name, where an item
+// with $patch: delete removes the item; other lists and values are replaced.
+func StrategicMerge(base, patch interface{}) interface{} {
+	switch p := patch.(type) {
+	case map[string]interface{}:
+		b, ok := base.(map[string]interface{})
+		if !ok {
+			b = map[string]interface{}{}
+		}
+		merged := make(map[string]interface{}, len(b)+len(p))
+		for key, value := range b {
+			merged[key] = value
+		}
+		for key, value := range p {
+			switch {
+			case key == patchDirective:
+			case value == nil:
+				delete(merged, key)
+			default:
+				merged[key] = StrategicMerge(merged[key], value)
+			}
+		}
+		return merged
+	case []interface{}:
+		b, ok := base.([]interface{})
+		if !ok || !namedItems(b) || !namedItems(p) {
+			return patch
+		}
+		merged := append([]interface{}{}, b...)
+		for _, item := range p {
+			patchItem := item.(map[string]interface{})
+			index := -1
+			for i, existing := range merged {
+				if existing.(map[string]interface{})["name"] == patchItem["name"] {
+					index = i
+					break
+				}
+			}
+			switch {
+			case patchItem[patchDirective] == "delete":
+				if index >= 0 {
+					merged = append(merged[:index], merged[index+1:]...)
+				}
+			case index >= 0:
+				merged[index] = StrategicMerge(merged[index], patchItem)
+			default:
+				merged = append(merged, StrategicMerge(nil, patchItem))
+			}
+		}
+		return merged
+	default:
+		return patch
+	}
+}
+
+func namedItems(items []interface{}) bool {
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m["name"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyJSONPatch applies RFC 6902 operations to a YAML value
+func ApplyJSONPatch(doc interface{}, operations []types.JSONPatchOperation) (interface{}, error) {
+	for i, op := range operations {
+		var err error
+		switch op.Op {
+		case "add":
+			doc, err = setPointer(doc, op.Path, copyValue(op.Value), true)
+		case "replace":
+			if _, err = getPointer(doc, op.Path); err == nil {
+				doc, err = setPointer(doc, op.Path, copyValue(op.Value), false)
+			}
+		case "remove":
+			doc, err = removePointer(doc, op.Path)
+		case "move", "copy":
+			var value interface{}
+			if value, err = getPointer(doc, op.From); err != nil {
+				break
+			}
+			if op.Op == "move" {
+				if doc, err = removePointer(doc, op.From); err != nil {
+					break
+				}
+			} else {
+				value = copyValue(value)
+			}
+			doc, err = setPointer(doc, op.Path, value, true)
+		case "test":
+			var value interface{}
+			if value, err = getPointer(doc, op.Path); err == nil && !reflect.DeepEqual(value, op.Value) {
+				err = fmt.Errorf("value is %v, not %v", value, op.Value)
+			}
+		default:
+			err = fmt.Errorf("unsupported operation")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("JSON patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// parsePointer splits an RFC 6901 JSON pointer into unescaped tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("path must start with /")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func listIndex(token string, length int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index >= length {
+		return 0, fmt.Errorf("invalid list index %s", token)
+	}
+	return index, nil
+}
+
+func getPointer(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	node := doc
+	for _, token := range tokens {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+			node = child
+		case []interface{}:
+			index, err := listIndex(token, len(n))
+			if err != nil {
+				return nil, err
+			}
+			node = n[index]
+		default:
+			return nil, fmt.Errorf("path not found")
+		}
+	}
+	return node, nil
+}
+
+// setPointer sets the value at a pointer. With insert, values are added to lists at the
+// index (or appended with -) and missing keys are created; otherwise they replace.
+func setPointer(doc interface{}, pointer string, value interface{}, insert bool) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return setTokens(doc, tokens, value, insert)
+}
+
+func setTokens(node interface{}, tokens []string, value interface{}, insert bool) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	token, last := tokens[0], len(tokens) == 1
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if last {
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("path not found")
+		}
+		updated, err := setTokens(child, tokens[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = updated
+		return n, nil
+	case []interface{}:
+		if last && insert {
+			if token == "-" {
+				return append(n, value), nil
+			}
+			index, err := listIndex(token, len(n)+1)
+			if err != nil {
+				return nil, err
+			}
+			n = append(n, nil)
+			copy(n[index+1:], n[index:])
+			n[index] = value
+			return n, nil
+		}
+		index, err := listIndex(token, len(n))
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			n[index] = value
+			return n, nil
+		}
+		updated, err := setTokens(n[index], tokens[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = updated
+		return n, nil
+	default:
+		return nil, fmt.Errorf("path not found")
+	}
+}
+
+func removePointer(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("cannot remove the whole resource")
+	}
+	return removeTokens(doc, tokens)
+}
+
+func removeTokens(node interface{}, tokens []string) (interface{}, error) {
+	token, last := tokens[0], len(tokens) == 1
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("path not found")
+		}
+		if last {
+			delete(n, token)
+			return n, nil
+		}
+		updated, err := removeTokens(child, tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		n[token] = updated
+		return n, nil
+	case []interface{}:
+		index, err := listIndex(token, len(n))
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			return append(n[:index], n[index+1:]...), nil
+		}
+		updated, err := removeTokens(n[index], tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		n[index] = updated
+		return n, nil
+	default:
+		return nil, fmt.Errorf("path not found")
+	}
+}
+
+// copyValue deep-copies a YAML value so patches never share state with documents
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = copyValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = copyValue(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
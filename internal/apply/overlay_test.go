@@ -0,0 +1,307 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func parseYAML(t *testing.T, content string) interface{} {
+	t.Helper()
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(content), &value); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return value
+}
+
+func TestStrategicMerge(t *testing.T) {
+	base := parseYAML(t, `
+spec:
+  instanceSize: M10
+  tags: {team: orders, owner: alice}
+  scopes:
+    - {name: a, type: CLUSTER}
+    - {name: b, type: CLUSTER}
+  roles:
+    - {roleName: readWrite, databaseName: orders}
+`)
+	patch := parseYAML(t, `
+spec:
+  instanceSize: M30
+  tags: {owner: null, tier: gold}
+  scopes:
+    - {name: b, type: DATA_LAKE}
+    - {name: a, $patch: delete}
+    - {name: c, type: CLUSTER}
+  roles:
+    - {roleName: read, databaseName: reporting}
+`)
+	want := parseYAML(t, `
+spec:
+  instanceSize: M30
+  tags: {team: orders, tier: gold}
+  scopes:
+    - {name: b, type: DATA_LAKE}
+    - {name: c, type: CLUSTER}
+  roles:
+    - {roleName: read, databaseName: reporting}
+`)
+
+	if got := StrategicMerge(base, patch); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected merge:\n got %v\nwant %v", got, want)
+	}
+	if base.(map[string]interface{})["spec"].(map[string]interface{})["instanceSize"] != "M10" {
+		t.Error("Expected the base to be left untouched")
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := parseYAML(t, `
+spec:
+  instanceSize: M10
+  tags: {a/b: x, "c~d": y}
+  roles: [{roleName: readWrite}]
+`)
+	operations := []types.JSONPatchOperation{
+		{Op: "test", Path: "/spec/instanceSize", Value: "M10"},
+		{Op: "replace", Path: "/spec/instanceSize", Value: "M30"},
+		{Op: "add", Path: "/spec/roles/-", Value: map[string]interface{}{"roleName": "read"}},
+		{Op: "add", Path: "/spec/roles/0", Value: map[string]interface{}{"roleName": "dbAdmin"}},
+		{Op: "remove", Path: "/spec/tags/a~1b"},
+		{Op: "move", From: "/spec/tags/c~0d", Path: "/spec/owner"},
+		{Op: "copy", From: "/spec/instanceSize", Path: "/spec/tags/size"},
+	}
+	got, err := ApplyJSONPatch(doc, operations)
+	if err != nil {
+		t.Fatalf("ApplyJSONPatch failed: %v", err)
+	}
+	want := parseYAML(t, `
+spec:
+  instanceSize: M30
+  owner: y
+  tags: {size: M30}
+  roles: [{roleName: dbAdmin}, {roleName: readWrite}, {roleName: read}]
+`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected patch result:\n got %v\nwant %v", got, want)
+	}
+
+	for _, op := range []types.JSONPatchOperation{
+		{Op: "test", Path: "/spec/instanceSize", Value: "M10"},
+		{Op: "replace", Path: "/spec/missing", Value: 1},
+		{Op: "remove", Path: "/spec/roles/9"},
+		{Op: "add", Path: "/spec/nested/key", Value: 1},
+		{Op: "add", Path: "spec", Value: 1},
+	} {
+		if _, err := ApplyJSONPatch(got, []types.JSONPatchOperation{op}); err == nil {
+			t.Errorf("Expected %s %s to fail", op.Op, op.Path)
+		}
+	}
+}
+
+func writeOverlay(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestLoadOverlay_RejectsInvalidPatches(t *testing.T) {
+	for name, patches := range map[string]string{
+		"none":         "    - target: {kind: Cluster, name: c}\n",
+		"two":          "    - target: {kind: Cluster, name: c}\n      patch: {spec: {}}\n      path: p.yaml\n",
+		"no target":    "    - operations: [{op: remove, path: /spec/x}]\n",
+		"unsupported":  "    - target: {kind: Cluster, name: c}\n      operations: [{op: merge, path: /spec}]\n",
+		"missing file": "    - path: missing.yaml\n",
+	} {
+		dir := writeOverlay(t, map[string]string{OverlayFile: "kind: Overlay\nspec:\n  patches:\n" + patches})
+		if _, err := LoadOverlay(dir); err == nil {
+			t.Errorf("%s: expected the overlay to be rejected", name)
+		}
+	}
+
+	dir := writeOverlay(t, map[string]string{OverlayFile: "kind: ApplyDocument\n"})
+	if _, err := LoadOverlay(dir); err == nil || !strings.Contains(err.Error(), "kind must be 'Overlay'") {
+		t.Errorf("Expected a kind error, got %v", err)
+	}
+}
+
+func TestOverlay_VariablesAndBases(t *testing.T) {
+	dir := writeOverlay(t, map[string]string{
+		OverlayFile: `kind: Overlay
+spec:
+  bases: [../base/project.yaml]
+  varFiles: [env.yaml]
+  variables:
+    REGION: EU_WEST_1
+`,
+		"env.yaml": "REGION: US_EAST_1\nSIZE: M30\n",
+	})
+	overlay, err := LoadOverlay(dir)
+	if err != nil {
+		t.Fatalf("LoadOverlay failed: %v", err)
+	}
+	vars, err := overlay.Variables()
+	if err != nil {
+		t.Fatalf("Variables failed: %v", err)
+	}
+	if !reflect.DeepEqual(vars, map[string]string{"REGION": "EU_WEST_1", "SIZE": "M30"}) {
+		t.Errorf("Expected inline variables to override variable files, got %v", vars)
+	}
+	if bases := overlay.Bases(); len(bases) != 1 || bases[0] != filepath.Join(dir, "..", "base", "project.yaml") {
+		t.Errorf("Expected bases relative to the overlay, got %v", bases)
+	}
+}
+
+func TestConfigurationLoader_AppliesOverlay(t *testing.T) {
+	dir := writeOverlay(t, map[string]string{
+		"modules/app/module.yaml": `kind: Module
+metadata:
+  name: app
+spec:
+  inputs:
+    - name: size
+      required: true
+`,
+		"modules/app/template.yaml": `resources:
+  - kind: Cluster
+    metadata:
+      name: app
+    spec:
+      projectName: proj
+      instanceSize: ${inputs.size}
+`,
+		"base.yaml": `apiVersion: matlas.mongodb.com/v1
+kind: ApplyDocument
+metadata:
+  name: base
+resources:
+  - kind: ModuleInstance
+    metadata:
+      name: app
+    spec:
+      source: ./modules/app
+      inputs:
+        size: M10
+  - kind: NetworkAccess
+    metadata:
+      name: office
+    spec:
+      projectName: proj
+      cidr: ${CIDR}
+  - kind: NetworkAccess
+    metadata:
+      name: legacy
+    spec:
+      projectName: proj
+      cidr: 10.0.0.0/8
+`,
+		"prod/" + OverlayFile: `kind: Overlay
+spec:
+  patches:
+    - patch:
+        kind: ModuleInstance
+        metadata:
+          name: app
+        spec:
+          inputs:
+            size: M30
+    - path: cluster.yaml
+    - patch:
+        kind: NetworkAccess
+        metadata:
+          name: legacy
+        $patch: delete
+    - target: {kind: NetworkAccess, name: office}
+      operations:
+        - {op: add, path: /spec/comment, value: office}
+    - target: {kind: DatabaseUser, name: ghost}
+      operations:
+        - {op: remove, path: /spec/password}
+`,
+		"prod/cluster.yaml": "kind: Cluster\nmetadata:\n  name: app\nspec:\n  backupEnabled: true\n",
+	})
+
+	overlay, err := LoadOverlay(filepath.Join(dir, "prod"))
+	if err != nil {
+		t.Fatalf("LoadOverlay failed: %v", err)
+	}
+	loader := NewConfigurationLoader(&LoaderOptions{
+		CacheEnabled: false,
+		MaxFileSize:  1024 * 1024,
+		Variables:    map[string]string{"CIDR": "192.0.2.0/24"},
+		Overlay:      overlay,
+	})
+	result, err := loader.LoadApplyConfig(filepath.Join(dir, "base.yaml"))
+	if err != nil {
+		t.Fatalf("LoadApplyConfig failed: %v", err)
+	}
+
+	doc := result.Config.(*types.ApplyDocument)
+	if len(doc.Resources) != 2 {
+		t.Fatalf("Expected the legacy entry to be deleted, got %+v", doc.Resources)
+	}
+	cluster, _ := doc.Resources[0].Spec.(map[string]interface{})
+	if doc.Resources[0].Kind != types.KindCluster || cluster["instanceSize"] != "M30" || cluster["backupEnabled"] != true {
+		t.Errorf("Expected the module input and cluster to be patched, got %+v", doc.Resources[0])
+	}
+	network, _ := doc.Resources[1].Spec.(map[string]interface{})
+	if network["cidr"] != "192.0.2.0/24" || network["comment"] != "office" {
+		t.Errorf("Expected variables and the JSON patch to apply, got %+v", network)
+	}
+	if unmatched := overlay.Unmatched(); !reflect.DeepEqual(unmatched, []string{"DatabaseUser/ghost"}) {
+		t.Errorf("Expected only the ghost patch to be unmatched, got %v", unmatched)
+	}
+}
+
+func TestLoadVariableFiles(t *testing.T) {
+	dir := writeOverlay(t, map[string]string{
+		"a.yaml":    "SIZE: M10\nBACKUP: true\nEMPTY:\n",
+		"b.json":    `{"SIZE": "M30", "DISK": 40}`,
+		"bad.yaml":  "TAGS: {team: orders}\n",
+		"name.yaml": "1SIZE: M10\n",
+	})
+	vars, err := LoadVariableFiles([]string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.json")})
+	if err != nil {
+		t.Fatalf("LoadVariableFiles failed: %v", err)
+	}
+	want := map[string]string{"SIZE": "M30", "BACKUP": "true", "EMPTY": "", "DISK": "40"}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("Expected %v, got %v", want, vars)
+	}
+	for _, name := range []string{"bad.yaml", "name.yaml", "missing.yaml"} {
+		if _, err := LoadVariableFiles([]string{filepath.Join(dir, name)}); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestParseVariableAssignments(t *testing.T) {
+	vars, err := ParseVariableAssignments([]string{"SIZE=M30", "URL=https://x/?a=b", "EMPTY="})
+	if err != nil {
+		t.Fatalf("ParseVariableAssignments failed: %v", err)
+	}
+	if !reflect.DeepEqual(vars, map[string]string{"SIZE": "M30", "URL": "https://x/?a=b", "EMPTY": ""}) {
+		t.Errorf("Unexpected variables: %v", vars)
+	}
+	for _, assignment := range []string{"SIZE", "bad-name=x", "=x"} {
+		if _, err := ParseVariableAssignments([]string{assignment}); err == nil {
+			t.Errorf("Expected %q to be rejected", assignment)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/teabranch/matlas-cli/internal/apply/dag"
 	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/validation"
 )
 
 // Plan represents an ordered execution plan for applying configuration changes
@@ -22,6 +23,8 @@ type Plan struct {
 	// Plan content
 	Operations []PlannedOperation `json:"operations"`
 	Summary    PlanSummary        `json:"summary"`
+	// DataSources are the read-only lookups the operations can reference
+	DataSources []DataSource `json:"dataSources,omitempty"`
 
 	// Execution tracking
 	Status       PlanStatus    `json:"status"`
@@ -54,6 +57,15 @@ type PlannedOperation struct {
 	// Batching support
 	BatchID   string `json:"batchId,omitempty"`
 	BatchSize int    `json:"batchSize,omitempty"`
+
+	// Hooks declared for the resource in its ApplyDocument
+	Hooks *types.Hooks `json:"hooks,omitempty"`
+
+	// Module is the ModuleInstance that declared the resource, if any
+	Module string `json:"module,omitempty"`
+
+	// References are the Kind/name of the resources the manifest's ${ref:...} expressions name
+	References []string `json:"references,omitempty"`
 }
 
 // PlanStatus represents the current status of a plan
@@ -101,6 +113,13 @@ type PlanSummary struct {
 	DestructiveOperations int                   `json:"destructiveOperations"`
 	RequiresApproval      bool                  `json:"requiresApproval"`
 	ParallelizationFactor float64               `json:"parallelizationFactor"`
+
+	// ETA range taking parallel stages into account: the low end uses median and the
+	// high end 90th percentile durations where history is available
+	EstimatedDurationLow  time.Duration `json:"estimatedDurationLow,omitempty"`
+	EstimatedDurationHigh time.Duration `json:"estimatedDurationHigh,omitempty"`
+	// HistoricalEstimates is the number of operations estimated from recorded history
+	HistoricalEstimates int `json:"historicalEstimates,omitempty"`
 }
 
 // PlanConfig contains configuration options for plan execution
@@ -132,6 +151,9 @@ type PlanBuilder struct {
 	operations  []Operation
 	config      PlanConfig
 	dependGraph *types.DependencyGraph
+	history     *DurationHistory
+	hooks       *OperationHooks
+	dataSources []DataSource
 }
 
 // NewPlanBuilder creates a new plan builder
@@ -210,6 +232,24 @@ func (pb *PlanBuilder) WithSchedulingStrategy(strategy string) *PlanBuilder {
 	return pb
 }
 
+// WithDurationHistory estimates operation durations from recorded history
+func (pb *PlanBuilder) WithDurationHistory(history *DurationHistory) *PlanBuilder {
+	pb.history = history
+	return pb
+}
+
+// WithHooks attaches the hooks declared in ApplyDocuments to the operations they cover
+func (pb *PlanBuilder) WithHooks(hooks *OperationHooks) *PlanBuilder {
+	pb.hooks = hooks
+	return pb
+}
+
+// WithDataSources makes resolved data sources available to the operations' references
+func (pb *PlanBuilder) WithDataSources(sources []DataSource) *PlanBuilder {
+	pb.dataSources = sources
+	return pb
+}
+
 // Build creates the execution plan
 func (pb *PlanBuilder) Build() (*Plan, error) {
 	if len(pb.operations) == 0 {
@@ -240,6 +280,7 @@ func (pb *PlanBuilder) Build() (*Plan, error) {
 		CreatedAt:    time.Now(),
 		Operations:   plannedOps,
 		Summary:      summary,
+		DataSources:  pb.dataSources,
 		Status:       PlanStatusDraft,
 		ApprovalInfo: approvalInfo,
 		Config:       pb.config,
@@ -254,12 +295,25 @@ func (pb *PlanBuilder) buildPlannedOperations() ([]PlannedOperation, error) {
 
 	for i, op := range pb.operations {
 		plannedOp := PlannedOperation{
-			Operation:  op,
+			Operation:  pb.applyDurationHistory(op),
 			ID:         fmt.Sprintf("op-%d", i),
 			Priority:   pb.calculatePriority(op),
 			Status:     OperationStatusPending,
 			RetryCount: 0,
 		}
+		if op.Type != OperationNoChange {
+			plannedOp.Hooks = pb.hooks.For(op.ResourceType, op.ResourceName)
+		}
+		plannedOp.Module = ModuleOf(op.Desired)
+		if op.Type == OperationCreate || op.Type == OperationUpdate {
+			refs := ReferencesOf(op.Desired)
+			for _, ref := range refs {
+				if err := checkReferencePath(ref); err != nil {
+					return nil, err
+				}
+			}
+			plannedOp.References = referenceKeys(refs)
+		}
 
 		// Add automatic dependencies based on resource types
 		deps := pb.detectAutomaticDependencies(op, pb.operations[:i])
@@ -268,6 +322,10 @@ func (pb *PlanBuilder) buildPlannedOperations() ([]PlannedOperation, error) {
 		plannedOps[i] = plannedOp
 	}
 
+	if err := addReferenceDependencies(plannedOps, pb.dataSources); err != nil {
+		return nil, err
+	}
+
 	// Assign stages for parallel execution
 	if err := pb.assignStages(plannedOps); err != nil {
 		return nil, err
@@ -276,6 +334,24 @@ func (pb *PlanBuilder) buildPlannedOperations() ([]PlannedOperation, error) {
 	return plannedOps, nil
 }
 
+// applyDurationHistory replaces the operation's estimated duration with percentiles from
+// recorded history. The impact is copied so the diff's operations are left untouched.
+func (pb *PlanBuilder) applyDurationHistory(op Operation) Operation {
+	estimate, ok := pb.history.Estimate(DurationKeyFor(op))
+	if !ok {
+		return op
+	}
+	impact := OperationImpact{RiskLevel: RiskLevelLow}
+	if op.Impact != nil {
+		impact = *op.Impact
+	}
+	impact.EstimatedDuration = estimate.P50
+	impact.EstimatedDurationP90 = estimate.P90
+	impact.DurationSamples = estimate.Samples
+	op.Impact = &impact
+	return op
+}
+
 // calculatePriority determines operation priority based on type and risk
 func (pb *PlanBuilder) calculatePriority(op Operation) int {
 	priority := 100 // Default priority
@@ -315,8 +391,31 @@ func (pb *PlanBuilder) calculatePriority(op Operation) int {
 		priority += 35
 	case types.KindNetworkAccess:
 		priority += 30
+	case types.KindIntegration:
+		// Integrations are independent of other project resources; configure them early
+		// so alert notifications and metrics are wired up while clusters provision
+		priority += 30
+	case types.KindLDAPConfiguration:
+		// LDAP must be configured before LDAP-authenticated users are created
+		priority += 25
 	case types.KindDatabaseUser:
 		priority += 20
+	case types.KindFederationSettings:
+		// Identity providers must exist before groups can be mapped to roles
+		priority += 15
+	case types.KindRoleMapping:
+		priority += 10
+	case types.KindCollection:
+		// Collections and indexes are created on a running cluster through a temporary user
+		priority += 8
+	case types.KindView:
+		// Views read the collections declared before them
+		priority += 6
+	case types.KindIndex:
+		priority += 5
+	case types.KindMaterializedView:
+		// Materialized views are filled once their collections and indexes exist
+		priority += 3
 	}
 
 	return priority
@@ -336,6 +435,48 @@ func (pb *PlanBuilder) detectAutomaticDependencies(op Operation, previousOps []O
 			if prevOp.ResourceType == types.KindDatabaseRole {
 				deps = append(deps, fmt.Sprintf("op-%d", i))
 			}
+			// LDAP users and groups need the project LDAP configuration in place
+			if prevOp.ResourceType == types.KindLDAPConfiguration && isLDAPUserOperation(op) {
+				deps = append(deps, fmt.Sprintf("op-%d", i))
+			}
+		}
+	}
+
+	// Role mappings depend on the federation settings of their organization
+	if op.ResourceType == types.KindRoleMapping {
+		for i, prevOp := range previousOps {
+			if prevOp.ResourceType == types.KindFederationSettings && sameOrganization(op, prevOp) {
+				deps = append(deps, fmt.Sprintf("op-%d", i))
+			}
+		}
+	}
+
+	// Collections, indexes and views wait for their cluster and for the database users that
+	// can reach it. Indexes also wait for their collection, views for the collection or view
+	// they read, and materialized views for the indexes of their own collection.
+	if isDatabaseResourceKind(op.ResourceType) && op.Type != OperationDelete {
+		clusterName, namespace := databaseResourceTarget(op.Desired)
+		source := viewSourceNamespace(op.Desired)
+		for i, prevOp := range previousOps {
+			prevCluster, prevNamespace := databaseResourceTarget(prevOp.Desired)
+			switch prevOp.ResourceType {
+			case types.KindCluster:
+				if prevOp.ResourceName == clusterName {
+					deps = append(deps, fmt.Sprintf("op-%d", i))
+				}
+			case types.KindDatabaseUser:
+				if prevOp.Type != OperationDelete && userReachesCluster(prevOp.Desired, clusterName) {
+					deps = append(deps, fmt.Sprintf("op-%d", i))
+				}
+			case types.KindCollection, types.KindView, types.KindMaterializedView:
+				if prevCluster == clusterName && ((op.ResourceType == types.KindIndex && prevNamespace == namespace) || (source != "" && prevNamespace == source)) {
+					deps = append(deps, fmt.Sprintf("op-%d", i))
+				}
+			case types.KindIndex:
+				if op.ResourceType == types.KindMaterializedView && prevOp.Type != OperationDelete && prevCluster == clusterName && prevNamespace == namespace {
+					deps = append(deps, fmt.Sprintf("op-%d", i))
+				}
+			}
 		}
 	}
 
@@ -344,6 +485,97 @@ func (pb *PlanBuilder) detectAutomaticDependencies(op Operation, previousOps []O
 	return deps
 }
 
+// isDatabaseResourceKind reports whether resources of a kind live inside a cluster's databases
+func isDatabaseResourceKind(kind types.ResourceKind) bool {
+	switch kind {
+	case types.KindCollection, types.KindIndex, types.KindView, types.KindMaterializedView:
+		return true
+	}
+	return false
+}
+
+// databaseResourceTarget returns the cluster and database.collection namespace of a
+// Collection, Index, View or MaterializedView manifest
+func databaseResourceTarget(resource interface{}) (string, string) {
+	switch r := resource.(type) {
+	case *types.ViewManifest:
+		if r != nil {
+			return r.Spec.ClusterName, r.Spec.DatabaseName + "." + r.Spec.Name
+		}
+	case *types.MaterializedViewManifest:
+		if r != nil {
+			return r.Spec.ClusterName, r.Spec.DatabaseName + "." + r.Spec.Name
+		}
+	case *types.CollectionManifest:
+		if r != nil {
+			return r.Spec.ClusterName, r.Spec.DatabaseName + "." + r.Spec.Name
+		}
+	case *types.IndexManifest:
+		if r != nil {
+			return r.Spec.ClusterName, r.Spec.DatabaseName + "." + r.Spec.CollectionName
+		}
+	}
+	return "", ""
+}
+
+// viewSourceNamespace returns the database.collection namespace a View or MaterializedView
+// manifest reads
+func viewSourceNamespace(resource interface{}) string {
+	switch r := resource.(type) {
+	case *types.ViewManifest:
+		if r != nil {
+			return r.Spec.DatabaseName + "." + r.Spec.ViewOn
+		}
+	case *types.MaterializedViewManifest:
+		if r != nil {
+			return r.Spec.DatabaseName + "." + r.Spec.Source
+		}
+	}
+	return ""
+}
+
+// userReachesCluster reports whether a database user can access a cluster: users without
+// scopes can access every cluster in the project
+func userReachesCluster(resource interface{}, clusterName string) bool {
+	user, ok := resource.(*types.DatabaseUserManifest)
+	if !ok || user == nil {
+		return false
+	}
+	if len(user.Spec.Scopes) == 0 {
+		return true
+	}
+	for _, scope := range user.Spec.Scopes {
+		if scope.Type == "CLUSTER" && scope.Name == clusterName {
+			return true
+		}
+	}
+	return false
+}
+
+// isLDAPUserOperation reports whether the operation targets an LDAP-authenticated database user
+func isLDAPUserOperation(op Operation) bool {
+	user, ok := op.Desired.(*types.DatabaseUserManifest)
+	if !ok || user == nil {
+		return false
+	}
+	authType := validation.NormalizeAuthType(user.Spec.AuthType)
+	return authType == validation.AuthTypeLDAPUser || authType == validation.AuthTypeLDAPGroup
+}
+
+// sameOrganization reports whether a role mapping operation targets the organization of a federation settings operation
+func sameOrganization(mappingOp, settingsOp Operation) bool {
+	desiredMapping, currentMapping := roleMappingSpecs(mappingOp.Desired, mappingOp.Current)
+	mapping := desiredMapping
+	if mapping == nil {
+		mapping = currentMapping
+	}
+	settings, ok := settingsOp.Desired.(*types.FederationSettingsManifest)
+	if mapping == nil || !ok || settings == nil {
+		return false
+	}
+	return mapping.OrgID == settings.Spec.OrgID
+}
+
 // assignStages groups operations into stages for parallel execution
 func (pb *PlanBuilder) assignStages(ops []PlannedOperation) error {
 	// Use DAG engine if enabled
@@ -477,6 +709,8 @@ func (pb *PlanBuilder) buildDAGFromOperations(ops []PlannedOperation) *dag.Graph
 	for _, op := range ops {
 		props := dag.NodeProperties{
 			EstimatedDuration: pb.estimateOperationDuration(op),
+			MinDuration:       estimatedMinDuration(op),
+			MaxDuration:       estimatedMaxDuration(op),
 			RiskLevel:         pb.mapRiskLevel(op),
 			IsDestructive:     pb.isDestructiveOperation(op),
 			Priority:          op.Priority,
@@ -531,6 +765,11 @@ func (pb *PlanBuilder) parseSchedulingStrategy() dag.SchedulingStrategy {
 
 // estimateOperationDuration estimates how long an operation will take
 func (pb *PlanBuilder) estimateOperationDuration(op PlannedOperation) time.Duration {
+	return estimatedDuration(op)
+}
+
+// estimatedDuration returns the operation's estimated (median, when from history) duration
+func estimatedDuration(op PlannedOperation) time.Duration {
 	if op.Impact != nil {
 		return op.Impact.EstimatedDuration
 	}
@@ -551,6 +790,47 @@ func (pb *PlanBuilder) estimateOperationDuration(op PlannedOperation) time.Durat
 	}
 }
 
+// estimatedMaxDuration returns the 90th percentile duration from history, or the
+// estimated duration when the operation has no history
+func estimatedMaxDuration(op PlannedOperation) time.Duration {
+	if op.Impact != nil && op.Impact.EstimatedDurationP90 > op.Impact.EstimatedDuration {
+		return op.Impact.EstimatedDurationP90
+	}
+	return estimatedDuration(op)
+}
+
+// estimatedMinDuration is zero unless history provides a range for the operation
+func estimatedMinDuration(op PlannedOperation) time.Duration {
+	if op.Impact != nil && op.Impact.DurationSamples > 0 {
+		return op.Impact.EstimatedDuration
+	}
+	return 0
+}
+
+// EstimatePlanETA estimates wall-clock time for operations executed stage by stage. Each
+// stage takes as long as its slowest operation; low sums the median and high the 90th
+// percentile stage durations. historical counts operations estimated from history.
+func EstimatePlanETA(ops []PlannedOperation) (low, high time.Duration, historical int) {
+	stageLow := make(map[int]time.Duration)
+	stageHigh := make(map[int]time.Duration)
+	for _, op := range ops {
+		if d := estimatedDuration(op); d > stageLow[op.Stage] {
+			stageLow[op.Stage] = d
+		}
+		if d := estimatedMaxDuration(op); d > stageHigh[op.Stage] {
+			stageHigh[op.Stage] = d
+		}
+		if op.Impact != nil && op.Impact.DurationSamples > 0 {
+			historical++
+		}
+	}
+	for stage := range stageLow {
+		low += stageLow[stage]
+		high += stageHigh[stage]
+	}
+	return low, high, historical
+}
+
 // mapRiskLevel maps apply.RiskLevel to dag.RiskLevel
 func (pb *PlanBuilder) mapRiskLevel(op PlannedOperation) dag.RiskLevel {
 	if op.Impact == nil {
@@ -625,6 +905,7 @@ func (pb *PlanBuilder) calculateSummary(ops []PlannedOperation) PlanSummary {
 	}
 
 	summary.EstimatedDuration = totalDuration
+	summary.EstimatedDurationLow, summary.EstimatedDurationHigh, summary.HistoricalEstimates = EstimatePlanETA(ops)
 	summary.DestructiveOperations = destructiveCount
 	summary.RequiresApproval = requiresApproval || destructiveCount > 0
 
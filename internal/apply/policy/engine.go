@@ -0,0 +1,281 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// costLimit bounds the work a single expression may do on one operation
+const costLimit = 1_000_000
+
+// RuleInfo describes an evaluated rule
+type RuleInfo struct {
+	ID          string `json:"id"`
+	Policy      string `json:"policy"`
+	Name        string `json:"name"`
+	Level       Level  `json:"level"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`
+}
+
+// Finding is an operation that does not comply with a rule
+type Finding struct {
+	RuleID        string              `json:"ruleId"`
+	Policy        string              `json:"policy"`
+	Rule          string              `json:"rule"`
+	Level         Level               `json:"level"`
+	OperationID   string              `json:"operationId"`
+	OperationType apply.OperationType `json:"operationType"`
+	ResourceKind  types.ResourceKind  `json:"resourceKind"`
+	ResourceName  string              `json:"resourceName"`
+	Message       string              `json:"message"`
+}
+
+// Resource returns the Kind/Name key of the finding's resource
+func (f Finding) Resource() string {
+	return apply.ExecutionOperationKey(f.ResourceKind, f.ResourceName)
+}
+
+// Report holds the result of evaluating policies against a plan
+type Report struct {
+	Rules               []RuleInfo `json:"rules"`
+	OperationsEvaluated int        `json:"operationsEvaluated"`
+	Findings            []Finding  `json:"findings"`
+}
+
+// Count returns the number of findings at a level
+func (r *Report) Count(level Level) int {
+	if r == nil {
+		return 0
+	}
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Level == level {
+			count++
+		}
+	}
+	return count
+}
+
+// Denied reports whether any deny rule was violated
+func (r *Report) Denied() bool {
+	return r.Count(LevelDeny) > 0
+}
+
+// Engine evaluates compiled policy rules
+type Engine struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	info RuleInfo
+	rule Rule
+	when cel.Program
+	expr cel.Program
+}
+
+// NewEngine compiles the rules of the given policies. Rules see four variables:
+// operation (the planned operation as JSON, credentials removed), desired and current
+// (its resource manifests, null when absent) and projectId.
+func NewEngine(policies []*Policy) (*Engine, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("operation", cel.DynType),
+		cel.Variable("desired", cel.DynType),
+		cel.Variable("current", cel.DynType),
+		cel.Variable("projectId", cel.StringType),
+		ext.Strings(),
+		ext.Lists(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy environment: %w", err)
+	}
+
+	engine := &Engine{}
+	for _, policy := range policies {
+		for _, rule := range policy.Spec.Rules {
+			compiled := compiledRule{
+				info: RuleInfo{
+					ID:          policy.ruleID(rule),
+					Policy:      policy.Metadata.Name,
+					Name:        rule.Name,
+					Level:       rule.Level,
+					Description: rule.Description,
+					Source:      policy.Source,
+				},
+				rule: rule,
+			}
+			if compiled.expr, err = compileBool(env, rule.Expression); err != nil {
+				return nil, fmt.Errorf("%s: rule %s: expression: %w", policy.Source, compiled.info.ID, err)
+			}
+			if rule.When != "" {
+				if compiled.when, err = compileBool(env, rule.When); err != nil {
+					return nil, fmt.Errorf("%s: rule %s: when: %w", policy.Source, compiled.info.ID, err)
+				}
+			}
+			engine.rules = append(engine.rules, compiled)
+		}
+	}
+	return engine, nil
+}
+
+func compileBool(env *cel.Env, expression string) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("must evaluate to a bool, got %s", ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(costLimit))
+}
+
+// Evaluate checks every operation of the plan against the compiled rules. Rules that
+// cannot be evaluated for an operation are reported as findings at the rule's level.
+func (e *Engine) Evaluate(plan *apply.Plan) (*Report, error) {
+	report := &Report{Rules: make([]RuleInfo, 0, len(e.rules)), Findings: []Finding{}}
+	for _, rule := range e.rules {
+		report.Rules = append(report.Rules, rule.info)
+	}
+
+	for i := range plan.Operations {
+		op := &plan.Operations[i]
+		vars, err := operationVariables(plan.ProjectID, op)
+		if err != nil {
+			return nil, err
+		}
+
+		evaluated := false
+		for _, rule := range e.rules {
+			if !rule.matches(op) {
+				continue
+			}
+			evaluated = true
+
+			message, violated := rule.evaluate(vars)
+			if !violated {
+				continue
+			}
+			report.Findings = append(report.Findings, Finding{
+				RuleID:        rule.info.ID,
+				Policy:        rule.info.Policy,
+				Rule:          rule.info.Name,
+				Level:         rule.info.Level,
+				OperationID:   op.ID,
+				OperationType: op.Type,
+				ResourceKind:  op.ResourceType,
+				ResourceName:  op.ResourceName,
+				Message:       message,
+			})
+		}
+		if evaluated {
+			report.OperationsEvaluated++
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return levelRank(report.Findings[i].Level) < levelRank(report.Findings[j].Level)
+	})
+	return report, nil
+}
+
+func (r compiledRule) matches(op *apply.PlannedOperation) bool {
+	if len(r.rule.Match.Kinds) > 0 && !containsString(r.rule.Match.Kinds, string(op.ResourceType)) {
+		return false
+	}
+	if len(r.rule.Match.Operations) == 0 {
+		return op.Type != apply.OperationNoChange
+	}
+	return containsString(r.rule.Match.Operations, string(op.Type))
+}
+
+// evaluate returns the finding message and whether the rule was violated
+func (r compiledRule) evaluate(vars map[string]interface{}) (string, bool) {
+	if r.when != nil {
+		applies, err := evalBool(r.when, vars)
+		if err != nil {
+			return fmt.Sprintf("could not evaluate when: %v", err), true
+		}
+		if !applies {
+			return "", false
+		}
+	}
+
+	ok, err := evalBool(r.expr, vars)
+	if err != nil {
+		return fmt.Sprintf("could not evaluate expression: %v", err), true
+	}
+	if ok {
+		return "", false
+	}
+	switch {
+	case r.rule.Message != "":
+		return r.rule.Message, true
+	case r.rule.Description != "":
+		return r.rule.Description, true
+	default:
+		return fmt.Sprintf("violates %s", r.info.ID), true
+	}
+}
+
+func evalBool(program cel.Program, vars map[string]interface{}) (bool, error) {
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	value, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a bool, got %s", out.Type())
+	}
+	return value, nil
+}
+
+// operationVariables converts an operation to the plain values CEL expressions see
+func operationVariables(projectID string, op *apply.PlannedOperation) (map[string]interface{}, error) {
+	clean := *op
+	clean.Hooks = nil
+	clean.Desired = apply.WithoutCredentials(op.Desired)
+	clean.Current = apply.WithoutCredentials(op.Current)
+
+	data, err := json.Marshal(clean)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode operation %s for policies: %w", op.ID, err)
+	}
+	var operation map[string]interface{}
+	if err := json.Unmarshal(data, &operation); err != nil {
+		return nil, fmt.Errorf("failed to encode operation %s for policies: %w", op.ID, err)
+	}
+
+	return map[string]interface{}{
+		"operation": operation,
+		"desired":   operation["desired"],
+		"current":   operation["current"],
+		"projectId": projectID,
+	}, nil
+}
+
+func levelRank(level Level) int {
+	switch level {
+	case LevelDeny:
+		return 0
+	case LevelWarn:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
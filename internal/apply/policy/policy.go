@@ -0,0 +1,199 @@
+// Package policy evaluates user-supplied CEL policies against execution plans.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is the kind of policy documents
+const Kind = "Policy"
+
+// Level is the enforcement level of a rule
+type Level string
+
+const (
+	// LevelDeny findings block the plan
+	LevelDeny Level = "deny"
+	// LevelWarn findings are reported but do not block the plan
+	LevelWarn Level = "warn"
+	// LevelAdvisory findings are informational
+	LevelAdvisory Level = "advisory"
+)
+
+// Policy is a named set of rules loaded from a policy document
+type Policy struct {
+	APIVersion string   `yaml:"apiVersion" json:"apiVersion"`
+	Kind       string   `yaml:"kind" json:"kind"`
+	Metadata   Metadata `yaml:"metadata" json:"metadata"`
+	Spec       Spec     `yaml:"spec" json:"spec"`
+
+	// Source is the file the policy was loaded from
+	Source string `yaml:"-" json:"source,omitempty"`
+}
+
+// Metadata identifies a policy
+type Metadata struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// Spec holds the rules of a policy
+type Spec struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rule is a CEL expression every matching operation must satisfy
+type Rule struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Level       Level  `yaml:"level,omitempty" json:"level,omitempty"` // defaults to deny
+	Match       Match  `yaml:"match,omitempty" json:"match,omitempty"`
+	// When optionally narrows the rule to operations for which it evaluates to true
+	When string `yaml:"when,omitempty" json:"when,omitempty"`
+	// Expression must evaluate to true for the operation to comply
+	Expression string `yaml:"expression" json:"expression"`
+	// Message is reported for operations that do not comply
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// Match selects the operations a rule applies to. Empty fields match everything, except
+// that operations without changes are only evaluated when listed explicitly.
+type Match struct {
+	Kinds      []string `yaml:"kinds,omitempty" json:"kinds,omitempty"`
+	Operations []string `yaml:"operations,omitempty" json:"operations,omitempty"`
+}
+
+// ruleID returns the identifier of a rule: <policy>/<rule>
+func (p *Policy) ruleID(rule Rule) string {
+	return p.Metadata.Name + "/" + rule.Name
+}
+
+// Load reads the policies in the given files and directories. Directories are searched
+// recursively for .yaml and .yml files.
+func Load(paths []string) ([]*Policy, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy path: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(file)) {
+			case ".yaml", ".yml", ".rego":
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy directory %s: %w", path, err)
+		}
+	}
+	sort.Strings(files)
+
+	var policies []*Policy
+	for _, file := range files {
+		loaded, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, loaded...)
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("no policies found in %s", strings.Join(paths, ", "))
+	}
+	return policies, nil
+}
+
+func loadFile(file string) ([]*Policy, error) {
+	if strings.EqualFold(filepath.Ext(file), ".rego") {
+		return nil, fmt.Errorf("%s: Rego policies are not supported; write the rules as CEL expressions in a Policy document", file)
+	}
+
+	data, err := os.ReadFile(file) // #nosec G304 -- policy paths are supplied by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var policies []*Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var policy Policy
+		err := decoder.Decode(&policy)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to parse policy: %w", file, err)
+		}
+		if policy.Kind == "" && policy.Metadata.Name == "" && len(policy.Spec.Rules) == 0 {
+			continue // empty document
+		}
+		policy.Source = file
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		policies = append(policies, &policy)
+	}
+	return policies, nil
+}
+
+func (p *Policy) validate() error {
+	if p.Kind != Kind {
+		return fmt.Errorf("kind must be '%s', got '%s'", Kind, p.Kind)
+	}
+	if p.Metadata.Name == "" {
+		return fmt.Errorf("policy metadata.name is required")
+	}
+	if len(p.Spec.Rules) == 0 {
+		return fmt.Errorf("policy %s has no rules", p.Metadata.Name)
+	}
+
+	seen := make(map[string]bool)
+	for i := range p.Spec.Rules {
+		rule := &p.Spec.Rules[i]
+		if rule.Name == "" {
+			return fmt.Errorf("policy %s: rules[%d].name is required", p.Metadata.Name, i)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("policy %s: duplicate rule %s", p.Metadata.Name, rule.Name)
+		}
+		seen[rule.Name] = true
+		if strings.TrimSpace(rule.Expression) == "" {
+			return fmt.Errorf("rule %s: expression is required", p.ruleID(*rule))
+		}
+		switch rule.Level {
+		case "":
+			rule.Level = LevelDeny
+		case LevelDeny, LevelWarn, LevelAdvisory:
+		default:
+			return fmt.Errorf("rule %s: level must be deny, warn or advisory, got '%s'", p.ruleID(*rule), rule.Level)
+		}
+		for _, op := range rule.Match.Operations {
+			switch op {
+			case "Create", "Update", "Delete", "NoChange":
+			default:
+				return fmt.Errorf("rule %s: match.operations must be Create, Update, Delete or NoChange, got '%s'", p.ruleID(*rule), op)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/teabranch/matlas-cli/internal/apply"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func cluster(name, size string, env string, backup bool) *types.ClusterManifest {
+	return &types.ClusterManifest{
+		Kind:     types.KindCluster,
+		Metadata: types.ResourceMetadata{Name: name},
+		Spec: types.ClusterSpec{
+			Provider:      "AWS",
+			Region:        "US_EAST_1",
+			InstanceSize:  size,
+			BackupEnabled: boolPtr(backup),
+			PitEnabled:    boolPtr(backup),
+			Tags:          map[string]string{"environment": env},
+		},
+	}
+}
+
+func plannedOp(id string, opType apply.OperationType, kind types.ResourceKind, name string, current, desired interface{}) apply.PlannedOperation {
+	return apply.PlannedOperation{
+		ID: id,
+		Operation: apply.Operation{
+			Type:         opType,
+			ResourceType: kind,
+			ResourceName: name,
+			Current:      current,
+			Desired:      desired,
+		},
+	}
+}
+
+func loadExamplePolicies(t *testing.T) []*Policy {
+	t.Helper()
+	policies, err := Load([]string{filepath.Join("..", "..", "..", "examples", "policies")})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return policies
+}
+
+func TestLoad_ValidatesPolicies(t *testing.T) {
+	policies := loadExamplePolicies(t)
+	if len(policies) != 1 || policies[0].Metadata.Name != "guardrails" || len(policies[0].Spec.Rules) != 4 {
+		t.Fatalf("Unexpected example policies: %+v", policies)
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		return path
+	}
+
+	defaulted := write("defaulted.yaml", "kind: Policy\nmetadata:\n  name: p\nspec:\n  rules:\n    - name: r\n      expression: 'true'\n")
+	loaded, err := Load([]string{defaulted})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded[0].Spec.Rules[0].Level != LevelDeny {
+		t.Errorf("Expected rules to default to deny, got %q", loaded[0].Spec.Rules[0].Level)
+	}
+
+	for name, content := range map[string]string{
+		"kind.yaml":  "kind: Cluster\nmetadata:\n  name: p\nspec:\n  rules:\n    - name: r\n      expression: 'true'\n",
+		"level.yaml": "kind: Policy\nmetadata:\n  name: p\nspec:\n  rules:\n    - name: r\n      level: block\n      expression: 'true'\n",
+		"op.yaml":    "kind: Policy\nmetadata:\n  name: p\nspec:\n  rules:\n    - name: r\n      match:\n        operations: [Destroy]\n      expression: 'true'\n",
+		"expr.yaml":  "kind: Policy\nmetadata:\n  name: p\nspec:\n  rules:\n    - name: r\n",
+		"rules.rego": "package matlas\n",
+	} {
+		if _, err := Load([]string{write(name, content)}); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestNewEngine_RejectsInvalidExpressions(t *testing.T) {
+	for _, expression := range []string{"desired.spec.", "desired.spec.instanceSize + 1 == ", "'not a bool'"} {
+		policies := []*Policy{{Kind: Kind, Metadata: Metadata{Name: "p"}, Spec: Spec{Rules: []Rule{{Name: "r", Level: LevelDeny, Expression: expression}}}}}
+		if _, err := NewEngine(policies); err == nil {
+			t.Errorf("Expected %q not to compile", expression)
+		}
+	}
+}
+
+func TestEngine_EvaluatesExamplePolicies(t *testing.T) {
+	engine, err := NewEngine(loadExamplePolicies(t))
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+
+	plan := &apply.Plan{ProjectID: "proj", Operations: []apply.PlannedOperation{
+		plannedOp("op-0", apply.OperationCreate, types.KindCluster, "prod", nil, cluster("prod", "M10", "production", true)),
+		plannedOp("op-1", apply.OperationCreate, types.KindCluster, "prod-ok", nil, cluster("prod-ok", "M30", "production", true)),
+		plannedOp("op-2", apply.OperationCreate, types.KindCluster, "dev", nil, cluster("dev", "M10", "development", false)),
+		plannedOp("op-3", apply.OperationCreate, types.KindNetworkAccess, "anywhere", nil,
+			&types.NetworkAccessManifest{Kind: types.KindNetworkAccess, Metadata: types.ResourceMetadata{Name: "anywhere"}, Spec: types.NetworkAccessSpec{CIDR: "0.0.0.0/0"}}),
+		plannedOp("op-4", apply.OperationCreate, types.KindDatabaseUser, "app", nil,
+			&types.DatabaseUserManifest{Kind: types.KindDatabaseUser, Metadata: types.ResourceMetadata{Name: "app"}, Spec: types.DatabaseUserSpec{Username: "app", Password: "s3cret"}}),
+		plannedOp("op-5", apply.OperationDelete, types.KindCluster, "legacy", cluster("legacy", "M10", "development", false), nil),
+		plannedOp("op-6", apply.OperationNoChange, types.KindNetworkAccess, "office", nil, nil),
+	}}
+
+	report, err := engine.Evaluate(plan)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	var got []string
+	for _, finding := range report.Findings {
+		got = append(got, string(finding.Level)+" "+finding.Rule+" "+finding.Resource())
+	}
+	want := []string{
+		"deny prod-cluster-sizing Cluster/prod",
+		"deny no-open-access NetworkAccess/anywhere",
+		"warn users-scoped-to-clusters DatabaseUser/app",
+		"advisory cluster-deletes Cluster/legacy",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !report.Denied() || report.Count(LevelDeny) != 2 || report.Count(LevelAdvisory) != 1 {
+		t.Errorf("Unexpected counts: deny=%d advisory=%d", report.Count(LevelDeny), report.Count(LevelAdvisory))
+	}
+	if report.OperationsEvaluated != 6 {
+		t.Errorf("Expected operations without changes to be skipped, evaluated %d", report.OperationsEvaluated)
+	}
+	if report.Findings[0].Message != "production clusters need instanceSize M30+, backupEnabled and pitEnabled" {
+		t.Errorf("Unexpected message: %q", report.Findings[0].Message)
+	}
+}
+
+func TestEngine_EvaluationErrorsAreFindings(t *testing.T) {
+	policies := []*Policy{{Kind: Kind, Metadata: Metadata{Name: "p"}, Spec: Spec{Rules: []Rule{
+		{Name: "tier", Level: LevelWarn, Expression: "int(desired.spec.instanceSize.substring(1)) >= 30"},
+		{Name: "project", Level: LevelDeny, Expression: `projectId == "proj" && operation.resourceName == desired.metadata.name`},
+		{Name: "no-passwords", Level: LevelDeny, Expression: `!has(desired.spec.password) || desired.spec.password == ""`},
+	}}}}
+	engine, err := NewEngine(policies)
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+
+	plan := &apply.Plan{ProjectID: "proj", Operations: []apply.PlannedOperation{
+		plannedOp("op-0", apply.OperationCreate, types.KindCluster, "nvme", nil, cluster("nvme", "M40_NVME", "production", true)),
+		plannedOp("op-1", apply.OperationCreate, types.KindDatabaseUser, "app", nil,
+			&types.DatabaseUserManifest{Kind: types.KindDatabaseUser, Metadata: types.ResourceMetadata{Name: "app"}, Spec: types.DatabaseUserSpec{Username: "app", Password: "s3cret"}}),
+	}}
+	report, err := engine.Evaluate(plan)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	// The tier rule cannot parse M40_NVME for the cluster, nor find an instance size on the
+	// user; credentials are never visible to policies
+	if len(report.Findings) != 2 {
+		t.Fatalf("Expected 2 findings, got %+v", report.Findings)
+	}
+	for _, finding := range report.Findings {
+		if finding.Rule != "tier" || finding.Level != LevelWarn || !strings.Contains(finding.Message, "could not evaluate") {
+			t.Errorf("Unexpected finding: %+v", finding)
+		}
+	}
+}
+
+func TestToSARIF(t *testing.T) {
+	report := &Report{
+		Rules: []RuleInfo{
+			{ID: "guardrails/no-open-access", Policy: "guardrails", Name: "no-open-access", Level: LevelDeny, Description: "no internet"},
+			{ID: "guardrails/scoped", Policy: "guardrails", Name: "scoped", Level: LevelWarn},
+		},
+		Findings: []Finding{
+			{RuleID: "guardrails/scoped", Level: LevelWarn, OperationType: apply.OperationCreate, ResourceKind: types.KindDatabaseUser, ResourceName: "app", Message: "unscoped"},
+			{RuleID: "guardrails/no-open-access", Level: LevelDeny, OperationType: apply.OperationCreate, ResourceKind: types.KindNetworkAccess, ResourceName: "anywhere", Message: "open"},
+		},
+	}
+	log := ToSARIF(report, map[string]Location{"DatabaseUser/app": {File: "configs/users.yaml", Line: 12}})
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF log: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].DefaultConfiguration.Level != "error" {
+		t.Errorf("Unexpected rules: %+v", run.Tool.Driver.Rules)
+	}
+
+	user := run.Results[0]
+	if user.Level != "warning" || user.RuleIndex != 1 {
+		t.Errorf("Unexpected result: %+v", user)
+	}
+	physical := user.Locations[0].PhysicalLocation
+	if physical == nil || physical.ArtifactLocation.URI != "configs/users.yaml" || physical.Region.StartLine != 12 {
+		t.Errorf("Expected the declaration location, got %+v", physical)
+	}
+
+	network := run.Results[1]
+	if network.Level != "error" || network.Locations[0].PhysicalLocation != nil {
+		t.Errorf("Expected only a logical location, got %+v", network.Locations[0])
+	}
+	if network.Locations[0].LogicalLocations[0].FullyQualifiedName != "NetworkAccess/anywhere" {
+		t.Errorf("Unexpected logical location: %+v", network.Locations[0].LogicalLocations)
+	}
+}
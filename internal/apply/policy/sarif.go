@@ -0,0 +1,162 @@
+package policy
+
+import (
+	"path/filepath"
+)
+
+// SARIF 2.1.0 constants
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Location is where a resource is declared in the configuration files
+type Location struct {
+	File string
+	Line int
+}
+
+// SARIFLog is a SARIF 2.1.0 log with a single run
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is one analysis run
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the analysis tool and its rules
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component that produced the results
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is a reporting descriptor for a policy rule
+type SARIFRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name,omitempty"`
+	ShortDescription     *SARIFMessage      `json:"shortDescription,omitempty"`
+	DefaultConfiguration SARIFConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]string  `json:"properties,omitempty"`
+}
+
+// SARIFConfiguration holds the default level of a rule
+type SARIFConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a single finding
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations,omitempty"`
+}
+
+// SARIFLocation points at a file region and the logical resource
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SARIFPhysicalLocation is a file and region
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation identifies a file
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a line range in a file
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIFLogicalLocation names the resource a finding is about
+type SARIFLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIFLevel maps a policy level to a SARIF result level
+func SARIFLevel(level Level) string {
+	switch level {
+	case LevelDeny:
+		return "error"
+	case LevelWarn:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// ToSARIF converts a report to a SARIF log. locations maps Kind/Name resource keys to
+// where they are declared; resources without a location only get a logical location.
+func ToSARIF(report *Report, locations map[string]Location) *SARIFLog {
+	driver := SARIFDriver{
+		Name:           "matlas",
+		InformationURI: "https://github.com/teabranch/matlas-cli",
+		Rules:          make([]SARIFRule, 0, len(report.Rules)),
+	}
+	ruleIndex := make(map[string]int, len(report.Rules))
+	for i, rule := range report.Rules {
+		ruleIndex[rule.ID] = i
+		sarifRule := SARIFRule{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			DefaultConfiguration: SARIFConfiguration{Level: SARIFLevel(rule.Level)},
+			Properties:           map[string]string{"policy": rule.Policy, "level": string(rule.Level)},
+		}
+		if rule.Description != "" {
+			sarifRule.ShortDescription = &SARIFMessage{Text: rule.Description}
+		}
+		driver.Rules = append(driver.Rules, sarifRule)
+	}
+
+	results := make([]SARIFResult, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		location := SARIFLocation{
+			LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: finding.Resource(), Kind: "resource"}},
+		}
+		if loc, ok := locations[finding.Resource()]; ok && loc.File != "" {
+			location.PhysicalLocation = &SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: filepath.ToSlash(loc.File)},
+			}
+			if loc.Line > 0 {
+				location.PhysicalLocation.Region = &SARIFRegion{StartLine: loc.Line}
+			}
+		}
+		results = append(results, SARIFResult{
+			RuleID:    finding.RuleID,
+			RuleIndex: ruleIndex[finding.RuleID],
+			Level:     SARIFLevel(finding.Level),
+			Message:   SARIFMessage{Text: finding.Resource() + " (" + string(finding.OperationType) + "): " + finding.Message},
+			Locations: []SARIFLocation{location},
+		})
+	}
+
+	return &SARIFLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: results}},
+	}
+}
@@ -0,0 +1,701 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+	"github.com/teabranch/matlas-cli/internal/services/atlas"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// referencePattern matches ${ref:<Kind>/<name>.<path>} expressions
+var referencePattern = regexp.MustCompile(`\$\{ref:([A-Za-z]+)/([A-Za-z0-9_-]+)\.([A-Za-z0-9_.-]+)\}`)
+
+// Reference is a ${ref:...} expression naming a field of another resource. Paths start
+// with metadata or spec, read from the configuration, or status, read from Atlas once the
+// referenced resource has been applied.
+type Reference struct {
+	Kind types.ResourceKind `json:"kind"`
+	Name string             `json:"name"`
+	Path string             `json:"path"`
+}
+
+// Key identifies the referenced resource as Kind/name
+func (r Reference) Key() string {
+	return ExecutionOperationKey(r.Kind, r.Name)
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("${ref:%s/%s.%s}", r.Kind, r.Name, r.Path)
+}
+
+// ReferenceError reports a reference that could not be resolved
+type ReferenceError struct {
+	Reference Reference
+	Err       error
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("cannot resolve %s: %v", e.Reference, e.Err)
+}
+
+func (e *ReferenceError) Unwrap() error { return e.Err }
+
+// ParseReferences returns the references in a string
+func ParseReferences(s string) []Reference {
+	var refs []Reference
+	for _, match := range referencePattern.FindAllStringSubmatch(s, -1) {
+		refs = append(refs, Reference{Kind: types.ResourceKind(match[1]), Name: match[2], Path: match[3]})
+	}
+	return refs
+}
+
+// ReferencesOf returns the distinct references in the string fields of a resource
+func ReferencesOf(resource interface{}) []Reference {
+	if resource == nil || reflect.ValueOf(resource).IsZero() {
+		return nil
+	}
+	values, err := toValues(resource)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[Reference]bool)
+	var refs []Reference
+	walkStrings(values, func(s string) {
+		for _, ref := range ParseReferences(s) {
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	})
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+	return refs
+}
+
+// referenceKeys returns the distinct Kind/name keys of references
+func referenceKeys(refs []Reference) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if key := ref.Key(); !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// addReferenceDependencies makes operations depend on the operations of the resources
+// they reference. Edges come from the DAG reference rule; an edge it drops to avoid a
+// cycle is reported as a circular reference. Data sources are resolved before any
+// operation runs, so references to them add no edge.
+func addReferenceDependencies(ops []PlannedOperation, dataSources []DataSource) error {
+	targets := make(map[string]*PlannedOperation)
+	for i := range ops {
+		targets[referenceKey(ops[i].ResourceType, ops[i].ResourceName)] = &ops[i]
+	}
+	sources := make(map[string]bool)
+	for _, source := range dataSources {
+		sources[referenceKey(types.KindDataSource, source.Manifest.Metadata.Name)] = true
+	}
+
+	registry := dag.NewRuleRegistry()
+	if err := registry.Register(dag.NewReferenceDependencyRule()); err != nil {
+		return err
+	}
+	evaluator := dag.NewRuleEvaluator(registry)
+
+	expected := make(map[string]int)
+	for i := range ops {
+		op := &ops[i]
+		var references []string
+		for _, key := range op.References {
+			kind, name, _ := strings.Cut(key, "/")
+			if sources[referenceKey(types.ResourceKind(kind), name)] {
+				continue
+			}
+			target, ok := targets[referenceKey(types.ResourceKind(kind), name)]
+			switch {
+			case !ok:
+				return fmt.Errorf("%s references %s, which is not declared in the configuration",
+					ExecutionOperationKey(op.ResourceType, op.ResourceName), key)
+			case target == op:
+				return fmt.Errorf("%s references itself", key)
+			case target.Type == OperationDelete:
+				return fmt.Errorf("%s references %s, which is being deleted",
+					ExecutionOperationKey(op.ResourceType, op.ResourceName), key)
+			}
+			references = append(references, key)
+		}
+		expected[op.ID] = len(references)
+		evaluator.AddOperation(&dag.PlannedOperation{
+			ID:           op.ID,
+			Name:         op.ID,
+			ResourceType: op.ResourceType,
+			ResourceName: op.ResourceName,
+			Metadata:     map[string]interface{}{dag.MetadataReferences: references},
+		})
+	}
+	total := 0
+	for _, count := range expected {
+		total += count
+	}
+	if total == 0 {
+		return nil
+	}
+
+	graph, err := evaluator.Evaluate(context.Background())
+	if err != nil {
+		return err
+	}
+	for i := range ops {
+		deps := graph.GetDependencies(ops[i].ID)
+		if len(deps) < expected[ops[i].ID] {
+			return fmt.Errorf("circular reference detected involving %s",
+				ExecutionOperationKey(ops[i].ResourceType, ops[i].ResourceName))
+		}
+		for _, dep := range deps {
+			if !containsDependency(ops[i].Dependencies, dep) {
+				ops[i].Dependencies = append(ops[i].Dependencies, dep)
+			}
+		}
+	}
+	return nil
+}
+
+func containsDependency(deps []string, id string) bool {
+	for _, dep := range deps {
+		if dep == id {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReferencePath checks that a reference reads metadata, spec or status
+func checkReferencePath(ref Reference) error {
+	switch strings.SplitN(ref.Path, ".", 2)[0] {
+	case "metadata", "spec", "status":
+		return nil
+	default:
+		return &ReferenceError{Reference: ref, Err: fmt.Errorf("path must start with metadata, spec or status")}
+	}
+}
+
+// StatusFetcher reads the live state of a resource, exposed to references as status
+type StatusFetcher interface {
+	FetchStatus(ctx context.Context, kind types.ResourceKind, name string, declared interface{}) (interface{}, error)
+}
+
+// ReferenceResolver substitutes references with the values of the resources they name.
+// Resources are declared from the configuration; their status is fetched on first use.
+type ReferenceResolver struct {
+	fetcher StatusFetcher
+
+	mu       sync.Mutex
+	declared map[string]interface{}
+	status   map[string]interface{}
+
+	// Resources the plan creates or updates, whose status may not be settled yet
+	changing     map[string]bool
+	waitTimeout  time.Duration
+	pollInterval time.Duration
+}
+
+// NewReferenceResolver creates a resolver reading status through fetcher
+func NewReferenceResolver(fetcher StatusFetcher) *ReferenceResolver {
+	return &ReferenceResolver{
+		fetcher:  fetcher,
+		declared: make(map[string]interface{}),
+		status:   make(map[string]interface{}),
+		changing: make(map[string]bool),
+	}
+}
+
+// WaitForStatus makes status references to resources the plan creates or updates poll
+// until the referenced field is set, for up to timeout. Atlas fills in fields such as a
+// cluster's connection strings only once provisioning completes.
+func (r *ReferenceResolver) WaitForStatus(timeout, interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.waitTimeout = timeout
+	r.pollInterval = interval
+}
+
+func referenceKey(kind types.ResourceKind, name string) string {
+	return strings.ToLower(string(kind)) + "/" + name
+}
+
+// Declare records the manifest of a resource references can name
+func (r *ReferenceResolver) Declare(kind types.ResourceKind, name string, manifest interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.declared[referenceKey(kind, name)] = manifest
+}
+
+// DeclarePlan declares the desired (or, for deletions, current) resource of every operation
+func (r *ReferenceResolver) DeclarePlan(plan *Plan) {
+	for _, op := range plan.Operations {
+		manifest := op.Desired
+		if manifest == nil || reflect.ValueOf(manifest).IsZero() {
+			manifest = op.Current
+		}
+		r.Declare(op.ResourceType, op.ResourceName, manifest)
+		if op.Type == OperationCreate || op.Type == OperationUpdate {
+			r.mu.Lock()
+			r.changing[referenceKey(op.ResourceType, op.ResourceName)] = true
+			r.mu.Unlock()
+		}
+	}
+	r.DeclareDataSources(plan.DataSources)
+}
+
+// DeclareState declares every resource of a desired state
+func (r *ReferenceResolver) DeclareState(state *ProjectState) {
+	if state.Project != nil {
+		r.Declare(types.KindProject, state.Project.Metadata.Name, state.Project)
+	}
+	for i := range state.Clusters {
+		r.Declare(types.KindCluster, state.Clusters[i].Metadata.Name, &state.Clusters[i])
+	}
+	for i := range state.DatabaseUsers {
+		r.Declare(types.KindDatabaseUser, state.DatabaseUsers[i].Metadata.Name, &state.DatabaseUsers[i])
+	}
+	for i := range state.DatabaseRoles {
+		r.Declare(types.KindDatabaseRole, state.DatabaseRoles[i].Metadata.Name, &state.DatabaseRoles[i])
+	}
+	for i := range state.NetworkAccess {
+		r.Declare(types.KindNetworkAccess, state.NetworkAccess[i].Metadata.Name, &state.NetworkAccess[i])
+	}
+	for i := range state.SearchIndexes {
+		r.Declare(types.KindSearchIndex, state.SearchIndexes[i].Metadata.Name, &state.SearchIndexes[i])
+	}
+	for i := range state.VPCEndpoints {
+		r.Declare(types.KindVPCEndpoint, state.VPCEndpoints[i].Metadata.Name, &state.VPCEndpoints[i])
+	}
+	for i := range state.LDAPConfigurations {
+		r.Declare(types.KindLDAPConfiguration, state.LDAPConfigurations[i].Metadata.Name, &state.LDAPConfigurations[i])
+	}
+	for i := range state.Integrations {
+		r.Declare(types.KindIntegration, state.Integrations[i].Metadata.Name, &state.Integrations[i])
+	}
+	for i := range state.Collections {
+		r.Declare(types.KindCollection, state.Collections[i].Metadata.Name, &state.Collections[i])
+	}
+	for i := range state.Indexes {
+		r.Declare(types.KindIndex, state.Indexes[i].Metadata.Name, &state.Indexes[i])
+	}
+	for i := range state.Views {
+		r.Declare(types.KindView, state.Views[i].Metadata.Name, &state.Views[i])
+	}
+	for i := range state.MaterializedViews {
+		r.Declare(types.KindMaterializedView, state.MaterializedViews[i].Metadata.Name, &state.MaterializedViews[i])
+	}
+	r.DeclareDataSources(UnresolvedDataSources(state.DataSources))
+}
+
+// DeclareDataSources declares data sources, with the status they were resolved to
+func (r *ReferenceResolver) DeclareDataSources(sources []DataSource) {
+	for _, source := range sources {
+		r.Declare(types.KindDataSource, source.Manifest.Metadata.Name, source.Manifest)
+		if source.Status != nil {
+			r.mu.Lock()
+			r.status[referenceKey(types.KindDataSource, source.Manifest.Metadata.Name)] = source.Status
+			r.mu.Unlock()
+		}
+	}
+}
+
+// Forget drops the cached status of a resource, after it has been changed
+func (r *ReferenceResolver) Forget(kind types.ResourceKind, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.status, referenceKey(kind, name))
+}
+
+// Lookup returns the value a reference names
+func (r *ReferenceResolver) Lookup(ctx context.Context, ref Reference) (interface{}, error) {
+	if err := checkReferencePath(ref); err != nil {
+		return nil, err
+	}
+	key := referenceKey(ref.Kind, ref.Name)
+
+	r.mu.Lock()
+	manifest, declared := r.declared[key]
+	wait := r.changing[key] && r.waitTimeout > 0
+	timeout, interval := r.waitTimeout, r.pollInterval
+	r.mu.Unlock()
+	if !declared {
+		return nil, &ReferenceError{Reference: ref, Err: fmt.Errorf("%s is not declared in the configuration", ref.Key())}
+	}
+
+	root, err := toValues(manifest)
+	if err != nil {
+		return nil, &ReferenceError{Reference: ref, Err: err}
+	}
+	document, _ := root.(map[string]interface{})
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+
+	if !strings.HasPrefix(ref.Path, "status") {
+		value, err := lookupPath(document, ref.Path)
+		if err != nil {
+			return nil, &ReferenceError{Reference: ref, Err: err}
+		}
+		return value, nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := r.fetchStatus(ctx, ref, manifest)
+		if err != nil {
+			return nil, err
+		}
+		document["status"] = status
+		value, err := lookupPath(document, ref.Path)
+		if err == nil && (!wait || value != nil && value != "") {
+			return value, nil
+		}
+		if !wait || time.Now().After(deadline) {
+			if err == nil {
+				err = fmt.Errorf("field is still not set after %s", timeout)
+			}
+			return nil, &ReferenceError{Reference: ref, Err: err}
+		}
+
+		// The resource is still being applied; read its status again
+		r.Forget(ref.Kind, ref.Name)
+		select {
+		case <-ctx.Done():
+			return nil, &ReferenceError{Reference: ref, Err: fmt.Errorf("timed out waiting for %s status: %w", ref.Key(), ctx.Err())}
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fetchStatus returns the cached status of a referenced resource, fetching it on first use
+func (r *ReferenceResolver) fetchStatus(ctx context.Context, ref Reference, manifest interface{}) (interface{}, error) {
+	key := referenceKey(ref.Kind, ref.Name)
+	r.mu.Lock()
+	status, fetched := r.status[key]
+	r.mu.Unlock()
+	if fetched {
+		return status, nil
+	}
+	if r.fetcher == nil {
+		return nil, &ReferenceError{Reference: ref, Err: fmt.Errorf("status is only known once %s has been applied", ref.Key())}
+	}
+	live, err := r.fetcher.FetchStatus(ctx, ref.Kind, ref.Name, manifest)
+	if err != nil {
+		return nil, &ReferenceError{Reference: ref, Err: err}
+	}
+	if status, err = toJSONValues(live); err != nil {
+		return nil, &ReferenceError{Reference: ref, Err: err}
+	}
+	r.mu.Lock()
+	r.status[key] = status
+	r.mu.Unlock()
+	return status, nil
+}
+
+// ResolveString substitutes the references in a string. A string that is a single
+// reference takes the referenced value with its type; otherwise values must be scalars.
+func (r *ReferenceResolver) ResolveString(ctx context.Context, s string) (interface{}, error) {
+	refs := ParseReferences(s)
+	if len(refs) == 0 {
+		return s, nil
+	}
+	if len(refs) == 1 && s == refs[0].String() {
+		return r.Lookup(ctx, refs[0])
+	}
+
+	var resolveErr error
+	resolved := referencePattern.ReplaceAllStringFunc(s, func(expression string) string {
+		ref := ParseReferences(expression)[0]
+		value, err := r.Lookup(ctx, ref)
+		if err == nil {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				err = &ReferenceError{Reference: ref, Err: fmt.Errorf("value is not a scalar and cannot be embedded in a string")}
+			case nil:
+				return ""
+			default:
+				return fmt.Sprint(value)
+			}
+		}
+		if resolveErr == nil {
+			resolveErr = err
+		}
+		return expression
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	return resolved, nil
+}
+
+// ResolveResource returns a copy of a manifest pointer with its references substituted
+func (r *ReferenceResolver) ResolveResource(ctx context.Context, resource interface{}) (interface{}, error) {
+	if len(ReferencesOf(resource)) == 0 {
+		return resource, nil
+	}
+	values, err := toValues(resource)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := r.resolveValues(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+	return fromValues(resolved, resource)
+}
+
+func (r *ReferenceResolver) resolveValues(ctx context.Context, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return r.ResolveString(ctx, v)
+	case map[string]interface{}:
+		for key, item := range v {
+			resolved, err := r.resolveValues(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := r.resolveValues(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	}
+	return value, nil
+}
+
+// ResolveOutputs resolves the outputs declared by ApplyDocuments
+func (r *ReferenceResolver) ResolveOutputs(ctx context.Context, outputs map[string]string) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(outputs))
+	for name, expression := range outputs {
+		value, err := r.ResolveString(ctx, expression)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", name, err)
+		}
+		resolved[name] = value
+	}
+	return resolved, nil
+}
+
+// withReferencesFrom returns a copy of desired whose fields holding references take the
+// value current has at the same path, so references resolved at execution time are not
+// reported as changes
+func withReferencesFrom(desired, current interface{}) interface{} {
+	if len(ReferencesOf(desired)) == 0 {
+		return desired
+	}
+	desiredValues, err := toValues(desired)
+	if err != nil {
+		return desired
+	}
+	currentValues, err := toValues(current)
+	if err != nil {
+		return desired
+	}
+	replaced, err := fromValues(replaceReferenceFields(desiredValues, currentValues), desired)
+	if err != nil {
+		return desired
+	}
+	return replaced
+}
+
+func replaceReferenceFields(desired, current interface{}) interface{} {
+	switch d := desired.(type) {
+	case string:
+		if len(ParseReferences(d)) > 0 && current != nil {
+			return current
+		}
+	case map[string]interface{}:
+		c, _ := current.(map[string]interface{})
+		for key, value := range d {
+			d[key] = replaceReferenceFields(value, c[key])
+		}
+	case []interface{}:
+		c, _ := current.([]interface{})
+		for i, value := range d {
+			var item interface{}
+			if i < len(c) {
+				item = c[i]
+			}
+			d[i] = replaceReferenceFields(value, item)
+		}
+	}
+	return desired
+}
+
+// lookupPath reads a dotted path from YAML values; list items are addressed by index
+func lookupPath(value interface{}, path string) (interface{}, error) {
+	node := value
+	for _, segment := range strings.Split(path, ".") {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[segment]
+			if !ok {
+				return nil, fmt.Errorf("field %s not found", segment)
+			}
+			node = child
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(n) {
+				return nil, fmt.Errorf("invalid list index %s", segment)
+			}
+			node = n[index]
+		default:
+			return nil, fmt.Errorf("field %s not found", segment)
+		}
+	}
+	return node, nil
+}
+
+func walkStrings(value interface{}, visit func(string)) {
+	switch v := value.(type) {
+	case string:
+		visit(v)
+	case map[string]interface{}:
+		for _, item := range v {
+			walkStrings(item, visit)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walkStrings(item, visit)
+		}
+	}
+}
+
+// toValues converts a manifest into generic YAML values
+func toValues(resource interface{}) (interface{}, error) {
+	var values interface{}
+	data, err := yaml.Marshal(resource)
+	if err == nil {
+		err = yaml.Unmarshal(data, &values)
+	}
+	return values, err
+}
+
+// fromValues decodes generic YAML values into a new value of like's type
+func fromValues(values interface{}, like interface{}) (interface{}, error) {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(like)
+	if t.Kind() != reflect.Ptr {
+		out := reflect.New(t)
+		if err := yaml.Unmarshal(data, out.Interface()); err != nil {
+			return nil, err
+		}
+		return out.Elem().Interface(), nil
+	}
+	out := reflect.New(t.Elem())
+	if err := yaml.Unmarshal(data, out.Interface()); err != nil {
+		return nil, err
+	}
+	return out.Interface(), nil
+}
+
+// toJSONValues converts Atlas API objects into generic values keyed by their API names
+func toJSONValues(value interface{}) (interface{}, error) {
+	var values interface{}
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = json.Unmarshal(data, &values)
+	}
+	return values, err
+}
+
+// AtlasStatusFetcher reads the status of referenced resources from the Atlas API
+type AtlasStatusFetcher struct {
+	ProjectID     string
+	Clusters      *atlas.ClustersService
+	Users         *atlas.DatabaseUsersService
+	NetworkAccess *atlas.NetworkAccessListsService
+	Projects      *atlas.ProjectsService
+	VPCEndpoints  *atlas.VPCEndpointsService
+}
+
+// FetchStatus implements StatusFetcher
+func (f *AtlasStatusFetcher) FetchStatus(ctx context.Context, kind types.ResourceKind, name string, declared interface{}) (interface{}, error) {
+	unavailable := fmt.Errorf("%s service not available", kind)
+	switch types.ResourceKind(strings.ToLower(string(kind))) {
+	case "cluster":
+		if f.Clusters == nil {
+			return nil, unavailable
+		}
+		return f.Clusters.Get(ctx, f.ProjectID, name)
+	case "project":
+		if f.Projects == nil {
+			return nil, unavailable
+		}
+		return f.Projects.Get(ctx, f.ProjectID)
+	case "databaseuser":
+		user, ok := declared.(*types.DatabaseUserManifest)
+		if !ok || f.Users == nil {
+			return nil, unavailable
+		}
+		authDatabase := user.Spec.AuthDatabase
+		if authDatabase == "" {
+			authDatabase = "admin"
+		}
+		return f.Users.Get(ctx, f.ProjectID, authDatabase, user.Spec.Username)
+	case "networkaccess":
+		entry, ok := declared.(*types.NetworkAccessManifest)
+		if !ok || f.NetworkAccess == nil {
+			return nil, unavailable
+		}
+		address := entry.Spec.IPAddress
+		if address == "" {
+			address = entry.Spec.CIDR
+		}
+		if address == "" {
+			address = entry.Spec.AWSSecurityGroup
+		}
+		return f.NetworkAccess.Get(ctx, f.ProjectID, address)
+	case "vpcendpoint":
+		endpoint, ok := declared.(*types.VPCEndpointManifest)
+		if !ok || f.VPCEndpoints == nil {
+			return nil, unavailable
+		}
+		services, err := f.VPCEndpoints.ListPrivateEndpointServices(ctx, f.ProjectID, endpoint.Spec.CloudProvider)
+		if err != nil {
+			return nil, err
+		}
+		region := strings.ReplaceAll(endpoint.Spec.Region, "-", "_")
+		for _, service := range services {
+			if strings.EqualFold(strings.ReplaceAll(service.GetRegionName(), "-", "_"), region) {
+				return service, nil
+			}
+		}
+		return nil, fmt.Errorf("no private endpoint service found in %s", endpoint.Spec.Region)
+	case "datasource":
+		source, ok := declared.(*types.DataSourceManifest)
+		if !ok {
+			return nil, unavailable
+		}
+		return f.LookupDataSource(ctx, source.Spec)
+	default:
+		return nil, fmt.Errorf("status of %s resources is not available; reference metadata or spec fields instead", kind)
+	}
+}
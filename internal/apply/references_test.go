@@ -0,0 +1,253 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+type fakeStatusFetcher struct {
+	status map[string]interface{}
+	calls  int
+}
+
+func (f *fakeStatusFetcher) FetchStatus(ctx context.Context, kind types.ResourceKind, name string, declared interface{}) (interface{}, error) {
+	f.calls++
+	status, ok := f.status[string(kind)+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("%s/%s not found", kind, name)
+	}
+	return status, nil
+}
+
+func referencingUser(name, scope string) *types.DatabaseUserManifest {
+	return &types.DatabaseUserManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindDatabaseUser,
+		Metadata:   types.ResourceMetadata{Name: name},
+		Spec: types.DatabaseUserSpec{
+			ProjectName: "proj",
+			Username:    name,
+			Roles:       []types.DatabaseRoleConfig{{RoleName: "read", DatabaseName: "app"}},
+			Scopes:      []types.UserScopeConfig{{Name: scope, Type: "CLUSTER"}},
+		},
+	}
+}
+
+func referencedCluster(name string) *types.ClusterManifest {
+	return &types.ClusterManifest{
+		APIVersion: types.APIVersionV1,
+		Kind:       types.KindCluster,
+		Metadata:   types.ResourceMetadata{Name: name},
+		Spec:       types.ClusterSpec{ProjectName: "proj", InstanceSize: "M10"},
+	}
+}
+
+func TestReferencesOf(t *testing.T) {
+	user := referencingUser("app", "${ref:Cluster/app-prod.metadata.name}")
+	user.Spec.Password = "${ref:Cluster/app-prod.metadata.name}-${ref:Project/proj.spec.name}"
+
+	refs := ReferencesOf(user)
+	want := []Reference{
+		{Kind: types.KindCluster, Name: "app-prod", Path: "metadata.name"},
+		{Kind: types.KindProject, Name: "proj", Path: "spec.name"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("Expected %v, got %v", want, refs)
+	}
+	if refs := ReferencesOf(referencedCluster("c")); len(refs) != 0 {
+		t.Errorf("Expected no references, got %v", refs)
+	}
+}
+
+func TestReferenceResolver_Lookup(t *testing.T) {
+	fetcher := &fakeStatusFetcher{status: map[string]interface{}{
+		"Cluster/app-prod": map[string]interface{}{
+			"stateName":         "IDLE",
+			"diskSizeGB":        40,
+			"connectionStrings": map[string]interface{}{"standardSrv": "mongodb+srv://app-prod.example.net"},
+		},
+	}}
+	resolver := NewReferenceResolver(fetcher)
+	resolver.Declare(types.KindCluster, "app-prod", referencedCluster("app-prod"))
+	ctx := context.Background()
+
+	value, err := resolver.ResolveString(ctx, "${ref:Cluster/app-prod.status.connectionStrings.standardSrv}")
+	if err != nil || value != "mongodb+srv://app-prod.example.net" {
+		t.Fatalf("Unexpected status value %v: %v", value, err)
+	}
+	if value, _ := resolver.ResolveString(ctx, "${ref:Cluster/app-prod.status.diskSizeGB}"); value != float64(40) {
+		t.Errorf("Expected a whole reference to keep its type, got %#v", value)
+	}
+	value, err = resolver.ResolveString(ctx, "${ref:cluster/app-prod.spec.instanceSize} is ${ref:Cluster/app-prod.status.stateName}")
+	if err != nil || value != "M10 is IDLE" {
+		t.Errorf("Unexpected embedded value %v: %v", value, err)
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("Expected status to be fetched once, got %d", fetcher.calls)
+	}
+	resolver.Forget(types.KindCluster, "app-prod")
+	if _, err := resolver.ResolveString(ctx, "${ref:Cluster/app-prod.status.stateName}"); err != nil || fetcher.calls != 2 {
+		t.Errorf("Expected status to be fetched again after Forget, got %d calls: %v", fetcher.calls, err)
+	}
+
+	for _, expression := range []string{
+		"${ref:Cluster/missing.spec.instanceSize}",
+		"${ref:Cluster/app-prod.spec.missing}",
+		"${ref:Cluster/app-prod.other.field}",
+		"prefix ${ref:Cluster/app-prod.status.connectionStrings}",
+	} {
+		_, err := resolver.ResolveString(ctx, expression)
+		var refErr *ReferenceError
+		if !errors.As(err, &refErr) {
+			t.Errorf("%s: expected a ReferenceError, got %v", expression, err)
+		}
+	}
+}
+
+// provisioningFetcher returns a cluster without connection strings until it has been read ready times
+type provisioningFetcher struct {
+	ready int
+	calls int
+}
+
+func (f *provisioningFetcher) FetchStatus(ctx context.Context, kind types.ResourceKind, name string, declared interface{}) (interface{}, error) {
+	f.calls++
+	if f.calls < f.ready {
+		return map[string]interface{}{"stateName": "CREATING"}, nil
+	}
+	return map[string]interface{}{
+		"stateName":         "IDLE",
+		"connectionStrings": map[string]interface{}{"standardSrv": "mongodb+srv://app-prod.example.net"},
+	}, nil
+}
+
+func TestReferenceResolver_WaitsForChangedResources(t *testing.T) {
+	plan := &Plan{Operations: []PlannedOperation{{
+		Operation: Operation{Type: OperationCreate, ResourceType: types.KindCluster, ResourceName: "app-prod", Desired: referencedCluster("app-prod")},
+	}}}
+	expression := "${ref:Cluster/app-prod.status.connectionStrings.standardSrv}"
+
+	fetcher := &provisioningFetcher{ready: 3}
+	resolver := NewReferenceResolver(fetcher)
+	resolver.DeclarePlan(plan)
+	resolver.WaitForStatus(time.Second, time.Millisecond)
+	value, err := resolver.ResolveString(context.Background(), expression)
+	if err != nil || value != "mongodb+srv://app-prod.example.net" {
+		t.Fatalf("Expected the connection string once the cluster is ready, got %v: %v", value, err)
+	}
+	if fetcher.calls != 3 {
+		t.Errorf("Expected status to be polled 3 times, got %d", fetcher.calls)
+	}
+
+	fetcher = &provisioningFetcher{ready: 1000}
+	resolver = NewReferenceResolver(fetcher)
+	resolver.DeclarePlan(plan)
+	resolver.WaitForStatus(20*time.Millisecond, time.Millisecond)
+	var refErr *ReferenceError
+	if _, err := resolver.ResolveString(context.Background(), expression); !errors.As(err, &refErr) {
+		t.Errorf("Expected a ReferenceError after the timeout, got %v", err)
+	}
+
+	// Without waiting, or for resources the plan does not change, status is read once
+	fetcher = &provisioningFetcher{ready: 3}
+	resolver = NewReferenceResolver(fetcher)
+	resolver.Declare(types.KindCluster, "app-prod", referencedCluster("app-prod"))
+	resolver.WaitForStatus(time.Second, time.Millisecond)
+	if _, err := resolver.ResolveString(context.Background(), expression); err == nil || fetcher.calls != 1 {
+		t.Errorf("Expected a single failed read for an unchanged cluster, got %d calls: %v", fetcher.calls, err)
+	}
+}
+
+func TestReferenceResolver_ResolveResource(t *testing.T) {
+	resolver := NewReferenceResolver(nil)
+	resolver.Declare(types.KindCluster, "app-prod", referencedCluster("app-prod"))
+	user := referencingUser("app", "${ref:Cluster/app-prod.metadata.name}")
+
+	resolved, err := resolver.ResolveResource(context.Background(), user)
+	if err != nil {
+		t.Fatalf("ResolveResource failed: %v", err)
+	}
+	got, ok := resolved.(*types.DatabaseUserManifest)
+	if !ok || got.Spec.Scopes[0].Name != "app-prod" {
+		t.Fatalf("Expected the scope to be resolved, got %#v", resolved)
+	}
+	if user.Spec.Scopes[0].Name != "${ref:Cluster/app-prod.metadata.name}" {
+		t.Error("Expected the original manifest to be left untouched")
+	}
+
+	if _, err := resolver.ResolveString(context.Background(), "${ref:Cluster/app-prod.status.stateName}"); err == nil {
+		t.Error("Expected status to be unavailable without a fetcher")
+	}
+}
+
+func TestPlanBuilder_ReferenceDependencies(t *testing.T) {
+	user := Operation{Type: OperationCreate, ResourceType: types.KindDatabaseUser, ResourceName: "app",
+		Desired: referencingUser("app", "${ref:Cluster/app-prod.metadata.name}")}
+	cluster := Operation{Type: OperationCreate, ResourceType: types.KindCluster, ResourceName: "app-prod",
+		Desired: referencedCluster("app-prod")}
+
+	plan, err := NewPlanBuilder("proj").AddOperations([]Operation{user, cluster}).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	userOp, clusterOp := plan.Operations[0], plan.Operations[1]
+	if !reflect.DeepEqual(userOp.References, []string{"Cluster/app-prod"}) {
+		t.Errorf("Unexpected references %v", userOp.References)
+	}
+	if !reflect.DeepEqual(userOp.Dependencies, []string{clusterOp.ID}) || userOp.Stage <= clusterOp.Stage {
+		t.Errorf("Expected the user to wait for the cluster, got deps %v stages %d/%d",
+			userOp.Dependencies, userOp.Stage, clusterOp.Stage)
+	}
+	if ordered := operationsByStage(plan.Operations); ordered[0].ID != clusterOp.ID {
+		t.Errorf("Expected the cluster to execute first, got %s", ordered[0].ID)
+	}
+
+	other := Operation{Type: OperationCreate, ResourceType: types.KindDatabaseUser, ResourceName: "other",
+		Desired: referencingUser("other", "${ref:DatabaseUser/app.metadata.name}")}
+	user.Desired = referencingUser("app", "${ref:DatabaseUser/other.metadata.name}")
+	deleted := cluster
+	deleted.Type, deleted.Current, deleted.Desired = OperationDelete, deleted.Desired, nil
+	badPath := Operation{Type: OperationCreate, ResourceType: types.KindDatabaseUser, ResourceName: "app",
+		Desired: referencingUser("app", "${ref:Cluster/app-prod.name}")}
+	for name, tc := range map[string]struct {
+		ops  []Operation
+		want string
+	}{
+		"cycle":   {[]Operation{user, other}, "circular reference"},
+		"missing": {[]Operation{other}, "not declared"},
+		"deleted": {[]Operation{referenceOnly(cluster), deleted}, "being deleted"},
+		"path":    {[]Operation{badPath, cluster}, "must start with"},
+	} {
+		if _, err := NewPlanBuilder("proj").AddOperations(tc.ops).Build(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, tc.want, err)
+		}
+	}
+}
+
+// referenceOnly returns a user operation referencing the resource of op
+func referenceOnly(op Operation) Operation {
+	return Operation{Type: OperationCreate, ResourceType: types.KindDatabaseUser, ResourceName: "ref",
+		Desired: referencingUser("ref", fmt.Sprintf("${ref:%s/%s.metadata.name}", op.ResourceType, op.ResourceName))}
+}
+
+func TestDiffEngine_IgnoresUnresolvedReferences(t *testing.T) {
+	desired := &ProjectState{DatabaseUsers: []types.DatabaseUserManifest{*referencingUser("app", "${ref:Cluster/app-prod.metadata.name}")}}
+	current := &ProjectState{DatabaseUsers: []types.DatabaseUserManifest{*referencingUser("app", "app-prod")}}
+
+	diff, err := NewDiffEngine().ComputeProjectDiff(desired, current)
+	if err != nil {
+		t.Fatalf("ComputeProjectDiff failed: %v", err)
+	}
+	for _, op := range diff.Operations {
+		if op.ResourceType == types.KindDatabaseUser && op.Type != OperationNoChange {
+			t.Errorf("Expected no change for a referenced field, got %s with %+v", op.Type, op.FieldChanges)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package apply
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/validation"
+)
+
+const rollbackLogVersion = 1
+
+// ErrNoRollbackLog is returned when an execution has no recorded rollback log
+var ErrNoRollbackLog = errors.New("no rollback record")
+
+// RollbackEntry records how to undo one completed operation of an execution
+type RollbackEntry struct {
+	Key          string             `json:"key"`
+	ResourceKind types.ResourceKind `json:"resourceKind"`
+	ResourceName string             `json:"resourceName"`
+	// Applied is the operation that was executed, Inverse the operation that undoes it
+	Applied OperationType `json:"applied"`
+	Inverse OperationType `json:"inverse,omitempty"`
+	Stage   int           `json:"stage"`
+	// Current is the resource as left by the applied operation and Desired the resource
+	// the inverse restores. Credentials are never recorded.
+	Current json.RawMessage `json:"current,omitempty"`
+	Desired json.RawMessage `json:"desired,omitempty"`
+	// Reversible is false when the inverse cannot be derived; Reason explains why
+	Reversible bool   `json:"reversible"`
+	Reason     string `json:"reason,omitempty"`
+	// Warning describes what the inverse does not restore, e.g. cluster data
+	Warning    string    `json:"warning,omitempty"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// RollbackLog is the list of inverse operations recorded while an execution ran
+type RollbackLog struct {
+	Version     int             `json:"version"`
+	ExecutionID string          `json:"executionId"`
+	ProjectID   string          `json:"projectId"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	Entries     []RollbackEntry `json:"entries"`
+	// RolledBackBy is the execution that rolled this one back
+	RolledBackBy string `json:"rolledBackBy,omitempty"`
+}
+
+// NewRollbackLog creates an empty rollback log for an execution
+func NewRollbackLog(executionID, projectID string) *RollbackLog {
+	now := time.Now().UTC()
+	return &RollbackLog{
+		Version:     rollbackLogVersion,
+		ExecutionID: executionID,
+		ProjectID:   projectID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Record adds the inverse of a completed operation. Only the first completion of a
+// resource is kept, so a resource re-applied on resume still rolls back to the state it
+// had before the execution started.
+func (l *RollbackLog) Record(op *PlannedOperation, now time.Time) {
+	if op.Type == OperationNoChange || !recordsRollback(op.Operation) {
+		return
+	}
+	key := ExecutionOperationKey(op.ResourceType, op.ResourceName)
+	for _, entry := range l.Entries {
+		if entry.Key == key {
+			return
+		}
+	}
+
+	entry := RollbackEntry{
+		Key:          key,
+		ResourceKind: op.ResourceType,
+		ResourceName: op.ResourceName,
+		Applied:      op.Type,
+		Stage:        op.Stage,
+		RecordedAt:   now.UTC(),
+	}
+	inverse, current, desired, reason, warning := invertOperation(op.Operation)
+	entry.Inverse = inverse
+	entry.Warning = warning
+	if reason == "" {
+		var err error
+		if entry.Current, err = rollbackSnapshot(op.ResourceType, current); err == nil {
+			entry.Desired, err = rollbackSnapshot(op.ResourceType, desired)
+		}
+		if err != nil {
+			reason = err.Error()
+		}
+	}
+	entry.Reversible = reason == ""
+	entry.Reason = reason
+	if !entry.Reversible {
+		entry.Current, entry.Desired = nil, nil
+	}
+
+	l.Entries = append(l.Entries, entry)
+	l.UpdatedAt = now.UTC()
+}
+
+// recordsRollback reports whether an operation changes anything that could be undone.
+// Search analytics kinds are read-only and project and federation settings deletes are
+// skipped by the executor.
+func recordsRollback(op Operation) bool {
+	switch op.ResourceType {
+	case types.KindSearchMetrics, types.KindSearchOptimization, types.KindSearchQueryValidation:
+		return false
+	case types.KindProject, types.KindFederationSettings:
+		return op.Type != OperationDelete
+	default:
+		return true
+	}
+}
+
+// invertOperation derives the operation that undoes op. It returns a reason when the
+// operation cannot be undone and a warning when the inverse is incomplete.
+func invertOperation(op Operation) (inverse OperationType, current, desired interface{}, reason, warning string) {
+	switch op.Type {
+	case OperationCreate:
+		switch op.ResourceType {
+		case types.KindSearchIndex:
+			return OperationDelete, nil, nil, "search index deletion is not supported by apply", ""
+		case types.KindFederationSettings:
+			return OperationDelete, nil, nil, "federation settings are never deleted by apply", ""
+		case types.KindProject:
+			return OperationDelete, nil, nil, "projects are never deleted by apply", ""
+		case types.KindCollection:
+			return OperationDelete, nil, nil, "collections are never dropped by apply", ""
+		case types.KindView, types.KindMaterializedView:
+			return OperationDelete, nil, nil, "views are never dropped by apply", ""
+		}
+		// Delete operations carry the resource as current, like the diff engine's
+		return OperationDelete, op.Desired, nil, "", ""
+
+	case OperationUpdate:
+		if op.Current == nil {
+			return OperationUpdate, nil, nil, "the previous state was not recorded", ""
+		}
+		switch op.ResourceType {
+		case types.KindIntegration:
+			return OperationUpdate, nil, nil, "Atlas does not return integration credentials, so the previous settings cannot be restored", ""
+		case types.KindLDAPConfiguration:
+			return OperationUpdate, nil, nil, "Atlas does not return the LDAP bind password, so the previous settings cannot be restored", ""
+		case types.KindSearchIndex:
+			return OperationUpdate, nil, nil, "search index updates are not supported by apply", ""
+		case types.KindDatabaseUser:
+			return OperationUpdate, op.Desired, op.Current, "", "password changes are not reverted"
+		case types.KindMaterializedView:
+			return OperationUpdate, op.Desired, op.Current, "", "reruns the previous pipeline; results merged by the update are kept"
+		}
+		return OperationUpdate, op.Desired, op.Current, "", ""
+
+	case OperationDelete:
+		if op.Current == nil {
+			return OperationCreate, nil, nil, "the deleted resource was not recorded", ""
+		}
+		switch op.ResourceType {
+		case types.KindIntegration:
+			return OperationCreate, nil, nil, "Atlas does not return integration credentials, so the integration cannot be recreated", ""
+		case types.KindLDAPConfiguration:
+			return OperationCreate, nil, nil, "Atlas does not return the LDAP bind password, so the configuration cannot be recreated", ""
+		case types.KindDatabaseUser:
+			if user, ok := op.Current.(*types.DatabaseUserManifest); ok && user != nil &&
+				validation.NormalizeAuthType(user.Spec.AuthType) == validation.AuthTypeSCRAM {
+				return OperationCreate, nil, nil, "Atlas does not return user passwords, so the user cannot be recreated", ""
+			}
+		case types.KindCluster:
+			return OperationCreate, nil, op.Current, "", "recreates an empty cluster; data is only recoverable from backup snapshots"
+		}
+		return OperationCreate, nil, op.Current, "", ""
+	}
+
+	return "", nil, nil, fmt.Sprintf("%s operations cannot be rolled back", op.Type), ""
+}
+
+// rollbackSnapshot serializes a resource for the rollback log with credentials removed
+func rollbackSnapshot(kind types.ResourceKind, resource interface{}) (json.RawMessage, error) {
+	if resource == nil {
+		return nil, nil
+	}
+	if v := reflect.ValueOf(resource); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+	expected := newRollbackResource(kind)
+	if expected == nil || reflect.TypeOf(resource) != reflect.TypeOf(expected) {
+		return nil, fmt.Errorf("%T resources cannot be recorded for rollback", resource)
+	}
+
+	data, err := json.Marshal(WithoutCredentials(resource))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record %s for rollback: %w", kind, err)
+	}
+	return data, nil
+}
+
+// newRollbackResource returns an empty manifest of the type the executor expects for kind
+func newRollbackResource(kind types.ResourceKind) interface{} {
+	switch kind {
+	case types.KindProject:
+		return &types.ProjectManifest{}
+	case types.KindCluster:
+		return &types.ClusterManifest{}
+	case types.KindDatabaseUser:
+		return &types.DatabaseUserManifest{}
+	case types.KindDatabaseRole:
+		return &types.DatabaseRoleManifest{}
+	case types.KindNetworkAccess:
+		return &types.NetworkAccessManifest{}
+	case types.KindSearchIndex:
+		return &types.SearchIndexManifest{}
+	case types.KindVPCEndpoint:
+		return &types.VPCEndpointManifest{}
+	case types.KindLDAPConfiguration:
+		return &types.LDAPConfigurationManifest{}
+	case types.KindIntegration:
+		return &types.IntegrationManifest{}
+	case types.KindFederationSettings:
+		return &types.FederationSettingsManifest{}
+	case types.KindRoleMapping:
+		return &types.RoleMappingManifest{}
+	case types.KindCollection:
+		return &types.CollectionManifest{}
+	case types.KindIndex:
+		return &types.IndexManifest{}
+	case types.KindView:
+		return &types.ViewManifest{}
+	case types.KindMaterializedView:
+		return &types.MaterializedViewManifest{}
+	default:
+		return nil
+	}
+}
+
+func decodeRollbackResource(kind types.ResourceKind, data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	resource := newRollbackResource(kind)
+	if resource == nil {
+		return nil, fmt.Errorf("unsupported resource kind %s", kind)
+	}
+	if err := json.Unmarshal(data, resource); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", kind, err)
+	}
+	return resource, nil
+}
+
+// Irreversible returns the entries that cannot be rolled back
+func (l *RollbackLog) Irreversible() []RollbackEntry {
+	var entries []RollbackEntry
+	for _, entry := range l.Entries {
+		if !entry.Reversible {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// BuildRollbackPlan turns the rollback log into a plan of inverse operations. Stages run
+// in the reverse order of the original execution, so dependents are undone before the
+// resources they depend on.
+func BuildRollbackPlan(l *RollbackLog) (*Plan, error) {
+	var entries []RollbackEntry
+	maxStage := 0
+	for _, entry := range l.Entries {
+		if !entry.Reversible {
+			continue
+		}
+		entries = append(entries, entry)
+		if entry.Stage > maxStage {
+			maxStage = entry.Stage
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("execution %s has no operations that can be rolled back", l.ExecutionID)
+	}
+
+	diffEngine := NewDiffEngine()
+	ops := make([]Operation, 0, len(entries))
+	for _, entry := range entries {
+		current, err := decodeRollbackResource(entry.ResourceKind, entry.Current)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rollback entry %s: %w", entry.Key, err)
+		}
+		desired, err := decodeRollbackResource(entry.ResourceKind, entry.Desired)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rollback entry %s: %w", entry.Key, err)
+		}
+		op := Operation{
+			Type:         entry.Inverse,
+			ResourceType: entry.ResourceKind,
+			ResourceName: entry.ResourceName,
+			Current:      current,
+			Desired:      desired,
+		}
+		if op.Type == OperationUpdate {
+			op.FieldChanges = diffEngine.computeFieldChanges(desired, current)
+		}
+		op.Impact = diffEngine.computeOperationImpact(&op)
+		if entry.Warning != "" {
+			op.Impact.Warnings = append(op.Impact.Warnings, entry.Warning)
+		}
+		ops = append(ops, op)
+	}
+
+	builder := NewPlanBuilder(l.ProjectID)
+	plan, err := builder.AddOperations(ops).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace dependency-based stages with the reversed stages of the original execution
+	for i := range plan.Operations {
+		plan.Operations[i].Stage = maxStage - entries[i].Stage
+		plan.Operations[i].Dependencies = nil
+	}
+	sort.SliceStable(plan.Operations, func(a, b int) bool {
+		return plan.Operations[a].Stage < plan.Operations[b].Stage
+	})
+	plan.Summary = builder.calculateSummary(plan.Operations)
+	plan.Description = fmt.Sprintf("Rollback of execution %s", l.ExecutionID)
+
+	return plan, nil
+}
+
+// RollbackStore keeps rollback logs, one file per execution
+type RollbackStore struct {
+	dir string
+}
+
+// NewRollbackStore creates a store in dir, defaulting to ~/.matlas/rollback
+func NewRollbackStore(dir string) *RollbackStore {
+	if dir == "" {
+		homeDir, _ := os.UserHomeDir()
+		dir = filepath.Join(homeDir, ".matlas", "rollback")
+	}
+	return &RollbackStore{dir: dir}
+}
+
+func (s *RollbackStore) path(executionID string) string {
+	return filepath.Join(s.dir, executionID+".json")
+}
+
+// Load reads the rollback log of an execution
+func (s *RollbackStore) Load(executionID string) (*RollbackLog, error) {
+	// #nosec G304 -- path is built from the rollback directory and an execution ID
+	data, err := os.ReadFile(s.path(executionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w for execution %s", ErrNoRollbackLog, executionID)
+		}
+		return nil, fmt.Errorf("failed to read rollback record: %w", err)
+	}
+
+	var l RollbackLog
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse rollback record for execution %s: %w", executionID, err)
+	}
+	if l.Version > rollbackLogVersion {
+		return nil, fmt.Errorf("rollback record for execution %s has unsupported version %d", executionID, l.Version)
+	}
+	return &l, nil
+}
+
+// Save writes the rollback log of an execution
+func (s *RollbackStore) Save(l *RollbackLog) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rollback record: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0750); err != nil {
+		return fmt.Errorf("failed to create rollback directory: %w", err)
+	}
+	// Write to a temporary file first so a crash never leaves a truncated record
+	path := s.path(l.ExecutionID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write rollback record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write rollback record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package apply
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teabranch/matlas-cli/internal/apply/dag"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func rollbackUser(name, password, authType string) *types.DatabaseUserManifest {
+	return &types.DatabaseUserManifest{
+		Kind:     types.KindDatabaseUser,
+		Metadata: types.ResourceMetadata{Name: name},
+		Spec: types.DatabaseUserSpec{
+			Username: name,
+			Password: password,
+			AuthType: authType,
+			Roles:    []types.DatabaseRoleConfig{{RoleName: "read", DatabaseName: "app"}},
+		},
+	}
+}
+
+func rollbackCluster(name, tier string) *types.ClusterManifest {
+	return &types.ClusterManifest{
+		Kind:     types.KindCluster,
+		Metadata: types.ResourceMetadata{Name: name},
+		Spec:     types.ClusterSpec{Provider: "AWS", Region: "US_EAST_1", InstanceSize: tier},
+	}
+}
+
+func rollbackOp(opType OperationType, kind types.ResourceKind, name string, stage int, current, desired interface{}) *PlannedOperation {
+	return &PlannedOperation{
+		Stage: stage,
+		Operation: Operation{
+			Type:         opType,
+			ResourceType: kind,
+			ResourceName: name,
+			Current:      current,
+			Desired:      desired,
+		},
+	}
+}
+
+func TestRollbackLog_RecordsInverseOperations(t *testing.T) {
+	log := NewRollbackLog("exec-1", "proj")
+	now := time.Now()
+
+	log.Record(rollbackOp(OperationCreate, types.KindDatabaseUser, "app", 1, nil, rollbackUser("app", "s3cret-pass", "")), now)
+	log.Record(rollbackOp(OperationUpdate, types.KindCluster, "main", 0, rollbackCluster("main", "M10"), rollbackCluster("main", "M30")), now)
+	log.Record(rollbackOp(OperationDelete, types.KindDatabaseUser, "old", 1, rollbackUser("old", "", ""), nil), now)
+	log.Record(rollbackOp(OperationDelete, types.KindDatabaseUser, "ci", 1, rollbackUser("ci", "", "AWS_IAM_ROLE"), nil), now)
+	log.Record(rollbackOp(OperationNoChange, types.KindNetworkAccess, "office", 0, nil, nil), now)
+	log.Record(rollbackOp(OperationCreate, types.KindSearchMetrics, "metrics", 0, nil, &types.SearchMetricsManifest{}), now)
+	// A second completion of the same resource keeps the original inverse
+	log.Record(rollbackOp(OperationUpdate, types.KindCluster, "main", 0, rollbackCluster("main", "M30"), rollbackCluster("main", "M40")), now)
+
+	if len(log.Entries) != 4 {
+		t.Fatalf("Expected 4 rollback entries, got %d", len(log.Entries))
+	}
+
+	create := log.Entries[0]
+	if create.Inverse != OperationDelete || !create.Reversible || create.Desired != nil {
+		t.Errorf("Unexpected inverse of create: %+v", create)
+	}
+	if strings.Contains(string(create.Current), "s3cret-pass") {
+		t.Error("Expected the user password not to be recorded")
+	}
+
+	update := log.Entries[1]
+	if update.Inverse != OperationUpdate || !strings.Contains(string(update.Desired), "M10") || !strings.Contains(string(update.Current), "M30") {
+		t.Errorf("Expected the update to restore the previous tier, got %+v", update)
+	}
+
+	if log.Entries[2].Reversible || !strings.Contains(log.Entries[2].Reason, "passwords") {
+		t.Errorf("Expected deleted password user to be irreversible, got %+v", log.Entries[2])
+	}
+	if !log.Entries[3].Reversible || log.Entries[3].Inverse != OperationCreate {
+		t.Errorf("Expected deleted IAM user to be recreated, got %+v", log.Entries[3])
+	}
+	if got := log.Irreversible(); len(got) != 1 || got[0].Key != "DatabaseUser/old" {
+		t.Errorf("Unexpected irreversible entries: %+v", got)
+	}
+}
+
+func TestBuildRollbackPlan_ReversesStages(t *testing.T) {
+	log := NewRollbackLog("exec-1", "proj")
+	now := time.Now()
+	log.Record(rollbackOp(OperationCreate, types.KindCluster, "main", 0, nil, rollbackCluster("main", "M10")), now)
+	log.Record(rollbackOp(OperationDelete, types.KindCluster, "legacy", 0, rollbackCluster("legacy", "M20"), nil), now)
+	log.Record(rollbackOp(OperationCreate, types.KindDatabaseUser, "app", 1, nil, rollbackUser("app", "s3cret-pass", "")), now)
+	log.Record(rollbackOp(OperationCreate, types.KindIntegration, "SLACK", 1, nil, &types.IntegrationManifest{Kind: types.KindIntegration}), now)
+
+	plan, err := BuildRollbackPlan(log)
+	if err != nil {
+		t.Fatalf("BuildRollbackPlan failed: %v", err)
+	}
+	if plan.ProjectID != "proj" || !strings.Contains(plan.Description, "exec-1") {
+		t.Errorf("Unexpected plan metadata: %s %q", plan.ProjectID, plan.Description)
+	}
+	if len(plan.Operations) != 4 {
+		t.Fatalf("Expected 4 inverse operations, got %d", len(plan.Operations))
+	}
+
+	// Operations of the last stage are undone first
+	first := plan.Operations[0]
+	if first.Stage != 0 || first.ResourceType != types.KindDatabaseUser || first.Type != OperationDelete {
+		t.Errorf("Expected the user delete first, got %s %s/%s (stage %d)", first.Type, first.ResourceType, first.ResourceName, first.Stage)
+	}
+	if _, ok := first.Current.(*types.DatabaseUserManifest); !ok {
+		t.Errorf("Expected a decoded user manifest, got %T", first.Current)
+	}
+
+	for _, op := range plan.Operations {
+		if op.ResourceName != "legacy" {
+			continue
+		}
+		if op.Type != OperationCreate || op.Stage != 1 {
+			t.Errorf("Expected the deleted cluster to be recreated in the last stage, got %s (stage %d)", op.Type, op.Stage)
+		}
+		if cluster, ok := op.Desired.(*types.ClusterManifest); !ok || cluster.Spec.InstanceSize != "M20" {
+			t.Errorf("Expected the previous cluster spec, got %+v", op.Desired)
+		}
+		if op.Impact == nil || len(op.Impact.Warnings) == 0 {
+			t.Error("Expected a warning that cluster data is not restored")
+		}
+	}
+	if plan.Summary.TotalOperations != 4 || plan.Summary.OperationsByStage[0] != 2 {
+		t.Errorf("Unexpected plan summary: %+v", plan.Summary)
+	}
+
+	if _, err := BuildRollbackPlan(NewRollbackLog("exec-2", "proj")); err == nil {
+		t.Error("Expected an error for an execution with nothing to roll back")
+	}
+}
+
+func TestRollbackStore_SaveAndLoad(t *testing.T) {
+	store := NewRollbackStore(filepath.Join(t.TempDir(), "rollback"))
+	if _, err := store.Load("exec-1"); !errors.Is(err, ErrNoRollbackLog) {
+		t.Fatalf("Expected ErrNoRollbackLog, got %v", err)
+	}
+
+	log := NewRollbackLog("exec-1", "proj")
+	log.Record(rollbackOp(OperationCreate, types.KindCluster, "main", 0, nil, rollbackCluster("main", "M10")), time.Now())
+	if err := store.Save(log); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	info, err := os.Stat(store.path("exec-1"))
+	if err != nil {
+		t.Fatalf("Expected rollback file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected rollback file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	loaded, err := store.Load("exec-1")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].Key != "Cluster/main" {
+		t.Errorf("Unexpected loaded entries: %+v", loaded.Entries)
+	}
+}
+
+func TestExecutionJournal_RecordsRollback(t *testing.T) {
+	journal, _, _ := newTestJournal(t)
+	store := NewRollbackStore(filepath.Join(t.TempDir(), "rollback"))
+	journal.WithRollbackStore(store)
+
+	plan := &Plan{ID: "plan-1", ProjectID: "proj", Operations: []PlannedOperation{
+		*rollbackOp(OperationCreate, types.KindCluster, "main", 0, nil, rollbackCluster("main", "M10")),
+		*rollbackOp(OperationCreate, types.KindDatabaseUser, "app", 1, nil, rollbackUser("app", "s3cret-pass", "")),
+	}}
+	if err := journal.Begin("exec-1", plan, nil, ""); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	journal.OperationFinished(&plan.Operations[0], nil)
+	journal.OperationFinished(&plan.Operations[1], errors.New("boom"))
+	journal.Finish(dag.ExecutionStatusFailed)
+
+	log, err := store.Load("exec-1")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(log.Entries) != 1 || log.Entries[0].Key != "Cluster/main" || log.ProjectID != "proj" {
+		t.Errorf("Expected only the completed operation to be recorded, got %+v", log.Entries)
+	}
+}
@@ -0,0 +1,244 @@
+package apply
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/teabranch/matlas-cli/internal/security"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// sensitiveTag marks a manifest field as holding a credential (`sensitive:"true"`).
+// Sensitive values are masked with security.MaskValue wherever plans and diffs are shown.
+const sensitiveTag = "sensitive"
+
+// SensitiveValue is a credential set on a resource, already masked for display
+type SensitiveValue struct {
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+func isSensitiveField(field reflect.StructField) bool {
+	return field.Tag.Get(sensitiveTag) == "true"
+}
+
+// maskSensitive masks a credential value for display, leaving empty values untouched
+func maskSensitive(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if s, ok := value.(string); ok && s == "" {
+		return s
+	}
+	return security.MaskValue(value)
+}
+
+// markSensitive masks the values of field changes on a sensitive field and flags them
+func markSensitive(changes []FieldChange) {
+	for i := range changes {
+		changes[i].Sensitive = true
+		changes[i].OldValue = maskSensitive(changes[i].OldValue)
+		changes[i].NewValue = maskSensitive(changes[i].NewValue)
+	}
+}
+
+// SensitiveValues returns the non-empty sensitive fields of a resource manifest with
+// their values masked. Paths use the same notation as FieldChange paths (e.g. Spec.APIKey).
+func SensitiveValues(resource interface{}) []SensitiveValue {
+	var values []SensitiveValue
+	v := reflect.ValueOf(resource)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	collectSensitiveValues("", v, &values)
+	return values
+}
+
+func collectSensitiveValues(path string, v reflect.Value, values *[]SensitiveValue) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		fieldValue := v.Field(i)
+		if isSensitiveField(field) {
+			if !fieldValue.IsZero() {
+				*values = append(*values, SensitiveValue{Path: fieldPath, Value: maskSensitive(fieldValue.Interface())})
+			}
+			continue
+		}
+		collectSensitiveValues(fieldPath, fieldValue, values)
+	}
+}
+
+// RedactResource returns a copy of a manifest pointer with its sensitive fields masked.
+// Only fields reachable through nested struct values are redacted; resources without
+// sensitive fields are returned as-is.
+func RedactResource(resource interface{}) interface{} {
+	return rewriteSensitive(resource, func(value string) string {
+		if masked, ok := maskSensitive(value).(string); ok {
+			return masked
+		}
+		return value
+	})
+}
+
+// ClearSensitive returns a copy of a manifest pointer with its sensitive fields emptied,
+// for resources that are persisted locally
+func ClearSensitive(resource interface{}) interface{} {
+	return rewriteSensitive(resource, func(string) string { return "" })
+}
+
+// WithoutCredentials returns a copy of a manifest pointer with every credential emptied:
+// sensitive fields as well as user and LDAP bind passwords, which are supplied in the
+// configuration rather than tagged on the manifest
+func WithoutCredentials(resource interface{}) interface{} {
+	switch r := resource.(type) {
+	case *types.DatabaseUserManifest:
+		if r != nil {
+			cp := *r
+			cp.Spec.Password = ""
+			resource = &cp
+		}
+	case *types.LDAPConfigurationManifest:
+		if r != nil {
+			cp := *r
+			cp.Spec.BindPassword = ""
+			resource = &cp
+		}
+	}
+	return ClearSensitive(resource)
+}
+
+func rewriteSensitive(resource interface{}, rewrite func(string) string) interface{} {
+	if len(SensitiveValues(resource)) == 0 {
+		return resource
+	}
+	v := reflect.ValueOf(resource)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return resource
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	rewriteStruct(cp.Elem(), rewrite)
+	return cp.Interface()
+}
+
+func rewriteStruct(v reflect.Value, rewrite func(string) string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldValue := v.Field(i)
+		switch {
+		case isSensitiveField(field) && fieldValue.Kind() == reflect.String && fieldValue.Len() > 0:
+			fieldValue.SetString(rewrite(fieldValue.String()))
+		case fieldValue.Kind() == reflect.Struct:
+			rewriteStruct(fieldValue, rewrite)
+		}
+	}
+}
+
+// RedactPlan returns a copy of the plan whose operations carry redacted resources,
+// suitable for JSON and YAML output. The original plan is left untouched for execution.
+func RedactPlan(plan *Plan) *Plan {
+	if plan == nil {
+		return nil
+	}
+	redacted := *plan
+	redacted.Operations = make([]PlannedOperation, len(plan.Operations))
+	for i, op := range plan.Operations {
+		op.Desired = RedactResource(op.Desired)
+		op.Current = RedactResource(op.Current)
+		op.Hooks = redactHooks(op.Hooks)
+		redacted.Operations[i] = op
+	}
+	return &redacted
+}
+
+// RedactDiff returns a copy of the diff whose operations carry redacted resources
+func RedactDiff(diff *Diff) *Diff {
+	if diff == nil {
+		return nil
+	}
+	redacted := *diff
+	redacted.Operations = make([]Operation, len(diff.Operations))
+	for i, op := range diff.Operations {
+		op.Desired = RedactResource(op.Desired)
+		op.Current = RedactResource(op.Current)
+		redacted.Operations[i] = op
+	}
+	return &redacted
+}
+
+// credentialKeys are the YAML keys of credentials: the sensitive fields of specs and hooks,
+// and user and LDAP bind passwords
+var credentialKeys = func() map[string]bool {
+	keys := map[string]bool{"password": true, "bindPassword": true}
+	for _, value := range []interface{}{types.IntegrationSpec{}, types.WebhookHook{}} {
+		t := reflect.TypeOf(value)
+		for i := 0; i < t.NumField(); i++ {
+			if field := t.Field(i); isSensitiveField(field) {
+				keys[strings.Split(field.Tag.Get("yaml"), ",")[0]] = true
+			}
+		}
+	}
+	return keys
+}()
+
+// RedactValues masks credentials in configuration decoded into generic YAML values, such
+// as rendered ApplyDocuments whose specs are still maps. Values are masked in place.
+func RedactValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if credentialKeys[key] {
+				v[key] = maskSensitive(item)
+			} else {
+				v[key] = RedactValues(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = RedactValues(item)
+		}
+	}
+	return value
+}
+
+// RedactOutputs masks the resolved values of outputs holding credentials: outputs named
+// or referencing a credential field, and credentials nested in object values
+func RedactOutputs(outputs map[string]string, values map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		if credentialKeys[name] || referencesCredential(outputs[name]) {
+			redacted[name] = maskSensitive(value)
+			continue
+		}
+		redacted[name] = RedactValues(value)
+	}
+	return redacted
+}
+
+// referencesCredential reports whether an expression references a credential field
+func referencesCredential(expression string) bool {
+	for _, ref := range ParseReferences(expression) {
+		for _, segment := range strings.Split(ref.Path, ".") {
+			if credentialKeys[segment] {
+				return true
+			}
+		}
+	}
+	return false
+}
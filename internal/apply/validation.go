@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/teabranch/matlas-cli/internal/services/atlas"
+	"github.com/teabranch/matlas-cli/internal/services/database"
 	"github.com/teabranch/matlas-cli/internal/types"
 	"github.com/teabranch/matlas-cli/internal/validation"
 )
@@ -357,12 +361,29 @@ func validateClusterConfig(cluster *types.ClusterConfig, basePath string, result
 }
 
 func validateDatabaseUserConfig(user *types.DatabaseUserConfig, basePath string, result *ValidationResult, opts *ValidatorOptions) {
-	// Validate username
-	if err := validation.ValidateUsername(user.Username); err != nil {
+	// Validate auth type and the username format it implies
+	if err := validation.ValidateAuthType(user.AuthType); err != nil {
+		result.AddError(basePath+".authType", "authType", user.AuthType,
+			err.Error(), "INVALID_AUTH_TYPE")
+	} else if err := validation.ValidateUsernameForAuthType(user.Username, user.AuthType); err != nil {
 		result.AddError(basePath+".username", "username", user.Username,
 			err.Error(), "INVALID_USERNAME")
 	}
 
+	// Externally authenticated users (X.509, LDAP, AWS IAM, OIDC) have no password
+	// and must live in the authentication database Atlas expects for their type
+	if validation.IsExternalAuthType(user.AuthType) {
+		if user.Password != "" {
+			result.AddError(basePath+".password", "password", "",
+				fmt.Sprintf("password is not supported for %s users", validation.NormalizeAuthType(user.AuthType)), "PASSWORD_NOT_SUPPORTED")
+		}
+		expected := validation.AuthDatabaseForType(user.AuthType)
+		if user.AuthDatabase != "" && user.AuthDatabase != expected {
+			result.AddError(basePath+".authDatabase", "authDatabase", user.AuthDatabase,
+				fmt.Sprintf("%s users must use authentication database %q", validation.NormalizeAuthType(user.AuthType), expected), "INVALID_AUTH_DATABASE")
+		}
+	}
+
 	// Validate roles
 	if len(user.Roles) == 0 {
 		result.AddError(basePath+".roles", "roles", "",
@@ -374,8 +395,8 @@ func validateDatabaseUserConfig(user *types.DatabaseUserConfig, basePath string,
 		validateDatabaseRole(&role, path, result)
 	}
 
-	// Validate auth database
-	if user.AuthDatabase != "" {
+	// Validate auth database (external auth types were checked above)
+	if user.AuthDatabase != "" && !validation.IsExternalAuthType(user.AuthType) {
 		validateDatabaseName(user.AuthDatabase, basePath+".authDatabase", result)
 	}
 
@@ -417,6 +438,35 @@ func validateNetworkAccessConfig(netAccess *types.NetworkAccessConfig, basePath
 				"invalid date format (use RFC3339)", "INVALID_DATE_FORMAT")
 		}
 	}
+
+	// Validate expiresAt alias and ttl
+	if netAccess.ExpiresAt != "" {
+		if _, err := time.Parse(time.RFC3339, netAccess.ExpiresAt); err != nil {
+			result.AddError(basePath+".expiresAt", "expiresAt", netAccess.ExpiresAt,
+				"invalid date format (use RFC3339)", "INVALID_DATE_FORMAT")
+		}
+	}
+	if netAccess.TTL != "" {
+		if _, err := atlas.ParseNetworkAccessTTL(netAccess.TTL); err != nil {
+			result.AddError(basePath+".ttl", "ttl", netAccess.TTL, err.Error(), "INVALID_TTL")
+		}
+	}
+	expirySettings := 0
+	for _, v := range []string{netAccess.DeleteAfterDate, netAccess.ExpiresAt, netAccess.TTL} {
+		if v != "" {
+			expirySettings++
+		}
+	}
+	if expirySettings > 1 {
+		addError(result, basePath, "ttl", netAccess.TTL,
+			"only one of deleteAfterDate, expiresAt or ttl can be specified", "CONFLICTING_EXPIRY")
+	}
+	for field, value := range map[string]string{"deleteAfterDate": netAccess.DeleteAfterDate, "expiresAt": netAccess.ExpiresAt} {
+		if expiry, err := time.Parse(time.RFC3339, value); err == nil && time.Until(expiry) > atlas.MaxNetworkAccessTTL {
+			addWarning(result, basePath+"."+field, field, value,
+				"Atlas only accepts expiry dates up to one week ahead; apply will fail until the date is within range", "EXPIRY_TOO_FAR")
+		}
+	}
 }
 
 func validateMetadata(metadata *types.MetadataConfig, basePath string, result *ValidationResult, opts *ValidatorOptions) {
@@ -462,6 +512,8 @@ func validateDocumentStructure(doc *types.ApplyDocument, result *ValidationResul
 		addError(result, "resources", "resources", "",
 			"at least one resource is required", "REQUIRED_FIELD_MISSING")
 	}
+
+	validateHooks(doc.Hooks, "hooks", result)
 }
 
 func validateResourceManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
@@ -482,6 +534,66 @@ func validateResourceManifest(manifest *types.ResourceManifest, basePath string,
 
 	// Validate resource-specific content based on kind
 	validateResourceContent(manifest, basePath, result, opts)
+
+	validateHooks(manifest.Hooks, basePath+".hooks", result)
+}
+
+// validateHooks validates the preApply/postApply/onFailure hooks of a document or resource
+func validateHooks(hooks *types.Hooks, basePath string, result *ValidationResult) {
+	if hooks == nil {
+		return
+	}
+	phases := []struct {
+		name  string
+		hooks []types.Hook
+	}{
+		{"preApply", hooks.PreApply},
+		{"postApply", hooks.PostApply},
+		{"onFailure", hooks.OnFailure},
+	}
+	for _, phase := range phases {
+		for i, hook := range phase.hooks {
+			validateHook(hook, fmt.Sprintf("%s.%s[%d]", basePath, phase.name, i), result)
+		}
+	}
+}
+
+func validateHook(hook types.Hook, path string, result *ValidationResult) {
+	hasCommand := strings.TrimSpace(hook.Command) != ""
+	if hasCommand == (hook.Webhook != nil) {
+		addError(result, path, "command", "",
+			"hook must declare exactly one of command or webhook", "INVALID_HOOK")
+	}
+
+	if hook.Webhook != nil {
+		// The URL is not echoed back since webhook URLs often embed tokens
+		if parsed, err := url.Parse(hook.Webhook.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			addError(result, path+".webhook.url", "url", "",
+				"webhook url must be an absolute http or https URL", "INVALID_HOOK")
+		}
+		switch strings.ToUpper(hook.Webhook.Method) {
+		case "", http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			addError(result, path+".webhook.method", "method", hook.Webhook.Method,
+				"webhook method must be POST, PUT or PATCH", "INVALID_HOOK")
+		}
+	}
+
+	for j, op := range hook.Operations {
+		switch OperationType(op) {
+		case OperationCreate, OperationUpdate, OperationDelete:
+		default:
+			addError(result, fmt.Sprintf("%s.operations[%d]", path, j), "operations", op,
+				"operations must be Create, Update or Delete", "INVALID_HOOK")
+		}
+	}
+
+	if hook.Timeout != "" {
+		if timeout, err := time.ParseDuration(hook.Timeout); err != nil || timeout <= 0 {
+			addError(result, path+".timeout", "timeout", hook.Timeout,
+				"timeout must be a positive duration such as 30s or 5m", "INVALID_HOOK")
+		}
+	}
 }
 
 // validateResourceContent validates the specific content of a resource based on its kind
@@ -511,6 +623,24 @@ func validateResourceContent(manifest *types.ResourceManifest, basePath string,
 		validateAlertConfigurationManifest(manifest, basePath, result, opts)
 	case types.KindAlert:
 		validateAlertManifest(manifest, basePath, result, opts)
+	case types.KindLDAPConfiguration:
+		validateLDAPConfigurationManifest(manifest, basePath, result, opts)
+	case types.KindIntegration:
+		validateIntegrationManifest(manifest, basePath, result, opts)
+	case types.KindFederationSettings:
+		validateFederationSettingsManifest(manifest, basePath, result, opts)
+	case types.KindRoleMapping:
+		validateRoleMappingManifest(manifest, basePath, result, opts)
+	case types.KindDataSource:
+		validateDataSourceManifest(manifest, basePath, result, opts)
+	case types.KindCollection:
+		validateCollectionManifest(manifest, basePath, result, opts)
+	case types.KindIndex:
+		validateIndexManifest(manifest, basePath, result, opts)
+	case types.KindView:
+		validateViewManifest(manifest, basePath, result, opts)
+	case types.KindMaterializedView:
+		validateMaterializedViewManifest(manifest, basePath, result, opts)
 	default:
 		// For unknown resource types, log a warning but don't fail validation
 		addWarning(result, basePath+".kind", "kind", string(manifest.Kind),
@@ -547,6 +677,7 @@ func validateDatabaseUserManifest(manifest *types.ResourceManifest, basePath str
 		Password:     userSpec.Password,
 		Roles:        userSpec.Roles,
 		AuthDatabase: userSpec.AuthDatabase,
+		AuthType:     userSpec.AuthType,
 		Scopes:       userSpec.Scopes,
 	}
 
@@ -658,6 +789,8 @@ func validateNetworkAccessManifest(manifest *types.ResourceManifest, basePath st
 		AWSSecurityGroup: netSpec.AWSSecurityGroup,
 		Comment:          netSpec.Comment,
 		DeleteAfterDate:  netSpec.DeleteAfterDate,
+		ExpiresAt:        netSpec.ExpiresAt,
+		TTL:              netSpec.TTL,
 	}
 
 	// Use the existing validation function
@@ -744,7 +877,7 @@ func validateCrossFieldRules(config *types.ApplyConfig, result *ValidationResult
 				break
 			}
 		}
-		if hasAdminRole && user.AuthDatabase != "admin" {
+		if hasAdminRole && user.AuthDatabase != "admin" && !validation.IsExternalAuthType(user.AuthType) {
 			path := fmt.Sprintf("spec.databaseUsers[%d].authDatabase", i)
 			addWarning(result, path, "authDatabase", user.AuthDatabase,
 				"users with admin roles should typically use 'admin' auth database", "ADMIN_ROLE_AUTH_DATABASE")
@@ -1454,8 +1587,8 @@ func validateBusinessLogicRules(config *types.ApplyConfig, result *ValidationRes
 				"SUBOPTIMAL_ROLE_COMBINATION")
 		}
 
-		// Validate authentication database
-		if user.AuthDatabase != "" {
+		// Validate authentication database (external auth types use $external)
+		if user.AuthDatabase != "" && !validation.IsExternalAuthType(user.AuthType) {
 			if !isAuthDatabaseValid(user.AuthDatabase, user.Roles) {
 				addError(result, path+".authDatabase", "authDatabase", user.AuthDatabase,
 					fmt.Sprintf("Authentication database %s is not valid for the specified roles", user.AuthDatabase),
@@ -2417,3 +2550,574 @@ func convertToAlertNotification(notificationMap map[string]interface{}) types.Al
 
 	return notification
 }
+
+// validateLDAPConfigurationManifest validates a project LDAPConfiguration resource manifest
+func validateLDAPConfigurationManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.LDAPConfigurationSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.LDAPConfigurationSpec:
+		spec = s
+	case map[string]interface{}:
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid LDAPConfiguration spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"LDAPConfiguration spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateLDAPConfigurationSpec(&spec, basePath+".spec", result)
+}
+
+// validateLDAPConfigurationSpec validates LDAP connection settings and user-to-DN mappings
+func validateLDAPConfigurationSpec(spec *types.LDAPConfigurationSpec, basePath string, result *ValidationResult) {
+	if spec.ProjectName == "" {
+		result.AddError(basePath+".projectName", "projectName", "",
+			"project name is required", "REQUIRED_FIELD_MISSING")
+	}
+
+	if spec.Hostname == "" {
+		result.AddError(basePath+".hostname", "hostname", "",
+			"LDAP hostname is required", "REQUIRED_FIELD_MISSING")
+	} else if strings.Contains(spec.Hostname, "://") || strings.ContainsAny(spec.Hostname, " /") {
+		result.AddError(basePath+".hostname", "hostname", spec.Hostname,
+			"hostname must not include a scheme or path (e.g., ldap.example.com)", "INVALID_HOSTNAME")
+	}
+
+	if spec.Port != 0 {
+		if err := validation.ValidateRange(spec.Port, "port", 1, 65535); err != nil {
+			result.AddError(basePath+".port", "port", fmt.Sprintf("%d", spec.Port),
+				err.Error(), "INVALID_PORT")
+		}
+	}
+
+	if err := validation.ValidateDistinguishedName(spec.BindUsername, "bindUsername"); err != nil {
+		result.AddError(basePath+".bindUsername", "bindUsername", spec.BindUsername,
+			err.Error(), "INVALID_BIND_USERNAME")
+	}
+
+	if spec.BindPassword == "" {
+		addWarning(result, basePath+".bindPassword", "bindPassword", "",
+			"bindPassword is required when LDAP is first configured or the bind user changes", "MISSING_BIND_PASSWORD")
+	}
+
+	if spec.AuthorizationEnabled != nil && *spec.AuthorizationEnabled && spec.AuthzQueryTemplate == "" {
+		result.AddError(basePath+".authzQueryTemplate", "authzQueryTemplate", "",
+			"authzQueryTemplate is required when authorizationEnabled is true", "REQUIRED_FIELD_MISSING")
+	}
+
+	for i, mapping := range spec.UserToDNMapping {
+		path := fmt.Sprintf("%s.userToDNMapping[%d]", basePath, i)
+		if mapping.Match == "" {
+			result.AddError(path+".match", "match", "",
+				"match expression is required", "REQUIRED_FIELD_MISSING")
+		} else if _, err := regexp.Compile(mapping.Match); err != nil {
+			result.AddError(path+".match", "match", mapping.Match,
+				fmt.Sprintf("match must be a valid regular expression: %v", err), "INVALID_REGEX")
+		}
+		if (mapping.Substitution == "") == (mapping.LDAPQuery == "") {
+			result.AddError(path, "userToDNMapping", "",
+				"exactly one of substitution or ldapQuery must be set", "INVALID_DN_MAPPING")
+		}
+	}
+}
+
+// validateIntegrationManifest validates a project third-party Integration resource manifest
+func validateIntegrationManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.IntegrationSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.IntegrationSpec:
+		spec = s
+	case map[string]interface{}:
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid Integration spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"Integration spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateIntegrationSpec(&spec, basePath+".spec", result)
+}
+
+// validateIntegrationSpec validates the integration type and the fields each type requires
+func validateIntegrationSpec(spec *types.IntegrationSpec, basePath string, result *ValidationResult) {
+	if spec.ProjectName == "" {
+		result.AddError(basePath+".projectName", "projectName", "",
+			"project name is required", "REQUIRED_FIELD_MISSING")
+	}
+
+	integrationType := atlas.NormalizeIntegrationType(spec.Type)
+	if integrationType == "" {
+		result.AddError(basePath+".type", "type", "",
+			"integration type is required", "REQUIRED_FIELD_MISSING")
+		return
+	}
+	if err := validation.ValidateEnum(integrationType, "type", atlas.SupportedIntegrationTypes); err != nil {
+		result.AddError(basePath+".type", "type", spec.Type, err.Error(), "INVALID_INTEGRATION_TYPE")
+		return
+	}
+
+	require := func(field, value string) {
+		if value == "" {
+			result.AddError(basePath+"."+field, field, "",
+				fmt.Sprintf("%s is required for %s integrations", field, integrationType), "REQUIRED_FIELD_MISSING")
+		}
+	}
+	checkRegion := func(allowed []string) {
+		if spec.Region == "" {
+			return
+		}
+		if err := validation.ValidateEnum(spec.Region, "region", allowed); err != nil {
+			result.AddError(basePath+".region", "region", spec.Region, err.Error(), "INVALID_REGION")
+		}
+	}
+	checkHTTPS := func(field, value string) {
+		if value != "" && !strings.HasPrefix(value, "https://") {
+			result.AddError(basePath+"."+field, field, "",
+				fmt.Sprintf("%s must be an https:// URL", field), "INVALID_URL")
+		}
+	}
+
+	switch integrationType {
+	case atlas.IntegrationTypeDatadog:
+		require("apiKey", spec.APIKey)
+		checkRegion([]string{"US", "EU", "US3", "US5", "AP1", "US1_FED"})
+	case atlas.IntegrationTypeOpsGenie:
+		require("apiKey", spec.APIKey)
+		checkRegion([]string{"US", "EU"})
+	case atlas.IntegrationTypePagerDuty:
+		require("serviceKey", spec.ServiceKey)
+		checkRegion([]string{"US", "EU"})
+	case atlas.IntegrationTypeSlack:
+		require("apiToken", spec.APIToken)
+		require("channelName", spec.ChannelName)
+		addWarning(result, basePath+".type", "type", integrationType,
+			"Slack integrations use OAuth and must first be connected in the Atlas UI; apply can only update an existing one", "SLACK_OAUTH_REQUIRED")
+	case atlas.IntegrationTypeVictorOps:
+		require("apiKey", spec.APIKey)
+		require("routingKey", spec.RoutingKey)
+	case atlas.IntegrationTypeMicrosoftTeams:
+		require("microsoftTeamsWebhookUrl", spec.MicrosoftTeamsWebhookURL)
+		checkHTTPS("microsoftTeamsWebhookUrl", spec.MicrosoftTeamsWebhookURL)
+	case atlas.IntegrationTypeWebhook:
+		require("url", spec.URL)
+		checkHTTPS("url", spec.URL)
+		if spec.Secret == "" {
+			addWarning(result, basePath+".secret", "secret", "",
+				"webhook requests are unsigned without a secret", "MISSING_WEBHOOK_SECRET")
+		}
+	case atlas.IntegrationTypePrometheus:
+		require("username", spec.Username)
+		require("password", spec.Password)
+		require("serviceDiscovery", spec.ServiceDiscovery)
+		if spec.ServiceDiscovery != "" {
+			if err := validation.ValidateEnum(spec.ServiceDiscovery, "serviceDiscovery", []string{"http", "file"}); err != nil {
+				result.AddError(basePath+".serviceDiscovery", "serviceDiscovery", spec.ServiceDiscovery,
+					err.Error(), "INVALID_SERVICE_DISCOVERY")
+			}
+		}
+	}
+}
+
+// validateFederationSettingsManifest validates an organization FederationSettings resource manifest
+func validateFederationSettingsManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.FederationSettingsSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.FederationSettingsSpec:
+		spec = s
+	case map[string]interface{}:
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid FederationSettings spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"FederationSettings spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateFederationSettingsSpec(&spec, basePath+".spec", result)
+}
+
+// validateFederationSettingsSpec validates identity providers and the connected organization
+func validateFederationSettingsSpec(spec *types.FederationSettingsSpec, basePath string, result *ValidationResult) {
+	if err := validation.ValidateOrganizationID(spec.OrgID); err != nil {
+		result.AddError(basePath+".orgId", "orgId", spec.OrgID, err.Error(), "INVALID_ORG_ID")
+	}
+
+	names := make(map[string]bool)
+	for i, idp := range spec.IdentityProviders {
+		path := fmt.Sprintf("%s.identityProviders[%d]", basePath, i)
+		if idp.DisplayName == "" {
+			result.AddError(path+".displayName", "displayName", "",
+				"identity provider displayName is required", "REQUIRED_FIELD_MISSING")
+		} else if names[idp.DisplayName] {
+			result.AddError(path+".displayName", "displayName", idp.DisplayName,
+				"identity provider displayName must be unique", "DUPLICATE_IDENTITY_PROVIDER")
+		}
+		names[idp.DisplayName] = true
+
+		if idp.IssuerURI == "" {
+			result.AddError(path+".issuerUri", "issuerUri", "",
+				"issuerUri is required", "REQUIRED_FIELD_MISSING")
+		} else if !strings.HasPrefix(idp.IssuerURI, "https://") {
+			result.AddError(path+".issuerUri", "issuerUri", idp.IssuerURI,
+				"issuerUri must be an https URL", "INVALID_ISSUER_URI")
+		}
+		if idp.Audience == "" {
+			result.AddError(path+".audience", "audience", "",
+				"audience is required", "REQUIRED_FIELD_MISSING")
+		}
+
+		idpType := strings.ToUpper(idp.IdpType)
+		switch idpType {
+		case "", "WORKFORCE":
+			if idp.ClientID == "" {
+				result.AddError(path+".clientId", "clientId", "",
+					"clientId is required for WORKFORCE identity providers", "REQUIRED_FIELD_MISSING")
+			}
+		case "WORKLOAD":
+		default:
+			result.AddError(path+".idpType", "idpType", idp.IdpType,
+				"idpType must be WORKFORCE or WORKLOAD", "INVALID_IDP_TYPE")
+		}
+
+		switch strings.ToUpper(idp.AuthorizationType) {
+		case "", "USER":
+		case "GROUP":
+			if idp.GroupsClaim == "" {
+				result.AddError(path+".groupsClaim", "groupsClaim", "",
+					"groupsClaim is required when authorizationType is GROUP", "REQUIRED_FIELD_MISSING")
+			}
+		default:
+			result.AddError(path+".authorizationType", "authorizationType", idp.AuthorizationType,
+				"authorizationType must be GROUP or USER", "INVALID_AUTHORIZATION_TYPE")
+		}
+	}
+
+	if spec.ConnectedOrg != nil {
+		for i, name := range spec.ConnectedOrg.DataAccessIdentityProviders {
+			if !names[name] {
+				addWarning(result, fmt.Sprintf("%s.connectedOrg.dataAccessIdentityProviders[%d]", basePath, i),
+					"dataAccessIdentityProviders", name,
+					"identity provider is not declared in this document and must already exist in the federation", "UNDECLARED_IDENTITY_PROVIDER")
+			}
+		}
+		if spec.ConnectedOrg.DomainRestrictionEnabled && len(spec.ConnectedOrg.DomainAllowList) == 0 {
+			result.AddError(basePath+".connectedOrg.domainAllowList", "domainAllowList", "",
+				"domainAllowList is required when domainRestrictionEnabled is true", "REQUIRED_FIELD_MISSING")
+		}
+	}
+}
+
+// validateRoleMappingManifest validates an organization RoleMapping resource manifest
+func validateRoleMappingManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.RoleMappingSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.RoleMappingSpec:
+		spec = s
+	case map[string]interface{}:
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid RoleMapping spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"RoleMapping spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateRoleMappingSpec(&spec, basePath+".spec", result)
+}
+
+// validateDataSourceManifest validates a DataSource resource manifest
+func validateDataSourceManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.DataSourceSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.DataSourceSpec:
+		spec = s
+	case map[string]interface{}:
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid DataSource spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"DataSource spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateDataSourceSpec(&spec, basePath+".spec", result)
+}
+
+// validateDataSourceSpec validates the type and name of the object a data source looks up
+func validateDataSourceSpec(spec *types.DataSourceSpec, basePath string, result *ValidationResult) {
+	switch spec.Type {
+	case types.KindProject, types.KindCluster, types.KindDatabaseUser, types.KindNetworkAccess:
+	default:
+		result.AddError(basePath+".type", "type", string(spec.Type),
+			"type must be Project, Cluster, DatabaseUser or NetworkAccess", "INVALID_DATA_SOURCE_TYPE")
+	}
+
+	if strings.TrimSpace(spec.Name) == "" {
+		result.AddError(basePath+".name", "name", "", "name is required", "REQUIRED_FIELD_MISSING")
+	}
+
+	if spec.Type == types.KindProject && spec.ProjectName != "" {
+		result.AddError(basePath+".projectName", "projectName", spec.ProjectName,
+			"projectName cannot be set for Project data sources; use name", "INVALID_DATA_SOURCE")
+	}
+	if spec.Type != types.KindDatabaseUser && spec.AuthDatabase != "" {
+		result.AddError(basePath+".authDatabase", "authDatabase", spec.AuthDatabase,
+			"authDatabase can only be set for DatabaseUser data sources", "INVALID_DATA_SOURCE")
+	}
+}
+
+// validateCollectionManifest validates a Collection resource manifest
+func validateCollectionManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.CollectionSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.CollectionSpec:
+		spec = s
+	case map[string]interface{}:
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid Collection spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"Collection spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateCollectionSpec(&spec, basePath+".spec", result)
+}
+
+// validateCollectionSpec validates the namespace of a collection and its creation options
+func validateCollectionSpec(spec *types.CollectionSpec, basePath string, result *ValidationResult) {
+	validateNamespaceFields(basePath, result, map[string]string{
+		"clusterName":  spec.ClusterName,
+		"databaseName": spec.DatabaseName,
+		"name":         spec.Name,
+	})
+
+	if err := types.ValidateCollectionOptions(&spec.CollectionOptions); err != nil {
+		result.AddError(basePath, "spec", "", err.Error(), "INVALID_COLLECTION_OPTIONS")
+	}
+}
+
+// validateIndexManifest validates an Index resource manifest
+func validateIndexManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.IndexSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.IndexSpec:
+		spec = s
+	case map[string]interface{}:
+		if keys, ok := s["keys"].(map[string]interface{}); ok && len(keys) > 1 {
+			result.AddError(basePath+".spec.keys", "keys", "",
+				"index keys must be a list to preserve their order, e.g. [\"field:1\", \"other:-1\"]", "UNORDERED_INDEX_KEYS")
+			return
+		}
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid Index spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"Index spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateIndexSpec(&spec, basePath+".spec", result)
+}
+
+// validateIndexSpec validates the namespace of an index, its key pattern and its options
+func validateIndexSpec(spec *types.IndexSpec, basePath string, result *ValidationResult) {
+	validateNamespaceFields(basePath, result, map[string]string{
+		"clusterName":    spec.ClusterName,
+		"databaseName":   spec.DatabaseName,
+		"collectionName": spec.CollectionName,
+	})
+
+	if err := types.ValidateIndex(spec.Keys, &spec.IndexOptions); err != nil {
+		result.AddError(basePath+".keys", "keys", "", err.Error(), "INVALID_INDEX")
+	}
+	if spec.IndexName() == "_id_" {
+		result.AddError(basePath+".name", "name", "_id_",
+			"the _id index is managed by MongoDB and cannot be declared", "INVALID_INDEX")
+	}
+}
+
+// validateViewManifest validates a View resource manifest
+func validateViewManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.ViewSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.ViewSpec:
+		spec = s
+	case map[string]interface{}:
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid View spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"View spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateViewSpec(&spec, basePath+".spec", result)
+}
+
+// validateViewSpec validates the namespace of a view, its source and its pipeline
+func validateViewSpec(spec *types.ViewSpec, basePath string, result *ValidationResult) {
+	validateNamespaceFields(basePath, result, map[string]string{
+		"clusterName":  spec.ClusterName,
+		"databaseName": spec.DatabaseName,
+		"name":         spec.Name,
+		"viewOn":       spec.ViewOn,
+	})
+
+	validateViewPipeline(spec.Pipeline, basePath+".pipeline", result)
+	if spec.Collation != nil && spec.Collation.Locale == "" {
+		result.AddError(basePath+".collation.locale", "locale", "",
+			"collation locale is required", "REQUIRED_FIELD_MISSING")
+	}
+}
+
+// validateMaterializedViewManifest validates a MaterializedView resource manifest
+func validateMaterializedViewManifest(manifest *types.ResourceManifest, basePath string, result *ValidationResult, opts *ValidatorOptions) {
+	var spec types.MaterializedViewSpec
+
+	switch s := manifest.Spec.(type) {
+	case types.MaterializedViewSpec:
+		spec = s
+	case map[string]interface{}:
+		if err := convertMapToStruct(s, &spec); err != nil {
+			result.AddError(basePath+".spec", "spec", "",
+				fmt.Sprintf("invalid MaterializedView spec format: %v", err), "INVALID_SPEC_FORMAT")
+			return
+		}
+	default:
+		result.AddError(basePath+".spec", "spec", "",
+			"MaterializedView spec must be a valid structure", "INVALID_SPEC_TYPE")
+		return
+	}
+
+	validateMaterializedViewSpec(&spec, basePath+".spec", result)
+}
+
+// validateMaterializedViewSpec validates the namespace of a materialized view, its source,
+// its pipeline and its $merge options
+func validateMaterializedViewSpec(spec *types.MaterializedViewSpec, basePath string, result *ValidationResult) {
+	validateNamespaceFields(basePath, result, map[string]string{
+		"clusterName":  spec.ClusterName,
+		"databaseName": spec.DatabaseName,
+		"name":         spec.Name,
+		"source":       spec.Source,
+	})
+
+	if spec.Name != "" && spec.Name == spec.Source {
+		result.AddError(basePath+".source", "source", spec.Source,
+			"a materialized view cannot merge into its own source", "INVALID_MATERIALIZED_VIEW")
+	}
+	validateViewPipeline(spec.Pipeline, basePath+".pipeline", result)
+	if err := types.ValidateMergeOptions(spec.Merge); err != nil {
+		result.AddError(basePath+".merge", "merge", "", err.Error(), "INVALID_MERGE_OPTIONS")
+	}
+}
+
+// validateViewPipeline checks that a declared pipeline can be converted to BSON and doesn't
+// write its results itself
+func validateViewPipeline(stages []interface{}, path string, result *ValidationResult) {
+	pipeline, err := database.PipelineFromSpec(stages)
+	if err == nil {
+		err = database.ValidateViewPipeline(pipeline)
+	}
+	if err != nil {
+		result.AddError(path, "pipeline", "", err.Error(), "INVALID_PIPELINE")
+	}
+}
+
+// validateNamespaceFields reports the empty fields among the cluster, database and collection names
+func validateNamespaceFields(basePath string, result *ValidationResult, fields map[string]string) {
+	for _, field := range []string{"clusterName", "databaseName", "collectionName", "name", "viewOn", "source"} {
+		value, ok := fields[field]
+		if ok && strings.TrimSpace(value) == "" {
+			result.AddError(basePath+"."+field, field, "",
+				fmt.Sprintf("%s is required", field), "REQUIRED_FIELD_MISSING")
+		}
+	}
+}
+
+// validateRoleMappingSpec validates the external group and its organization and project role assignments
+func validateRoleMappingSpec(spec *types.RoleMappingSpec, basePath string, result *ValidationResult) {
+	if err := validation.ValidateOrganizationID(spec.OrgID); err != nil {
+		result.AddError(basePath+".orgId", "orgId", spec.OrgID, err.Error(), "INVALID_ORG_ID")
+	}
+
+	if strings.TrimSpace(spec.ExternalGroupName) == "" {
+		result.AddError(basePath+".externalGroupName", "externalGroupName", "",
+			"externalGroupName is required", "REQUIRED_FIELD_MISSING")
+	}
+
+	if len(spec.RoleAssignments) == 0 {
+		result.AddError(basePath+".roleAssignments", "roleAssignments", "",
+			"at least one role assignment is required", "REQUIRED_FIELD_MISSING")
+	}
+
+	seen := make(map[string]bool)
+	for i, ra := range spec.RoleAssignments {
+		path := fmt.Sprintf("%s.roleAssignments[%d]", basePath, i)
+		switch {
+		case strings.HasPrefix(ra.Role, "ORG_"):
+			if ra.ProjectID != "" {
+				result.AddError(path+".projectId", "projectId", ra.ProjectID,
+					"organization roles (ORG_*) cannot set projectId", "INVALID_ROLE_ASSIGNMENT")
+			}
+			if ra.OrgID != "" && ra.OrgID != spec.OrgID {
+				result.AddError(path+".orgId", "orgId", ra.OrgID,
+					"organization roles can only be granted in the mapping's organization", "INVALID_ROLE_ASSIGNMENT")
+			}
+		case strings.HasPrefix(ra.Role, "GROUP_"):
+			if err := validation.ValidateProjectID(ra.ProjectID); err != nil {
+				result.AddError(path+".projectId", "projectId", ra.ProjectID,
+					"project roles (GROUP_*) require a valid projectId", "INVALID_ROLE_ASSIGNMENT")
+			}
+			if ra.OrgID != "" {
+				result.AddError(path+".orgId", "orgId", ra.OrgID,
+					"project roles (GROUP_*) cannot set orgId", "INVALID_ROLE_ASSIGNMENT")
+			}
+		default:
+			result.AddError(path+".role", "role", ra.Role,
+				"role must be an organization (ORG_*) or project (GROUP_*) role", "INVALID_ROLE")
+		}
+
+		key := ra.Role + "|" + ra.ProjectID
+		if seen[key] {
+			addWarning(result, path, "roleAssignments", ra.Role,
+				"duplicate role assignment", "DUPLICATE_ROLE_ASSIGNMENT")
+		}
+		seen[key] = true
+	}
+}
@@ -167,6 +167,205 @@ func TestValidateCollectionName(t *testing.T) {
 	}, func(val string, res *ValidationResult) { validateCollectionName(val, "test.collectionName", res) })
 }
 
+func TestValidateLDAPConfigurationSpec(t *testing.T) {
+	valid := func() types.LDAPConfigurationSpec {
+		return types.LDAPConfigurationSpec{
+			ProjectName:  "my-project",
+			Hostname:     "ldap.example.com",
+			BindUsername: "CN=svc,DC=example,DC=com",
+			BindPassword: "secret",
+			UserToDNMapping: []types.UserToDNMappingConfig{
+				{Match: "(.+)", Substitution: "CN={0},DC=example,DC=com"},
+			},
+		}
+	}
+	enabled := true
+
+	withScheme := valid()
+	withScheme.Hostname = "ldaps://ldap.example.com"
+	badPort := valid()
+	badPort.Port = 70000
+	badBind := valid()
+	badBind.BindUsername = "svc"
+	noTemplate := valid()
+	noTemplate.AuthorizationEnabled = &enabled
+	badRegex := valid()
+	badRegex.UserToDNMapping[0].Match = "(unclosed"
+	bothMappings := valid()
+	bothMappings.UserToDNMapping[0].LDAPQuery = "DC=example,DC=com??sub?(uid={0})"
+
+	assertValidationTable(t, []validationCase[types.LDAPConfigurationSpec]{
+		{name: "Valid configuration", in: valid(), wantErr: false},
+		{name: "Hostname with scheme", in: withScheme, wantErr: true, errCode: "INVALID_HOSTNAME"},
+		{name: "Port out of range", in: badPort, wantErr: true, errCode: "INVALID_PORT"},
+		{name: "Bind username not a DN", in: badBind, wantErr: true, errCode: "INVALID_BIND_USERNAME"},
+		{name: "Authorization without template", in: noTemplate, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+		{name: "Invalid match regex", in: badRegex, wantErr: true, errCode: "INVALID_REGEX"},
+		{name: "Substitution and ldapQuery", in: bothMappings, wantErr: true, errCode: "INVALID_DN_MAPPING"},
+	}, func(val types.LDAPConfigurationSpec, res *ValidationResult) {
+		validateLDAPConfigurationSpec(&val, "spec", res)
+	})
+}
+
+func TestValidateRoleMappingSpec(t *testing.T) {
+	const orgID = "5f1d7f4b3a1e2c0012345678"
+	valid := func() types.RoleMappingSpec {
+		return types.RoleMappingSpec{
+			OrgID:             orgID,
+			ExternalGroupName: "okta-dbas",
+			RoleAssignments: []types.RoleAssignmentConfig{
+				{Role: "ORG_MEMBER"},
+				{Role: "GROUP_OWNER", ProjectID: "6a1d7f4b3a1e2c0012345678"},
+			},
+		}
+	}
+
+	noGroup := valid()
+	noGroup.ExternalGroupName = ""
+	noAssignments := valid()
+	noAssignments.RoleAssignments = nil
+	projectRoleWithoutProject := valid()
+	projectRoleWithoutProject.RoleAssignments[1].ProjectID = ""
+	orgRoleWithProject := valid()
+	orgRoleWithProject.RoleAssignments[0].ProjectID = "6a1d7f4b3a1e2c0012345678"
+	unknownRole := valid()
+	unknownRole.RoleAssignments[0].Role = "readWriteAnyDatabase"
+	badOrg := valid()
+	badOrg.OrgID = "not-an-org"
+
+	assertValidationTable(t, []validationCase[types.RoleMappingSpec]{
+		{name: "Valid mapping", in: valid(), wantErr: false},
+		{name: "Missing external group", in: noGroup, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+		{name: "No role assignments", in: noAssignments, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+		{name: "Project role without projectId", in: projectRoleWithoutProject, wantErr: true, errCode: "INVALID_ROLE_ASSIGNMENT"},
+		{name: "Organization role with projectId", in: orgRoleWithProject, wantErr: true, errCode: "INVALID_ROLE_ASSIGNMENT"},
+		{name: "Unknown role", in: unknownRole, wantErr: true, errCode: "INVALID_ROLE"},
+		{name: "Invalid orgId", in: badOrg, wantErr: true, errCode: "INVALID_ORG_ID"},
+	}, func(val types.RoleMappingSpec, res *ValidationResult) {
+		validateRoleMappingSpec(&val, "spec", res)
+	})
+}
+
+func TestValidateDataSourceSpec(t *testing.T) {
+	cluster := types.DataSourceSpec{Type: types.KindCluster, Name: "analytics", ProjectName: "shared-prod"}
+	user := types.DataSourceSpec{Type: types.KindDatabaseUser, Name: "etl", AuthDatabase: "$external"}
+
+	noName := cluster
+	noName.Name = ""
+	unknownType := cluster
+	unknownType.Type = types.KindSearchIndex
+	projectWithProject := types.DataSourceSpec{Type: types.KindProject, Name: "shared-prod", ProjectName: "other"}
+	clusterAuthDatabase := cluster
+	clusterAuthDatabase.AuthDatabase = "admin"
+
+	assertValidationTable(t, []validationCase[types.DataSourceSpec]{
+		{name: "Valid cluster", in: cluster, wantErr: false},
+		{name: "Valid user", in: user, wantErr: false},
+		{name: "Valid project", in: types.DataSourceSpec{Type: types.KindProject, Name: "shared-prod"}, wantErr: false},
+		{name: "Missing name", in: noName, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+		{name: "Unsupported type", in: unknownType, wantErr: true, errCode: "INVALID_DATA_SOURCE_TYPE"},
+		{name: "Project with projectName", in: projectWithProject, wantErr: true, errCode: "INVALID_DATA_SOURCE"},
+		{name: "authDatabase on a cluster", in: clusterAuthDatabase, wantErr: true, errCode: "INVALID_DATA_SOURCE"},
+	}, func(val types.DataSourceSpec, res *ValidationResult) {
+		validateDataSourceSpec(&val, "spec", res)
+	})
+}
+
+func TestValidateIntegrationSpec(t *testing.T) {
+	datadog := types.IntegrationSpec{ProjectName: "my-project", Type: "DATADOG", APIKey: "0123456789abcdef", Region: "EU"}
+	pagerDuty := types.IntegrationSpec{ProjectName: "my-project", Type: "pager-duty", ServiceKey: "svc-key"}
+	prometheus := types.IntegrationSpec{ProjectName: "my-project", Type: "PROMETHEUS", Username: "prom", Password: "secret", ServiceDiscovery: "http"}
+	webhook := types.IntegrationSpec{ProjectName: "my-project", Type: "WEBHOOK", URL: "https://hooks.example.com/atlas", Secret: "s3cret"}
+
+	noProject := datadog
+	noProject.ProjectName = ""
+	noKey := datadog
+	noKey.APIKey = ""
+	badRegion := datadog
+	badRegion.Region = "MARS"
+	unknownType := datadog
+	unknownType.Type = "HIPCHAT"
+	badDiscovery := prometheus
+	badDiscovery.ServiceDiscovery = "dns"
+	httpWebhook := webhook
+	httpWebhook.URL = "http://hooks.example.com/atlas"
+	teamsNoURL := types.IntegrationSpec{ProjectName: "my-project", Type: "MICROSOFT_TEAMS"}
+
+	assertValidationTable(t, []validationCase[types.IntegrationSpec]{
+		{name: "Valid Datadog", in: datadog, wantErr: false},
+		{name: "Valid PagerDuty with dashed type", in: pagerDuty, wantErr: false},
+		{name: "Valid Prometheus", in: prometheus, wantErr: false},
+		{name: "Valid webhook", in: webhook, wantErr: false},
+		{name: "Missing project", in: noProject, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+		{name: "Missing API key", in: noKey, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+		{name: "Invalid region", in: badRegion, wantErr: true, errCode: "INVALID_REGION"},
+		{name: "Unknown type", in: unknownType, wantErr: true, errCode: "INVALID_INTEGRATION_TYPE"},
+		{name: "Invalid service discovery", in: badDiscovery, wantErr: true, errCode: "INVALID_SERVICE_DISCOVERY"},
+		{name: "Plain HTTP webhook", in: httpWebhook, wantErr: true, errCode: "INVALID_URL"},
+		{name: "Teams without webhook URL", in: teamsNoURL, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+	}, func(val types.IntegrationSpec, res *ValidationResult) {
+		validateIntegrationSpec(&val, "spec", res)
+	})
+}
+
+func TestValidateFederationSettingsSpec(t *testing.T) {
+	valid := func() types.FederationSettingsSpec {
+		return types.FederationSettingsSpec{
+			OrgID: "5f1d7f4b3a1e2c0012345678",
+			IdentityProviders: []types.IdentityProviderConfig{{
+				DisplayName:       "Okta Workforce",
+				IssuerURI:         "https://example.okta.com/oauth2/default",
+				Audience:          "api://atlas",
+				ClientID:          "0oa1b2c3d4",
+				AuthorizationType: "GROUP",
+				GroupsClaim:       "groups",
+			}},
+		}
+	}
+
+	httpIssuer := valid()
+	httpIssuer.IdentityProviders[0].IssuerURI = "http://example.okta.com"
+	noClientID := valid()
+	noClientID.IdentityProviders[0].ClientID = ""
+	noGroupsClaim := valid()
+	noGroupsClaim.IdentityProviders[0].GroupsClaim = ""
+	domainRestriction := valid()
+	domainRestriction.ConnectedOrg = &types.ConnectedOrgSettings{DomainRestrictionEnabled: true}
+
+	assertValidationTable(t, []validationCase[types.FederationSettingsSpec]{
+		{name: "Valid settings", in: valid(), wantErr: false},
+		{name: "Issuer not https", in: httpIssuer, wantErr: true, errCode: "INVALID_ISSUER_URI"},
+		{name: "Workforce without clientId", in: noClientID, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+		{name: "Group authorization without claim", in: noGroupsClaim, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+		{name: "Domain restriction without allow list", in: domainRestriction, wantErr: true, errCode: "REQUIRED_FIELD_MISSING"},
+	}, func(val types.FederationSettingsSpec, res *ValidationResult) {
+		validateFederationSettingsSpec(&val, "spec", res)
+	})
+}
+
+func TestValidateNetworkAccessConfig_Expiry(t *testing.T) {
+	entry := func(deleteAfterDate, expiresAt, ttl string) types.NetworkAccessConfig {
+		return types.NetworkAccessConfig{
+			IPAddress:       "203.0.113.10",
+			DeleteAfterDate: deleteAfterDate,
+			ExpiresAt:       expiresAt,
+			TTL:             ttl,
+		}
+	}
+
+	assertValidationTable(t, []validationCase[types.NetworkAccessConfig]{
+		{name: "Permanent entry", in: entry("", "", ""), wantErr: false},
+		{name: "Valid ttl", in: entry("", "", "8h"), wantErr: false},
+		{name: "Valid expiresAt", in: entry("", "2026-10-19T00:00:00Z", ""), wantErr: false},
+		{name: "Invalid expiresAt", in: entry("", "tomorrow", ""), wantErr: true, errCode: "INVALID_DATE_FORMAT"},
+		{name: "Invalid ttl", in: entry("", "", "forever"), wantErr: true, errCode: "INVALID_TTL"},
+		{name: "Ttl above one week", in: entry("", "", "8d"), wantErr: true, errCode: "INVALID_TTL"},
+		{name: "Ttl with deleteAfterDate", in: entry("2026-10-19T00:00:00Z", "", "8h"), wantErr: true, errCode: "CONFLICTING_EXPIRY"},
+	}, func(val types.NetworkAccessConfig, res *ValidationResult) {
+		validateNetworkAccessConfig(&val, "spec", res, DefaultValidatorOptions())
+	})
+}
+
 // Generic validation helpers to reduce duplication
 type validationCase[T any] struct {
 	name    string
@@ -0,0 +1,66 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// variableName matches the names ${VAR} templates can substitute
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LoadVariableFiles reads template variables from YAML or JSON files holding a mapping of
+// names to scalar values. Later files override earlier ones.
+func LoadVariableFiles(paths []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, path := range paths {
+		data, err := os.ReadFile(path) // #nosec G304 -- variable files are supplied by the user
+		if err != nil {
+			return nil, fmt.Errorf("failed to read variable file: %w", err)
+		}
+		var values map[string]interface{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse variable file %s: %w", path, err)
+		}
+		for name, value := range values {
+			if err := checkVariableName(name); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				return nil, fmt.Errorf("%s: variable %s must be a string, number or bool", path, name)
+			case nil:
+				vars[name] = ""
+			default:
+				vars[name] = fmt.Sprint(value)
+			}
+		}
+	}
+	return vars, nil
+}
+
+// ParseVariableAssignments parses NAME=VALUE assignments from the command line
+func ParseVariableAssignments(assignments []string) (map[string]string, error) {
+	vars := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		name, value, ok := strings.Cut(assignment, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid variable %q: expected NAME=VALUE", assignment)
+		}
+		if err := checkVariableName(name); err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+func checkVariableName(name string) error {
+	if !variableName.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q: use letters, digits and underscores, not starting with a digit", name)
+	}
+	return nil
+}
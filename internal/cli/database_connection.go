@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/logging"
+	"github.com/teabranch/matlas-cli/internal/output"
+	atlasservice "github.com/teabranch/matlas-cli/internal/services/atlas"
+	"github.com/teabranch/matlas-cli/internal/services/database"
+	"github.com/teabranch/matlas-cli/internal/types"
+	"github.com/teabranch/matlas-cli/internal/ui"
+	"github.com/teabranch/matlas-cli/internal/validation"
+)
+
+// tempUserCleanupTimeout bounds the removal of a temporary user once a command is done
+const tempUserCleanupTimeout = 30 * time.Second
+
+// DatabaseConnectionFlags holds the flags selecting the deployment a database command
+// connects to: a connection string, or an Atlas cluster in a project
+type DatabaseConnectionFlags struct {
+	ConnectionString string
+	ClusterName      string
+	ProjectID        string
+	UseTempUser      bool
+}
+
+// AddDatabaseConnectionFlags adds the standard connection flags to a command. Either
+// --connection-string or --cluster with --project-id is required.
+func AddDatabaseConnectionFlags(cmd *cobra.Command, flags *DatabaseConnectionFlags) {
+	cmd.Flags().StringVar(&flags.ConnectionString, "connection-string", "", "MongoDB connection string")
+	cmd.Flags().StringVar(&flags.ClusterName, "cluster", "", "Atlas cluster name (requires --project-id)")
+	cmd.Flags().StringVar(&flags.ProjectID, "project-id", "", "Atlas project ID (used with --cluster)")
+	cmd.Flags().BoolVar(&flags.UseTempUser, "use-temp-user", false, "Create a temporary database user on the cluster for access (with --cluster)")
+
+	cmd.MarkFlagsOneRequired("connection-string", "cluster")
+	cmd.MarkFlagsRequiredTogether("cluster", "project-id")
+}
+
+// MustMarkFlagRequired marks a flag as required and panics if the flag does not exist
+func MustMarkFlagRequired(cmd *cobra.Command, name string) {
+	if err := cmd.MarkFlagRequired(name); err != nil {
+		panic(fmt.Errorf("failed to mark flag %q required: %w", name, err))
+	}
+}
+
+// Connect resolves the connection to the selected deployment. The connection string of a
+// cluster is looked up in Atlas; with UseTempUser a temporary user with read and write
+// access is created on the cluster instead, and the returned cleanup removes it.
+func (f *DatabaseConnectionFlags) Connect(ctx context.Context, cfg *config.Config, dbService *database.Service, progress *ui.ProgressIndicator) (*types.ConnectionInfo, func(), error) {
+	noop := func() {}
+	if f.ConnectionString != "" {
+		return &types.ConnectionInfo{ConnectionString: f.ConnectionString}, noop, nil
+	}
+
+	if err := validation.ValidateProjectID(f.ProjectID); err != nil {
+		return nil, noop, FormatValidationError("project-id", f.ProjectID, err.Error())
+	}
+	if err := validation.ValidateClusterName(f.ClusterName); err != nil {
+		return nil, noop, FormatValidationError("cluster", f.ClusterName, err.Error())
+	}
+
+	client, err := cfg.CreateAtlasClient()
+	if err != nil {
+		return nil, noop, WrapWithSuggestion(err, "Check your API key and public key configuration")
+	}
+	clusters := atlasservice.NewClustersService(client)
+
+	if !f.UseTempUser {
+		progress.StartSpinner(fmt.Sprintf("Resolving connection string for cluster '%s'...", f.ClusterName))
+		cluster, err := clusters.Get(ctx, f.ProjectID, f.ClusterName)
+		if err != nil {
+			progress.StopSpinnerWithError("Failed to get cluster details")
+			return nil, noop, fmt.Errorf("%s", NewErrorFormatter(true).Format(err))
+		}
+		if cluster.ConnectionStrings == nil || cluster.ConnectionStrings.StandardSrv == nil {
+			progress.StopSpinnerWithError("No connection string available")
+			return nil, noop, fmt.Errorf("cluster '%s' does not have a connection string available", f.ClusterName)
+		}
+		progress.StopSpinner("Connection string resolved")
+		return &types.ConnectionInfo{ConnectionString: *cluster.ConnectionStrings.StandardSrv}, noop, nil
+	}
+
+	connector := database.NewClusterConnector(dbService, clusters, atlasservice.NewDatabaseUsersService(client), f.ProjectID)
+	cleanup := func() {
+		// Use a fresh context so the temporary user is removed even after cancellation
+		ctx, cancel := context.WithTimeout(context.Background(), tempUserCleanupTimeout)
+		defer cancel()
+		if err := connector.Close(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	progress.StartSpinner(fmt.Sprintf("Creating temporary database user on cluster '%s'...", f.ClusterName))
+	connInfo, err := connector.Connect(ctx, f.ClusterName)
+	if err != nil {
+		progress.StopSpinnerWithError("Failed to connect with a temporary user")
+		cleanup()
+		return nil, noop, err
+	}
+	progress.StopSpinner(fmt.Sprintf("Connected as temporary user '%s'", connInfo.TempUser.Username))
+	return connInfo, cleanup, nil
+}
+
+// DatabaseSession is an open connection of a database command
+type DatabaseSession struct {
+	Config    *config.Config
+	Context   context.Context
+	ConnInfo  *types.ConnectionInfo
+	Service   *database.Service
+	Logger    *logging.Logger
+	Progress  *ui.ProgressIndicator
+	Errors    *ErrorFormatter
+	Formatter *output.Formatter
+}
+
+// Fail stops the spinner and formats an error
+func (s *DatabaseSession) Fail(message string, err error) error {
+	s.Progress.StopSpinnerWithError(message)
+	return fmt.Errorf("%s", s.Errors.Format(err))
+}
+
+// WithSession loads the configuration, connects and runs fn within the configured timeout,
+// closing the connection and removing any temporary user afterwards
+func (f *DatabaseConnectionFlags) WithSession(cmd *cobra.Command, fn func(s *DatabaseSession) error) error {
+	return f.withSession(cmd, true, fn)
+}
+
+// WithUnboundedSession is WithSession for long-running commands, which are not bound by
+// the configured timeout
+func (f *DatabaseConnectionFlags) WithUnboundedSession(cmd *cobra.Command, fn func(s *DatabaseSession) error) error {
+	return f.withSession(cmd, false, fn)
+}
+
+func (f *DatabaseConnectionFlags) withSession(cmd *cobra.Command, bounded bool, fn func(s *DatabaseSession) error) error {
+	cfg, err := config.Load(cmd, "")
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if bounded {
+		ctx, cancel = context.WithTimeout(cmd.Context(), cfg.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(cmd.Context())
+	}
+	defer cancel()
+
+	verbose := cmd.Flag("verbose").Changed
+	progress := ui.NewProgressIndicator(verbose, false)
+
+	logger := logging.Default()
+	dbService := database.NewService(logger)
+	defer func() {
+		if err := dbService.Close(ctx); err != nil {
+			fmt.Printf("Warning: Failed to close database service: %v\n", err)
+		}
+	}()
+
+	connInfo, cleanup, err := f.Connect(ctx, cfg, dbService, progress)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return fn(&DatabaseSession{
+		Config:    cfg,
+		Context:   ctx,
+		ConnInfo:  connInfo,
+		Service:   dbService,
+		Logger:    logger,
+		Progress:  progress,
+		Errors:    NewErrorFormatter(verbose),
+		Formatter: output.NewFormatter(cfg.Output, os.Stdout),
+	})
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teabranch/matlas-cli/internal/config"
+)
+
+func TestAddDatabaseConnectionFlags(t *testing.T) {
+	newCmd := func(flags *DatabaseConnectionFlags) *cobra.Command {
+		cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+		AddDatabaseConnectionFlags(cmd, flags)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		return cmd
+	}
+
+	flags := &DatabaseConnectionFlags{}
+	cmd := newCmd(flags)
+	cmd.SetArgs([]string{"--cluster", "app", "--project-id", "507f1f77bcf86cd799439011", "--use-temp-user"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, DatabaseConnectionFlags{ClusterName: "app", ProjectID: "507f1f77bcf86cd799439011", UseTempUser: true}, *flags)
+
+	cmd = newCmd(&DatabaseConnectionFlags{})
+	cmd.SetArgs([]string{})
+	assert.Error(t, cmd.Execute(), "a connection string or cluster is required")
+
+	cmd = newCmd(&DatabaseConnectionFlags{})
+	cmd.SetArgs([]string{"--cluster", "app"})
+	assert.Error(t, cmd.Execute(), "--cluster requires --project-id")
+}
+
+func TestDatabaseConnectionFlags_Connect(t *testing.T) {
+	flags := &DatabaseConnectionFlags{ConnectionString: "mongodb://localhost:27017"}
+	connInfo, cleanup, err := flags.Connect(context.Background(), &config.Config{}, nil, nil)
+	require.NoError(t, err)
+	cleanup()
+	assert.Equal(t, "mongodb://localhost:27017", connInfo.ConnectionString)
+
+	flags = &DatabaseConnectionFlags{ClusterName: "app", ProjectID: "not-a-project"}
+	_, cleanup, err = flags.Connect(context.Background(), &config.Config{}, nil, nil)
+	require.Error(t, err)
+	cleanup()
+	assert.Contains(t, err.Error(), "project-id")
+}
+
+func TestMustMarkFlagRequired(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("database", "", "")
+	MustMarkFlagRequired(cmd, "database")
+	assert.Equal(t, []string{"true"}, cmd.Flags().Lookup("database").Annotations[cobra.BashCompOneRequiredFlag])
+
+	assert.Panics(t, func() { MustMarkFlagRequired(cmd, "missing") })
+}
@@ -18,6 +18,8 @@ type SignalHandler struct {
 	cancel         context.CancelFunc
 	logger         *logging.Logger
 	cleanupFuncs   []CleanupFunc
+	scopedCleanups map[int]CleanupFunc
+	nextScopedID   int
 	timeoutSeconds int
 	mu             sync.RWMutex
 	shutdownOnce   sync.Once
@@ -54,6 +56,7 @@ func NewSignalHandler(logger *logging.Logger, timeoutSeconds int) *SignalHandler
 		cancel:         cancel,
 		logger:         logger,
 		cleanupFuncs:   make([]CleanupFunc, 0),
+		scopedCleanups: make(map[int]CleanupFunc),
 		timeoutSeconds: timeoutSeconds,
 	}
 }
@@ -70,6 +73,22 @@ func (sh *SignalHandler) RegisterCleanup(fn CleanupFunc) {
 	sh.cleanupFuncs = append(sh.cleanupFuncs, fn)
 }
 
+// RegisterScopedCleanup registers a cleanup function that only applies while an operation is
+// running, such as aborting a server-side task the operation started. Call the returned
+// function once the operation has finished to unregister it.
+func (sh *SignalHandler) RegisterScopedCleanup(fn CleanupFunc) (unregister func()) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	id := sh.nextScopedID
+	sh.nextScopedID++
+	sh.scopedCleanups[id] = fn
+	return func() {
+		sh.mu.Lock()
+		defer sh.mu.Unlock()
+		delete(sh.scopedCleanups, id)
+	}
+}
+
 // RegisterCleanupWithConfig registers a cleanup function with specific configuration
 func (sh *SignalHandler) RegisterCleanupWithConfig(fn CleanupFunc, config CleanupConfig) {
 	// For now, we'll just use the simple registration
@@ -128,24 +147,30 @@ func (sh *SignalHandler) handleSignal(sig os.Signal) {
 
 // performCleanup executes all registered cleanup functions
 func (sh *SignalHandler) performCleanup() {
-	if len(sh.cleanupFuncs) == 0 {
+	sh.mu.RLock()
+	cleanupFuncs := append([]CleanupFunc{}, sh.cleanupFuncs...)
+	for _, fn := range sh.scopedCleanups {
+		cleanupFuncs = append(cleanupFuncs, fn)
+	}
+	sh.mu.RUnlock()
+
+	if len(cleanupFuncs) == 0 {
 		sh.logger.Debug("No cleanup functions registered")
 		return
 	}
 
-	sh.logger.Info("Starting cleanup process", "cleanup_functions", len(sh.cleanupFuncs))
-	fmt.Fprintf(os.Stderr, "🧹 Running cleanup... (%d operations)\n", len(sh.cleanupFuncs))
+	sh.logger.Info("Starting cleanup process", "cleanup_functions", len(cleanupFuncs))
+	fmt.Fprintf(os.Stderr, "🧹 Running cleanup... (%d operations)\n", len(cleanupFuncs))
 
 	// Create cleanup context with timeout
 	cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), time.Duration(sh.timeoutSeconds)*time.Second)
 	defer cleanupCancel()
 
 	var wg sync.WaitGroup
-	errorChan := make(chan error, len(sh.cleanupFuncs))
+	errorChan := make(chan error, len(cleanupFuncs))
 
 	// Execute cleanup functions
-	sh.mu.RLock()
-	for i, fn := range sh.cleanupFuncs {
+	for i, fn := range cleanupFuncs {
 		wg.Add(1)
 		go func(index int, cleanupFn CleanupFunc) {
 			defer wg.Done()
@@ -161,7 +186,6 @@ func (sh *SignalHandler) performCleanup() {
 			}
 		}(i, fn)
 	}
-	sh.mu.RUnlock()
 
 	// Wait for all cleanup functions to complete or timeout
 	done := make(chan struct{})
@@ -212,6 +236,25 @@ func (sh *SignalHandler) Shutdown() {
 	sh.cancel()
 }
 
+var (
+	defaultHandlerMu sync.RWMutex
+	defaultHandler   *SignalHandler
+)
+
+// SetDefaultSignalHandler sets the handler commands register their cleanup functions with
+func SetDefaultSignalHandler(handler *SignalHandler) {
+	defaultHandlerMu.Lock()
+	defer defaultHandlerMu.Unlock()
+	defaultHandler = handler
+}
+
+// DefaultSignalHandler returns the handler set by SetDefaultSignalHandler, or nil
+func DefaultSignalHandler() *SignalHandler {
+	defaultHandlerMu.RLock()
+	defer defaultHandlerMu.RUnlock()
+	return defaultHandler
+}
+
 // WithSignalHandler creates a signal handler and runs the provided function
 func WithSignalHandler(logger *logging.Logger, timeoutSeconds int, fn func(*SignalHandler) error) error {
 	handler := NewSignalHandler(logger, timeoutSeconds)
@@ -95,6 +95,24 @@ func TestSignalHandler_RegisterCleanup(t *testing.T) {
 	handler.mu.RUnlock()
 }
 
+func TestSignalHandler_RegisterScopedCleanup(t *testing.T) {
+	logger := logging.New(nil)
+	handler := NewSignalHandler(logger, 30)
+
+	var called int32
+	unregister := handler.RegisterScopedCleanup(func(ctx context.Context) error {
+		atomic.AddInt32(&called, 1)
+		return nil
+	})
+	handler.performCleanup()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&called))
+
+	// Once the operation has finished the cleanup no longer runs
+	unregister()
+	handler.performCleanup()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&called))
+}
+
 func TestSignalHandler_RegisterCleanupWithConfig(t *testing.T) {
 	logger := logging.New(nil)
 	handler := NewSignalHandler(logger, 30)
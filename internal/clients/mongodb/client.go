@@ -7,6 +7,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -255,6 +257,91 @@ func (c *Client) CreateCollection(ctx context.Context, dbName, collectionName st
 	return nil
 }
 
+// CreateView creates a read-only view that runs pipeline on viewOn
+func (c *Client) CreateView(ctx context.Context, dbName, viewName, viewOn string, pipeline bson.A, collation *types.IndexCollation) error {
+	opts := options.CreateView()
+	if collation != nil {
+		opts.SetCollation(collationOptions(collation))
+	}
+	if err := c.client.Database(dbName).CreateView(ctx, viewName, viewOn, pipeline, opts); err != nil {
+		return fmt.Errorf("failed to create view %q in database %q: %w", viewName, dbName, err)
+	}
+
+	c.logger.Info("Created view",
+		"database", dbName,
+		"view", viewName,
+		"viewOn", viewOn)
+
+	return nil
+}
+
+// CreateCollectionOptions converts collection options to driver options
+func CreateCollectionOptions(opts *types.CollectionOptions) *options.CreateCollectionOptions {
+	if opts == nil {
+		return nil
+	}
+	createOpts := options.CreateCollection()
+	if opts.Capped {
+		createOpts.SetCapped(true).SetSizeInBytes(opts.Size)
+		if opts.Max > 0 {
+			createOpts.SetMaxDocuments(opts.Max)
+		}
+	}
+	if opts.Validator != nil {
+		createOpts.SetValidator(opts.Validator)
+	}
+	if opts.ValidationLevel != "" {
+		createOpts.SetValidationLevel(opts.ValidationLevel)
+	}
+	if opts.ValidationAction != "" {
+		createOpts.SetValidationAction(opts.ValidationAction)
+	}
+	if ts := opts.TimeSeries; ts != nil {
+		tsOpts := options.TimeSeries().SetTimeField(ts.TimeField)
+		if ts.MetaField != "" {
+			tsOpts.SetMetaField(ts.MetaField)
+		}
+		if ts.Granularity != "" {
+			tsOpts.SetGranularity(ts.Granularity)
+		}
+		if ts.BucketMaxSpanSeconds != nil {
+			tsOpts.SetBucketMaxSpan(time.Duration(*ts.BucketMaxSpanSeconds) * time.Second)
+		}
+		if ts.BucketRoundingSeconds != nil {
+			tsOpts.SetBucketRounding(time.Duration(*ts.BucketRoundingSeconds) * time.Second)
+		}
+		createOpts.SetTimeSeriesOptions(tsOpts)
+	}
+	if ci := opts.ClusteredIndex; ci != nil {
+		clustered := bson.D{{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}}, {Key: "unique", Value: true}}
+		if ci.Name != "" {
+			clustered = append(clustered, bson.E{Key: "name", Value: ci.Name})
+		}
+		createOpts.SetClusteredIndex(clustered)
+	}
+	if opts.ExpireAfterSeconds != nil {
+		createOpts.SetExpireAfterSeconds(*opts.ExpireAfterSeconds)
+	}
+	if opts.Collation != nil {
+		createOpts.SetCollation(collationOptions(opts.Collation))
+	}
+	return createOpts
+}
+
+// ModifyCollection runs collMod on a collection with the given changes
+func (c *Client) ModifyCollection(ctx context.Context, dbName, collectionName string, changes bson.D) error {
+	cmd := append(bson.D{{Key: "collMod", Value: collectionName}}, changes...)
+	if err := c.client.Database(dbName).RunCommand(ctx, cmd).Err(); err != nil {
+		return fmt.Errorf("failed to modify collection %q in database %q: %w", collectionName, dbName, err)
+	}
+
+	c.logger.Info("Modified collection",
+		"database", dbName,
+		"collection", collectionName)
+
+	return nil
+}
+
 // DropCollection drops a collection from the specified database
 func (c *Client) DropCollection(ctx context.Context, dbName, collectionName string) error {
 	db := c.client.Database(dbName)
@@ -316,9 +403,24 @@ func (c *Client) GetCollectionStats(ctx context.Context, dbName, collectionName
 		}
 	}
 
+	if timeseries, ok := result["timeseries"].(bson.M); ok {
+		stats.TimeSeries = decodeTimeSeriesStats(timeseries)
+	}
+
 	return stats, nil
 }
 
+// decodeTimeSeriesStats reads the timeseries section collStats reports for a time series
+// collection
+func decodeTimeSeriesStats(section bson.M) *types.TimeSeriesStats {
+	stats := &types.TimeSeriesStats{}
+	stats.BucketsNamespace, _ = section["bucketsNs"].(string)
+	stats.BucketCount, _ = toInt64(section["bucketCount"])
+	stats.AvgBucketSize, _ = toInt64(section["avgBucketSize"])
+	stats.MeasurementsCommitted, _ = toInt64(section["numMeasurementsCommitted"])
+	return stats
+}
+
 // Ping tests the connection to MongoDB
 func (c *Client) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx, readpref.Primary())
@@ -334,43 +436,17 @@ func (c *Client) DropDatabase(ctx context.Context, dbName string) error {
 	return nil
 }
 
-// CreateIndex creates an index on a collection
-func (c *Client) CreateIndex(ctx context.Context, dbName, collectionName string, keys map[string]int, opts map[string]interface{}) (string, error) {
+// CreateIndex creates an index on a collection. Keys are applied in order.
+func (c *Client) CreateIndex(ctx context.Context, dbName, collectionName string, keys []types.IndexKey, opts *types.IndexOptions) (string, error) {
 	db := c.client.Database(dbName)
 	collection := db.Collection(collectionName)
 
-	// Convert keys map to IndexModel
-	indexKeys := bson.D{}
-	for field, order := range keys {
-		indexKeys = append(indexKeys, bson.E{Key: field, Value: order})
-	}
-
 	indexModel := mongo.IndexModel{
-		Keys: indexKeys,
-	}
-
-	// Apply options if provided
-	if opts != nil {
-		indexOptions := options.Index()
-
-		if unique, ok := opts["unique"].(bool); ok {
-			indexOptions.SetUnique(unique)
-		}
-		if sparse, ok := opts["sparse"].(bool); ok {
-			indexOptions.SetSparse(sparse)
-		}
-		if background, ok := opts["background"].(bool); ok {
-			// Background option deprecated since MongoDB 4.2; kept for backward compat.
-			indexOptions.SetBackground(background) //nolint:staticcheck
-		}
-		if name, ok := opts["name"].(string); ok {
-			indexOptions.SetName(name)
-		}
-
-		indexModel.Options = indexOptions
+		Keys:    indexKeysDocument(keys),
+		Options: indexOptions(opts),
 	}
 
-	indexName, err := collection.Indexes().CreateOne(ctx, indexModel)
+	indexName, err := collection.Indexes().CreateOne(ctx, indexModel, createIndexesOptions(opts))
 	if err != nil {
 		return "", fmt.Errorf("failed to create index on collection %q in database %q: %w", collectionName, dbName, err)
 	}
@@ -383,6 +459,79 @@ func (c *Client) CreateIndex(ctx context.Context, dbName, collectionName string,
 	return indexName, nil
 }
 
+// indexKeysDocument converts index keys into an ordered key pattern
+func indexKeysDocument(keys []types.IndexKey) bson.D {
+	doc := make(bson.D, 0, len(keys))
+	for _, key := range keys {
+		doc = append(doc, bson.E{Key: key.Field, Value: key.Value()})
+	}
+	return doc
+}
+
+// indexOptions converts index options into driver options
+func indexOptions(opts *types.IndexOptions) *options.IndexOptions {
+	indexOptions := options.Index()
+	if opts == nil {
+		return indexOptions
+	}
+
+	if opts.Name != "" {
+		indexOptions.SetName(opts.Name)
+	}
+	if opts.Unique {
+		indexOptions.SetUnique(true)
+	}
+	if opts.Sparse {
+		indexOptions.SetSparse(true)
+	}
+	if opts.Background {
+		// Background option deprecated since MongoDB 4.2; kept for backward compat.
+		indexOptions.SetBackground(true) //nolint:staticcheck
+	}
+	if opts.Hidden {
+		indexOptions.SetHidden(true)
+	}
+	if opts.PartialFilterExpression != nil {
+		indexOptions.SetPartialFilterExpression(opts.PartialFilterExpression)
+	}
+	if opts.ExpireAfterSeconds != nil {
+		indexOptions.SetExpireAfterSeconds(*opts.ExpireAfterSeconds)
+	}
+	if opts.WildcardProjection != nil {
+		indexOptions.SetWildcardProjection(opts.WildcardProjection)
+	}
+	if opts.Collation != nil {
+		indexOptions.SetCollation(collationOptions(opts.Collation))
+	}
+	return indexOptions
+}
+
+// createIndexesOptions converts the build options of an index into createIndexes options
+func createIndexesOptions(opts *types.IndexOptions) *options.CreateIndexesOptions {
+	createOptions := options.CreateIndexes()
+	if opts == nil || opts.CommitQuorum == "" {
+		return createOptions
+	}
+	if members, err := strconv.ParseInt(opts.CommitQuorum, 10, 32); err == nil {
+		return createOptions.SetCommitQuorumInt(int32(members))
+	}
+	return createOptions.SetCommitQuorumString(opts.CommitQuorum)
+}
+
+// collationOptions converts a collation to driver options
+func collationOptions(collation *types.IndexCollation) *options.Collation {
+	return &options.Collation{
+		Locale:          collation.Locale,
+		CaseLevel:       collation.CaseLevel,
+		CaseFirst:       collation.CaseFirst,
+		Strength:        collation.Strength,
+		NumericOrdering: collation.NumericOrdering,
+		Alternate:       collation.Alternate,
+		MaxVariable:     collation.MaxVariable,
+		Backwards:       collation.Backwards,
+	}
+}
+
 // DropIndex drops an index from a collection
 func (c *Client) DropIndex(ctx context.Context, dbName, collectionName, indexName string) error {
 	db := c.client.Database(dbName)
@@ -400,63 +549,216 @@ func (c *Client) DropIndex(ctx context.Context, dbName, collectionName, indexNam
 	return nil
 }
 
-// ListIndexes lists all indexes for a collection
-func (c *Client) ListIndexes(ctx context.Context, dbName, collectionName string) ([]types.IndexInfo, error) {
-	db := c.client.Database(dbName)
-	collection := db.Collection(collectionName)
+// decodeIndexInfo decodes an index specification returned by listIndexes. The key pattern
+// is decoded separately to keep its field order.
+func decodeIndexInfo(raw bson.Raw) (types.IndexInfo, error) {
+	var indexBson bson.M
+	if err := bson.Unmarshal(raw, &indexBson); err != nil {
+		return types.IndexInfo{}, err
+	}
+	var spec struct {
+		Key bson.D `bson:"key"`
+	}
+	if err := bson.Unmarshal(raw, &spec); err != nil {
+		return types.IndexInfo{}, err
+	}
+
+	index := types.IndexInfo{
+		Options: make(map[string]interface{}),
+	}
+	for _, e := range spec.Key {
+		index.Keys = append(index.Keys, types.IndexKeyFromValue(e.Key, e.Value))
+	}
+
+	for k, v := range indexBson {
+		switch k {
+		case "key":
+		case "name":
+			index.Name, _ = v.(string)
+		case "unique":
+			index.Unique, _ = v.(bool)
+		case "sparse":
+			index.Sparse, _ = v.(bool)
+		case "background":
+			index.Background, _ = v.(bool)
+		case "hidden":
+			index.Hidden, _ = v.(bool)
+		case "v":
+			if version, ok := v.(int32); ok {
+				index.Version = int(version)
+			}
+		case "expireAfterSeconds":
+			if seconds, ok := toInt32(v); ok {
+				index.ExpireAfterSeconds = &seconds
+			}
+		case "partialFilterExpression":
+			index.PartialFilterExpression = documentMap(v)
+		case "wildcardProjection":
+			index.WildcardProjection = documentMap(v)
+		case "collation":
+			var collation struct {
+				Locale          string `bson:"locale"`
+				CaseLevel       bool   `bson:"caseLevel"`
+				CaseFirst       string `bson:"caseFirst"`
+				Strength        int    `bson:"strength"`
+				NumericOrdering bool   `bson:"numericOrdering"`
+				Alternate       string `bson:"alternate"`
+				MaxVariable     string `bson:"maxVariable"`
+				Backwards       bool   `bson:"backwards"`
+			}
+			if data, err := bson.Marshal(v); err == nil && bson.Unmarshal(data, &collation) == nil {
+				indexCollation := types.IndexCollation(collation)
+				index.Collation = &indexCollation
+			}
+		default:
+			// Copy other options
+			index.Options[k] = v
+		}
+	}
+	return index, nil
+}
 
-	cursor, err := collection.Indexes().List(ctx)
+func toInt32(v interface{}) (int32, bool) {
+	switch n := v.(type) {
+	case int32:
+		return n, true
+	case int64:
+		return int32(n), true // #nosec G115 -- TTLs fit in int32
+	case float64:
+		return int32(n), true
+	default:
+		return 0, false
+	}
+}
+
+func documentMap(v interface{}) map[string]interface{} {
+	if m, ok := v.(bson.M); ok {
+		return m
+	}
+	return nil
+}
+
+// CurrentIndexBuilds lists the index builds in progress on the deployment, optionally only
+// those of a namespace (database or database.collection)
+func (c *Client) CurrentIndexBuilds(ctx context.Context, namespace string) ([]types.IndexBuild, error) {
+	match := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "command.createIndexes", Value: bson.D{{Key: "$exists", Value: true}}}},
+		bson.D{{Key: "msg", Value: bson.D{{Key: "$regex", Value: "^Index Build"}}}},
+	}}}
+	switch {
+	case strings.Contains(namespace, "."):
+		match = append(match, bson.E{Key: "ns", Value: namespace})
+	case namespace != "":
+		match = append(match, bson.E{Key: "ns", Value: bson.D{{Key: "$regex", Value: "^" + regexp.QuoteMeta(namespace) + `\.`}}})
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$currentOp", Value: bson.D{{Key: "allUsers", Value: true}, {Key: "idleConnections", Value: false}}}},
+		{{Key: "$match", Value: match}},
+	}
+
+	cursor, err := c.client.Database("admin").Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list indexes for collection %q in database %q: %w", collectionName, dbName, err)
+		return nil, fmt.Errorf("failed to read current operations: %w", err)
 	}
 	defer func() { _ = cursor.Close(ctx) }()
 
-	var indexes []types.IndexInfo
-	for cursor.Next(ctx) {
-		var indexBson bson.M
-		if err := cursor.Decode(&indexBson); err != nil {
-			c.logger.Warn("Failed to decode index info", "error", err.Error())
-			continue
-		}
+	var ops []bson.M
+	if err := cursor.All(ctx, &ops); err != nil {
+		return nil, fmt.Errorf("failed to decode current operations: %w", err)
+	}
+	return decodeIndexBuilds(ops), nil
+}
 
-		index := types.IndexInfo{
-			Options: make(map[string]interface{}),
+// decodeIndexBuilds converts $currentOp entries into index builds. A build can appear as
+// both the client's createIndexes command and the server's build thread; the entry with
+// progress is kept.
+func decodeIndexBuilds(ops []bson.M) []types.IndexBuild {
+	var builds []types.IndexBuild
+	positions := make(map[string]int)
+	for _, op := range ops {
+		build := types.IndexBuild{}
+		build.Host, _ = op["host"].(string)
+		build.Namespace, _ = op["ns"].(string)
+		if opid, ok := op["opid"]; ok {
+			build.OpID = fmt.Sprint(opid)
 		}
-
-		if name, ok := indexBson["name"].(string); ok {
-			index.Name = name
+		if secs, ok := toInt64(op["secs_running"]); ok {
+			build.SecsRunning = secs
 		}
-
-		if keys, ok := indexBson["key"].(bson.M); ok {
-			index.Keys = make(map[string]interface{})
-			for k, v := range keys {
-				index.Keys[k] = v
+		if msg, ok := op["msg"].(string); ok {
+			build.Phase = indexBuildPhase(msg)
+		}
+		if progress := documentMap(op["progress"]); progress != nil {
+			build.Done, _ = toInt64(progress["done"])
+			build.Total, _ = toInt64(progress["total"])
+		}
+		if command := documentMap(op["command"]); command != nil {
+			if indexes, ok := command["indexes"].(bson.A); ok {
+				for _, index := range indexes {
+					if name, ok := documentMap(index)["name"].(string); ok {
+						build.Indexes = append(build.Indexes, name)
+					}
+				}
 			}
 		}
 
-		if unique, ok := indexBson["unique"].(bool); ok {
-			index.Unique = unique
+		key := build.Host + "|" + build.Namespace + "|" + strings.Join(build.Indexes, ",")
+		if i, seen := positions[key]; seen {
+			if builds[i].Total == 0 && build.Total > 0 {
+				builds[i] = build
+			}
+			continue
 		}
+		positions[key] = len(builds)
+		builds = append(builds, build)
+	}
+	return builds
+}
 
-		if sparse, ok := indexBson["sparse"].(bool); ok {
-			index.Sparse = sparse
-		}
+// indexBuildPhase extracts the phase from a $currentOp message such as
+// "Index Build: scanning collection Index Build: scanning collection: 1024/4096 25%"
+func indexBuildPhase(msg string) string {
+	msg = strings.TrimPrefix(msg, "Index Build: ")
+	if i := strings.Index(msg, " Index Build"); i != -1 {
+		msg = msg[:i]
+	}
+	if i := strings.Index(msg, ":"); i != -1 {
+		msg = msg[:i]
+	}
+	return strings.TrimSpace(msg)
+}
 
-		if background, ok := indexBson["background"].(bool); ok {
-			index.Background = background
-		}
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
 
-		if version, ok := indexBson["v"].(int32); ok {
-			index.Version = int(version)
-		}
+// ListIndexes lists all indexes for a collection
+func (c *Client) ListIndexes(ctx context.Context, dbName, collectionName string) ([]types.IndexInfo, error) {
+	db := c.client.Database(dbName)
+	collection := db.Collection(collectionName)
 
-		// Copy other options
-		for k, v := range indexBson {
-			if k != "name" && k != "key" && k != "unique" && k != "sparse" && k != "background" && k != "v" {
-				index.Options[k] = v
-			}
-		}
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes for collection %q in database %q: %w", collectionName, dbName, err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
 
+	var indexes []types.IndexInfo
+	for cursor.Next(ctx) {
+		index, err := decodeIndexInfo(cursor.Current)
+		if err != nil {
+			c.logger.Warn("Failed to decode index info", "error", err.Error())
+			continue
+		}
 		indexes = append(indexes, index)
 	}
 
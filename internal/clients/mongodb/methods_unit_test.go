@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 
 	"github.com/teabranch/matlas-cli/internal/logging"
 	"github.com/teabranch/matlas-cli/internal/types"
@@ -264,3 +265,133 @@ func TestClient_TypesIntegration(t *testing.T) {
 	assert.Equal(t, int64(2048), stats.Size)
 	assert.Equal(t, int64(20), stats.AvgObjSize)
 }
+
+func TestDecodeTimeSeriesStats(t *testing.T) {
+	stats := decodeTimeSeriesStats(bson.M{
+		"bucketsNs":                "metrics.system.buckets.readings",
+		"bucketCount":              int32(12),
+		"avgBucketSize":            int64(4096),
+		"numMeasurementsCommitted": int64(1500),
+		"numBucketInserts":         int64(12),
+	})
+
+	assert.Equal(t, &types.TimeSeriesStats{
+		BucketsNamespace:      "metrics.system.buckets.readings",
+		BucketCount:           12,
+		AvgBucketSize:         4096,
+		MeasurementsCommitted: 1500,
+	}, stats)
+}
+
+func TestIndexKeysDocument_KeepsOrder(t *testing.T) {
+	keys := []types.IndexKey{{Field: "z", Order: 1}, {Field: "a", Order: -1}, {Field: "body", Type: types.IndexTypeText}}
+
+	doc := indexKeysDocument(keys)
+	assert.Equal(t, bson.D{{Key: "z", Value: 1}, {Key: "a", Value: -1}, {Key: "body", Value: "text"}}, doc)
+}
+
+func TestDecodeIndexInfo(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{
+		{Key: "v", Value: int32(2)},
+		{Key: "key", Value: bson.D{{Key: "status", Value: int32(1)}, {Key: "createdAt", Value: int32(-1)}, {Key: "loc", Value: "2dsphere"}}},
+		{Key: "name", Value: "status_1_createdAt_-1_loc_2dsphere"},
+		{Key: "expireAfterSeconds", Value: int32(3600)},
+		{Key: "partialFilterExpression", Value: bson.D{{Key: "active", Value: true}}},
+		{Key: "collation", Value: bson.D{{Key: "locale", Value: "en"}, {Key: "strength", Value: int32(2)}, {Key: "caseLevel", Value: false}}},
+		{Key: "hidden", Value: true},
+		{Key: "2dsphereIndexVersion", Value: int32(3)},
+	})
+	require.NoError(t, err)
+
+	index, err := decodeIndexInfo(raw)
+	require.NoError(t, err)
+	assert.Equal(t, types.IndexKeys{
+		{Field: "status", Order: 1},
+		{Field: "createdAt", Order: -1},
+		{Field: "loc", Type: types.IndexType2DSphere},
+	}, index.Keys)
+	assert.Equal(t, "status_1_createdAt_-1_loc_2dsphere", index.Name)
+	assert.Equal(t, 2, index.Version)
+	require.NotNil(t, index.ExpireAfterSeconds)
+	assert.Equal(t, int32(3600), *index.ExpireAfterSeconds)
+	assert.Equal(t, true, index.PartialFilterExpression["active"])
+	assert.Equal(t, &types.IndexCollation{Locale: "en", Strength: 2}, index.Collation)
+	assert.True(t, index.Hidden)
+	assert.Equal(t, map[string]interface{}{"2dsphereIndexVersion": int32(3)}, index.Options)
+}
+
+func TestIndexOptions(t *testing.T) {
+	ttl := int32(60)
+	opts := indexOptions(&types.IndexOptions{
+		Name:               "ttl_idx",
+		Hidden:             true,
+		ExpireAfterSeconds: &ttl,
+		Collation:          &types.IndexCollation{Locale: "fr", Strength: 1},
+	})
+
+	assert.Equal(t, "ttl_idx", *opts.Name)
+	assert.True(t, *opts.Hidden)
+	assert.Equal(t, int32(60), *opts.ExpireAfterSeconds)
+	assert.Equal(t, "fr", opts.Collation.Locale)
+	assert.Nil(t, opts.Unique)
+	assert.NotNil(t, indexOptions(nil))
+}
+
+func TestDecodeIndexBuilds(t *testing.T) {
+	entries := []bson.D{
+		// The client's createIndexes command waiting for the build
+		{
+			{Key: "host", Value: "shard-00-01:27017"},
+			{Key: "opid", Value: int32(101)},
+			{Key: "ns", Value: "shop.orders"},
+			{Key: "secs_running", Value: int64(40)},
+			{Key: "command", Value: bson.D{
+				{Key: "createIndexes", Value: "orders"},
+				{Key: "indexes", Value: bson.A{bson.D{{Key: "key", Value: bson.D{{Key: "customerId", Value: int32(1)}}}, {Key: "name", Value: "customerId_1"}}}},
+			}},
+		},
+		// The build thread reporting progress
+		{
+			{Key: "host", Value: "shard-00-01:27017"},
+			{Key: "opid", Value: int32(102)},
+			{Key: "ns", Value: "shop.orders"},
+			{Key: "secs_running", Value: int64(38)},
+			{Key: "msg", Value: "Index Build: scanning collection Index Build: scanning collection: 2500/10000 25%"},
+			{Key: "progress", Value: bson.D{{Key: "done", Value: int64(2500)}, {Key: "total", Value: int64(10000)}}},
+			{Key: "command", Value: bson.D{
+				{Key: "createIndexes", Value: "orders"},
+				{Key: "indexes", Value: bson.A{bson.D{{Key: "key", Value: bson.D{{Key: "customerId", Value: int32(1)}}}, {Key: "name", Value: "customerId_1"}}}},
+			}},
+		},
+	}
+
+	ops := make([]bson.M, 0, len(entries))
+	for _, entry := range entries {
+		raw, err := bson.Marshal(entry)
+		require.NoError(t, err)
+		var op bson.M
+		require.NoError(t, bson.Unmarshal(raw, &op))
+		ops = append(ops, op)
+	}
+
+	builds := decodeIndexBuilds(ops)
+	require.Len(t, builds, 1)
+	assert.Equal(t, types.IndexBuild{
+		Host:        "shard-00-01:27017",
+		Namespace:   "shop.orders",
+		Indexes:     []string{"customerId_1"},
+		OpID:        "102",
+		Phase:       "scanning collection",
+		Done:        2500,
+		Total:       10000,
+		SecsRunning: 38,
+	}, builds[0])
+	assert.Equal(t, 25.0, builds[0].Percent())
+	assert.True(t, builds[0].Builds("customerId_1"))
+}
+
+func TestCreateIndexesOptions(t *testing.T) {
+	assert.Nil(t, createIndexesOptions(nil).CommitQuorum)
+	assert.Equal(t, int32(2), createIndexesOptions(&types.IndexOptions{CommitQuorum: "2"}).CommitQuorum)
+	assert.Equal(t, "majority", createIndexesOptions(&types.IndexOptions{CommitQuorum: "majority"}).CommitQuorum)
+}
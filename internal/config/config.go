@@ -23,6 +23,10 @@ const (
 // specify `--timeout`, `ATLAS_TIMEOUT`, or `timeout` YAML key.
 const DefaultTimeout = 30 * time.Second
 
+// DefaultIPResolverURL is the service queried for the caller's public IP when neither
+// `--resolver-url`, `ATLAS_IP_RESOLVER_URL` nor the `ipResolverUrl` YAML key is set.
+const DefaultIPResolverURL = "https://checkip.amazonaws.com"
+
 // DefaultConfigDir is the default directory under the user's home for matlas config files.
 const DefaultConfigDir = ".matlas"
 
@@ -46,6 +50,9 @@ type Config struct {
 	Output  OutputFormat  `mapstructure:"output" yaml:"output"`
 	Timeout time.Duration `mapstructure:"timeout" yaml:"timeout"`
 
+	// IPResolverURL returns the caller's public IP as plain text (used by `network add-my-ip`)
+	IPResolverURL string `mapstructure:"ipResolverUrl" yaml:"ipResolverUrl"`
+
 	// Credentials (avoid printing/logging!)
 	APIKey    string `mapstructure:"apiKey" yaml:"apiKey"`
 	PublicKey string `mapstructure:"publicKey" yaml:"publicKey"`
@@ -78,6 +85,18 @@ func (c *Config) ResolveOrgID(flagValue string) string {
 	return c.OrgID
 }
 
+// ResolveIPResolverURL resolves the public IP resolver URL from flag value or configuration,
+// falling back to DefaultIPResolverURL
+func (c *Config) ResolveIPResolverURL(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if c.IPResolverURL != "" {
+		return c.IPResolverURL
+	}
+	return DefaultIPResolverURL
+}
+
 // Validate performs sanity checks after the full precedence merge.
 // Only inexpensive validation belongs here; cross-service validation should live closer to service layers.
 func (c *Config) Validate() error {
@@ -109,6 +109,30 @@ func TestLoad_FlagClusterNameMapping(t *testing.T) {
 	}
 }
 
+func TestLoad_IPResolverURL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cfg, err := config.Load(nil, "")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.ResolveIPResolverURL(""); got != config.DefaultIPResolverURL {
+		t.Fatalf("expected default resolver, got %s", got)
+	}
+
+	t.Setenv("ATLAS_IP_RESOLVER_URL", "https://ip.internal.example.com")
+	cfg, err = config.Load(nil, "")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.ResolveIPResolverURL(""); got != "https://ip.internal.example.com" {
+		t.Fatalf("expected resolver from env, got %s", got)
+	}
+	if got := cfg.ResolveIPResolverURL("https://flag.example.com"); got != "https://flag.example.com" {
+		t.Fatalf("expected flag to take precedence, got %s", got)
+	}
+}
+
 // -------------------- Credential negative path --------------------
 
 func TestResolveAPIKey_NotFound(t *testing.T) {
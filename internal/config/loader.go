@@ -67,6 +67,7 @@ func Load(cmd *cobra.Command, explicitPath string) (*Config, error) {
 	_ = v.BindEnv("clusterName", "ATLAS_CLUSTER_NAME")
 	_ = v.BindEnv("apiKey", "ATLAS_API_KEY")
 	_ = v.BindEnv("publicKey", "ATLAS_PUB_KEY")
+	_ = v.BindEnv("ipResolverUrl", "ATLAS_IP_RESOLVER_URL")
 
 	// ---------- 4. Flags ----------
 	if cmd != nil {
@@ -85,6 +86,7 @@ func Load(cmd *cobra.Command, explicitPath string) (*Config, error) {
 		bind("clusterName", "cluster-name")
 		bind("apiKey", "api-key")
 		bind("publicKey", "pub-key")
+		bind("ipResolverUrl", "resolver-url")
 		// output and timeout flags use same spelling as struct tags already when no dashes.
 	}
 
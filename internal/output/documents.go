@@ -0,0 +1,126 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/teabranch/matlas-cli/internal/config"
+)
+
+// maxDocumentCellWidth bounds the width of a field value in document tables
+const maxDocumentCellWidth = 48
+
+// ExtendedJSONDocument converts a BSON document into plain JSON values through relaxed
+// MongoDB Extended JSON, so ObjectIDs, dates and other BSON types print the way
+// Extended JSON filters and files accept them
+func ExtendedJSONDocument(doc interface{}) (map[string]interface{}, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	data, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document as Extended JSON: %w", err)
+	}
+	var converted map[string]interface{}
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return nil, fmt.Errorf("failed to decode Extended JSON: %w", err)
+	}
+	return converted, nil
+}
+
+// DocumentTable lays documents out with one column per top-level field, in the order the
+// fields first appear. Values are shown as compact relaxed Extended JSON.
+func DocumentTable(docs []bson.D) (TableData, error) {
+	var headers []string
+	columns := make(map[string]int)
+	for _, doc := range docs {
+		for _, field := range doc {
+			if _, ok := columns[field.Key]; !ok {
+				columns[field.Key] = len(headers)
+				headers = append(headers, field.Key)
+			}
+		}
+	}
+
+	rows := make([][]string, 0, len(docs))
+	for _, doc := range docs {
+		row := make([]string, len(headers))
+		for _, field := range doc {
+			value, err := documentCell(field.Value, maxDocumentCellWidth)
+			if err != nil {
+				return TableData{}, err
+			}
+			row[columns[field.Key]] = value
+		}
+		rows = append(rows, row)
+	}
+	return TableData{Headers: headers, Rows: rows}, nil
+}
+
+// FormatDocuments outputs documents as a table, or as relaxed Extended JSON values for
+// JSON and YAML output
+func FormatDocuments(formatter *Formatter, docs []bson.D) error {
+	switch formatter.format {
+	case config.OutputJSON, config.OutputYAML:
+		converted := make([]map[string]interface{}, 0, len(docs))
+		for _, doc := range docs {
+			value, err := ExtendedJSONDocument(doc)
+			if err != nil {
+				return err
+			}
+			converted = append(converted, value)
+		}
+		return formatter.Format(converted)
+	default:
+		table, err := DocumentTable(docs)
+		if err != nil {
+			return err
+		}
+		return formatter.Format(table)
+	}
+}
+
+// FormatDocument outputs a single document as a FIELD/VALUE table, or as relaxed
+// Extended JSON values for JSON and YAML output
+func FormatDocument(formatter *Formatter, doc bson.D) error {
+	switch formatter.format {
+	case config.OutputJSON, config.OutputYAML:
+		value, err := ExtendedJSONDocument(doc)
+		if err != nil {
+			return err
+		}
+		return formatter.Format(value)
+	default:
+		table := TableData{Headers: []string{"FIELD", "VALUE"}}
+		for _, field := range doc {
+			value, err := documentCell(field.Value, 0)
+			if err != nil {
+				return err
+			}
+			table.Rows = append(table.Rows, []string{field.Key, value})
+		}
+		return formatter.Format(table)
+	}
+}
+
+// documentCell renders a field value, truncated to width runes unless width is 0
+func documentCell(value interface{}, width int) (string, error) {
+	if s, ok := value.(string); ok {
+		return truncateCell(s, width), nil
+	}
+	data, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: value}}, false, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode field value: %w", err)
+	}
+	// Strip the {"v": ...} wrapper needed to encode a bare value
+	return truncateCell(string(data[len(`{"v":`):len(data)-1]), width), nil
+}
+
+func truncateCell(s string, width int) string {
+	if runes := []rune(s); width > 0 && len(runes) > width {
+		return string(runes[:width-3]) + "..."
+	}
+	return s
+}
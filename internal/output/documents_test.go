@@ -0,0 +1,47 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/teabranch/matlas-cli/internal/config"
+)
+
+func TestDocumentTable(t *testing.T) {
+	oid, err := primitive.ObjectIDFromHex("65f1c2a9e4b0a1b2c3d4e5f6")
+	require.NoError(t, err)
+
+	table, err := DocumentTable([]bson.D{
+		{{Key: "_id", Value: oid}, {Key: "name", Value: "Ada"}},
+		{{Key: "_id", Value: int32(2)}, {Key: "tags", Value: bson.A{"a", "b"}}, {Key: "name", Value: "Grace"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"_id", "name", "tags"}, table.Headers, "columns follow the order fields first appear")
+	assert.Equal(t, [][]string{
+		{`{"$oid":"65f1c2a9e4b0a1b2c3d4e5f6"}`, "Ada", ""},
+		{"2", "Grace", `["a","b"]`},
+	}, table.Rows)
+}
+
+func TestFormatDocuments_JSONUsesExtendedJSON(t *testing.T) {
+	var buf bytes.Buffer
+	formatter := NewFormatter(config.OutputJSON, &buf)
+
+	oid := primitive.NewObjectID()
+	require.NoError(t, FormatDocuments(formatter, []bson.D{{{Key: "_id", Value: oid}, {Key: "n", Value: int64(5)}}}))
+
+	assert.Contains(t, buf.String(), `"$oid": "`+oid.Hex()+`"`)
+	assert.Contains(t, buf.String(), `"n": 5`)
+}
+
+func TestTruncateCell(t *testing.T) {
+	assert.Equal(t, "abc", truncateCell("abc", 5))
+	assert.Equal(t, "ab...", truncateCell("abcdef", 5))
+	assert.Equal(t, "abcdef", truncateCell("abcdef", 0))
+}
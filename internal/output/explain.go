@@ -0,0 +1,140 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// FormatExplain outputs an explain summary as a plan tree followed by pipeline stages,
+// counts and warnings, or as structured data for JSON and YAML output
+func FormatExplain(formatter *Formatter, summary *types.ExplainSummary) error {
+	switch formatter.format {
+	case config.OutputJSON, config.OutputYAML:
+		return formatter.Format(summary)
+	default:
+		return WriteExplain(formatter.writer, summary)
+	}
+}
+
+// WriteExplain writes an explain summary as readable text
+func WriteExplain(w io.Writer, summary *types.ExplainSummary) error {
+	var b strings.Builder
+	if summary.Namespace != "" {
+		fmt.Fprintf(&b, "Namespace: %s\n", summary.Namespace)
+	}
+	fmt.Fprintf(&b, "Verbosity: %s\n", summary.Verbosity)
+
+	if len(summary.Shards) > 0 {
+		for _, shard := range summary.Shards {
+			fmt.Fprintf(&b, "\nShard %s:\n", shard.Shard)
+			writePlan(&b, &shard, "  ")
+		}
+		b.WriteString("\n")
+	} else {
+		writePlan(&b, summary, "")
+	}
+
+	if len(summary.Stages) > 0 {
+		stages := make([]string, 0, len(summary.Stages))
+		for _, stage := range summary.Stages {
+			stages = append(stages, stage.Stage+stageCounts(stage))
+		}
+		fmt.Fprintf(&b, "Pipeline: %s\n", strings.Join(stages, " → "))
+	}
+	if len(summary.IndexesUsed) > 0 {
+		fmt.Fprintf(&b, "Indexes used: %s\n", strings.Join(summary.IndexesUsed, ", "))
+	} else if summary.Plan != nil || len(summary.Shards) > 0 {
+		b.WriteString("Indexes used: none\n")
+	}
+	if counts := explainTotals(summary); counts != "" {
+		fmt.Fprintf(&b, "%s\n", counts)
+	}
+	for _, warning := range summary.Warnings {
+		fmt.Fprintf(&b, "Warning: %s\n", warning)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writePlan writes the query plan of a summary as a tree
+func writePlan(b *strings.Builder, summary *types.ExplainSummary, indent string) {
+	if summary.Plan == nil {
+		fmt.Fprintf(b, "%sPlan: none (the pipeline doesn't read from a collection)\n", indent)
+		return
+	}
+	fmt.Fprintf(b, "%sPlan:\n", indent)
+	writePlanStage(b, summary.Plan, indent+"  ", "")
+	if len(summary.Shards) == 0 && summary.Shard != "" {
+		if counts := explainTotals(summary); counts != "" {
+			fmt.Fprintf(b, "%s%s\n", indent, counts)
+		}
+	}
+}
+
+func writePlanStage(b *strings.Builder, stage *types.ExplainStage, prefix, connector string) {
+	line := stage.Stage
+	if stage.IndexName != "" {
+		line += " " + stage.IndexName
+	}
+	fmt.Fprintf(b, "%s%s%s%s\n", prefix, connector, line, stageCounts(*stage))
+
+	childPrefix := prefix
+	switch connector {
+	case "├─ ":
+		childPrefix += "│  "
+	case "└─ ":
+		childPrefix += "   "
+	}
+	for i := range stage.Inputs {
+		next := "├─ "
+		if i == len(stage.Inputs)-1 {
+			next = "└─ "
+		}
+		writePlanStage(b, &stage.Inputs[i], childPrefix, next)
+	}
+}
+
+// stageCounts formats the counts of a stage, which are only known with executionStats
+func stageCounts(stage types.ExplainStage) string {
+	var counts []string
+	if stage.Returned != nil {
+		counts = append(counts, fmt.Sprintf("returned %d", *stage.Returned))
+	}
+	if stage.DocsExamined != nil {
+		counts = append(counts, fmt.Sprintf("docs examined %d", *stage.DocsExamined))
+	}
+	if stage.KeysExamined != nil {
+		counts = append(counts, fmt.Sprintf("keys examined %d", *stage.KeysExamined))
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(counts, ", ") + ")"
+}
+
+// explainTotals formats the documents and keys examined against the documents returned
+func explainTotals(summary *types.ExplainSummary) string {
+	if summary.DocsExamined == nil && summary.KeysExamined == nil && summary.Returned == nil {
+		return ""
+	}
+	var parts []string
+	if summary.DocsExamined != nil {
+		parts = append(parts, fmt.Sprintf("docs examined %d", *summary.DocsExamined))
+	}
+	if summary.KeysExamined != nil {
+		parts = append(parts, fmt.Sprintf("keys examined %d", *summary.KeysExamined))
+	}
+	if summary.Returned != nil {
+		parts = append(parts, fmt.Sprintf("returned %d", *summary.Returned))
+	}
+	totals := "Totals: " + strings.Join(parts, ", ")
+	if summary.ExecutionMS != nil {
+		totals += fmt.Sprintf(" in %dms", *summary.ExecutionMS)
+	}
+	return totals
+}
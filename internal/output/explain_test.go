@@ -0,0 +1,59 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/teabranch/matlas-cli/internal/config"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func count(n int64) *int64 { return &n }
+
+func TestWriteExplain_PlanTree(t *testing.T) {
+	summary := &types.ExplainSummary{
+		Namespace: "shop.orders",
+		Verbosity: types.ExplainExecutionStats,
+		Plan: &types.ExplainStage{Stage: "OR", Returned: count(40), Inputs: []types.ExplainStage{
+			{Stage: "FETCH", Inputs: []types.ExplainStage{{Stage: "IXSCAN", IndexName: "status_1", KeysExamined: count(30)}}},
+			{Stage: "COLLSCAN", DocsExamined: count(900)},
+		}},
+		Stages:         []types.ExplainStage{{Stage: "$group", Returned: count(4)}, {Stage: "$sort"}},
+		IndexesUsed:    []string{"status_1"},
+		CollectionScan: true,
+		Returned:       count(40),
+		DocsExamined:   count(930),
+		KeysExamined:   count(30),
+		ExecutionMS:    count(12),
+		Warnings:       []string{"COLLSCAN: the query reads every document of shop.orders"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteExplain(&buf, summary))
+
+	assert.Equal(t, `Namespace: shop.orders
+Verbosity: executionStats
+Plan:
+  OR (returned 40)
+  ├─ FETCH
+  │  └─ IXSCAN status_1 (keys examined 30)
+  └─ COLLSCAN (docs examined 900)
+Pipeline: $group (returned 4) → $sort
+Indexes used: status_1
+Totals: docs examined 930, keys examined 30, returned 40 in 12ms
+Warning: COLLSCAN: the query reads every document of shop.orders
+`, buf.String())
+}
+
+func TestFormatExplain_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	summary := &types.ExplainSummary{Verbosity: types.ExplainQueryPlanner, Plan: &types.ExplainStage{Stage: "COLLSCAN"}, CollectionScan: true}
+
+	require.NoError(t, FormatExplain(NewFormatter(config.OutputJSON, &buf), summary))
+
+	assert.Contains(t, buf.String(), `"collectionScan": true`)
+	assert.Contains(t, buf.String(), `"stage": "COLLSCAN"`)
+}
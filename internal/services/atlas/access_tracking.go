@@ -0,0 +1,48 @@
+package atlas
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+)
+
+// MaxAccessLogs is the largest number of log entries Atlas returns per access history request.
+const MaxAccessLogs = 20000
+
+// AccessTrackingService reads the database access history (authentication attempts) of clusters.
+type AccessTrackingService struct {
+	client *atlasclient.Client
+}
+
+// NewAccessTrackingService creates a new AccessTrackingService.
+func NewAccessTrackingService(client *atlasclient.Client) *AccessTrackingService {
+	return &AccessTrackingService{client: client}
+}
+
+// ListClusterAccessLogs returns the successful authentications against a cluster since the given time.
+// Atlas does not keep access history for every cluster tier; callers should treat errors as
+// "history unavailable" for that cluster.
+func (s *AccessTrackingService) ListClusterAccessLogs(ctx context.Context, projectID, clusterName string, since time.Time) ([]admin.MongoDBAccessLogs, error) {
+	if projectID == "" || clusterName == "" {
+		return nil, fmt.Errorf("projectID and clusterName are required")
+	}
+	var logs []admin.MongoDBAccessLogs
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		req := api.AccessTrackingApi.GetAccessHistoryCluster(ctx, projectID, clusterName).
+			AuthResult(true).
+			NLogs(MaxAccessLogs)
+		if !since.IsZero() {
+			req = req.Start(since.UnixMilli())
+		}
+		resp, _, err := req.Execute()
+		if err != nil {
+			return err
+		}
+		logs = resp.GetAccessLogs()
+		return nil
+	})
+	return logs, err
+}
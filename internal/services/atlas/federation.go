@@ -0,0 +1,386 @@
+package atlas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	"github.com/teabranch/matlas-cli/internal/types"
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+)
+
+// oidcProtocol is the only identity provider protocol managed by matlas.
+const oidcProtocol = "OIDC"
+
+// FederationService manages federated authentication: federation settings,
+// OIDC identity providers, connected organizations and role mappings.
+type FederationService struct {
+	client *atlasclient.Client
+}
+
+// NewFederationService creates a new FederationService.
+func NewFederationService(client *atlasclient.Client) *FederationService {
+	return &FederationService{client: client}
+}
+
+// GetSettings returns the federation settings the organization is connected to.
+func (s *FederationService) GetSettings(ctx context.Context, orgID string) (*admin.OrgFederationSettings, error) {
+	if orgID == "" {
+		return nil, fmt.Errorf("orgID required")
+	}
+	var settings *admin.OrgFederationSettings
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.GetFederationSettings(ctx, orgID).Execute()
+		if err != nil {
+			return err
+		}
+		settings = resp
+		return nil
+	})
+	return settings, err
+}
+
+// ListIdentityProviders returns the OIDC identity providers in the federation.
+func (s *FederationService) ListIdentityProviders(ctx context.Context, federationSettingsID string) ([]admin.FederationIdentityProvider, error) {
+	if federationSettingsID == "" {
+		return nil, fmt.Errorf("federationSettingsID required")
+	}
+	var providers []admin.FederationIdentityProvider
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.ListIdentityProviders(ctx, federationSettingsID).
+			Protocol([]string{oidcProtocol}).
+			IdpType([]string{"WORKFORCE", "WORKLOAD"}).
+			Execute()
+		if err != nil {
+			return err
+		}
+		if resp != nil && resp.Results != nil {
+			providers = *resp.Results
+		}
+		return nil
+	})
+	return providers, err
+}
+
+// CreateIdentityProvider creates an OIDC identity provider in the federation.
+func (s *FederationService) CreateIdentityProvider(ctx context.Context, federationSettingsID string, idp *types.IdentityProviderConfig) (*admin.FederationOidcIdentityProvider, error) {
+	if federationSettingsID == "" || idp == nil {
+		return nil, fmt.Errorf("federationSettingsID and identity provider are required")
+	}
+	request := ConvertIdentityProviderToAtlas(idp)
+
+	var created *admin.FederationOidcIdentityProvider
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.CreateIdentityProvider(ctx, federationSettingsID, request).Execute()
+		if err != nil {
+			return err
+		}
+		created = resp
+		return nil
+	})
+	return created, err
+}
+
+// UpdateIdentityProvider updates an existing OIDC identity provider.
+func (s *FederationService) UpdateIdentityProvider(ctx context.Context, federationSettingsID, identityProviderID string, idp *types.IdentityProviderConfig) (*admin.FederationIdentityProvider, error) {
+	if federationSettingsID == "" || identityProviderID == "" || idp == nil {
+		return nil, fmt.Errorf("federationSettingsID, identityProviderID and identity provider are required")
+	}
+	request := ConvertIdentityProviderUpdateToAtlas(idp)
+
+	var updated *admin.FederationIdentityProvider
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.UpdateIdentityProvider(ctx, federationSettingsID, identityProviderID, request).Execute()
+		if err != nil {
+			return err
+		}
+		updated = resp
+		return nil
+	})
+	return updated, err
+}
+
+// GetConnectedOrg returns the connected organization configuration.
+func (s *FederationService) GetConnectedOrg(ctx context.Context, federationSettingsID, orgID string) (*admin.ConnectedOrgConfig, error) {
+	if federationSettingsID == "" || orgID == "" {
+		return nil, fmt.Errorf("federationSettingsID and orgID are required")
+	}
+	var config *admin.ConnectedOrgConfig
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.GetConnectedOrgConfig(ctx, federationSettingsID, orgID).Execute()
+		if err != nil {
+			return err
+		}
+		config = resp
+		return nil
+	})
+	return config, err
+}
+
+// UpdateConnectedOrg updates the connected organization configuration.
+func (s *FederationService) UpdateConnectedOrg(ctx context.Context, federationSettingsID, orgID string, config *admin.ConnectedOrgConfig) (*admin.ConnectedOrgConfig, error) {
+	if federationSettingsID == "" || orgID == "" || config == nil {
+		return nil, fmt.Errorf("federationSettingsID, orgID and config are required")
+	}
+	var updated *admin.ConnectedOrgConfig
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.UpdateConnectedOrgConfig(ctx, federationSettingsID, orgID, config).Execute()
+		if err != nil {
+			return err
+		}
+		updated = resp
+		return nil
+	})
+	return updated, err
+}
+
+// ListRoleMappings returns the role mappings of a connected organization.
+func (s *FederationService) ListRoleMappings(ctx context.Context, federationSettingsID, orgID string) ([]admin.AuthFederationRoleMapping, error) {
+	if federationSettingsID == "" || orgID == "" {
+		return nil, fmt.Errorf("federationSettingsID and orgID are required")
+	}
+	var mappings []admin.AuthFederationRoleMapping
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.ListRoleMappings(ctx, federationSettingsID, orgID).Execute()
+		if err != nil {
+			return err
+		}
+		if resp != nil && resp.Results != nil {
+			mappings = *resp.Results
+		}
+		return nil
+	})
+	return mappings, err
+}
+
+// CreateRoleMapping creates a role mapping in a connected organization.
+func (s *FederationService) CreateRoleMapping(ctx context.Context, federationSettingsID string, spec *types.RoleMappingSpec) (*admin.AuthFederationRoleMapping, error) {
+	if federationSettingsID == "" || spec == nil || spec.OrgID == "" {
+		return nil, fmt.Errorf("federationSettingsID and spec with orgId are required")
+	}
+	request := ConvertRoleMappingToAtlas(spec)
+
+	var created *admin.AuthFederationRoleMapping
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.CreateRoleMapping(ctx, federationSettingsID, spec.OrgID, request).Execute()
+		if err != nil {
+			return err
+		}
+		created = resp
+		return nil
+	})
+	return created, err
+}
+
+// UpdateRoleMapping replaces the role assignments of an existing role mapping.
+func (s *FederationService) UpdateRoleMapping(ctx context.Context, federationSettingsID, mappingID string, spec *types.RoleMappingSpec) (*admin.AuthFederationRoleMapping, error) {
+	if federationSettingsID == "" || mappingID == "" || spec == nil || spec.OrgID == "" {
+		return nil, fmt.Errorf("federationSettingsID, mappingID and spec with orgId are required")
+	}
+	request := ConvertRoleMappingToAtlas(spec)
+
+	var updated *admin.AuthFederationRoleMapping
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.FederatedAuthenticationApi.UpdateRoleMapping(ctx, federationSettingsID, mappingID, spec.OrgID, request).Execute()
+		if err != nil {
+			return err
+		}
+		updated = resp
+		return nil
+	})
+	return updated, err
+}
+
+// DeleteRoleMapping removes a role mapping from a connected organization.
+func (s *FederationService) DeleteRoleMapping(ctx context.Context, federationSettingsID, mappingID, orgID string) error {
+	if federationSettingsID == "" || mappingID == "" || orgID == "" {
+		return fmt.Errorf("federationSettingsID, mappingID and orgID are required")
+	}
+	return s.client.Do(ctx, func(api *admin.APIClient) error {
+		_, err := api.FederatedAuthenticationApi.DeleteRoleMapping(ctx, federationSettingsID, mappingID, orgID).Execute()
+		return err
+	})
+}
+
+// ConvertIdentityProviderToAtlas converts an IdentityProviderConfig to an Atlas OIDC create request.
+func ConvertIdentityProviderToAtlas(idp *types.IdentityProviderConfig) *admin.FederationOidcIdentityProviderUpdate {
+	request := &admin.FederationOidcIdentityProviderUpdate{
+		DisplayName: admin.PtrString(idp.DisplayName),
+		IssuerUri:   admin.PtrString(idp.IssuerURI),
+		Audience:    admin.PtrString(idp.Audience),
+		IdpType:     admin.PtrString(identityProviderType(idp.IdpType)),
+		Protocol:    admin.PtrString(oidcProtocol),
+	}
+	if idp.Description != "" {
+		request.Description = admin.PtrString(idp.Description)
+	}
+	if idp.ClientID != "" {
+		request.ClientId = admin.PtrString(idp.ClientID)
+	}
+	if idp.AuthorizationType != "" {
+		request.AuthorizationType = admin.PtrString(idp.AuthorizationType)
+	}
+	if idp.GroupsClaim != "" {
+		request.GroupsClaim = admin.PtrString(idp.GroupsClaim)
+	}
+	if idp.UserClaim != "" {
+		request.UserClaim = admin.PtrString(idp.UserClaim)
+	}
+	if len(idp.RequestedScopes) > 0 {
+		request.RequestedScopes = &idp.RequestedScopes
+	}
+	if len(idp.AssociatedDomains) > 0 {
+		request.AssociatedDomains = &idp.AssociatedDomains
+	}
+	return request
+}
+
+// ConvertIdentityProviderUpdateToAtlas converts an IdentityProviderConfig to an Atlas update request.
+func ConvertIdentityProviderUpdateToAtlas(idp *types.IdentityProviderConfig) *admin.FederationIdentityProviderUpdate {
+	create := ConvertIdentityProviderToAtlas(idp)
+	return &admin.FederationIdentityProviderUpdate{
+		DisplayName:       create.DisplayName,
+		Description:       create.Description,
+		IssuerUri:         create.IssuerUri,
+		Audience:          create.Audience,
+		IdpType:           create.IdpType,
+		Protocol:          create.Protocol,
+		ClientId:          create.ClientId,
+		AuthorizationType: create.AuthorizationType,
+		GroupsClaim:       create.GroupsClaim,
+		UserClaim:         create.UserClaim,
+		RequestedScopes:   create.RequestedScopes,
+		AssociatedDomains: create.AssociatedDomains,
+	}
+}
+
+// ConvertIdentityProviderFromAtlas converts an Atlas identity provider to our IdentityProviderConfig.
+func ConvertIdentityProviderFromAtlas(idp admin.FederationIdentityProvider) types.IdentityProviderConfig {
+	return types.IdentityProviderConfig{
+		DisplayName:       idp.GetDisplayName(),
+		ID:                idp.GetId(),
+		Description:       idp.GetDescription(),
+		IdpType:           idp.GetIdpType(),
+		IssuerURI:         idp.GetIssuerUri(),
+		Audience:          idp.GetAudience(),
+		ClientID:          idp.GetClientId(),
+		AuthorizationType: idp.GetAuthorizationType(),
+		GroupsClaim:       idp.GetGroupsClaim(),
+		UserClaim:         idp.GetUserClaim(),
+		RequestedScopes:   idp.GetRequestedScopes(),
+		AssociatedDomains: idp.GetAssociatedDomains(),
+	}
+}
+
+// ConvertConnectedOrgToAtlas converts ConnectedOrgSettings to an Atlas ConnectedOrgConfig.
+// Data access identity providers are given by display name and resolved through providerIDs.
+func ConvertConnectedOrgToAtlas(orgID string, settings *types.ConnectedOrgSettings, providerIDs map[string]string) (*admin.ConnectedOrgConfig, error) {
+	config := &admin.ConnectedOrgConfig{
+		OrgId:                    orgID,
+		DomainRestrictionEnabled: settings.DomainRestrictionEnabled,
+	}
+	if settings.IdentityProviderID != "" {
+		config.IdentityProviderId = admin.PtrString(settings.IdentityProviderID)
+	}
+	dataAccess := make([]string, 0, len(settings.DataAccessIdentityProviders))
+	for _, name := range settings.DataAccessIdentityProviders {
+		id, ok := providerIDs[name]
+		if !ok {
+			return nil, fmt.Errorf("identity provider %q not found in federation", name)
+		}
+		dataAccess = append(dataAccess, id)
+	}
+	config.DataAccessIdentityProviderIds = &dataAccess
+	if settings.DomainAllowList != nil {
+		config.DomainAllowList = &settings.DomainAllowList
+	}
+	if settings.PostAuthRoleGrants != nil {
+		config.PostAuthRoleGrants = &settings.PostAuthRoleGrants
+	}
+	return config, nil
+}
+
+// ConvertConnectedOrgFromAtlas converts an Atlas ConnectedOrgConfig to our ConnectedOrgSettings.
+// Data access identity provider IDs are reported by display name when providerNames knows them.
+func ConvertConnectedOrgFromAtlas(config *admin.ConnectedOrgConfig, providerNames map[string]string) *types.ConnectedOrgSettings {
+	if config == nil {
+		return nil
+	}
+	settings := &types.ConnectedOrgSettings{
+		IdentityProviderID:       config.GetIdentityProviderId(),
+		DomainRestrictionEnabled: config.DomainRestrictionEnabled,
+		DomainAllowList:          config.GetDomainAllowList(),
+		PostAuthRoleGrants:       config.GetPostAuthRoleGrants(),
+	}
+	for _, id := range config.GetDataAccessIdentityProviderIds() {
+		if name, ok := providerNames[id]; ok {
+			id = name
+		}
+		settings.DataAccessIdentityProviders = append(settings.DataAccessIdentityProviders, id)
+	}
+	return settings
+}
+
+// ConvertRoleMappingToAtlas converts our RoleMappingSpec to an Atlas role mapping.
+// Organization roles without an explicit orgId are granted in the mapping's organization.
+func ConvertRoleMappingToAtlas(spec *types.RoleMappingSpec) *admin.AuthFederationRoleMapping {
+	assignments := make([]admin.ConnectedOrgConfigRoleAssignment, 0, len(spec.RoleAssignments))
+	for _, ra := range spec.RoleAssignments {
+		assignment := admin.ConnectedOrgConfigRoleAssignment{Role: admin.PtrString(ra.Role)}
+		if ra.ProjectID != "" {
+			assignment.GroupId = admin.PtrString(ra.ProjectID)
+		} else {
+			orgID := ra.OrgID
+			if orgID == "" {
+				orgID = spec.OrgID
+			}
+			assignment.OrgId = admin.PtrString(orgID)
+		}
+		assignments = append(assignments, assignment)
+	}
+	return &admin.AuthFederationRoleMapping{
+		ExternalGroupName: spec.ExternalGroupName,
+		RoleAssignments:   &assignments,
+	}
+}
+
+// ConvertRoleMappingFromAtlas converts an Atlas role mapping to our RoleMappingSpec.
+// Role assignments are sorted so that specs can be compared independent of API ordering.
+func ConvertRoleMappingFromAtlas(mapping admin.AuthFederationRoleMapping, orgID string) types.RoleMappingSpec {
+	spec := types.RoleMappingSpec{
+		OrgID:             orgID,
+		ExternalGroupName: mapping.ExternalGroupName,
+	}
+	for _, ra := range mapping.GetRoleAssignments() {
+		spec.RoleAssignments = append(spec.RoleAssignments, types.RoleAssignmentConfig{
+			Role:      ra.GetRole(),
+			OrgID:     ra.GetOrgId(),
+			ProjectID: ra.GetGroupId(),
+		})
+	}
+	SortRoleAssignments(spec.RoleAssignments)
+	return spec
+}
+
+// SortRoleAssignments orders role assignments by project, organization and role.
+func SortRoleAssignments(assignments []types.RoleAssignmentConfig) {
+	sort.SliceStable(assignments, func(i, j int) bool {
+		a, b := assignments[i], assignments[j]
+		if a.ProjectID != b.ProjectID {
+			return a.ProjectID < b.ProjectID
+		}
+		if a.OrgID != b.OrgID {
+			return a.OrgID < b.OrgID
+		}
+		return a.Role < b.Role
+	})
+}
+
+func identityProviderType(idpType string) string {
+	if idpType == "" {
+		return "WORKFORCE"
+	}
+	return strings.ToUpper(idpType)
+}
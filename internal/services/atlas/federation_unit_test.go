@@ -0,0 +1,90 @@
+package atlas
+
+import (
+	"context"
+	"testing"
+
+	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func TestFederationService_Validation(t *testing.T) {
+	service := NewFederationService(&atlasclient.Client{})
+	ctx := context.Background()
+
+	if _, err := service.GetSettings(ctx, ""); err == nil {
+		t.Fatal("expected error for empty orgID")
+	}
+	if _, err := service.ListIdentityProviders(ctx, ""); err == nil {
+		t.Fatal("expected error for empty federationSettingsID")
+	}
+	if _, err := service.CreateIdentityProvider(ctx, "fed123", nil); err == nil {
+		t.Fatal("expected error for nil identity provider")
+	}
+	if _, err := service.ListRoleMappings(ctx, "fed123", ""); err == nil {
+		t.Fatal("expected error for empty orgID")
+	}
+	if _, err := service.CreateRoleMapping(ctx, "fed123", &types.RoleMappingSpec{}); err == nil {
+		t.Fatal("expected error for spec without orgId")
+	}
+	if err := service.DeleteRoleMapping(ctx, "fed123", "", "org123"); err == nil {
+		t.Fatal("expected error for empty mappingID")
+	}
+}
+
+func TestConvertRoleMapping_RoundTrip(t *testing.T) {
+	spec := &types.RoleMappingSpec{
+		OrgID:             "5f1d7f4b3a1e2c0012345678",
+		ExternalGroupName: "okta-dbas",
+		RoleAssignments: []types.RoleAssignmentConfig{
+			{Role: "GROUP_OWNER", ProjectID: "6a1d7f4b3a1e2c0012345678"},
+			{Role: "ORG_MEMBER"},
+		},
+	}
+
+	mapping := ConvertRoleMappingToAtlas(spec)
+	assignments := mapping.GetRoleAssignments()
+	if len(assignments) != 2 {
+		t.Fatalf("expected 2 role assignments, got %d", len(assignments))
+	}
+	if assignments[0].GetGroupId() != "6a1d7f4b3a1e2c0012345678" || assignments[0].OrgId != nil {
+		t.Fatalf("project role should only set groupId: %+v", assignments[0])
+	}
+	if assignments[1].GetOrgId() != spec.OrgID {
+		t.Fatalf("organization role should default to mapping orgId, got %q", assignments[1].GetOrgId())
+	}
+
+	back := ConvertRoleMappingFromAtlas(*mapping, spec.OrgID)
+	if back.ExternalGroupName != spec.ExternalGroupName || len(back.RoleAssignments) != 2 {
+		t.Fatalf("unexpected round trip result: %+v", back)
+	}
+	// Sorted: organization role (no project) first
+	if back.RoleAssignments[0].Role != "ORG_MEMBER" || back.RoleAssignments[1].ProjectID == "" {
+		t.Fatalf("role assignments not sorted: %+v", back.RoleAssignments)
+	}
+}
+
+func TestConvertConnectedOrgToAtlas(t *testing.T) {
+	settings := &types.ConnectedOrgSettings{
+		DataAccessIdentityProviders: []string{"Okta Workforce"},
+		DomainRestrictionEnabled:    true,
+		DomainAllowList:             []string{"example.com"},
+	}
+
+	config, err := ConvertConnectedOrgToAtlas("org123", settings, map[string]string{"Okta Workforce": "idp1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := config.GetDataAccessIdentityProviderIds(); len(ids) != 1 || ids[0] != "idp1" {
+		t.Fatalf("expected data access provider idp1, got %v", ids)
+	}
+
+	back := ConvertConnectedOrgFromAtlas(config, map[string]string{"idp1": "Okta Workforce"})
+	if len(back.DataAccessIdentityProviders) != 1 || back.DataAccessIdentityProviders[0] != "Okta Workforce" {
+		t.Fatalf("expected provider display name, got %v", back.DataAccessIdentityProviders)
+	}
+
+	if _, err := ConvertConnectedOrgToAtlas("org123", settings, map[string]string{}); err == nil {
+		t.Fatal("expected error for unknown identity provider")
+	}
+}
@@ -0,0 +1,216 @@
+package atlas
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	"github.com/teabranch/matlas-cli/internal/security"
+	"github.com/teabranch/matlas-cli/internal/types"
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+)
+
+// Supported third-party integration types.
+const (
+	IntegrationTypeDatadog        = "DATADOG"
+	IntegrationTypePagerDuty      = "PAGER_DUTY"
+	IntegrationTypeSlack          = "SLACK"
+	IntegrationTypeMicrosoftTeams = "MICROSOFT_TEAMS"
+	IntegrationTypePrometheus     = "PROMETHEUS"
+	IntegrationTypeWebhook        = "WEBHOOK"
+	IntegrationTypeOpsGenie       = "OPS_GENIE"
+	IntegrationTypeVictorOps      = "VICTOR_OPS"
+)
+
+// SupportedIntegrationTypes lists the integration types manageable through matlas.
+var SupportedIntegrationTypes = []string{
+	IntegrationTypeDatadog,
+	IntegrationTypePagerDuty,
+	IntegrationTypeSlack,
+	IntegrationTypeMicrosoftTeams,
+	IntegrationTypePrometheus,
+	IntegrationTypeWebhook,
+	IntegrationTypeOpsGenie,
+	IntegrationTypeVictorOps,
+}
+
+// IntegrationsService manages project-level third-party service integrations.
+type IntegrationsService struct {
+	client *atlasclient.Client
+}
+
+// NewIntegrationsService creates a new IntegrationsService.
+func NewIntegrationsService(client *atlasclient.Client) *IntegrationsService {
+	return &IntegrationsService{client: client}
+}
+
+// List returns all third-party integrations configured for the specified project.
+func (s *IntegrationsService) List(ctx context.Context, projectID string) ([]admin.ThirdPartyIntegration, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("projectID required")
+	}
+	var integrations []admin.ThirdPartyIntegration
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.ThirdPartyIntegrationsApi.ListGroupIntegrations(ctx, projectID).Execute()
+		if err != nil {
+			return err
+		}
+		integrations = resp.GetResults()
+		return nil
+	})
+	return integrations, err
+}
+
+// Get returns the integration of the given type for the specified project.
+func (s *IntegrationsService) Get(ctx context.Context, projectID, integrationType string) (*admin.ThirdPartyIntegration, error) {
+	if projectID == "" || integrationType == "" {
+		return nil, fmt.Errorf("projectID and integrationType are required")
+	}
+	var integration *admin.ThirdPartyIntegration
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.ThirdPartyIntegrationsApi.GetGroupIntegration(ctx, projectID, integrationType).Execute()
+		if err != nil {
+			return err
+		}
+		integration = resp
+		return nil
+	})
+	return integration, err
+}
+
+// Create configures a new third-party integration for the specified project.
+func (s *IntegrationsService) Create(ctx context.Context, projectID string, spec *types.IntegrationSpec) error {
+	if projectID == "" || spec == nil {
+		return fmt.Errorf("projectID and spec are required")
+	}
+	if spec.Type == "" {
+		return fmt.Errorf("integration type required")
+	}
+	request := ConvertIntegrationToAtlas(spec)
+	return s.client.Do(ctx, func(api *admin.APIClient) error {
+		_, _, err := api.ThirdPartyIntegrationsApi.CreateGroupIntegration(ctx, spec.Type, projectID, request).Execute()
+		return err
+	})
+}
+
+// Update replaces the settings of an existing third-party integration.
+func (s *IntegrationsService) Update(ctx context.Context, projectID string, spec *types.IntegrationSpec) error {
+	if projectID == "" || spec == nil {
+		return fmt.Errorf("projectID and spec are required")
+	}
+	if spec.Type == "" {
+		return fmt.Errorf("integration type required")
+	}
+	request := ConvertIntegrationToAtlas(spec)
+	return s.client.Do(ctx, func(api *admin.APIClient) error {
+		_, _, err := api.ThirdPartyIntegrationsApi.UpdateGroupIntegration(ctx, spec.Type, projectID, request).Execute()
+		return err
+	})
+}
+
+// Delete removes the integration of the given type from the specified project.
+func (s *IntegrationsService) Delete(ctx context.Context, projectID, integrationType string) error {
+	if projectID == "" || integrationType == "" {
+		return fmt.Errorf("projectID and integrationType are required")
+	}
+	return s.client.Do(ctx, func(api *admin.APIClient) error {
+		_, err := api.ThirdPartyIntegrationsApi.DeleteGroupIntegration(ctx, integrationType, projectID).Execute()
+		return err
+	})
+}
+
+// NormalizeIntegrationType upper-cases an integration type and accepts dashes
+// (e.g. "pager-duty") in place of underscores.
+func NormalizeIntegrationType(integrationType string) string {
+	return strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(integrationType)), "-", "_")
+}
+
+// ConvertIntegrationToAtlas converts our IntegrationSpec to an Atlas ThirdPartyIntegration.
+// Only the fields relevant to the integration type are sent.
+func ConvertIntegrationToAtlas(spec *types.IntegrationSpec) *admin.ThirdPartyIntegration {
+	integration := &admin.ThirdPartyIntegration{Type: admin.PtrString(spec.Type)}
+	switch spec.Type {
+	case IntegrationTypeDatadog:
+		integration.ApiKey = optionalString(spec.APIKey)
+		integration.Region = optionalString(spec.Region)
+		integration.SendCollectionLatencyMetrics = spec.SendCollectionLatencyMetrics
+		integration.SendDatabaseMetrics = spec.SendDatabaseMetrics
+	case IntegrationTypeOpsGenie:
+		integration.ApiKey = optionalString(spec.APIKey)
+		integration.Region = optionalString(spec.Region)
+	case IntegrationTypePagerDuty:
+		integration.ServiceKey = optionalString(spec.ServiceKey)
+		integration.Region = optionalString(spec.Region)
+	case IntegrationTypeSlack:
+		integration.ApiToken = optionalString(spec.APIToken)
+		integration.ChannelName = optionalString(spec.ChannelName)
+		integration.TeamName = optionalString(spec.TeamName)
+	case IntegrationTypeVictorOps:
+		integration.ApiKey = optionalString(spec.APIKey)
+		integration.RoutingKey = optionalString(spec.RoutingKey)
+	case IntegrationTypeMicrosoftTeams:
+		integration.MicrosoftTeamsWebhookUrl = optionalString(spec.MicrosoftTeamsWebhookURL)
+	case IntegrationTypeWebhook:
+		integration.Url = optionalString(spec.URL)
+		integration.Secret = optionalString(spec.Secret)
+	case IntegrationTypePrometheus:
+		integration.Username = optionalString(spec.Username)
+		integration.Password = optionalString(spec.Password)
+		integration.ServiceDiscovery = optionalString(spec.ServiceDiscovery)
+		enabled := true
+		if spec.Enabled != nil {
+			enabled = *spec.Enabled
+		}
+		integration.Enabled = admin.PtrBool(enabled)
+	}
+	return integration
+}
+
+// ConvertIntegrationFromAtlas converts an Atlas ThirdPartyIntegration to our IntegrationSpec.
+// Atlas returns credentials redacted, so the secret fields carry the redacted values.
+func ConvertIntegrationFromAtlas(integration admin.ThirdPartyIntegration, projectName string) *types.IntegrationSpec {
+	return &types.IntegrationSpec{
+		ProjectName:                  projectName,
+		Type:                         integration.GetType(),
+		APIKey:                       integration.GetApiKey(),
+		Region:                       integration.GetRegion(),
+		SendCollectionLatencyMetrics: integration.SendCollectionLatencyMetrics,
+		SendDatabaseMetrics:          integration.SendDatabaseMetrics,
+		ServiceKey:                   integration.GetServiceKey(),
+		APIToken:                     integration.GetApiToken(),
+		ChannelName:                  integration.GetChannelName(),
+		TeamName:                     integration.GetTeamName(),
+		RoutingKey:                   integration.GetRoutingKey(),
+		MicrosoftTeamsWebhookURL:     integration.GetMicrosoftTeamsWebhookUrl(),
+		URL:                          integration.GetUrl(),
+		Secret:                       integration.GetSecret(),
+		Username:                     integration.GetUsername(),
+		ServiceDiscovery:             integration.GetServiceDiscovery(),
+		Enabled:                      integration.Enabled,
+	}
+}
+
+// MaskIntegrationSecrets returns a copy of the spec with every credential masked for display
+func MaskIntegrationSecrets(spec *types.IntegrationSpec) *types.IntegrationSpec {
+	if spec == nil {
+		return nil
+	}
+	masked := *spec
+	for _, field := range []*string{
+		&masked.APIKey, &masked.ServiceKey, &masked.APIToken, &masked.RoutingKey,
+		&masked.MicrosoftTeamsWebhookURL, &masked.URL, &masked.Secret, &masked.Password,
+	} {
+		if *field != "" {
+			*field = security.MaskValue(*field).(string)
+		}
+	}
+	return &masked
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return admin.PtrString(value)
+}
@@ -0,0 +1,199 @@
+package atlas
+
+import (
+	"context"
+	"fmt"
+
+	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	"github.com/teabranch/matlas-cli/internal/types"
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+)
+
+// defaultLDAPPort is the port Atlas uses when an LDAP configuration does not specify one.
+const defaultLDAPPort = 636
+
+// LDAPConfigurationService manages the project-level LDAP configuration (userSecurity).
+type LDAPConfigurationService struct {
+	client *atlasclient.Client
+}
+
+// NewLDAPConfigurationService creates a new LDAPConfigurationService.
+func NewLDAPConfigurationService(client *atlasclient.Client) *LDAPConfigurationService {
+	return &LDAPConfigurationService{client: client}
+}
+
+// Get returns the current LDAP configuration for the specified project.
+func (s *LDAPConfigurationService) Get(ctx context.Context, projectID string) (*admin.UserSecurity, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("projectID required")
+	}
+	var security *admin.UserSecurity
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.LDAPConfigurationApi.GetUserSecurity(ctx, projectID).Execute()
+		if err != nil {
+			return err
+		}
+		security = resp
+		return nil
+	})
+	return security, err
+}
+
+// Update applies the LDAP configuration for the specified project.
+func (s *LDAPConfigurationService) Update(ctx context.Context, projectID string, spec *types.LDAPConfigurationSpec) (*admin.UserSecurity, error) {
+	if projectID == "" || spec == nil {
+		return nil, fmt.Errorf("projectID and spec are required")
+	}
+	request := &admin.UserSecurity{Ldap: ConvertLDAPSpecToAtlas(spec)}
+
+	var updated *admin.UserSecurity
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.LDAPConfigurationApi.UpdateUserSecurity(ctx, projectID, request).Execute()
+		if err != nil {
+			return err
+		}
+		updated = resp
+		return nil
+	})
+	return updated, err
+}
+
+// Disable turns off LDAP authentication and authorization and removes the user-to-DN mapping.
+func (s *LDAPConfigurationService) Disable(ctx context.Context, projectID string) error {
+	if projectID == "" {
+		return fmt.Errorf("projectID required")
+	}
+	return s.client.Do(ctx, func(api *admin.APIClient) error {
+		if _, _, err := api.LDAPConfigurationApi.DeleteLdapUserMapping(ctx, projectID).Execute(); err != nil {
+			return err
+		}
+		request := &admin.UserSecurity{Ldap: &admin.LDAPSecuritySettings{
+			AuthenticationEnabled: admin.PtrBool(false),
+			AuthorizationEnabled:  admin.PtrBool(false),
+		}}
+		_, _, err := api.LDAPConfigurationApi.UpdateUserSecurity(ctx, projectID, request).Execute()
+		return err
+	})
+}
+
+// Verify starts an LDAP connectivity verification job and returns its request ID.
+func (s *LDAPConfigurationService) Verify(ctx context.Context, projectID string, spec *types.LDAPConfigurationSpec) (*admin.LDAPVerifyConnectivityJobRequest, error) {
+	if projectID == "" || spec == nil {
+		return nil, fmt.Errorf("projectID and spec are required")
+	}
+	params := &admin.LDAPVerifyConnectivityJobRequestParams{
+		BindUsername: spec.BindUsername,
+		BindPassword: spec.BindPassword,
+		Hostname:     spec.Hostname,
+		Port:         ldapPort(spec.Port),
+	}
+	if spec.CACertificate != "" {
+		params.CaCertificate = admin.PtrString(spec.CACertificate)
+	}
+	if spec.AuthzQueryTemplate != "" {
+		params.AuthzQueryTemplate = admin.PtrString(spec.AuthzQueryTemplate)
+	}
+
+	var job *admin.LDAPVerifyConnectivityJobRequest
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.LDAPConfigurationApi.VerifyUserSecurityLdap(ctx, projectID, params).Execute()
+		if err != nil {
+			return err
+		}
+		job = resp
+		return nil
+	})
+	return job, err
+}
+
+// GetVerifyStatus returns the status of an LDAP connectivity verification job.
+func (s *LDAPConfigurationService) GetVerifyStatus(ctx context.Context, projectID, requestID string) (*admin.LDAPVerifyConnectivityJobRequest, error) {
+	if projectID == "" || requestID == "" {
+		return nil, fmt.Errorf("projectID and requestID are required")
+	}
+	var job *admin.LDAPVerifyConnectivityJobRequest
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.LDAPConfigurationApi.GetUserSecurityVerify(ctx, projectID, requestID).Execute()
+		if err != nil {
+			return err
+		}
+		job = resp
+		return nil
+	})
+	return job, err
+}
+
+// ConvertLDAPSpecToAtlas converts our LDAPConfigurationSpec to Atlas LDAPSecuritySettings.
+func ConvertLDAPSpecToAtlas(spec *types.LDAPConfigurationSpec) *admin.LDAPSecuritySettings {
+	settings := &admin.LDAPSecuritySettings{
+		Hostname:              admin.PtrString(spec.Hostname),
+		Port:                  admin.PtrInt(ldapPort(spec.Port)),
+		BindUsername:          admin.PtrString(spec.BindUsername),
+		AuthenticationEnabled: admin.PtrBool(true),
+	}
+	if spec.AuthenticationEnabled != nil {
+		settings.AuthenticationEnabled = spec.AuthenticationEnabled
+	}
+	if spec.AuthorizationEnabled != nil {
+		settings.AuthorizationEnabled = spec.AuthorizationEnabled
+	}
+	if spec.BindPassword != "" {
+		settings.BindPassword = admin.PtrString(spec.BindPassword)
+	}
+	if spec.CACertificate != "" {
+		settings.CaCertificate = admin.PtrString(spec.CACertificate)
+	}
+	if spec.AuthzQueryTemplate != "" {
+		settings.AuthzQueryTemplate = admin.PtrString(spec.AuthzQueryTemplate)
+	}
+	// Without declared mappings the field is left out, so an update keeps those configured
+	if len(spec.UserToDNMapping) == 0 {
+		return settings
+	}
+	mappings := make([]admin.UserToDNMapping, 0, len(spec.UserToDNMapping))
+	for _, m := range spec.UserToDNMapping {
+		mapping := admin.UserToDNMapping{Match: m.Match}
+		if m.Substitution != "" {
+			mapping.Substitution = admin.PtrString(m.Substitution)
+		}
+		if m.LDAPQuery != "" {
+			mapping.LdapQuery = admin.PtrString(m.LDAPQuery)
+		}
+		mappings = append(mappings, mapping)
+	}
+	settings.UserToDNMapping = &mappings
+	return settings
+}
+
+// ConvertLDAPFromAtlas converts Atlas LDAPSecuritySettings to our LDAPConfigurationSpec.
+// The bind password is never returned by Atlas and is left empty.
+func ConvertLDAPFromAtlas(settings *admin.LDAPSecuritySettings, projectName string) *types.LDAPConfigurationSpec {
+	if settings == nil {
+		return nil
+	}
+	spec := &types.LDAPConfigurationSpec{
+		ProjectName:           projectName,
+		Hostname:              settings.GetHostname(),
+		Port:                  settings.GetPort(),
+		BindUsername:          settings.GetBindUsername(),
+		CACertificate:         settings.GetCaCertificate(),
+		AuthenticationEnabled: settings.AuthenticationEnabled,
+		AuthorizationEnabled:  settings.AuthorizationEnabled,
+		AuthzQueryTemplate:    settings.GetAuthzQueryTemplate(),
+	}
+	for _, m := range settings.GetUserToDNMapping() {
+		spec.UserToDNMapping = append(spec.UserToDNMapping, types.UserToDNMappingConfig{
+			Match:        m.GetMatch(),
+			Substitution: m.GetSubstitution(),
+			LDAPQuery:    m.GetLdapQuery(),
+		})
+	}
+	return spec
+}
+
+func ldapPort(port int) int {
+	if port == 0 {
+		return defaultLDAPPort
+	}
+	return port
+}
@@ -0,0 +1,83 @@
+package atlas
+
+import (
+	"context"
+	"testing"
+
+	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func TestLDAPConfigurationService_Validation(t *testing.T) {
+	service := NewLDAPConfigurationService(&atlasclient.Client{})
+	ctx := context.Background()
+
+	if _, err := service.Get(ctx, ""); err == nil {
+		t.Fatal("expected error for empty projectID")
+	}
+	if _, err := service.Update(ctx, "proj123", nil); err == nil {
+		t.Fatal("expected error for nil spec")
+	}
+	if err := service.Disable(ctx, ""); err == nil {
+		t.Fatal("expected error for empty projectID")
+	}
+	if _, err := service.GetVerifyStatus(ctx, "proj123", ""); err == nil {
+		t.Fatal("expected error for empty requestID")
+	}
+}
+
+func TestConvertLDAPSpec_RoundTrip(t *testing.T) {
+	authz := true
+	spec := &types.LDAPConfigurationSpec{
+		ProjectName:          "my-project",
+		Hostname:             "ldap.example.com",
+		BindUsername:         "CN=svc,DC=example,DC=com",
+		BindPassword:         "secret",
+		AuthorizationEnabled: &authz,
+		AuthzQueryTemplate:   "{USER}?memberOf?base",
+		UserToDNMapping: []types.UserToDNMappingConfig{
+			{Match: "(.+)@example.com", Substitution: "CN={0},DC=example,DC=com"},
+		},
+	}
+
+	settings := ConvertLDAPSpecToAtlas(spec)
+	if settings.GetPort() != 636 {
+		t.Fatalf("expected default port 636, got %d", settings.GetPort())
+	}
+	if !settings.GetAuthenticationEnabled() {
+		t.Fatal("expected authentication to be enabled by default")
+	}
+
+	back := ConvertLDAPFromAtlas(settings, "my-project")
+	if back.Hostname != spec.Hostname || back.BindUsername != spec.BindUsername {
+		t.Fatalf("unexpected round trip result: %+v", back)
+	}
+	if len(back.UserToDNMapping) != 1 || back.UserToDNMapping[0].Substitution != spec.UserToDNMapping[0].Substitution {
+		t.Fatalf("user to DN mapping not preserved: %+v", back.UserToDNMapping)
+	}
+}
+
+func TestConvertLDAPSpecToAtlas_WithoutMappings(t *testing.T) {
+	settings := ConvertLDAPSpecToAtlas(&types.LDAPConfigurationSpec{Hostname: "ldap.example.com", BindUsername: "CN=svc,DC=example,DC=com"})
+	if settings.UserToDNMapping != nil {
+		t.Fatalf("undeclared mappings must be left out so updates keep those configured, got %+v", *settings.UserToDNMapping)
+	}
+}
+
+func TestX509AuthenticationService_Validation(t *testing.T) {
+	service := NewX509AuthenticationService(&atlasclient.Client{})
+	ctx := context.Background()
+
+	if _, err := service.CreateUserCertificate(ctx, "", "user", 3); err == nil {
+		t.Fatal("expected error for empty projectID")
+	}
+	if _, err := service.CreateUserCertificate(ctx, "proj123", "user", 0); err == nil {
+		t.Fatal("expected error for zero months")
+	}
+	if _, err := service.CreateUserCertificate(ctx, "proj123", "user", MaxX509CertificateMonths+1); err == nil {
+		t.Fatal("expected error for months above the maximum")
+	}
+	if _, err := service.ListUserCertificates(ctx, "proj123", ""); err == nil {
+		t.Fatal("expected error for empty username")
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
 	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
 )
 
+// MaxNetworkAccessTTL is the longest lifetime Atlas accepts for a temporary access list entry.
+const MaxNetworkAccessTTL = 7 * 24 * time.Hour
+
 // NetworkAccessListsService provides CRUD operations for Atlas IP Access Lists (network access control).
 type NetworkAccessListsService struct {
 	client *atlasclient.Client
@@ -114,6 +119,9 @@ func (s *NetworkAccessListsService) validateIPEntry(entry admin.NetworkPermissio
 	} else {
 		return fmt.Errorf("entry must specify ipAddress, cidrBlock, or awsSecurityGroup")
 	}
+	if entry.DeleteAfterDate != nil && !entry.DeleteAfterDate.After(time.Now()) {
+		return fmt.Errorf("deleteAfterDate %s is in the past", entry.DeleteAfterDate.Format(time.RFC3339))
+	}
 	return nil
 }
 
@@ -133,3 +141,63 @@ func ValidateCIDR(cidr string) error {
 	}
 	return nil
 }
+
+// ParseNetworkAccessTTL parses the lifetime of a temporary access list entry. It accepts Go
+// durations ("8h", "90m") and whole days ("2d"), and rejects values above MaxNetworkAccessTTL.
+func ParseNetworkAccessTTL(ttl string) (time.Duration, error) {
+	ttl = strings.TrimSpace(ttl)
+	var d time.Duration
+	if days, ok := strings.CutSuffix(ttl, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ttl %q: use a duration such as 8h, 90m or 2d", ttl)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ttl %q: use a duration such as 8h, 90m or 2d", ttl)
+		}
+		d = parsed
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("ttl must be positive")
+	}
+	if d > MaxNetworkAccessTTL {
+		return 0, fmt.Errorf("ttl %s exceeds the maximum of %s", ttl, MaxNetworkAccessTTL)
+	}
+	return d, nil
+}
+
+// ResolveNetworkAccessExpiry returns when an access list entry expires, given its deleteAfterDate,
+// the expiresAt alias (both RFC3339) or a ttl counted from now. At most one may be set.
+// A nil time means the entry is permanent.
+func ResolveNetworkAccessExpiry(deleteAfterDate, expiresAt, ttl string, now time.Time) (*time.Time, error) {
+	set := 0
+	for _, v := range []string{deleteAfterDate, expiresAt, ttl} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("only one of deleteAfterDate, expiresAt or ttl can be specified")
+	}
+
+	switch {
+	case ttl != "":
+		d, err := ParseNetworkAccessTTL(ttl)
+		if err != nil {
+			return nil, err
+		}
+		expiry := now.Add(d).UTC().Truncate(time.Second)
+		return &expiry, nil
+	case deleteAfterDate != "" || expiresAt != "":
+		value := deleteAfterDate + expiresAt
+		expiry, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry %q (must be RFC3339): %w", value, err)
+		}
+		return &expiry, nil
+	}
+	return nil, nil
+}
@@ -100,6 +100,59 @@ func TestNetworkAccessListsService_ValidationHelpers(t *testing.T) {
 	}
 }
 
+func TestParseNetworkAccessTTL(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"8h", 8 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"2d", 48 * time.Hour, false},
+		{"7d", MaxNetworkAccessTTL, false},
+		{"8d", 0, true},
+		{"0s", 0, true},
+		{"-1h", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseNetworkAccessTTL(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNetworkAccessTTL(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNetworkAccessTTL(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNetworkAccessExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	expiry, err := ResolveNetworkAccessExpiry("", "", "4h", now)
+	if err != nil || expiry == nil || !expiry.Equal(now.Add(4*time.Hour)) {
+		t.Fatalf("ttl expiry = %v, %v", expiry, err)
+	}
+
+	expiry, err = ResolveNetworkAccessExpiry("", "2026-10-19T00:00:00Z", "", now)
+	if err != nil || expiry == nil || expiry.Format(time.RFC3339) != "2026-10-19T00:00:00Z" {
+		t.Fatalf("expiresAt expiry = %v, %v", expiry, err)
+	}
+
+	if expiry, err := ResolveNetworkAccessExpiry("", "", "", now); err != nil || expiry != nil {
+		t.Fatalf("permanent entry should have no expiry, got %v, %v", expiry, err)
+	}
+	if _, err := ResolveNetworkAccessExpiry("2026-10-19T00:00:00Z", "", "4h", now); err == nil {
+		t.Fatal("expected error when both deleteAfterDate and ttl are set")
+	}
+	if _, err := ResolveNetworkAccessExpiry("tomorrow", "", "", now); err == nil {
+		t.Fatal("expected error for non-RFC3339 date")
+	}
+}
+
 // Error scenario tests for network access
 
 func TestNetworkAccessListsService_Create_DuplicateEntry(t *testing.T) {
@@ -0,0 +1,167 @@
+package atlas
+
+import (
+	"net"
+	"time"
+
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+)
+
+// Network access audit finding codes.
+const (
+	NetworkAuditUnused      = "UNUSED"
+	NetworkAuditOpenToWorld = "OPEN_TO_WORLD"
+	NetworkAuditBroadCIDR   = "BROAD_CIDR"
+	NetworkAuditRedundant   = "REDUNDANT"
+)
+
+// DefaultAuditMinPrefixLength flags IPv4 CIDRs wider than a /16 as overly broad.
+const DefaultAuditMinPrefixLength = 16
+
+// NetworkAccessAuditOptions controls how access list entries are audited.
+type NetworkAccessAuditOptions struct {
+	// Since is the start of the usage window; older connections are ignored.
+	Since time.Time
+	// MinPrefixLength is the narrowest prefix an IPv4 CIDR may have before it is flagged as broad.
+	MinPrefixLength int
+	// HistoryAvailable is true only when every cluster in the project returned access history.
+	// Otherwise connection counts are partial and entries are not reported as unused, since a
+	// cluster without history may still be reached through them.
+	HistoryAvailable bool
+}
+
+// NetworkAccessAuditEntry is the audit result for one access list entry.
+type NetworkAccessAuditEntry struct {
+	Entry       string     `json:"entry" yaml:"entry"`
+	Comment     string     `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Connections int        `json:"connections" yaml:"connections"`
+	LastSeen    *time.Time `json:"lastSeen,omitempty" yaml:"lastSeen,omitempty"`
+	Findings    []string   `json:"findings,omitempty" yaml:"findings,omitempty"`
+	CoveredBy   string     `json:"coveredBy,omitempty" yaml:"coveredBy,omitempty"`
+	// Partial is set when some clusters had no access history, so usage is incomplete
+	Partial bool `json:"partial,omitempty" yaml:"partial,omitempty"`
+}
+
+// auditNetwork is an access list entry parsed into a network; nil for AWS security groups.
+type auditNetwork struct {
+	entry   admin.NetworkPermissionEntry
+	name    string
+	network *net.IPNet
+}
+
+// AuditNetworkAccess cross-references access list entries with database access logs. It reports
+// per entry how many successful connections came from it and flags entries that saw no
+// connections in the window, are open to the world, are broader than the allowed prefix, or are
+// already covered by a wider CIDR. AWS security group entries are never matched against logs.
+func AuditNetworkAccess(entries []admin.NetworkPermissionEntry, logs []admin.MongoDBAccessLogs, opts NetworkAccessAuditOptions) []NetworkAccessAuditEntry {
+	if opts.MinPrefixLength <= 0 {
+		opts.MinPrefixLength = DefaultAuditMinPrefixLength
+	}
+
+	networks := make([]auditNetwork, len(entries))
+	for i, entry := range entries {
+		networks[i] = parseAuditNetwork(entry)
+	}
+
+	results := make([]NetworkAccessAuditEntry, len(entries))
+	for i, n := range networks {
+		results[i] = NetworkAccessAuditEntry{Entry: n.name, Comment: n.entry.GetComment(), Partial: !opts.HistoryAvailable}
+	}
+
+	// Attribute each successful connection to every entry that admits its source address
+	for _, log := range logs {
+		ip := net.ParseIP(log.GetIpAddress())
+		if ip == nil {
+			continue
+		}
+		seen, ok := parseAccessLogTime(log.GetTimestamp())
+		if ok && !opts.Since.IsZero() && seen.Before(opts.Since) {
+			continue
+		}
+		for i, n := range networks {
+			if n.network == nil || !n.network.Contains(ip) {
+				continue
+			}
+			results[i].Connections++
+			if ok && (results[i].LastSeen == nil || seen.After(*results[i].LastSeen)) {
+				t := seen
+				results[i].LastSeen = &t
+			}
+		}
+	}
+
+	for i, n := range networks {
+		if n.network == nil {
+			continue
+		}
+		ones, bits := n.network.Mask.Size()
+		switch {
+		case ones == 0:
+			results[i].Findings = append(results[i].Findings, NetworkAuditOpenToWorld)
+		case bits == net.IPv4len*8 && ones < opts.MinPrefixLength:
+			results[i].Findings = append(results[i].Findings, NetworkAuditBroadCIDR)
+		}
+		if cover := widestCover(networks, i); cover != "" {
+			results[i].Findings = append(results[i].Findings, NetworkAuditRedundant)
+			results[i].CoveredBy = cover
+		}
+		if opts.HistoryAvailable && results[i].Connections == 0 {
+			results[i].Findings = append(results[i].Findings, NetworkAuditUnused)
+		}
+	}
+	return results
+}
+
+func parseAuditNetwork(entry admin.NetworkPermissionEntry) auditNetwork {
+	n := auditNetwork{entry: entry}
+	switch {
+	case entry.GetCidrBlock() != "":
+		n.name = entry.GetCidrBlock()
+		if _, network, err := net.ParseCIDR(n.name); err == nil {
+			n.network = network
+		}
+	case entry.GetIpAddress() != "":
+		n.name = entry.GetIpAddress()
+		if ip := net.ParseIP(n.name); ip != nil {
+			bits := net.IPv6len * 8
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = net.IPv4len * 8
+			}
+			n.network = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		}
+	default:
+		n.name = entry.GetAwsSecurityGroup()
+	}
+	return n
+}
+
+// widestCover returns the widest other entry whose network strictly contains entry i's network
+func widestCover(networks []auditNetwork, i int) string {
+	target := networks[i].network
+	targetOnes, targetBits := target.Mask.Size()
+	cover, coverOnes := "", targetOnes
+	for j, n := range networks {
+		if j == i || n.network == nil {
+			continue
+		}
+		ones, bits := n.network.Mask.Size()
+		if bits != targetBits || ones >= coverOnes || !n.network.Contains(target.IP) {
+			continue
+		}
+		cover, coverOnes = n.name, ones
+	}
+	return cover
+}
+
+// accessLogTimeLayouts are the timestamp formats seen in access history responses
+var accessLogTimeLayouts = []string{time.RFC3339Nano, "Mon Jan 02 15:04:05 MST 2006", time.UnixDate}
+
+func parseAccessLogTime(value string) (time.Time, bool) {
+	for _, layout := range accessLogTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
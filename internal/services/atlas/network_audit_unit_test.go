@@ -0,0 +1,85 @@
+package atlas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+)
+
+func TestAccessTrackingService_Validation(t *testing.T) {
+	service := NewAccessTrackingService(&atlasclient.Client{})
+	if _, err := service.ListClusterAccessLogs(context.Background(), "proj", "", time.Time{}); err == nil {
+		t.Fatal("expected error for empty cluster name")
+	}
+}
+
+func TestAuditNetworkAccess(t *testing.T) {
+	since := time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC)
+	entries := []admin.NetworkPermissionEntry{
+		{CidrBlock: admin.PtrString("10.0.0.0/8"), Comment: admin.PtrString("legacy vpn")},
+		{CidrBlock: admin.PtrString("10.1.0.0/24")},
+		{IpAddress: admin.PtrString("203.0.113.10")},
+		{CidrBlock: admin.PtrString("0.0.0.0/0")},
+		{IpAddress: admin.PtrString("198.51.100.7")},
+		{AwsSecurityGroup: admin.PtrString("sg-0abc123def45")},
+	}
+	logs := []admin.MongoDBAccessLogs{
+		{IpAddress: admin.PtrString("10.1.0.5"), Timestamp: admin.PtrString("2026-10-17T08:00:00Z")},
+		{IpAddress: admin.PtrString("10.1.0.6"), Timestamp: admin.PtrString("Sat Oct 18 07:30:00 GMT 2026")},
+		// Outside the window
+		{IpAddress: admin.PtrString("198.51.100.7"), Timestamp: admin.PtrString("2026-09-01T08:00:00Z")},
+	}
+
+	results := AuditNetworkAccess(entries, logs, NetworkAccessAuditOptions{Since: since, HistoryAvailable: true})
+	byEntry := make(map[string]NetworkAccessAuditEntry)
+	for _, r := range results {
+		byEntry[r.Entry] = r
+	}
+
+	assertFindings := func(entry string, want ...string) {
+		t.Helper()
+		got := byEntry[entry].Findings
+		if len(got) != len(want) {
+			t.Fatalf("%s: findings = %v, want %v", entry, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("%s: findings = %v, want %v", entry, got, want)
+			}
+		}
+	}
+
+	assertFindings("10.0.0.0/8", NetworkAuditBroadCIDR, NetworkAuditRedundant)
+	assertFindings("10.1.0.0/24", NetworkAuditRedundant)
+	assertFindings("203.0.113.10", NetworkAuditRedundant, NetworkAuditUnused)
+	assertFindings("0.0.0.0/0", NetworkAuditOpenToWorld)
+	assertFindings("198.51.100.7", NetworkAuditRedundant, NetworkAuditUnused)
+	assertFindings("sg-0abc123def45")
+
+	if byEntry["10.1.0.0/24"].CoveredBy != "0.0.0.0/0" {
+		t.Errorf("expected the widest covering entry, got %q", byEntry["10.1.0.0/24"].CoveredBy)
+	}
+	vpn := byEntry["10.0.0.0/8"]
+	if vpn.Connections != 2 || vpn.LastSeen == nil || !vpn.LastSeen.Equal(time.Date(2026, 10, 18, 7, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected usage for 10.0.0.0/8: %+v", vpn)
+	}
+	if vpn.Comment != "legacy vpn" {
+		t.Errorf("expected comment to be carried over, got %q", vpn.Comment)
+	}
+
+	if vpn.Partial {
+		t.Errorf("expected complete usage when every cluster has history")
+	}
+
+	// Without access history from every cluster nothing is reported as unused
+	results = AuditNetworkAccess(entries[2:3], nil, NetworkAccessAuditOptions{Since: since})
+	if len(results[0].Findings) != 0 {
+		t.Errorf("expected no findings without history, got %v", results[0].Findings)
+	}
+	if !results[0].Partial {
+		t.Errorf("expected usage to be marked partial without history")
+	}
+}
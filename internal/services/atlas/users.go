@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	"github.com/teabranch/matlas-cli/internal/validation"
 	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
 )
 
@@ -173,3 +174,65 @@ func (s *DatabaseUsersService) Delete(ctx context.Context, projectID, databaseNa
 		return err
 	})
 }
+
+// ApplyAuthType sets the Atlas authentication fields (x509Type, ldapAuthType, awsIAMType,
+// oidcAuthType) for the given matlas authType. An empty authType means SCRAM.
+func ApplyAuthType(user *admin.CloudDatabaseUser, authType string) error {
+	if user == nil {
+		return fmt.Errorf("user is required")
+	}
+	if err := validation.ValidateAuthType(authType); err != nil {
+		return err
+	}
+	switch validation.NormalizeAuthType(authType) {
+	case validation.AuthTypeX509Managed:
+		user.X509Type = admin.PtrString("MANAGED")
+	case validation.AuthTypeX509Customer:
+		user.X509Type = admin.PtrString("CUSTOMER")
+	case validation.AuthTypeLDAPUser:
+		user.LdapAuthType = admin.PtrString("USER")
+	case validation.AuthTypeLDAPGroup:
+		user.LdapAuthType = admin.PtrString("GROUP")
+	case validation.AuthTypeAWSIAMUser:
+		user.AwsIAMType = admin.PtrString("USER")
+	case validation.AuthTypeAWSIAMRole:
+		user.AwsIAMType = admin.PtrString("ROLE")
+	case validation.AuthTypeOIDCWorkforce:
+		user.OidcAuthType = admin.PtrString("IDP_GROUP")
+	case validation.AuthTypeOIDCWorkload:
+		user.OidcAuthType = admin.PtrString("USER")
+	}
+	return nil
+}
+
+// AuthTypeFromUser returns the matlas authType of an Atlas database user.
+func AuthTypeFromUser(user *admin.CloudDatabaseUser) string {
+	if user == nil {
+		return validation.AuthTypeSCRAM
+	}
+	switch user.GetX509Type() {
+	case "MANAGED":
+		return validation.AuthTypeX509Managed
+	case "CUSTOMER":
+		return validation.AuthTypeX509Customer
+	}
+	switch user.GetLdapAuthType() {
+	case "USER":
+		return validation.AuthTypeLDAPUser
+	case "GROUP":
+		return validation.AuthTypeLDAPGroup
+	}
+	switch user.GetAwsIAMType() {
+	case "USER":
+		return validation.AuthTypeAWSIAMUser
+	case "ROLE":
+		return validation.AuthTypeAWSIAMRole
+	}
+	switch user.GetOidcAuthType() {
+	case "IDP_GROUP":
+		return validation.AuthTypeOIDCWorkforce
+	case "USER":
+		return validation.AuthTypeOIDCWorkload
+	}
+	return validation.AuthTypeSCRAM
+}
@@ -291,3 +291,31 @@ func TestDatabaseUsersService_StructBasics(t *testing.T) {
 		t.Fatal("expected username to be 'testuser'")
 	}
 }
+
+func TestApplyAuthType_RoundTrip(t *testing.T) {
+	authTypes := []string{
+		"SCRAM", "X509_MANAGED", "X509_CUSTOMER", "LDAP_USER", "LDAP_GROUP",
+		"AWS_IAM_USER", "AWS_IAM_ROLE", "OIDC_WORKFORCE", "OIDC_WORKLOAD",
+	}
+
+	for _, authType := range authTypes {
+		t.Run(authType, func(t *testing.T) {
+			user := &admin.CloudDatabaseUser{Username: "u", DatabaseName: "admin"}
+			if err := ApplyAuthType(user, authType); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := AuthTypeFromUser(user); got != authType {
+				t.Fatalf("expected %s, got %s", authType, got)
+			}
+		})
+	}
+}
+
+func TestApplyAuthType_Invalid(t *testing.T) {
+	if err := ApplyAuthType(&admin.CloudDatabaseUser{}, "KERBEROS"); err == nil {
+		t.Fatal("expected error for unsupported auth type")
+	}
+	if err := ApplyAuthType(nil, "SCRAM"); err == nil {
+		t.Fatal("expected error for nil user")
+	}
+}
@@ -0,0 +1,64 @@
+package atlas
+
+import (
+	"context"
+	"fmt"
+
+	atlasclient "github.com/teabranch/matlas-cli/internal/clients/atlas"
+	admin "go.mongodb.org/atlas-sdk/v20250312010/admin"
+)
+
+// MaxX509CertificateMonths is the longest validity Atlas allows for a managed user certificate.
+const MaxX509CertificateMonths = 24
+
+// X509AuthenticationService manages Atlas-managed X.509 certificates for database users.
+type X509AuthenticationService struct {
+	client *atlasclient.Client
+}
+
+// NewX509AuthenticationService creates a new X509AuthenticationService.
+func NewX509AuthenticationService(client *atlasclient.Client) *X509AuthenticationService {
+	return &X509AuthenticationService{client: client}
+}
+
+// CreateUserCertificate issues a new Atlas-managed X.509 certificate for a database user
+// and returns it as a PEM bundle (certificate and private key).
+func (s *X509AuthenticationService) CreateUserCertificate(ctx context.Context, projectID, username string, months int) (string, error) {
+	if projectID == "" || username == "" {
+		return "", fmt.Errorf("projectID and username are required")
+	}
+	if months < 1 || months > MaxX509CertificateMonths {
+		return "", fmt.Errorf("months must be between 1 and %d", MaxX509CertificateMonths)
+	}
+	request := &admin.UserCert{MonthsUntilExpiration: admin.PtrInt(months)}
+
+	var pem string
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.X509AuthenticationApi.CreateDatabaseUserCert(ctx, projectID, username, request).Execute()
+		if err != nil {
+			return err
+		}
+		pem = resp
+		return nil
+	})
+	return pem, err
+}
+
+// ListUserCertificates returns the unexpired certificates issued for a database user.
+func (s *X509AuthenticationService) ListUserCertificates(ctx context.Context, projectID, username string) ([]admin.UserCert, error) {
+	if projectID == "" || username == "" {
+		return nil, fmt.Errorf("projectID and username are required")
+	}
+	var certs []admin.UserCert
+	err := s.client.Do(ctx, func(api *admin.APIClient) error {
+		resp, _, err := api.X509AuthenticationApi.ListDatabaseUserCerts(ctx, projectID, username).Execute()
+		if err != nil {
+			return err
+		}
+		if resp != nil && resp.Results != nil {
+			certs = *resp.Results
+		}
+		return nil
+	})
+	return certs, err
+}
@@ -0,0 +1,532 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/yaml.v3"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// Thresholds above which an explain summary warns that the query examines far more
+// documents than it returns
+const (
+	examinedWarningMinimum = 1000
+	examinedWarningRatio   = 10
+)
+
+// AggregateOptions controls how Aggregate runs a pipeline
+type AggregateOptions struct {
+	// AllowDiskUse lets stages such as $sort and $group write temporary files when they
+	// exceed their memory limit
+	AllowDiskUse bool
+	BatchSize    int32
+}
+
+// Aggregate runs a pipeline on a collection and calls fn with each result in order. The
+// document passed to fn is only valid until fn returns. An error returned by fn stops the
+// aggregation. Aggregate returns the number of results read.
+func (ds *DocumentService) Aggregate(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, pipeline bson.A, opts *AggregateOptions, fn func(bson.Raw) error) (int64, error) {
+	if databaseName == "" {
+		return 0, fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return 0, fmt.Errorf("collection name is required")
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return 0, err
+	}
+
+	collection := client.GetUnderlyingClient().Database(databaseName).Collection(collectionName)
+
+	aggregateOptions := options.Aggregate()
+	if opts != nil {
+		if opts.AllowDiskUse {
+			aggregateOptions.SetAllowDiskUse(true)
+		}
+		if opts.BatchSize > 0 {
+			aggregateOptions.SetBatchSize(opts.BatchSize)
+		}
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline, aggregateOptions)
+	if err != nil {
+		return 0, fmt.Errorf("failed to run aggregation: %w", err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var count int64
+	for cursor.Next(ctx) {
+		if err := fn(cursor.Current); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return count, fmt.Errorf("failed to read aggregation results: %w", err)
+	}
+
+	ds.logger.Debug("Ran aggregation",
+		"database", databaseName,
+		"collection", collectionName,
+		"stages", len(pipeline),
+		"results", count)
+
+	return count, nil
+}
+
+// ExplainAggregate returns the explain output of a pipeline with queryPlanner or
+// executionStats verbosity. With executionStats the pipeline runs, but stages such as
+// $out and $merge don't write their results.
+func (ds *DocumentService) ExplainAggregate(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, pipeline bson.A, verbosity string) (bson.M, error) {
+	if databaseName == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return nil, fmt.Errorf("collection name is required")
+	}
+	if verbosity != types.ExplainQueryPlanner && verbosity != types.ExplainExecutionStats {
+		return nil, fmt.Errorf("unsupported explain verbosity %q: use %s or %s", verbosity, types.ExplainQueryPlanner, types.ExplainExecutionStats)
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	command := bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "aggregate", Value: collectionName},
+			{Key: "pipeline", Value: pipeline},
+			{Key: "cursor", Value: bson.D{}},
+		}},
+		{Key: "verbosity", Value: verbosity},
+	}
+	var explain bson.M
+	if err := client.GetUnderlyingClient().Database(databaseName).RunCommand(ctx, command).Decode(&explain); err != nil {
+		return nil, fmt.Errorf("failed to explain aggregation: %w", err)
+	}
+	return explain, nil
+}
+
+// SummarizeExplain condenses the explain output of an aggregation. It understands the
+// classic $cursor stage, plans pushed down to the slot-based engine and sharded output.
+func SummarizeExplain(explain bson.M, verbosity string) *types.ExplainSummary {
+	summary := summarizeExplain(explain, verbosity)
+
+	if shards, ok := explain["shards"].(bson.M); ok {
+		names := make([]string, 0, len(shards))
+		for name := range shards {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			shardExplain, ok := shards[name].(bson.M)
+			if !ok {
+				continue
+			}
+			shard := summarizeExplain(shardExplain, verbosity)
+			shard.Shard = name
+			if summary.Namespace == "" {
+				summary.Namespace = shard.Namespace
+			}
+			summary.CollectionScan = summary.CollectionScan || shard.CollectionScan
+			summary.IndexesUsed = appendUnique(summary.IndexesUsed, shard.IndexesUsed...)
+			summary.Returned = addCount(summary.Returned, shard.Returned)
+			summary.DocsExamined = addCount(summary.DocsExamined, shard.DocsExamined)
+			summary.KeysExamined = addCount(summary.KeysExamined, shard.KeysExamined)
+			summary.Shards = append(summary.Shards, *shard)
+		}
+		if split, ok := explain["splitPipeline"].(bson.M); ok {
+			if merger, ok := split["mergerPart"].(bson.A); ok {
+				summary.Stages = append(summary.Stages, pipelineStages(merger)...)
+			}
+		}
+	}
+
+	if summary.CollectionScan {
+		summary.Warnings = append(summary.Warnings, fmt.Sprintf("COLLSCAN: the query reads every document of %s; an index on the fields of the first $match or $sort avoids it", namespaceOrCollection(summary.Namespace)))
+	}
+	if summary.DocsExamined != nil && summary.Returned != nil {
+		examined, returned := *summary.DocsExamined, *summary.Returned
+		if examined >= examinedWarningMinimum && examined > examinedWarningRatio*max(returned, 1) {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("%d documents examined to return %d; a more selective index reads fewer documents", examined, returned))
+		}
+	}
+	return summary
+}
+
+// summarizeExplain summarizes the explain output of one shard or unsharded collection
+func summarizeExplain(explain bson.M, verbosity string) *types.ExplainSummary {
+	summary := &types.ExplainSummary{Verbosity: verbosity}
+
+	// The classic engine reports the query in a leading $cursor stage
+	query := explain
+	if stages, ok := explain["stages"].(bson.A); ok {
+		if first, ok := firstDocument(stages); ok {
+			if cursor, ok := first["$cursor"].(bson.M); ok {
+				query = cursor
+				stages = stages[1:]
+			}
+		}
+		summary.Stages = pipelineStages(stages)
+	}
+
+	queryPlanner, _ := query["queryPlanner"].(bson.M)
+	if namespace, ok := queryPlanner["namespace"].(string); ok {
+		summary.Namespace = namespace
+	}
+	winningPlan, _ := queryPlanner["winningPlan"].(bson.M)
+	// Plans run by the slot-based engine nest the query plan
+	queryPlan, sbe := winningPlan["queryPlan"].(bson.M)
+	if sbe {
+		winningPlan = queryPlan
+	}
+
+	if stats, ok := query["executionStats"].(bson.M); ok {
+		summary.Returned = explainCount(stats, "nReturned")
+		summary.DocsExamined = explainCount(stats, "totalDocsExamined")
+		summary.KeysExamined = explainCount(stats, "totalKeysExamined")
+		summary.ExecutionMS = explainCount(stats, "executionTimeMillis")
+		// Classic execution stages mirror the winning plan with counts added
+		if executionStages, ok := stats["executionStages"].(bson.M); ok && winningPlan != nil && !sbe {
+			winningPlan = executionStages
+		}
+	}
+
+	if winningPlan != nil {
+		plan := planStage(winningPlan, summary)
+		summary.Plan = &plan
+	}
+	return summary
+}
+
+// planStage converts a query plan stage and its inputs, recording index use and
+// collection scans in the summary
+func planStage(doc bson.M, summary *types.ExplainSummary) types.ExplainStage {
+	stage := types.ExplainStage{
+		Stage:        fmt.Sprint(doc["stage"]),
+		Returned:     explainCount(doc, "nReturned"),
+		DocsExamined: explainCount(doc, "docsExamined"),
+		KeysExamined: explainCount(doc, "keysExamined"),
+	}
+	if indexName, ok := doc["indexName"].(string); ok {
+		stage.IndexName = indexName
+		summary.IndexesUsed = appendUnique(summary.IndexesUsed, indexName)
+	}
+	if stage.Stage == "COLLSCAN" {
+		summary.CollectionScan = true
+	}
+
+	for _, key := range []string{"inputStage", "outerStage", "innerStage"} {
+		if input, ok := doc[key].(bson.M); ok {
+			stage.Inputs = append(stage.Inputs, planStage(input, summary))
+		}
+	}
+	if inputs, ok := doc["inputStages"].(bson.A); ok {
+		for _, input := range inputs {
+			if input, ok := input.(bson.M); ok {
+				stage.Inputs = append(stage.Inputs, planStage(input, summary))
+			}
+		}
+	}
+	return stage
+}
+
+// pipelineStages lists the stages of an explained pipeline with their result counts
+func pipelineStages(stages bson.A) []types.ExplainStage {
+	var result []types.ExplainStage
+	for _, entry := range stages {
+		doc, ok := entry.(bson.M)
+		if !ok {
+			continue
+		}
+		for key := range doc {
+			if strings.HasPrefix(key, "$") {
+				result = append(result, types.ExplainStage{Stage: key, Returned: explainCount(doc, "nReturned")})
+				break
+			}
+		}
+	}
+	return result
+}
+
+func firstDocument(values bson.A) (bson.M, bool) {
+	if len(values) == 0 {
+		return nil, false
+	}
+	doc, ok := values[0].(bson.M)
+	return doc, ok
+}
+
+// explainCount reads a count, which the server reports as an int32, int64 or double
+func explainCount(doc bson.M, key string) *int64 {
+	var count int64
+	switch v := doc[key].(type) {
+	case int32:
+		count = int64(v)
+	case int64:
+		count = v
+	case float64:
+		count = int64(v)
+	default:
+		return nil
+	}
+	return &count
+}
+
+func addCount(total, count *int64) *int64 {
+	if count == nil {
+		return total
+	}
+	sum := *count
+	if total != nil {
+		sum += *total
+	}
+	return &sum
+}
+
+func appendUnique(values []string, additions ...string) []string {
+	for _, addition := range additions {
+		found := false
+		for _, value := range values {
+			if value == addition {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, addition)
+		}
+	}
+	return values
+}
+
+func namespaceOrCollection(namespace string) string {
+	if namespace == "" {
+		return "the collection"
+	}
+	return namespace
+}
+
+// ParsePipeline parses an aggregation pipeline: an array of stages written as MongoDB
+// Extended JSON, or as YAML when yamlFormat is set. The field order of stages such as
+// $sort is kept. Each stage must be a document with a single $-prefixed field.
+func ParsePipeline(data []byte, yamlFormat bool) (bson.A, error) {
+	if yamlFormat {
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, fmt.Errorf("invalid pipeline: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := yamlNodeJSON(&buf, &node); err != nil {
+			return nil, fmt.Errorf("invalid pipeline: %w", err)
+		}
+		data = buf.Bytes()
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("pipeline is empty")
+	}
+
+	// Extended JSON is decoded through a wrapper document so nested documents keep order
+	wrapped := append(append([]byte(`{"pipeline":`), data...), '}')
+	var doc bson.D
+	if err := bson.UnmarshalExtJSON(wrapped, false, &doc); err != nil {
+		return nil, fmt.Errorf("invalid pipeline: %w", err)
+	}
+	pipeline, ok := doc[0].Value.(bson.A)
+	if !ok {
+		return nil, fmt.Errorf("invalid pipeline: expected an array of stages")
+	}
+	for i, entry := range pipeline {
+		stage, ok := entry.(bson.D)
+		if !ok || len(stage) != 1 || !strings.HasPrefix(stage[0].Key, "$") {
+			return nil, fmt.Errorf("invalid pipeline stage %d: a stage is a document with a single field such as $match", i+1)
+		}
+	}
+	return pipeline, nil
+}
+
+// PipelineFromSpec converts the stages of a pipeline declared in a YAML or JSON spec into
+// a pipeline that can be run. Extended JSON values such as {"$date": ...} become BSON
+// values. Documents of a spec have already lost their key order, so a $sort stage on
+// several fields must list them as "field:order", e.g. ["status:1", "createdAt:-1"].
+func PipelineFromSpec(stages []interface{}) (bson.A, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, entry := range stages {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		data, err := specStageJSON(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline stage %d: %w", i+1, err)
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte(']')
+	return ParsePipeline(buf.Bytes(), false)
+}
+
+// specStageJSON encodes one stage of a spec pipeline as Extended JSON
+func specStageJSON(entry interface{}) ([]byte, error) {
+	var stage map[string]interface{}
+	switch v := entry.(type) {
+	case bson.D:
+		return bson.MarshalExtJSON(v, false, false)
+	case bson.M:
+		stage = v
+	case map[string]interface{}:
+		stage = v
+	}
+	if len(stage) != 1 {
+		return nil, fmt.Errorf("a stage is a document with a single field such as $match")
+	}
+	sortSpec, ok := stage["$sort"]
+	if !ok {
+		return json.Marshal(stage)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"$sort":{`)
+	switch fields := sortSpec.(type) {
+	case []interface{}:
+		for i, field := range fields {
+			spec, ok := field.(string)
+			if !ok {
+				return nil, fmt.Errorf("$sort fields must be strings such as \"createdAt:-1\"")
+			}
+			key, err := types.ParseIndexKey(spec)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONField(&buf, key.Field, key.Value()); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		if len(fields) > 1 {
+			return nil, fmt.Errorf("$sort on several fields must be a list to keep their order, e.g. [\"status:1\", \"createdAt:-1\"]")
+		}
+		for field, order := range fields {
+			if err := writeJSONField(&buf, field, order); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("$sort must be a list of \"field:order\" strings or a document")
+	}
+	buf.WriteString(`}}`)
+	return buf.Bytes(), nil
+}
+
+func writeJSONField(buf *bytes.Buffer, name string, value interface{}) error {
+	key, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(data)
+	return nil
+}
+
+// PipelineValues converts a pipeline to plain JSON values through relaxed Extended JSON,
+// the form pipelines are declared in specs and compared in plans
+func PipelineValues(pipeline interface{}) ([]interface{}, error) {
+	data, err := bson.MarshalExtJSON(bson.D{{Key: "pipeline", Value: pipeline}}, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pipeline as Extended JSON: %w", err)
+	}
+	var doc struct {
+		Pipeline []interface{} `json:"pipeline"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode Extended JSON pipeline: %w", err)
+	}
+	if doc.Pipeline == nil {
+		doc.Pipeline = []interface{}{}
+	}
+	return doc.Pipeline, nil
+}
+
+// ValidateViewPipeline checks that a view or materialized view pipeline doesn't write
+// its results itself
+func ValidateViewPipeline(pipeline bson.A) error {
+	for i, entry := range pipeline {
+		if stage, ok := entry.(bson.D); ok && len(stage) > 0 && (stage[0].Key == "$out" || stage[0].Key == "$merge") {
+			return fmt.Errorf("invalid pipeline stage %d: views cannot contain %s", i+1, stage[0].Key)
+		}
+	}
+	return nil
+}
+
+// yamlNodeJSON writes a YAML node as JSON, keeping the order of mapping keys
+func yamlNodeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return yamlNodeJSON(buf, node.Content[0])
+	case yaml.AliasNode:
+		return yamlNodeJSON(buf, node.Alias)
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := yamlNodeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := yamlNodeJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	default:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		buf.Write(data)
+		return nil
+	}
+}
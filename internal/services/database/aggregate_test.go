@@ -0,0 +1,156 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func TestParsePipeline_KeepsFieldOrder(t *testing.T) {
+	json := `[{"$match": {"createdAt": {"$gte": {"$date": "2026-01-01T00:00:00Z"}}}}, {"$sort": {"status": 1, "createdAt": -1}}]`
+	yamlPipeline := `
+- $match:
+    createdAt:
+      $gte:
+        $date: "2026-01-01T00:00:00Z"
+- $sort:
+    status: 1
+    createdAt: -1
+`
+	fromJSON, err := ParsePipeline([]byte(json), false)
+	require.NoError(t, err)
+	fromYAML, err := ParsePipeline([]byte(yamlPipeline), true)
+	require.NoError(t, err)
+
+	assert.Equal(t, fromJSON, fromYAML)
+	require.Len(t, fromJSON, 2)
+	sortStage := fromJSON[1].(bson.D)[0].Value.(bson.D)
+	assert.Equal(t, []string{"status", "createdAt"}, []string{sortStage[0].Key, sortStage[1].Key})
+}
+
+func TestParsePipeline_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		pipeline string
+		yaml     bool
+		message  string
+	}{
+		{name: "empty", pipeline: "  ", message: "pipeline is empty"},
+		{name: "not an array", pipeline: `{"$match": {}}`, message: "expected an array of stages"},
+		{name: "stage without operator", pipeline: `[{"$match": {}}, {"status": "open"}]`, message: "stage 2"},
+		{name: "stage with two operators", pipeline: `[{"$match": {}, "$limit": 1}]`, message: "stage 1"},
+		{name: "invalid json", pipeline: `[{"$match": }]`, message: "invalid pipeline"},
+		{name: "invalid yaml", pipeline: "- $match: [", yaml: true, message: "invalid pipeline"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePipeline([]byte(tt.pipeline), tt.yaml)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestSummarizeExplain_ClassicCursor(t *testing.T) {
+	explain := bson.M{
+		"stages": bson.A{
+			bson.M{"$cursor": bson.M{
+				"queryPlanner": bson.M{
+					"namespace":   "shop.orders",
+					"winningPlan": bson.M{"stage": "FETCH", "inputStage": bson.M{"stage": "IXSCAN", "indexName": "status_1"}},
+				},
+				"executionStats": bson.M{
+					"nReturned":           int32(35),
+					"totalDocsExamined":   int32(35),
+					"totalKeysExamined":   int32(35),
+					"executionTimeMillis": int32(4),
+					"executionStages": bson.M{
+						"stage": "FETCH", "nReturned": int32(35), "docsExamined": int32(35),
+						"inputStage": bson.M{"stage": "IXSCAN", "indexName": "status_1", "nReturned": int32(35), "keysExamined": int32(35)},
+					},
+				},
+			}},
+			bson.M{"$group": bson.M{"_id": "$customerId"}, "nReturned": int64(4)},
+			bson.M{"$sort": bson.M{"sortKey": bson.M{"total": -1}}, "nReturned": int64(4)},
+		},
+	}
+
+	summary := SummarizeExplain(explain, types.ExplainExecutionStats)
+
+	assert.Equal(t, "shop.orders", summary.Namespace)
+	assert.False(t, summary.CollectionScan)
+	assert.Equal(t, []string{"status_1"}, summary.IndexesUsed)
+	assert.Equal(t, int64(35), *summary.DocsExamined)
+	assert.Equal(t, int64(35), *summary.Returned)
+	assert.Empty(t, summary.Warnings)
+
+	require.NotNil(t, summary.Plan)
+	assert.Equal(t, "FETCH", summary.Plan.Stage)
+	assert.Equal(t, int64(35), *summary.Plan.DocsExamined)
+	require.Len(t, summary.Plan.Inputs, 1)
+	assert.Equal(t, "status_1", summary.Plan.Inputs[0].IndexName)
+
+	require.Len(t, summary.Stages, 2)
+	assert.Equal(t, "$group", summary.Stages[0].Stage)
+	assert.Equal(t, int64(4), *summary.Stages[1].Returned)
+}
+
+func TestSummarizeExplain_SlotBasedCollectionScan(t *testing.T) {
+	explain := bson.M{
+		"queryPlanner": bson.M{
+			"namespace": "shop.orders",
+			"winningPlan": bson.M{
+				"queryPlan":     bson.M{"stage": "GROUP", "inputStage": bson.M{"stage": "COLLSCAN"}},
+				"slotBasedPlan": bson.M{"stages": "..."},
+			},
+		},
+		"executionStats": bson.M{
+			"nReturned":         int32(4),
+			"totalDocsExamined": int32(50000),
+			"totalKeysExamined": int32(0),
+			"executionStages":   bson.M{"stage": "group", "nReturned": int32(4)},
+		},
+	}
+
+	summary := SummarizeExplain(explain, types.ExplainExecutionStats)
+
+	assert.True(t, summary.CollectionScan)
+	require.NotNil(t, summary.Plan)
+	assert.Equal(t, "GROUP", summary.Plan.Stage)
+	assert.Equal(t, "COLLSCAN", summary.Plan.Inputs[0].Stage)
+	require.Len(t, summary.Warnings, 2)
+	assert.Contains(t, summary.Warnings[0], "COLLSCAN")
+	assert.Contains(t, summary.Warnings[1], "50000 documents examined to return 4")
+}
+
+func TestSummarizeExplain_Sharded(t *testing.T) {
+	shard := func(stage bson.M, examined int32) bson.M {
+		return bson.M{
+			"queryPlanner":   bson.M{"namespace": "shop.orders", "winningPlan": stage},
+			"executionStats": bson.M{"nReturned": int32(10), "totalDocsExamined": examined},
+		}
+	}
+	explain := bson.M{
+		"splitPipeline": bson.M{"mergerPart": bson.A{bson.M{"$mergeCursors": bson.M{}}, bson.M{"$group": bson.M{}}}},
+		"shards": bson.M{
+			"shard-1": shard(bson.M{"stage": "COLLSCAN"}, 100),
+			"shard-0": shard(bson.M{"stage": "FETCH", "inputStage": bson.M{"stage": "IXSCAN", "indexName": "status_1"}}, 10),
+		},
+	}
+
+	summary := SummarizeExplain(explain, types.ExplainQueryPlanner)
+
+	require.Len(t, summary.Shards, 2)
+	assert.Equal(t, "shard-0", summary.Shards[0].Shard)
+	assert.Equal(t, "shop.orders", summary.Namespace)
+	assert.True(t, summary.CollectionScan)
+	assert.Equal(t, []string{"status_1"}, summary.IndexesUsed)
+	assert.Equal(t, int64(110), *summary.DocsExamined)
+	assert.Equal(t, int64(20), *summary.Returned)
+	require.Len(t, summary.Stages, 2)
+	assert.Equal(t, "$group", summary.Stages[1].Stage)
+}
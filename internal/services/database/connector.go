@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/atlas-sdk/v20250312010/admin"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// ClusterGetter fetches the description of a cluster, including its connection strings
+type ClusterGetter interface {
+	Get(ctx context.Context, projectID, clusterName string) (*admin.ClusterDescription20240805, error)
+}
+
+// ClusterConnector connects to the clusters of a project through temporary database users.
+// One maintenance user is created per cluster and reused until Close removes them all.
+type ClusterConnector struct {
+	service   *Service
+	clusters  ClusterGetter
+	tempUsers *TempUserManager
+	projectID string
+
+	// ReadyTimeout bounds how long Connect waits for a cluster to publish its connection string
+	ReadyTimeout time.Duration
+	// PropagationTimeout bounds how long Connect waits for a new user to be accepted
+	PropagationTimeout time.Duration
+	// PollInterval is the delay between checks while the cluster or the user is not ready
+	PollInterval time.Duration
+
+	mu          sync.Mutex
+	connections map[string]*types.ConnectionInfo
+	// clusterLocks serialize connecting to the same cluster, so it gets a single user
+	clusterLocks map[string]*sync.Mutex
+	// users are every temporary user created, including those that never propagated
+	users []*types.TempUserInfo
+}
+
+// NewClusterConnector creates a connector for the clusters of a project
+func NewClusterConnector(service *Service, clusters ClusterGetter, users DatabaseUsersServiceInterface, projectID string) *ClusterConnector {
+	return &ClusterConnector{
+		service:            service,
+		clusters:           clusters,
+		tempUsers:          NewTempUserManager(users, projectID),
+		projectID:          projectID,
+		ReadyTimeout:       20 * time.Minute,
+		PropagationTimeout: 3 * time.Minute,
+		PollInterval:       10 * time.Second,
+		connections:        make(map[string]*types.ConnectionInfo),
+		clusterLocks:       make(map[string]*sync.Mutex),
+	}
+}
+
+// Connect returns connection details for a cluster, creating a temporary user on first use.
+// It waits until the cluster publishes a connection string and accepts the user.
+func (c *ClusterConnector) Connect(ctx context.Context, clusterName string) (*types.ConnectionInfo, error) {
+	c.mu.Lock()
+	clusterLock, ok := c.clusterLocks[clusterName]
+	if !ok {
+		clusterLock = &sync.Mutex{}
+		c.clusterLocks[clusterName] = clusterLock
+	}
+	c.mu.Unlock()
+
+	// Only connections to the same cluster wait for each other
+	clusterLock.Lock()
+	defer clusterLock.Unlock()
+
+	c.mu.Lock()
+	connInfo, ok := c.connections[clusterName]
+	c.mu.Unlock()
+	if ok {
+		return connInfo, nil
+	}
+
+	connectionString, err := c.waitForCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	tempUser, err := c.tempUsers.CreateTempUserForMaintenance(ctx, []string{clusterName})
+	if err != nil {
+		return nil, err
+	}
+	connInfo = &types.ConnectionInfo{
+		ConnectionString: ConnectionStringWithCredentials(connectionString, tempUser.Username, tempUser.Password),
+		TempUser: &types.TempUserInfo{
+			Username:    tempUser.Username,
+			ExpiresAt:   tempUser.ExpiresAt,
+			CleanupFunc: tempUser.CleanupFunc,
+		},
+	}
+	// Record the user before waiting so Close removes it even if propagation fails
+	c.mu.Lock()
+	c.users = append(c.users, connInfo.TempUser)
+	c.mu.Unlock()
+
+	if err := c.waitForUser(ctx, clusterName, connInfo); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.connections[clusterName] = connInfo
+	c.mu.Unlock()
+	return connInfo, nil
+}
+
+// waitForCluster polls a cluster until it publishes its SRV connection string, which Atlas
+// only does once a new cluster has been provisioned
+func (c *ClusterConnector) waitForCluster(ctx context.Context, clusterName string) (string, error) {
+	deadline := time.Now().Add(c.ReadyTimeout)
+	for {
+		cluster, err := c.clusters.Get(ctx, c.projectID, clusterName)
+		if err != nil {
+			return "", fmt.Errorf("failed to get cluster %s: %w", clusterName, err)
+		}
+		if cluster.ConnectionStrings != nil && cluster.ConnectionStrings.StandardSrv != nil {
+			return *cluster.ConnectionStrings.StandardSrv, nil
+		}
+		if time.Now().Add(c.PollInterval).After(deadline) {
+			return "", fmt.Errorf("cluster '%s' does not have a connection string available (state %s)", clusterName, cluster.GetStateName())
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("cancelled while waiting for cluster %s to be ready: %w", clusterName, ctx.Err())
+		case <-time.After(c.PollInterval):
+		}
+	}
+}
+
+// waitForUser retries connecting until Atlas has propagated the temporary user to the cluster
+func (c *ClusterConnector) waitForUser(ctx context.Context, clusterName string, connInfo *types.ConnectionInfo) error {
+	deadline := time.Now().Add(c.PropagationTimeout)
+	for {
+		_, err := c.service.GetOrCreateClient(ctx, connInfo)
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(c.PollInterval).After(deadline) {
+			return fmt.Errorf("temporary user was not accepted by cluster %s within %s: %w", clusterName, c.PropagationTimeout, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cancelled while waiting for user propagation to cluster %s: %w", clusterName, ctx.Err())
+		case <-time.After(c.PollInterval):
+		}
+	}
+}
+
+// Close removes the temporary users created by the connector
+func (c *ClusterConnector) Close(ctx context.Context) error {
+	c.mu.Lock()
+	users := c.users
+	c.users = nil
+	c.connections = make(map[string]*types.ConnectionInfo)
+	c.mu.Unlock()
+
+	var errors []error
+	for _, user := range users {
+		if user.CleanupFunc == nil {
+			continue
+		}
+		if err := user.CleanupFunc(ctx); err != nil {
+			errors = append(errors, fmt.Errorf("failed to remove temporary user %s: %w", user.Username, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors removing temporary users: %v", errors)
+	}
+	return nil
+}
+
+// ConnectionStringWithCredentials inserts URL-encoded credentials into an Atlas SRV
+// connection string, authenticating against the admin database
+func ConnectionStringWithCredentials(connectionString, username, password string) string {
+	connectionString = strings.Replace(connectionString, "mongodb+srv://",
+		fmt.Sprintf("mongodb+srv://%s:%s@", url.QueryEscape(username), url.QueryEscape(password)), 1)
+
+	// Atlas connection strings don't include a database path
+	rest := strings.TrimPrefix(connectionString, "mongodb+srv://")
+	if !strings.Contains(rest, "/") {
+		if idx := strings.Index(connectionString, "?"); idx != -1 {
+			connectionString = connectionString[:idx] + "/admin" + connectionString[idx:]
+		} else {
+			connectionString += "/admin"
+		}
+	}
+
+	if strings.Contains(connectionString, "?") {
+		return connectionString + "&authSource=admin"
+	}
+	return connectionString + "?authSource=admin"
+}
@@ -3,9 +3,11 @@ package database
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/teabranch/matlas-cli/internal/logging"
@@ -266,3 +268,245 @@ func (ds *DocumentService) CountDocuments(ctx context.Context, connInfo *types.C
 
 	return count, nil
 }
+
+// FindOptions controls which documents Find returns and how
+type FindOptions struct {
+	Projection map[string]interface{}
+	// Sort keeps the order of its keys, which sets the sort precedence
+	Sort  bson.D
+	Limit int64
+	Skip  int64
+	// Sample returns a random sample of this many matching documents instead of reading
+	// them in natural order; sort, skip and limit then apply to the sample
+	Sample int64
+}
+
+// UpdateResult reports the outcome of UpdateDocuments
+type UpdateResult struct {
+	Matched    int64       `json:"matched"`
+	Modified   int64       `json:"modified"`
+	UpsertedID interface{} `json:"upsertedId,omitempty"`
+}
+
+// Find returns the documents matching the filter as ordered BSON documents. Unlike
+// FindDocuments it accepts any type of _id and keeps the field order of each document.
+func (ds *DocumentService) Find(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, filter map[string]interface{}, opts *FindOptions) ([]bson.D, error) {
+	if databaseName == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return nil, fmt.Errorf("collection name is required")
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := client.GetUnderlyingClient().Database(databaseName).Collection(collectionName)
+
+	var cursor *mongo.Cursor
+	if opts != nil && opts.Sample > 0 {
+		cursor, err = collection.Aggregate(ctx, samplePipeline(filter, opts))
+	} else {
+		cursor, err = collection.Find(ctx, toFilter(filter), findOptionsFrom(opts))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find documents: %w", err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	documents := []bson.D{}
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("failed to read documents: %w", err)
+	}
+
+	ds.logger.Debug("Found documents",
+		"database", databaseName,
+		"collection", collectionName,
+		"count", len(documents))
+
+	return documents, nil
+}
+
+func findOptionsFrom(opts *FindOptions) *options.FindOptions {
+	findOptions := options.Find()
+	if opts != nil {
+		if len(opts.Projection) > 0 {
+			findOptions.SetProjection(opts.Projection)
+		}
+		if len(opts.Sort) > 0 {
+			findOptions.SetSort(opts.Sort)
+		}
+		if opts.Limit > 0 {
+			findOptions.SetLimit(opts.Limit)
+		}
+		if opts.Skip > 0 {
+			findOptions.SetSkip(opts.Skip)
+		}
+	}
+	return findOptions
+}
+
+// samplePipeline is the aggregation equivalent of a find that returns a random sample
+func samplePipeline(filter map[string]interface{}, opts *FindOptions) mongo.Pipeline {
+	var pipeline mongo.Pipeline
+	if len(filter) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: toFilter(filter)}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sample", Value: bson.M{"size": opts.Sample}}})
+	if len(opts.Projection) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: opts.Projection}})
+	}
+	if len(opts.Sort) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: opts.Sort}})
+	}
+	if opts.Skip > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: opts.Skip}})
+	}
+	if opts.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: opts.Limit}})
+	}
+	return pipeline
+}
+
+// InsertDocuments inserts documents in order and returns their _id values. Documents
+// without an _id are given an ObjectID.
+func (ds *DocumentService) InsertDocuments(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, documents []interface{}) ([]interface{}, error) {
+	if databaseName == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return nil, fmt.Errorf("collection name is required")
+	}
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("at least one document is required")
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := client.GetUnderlyingClient().Database(databaseName).Collection(collectionName)
+
+	result, err := collection.InsertMany(ctx, documents)
+	if err != nil {
+		var inserted []interface{}
+		if result != nil {
+			inserted = result.InsertedIDs
+		}
+		return inserted, fmt.Errorf("failed to insert documents: %w", err)
+	}
+
+	ds.logger.Debug("Inserted documents",
+		"database", databaseName,
+		"collection", collectionName,
+		"count", len(result.InsertedIDs))
+
+	return result.InsertedIDs, nil
+}
+
+// UpdateDocuments applies an update to the first document matching the filter, or to
+// every matching document when many is set. An update without operators is applied as
+// $set, like UpdateDocument.
+func (ds *DocumentService) UpdateDocuments(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, filter, update map[string]interface{}, many, upsert bool) (*UpdateResult, error) {
+	if databaseName == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return nil, fmt.Errorf("collection name is required")
+	}
+	if len(update) == 0 {
+		return nil, fmt.Errorf("update is required")
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := client.GetUnderlyingClient().Database(databaseName).Collection(collectionName)
+
+	updateDoc := bson.M(update)
+	if !hasOperators(update) {
+		updateDoc = bson.M{"$set": update}
+	}
+	updateOptions := options.Update().SetUpsert(upsert)
+
+	var result *mongo.UpdateResult
+	if many {
+		result, err = collection.UpdateMany(ctx, toFilter(filter), updateDoc, updateOptions)
+	} else {
+		result, err = collection.UpdateOne(ctx, toFilter(filter), updateDoc, updateOptions)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update documents: %w", err)
+	}
+
+	ds.logger.Debug("Updated documents",
+		"database", databaseName,
+		"collection", collectionName,
+		"matched_count", result.MatchedCount,
+		"modified_count", result.ModifiedCount)
+
+	return &UpdateResult{
+		Matched:    result.MatchedCount,
+		Modified:   result.ModifiedCount,
+		UpsertedID: result.UpsertedID,
+	}, nil
+}
+
+// DeleteDocuments deletes the first document matching the filter, or every matching
+// document when many is set, and returns the number deleted
+func (ds *DocumentService) DeleteDocuments(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, filter map[string]interface{}, many bool) (int64, error) {
+	if databaseName == "" {
+		return 0, fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return 0, fmt.Errorf("collection name is required")
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return 0, err
+	}
+
+	collection := client.GetUnderlyingClient().Database(databaseName).Collection(collectionName)
+
+	var result *mongo.DeleteResult
+	if many {
+		result, err = collection.DeleteMany(ctx, toFilter(filter))
+	} else {
+		result, err = collection.DeleteOne(ctx, toFilter(filter))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete documents: %w", err)
+	}
+
+	ds.logger.Debug("Deleted documents",
+		"database", databaseName,
+		"collection", collectionName,
+		"count", result.DeletedCount)
+
+	return result.DeletedCount, nil
+}
+
+// toFilter converts a filter map into a BSON document, treating nil as match-all
+func toFilter(filter map[string]interface{}) bson.M {
+	bsonFilter := bson.M{}
+	for k, v := range filter {
+		bsonFilter[k] = v
+	}
+	return bsonFilter
+}
+
+// hasOperators reports whether an update document uses update operators such as $set
+func hasOperators(update map[string]interface{}) bool {
+	for key := range update {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/teabranch/matlas-cli/internal/logging"
@@ -427,3 +428,23 @@ func TestDocument_WithData(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "value", nested["inner"])
 }
+
+func TestSamplePipeline(t *testing.T) {
+	pipeline := samplePipeline(map[string]interface{}{"status": "open"}, &FindOptions{
+		Sample:     50,
+		Projection: map[string]interface{}{"status": 1},
+		Sort:       bson.D{{Key: "createdAt", Value: -1}},
+		Limit:      10,
+	})
+
+	stages := make([]string, len(pipeline))
+	for i, stage := range pipeline {
+		stages[i] = stage[0].Key
+	}
+	assert.Equal(t, []string{"$match", "$sample", "$project", "$sort", "$limit"}, stages)
+	assert.Equal(t, bson.M{"size": int64(50)}, pipeline[1][0].Value)
+
+	pipeline = samplePipeline(nil, &FindOptions{Sample: 5})
+	require.Len(t, pipeline, 1)
+	assert.Equal(t, "$sample", pipeline[0][0].Key)
+}
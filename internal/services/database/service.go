@@ -3,8 +3,9 @@ package database
 import (
 	"context"
 	"fmt"
+	"sync"
 
-	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/bson"
 
 	"github.com/teabranch/matlas-cli/internal/clients/mongodb"
 	"github.com/teabranch/matlas-cli/internal/logging"
@@ -13,6 +14,7 @@ import (
 
 // Service provides database operations
 type Service struct {
+	mu      sync.Mutex
 	clients map[string]*mongodb.Client // keyed by connection string
 	logger  *logging.Logger
 }
@@ -35,6 +37,10 @@ func (s *Service) GetOrCreateClient(ctx context.Context, connInfo *types.Connect
 		return nil, fmt.Errorf("connection info is required")
 	}
 
+	// Operations such as watched index builds use the service from several goroutines
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// Check if we already have a client for this connection string
 	if client, exists := s.clients[connInfo.ConnectionString]; exists {
 		// Test the connection to make sure it's still valid
@@ -111,8 +117,11 @@ func (s *Service) ListCollections(ctx context.Context, connInfo *types.Connectio
 		return nil, fmt.Errorf("failed to list collections: %w", err)
 	}
 
-	// Enrich collections with statistics
+	// Enrich collections with statistics; views have none
 	for i := range collections {
+		if collections[i].IsView() {
+			continue
+		}
 		stats, err := client.GetCollectionStats(ctx, databaseName, collections[i].Name)
 		if err != nil {
 			s.logger.Warn("Failed to get collection stats",
@@ -133,38 +142,64 @@ func (s *Service) ListCollections(ctx context.Context, connInfo *types.Connectio
 
 // CreateCollection creates a new collection in the specified database
 func (s *Service) CreateCollection(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, opts map[string]interface{}) error {
-	if databaseName == "" {
-		return fmt.Errorf("database name is required")
-	}
-	if collectionName == "" {
-		return fmt.Errorf("collection name is required")
-	}
-
-	client, err := s.GetOrCreateClient(ctx, connInfo)
-	if err != nil {
-		return err
+	// A view is created from viewOn and pipeline rather than collection options
+	if viewOn, ok := opts["viewOn"].(string); ok && viewOn != "" {
+		var stages []interface{}
+		switch pipeline := opts["pipeline"].(type) {
+		case bson.A:
+			stages = pipeline
+		case []interface{}:
+			stages = pipeline
+		case nil:
+		default:
+			return fmt.Errorf("view pipeline must be an array of stages, got %T", pipeline)
+		}
+		pipeline, err := PipelineFromSpec(stages)
+		if err != nil {
+			return err
+		}
+		return s.CreateView(ctx, connInfo, databaseName, collectionName, viewOn, pipeline, nil)
 	}
 
-	// Convert options map to MongoDB options
-	var createOpts *options.CreateCollectionOptions
+	// Convert options map to collection options
+	var collectionOpts *types.CollectionOptions
 	if opts != nil {
-		createOpts = options.CreateCollection()
+		collectionOpts = &types.CollectionOptions{}
 
 		// Handle common options
 		if capped, ok := opts["capped"].(bool); ok && capped {
-			createOpts.SetCapped(true)
+			collectionOpts.Capped = true
 			if size, ok := opts["size"].(int64); ok {
-				createOpts.SetSizeInBytes(size)
+				collectionOpts.Size = size
 			}
 			if max, ok := opts["max"].(int64); ok {
-				createOpts.SetMaxDocuments(max)
+				collectionOpts.Max = max
 			}
 		}
+	}
+
+	return s.CreateCollectionWithOptions(ctx, connInfo, databaseName, collectionName, collectionOpts)
+}
+
+// CreateCollectionWithOptions creates a collection with validator, capped, time series or
+// clustered options
+func (s *Service) CreateCollectionWithOptions(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, opts *types.CollectionOptions) error {
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return fmt.Errorf("collection name is required")
+	}
+	if err := types.ValidateCollectionOptions(opts); err != nil {
+		return fmt.Errorf("invalid collection options: %w", err)
+	}
 
-		// Add other options as needed
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return err
 	}
 
-	if err := client.CreateCollection(ctx, databaseName, collectionName, createOpts); err != nil {
+	if err := client.CreateCollection(ctx, databaseName, collectionName, mongodb.CreateCollectionOptions(opts)); err != nil {
 		return fmt.Errorf("failed to create collection: %w", err)
 	}
 
@@ -175,6 +210,53 @@ func (s *Service) CreateCollection(ctx context.Context, connInfo *types.Connecti
 	return nil
 }
 
+// ModifyCollection changes the options of an existing collection that collMod can change:
+// the validator, validationLevel, validationAction and expireAfterSeconds. Options left
+// empty are not changed.
+func (s *Service) ModifyCollection(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, opts *types.CollectionOptions) error {
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return fmt.Errorf("collection name is required")
+	}
+	if opts == nil {
+		return nil
+	}
+
+	var changes bson.D
+	if opts.Validator != nil {
+		changes = append(changes, bson.E{Key: "validator", Value: opts.Validator})
+	}
+	if opts.ValidationLevel != "" {
+		changes = append(changes, bson.E{Key: "validationLevel", Value: opts.ValidationLevel})
+	}
+	if opts.ValidationAction != "" {
+		changes = append(changes, bson.E{Key: "validationAction", Value: opts.ValidationAction})
+	}
+	if opts.ExpireAfterSeconds != nil {
+		changes = append(changes, bson.E{Key: "expireAfterSeconds", Value: *opts.ExpireAfterSeconds})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return err
+	}
+
+	if err := client.ModifyCollection(ctx, databaseName, collectionName, changes); err != nil {
+		return err
+	}
+
+	s.logger.Info("Modified collection",
+		"database", databaseName,
+		"collection", collectionName)
+
+	return nil
+}
+
 // DropCollection drops a collection from the specified database
 func (s *Service) DropCollection(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string) error {
 	if databaseName == "" {
@@ -307,7 +389,7 @@ func (s *Service) DropDatabase(ctx context.Context, connInfo *types.ConnectionIn
 }
 
 // CreateIndex creates an index on a collection
-func (s *Service) CreateIndex(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, keys map[string]int, opts map[string]interface{}) (string, error) {
+func (s *Service) CreateIndex(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, keys []types.IndexKey, opts *types.IndexOptions) (string, error) {
 	if databaseName == "" {
 		return "", fmt.Errorf("database name is required")
 	}
@@ -317,6 +399,9 @@ func (s *Service) CreateIndex(ctx context.Context, connInfo *types.ConnectionInf
 	if len(keys) == 0 {
 		return "", fmt.Errorf("index keys are required")
 	}
+	if err := types.ValidateIndex(keys, opts); err != nil {
+		return "", fmt.Errorf("invalid index: %w", err)
+	}
 
 	client, err := s.GetOrCreateClient(ctx, connInfo)
 	if err != nil {
@@ -365,6 +450,47 @@ func (s *Service) DropIndex(ctx context.Context, connInfo *types.ConnectionInfo,
 	return nil
 }
 
+// ModifyIndex hides or unhides an index, or changes the expireAfterSeconds of a TTL index,
+// without rebuilding it
+func (s *Service) ModifyIndex(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName, indexName string, hidden *bool, expireAfterSeconds *int32) error {
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return fmt.Errorf("collection name is required")
+	}
+	if indexName == "" {
+		return fmt.Errorf("index name is required")
+	}
+
+	index := bson.D{{Key: "name", Value: indexName}}
+	if hidden != nil {
+		index = append(index, bson.E{Key: "hidden", Value: *hidden})
+	}
+	if expireAfterSeconds != nil {
+		index = append(index, bson.E{Key: "expireAfterSeconds", Value: *expireAfterSeconds})
+	}
+	if len(index) == 1 {
+		return nil
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return err
+	}
+
+	if err := client.ModifyCollection(ctx, databaseName, collectionName, bson.D{{Key: "index", Value: index}}); err != nil {
+		return fmt.Errorf("failed to modify index: %w", err)
+	}
+
+	s.logger.Info("Modified index",
+		"database", databaseName,
+		"collection", collectionName,
+		"index", indexName)
+
+	return nil
+}
+
 // ListIndexes lists all indexes for a collection
 func (s *Service) ListIndexes(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string) ([]types.IndexInfo, error) {
 	if databaseName == "" {
@@ -392,8 +518,34 @@ func (s *Service) ListIndexes(ctx context.Context, connInfo *types.ConnectionInf
 	return indexes, nil
 }
 
+// IndexBuilds lists the index builds in progress on a cluster. An empty database lists the
+// builds of every database, and an empty collection those of every collection in the database.
+func (s *Service) IndexBuilds(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string) ([]types.IndexBuild, error) {
+	if collectionName != "" && databaseName == "" {
+		return nil, fmt.Errorf("database name is required to filter by collection")
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := databaseName
+	if collectionName != "" {
+		namespace += "." + collectionName
+	}
+	builds, err := client.CurrentIndexBuilds(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list index builds: %w", err)
+	}
+	return builds, nil
+}
+
 // Close closes all cached MongoDB clients
 func (s *Service) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var errors []error
 
 	for connString, client := range s.clients {
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// systemDatabases hold server state rather than application data
+var systemDatabases = map[string]bool{"admin": true, "local": true, "config": true}
+
+// Snapshot records the collections, views, validators and indexes of databases. Without
+// database names, every database except admin, local and config is recorded. Databases
+// that don't exist are recorded without collections. system.* collections and the
+// collection holding materialized view definitions are left out.
+func (s *Service) Snapshot(ctx context.Context, connInfo *types.ConnectionInfo, databaseNames []string) (*types.DatabaseSnapshot, error) {
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(databaseNames) == 0 {
+		databases, err := client.ListDatabases(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list databases: %w", err)
+		}
+		for _, database := range databases {
+			if !systemDatabases[database.Name] {
+				databaseNames = append(databaseNames, database.Name)
+			}
+		}
+	}
+	databaseNames = append([]string(nil), databaseNames...)
+	sort.Strings(databaseNames)
+
+	snapshot := &types.DatabaseSnapshot{Databases: make([]types.DatabaseSchema, 0, len(databaseNames))}
+	for _, databaseName := range databaseNames {
+		infos, err := client.ListCollections(ctx, databaseName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collections of %s: %w", databaseName, err)
+		}
+
+		schema := types.DatabaseSchema{Name: databaseName, Collections: []types.CollectionSchema{}}
+		for _, info := range infos {
+			if strings.HasPrefix(info.Name, "system.") || info.Name == MaterializedViewsCollection {
+				continue
+			}
+			collection, err := collectionSchema(info)
+			if err != nil {
+				return nil, fmt.Errorf("collection %s.%s: %w", databaseName, info.Name, err)
+			}
+			if collection.Type != "view" {
+				indexes, err := client.ListIndexes(ctx, databaseName, info.Name)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list indexes of %s.%s: %w", databaseName, info.Name, err)
+				}
+				sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+				collection.Indexes = indexes
+			}
+			schema.Collections = append(schema.Collections, collection)
+		}
+		sort.Slice(schema.Collections, func(i, j int) bool { return schema.Collections[i].Name < schema.Collections[j].Name })
+		snapshot.Databases = append(snapshot.Databases, schema)
+	}
+
+	s.logger.Debug("Took database snapshot", "databases", len(snapshot.Databases))
+
+	return snapshot, nil
+}
+
+// collectionSchema converts a listCollections entry
+func collectionSchema(info types.CollectionInfo) (types.CollectionSchema, error) {
+	opts, err := types.ParseCollectionOptions(info.Options)
+	if err != nil {
+		return types.CollectionSchema{}, err
+	}
+	collection := types.CollectionSchema{Name: info.Name, Type: info.Type, Options: *opts}
+	if collection.Type == "" {
+		collection.Type = "collection"
+	}
+	if viewOn, ok := info.Options["viewOn"].(string); ok {
+		collection.ViewOn = viewOn
+	}
+	if pipeline, ok := info.Options["pipeline"].(bson.A); ok {
+		collection.Pipeline = []interface{}(pipeline)
+	}
+	return collection, nil
+}
@@ -0,0 +1,631 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// Checkpoint files of export and import
+const (
+	// ExportStateFile is written in the export directory
+	ExportStateFile = ".matlas-export.json"
+	// ImportStateFile is written in the imported directory, or beside an imported file
+	// with the file name as prefix
+	ImportStateFile = ".matlas-import.json"
+)
+
+// Transfer defaults
+const (
+	DefaultTransferBatchSize = 1000
+	DefaultTransferParallel  = 4
+)
+
+// TransferState is the checkpoint of an export or import. It is saved after every batch
+// so an interrupted run can resume where it stopped.
+type TransferState struct {
+	Operation string `json:"operation"`
+	Database  string `json:"database"`
+	// Transfers are keyed by file path
+	Transfers map[string]*CollectionTransfer `json:"transfers"`
+}
+
+// CollectionTransfer is the progress of exporting a collection to a file or importing a
+// file into a collection
+type CollectionTransfer struct {
+	Collection string         `json:"collection"`
+	File       string         `json:"file"`
+	Format     TransferFormat `json:"format"`
+	Documents  int64          `json:"documents"`
+	// Offset is the file position up to which documents were written or imported
+	Offset int64 `json:"offset"`
+	// LastID is the last exported _id as canonical Extended JSON
+	LastID string `json:"lastId,omitempty"`
+	Done   bool   `json:"done"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ExportOptions configures Export
+type ExportOptions struct {
+	Database string
+	// Collections to export; every collection of the database when empty
+	Collections []string
+	Dir         string
+	Format      TransferFormat
+	Filter      map[string]interface{}
+	Projection  map[string]interface{}
+	// Fields are the CSV columns, as dotted paths
+	Fields    []string
+	BatchSize int
+	Parallel  int
+	// Resume continues the export recorded in the directory's checkpoint
+	Resume bool
+	// Progress is called with the documents exported and the documents to export
+	Progress func(done, total int64)
+}
+
+// ImportFile is a file to import and its target collection
+type ImportFile struct {
+	Path       string
+	Collection string
+	Format     TransferFormat
+}
+
+// ImportOptions configures Import
+type ImportOptions struct {
+	Database  string
+	Files     []ImportFile
+	StatePath string
+	// Columns map CSV headers to fields and types; unmapped headers are read as auto
+	Columns   []CSVColumn
+	BatchSize int
+	Parallel  int
+	// Resume continues the import recorded in StatePath
+	Resume bool
+	// Drop drops each target collection before importing into it
+	Drop bool
+	// Upsert replaces documents with the same _id instead of failing on duplicates
+	Upsert bool
+	// Progress is called with the bytes imported and the bytes to import
+	Progress func(done, total int64)
+}
+
+// transferRun guards a checkpoint shared by the collection streams of one run
+type transferRun struct {
+	mu       sync.Mutex
+	path     string
+	state    *TransferState
+	done     int64
+	total    int64
+	progress func(done, total int64)
+}
+
+// update changes a transfer under the lock, then saves the checkpoint and reports progress
+func (r *transferRun) update(advance int64, change func()) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	change()
+	r.done += advance
+	if r.progress != nil {
+		r.progress(r.done, r.total)
+	}
+	return saveTransferState(r.path, r.state)
+}
+
+// results returns a copy of the transfers ordered by file
+func (r *transferRun) results() []CollectionTransfer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]CollectionTransfer, 0, len(r.state.Transfers))
+	for _, transfer := range r.state.Transfers {
+		results = append(results, *transfer)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].File < results[j].File })
+	return results
+}
+
+// run streams every unfinished transfer, at most parallel at a time, and reports how many failed
+func (r *transferRun) run(parallel int, stream func(*CollectionTransfer) error) error {
+	var pending []*CollectionTransfer
+	for _, transfer := range r.state.Transfers {
+		if !transfer.Done {
+			pending = append(pending, transfer)
+		}
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, max(parallel, 1))
+	var failed int
+	var failedMu sync.Mutex
+	for _, transfer := range pending {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(transfer *CollectionTransfer) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := stream(transfer); err != nil {
+				failedMu.Lock()
+				failed++
+				failedMu.Unlock()
+				_ = r.update(0, func() { transfer.Error = err.Error() })
+			}
+		}(transfer)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d transfer(s) failed; run again with --resume to continue from the last checkpoint", failed, len(pending))
+	}
+	return nil
+}
+
+// Export writes collections to one file per collection in a directory, streaming several
+// collections in parallel. Documents are read in _id order and a checkpoint is saved after
+// every batch; a resumed export truncates each file to its checkpoint and continues after
+// the last exported _id.
+func (ds *DocumentService) Export(ctx context.Context, connInfo *types.ConnectionInfo, opts ExportOptions) ([]CollectionTransfer, error) {
+	if opts.Database == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("output directory is required")
+	}
+	if opts.Format == FormatCSV && len(opts.Fields) == 0 {
+		return nil, fmt.Errorf("csv export requires the fields to write")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultTransferBatchSize
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+	db := client.GetUnderlyingClient().Database(opts.Database)
+
+	statePath := filepath.Join(opts.Dir, ExportStateFile)
+	var state *TransferState
+	if opts.Resume {
+		if state, err = loadTransferState(statePath, "export", opts.Database); err != nil {
+			return nil, err
+		}
+	} else {
+		collections := opts.Collections
+		if len(collections) == 0 {
+			if collections, err = exportableCollections(ctx, client.ListCollections, opts.Database); err != nil {
+				return nil, err
+			}
+		}
+		state = &TransferState{Operation: "export", Database: opts.Database, Transfers: make(map[string]*CollectionTransfer)}
+		for _, collection := range collections {
+			file := collection + opts.Format.Extension()
+			state.Transfers[file] = &CollectionTransfer{Collection: collection, File: file, Format: opts.Format}
+		}
+	}
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	run := &transferRun{path: statePath, state: state, progress: opts.Progress}
+	for _, transfer := range state.Transfers {
+		if transfer.Done {
+			continue
+		}
+		count, err := db.Collection(transfer.Collection).CountDocuments(ctx, toFilter(opts.Filter))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count documents in %s: %w", transfer.Collection, err)
+		}
+		run.total += max(count-transfer.Documents, 0)
+	}
+	if err := saveTransferState(statePath, state); err != nil {
+		return nil, err
+	}
+
+	err = run.run(opts.Parallel, func(transfer *CollectionTransfer) error {
+		return exportCollection(ctx, db.Collection(transfer.Collection), filepath.Join(opts.Dir, transfer.File), transfer, opts, batchSize, run)
+	})
+
+	ds.logger.Debug("Exported collections",
+		"database", opts.Database,
+		"directory", opts.Dir,
+		"documents", run.done)
+
+	return run.results(), err
+}
+
+// exportableCollections lists the collections of a database, leaving out views and system collections
+func exportableCollections(ctx context.Context, list func(context.Context, string) ([]types.CollectionInfo, error), databaseName string) ([]string, error) {
+	infos, err := list(ctx, databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	var names []string
+	for _, info := range infos {
+		if info.Type == "view" || strings.HasPrefix(info.Name, "system.") {
+			continue
+		}
+		names = append(names, info.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func exportCollection(ctx context.Context, collection *mongo.Collection, path string, transfer *CollectionTransfer, opts ExportOptions, batchSize int, run *transferRun) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // the export directory is chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	// Drop anything written after the last checkpoint
+	if err := file.Truncate(transfer.Offset); err != nil {
+		return fmt.Errorf("failed to truncate %s: %w", path, err)
+	}
+	if _, err := file.Seek(transfer.Offset, io.SeekStart); err != nil {
+		return err
+	}
+	out := &countingWriter{w: file, n: transfer.Offset}
+	writer, err := newDocumentWriter(transfer.Format, out, opts.Fields)
+	if err != nil {
+		return err
+	}
+	if transfer.Offset == 0 {
+		if err := writer.WriteHeader(); err != nil {
+			return err
+		}
+	}
+
+	filter, err := exportFilter(opts.Filter, transfer.LastID)
+	if err != nil {
+		return err
+	}
+	projection, stripID := exportProjection(opts.Projection)
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetBatchSize(int32(min(batchSize, 1<<20))) //nolint:gosec // bounded above
+	if len(projection) > 0 {
+		findOptions.SetProjection(projection)
+	}
+
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", collection.Name(), err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var lastID bson.RawValue
+	var pending int64
+	checkpoint := func(done bool) error {
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		var id string
+		if lastID.Type != 0 {
+			data, err := bson.MarshalExtJSON(bson.D{{Key: "_id", Value: lastID}}, true, false)
+			if err != nil {
+				return fmt.Errorf("failed to record the last exported _id: %w", err)
+			}
+			id = string(data)
+		}
+		advance := pending
+		pending = 0
+		return run.update(advance, func() {
+			transfer.Documents += advance
+			transfer.Offset = out.n
+			if id != "" {
+				transfer.LastID = id
+			}
+			transfer.Done = done
+			transfer.Error = ""
+		})
+	}
+
+	for cursor.Next(ctx) {
+		doc := cursor.Current
+		lastID = doc.Lookup("_id")
+		if stripID {
+			if doc, err = withoutID(doc); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(doc); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		pending++
+		if pending == int64(batchSize) {
+			if err := checkpoint(false); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		// Keep what was read so the export resumes after it
+		_ = checkpoint(false)
+		return fmt.Errorf("failed to read %s: %w", collection.Name(), err)
+	}
+	return checkpoint(true)
+}
+
+// exportFilter restricts the filter to documents after the last exported _id. Resuming
+// relies on _id order, which holds when the _id values of a collection share a type.
+func exportFilter(filter map[string]interface{}, lastID string) (interface{}, error) {
+	if lastID == "" {
+		return toFilter(filter), nil
+	}
+	var last bson.D
+	if err := bson.UnmarshalExtJSON([]byte(lastID), true, &last); err != nil || len(last) != 1 {
+		return nil, fmt.Errorf("invalid checkpoint _id %s", lastID)
+	}
+	after := bson.M{"_id": bson.M{"$gt": last[0].Value}}
+	if len(filter) == 0 {
+		return after, nil
+	}
+	return bson.M{"$and": bson.A{toFilter(filter), after}}, nil
+}
+
+// exportProjection keeps _id in the projection, which checkpoints need, and reports
+// whether it must be removed from the written documents
+func exportProjection(projection map[string]interface{}) (map[string]interface{}, bool) {
+	id, ok := projection["_id"]
+	if !ok {
+		return projection, false
+	}
+	switch v := id.(type) {
+	case bool:
+		ok = !v
+	case int32:
+		ok = v == 0
+	case int64:
+		ok = v == 0
+	case float64:
+		ok = v == 0
+	default:
+		ok = false
+	}
+	if !ok {
+		return projection, false
+	}
+	kept := make(map[string]interface{}, len(projection))
+	for k, v := range projection {
+		if k != "_id" {
+			kept[k] = v
+		}
+	}
+	return kept, true
+}
+
+func withoutID(doc bson.Raw) (bson.Raw, error) {
+	var fields bson.D
+	if err := bson.Unmarshal(doc, &fields); err != nil {
+		return nil, err
+	}
+	trimmed := fields[:0]
+	for _, field := range fields {
+		if field.Key != "_id" {
+			trimmed = append(trimmed, field)
+		}
+	}
+	return bson.Marshal(trimmed)
+}
+
+// Import loads files into collections, streaming several files in parallel and writing in
+// ordered batches. A checkpoint is saved after every batch; when a batch fails, the
+// documents written before the failure are recorded so a resumed import continues with
+// the document that failed.
+func (ds *DocumentService) Import(ctx context.Context, connInfo *types.ConnectionInfo, opts ImportOptions) ([]CollectionTransfer, error) {
+	if opts.Database == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+	if len(opts.Files) == 0 {
+		return nil, fmt.Errorf("at least one file is required")
+	}
+	if opts.Resume && opts.Drop {
+		return nil, fmt.Errorf("drop cannot be combined with resume")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultTransferBatchSize
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+	db := client.GetUnderlyingClient().Database(opts.Database)
+
+	state := &TransferState{Operation: "import", Database: opts.Database, Transfers: make(map[string]*CollectionTransfer)}
+	if opts.Resume {
+		if state, err = loadTransferState(opts.StatePath, "import", opts.Database); err != nil {
+			return nil, err
+		}
+	}
+
+	run := &transferRun{path: opts.StatePath, state: state, progress: opts.Progress}
+	dropped := make(map[string]bool)
+	for _, file := range opts.Files {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Path, err)
+		}
+		transfer, ok := state.Transfers[file.Path]
+		if !ok {
+			transfer = &CollectionTransfer{Collection: file.Collection, File: file.Path, Format: file.Format}
+			state.Transfers[file.Path] = transfer
+		}
+		if transfer.Offset > info.Size() {
+			return nil, fmt.Errorf("%s is smaller than its checkpoint; it changed since the import started", file.Path)
+		}
+		if !transfer.Done {
+			run.total += info.Size() - transfer.Offset
+		}
+
+		if opts.Drop && !dropped[file.Collection] {
+			if err := db.Collection(file.Collection).Drop(ctx); err != nil {
+				return nil, fmt.Errorf("failed to drop collection %s: %w", file.Collection, err)
+			}
+			dropped[file.Collection] = true
+		}
+	}
+	if err := saveTransferState(opts.StatePath, state); err != nil {
+		return nil, err
+	}
+
+	err = run.run(opts.Parallel, func(transfer *CollectionTransfer) error {
+		return importFile(ctx, db.Collection(transfer.Collection), transfer, opts, batchSize, run)
+	})
+
+	ds.logger.Debug("Imported files",
+		"database", opts.Database,
+		"files", len(opts.Files),
+		"bytes", run.done)
+
+	return run.results(), err
+}
+
+func importFile(ctx context.Context, collection *mongo.Collection, transfer *CollectionTransfer, opts ImportOptions, batchSize int, run *transferRun) error {
+	file, err := os.Open(transfer.File) //nolint:gosec // the imported files are chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", transfer.File, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader, err := newDocumentReader(transfer.Format, file, transfer.Offset, opts.Columns)
+	if err != nil {
+		return fmt.Errorf("%s: %w", transfer.File, err)
+	}
+
+	models := make([]mongo.WriteModel, 0, batchSize)
+	// ends holds the file offset after each document of the batch
+	ends := make([]int64, 0, batchSize)
+	flush := func(done bool) error {
+		written := len(models)
+		var writeErr error
+		if written > 0 {
+			if _, writeErr = collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true)); writeErr != nil {
+				written = 0
+				var bulkErr mongo.BulkWriteException
+				if errors.As(writeErr, &bulkErr) && len(bulkErr.WriteErrors) > 0 {
+					written = bulkErr.WriteErrors[0].Index
+				}
+			}
+		}
+
+		offset := transfer.Offset
+		if written > 0 {
+			offset = ends[written-1]
+		}
+		if done && writeErr == nil {
+			offset = reader.Offset()
+		}
+		advance := offset - transfer.Offset
+		models, ends = models[:0], ends[:0]
+		if err := run.update(advance, func() {
+			transfer.Documents += int64(written)
+			transfer.Offset = offset
+			transfer.Done = done && writeErr == nil
+			transfer.Error = ""
+		}); err != nil {
+			return err
+		}
+		if writeErr != nil {
+			return fmt.Errorf("failed to write to %s: %w", collection.Name(), writeErr)
+		}
+		return nil
+	}
+
+	for {
+		doc, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return flush(true)
+		}
+		if err != nil {
+			// Keep the documents read before the invalid one
+			if flushErr := flush(false); flushErr != nil {
+				return flushErr
+			}
+			return fmt.Errorf("%s: %w", transfer.File, err)
+		}
+
+		models = append(models, importModel(doc, opts.Upsert))
+		ends = append(ends, reader.Offset())
+		if len(models) == batchSize {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// importModel inserts a document, or replaces the document with its _id when upserting
+func importModel(doc bson.D, upsert bool) mongo.WriteModel {
+	if upsert {
+		for _, field := range doc {
+			if field.Key == "_id" {
+				return mongo.NewReplaceOneModel().SetFilter(bson.D{{Key: "_id", Value: field.Value}}).SetReplacement(doc).SetUpsert(true)
+			}
+		}
+	}
+	return mongo.NewInsertOneModel().SetDocument(doc)
+}
+
+func loadTransferState(path, operation, databaseName string) (*TransferState, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // the checkpoint path is derived from user input
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no %s checkpoint found at %s", operation, path)
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	state := &TransferState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if state.Operation != operation || state.Database != databaseName {
+		return nil, fmt.Errorf("checkpoint %s is for the %s of database '%s'", path, state.Operation, state.Database)
+	}
+	if state.Transfers == nil {
+		state.Transfers = make(map[string]*CollectionTransfer)
+	}
+	return state, nil
+}
+
+// saveTransferState writes the checkpoint through a temporary file so it is never left
+// half written
+func saveTransferState(path string, state *TransferState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// countingWriter tracks the file position of written bytes
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,451 @@
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TransferFormat is a file format of export and import
+type TransferFormat string
+
+// Transfer formats
+const (
+	// FormatNDJSON is relaxed Extended JSON, one document per line
+	FormatNDJSON TransferFormat = "ndjson"
+	// FormatExtJSON is canonical Extended JSON, one document per line, which keeps every BSON type
+	FormatExtJSON TransferFormat = "extjson"
+	// FormatCSV is comma-separated values with a header row
+	FormatCSV TransferFormat = "csv"
+	// FormatBSON is concatenated BSON documents, as in mongodump .bson files
+	FormatBSON TransferFormat = "bson"
+)
+
+// maxBSONDocumentSize is the largest document MongoDB stores
+const maxBSONDocumentSize = 16 * 1024 * 1024
+
+// ParseTransferFormat validates a format name
+func ParseTransferFormat(name string) (TransferFormat, error) {
+	switch format := TransferFormat(strings.ToLower(name)); format {
+	case FormatNDJSON, FormatExtJSON, FormatCSV, FormatBSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported format '%s': must be ndjson, extjson, csv or bson", name)
+}
+
+// TransferFormatFromPath returns the format of a file from its extension
+func TransferFormatFromPath(path string) (TransferFormat, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl", ".json":
+		return FormatNDJSON, true
+	case ".csv":
+		return FormatCSV, true
+	case ".bson":
+		return FormatBSON, true
+	}
+	return "", false
+}
+
+// Extension returns the file extension export uses for the format
+func (f TransferFormat) Extension() string {
+	switch f {
+	case FormatCSV:
+		return ".csv"
+	case FormatBSON:
+		return ".bson"
+	}
+	return ".ndjson"
+}
+
+// documentWriter encodes exported documents
+type documentWriter interface {
+	// WriteHeader starts a new file; it is not called when appending to a resumed file
+	WriteHeader() error
+	Write(doc bson.Raw) error
+	Flush() error
+}
+
+func newDocumentWriter(format TransferFormat, w io.Writer, fields []string) (documentWriter, error) {
+	buffered := bufio.NewWriter(w)
+	switch format {
+	case FormatNDJSON, FormatExtJSON:
+		return &jsonLinesWriter{w: buffered, canonical: format == FormatExtJSON}, nil
+	case FormatCSV:
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("csv export requires the fields to write")
+		}
+		return &csvWriter{w: csv.NewWriter(buffered), buffered: buffered, fields: fields}, nil
+	case FormatBSON:
+		return &bsonWriter{w: buffered}, nil
+	}
+	return nil, fmt.Errorf("unsupported format '%s'", format)
+}
+
+type jsonLinesWriter struct {
+	w         *bufio.Writer
+	canonical bool
+}
+
+func (j *jsonLinesWriter) WriteHeader() error { return nil }
+
+func (j *jsonLinesWriter) Write(doc bson.Raw) error {
+	data, err := bson.MarshalExtJSON(doc, j.canonical, false)
+	if err != nil {
+		return fmt.Errorf("failed to encode document as Extended JSON: %w", err)
+	}
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+	return j.w.WriteByte('\n')
+}
+
+func (j *jsonLinesWriter) Flush() error { return j.w.Flush() }
+
+type csvWriter struct {
+	w        *csv.Writer
+	buffered *bufio.Writer
+	fields   []string
+}
+
+func (c *csvWriter) WriteHeader() error { return c.w.Write(c.fields) }
+
+// Write writes the value at each field path: strings as is, missing fields as empty
+// cells and any other value as compact relaxed Extended JSON
+func (c *csvWriter) Write(doc bson.Raw) error {
+	record := make([]string, len(c.fields))
+	for i, field := range c.fields {
+		value, err := doc.LookupErr(strings.Split(field, ".")...)
+		if err != nil {
+			continue
+		}
+		if record[i], err = csvCell(value); err != nil {
+			return err
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+	return c.buffered.Flush()
+}
+
+func csvCell(value bson.RawValue) (string, error) {
+	if s, ok := value.StringValueOK(); ok {
+		return s, nil
+	}
+	data, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: value}}, false, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode field value: %w", err)
+	}
+	return string(data[len(`{"v":`) : len(data)-1]), nil
+}
+
+type bsonWriter struct {
+	w *bufio.Writer
+}
+
+func (b *bsonWriter) WriteHeader() error { return nil }
+
+func (b *bsonWriter) Write(doc bson.Raw) error {
+	_, err := b.w.Write(doc)
+	return err
+}
+
+func (b *bsonWriter) Flush() error { return b.w.Flush() }
+
+// documentReader decodes documents to import. Offset is the position in the file just
+// after the last document returned, from which a resumed import continues.
+type documentReader interface {
+	Next() (bson.D, error)
+	Offset() int64
+}
+
+// newDocumentReader reads documents from r starting at offset, the Offset of a previous
+// reader of the same file. CSV files are always read from the start for their header.
+func newDocumentReader(format TransferFormat, r io.ReadSeeker, offset int64, columns []CSVColumn) (documentReader, error) {
+	if format == FormatCSV {
+		return newCSVReader(r, offset, columns)
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatNDJSON, FormatExtJSON:
+		return &jsonLinesReader{r: bufio.NewReaderSize(r, 64*1024), offset: offset}, nil
+	case FormatBSON:
+		return &bsonReader{r: bufio.NewReaderSize(r, 64*1024), offset: offset}, nil
+	}
+	return nil, fmt.Errorf("unsupported format '%s'", format)
+}
+
+type jsonLinesReader struct {
+	r      *bufio.Reader
+	offset int64
+}
+
+func (j *jsonLinesReader) Next() (bson.D, error) {
+	for {
+		data, err := j.r.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return nil, err
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		start := j.offset
+		j.offset += int64(len(data))
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON(data, false, &doc); err != nil {
+			return nil, fmt.Errorf("invalid document at byte %d: %w", start, err)
+		}
+		return doc, nil
+	}
+}
+
+func (j *jsonLinesReader) Offset() int64 { return j.offset }
+
+type bsonReader struct {
+	r      *bufio.Reader
+	offset int64
+}
+
+func (b *bsonReader) Next() (bson.D, error) {
+	header, err := b.r.Peek(4)
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("truncated BSON document at byte %d", b.offset)
+	}
+	size := int(binary.LittleEndian.Uint32(header))
+	if size < 5 || size > maxBSONDocumentSize {
+		return nil, fmt.Errorf("invalid BSON document size %d at byte %d", size, b.offset)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(b.r, data); err != nil {
+		return nil, fmt.Errorf("truncated BSON document at byte %d", b.offset)
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid BSON document at byte %d: %w", b.offset, err)
+	}
+	b.offset += int64(size)
+	return doc, nil
+}
+
+func (b *bsonReader) Offset() int64 { return b.offset }
+
+// CSV column types
+const (
+	CSVTypeAuto     = "auto"
+	CSVTypeString   = "string"
+	CSVTypeInt      = "int"
+	CSVTypeLong     = "long"
+	CSVTypeDouble   = "double"
+	CSVTypeBool     = "bool"
+	CSVTypeDate     = "date"
+	CSVTypeObjectID = "objectId"
+	CSVTypeJSON     = "json"
+)
+
+// CSVColumn maps a CSV header to a document field and the type its cells are read as
+type CSVColumn struct {
+	Header string
+	// Field is a dotted path; nested documents are created as needed
+	Field string
+	Type  string
+}
+
+// ParseCSVColumn parses a column mapping of the form header=field[:type] or header:type
+func ParseCSVColumn(spec string) (CSVColumn, error) {
+	column := CSVColumn{Type: CSVTypeAuto}
+	target := spec
+	header, field, mapped := strings.Cut(spec, "=")
+	if mapped {
+		column.Header, target = header, field
+	}
+	if field, typ, ok := strings.Cut(target, ":"); ok {
+		target, column.Type = field, typ
+	}
+	if !mapped {
+		column.Header = target
+	}
+	column.Field = target
+
+	if column.Header == "" || column.Field == "" {
+		return CSVColumn{}, fmt.Errorf("invalid column mapping '%s': expected header=field[:type]", spec)
+	}
+	switch column.Type {
+	case CSVTypeAuto, CSVTypeString, CSVTypeInt, CSVTypeLong, CSVTypeDouble, CSVTypeBool, CSVTypeDate, CSVTypeObjectID, CSVTypeJSON:
+	default:
+		return CSVColumn{}, fmt.Errorf("invalid column type '%s' in '%s': must be auto, string, int, long, double, bool, date, objectId or json", column.Type, spec)
+	}
+	return column, nil
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns []CSVColumn
+	base    int64
+	offset  int64
+}
+
+func newCSVReader(r io.ReadSeeker, offset int64, mappings []CSVColumn) (*csvReader, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	headers, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("csv file has no header row")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	byHeader := make(map[string]CSVColumn, len(mappings))
+	for _, mapping := range mappings {
+		byHeader[mapping.Header] = mapping
+	}
+	columns := make([]CSVColumn, len(headers))
+	for i, header := range headers {
+		column, ok := byHeader[header]
+		if !ok {
+			column = CSVColumn{Header: header, Field: header, Type: CSVTypeAuto}
+		}
+		columns[i] = column
+	}
+
+	c := &csvReader{r: reader, columns: columns, offset: reader.InputOffset()}
+	if offset > c.offset {
+		// Continue after the last imported row with a reader that starts there
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		c.r = csv.NewReader(r)
+		c.r.ReuseRecord = true
+		c.base, c.offset = offset, offset
+	}
+	return c, nil
+}
+
+func (c *csvReader) Next() (bson.D, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	c.offset = c.base + c.r.InputOffset()
+
+	var doc bson.D
+	for i, cell := range record {
+		if i >= len(c.columns) || cell == "" {
+			continue
+		}
+		value, err := csvValue(cell, c.columns[i].Type)
+		if err != nil {
+			line, _ := c.r.FieldPos(i)
+			return nil, fmt.Errorf("line %d, column %s: %w", line, c.columns[i].Header, err)
+		}
+		doc = setPath(doc, strings.Split(c.columns[i].Field, "."), value)
+	}
+	return doc, nil
+}
+
+func (c *csvReader) Offset() int64 { return c.offset }
+
+// autoNumberPattern matches the cells auto reads as numbers. Values with leading zeros,
+// such as postal codes, stay strings.
+var autoNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// csvValue converts a CSV cell to the column type. Auto reads integers, decimals and
+// true/false as numbers and booleans and anything else as a string.
+func csvValue(cell, typ string) (interface{}, error) {
+	switch typ {
+	case CSVTypeString:
+		return cell, nil
+	case CSVTypeInt:
+		v, err := strconv.ParseInt(cell, 10, 32)
+		return int32(v), err
+	case CSVTypeLong:
+		return strconv.ParseInt(cell, 10, 64)
+	case CSVTypeDouble:
+		return strconv.ParseFloat(cell, 64)
+	case CSVTypeBool:
+		return strconv.ParseBool(cell)
+	case CSVTypeDate:
+		t, err := time.Parse(time.RFC3339Nano, cell)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, cell)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid date '%s': use RFC 3339 or YYYY-MM-DD", cell)
+		}
+		return primitive.NewDateTimeFromTime(t), nil
+	case CSVTypeObjectID:
+		return primitive.ObjectIDFromHex(cell)
+	case CSVTypeJSON:
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON([]byte(`{"v":`+cell+`}`), false, &doc); err != nil || len(doc) != 1 {
+			return nil, fmt.Errorf("invalid Extended JSON value '%s'", cell)
+		}
+		return doc[0].Value, nil
+	}
+
+	if autoNumberPattern.MatchString(cell) {
+		if v, err := strconv.ParseInt(cell, 10, 64); err == nil {
+			if v >= -1<<31 && v < 1<<31 {
+				return int32(v), nil
+			}
+			return v, nil
+		}
+		if v, err := strconv.ParseFloat(cell, 64); err == nil {
+			return v, nil
+		}
+	}
+	if cell == "true" || cell == "false" {
+		return cell == "true", nil
+	}
+	return cell, nil
+}
+
+// setPath sets a dotted path in an ordered document, creating nested documents
+func setPath(doc bson.D, path []string, value interface{}) bson.D {
+	for i := range doc {
+		if doc[i].Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			doc[i].Value = value
+		} else if nested, ok := doc[i].Value.(bson.D); ok {
+			doc[i].Value = setPath(nested, path[1:], value)
+		}
+		return doc
+	}
+	if len(path) == 1 {
+		return append(doc, bson.E{Key: path[0], Value: value})
+	}
+	return append(doc, bson.E{Key: path[0], Value: setPath(nil, path[1:], value)})
+}
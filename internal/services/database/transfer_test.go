@@ -0,0 +1,233 @@
+package database
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func marshalRaw(t *testing.T, doc bson.D) bson.Raw {
+	t.Helper()
+	data, err := bson.Marshal(doc)
+	require.NoError(t, err)
+	return data
+}
+
+func readAll(t *testing.T, reader documentReader) []bson.D {
+	t.Helper()
+	var docs []bson.D
+	for {
+		doc, err := reader.Next()
+		if err == io.EOF {
+			return docs
+		}
+		require.NoError(t, err)
+		docs = append(docs, doc)
+	}
+}
+
+func TestParseTransferFormat(t *testing.T) {
+	format, err := ParseTransferFormat("CSV")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	_, err = ParseTransferFormat("xml")
+	assert.Error(t, err)
+
+	format, ok := TransferFormatFromPath("dump/users.jsonl")
+	assert.True(t, ok)
+	assert.Equal(t, FormatNDJSON, format)
+	_, ok = TransferFormatFromPath("users.txt")
+	assert.False(t, ok)
+
+	assert.Equal(t, ".ndjson", FormatExtJSON.Extension())
+	assert.Equal(t, ".bson", FormatBSON.Extension())
+}
+
+func TestTransferFormats_RoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	docs := []bson.D{
+		{{Key: "_id", Value: id}, {Key: "name", Value: "Ada"}, {Key: "age", Value: int64(36)}},
+		{{Key: "_id", Value: "second"}, {Key: "tags", Value: bson.A{"a", "b"}}},
+	}
+
+	for _, format := range []TransferFormat{FormatNDJSON, FormatExtJSON, FormatBSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := newDocumentWriter(format, &buf, nil)
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteHeader())
+			for _, doc := range docs {
+				require.NoError(t, writer.Write(marshalRaw(t, doc)))
+			}
+			require.NoError(t, writer.Flush())
+
+			reader, err := newDocumentReader(format, bytes.NewReader(buf.Bytes()), 0, nil)
+			require.NoError(t, err)
+			read := readAll(t, reader)
+			require.Len(t, read, 2)
+			assert.Equal(t, id, read[0][0].Value)
+			assert.Equal(t, "Ada", read[0][1].Value)
+			assert.Equal(t, "second", read[1][0].Value)
+			assert.Equal(t, int64(buf.Len()), reader.Offset())
+		})
+	}
+}
+
+func TestDocumentReader_ResumesAtOffset(t *testing.T) {
+	for _, format := range []TransferFormat{FormatNDJSON, FormatBSON, FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := newDocumentWriter(format, &buf, []string{"n"})
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteHeader())
+			for n := int32(1); n <= 3; n++ {
+				require.NoError(t, writer.Write(marshalRaw(t, bson.D{{Key: "n", Value: n}})))
+			}
+			require.NoError(t, writer.Flush())
+
+			reader, err := newDocumentReader(format, bytes.NewReader(buf.Bytes()), 0, nil)
+			require.NoError(t, err)
+			_, err = reader.Next()
+			require.NoError(t, err)
+			checkpoint := reader.Offset()
+
+			resumed, err := newDocumentReader(format, bytes.NewReader(buf.Bytes()), checkpoint, nil)
+			require.NoError(t, err)
+			read := readAll(t, resumed)
+			require.Len(t, read, 2)
+			assert.Equal(t, int32(2), read[0][0].Value)
+			assert.Equal(t, int32(3), read[1][0].Value)
+		})
+	}
+}
+
+func TestCSVWriter_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := newDocumentWriter(FormatCSV, &buf, []string{"name", "address.city", "missing"})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteHeader())
+	require.NoError(t, writer.Write(marshalRaw(t, bson.D{
+		{Key: "name", Value: "Ada, Countess"},
+		{Key: "address", Value: bson.D{{Key: "city", Value: "London"}}},
+	})))
+	require.NoError(t, writer.Flush())
+
+	assert.Equal(t, "name,address.city,missing\n\"Ada, Countess\",London,\n", buf.String())
+}
+
+func TestCSVReader_ColumnMapping(t *testing.T) {
+	input := "id,zip,score,joined,city,active\n" +
+		"507f1f77bcf86cd799439011,02134,9.5,2024-03-01,Boston,true\n"
+
+	id, err := ParseCSVColumn("id=_id:objectId")
+	require.NoError(t, err)
+	joined, err := ParseCSVColumn("joined:date")
+	require.NoError(t, err)
+	city, err := ParseCSVColumn("city=address.city")
+	require.NoError(t, err)
+
+	reader, err := newDocumentReader(FormatCSV, strings.NewReader(input), 0, []CSVColumn{id, joined, city})
+	require.NoError(t, err)
+	docs := readAll(t, reader)
+	require.Len(t, docs, 1)
+
+	doc := docs[0]
+	require.Len(t, doc, 6)
+	assert.Equal(t, "_id", doc[0].Key)
+	assert.IsType(t, primitive.ObjectID{}, doc[0].Value)
+	assert.Equal(t, "02134", doc[1].Value, "leading zeros keep auto values as strings")
+	assert.Equal(t, 9.5, doc[2].Value)
+	assert.IsType(t, primitive.DateTime(0), doc[3].Value)
+	assert.Equal(t, bson.D{{Key: "city", Value: "Boston"}}, doc[4].Value)
+	assert.Equal(t, true, doc[5].Value)
+}
+
+func TestParseCSVColumn_Errors(t *testing.T) {
+	for _, spec := range []string{"", "=field", "name:decimal"} {
+		_, err := ParseCSVColumn(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestCSVValue_Auto(t *testing.T) {
+	tests := map[string]interface{}{
+		"42":          int32(42),
+		"5000000000":  int64(5000000000),
+		"-1.5":        -1.5,
+		"false":       false,
+		"nan":         "nan",
+		"007":         "007",
+		"hello world": "hello world",
+	}
+	for cell, expected := range tests {
+		value, err := csvValue(cell, CSVTypeAuto)
+		require.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+
+	_, err := csvValue("abc", CSVTypeInt)
+	assert.Error(t, err)
+}
+
+func TestExportFilter(t *testing.T) {
+	filter, err := exportFilter(nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{}, filter)
+
+	filter, err = exportFilter(map[string]interface{}{"status": "active"}, `{"_id":{"$numberInt":"7"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"$and": bson.A{
+		bson.M{"status": "active"},
+		bson.M{"_id": bson.M{"$gt": int32(7)}},
+	}}, filter)
+
+	_, err = exportFilter(nil, "not json")
+	assert.Error(t, err)
+}
+
+func TestExportProjection(t *testing.T) {
+	projection, strip := exportProjection(map[string]interface{}{"_id": 0.0, "name": 1.0})
+	assert.True(t, strip)
+	assert.Equal(t, map[string]interface{}{"name": 1.0}, projection)
+
+	projection, strip = exportProjection(map[string]interface{}{"name": 1.0})
+	assert.False(t, strip)
+	assert.Equal(t, map[string]interface{}{"name": 1.0}, projection)
+
+	doc, err := withoutID(marshalRaw(t, bson.D{{Key: "_id", Value: 1}, {Key: "name", Value: "Ada"}}))
+	require.NoError(t, err)
+	_, err = doc.LookupErr("_id")
+	assert.Error(t, err)
+}
+
+func TestTransferState_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ExportStateFile)
+	state := &TransferState{
+		Operation: "export",
+		Database:  "shop",
+		Transfers: map[string]*CollectionTransfer{
+			"orders.ndjson": {Collection: "orders", File: "orders.ndjson", Format: FormatNDJSON, Documents: 10, Offset: 512},
+		},
+	}
+	require.NoError(t, saveTransferState(path, state))
+	_, err := os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	loaded, err := loadTransferState(path, "export", "shop")
+	require.NoError(t, err)
+	assert.Equal(t, state, loaded)
+
+	_, err = loadTransferState(path, "import", "shop")
+	assert.Error(t, err)
+	_, err = loadTransferState(filepath.Join(t.TempDir(), ExportStateFile), "export", "shop")
+	assert.ErrorContains(t, err, "no export checkpoint")
+}
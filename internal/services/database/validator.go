@@ -0,0 +1,321 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// GetValidator returns the schema validation configuration of a collection. Collections
+// without an explicit validation level or action report the server defaults.
+func (s *Service) GetValidator(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string) (*types.CollectionValidator, error) {
+	if databaseName == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return nil, fmt.Errorf("collection name is required")
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	collections, err := client.ListCollections(ctx, databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	for _, collection := range collections {
+		if collection.Name == collectionName {
+			return collectionValidator(collection.Options), nil
+		}
+	}
+	return nil, fmt.Errorf("collection '%s' not found in database '%s'", collectionName, databaseName)
+}
+
+// collectionValidator reads the validation settings from listCollections options. The
+// validator keeps its BSON values so it can be sent back to the server unchanged.
+func collectionValidator(options map[string]interface{}) *types.CollectionValidator {
+	validator := &types.CollectionValidator{
+		ValidationLevel:  types.ValidationLevelStrict,
+		ValidationAction: types.ValidationActionError,
+	}
+	switch v := options["validator"].(type) {
+	case bson.M:
+		validator.Validator = v
+	case map[string]interface{}:
+		validator.Validator = v
+	}
+	if level, ok := options["validationLevel"].(string); ok && level != "" {
+		validator.ValidationLevel = level
+	}
+	if action, ok := options["validationAction"].(string); ok && action != "" {
+		validator.ValidationAction = action
+	}
+	return validator
+}
+
+// TestValidator matches a random sample of documents against a validator and returns the
+// documents it would reject. A sampleSize of 0 tests every document of the collection.
+func (ds *DocumentService) TestValidator(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, collectionName string, validator map[string]interface{}, sampleSize, maxRejected int64) (*types.ValidatorTestResult, error) {
+	if databaseName == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+	if collectionName == "" {
+		return nil, fmt.Errorf("collection name is required")
+	}
+	if len(validator) == 0 {
+		return nil, fmt.Errorf("validator is required")
+	}
+
+	client, err := ds.dbService.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := client.GetUnderlyingClient().Database(databaseName).Collection(collectionName)
+
+	cursor, err := collection.Aggregate(ctx, validatorTestPipeline(validator, sampleSize, maxRejected))
+	if err != nil {
+		return nil, fmt.Errorf("failed to test validator: %w", err)
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var facets []struct {
+		Sampled       []struct{ Count int64 } `bson:"sampled"`
+		RejectedCount []struct{ Count int64 } `bson:"rejectedCount"`
+		Rejected      []bson.M                `bson:"rejected"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, fmt.Errorf("failed to read validator test results: %w", err)
+	}
+
+	result := &types.ValidatorTestResult{}
+	if len(facets) > 0 {
+		if len(facets[0].Sampled) > 0 {
+			result.Sampled = facets[0].Sampled[0].Count
+		}
+		if len(facets[0].RejectedCount) > 0 {
+			result.Rejected = facets[0].RejectedCount[0].Count
+		}
+		for _, doc := range facets[0].Rejected {
+			result.RejectedDocuments = append(result.RejectedDocuments, doc)
+		}
+	}
+
+	ds.logger.Debug("Tested validator",
+		"database", databaseName,
+		"collection", collectionName,
+		"sampled", result.Sampled,
+		"rejected", result.Rejected)
+
+	return result, nil
+}
+
+// validatorTestPipeline samples documents and counts those that do not match the
+// validator, keeping the first maxRejected of them
+func validatorTestPipeline(validator map[string]interface{}, sampleSize, maxRejected int64) mongo.Pipeline {
+	rejected := bson.D{{Key: "$match", Value: bson.M{"$nor": bson.A{validator}}}}
+	facets := bson.D{
+		{Key: "sampled", Value: bson.A{bson.D{{Key: "$count", Value: "count"}}}},
+		{Key: "rejectedCount", Value: bson.A{rejected, bson.D{{Key: "$count", Value: "count"}}}},
+	}
+	if maxRejected > 0 {
+		facets = append(facets, bson.E{Key: "rejected", Value: bson.A{rejected, bson.D{{Key: "$limit", Value: maxRejected}}}})
+	}
+
+	var pipeline mongo.Pipeline
+	if sampleSize > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$sample", Value: bson.M{"size": sampleSize}}})
+	}
+	return append(pipeline, bson.D{{Key: "$facet", Value: facets}})
+}
+
+// InferJSONSchema drafts a $jsonSchema from sample documents. Each field gets the BSON
+// types seen for it, with mixed numeric types widened to "number", and fields present in
+// every sampled document are required. Nested documents and array items are described
+// the same way. The draft is meant to be reviewed before it is enforced.
+func InferJSONSchema(documents []bson.D) map[string]interface{} {
+	root := &schemaNode{}
+	for _, doc := range documents {
+		root.add(doc)
+	}
+	if root.objects == 0 {
+		return map[string]interface{}{"bsonType": "object"}
+	}
+	return root.schema()
+}
+
+// schemaNode accumulates the values seen at one path of the sampled documents
+type schemaNode struct {
+	types map[string]bool
+	// present counts the parent documents that contain the field
+	present    int
+	objects    int
+	properties map[string]*schemaNode
+	items      *schemaNode
+}
+
+func (n *schemaNode) add(value interface{}) {
+	bsonType := bsonTypeOf(value)
+	if bsonType == "" {
+		return
+	}
+	if n.types == nil {
+		n.types = make(map[string]bool)
+	}
+	n.types[bsonType] = true
+
+	switch bsonType {
+	case "object":
+		n.objects++
+		if n.properties == nil {
+			n.properties = make(map[string]*schemaNode)
+		}
+		for key, fieldValue := range documentFields(value) {
+			child, ok := n.properties[key]
+			if !ok {
+				child = &schemaNode{}
+				n.properties[key] = child
+			}
+			child.present++
+			child.add(fieldValue)
+		}
+	case "array":
+		if n.items == nil {
+			n.items = &schemaNode{}
+		}
+		for _, item := range arrayItems(value) {
+			n.items.add(item)
+		}
+	}
+}
+
+func (n *schemaNode) schema() map[string]interface{} {
+	schema := make(map[string]interface{})
+	if bsonTypes := n.bsonTypes(); len(bsonTypes) == 1 {
+		schema["bsonType"] = bsonTypes[0]
+	} else if len(bsonTypes) > 1 {
+		schema["bsonType"] = bsonTypes
+	}
+
+	if len(n.properties) > 0 {
+		properties := make(map[string]interface{}, len(n.properties))
+		var required []string
+		for key, child := range n.properties {
+			properties[key] = child.schema()
+			if child.present == n.objects {
+				required = append(required, key)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+	if n.items != nil && len(n.items.types) > 0 {
+		schema["items"] = n.items.schema()
+	}
+	return schema
+}
+
+// bsonTypes returns the sorted type aliases seen, widening mixed numeric types to number
+func (n *schemaNode) bsonTypes() []string {
+	numeric := 0
+	for _, t := range []string{"int", "long", "double", "decimal"} {
+		if n.types[t] {
+			numeric++
+		}
+	}
+
+	var bsonTypes []string
+	for t := range n.types {
+		switch t {
+		case "int", "long", "double", "decimal":
+			if numeric > 1 {
+				continue
+			}
+		}
+		bsonTypes = append(bsonTypes, t)
+	}
+	if numeric > 1 {
+		bsonTypes = append(bsonTypes, "number")
+	}
+	sort.Strings(bsonTypes)
+	return bsonTypes
+}
+
+// bsonTypeOf returns the $jsonSchema bsonType alias of a decoded BSON value
+func bsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil, primitive.Null:
+		return "null"
+	case string:
+		return "string"
+	case int32, int:
+		return "int"
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case primitive.Decimal128:
+		return "decimal"
+	case bool:
+		return "bool"
+	case primitive.ObjectID:
+		return "objectId"
+	case primitive.DateTime, time.Time:
+		return "date"
+	case primitive.Timestamp:
+		return "timestamp"
+	case primitive.Binary:
+		return "binData"
+	case primitive.Regex:
+		return "regex"
+	case primitive.JavaScript:
+		return "javascript"
+	case primitive.MinKey:
+		return "minKey"
+	case primitive.MaxKey:
+		return "maxKey"
+	case bson.M, map[string]interface{}, bson.D:
+		return "object"
+	case bson.A, []interface{}:
+		return "array"
+	}
+	return ""
+}
+
+func documentFields(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case bson.M:
+		return v
+	case map[string]interface{}:
+		return v
+	case bson.D:
+		fields := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			fields[e.Key] = e.Value
+		}
+		return fields
+	}
+	return nil
+}
+
+func arrayItems(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case bson.A:
+		return v
+	case []interface{}:
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func TestInferJSONSchema(t *testing.T) {
+	documents := []bson.D{
+		{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "email", Value: "a@example.com"},
+			{Key: "age", Value: int32(31)},
+			{Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.Now())},
+			{Key: "address", Value: map[string]interface{}{"city": "Paris", "zip": "75001"}},
+			{Key: "tags", Value: bson.A{"new", "vip"}},
+		},
+		{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "email", Value: "b@example.com"},
+			{Key: "age", Value: 31.5},
+			{Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.Now())},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Lyon"}}},
+			{Key: "nickname", Value: nil},
+		},
+	}
+
+	schema := InferJSONSchema(documents)
+
+	assert.Equal(t, "object", schema["bsonType"])
+	assert.Equal(t, []string{"_id", "address", "age", "createdAt", "email"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"bsonType": "objectId"}, properties["_id"])
+	assert.Equal(t, map[string]interface{}{"bsonType": "number"}, properties["age"], "mixed numeric types are widened")
+	assert.Equal(t, map[string]interface{}{"bsonType": "date"}, properties["createdAt"])
+	assert.Equal(t, map[string]interface{}{"bsonType": "null"}, properties["nickname"])
+	assert.Equal(t, map[string]interface{}{
+		"bsonType": "array",
+		"items":    map[string]interface{}{"bsonType": "string"},
+	}, properties["tags"])
+
+	address := properties["address"].(map[string]interface{})
+	assert.Equal(t, []string{"city"}, address["required"], "only fields present in every sampled document are required")
+	assert.Contains(t, address["properties"], "zip")
+}
+
+func TestInferJSONSchema_MixedTypesAndEmptySample(t *testing.T) {
+	schema := InferJSONSchema([]bson.D{
+		{{Key: "_id", Value: "order-1"}, {Key: "ref", Value: "abc"}},
+		{{Key: "_id", Value: "order-2"}, {Key: "ref", Value: int64(42)}},
+	})
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"bsonType": []string{"long", "string"}}, properties["ref"])
+	assert.Equal(t, map[string]interface{}{"bsonType": "string"}, properties["_id"], "any type of _id is described")
+
+	assert.Equal(t, map[string]interface{}{"bsonType": "object"}, InferJSONSchema(nil))
+}
+
+func TestValidatorTestPipeline(t *testing.T) {
+	validator := map[string]interface{}{"$jsonSchema": map[string]interface{}{"required": []string{"email"}}}
+
+	pipeline := validatorTestPipeline(validator, 500, 10)
+	require.Len(t, pipeline, 2)
+	assert.Equal(t, "$sample", pipeline[0][0].Key)
+	facets := pipeline[1][0].Value.(bson.D)
+	require.Len(t, facets, 3)
+	assert.Equal(t, "rejected", facets[2].Key)
+
+	pipeline = validatorTestPipeline(validator, 0, 0)
+	require.Len(t, pipeline, 1, "a sample size of 0 tests the whole collection")
+	assert.Len(t, pipeline[0][0].Value.(bson.D), 2, "no rejected documents are returned when none are requested")
+}
+
+func TestCollectionValidator_Defaults(t *testing.T) {
+	validator := collectionValidator(map[string]interface{}{})
+	assert.Nil(t, validator.Validator)
+	assert.Equal(t, types.ValidationLevelStrict, validator.ValidationLevel)
+	assert.Equal(t, types.ValidationActionError, validator.ValidationAction)
+
+	validator = collectionValidator(map[string]interface{}{
+		"validator":        bson.M{"$jsonSchema": bson.M{"bsonType": "object"}},
+		"validationLevel":  "moderate",
+		"validationAction": "warn",
+	})
+	assert.Contains(t, validator.Validator, "$jsonSchema")
+	assert.Equal(t, types.ValidationLevelModerate, validator.ValidationLevel)
+	assert.Equal(t, types.ValidationActionWarn, validator.ValidationAction)
+}
@@ -0,0 +1,335 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+// MaterializedViewsCollection holds the definitions of the on-demand materialized views of
+// a database, so they can be listed and refreshed by name
+const MaterializedViewsCollection = "_matlas_materialized_views"
+
+// MaterializedView is the definition of an on-demand materialized view: Pipeline runs on
+// Source and its results are merged into the collection Name each time it is refreshed
+type MaterializedView struct {
+	Name           string     `bson:"_id"`
+	Source         string     `bson:"source"`
+	Pipeline       bson.A     `bson:"pipeline"`
+	On             []string   `bson:"on,omitempty"`
+	WhenMatched    string     `bson:"whenMatched,omitempty"`
+	WhenNotMatched string     `bson:"whenNotMatched,omitempty"`
+	RefreshedAt    *time.Time `bson:"refreshedAt,omitempty"`
+}
+
+// MergeOptions returns the $merge options of the materialized view
+func (v *MaterializedView) MergeOptions() *types.MergeOptions {
+	return &types.MergeOptions{On: v.On, WhenMatched: v.WhenMatched, WhenNotMatched: v.WhenNotMatched}
+}
+
+// SetMergeOptions sets the $merge options of the materialized view
+func (v *MaterializedView) SetMergeOptions(merge *types.MergeOptions) {
+	if merge == nil {
+		merge = &types.MergeOptions{}
+	}
+	v.On, v.WhenMatched, v.WhenNotMatched = merge.On, merge.WhenMatched, merge.WhenNotMatched
+}
+
+// Info describes the materialized view with its pipeline as plain JSON values
+func (v *MaterializedView) Info() (types.ViewInfo, error) {
+	pipeline, err := PipelineValues(v.Pipeline)
+	if err != nil {
+		return types.ViewInfo{}, err
+	}
+	return types.ViewInfo{
+		Name:        v.Name,
+		Type:        types.ViewTypeMaterialized,
+		Source:      v.Source,
+		Pipeline:    pipeline,
+		Merge:       v.MergeOptions(),
+		RefreshedAt: v.RefreshedAt,
+	}, nil
+}
+
+// mergeStage builds the $merge stage that writes the results of the view into its collection
+func (v *MaterializedView) mergeStage(databaseName string) bson.D {
+	merge := v.MergeOptions().WithDefaults()
+	spec := bson.D{{Key: "into", Value: bson.D{{Key: "db", Value: databaseName}, {Key: "coll", Value: v.Name}}}}
+	if len(merge.On) > 0 {
+		spec = append(spec, bson.E{Key: "on", Value: merge.On})
+	}
+	spec = append(spec,
+		bson.E{Key: "whenMatched", Value: merge.WhenMatched},
+		bson.E{Key: "whenNotMatched", Value: merge.WhenNotMatched})
+	return bson.D{{Key: "$merge", Value: spec}}
+}
+
+// validate checks the definition of a materialized view before it is run
+func (v *MaterializedView) validate() error {
+	switch {
+	case v.Name == "":
+		return fmt.Errorf("materialized view name is required")
+	case v.Source == "":
+		return fmt.Errorf("materialized view source is required")
+	case v.Name == v.Source:
+		return fmt.Errorf("a materialized view cannot merge into its own source")
+	}
+	if err := types.ValidateMergeOptions(v.MergeOptions()); err != nil {
+		return err
+	}
+	return ValidateViewPipeline(v.Pipeline)
+}
+
+// CreateView creates a read-only view that runs pipeline on viewOn
+func (s *Service) CreateView(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, viewName, viewOn string, pipeline bson.A, collation *types.IndexCollation) error {
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if viewName == "" || viewOn == "" {
+		return fmt.Errorf("view name and source collection are required")
+	}
+	if err := ValidateViewPipeline(pipeline); err != nil {
+		return err
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return err
+	}
+	if pipeline == nil {
+		pipeline = bson.A{}
+	}
+	if err := client.CreateView(ctx, databaseName, viewName, viewOn, pipeline, collation); err != nil {
+		return err
+	}
+
+	s.logger.Info("Created view", "database", databaseName, "view", viewName, "viewOn", viewOn)
+	return nil
+}
+
+// ModifyView replaces the source and pipeline of a view. The collation of a view can't be
+// changed.
+func (s *Service) ModifyView(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, viewName, viewOn string, pipeline bson.A) error {
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if viewName == "" || viewOn == "" {
+		return fmt.Errorf("view name and source collection are required")
+	}
+	if err := ValidateViewPipeline(pipeline); err != nil {
+		return err
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return err
+	}
+	if pipeline == nil {
+		pipeline = bson.A{}
+	}
+	if err := client.ModifyCollection(ctx, databaseName, viewName, bson.D{{Key: "viewOn", Value: viewOn}, {Key: "pipeline", Value: pipeline}}); err != nil {
+		return err
+	}
+
+	s.logger.Info("Modified view", "database", databaseName, "view", viewName)
+	return nil
+}
+
+// ListViews lists the views and on-demand materialized views of a database, ordered by name
+func (s *Service) ListViews(ctx context.Context, connInfo *types.ConnectionInfo, databaseName string) ([]types.ViewInfo, error) {
+	if databaseName == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := client.ListCollections(ctx, databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	views := []types.ViewInfo{}
+	for _, collection := range collections {
+		if !collection.IsView() {
+			continue
+		}
+		view, err := ViewInfoFromCollection(collection)
+		if err != nil {
+			return nil, fmt.Errorf("view %s.%s: %w", databaseName, collection.Name, err)
+		}
+		views = append(views, view)
+	}
+
+	materialized, err := s.listMaterializedViews(ctx, connInfo, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range materialized {
+		view, err := materialized[i].Info()
+		if err != nil {
+			return nil, fmt.Errorf("materialized view %s.%s: %w", databaseName, materialized[i].Name, err)
+		}
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+
+	s.logger.Debug("Listed views", "database", databaseName, "count", len(views))
+	return views, nil
+}
+
+// GetView returns a view or on-demand materialized view by name
+func (s *Service) GetView(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, viewName string) (*types.ViewInfo, error) {
+	views, err := s.ListViews(ctx, connInfo, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range views {
+		if views[i].Name == viewName {
+			return &views[i], nil
+		}
+	}
+	return nil, fmt.Errorf("view '%s' not found in database '%s'", viewName, databaseName)
+}
+
+// ViewInfoFromCollection converts a listCollections entry of a view
+func ViewInfoFromCollection(collection types.CollectionInfo) (types.ViewInfo, error) {
+	view := types.ViewInfo{Name: collection.Name, Type: types.ViewTypeStandard}
+	if viewOn, ok := collection.Options["viewOn"].(string); ok {
+		view.Source = viewOn
+	}
+	pipeline, err := PipelineValues(collection.Options["pipeline"])
+	if err != nil {
+		return types.ViewInfo{}, err
+	}
+	view.Pipeline = pipeline
+	opts, err := types.ParseCollectionOptions(map[string]interface{}{"collation": collection.Options["collation"]})
+	if err != nil {
+		return types.ViewInfo{}, err
+	}
+	view.Collation = opts.Collation
+	return view, nil
+}
+
+// GetMaterializedView returns the definition of an on-demand materialized view, or nil when
+// the database defines none by that name
+func (s *Service) GetMaterializedView(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, viewName string) (*MaterializedView, error) {
+	if databaseName == "" {
+		return nil, fmt.Errorf("database name is required")
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+	definitions := client.GetUnderlyingClient().Database(databaseName).Collection(MaterializedViewsCollection)
+
+	var view MaterializedView
+	if err := definitions.FindOne(ctx, bson.D{{Key: "_id", Value: viewName}}).Decode(&view); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read materialized view '%s': %w", viewName, err)
+	}
+	return &view, nil
+}
+
+// listMaterializedViews reads the definitions of the materialized views of a database
+func (s *Service) listMaterializedViews(ctx context.Context, connInfo *types.ConnectionInfo, databaseName string) ([]MaterializedView, error) {
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return nil, err
+	}
+	definitions := client.GetUnderlyingClient().Database(databaseName).Collection(MaterializedViewsCollection)
+
+	cursor, err := definitions.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list materialized views: %w", err)
+	}
+	var views []MaterializedView
+	if err := cursor.All(ctx, &views); err != nil {
+		return nil, fmt.Errorf("failed to read materialized views: %w", err)
+	}
+	return views, nil
+}
+
+// RefreshMaterializedView runs the pipeline of a materialized view on its source and merges
+// the results into the view's collection, which $merge creates when it doesn't exist. The
+// definition is saved with the time of the refresh, which is set on view.
+func (s *Service) RefreshMaterializedView(ctx context.Context, connInfo *types.ConnectionInfo, databaseName string, view *MaterializedView) error {
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if err := view.validate(); err != nil {
+		return err
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return err
+	}
+	db := client.GetUnderlyingClient().Database(databaseName)
+
+	pipeline := append(append(bson.A{}, view.Pipeline...), view.mergeStage(databaseName))
+	cursor, err := db.Collection(view.Source).Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to refresh materialized view '%s': %w", view.Name, err)
+	}
+	if err := cursor.Close(ctx); err != nil {
+		return fmt.Errorf("failed to refresh materialized view '%s': %w", view.Name, err)
+	}
+
+	refreshedAt := time.Now().UTC().Truncate(time.Millisecond)
+	saved := *view
+	saved.RefreshedAt = &refreshedAt
+	if saved.Pipeline == nil {
+		saved.Pipeline = bson.A{}
+	}
+	_, err = db.Collection(MaterializedViewsCollection).ReplaceOne(ctx, bson.D{{Key: "_id", Value: view.Name}}, &saved, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save materialized view '%s': %w", view.Name, err)
+	}
+	view.RefreshedAt = &refreshedAt
+
+	s.logger.Info("Refreshed materialized view", "database", databaseName, "view", view.Name, "source", view.Source)
+	return nil
+}
+
+// DeleteMaterializedView removes the definition of a materialized view and, unless keepData
+// is set, drops its collection
+func (s *Service) DeleteMaterializedView(ctx context.Context, connInfo *types.ConnectionInfo, databaseName, viewName string, keepData bool) error {
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+
+	client, err := s.GetOrCreateClient(ctx, connInfo)
+	if err != nil {
+		return err
+	}
+	definitions := client.GetUnderlyingClient().Database(databaseName).Collection(MaterializedViewsCollection)
+
+	result, err := definitions.DeleteOne(ctx, bson.D{{Key: "_id", Value: viewName}})
+	if err != nil {
+		return fmt.Errorf("failed to delete materialized view '%s': %w", viewName, err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("materialized view '%s' not found in database '%s'", viewName, databaseName)
+	}
+	if !keepData {
+		if err := client.DropCollection(ctx, databaseName, viewName); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("Deleted materialized view", "database", databaseName, "view", viewName, "keepData", keepData)
+	return nil
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/teabranch/matlas-cli/internal/types"
+)
+
+func TestPipelineFromSpec(t *testing.T) {
+	pipeline, err := PipelineFromSpec([]interface{}{
+		map[string]interface{}{"$match": map[string]interface{}{
+			"createdAt": map[string]interface{}{"$gte": map[string]interface{}{"$date": "2026-01-01T00:00:00Z"}},
+		}},
+		map[string]interface{}{"$sort": []interface{}{"status:1", "createdAt:-1"}},
+		bson.D{{Key: "$limit", Value: 10}},
+	})
+	require.NoError(t, err)
+	require.Len(t, pipeline, 3)
+
+	match := pipeline[0].(bson.D)[0].Value.(bson.D)
+	since := match[0].Value.(bson.D)[0].Value
+	assert.Equal(t, primitive.NewDateTimeFromTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), since)
+
+	sortStage := pipeline[1].(bson.D)[0].Value.(bson.D)
+	assert.Equal(t, []string{"status", "createdAt"}, []string{sortStage[0].Key, sortStage[1].Key})
+	assert.EqualValues(t, -1, sortStage[1].Value)
+
+	assert.Equal(t, "$limit", pipeline[2].(bson.D)[0].Key)
+}
+
+func TestPipelineFromSpec_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		stages  []interface{}
+		message string
+	}{
+		{
+			name:    "sort map with several fields",
+			stages:  []interface{}{map[string]interface{}{"$sort": map[string]interface{}{"a": 1, "b": -1}}},
+			message: "must be a list",
+		},
+		{
+			name:    "stage with several operators",
+			stages:  []interface{}{map[string]interface{}{"$match": map[string]interface{}{}, "$limit": 1}},
+			message: "invalid pipeline stage 1",
+		},
+		{
+			name:    "stage that is not a document",
+			stages:  []interface{}{"$match"},
+			message: "invalid pipeline stage 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PipelineFromSpec(tt.stages)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestPipelineValues(t *testing.T) {
+	values, err := PipelineValues(bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "total", Value: bson.D{{Key: "$gte", Value: int32(100)}}}}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"$match": map[string]interface{}{"total": map[string]interface{}{"$gte": float64(100)}}},
+	}, values)
+
+	empty, err := PipelineValues(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{}, empty)
+}
+
+func TestValidateViewPipeline(t *testing.T) {
+	assert.NoError(t, ValidateViewPipeline(bson.A{bson.D{{Key: "$match", Value: bson.D{}}}}))
+
+	err := ValidateViewPipeline(bson.A{bson.D{{Key: "$match", Value: bson.D{}}}, bson.D{{Key: "$out", Value: "copy"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid pipeline stage 2")
+}
+
+func TestMaterializedView_MergeStage(t *testing.T) {
+	view := &MaterializedView{Name: "revenue", Source: "orders"}
+	assert.Equal(t, bson.D{{Key: "$merge", Value: bson.D{
+		{Key: "into", Value: bson.D{{Key: "db", Value: "shop"}, {Key: "coll", Value: "revenue"}}},
+		{Key: "whenMatched", Value: types.MergeWhenMatchedMerge},
+		{Key: "whenNotMatched", Value: types.MergeWhenNotMatchedInsert},
+	}}}, view.mergeStage("shop"))
+
+	view.SetMergeOptions(&types.MergeOptions{On: []string{"customerId"}, WhenMatched: types.MergeWhenMatchedReplace})
+	merge := view.mergeStage("shop")[0].Value.(bson.D)
+	assert.Equal(t, bson.E{Key: "on", Value: []string{"customerId"}}, merge[1])
+	assert.Equal(t, bson.E{Key: "whenMatched", Value: types.MergeWhenMatchedReplace}, merge[2])
+}
+
+func TestMaterializedView_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		view    MaterializedView
+		message string
+	}{
+		{name: "valid", view: MaterializedView{Name: "revenue", Source: "orders"}},
+		{name: "missing source", view: MaterializedView{Name: "revenue"}, message: "source is required"},
+		{name: "merges into source", view: MaterializedView{Name: "orders", Source: "orders"}, message: "own source"},
+		{name: "invalid merge action", view: MaterializedView{Name: "revenue", Source: "orders", WhenMatched: "upsert"}, message: "whenMatched"},
+		{
+			name:    "pipeline with $merge",
+			view:    MaterializedView{Name: "revenue", Source: "orders", Pipeline: bson.A{bson.D{{Key: "$merge", Value: "other"}}}},
+			message: "cannot contain $merge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.view.validate()
+			if tt.message == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestViewInfoFromCollection(t *testing.T) {
+	view, err := ViewInfoFromCollection(types.CollectionInfo{
+		Name: "open_orders",
+		Type: "view",
+		Options: map[string]interface{}{
+			"viewOn":   "orders",
+			"pipeline": bson.A{bson.M{"$match": bson.M{"status": "open"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, types.ViewInfo{
+		Name:     "open_orders",
+		Type:     types.ViewTypeStandard,
+		Source:   "orders",
+		Pipeline: []interface{}{map[string]interface{}{"$match": map[string]interface{}{"status": "open"}}},
+	}, view)
+}
@@ -30,7 +30,36 @@ const (
 	KindVPCEndpoint           ResourceKind = "VPCEndpoint"
 	KindAlert                 ResourceKind = "Alert"
 	KindAlertConfiguration    ResourceKind = "AlertConfiguration"
+	KindLDAPConfiguration     ResourceKind = "LDAPConfiguration"
+	KindFederationSettings    ResourceKind = "FederationSettings"
+	KindRoleMapping           ResourceKind = "RoleMapping"
+	KindIntegration           ResourceKind = "Integration"
+	KindDataSource            ResourceKind = "DataSource"
+	KindCollection            ResourceKind = "Collection"
+	KindIndex                 ResourceKind = "Index"
+	KindView                  ResourceKind = "View"
+	KindMaterializedView      ResourceKind = "MaterializedView"
 	KindApplyDocument         ResourceKind = "ApplyDocument"
+	KindModule                ResourceKind = "Module"
+	KindModuleInstance        ResourceKind = "ModuleInstance"
+	KindOverlay               ResourceKind = "Overlay"
+)
+
+// Annotations set on resources generated from a ModuleInstance
+const (
+	// AnnotationModule holds the name of the ModuleInstance that produced a resource;
+	// nested instances are joined with "/"
+	AnnotationModule = "matlas.mongodb.com/module"
+	// AnnotationModuleSource holds the module name and version, as name@version
+	AnnotationModuleSource = "matlas.mongodb.com/module-source"
+)
+
+// Annotations set on resources expanded from a forEach or count
+const (
+	// AnnotationForEach holds the metadata.name of the resource as written, such as user-${each.key}
+	AnnotationForEach = "matlas.mongodb.com/for-each"
+	// AnnotationForEachKey holds the key the resource was expanded for
+	AnnotationForEachKey = "matlas.mongodb.com/for-each-key"
 )
 
 // ResourceStatus represents the current status of a resource.
@@ -52,6 +81,9 @@ type ApplyDocument struct {
 	Kind       ResourceKind       `yaml:"kind" json:"kind"`
 	Metadata   MetadataConfig     `yaml:"metadata" json:"metadata"`
 	Resources  []ResourceManifest `yaml:"resources" json:"resources"`
+	Hooks      *Hooks             `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	// Outputs name values, usually ${ref:...} expressions, printed by 'matlas infra output'
+	Outputs map[string]string `yaml:"outputs,omitempty" json:"outputs,omitempty"`
 }
 
 // ResourceManifest represents a single resource within an ApplyDocument
@@ -61,6 +93,57 @@ type ResourceManifest struct {
 	Metadata   ResourceMetadata    `yaml:"metadata" json:"metadata"`
 	Spec       interface{}         `yaml:"spec" json:"spec"`
 	Status     *ResourceStatusInfo `yaml:"status,omitempty" json:"status,omitempty"`
+	Hooks      *Hooks              `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	// ForEach (a list of scalars or a map) or Count repeats the resource once per item;
+	// ${each.key} and ${each.value} refer to the item. Expanded when loading.
+	ForEach interface{} `yaml:"forEach,omitempty" json:"forEach,omitempty"`
+	Count   *int        `yaml:"count,omitempty" json:"count,omitempty"`
+}
+
+// Hooks are custom steps run around the operations applied to resources. Hooks declared
+// on an ApplyDocument run for every operation of the document, before the hooks declared
+// on the resource itself.
+type Hooks struct {
+	PreApply  []Hook `yaml:"preApply,omitempty" json:"preApply,omitempty"`
+	PostApply []Hook `yaml:"postApply,omitempty" json:"postApply,omitempty"`
+	OnFailure []Hook `yaml:"onFailure,omitempty" json:"onFailure,omitempty"`
+}
+
+// Hook is a shell command or HTTP webhook that receives the planned operation as JSON.
+// A failing preApply hook vetoes the operation.
+type Hook struct {
+	Name       string       `yaml:"name,omitempty" json:"name,omitempty"`
+	Command    string       `yaml:"command,omitempty" json:"command,omitempty"`
+	Webhook    *WebhookHook `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	Operations []string     `yaml:"operations,omitempty" json:"operations,omitempty"` // Create, Update, Delete; empty means all
+	Timeout    string       `yaml:"timeout,omitempty" json:"timeout,omitempty"`       // Go duration, defaults to 5m
+}
+
+// WebhookHook sends the planned operation to an HTTP endpoint
+type WebhookHook struct {
+	URL     string            `yaml:"url" json:"url" sensitive:"true"`
+	Method  string            `yaml:"method,omitempty" json:"method,omitempty"` // defaults to POST
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+// IsEmpty reports whether no hooks are declared
+func (h *Hooks) IsEmpty() bool {
+	return h == nil || len(h.PreApply)+len(h.PostApply)+len(h.OnFailure) == 0
+}
+
+// MergeHooks combines document-level and resource-level hooks, document hooks first
+func MergeHooks(document, resource *Hooks) *Hooks {
+	if document.IsEmpty() {
+		return resource
+	}
+	if resource.IsEmpty() {
+		return document
+	}
+	return &Hooks{
+		PreApply:  append(append([]Hook{}, document.PreApply...), resource.PreApply...),
+		PostApply: append(append([]Hook{}, document.PostApply...), resource.PostApply...),
+		OnFailure: append(append([]Hook{}, document.OnFailure...), resource.OnFailure...),
+	}
 }
 
 // ResourceStatusInfo contains detailed status information about a resource
@@ -306,6 +389,7 @@ type DatabaseUserSpec struct {
 	Password     string               `yaml:"password,omitempty" json:"password,omitempty"`
 	Roles        []DatabaseRoleConfig `yaml:"roles" json:"roles"`
 	AuthDatabase string               `yaml:"authDatabase,omitempty" json:"authDatabase,omitempty"`
+	AuthType     string               `yaml:"authType,omitempty" json:"authType,omitempty"` // SCRAM (default), X509_MANAGED, X509_CUSTOMER, LDAP_USER, LDAP_GROUP, AWS_IAM_USER, AWS_IAM_ROLE, OIDC_WORKFORCE, OIDC_WORKLOAD
 	Scopes       []UserScopeConfig    `yaml:"scopes,omitempty" json:"scopes,omitempty"`
 }
 
@@ -343,6 +427,372 @@ type NetworkAccessSpec struct {
 	AWSSecurityGroup string `yaml:"awsSecurityGroup,omitempty" json:"awsSecurityGroup,omitempty"`
 	Comment          string `yaml:"comment,omitempty" json:"comment,omitempty"`
 	DeleteAfterDate  string `yaml:"deleteAfterDate,omitempty" json:"deleteAfterDate,omitempty"`
+	// ExpiresAt is an alias of DeleteAfterDate (RFC3339)
+	ExpiresAt string `yaml:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	// TTL makes the entry temporary: it expires this long (e.g. "8h") after it is created
+	TTL string `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+}
+
+// LDAPConfigurationManifest represents a project LDAP configuration resource manifest
+type LDAPConfigurationManifest struct {
+	APIVersion APIVersion            `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind          `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata      `yaml:"metadata" json:"metadata"`
+	Spec       LDAPConfigurationSpec `yaml:"spec" json:"spec"`
+	Status     *ResourceStatusInfo   `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// LDAPConfigurationSpec represents the specification for a project LDAP configuration
+type LDAPConfigurationSpec struct {
+	ProjectName           string                  `yaml:"projectName" json:"projectName"`
+	Hostname              string                  `yaml:"hostname" json:"hostname"`
+	Port                  int                     `yaml:"port,omitempty" json:"port,omitempty"`
+	BindUsername          string                  `yaml:"bindUsername" json:"bindUsername"`
+	BindPassword          string                  `yaml:"bindPassword,omitempty" json:"bindPassword,omitempty"`
+	CACertificate         string                  `yaml:"caCertificate,omitempty" json:"caCertificate,omitempty"`
+	AuthenticationEnabled *bool                   `yaml:"authenticationEnabled,omitempty" json:"authenticationEnabled,omitempty"`
+	AuthorizationEnabled  *bool                   `yaml:"authorizationEnabled,omitempty" json:"authorizationEnabled,omitempty"`
+	AuthzQueryTemplate    string                  `yaml:"authzQueryTemplate,omitempty" json:"authzQueryTemplate,omitempty"`
+	UserToDNMapping       []UserToDNMappingConfig `yaml:"userToDNMapping,omitempty" json:"userToDNMapping,omitempty"`
+	DependsOn             []string                `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// UserToDNMappingConfig maps an authenticated username to an LDAP distinguished name
+type UserToDNMappingConfig struct {
+	Match        string `yaml:"match" json:"match"`
+	Substitution string `yaml:"substitution,omitempty" json:"substitution,omitempty"`
+	LDAPQuery    string `yaml:"ldapQuery,omitempty" json:"ldapQuery,omitempty"`
+}
+
+// FederationSettingsManifest represents an organization's federated authentication settings.
+// It is organization-scoped: spec.orgId selects the connected organization.
+type FederationSettingsManifest struct {
+	APIVersion APIVersion             `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind           `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata       `yaml:"metadata" json:"metadata"`
+	Spec       FederationSettingsSpec `yaml:"spec" json:"spec"`
+	Status     *ResourceStatusInfo    `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// FederationSettingsSpec represents the specification for federation settings
+type FederationSettingsSpec struct {
+	OrgID                string                   `yaml:"orgId" json:"orgId"`
+	FederationSettingsID string                   `yaml:"federationSettingsId,omitempty" json:"federationSettingsId,omitempty"` // Resolved from orgId when empty
+	IdentityProviders    []IdentityProviderConfig `yaml:"identityProviders,omitempty" json:"identityProviders,omitempty"`
+	ConnectedOrg         *ConnectedOrgSettings    `yaml:"connectedOrg,omitempty" json:"connectedOrg,omitempty"`
+	DependsOn            []string                 `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// IdentityProviderConfig represents an OIDC identity provider in the federation.
+// Identity providers are matched by displayName.
+type IdentityProviderConfig struct {
+	DisplayName       string   `yaml:"displayName" json:"displayName"`
+	ID                string   `yaml:"id,omitempty" json:"id,omitempty"` // Read-only, set by discovery
+	Description       string   `yaml:"description,omitempty" json:"description,omitempty"`
+	IdpType           string   `yaml:"idpType,omitempty" json:"idpType,omitempty"` // WORKFORCE (default) or WORKLOAD
+	IssuerURI         string   `yaml:"issuerUri" json:"issuerUri"`
+	Audience          string   `yaml:"audience" json:"audience"`
+	ClientID          string   `yaml:"clientId,omitempty" json:"clientId,omitempty"`                   // WORKFORCE only
+	AuthorizationType string   `yaml:"authorizationType,omitempty" json:"authorizationType,omitempty"` // GROUP or USER
+	GroupsClaim       string   `yaml:"groupsClaim,omitempty" json:"groupsClaim,omitempty"`
+	UserClaim         string   `yaml:"userClaim,omitempty" json:"userClaim,omitempty"`
+	RequestedScopes   []string `yaml:"requestedScopes,omitempty" json:"requestedScopes,omitempty"`
+	AssociatedDomains []string `yaml:"associatedDomains,omitempty" json:"associatedDomains,omitempty"`
+}
+
+// ConnectedOrgSettings represents how an organization is connected to the federation
+type ConnectedOrgSettings struct {
+	IdentityProviderID          string   `yaml:"identityProviderId,omitempty" json:"identityProviderId,omitempty"`                   // Identity provider used for Atlas UI sign-in
+	DataAccessIdentityProviders []string `yaml:"dataAccessIdentityProviders,omitempty" json:"dataAccessIdentityProviders,omitempty"` // Display names of OIDC providers enabled for database access
+	DomainRestrictionEnabled    bool     `yaml:"domainRestrictionEnabled,omitempty" json:"domainRestrictionEnabled,omitempty"`
+	DomainAllowList             []string `yaml:"domainAllowList,omitempty" json:"domainAllowList,omitempty"`
+	PostAuthRoleGrants          []string `yaml:"postAuthRoleGrants,omitempty" json:"postAuthRoleGrants,omitempty"`
+}
+
+// RoleMappingManifest represents a federated role mapping resource manifest.
+// It is organization-scoped: spec.orgId selects the connected organization.
+type RoleMappingManifest struct {
+	APIVersion APIVersion          `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind        `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata    `yaml:"metadata" json:"metadata"`
+	Spec       RoleMappingSpec     `yaml:"spec" json:"spec"`
+	Status     *ResourceStatusInfo `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// RoleMappingSpec maps an identity provider group to Atlas organization and project roles
+type RoleMappingSpec struct {
+	OrgID             string                 `yaml:"orgId" json:"orgId"`
+	ExternalGroupName string                 `yaml:"externalGroupName" json:"externalGroupName"`
+	RoleAssignments   []RoleAssignmentConfig `yaml:"roleAssignments" json:"roleAssignments"`
+	DependsOn         []string               `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// RoleAssignmentConfig grants an organization role (ORG_*) or a project role (GROUP_*).
+// Organization roles default to the mapping's orgId; project roles require projectId.
+type RoleAssignmentConfig struct {
+	Role      string `yaml:"role" json:"role"`
+	OrgID     string `yaml:"orgId,omitempty" json:"orgId,omitempty"`
+	ProjectID string `yaml:"projectId,omitempty" json:"projectId,omitempty"`
+}
+
+// DataSourceManifest represents a read-only lookup of an existing Atlas object. Data sources
+// are resolved when planning, never produce operations, and are referenced by other
+// resources as ${ref:DataSource/<name>.status.<field>}.
+type DataSourceManifest struct {
+	APIVersion APIVersion       `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind     `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata `yaml:"metadata" json:"metadata"`
+	Spec       DataSourceSpec   `yaml:"spec" json:"spec"`
+}
+
+// DataSourceSpec identifies the object to look up
+type DataSourceSpec struct {
+	// Type is the kind of object: Project, Cluster, DatabaseUser or NetworkAccess
+	Type ResourceKind `yaml:"type" json:"type"`
+	// Name is the project name or ID, cluster name, username, or IP address or CIDR block
+	Name string `yaml:"name" json:"name"`
+	// ProjectName is the project (name or ID) holding the object; defaults to the configuration's project
+	ProjectName string `yaml:"projectName,omitempty" json:"projectName,omitempty"`
+	// AuthDatabase is the authentication database of a DatabaseUser (default admin)
+	AuthDatabase string `yaml:"authDatabase,omitempty" json:"authDatabase,omitempty"`
+}
+
+// CollectionManifest represents a MongoDB collection on a cluster. Collections are created
+// and modified through a temporary database user.
+type CollectionManifest struct {
+	APIVersion APIVersion          `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind        `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata    `yaml:"metadata" json:"metadata"`
+	Spec       CollectionSpec      `yaml:"spec" json:"spec"`
+	Status     *ResourceStatusInfo `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// CollectionSpec represents the specification for a collection. Capped, time series,
+// clustered and collation options can only be set when the collection is created.
+type CollectionSpec struct {
+	ProjectName       string `yaml:"projectName" json:"projectName"`
+	ClusterName       string `yaml:"clusterName" json:"clusterName"`
+	DatabaseName      string `yaml:"databaseName" json:"databaseName"`
+	Name              string `yaml:"name" json:"name"`
+	CollectionOptions `yaml:",inline"`
+	DependsOn         []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// IndexManifest represents a MongoDB index on a collection
+type IndexManifest struct {
+	APIVersion APIVersion          `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind        `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata    `yaml:"metadata" json:"metadata"`
+	Spec       IndexSpec           `yaml:"spec" json:"spec"`
+	Status     *ResourceStatusInfo `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// IndexSpec represents the specification for an index. The index is identified by its
+// name, which defaults to the name MongoDB generates from the keys.
+type IndexSpec struct {
+	ProjectName    string    `yaml:"projectName" json:"projectName"`
+	ClusterName    string    `yaml:"clusterName" json:"clusterName"`
+	DatabaseName   string    `yaml:"databaseName" json:"databaseName"`
+	CollectionName string    `yaml:"collectionName" json:"collectionName"`
+	Keys           IndexKeys `yaml:"keys" json:"keys"`
+	IndexOptions   `yaml:",inline"`
+	DependsOn      []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// IndexName returns the name of the index
+func (s *IndexSpec) IndexName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Keys.DefaultName()
+}
+
+// ViewManifest represents a read-only MongoDB view on a cluster
+type ViewManifest struct {
+	APIVersion APIVersion          `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind        `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata    `yaml:"metadata" json:"metadata"`
+	Spec       ViewSpec            `yaml:"spec" json:"spec"`
+	Status     *ResourceStatusInfo `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// ViewSpec represents the specification for a view. The pipeline runs on viewOn whenever
+// the view is read. The collation can only be set when the view is created.
+type ViewSpec struct {
+	ProjectName  string          `yaml:"projectName" json:"projectName"`
+	ClusterName  string          `yaml:"clusterName" json:"clusterName"`
+	DatabaseName string          `yaml:"databaseName" json:"databaseName"`
+	Name         string          `yaml:"name" json:"name"`
+	ViewOn       string          `yaml:"viewOn" json:"viewOn"`
+	Pipeline     []interface{}   `yaml:"pipeline" json:"pipeline"`
+	Collation    *IndexCollation `yaml:"collation,omitempty" json:"collation,omitempty"`
+	DependsOn    []string        `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// MaterializedViewManifest represents an on-demand materialized view: a collection the
+// results of a pipeline are merged into when it is created, changed or refreshed
+type MaterializedViewManifest struct {
+	APIVersion APIVersion           `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind         `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata     `yaml:"metadata" json:"metadata"`
+	Spec       MaterializedViewSpec `yaml:"spec" json:"spec"`
+	Status     *ResourceStatusInfo  `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// MaterializedViewSpec represents the specification for a materialized view. The pipeline
+// runs on source and a $merge stage built from merge writes its results into the
+// collection name.
+type MaterializedViewSpec struct {
+	ProjectName  string        `yaml:"projectName" json:"projectName"`
+	ClusterName  string        `yaml:"clusterName" json:"clusterName"`
+	DatabaseName string        `yaml:"databaseName" json:"databaseName"`
+	Name         string        `yaml:"name" json:"name"`
+	Source       string        `yaml:"source" json:"source"`
+	Pipeline     []interface{} `yaml:"pipeline" json:"pipeline"`
+	Merge        *MergeOptions `yaml:"merge,omitempty" json:"merge,omitempty"`
+	DependsOn    []string      `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// IntegrationManifest represents a project third-party service integration resource manifest.
+// Atlas allows one integration per type in a project, so spec.type identifies the integration.
+type IntegrationManifest struct {
+	APIVersion APIVersion          `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind        `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata    `yaml:"metadata" json:"metadata"`
+	Spec       IntegrationSpec     `yaml:"spec" json:"spec"`
+	Status     *ResourceStatusInfo `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// IntegrationSpec represents the specification for a third-party service integration.
+// Fields tagged sensitive hold credentials; they are masked in plan and diff output
+// and Atlas only ever returns them redacted.
+type IntegrationSpec struct {
+	ProjectName string `yaml:"projectName" json:"projectName"`
+	Type        string `yaml:"type" json:"type"` // DATADOG, PAGER_DUTY, SLACK, MICROSOFT_TEAMS, PROMETHEUS, WEBHOOK, OPS_GENIE, VICTOR_OPS
+
+	// DATADOG, OPS_GENIE
+	APIKey string `yaml:"apiKey,omitempty" json:"apiKey,omitempty" sensitive:"true"`
+	Region string `yaml:"region,omitempty" json:"region,omitempty"` // DATADOG: US, EU, US3, US5, AP1, US1_FED; OPS_GENIE: US, EU
+	// DATADOG metric toggles
+	SendCollectionLatencyMetrics *bool `yaml:"sendCollectionLatencyMetrics,omitempty" json:"sendCollectionLatencyMetrics,omitempty"`
+	SendDatabaseMetrics          *bool `yaml:"sendDatabaseMetrics,omitempty" json:"sendDatabaseMetrics,omitempty"`
+
+	// PAGER_DUTY
+	ServiceKey string `yaml:"serviceKey,omitempty" json:"serviceKey,omitempty" sensitive:"true"`
+
+	// SLACK
+	APIToken    string `yaml:"apiToken,omitempty" json:"apiToken,omitempty" sensitive:"true"`
+	ChannelName string `yaml:"channelName,omitempty" json:"channelName,omitempty"`
+	TeamName    string `yaml:"teamName,omitempty" json:"teamName,omitempty"`
+
+	// VICTOR_OPS
+	RoutingKey string `yaml:"routingKey,omitempty" json:"routingKey,omitempty" sensitive:"true"`
+
+	// MICROSOFT_TEAMS
+	MicrosoftTeamsWebhookURL string `yaml:"microsoftTeamsWebhookUrl,omitempty" json:"microsoftTeamsWebhookUrl,omitempty" sensitive:"true"`
+
+	// WEBHOOK
+	URL    string `yaml:"url,omitempty" json:"url,omitempty" sensitive:"true"`
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty" sensitive:"true"`
+
+	// PROMETHEUS
+	Username         string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password         string `yaml:"password,omitempty" json:"password,omitempty" sensitive:"true"`
+	ServiceDiscovery string `yaml:"serviceDiscovery,omitempty" json:"serviceDiscovery,omitempty"` // http or file
+	Enabled          *bool  `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+}
+
+// ModuleManifest describes a reusable module: the module.yaml file at the root of a
+// module directory. The template it points to holds the resources the module produces.
+type ModuleManifest struct {
+	APIVersion APIVersion     `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind   `yaml:"kind" json:"kind"`
+	Metadata   ModuleMetadata `yaml:"metadata" json:"metadata"`
+	Spec       ModuleSpec     `yaml:"spec" json:"spec"`
+}
+
+// ModuleMetadata identifies a module
+type ModuleMetadata struct {
+	Name        string `yaml:"name" json:"name"`
+	Version     string `yaml:"version,omitempty" json:"version,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// ModuleSpec declares the inputs, outputs and template of a module
+type ModuleSpec struct {
+	Inputs []ModuleInput `yaml:"inputs,omitempty" json:"inputs,omitempty"`
+	// Outputs are values other resources can reference as ${module.<instance>.<output>};
+	// they may use ${inputs.<name>} placeholders
+	Outputs map[string]string `yaml:"outputs,omitempty" json:"outputs,omitempty"`
+	// Template is the path of the resource template relative to the module directory
+	// (default: template.yaml)
+	Template string `yaml:"template,omitempty" json:"template,omitempty"`
+}
+
+// ModuleInput is a typed module parameter
+type ModuleInput struct {
+	Name        string      `yaml:"name" json:"name"`
+	Type        string      `yaml:"type,omitempty" json:"type,omitempty"` // string (default), number, bool, list, map
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool        `yaml:"required,omitempty" json:"required,omitempty"`
+	Default     interface{} `yaml:"default,omitempty" json:"default,omitempty"`
+}
+
+// ModuleInstanceSpec instantiates a module within an ApplyDocument
+type ModuleInstanceSpec struct {
+	// Source is a local module directory (relative to the document) or a git URL:
+	// git::https://github.com/org/modules.git//standard-project?ref=v1.2.0
+	Source string `yaml:"source" json:"source"`
+	// Version pins the module version. It must match the module's metadata.version and
+	// is the git ref to check out when the source has no ref.
+	Version string                 `yaml:"version,omitempty" json:"version,omitempty"`
+	Inputs  map[string]interface{} `yaml:"inputs,omitempty" json:"inputs,omitempty"`
+}
+
+// OverlayManifest describes an environment overlay: the overlay.yaml file of an overlay
+// directory. It patches the resources of base documents so environments share one base.
+type OverlayManifest struct {
+	APIVersion APIVersion       `yaml:"apiVersion" json:"apiVersion"`
+	Kind       ResourceKind     `yaml:"kind" json:"kind"`
+	Metadata   ResourceMetadata `yaml:"metadata" json:"metadata"`
+	Spec       OverlaySpec      `yaml:"spec" json:"spec"`
+}
+
+// OverlaySpec lists the base documents, variables and patches of an overlay. Paths are
+// relative to the overlay directory.
+type OverlaySpec struct {
+	// Bases are the configuration files used when no files are given on the command line
+	Bases []string `yaml:"bases,omitempty" json:"bases,omitempty"`
+	// VarFiles and Variables provide template variables; command line variables win
+	VarFiles  []string          `yaml:"varFiles,omitempty" json:"varFiles,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Patches   []OverlayPatch    `yaml:"patches,omitempty" json:"patches,omitempty"`
+}
+
+// OverlayPatch changes one resource. Exactly one of Patch (a strategic merge patch),
+// Operations (a JSON patch) or Path (a file holding either) is set. Target defaults to
+// the kind and metadata.name of a merge patch.
+type OverlayPatch struct {
+	Target     *PatchTarget           `yaml:"target,omitempty" json:"target,omitempty"`
+	Patch      map[string]interface{} `yaml:"patch,omitempty" json:"patch,omitempty"`
+	Operations []JSONPatchOperation   `yaml:"operations,omitempty" json:"operations,omitempty"`
+	Path       string                 `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// PatchTarget selects the resource a patch applies to
+type PatchTarget struct {
+	Kind ResourceKind `yaml:"kind" json:"kind"`
+	Name string       `yaml:"name" json:"name"`
+}
+
+// JSONPatchOperation is an RFC 6902 JSON patch operation
+type JSONPatchOperation struct {
+	Op    string      `yaml:"op" json:"op"` // add, remove, replace, move, copy, test
+	Path  string      `yaml:"path" json:"path"`
+	From  string      `yaml:"from,omitempty" json:"from,omitempty"`
+	Value interface{} `yaml:"value,omitempty" json:"value,omitempty"`
 }
 
 // DependencyGraph represents the dependency relationships between resources
@@ -461,7 +911,7 @@ func ValidateAPIVersion(version APIVersion) error {
 // ValidateResourceKind validates the resource kind
 func ValidateResourceKind(kind ResourceKind) error {
 	switch kind {
-	case KindProject, KindCluster, KindDatabaseUser, KindDatabaseRole, KindNetworkAccess, KindApplyDocument, KindSearchIndex, KindSearchMetrics, KindSearchOptimization, KindSearchQueryValidation, KindVPCEndpoint:
+	case KindProject, KindCluster, KindDatabaseUser, KindDatabaseRole, KindNetworkAccess, KindApplyDocument, KindSearchIndex, KindSearchMetrics, KindSearchOptimization, KindSearchQueryValidation, KindVPCEndpoint, KindLDAPConfiguration, KindFederationSettings, KindRoleMapping, KindIntegration, KindDataSource, KindCollection, KindIndex, KindView, KindMaterializedView:
 		return nil
 	default:
 		return fmt.Errorf("unsupported resource kind: %s", kind)
@@ -141,6 +141,7 @@ type DatabaseUserConfig struct {
 	Password     string               `yaml:"password,omitempty" json:"password,omitempty" validate:"omitempty,min=8,max=256"`
 	Roles        []DatabaseRoleConfig `yaml:"roles" json:"roles" validate:"required,min=1,dive"`
 	AuthDatabase string               `yaml:"authDatabase,omitempty" json:"authDatabase,omitempty" validate:"omitempty,min=1,max=63"`
+	AuthType     string               `yaml:"authType,omitempty" json:"authType,omitempty" validate:"omitempty,oneof=SCRAM X509_MANAGED X509_CUSTOMER LDAP_USER LDAP_GROUP AWS_IAM_USER AWS_IAM_ROLE OIDC_WORKFORCE OIDC_WORKLOAD"`
 	Scopes       []UserScopeConfig    `yaml:"scopes,omitempty" json:"scopes,omitempty" validate:"dive"`
 	DependsOn    []string             `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty" validate:"dive,min=1,max=64"`
 }
@@ -194,6 +195,8 @@ type NetworkAccessConfig struct {
 	AWSSecurityGroup string           `yaml:"awsSecurityGroup,omitempty" json:"awsSecurityGroup,omitempty" validate:"omitempty,min=11,max=20"`
 	Comment          string           `yaml:"comment,omitempty" json:"comment,omitempty" validate:"omitempty,max=80"`
 	DeleteAfterDate  string           `yaml:"deleteAfterDate,omitempty" json:"deleteAfterDate,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	ExpiresAt        string           `yaml:"expiresAt,omitempty" json:"expiresAt,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	TTL              string           `yaml:"ttl,omitempty" json:"ttl,omitempty"`
 	DependsOn        []string         `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty" validate:"dive,min=1,max=64"`
 }
 
@@ -1,8 +1,15 @@
 package types
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // DatabaseInfo represents information about a MongoDB database
@@ -27,17 +34,666 @@ type CollectionStats struct {
 	Size       int64            `json:"size"`
 	AvgObjSize int64            `json:"avgObjSize"`
 	IndexSizes map[string]int64 `json:"indexSizes,omitempty"`
+	// TimeSeries is only set for time series collections
+	TimeSeries *TimeSeriesStats `json:"timeseries,omitempty"`
+}
+
+// TimeSeriesStats describes the buckets that hold the measurements of a time series
+// collection
+type TimeSeriesStats struct {
+	BucketsNamespace      string `json:"bucketsNs,omitempty"`
+	BucketCount           int64  `json:"bucketCount"`
+	AvgBucketSize         int64  `json:"avgBucketSize"`
+	MeasurementsCommitted int64  `json:"numMeasurementsCommitted"`
 }
 
 // IndexInfo represents information about a MongoDB index
 type IndexInfo struct {
-	Name       string                 `json:"name"`
-	Keys       map[string]interface{} `json:"key"`
-	Unique     bool                   `json:"unique,omitempty"`
-	Sparse     bool                   `json:"sparse,omitempty"`
-	Background bool                   `json:"background,omitempty"`
-	Version    int                    `json:"v,omitempty"`
-	Options    map[string]interface{} `json:"options,omitempty"`
+	Name                    string                 `json:"name"`
+	Keys                    IndexKeys              `json:"key"`
+	Unique                  bool                   `json:"unique,omitempty"`
+	Sparse                  bool                   `json:"sparse,omitempty"`
+	Background              bool                   `json:"background,omitempty"`
+	Hidden                  bool                   `json:"hidden,omitempty"`
+	PartialFilterExpression map[string]interface{} `json:"partialFilterExpression,omitempty"`
+	ExpireAfterSeconds      *int32                 `json:"expireAfterSeconds,omitempty"`
+	Collation               *IndexCollation        `json:"collation,omitempty"`
+	WildcardProjection      map[string]interface{} `json:"wildcardProjection,omitempty"`
+	Version                 int                    `json:"v,omitempty"`
+	Options                 map[string]interface{} `json:"options,omitempty"`
+}
+
+// Index key types other than ascending and descending
+const (
+	IndexTypeText      = "text"
+	IndexType2DSphere  = "2dsphere"
+	IndexType2D        = "2d"
+	IndexTypeHashed    = "hashed"
+	IndexWildcardField = "$**"
+)
+
+// IndexKey is one field of an index key pattern: either an Order of 1 (ascending) or -1
+// (descending), or a Type such as text, 2dsphere, 2d or hashed. A Field of $** or ending
+// in .$** is a wildcard key.
+type IndexKey struct {
+	Field string `json:"field" yaml:"field"`
+	Order int    `json:"order,omitempty" yaml:"order,omitempty"`
+	Type  string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// Value returns the key's value in a MongoDB key pattern
+func (k IndexKey) Value() interface{} {
+	if k.Type != "" {
+		return k.Type
+	}
+	return k.Order
+}
+
+// IsWildcard reports whether the key indexes all fields under a path
+func (k IndexKey) IsWildcard() bool {
+	return k.Field == IndexWildcardField || strings.HasSuffix(k.Field, "."+IndexWildcardField)
+}
+
+// String formats the key as field:value, the form accepted by ParseIndexKey
+func (k IndexKey) String() string {
+	return fmt.Sprintf("%s:%v", k.Field, k.Value())
+}
+
+// IndexKeys is an ordered index key pattern. It is encoded as a JSON object with the
+// fields in order, like the key document MongoDB returns.
+type IndexKeys []IndexKey
+
+// MarshalJSON implements json.Marshaler
+func (keys IndexKeys) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		field, err := json.Marshal(key.Field)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(key.Value())
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(field)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Keys are read from an object in field order
+// or from a list of IndexKey objects or field:value strings.
+func (keys *IndexKeys) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		result := make(IndexKeys, len(items))
+		for i, item := range items {
+			var spec string
+			if err := json.Unmarshal(item, &spec); err == nil {
+				key, err := ParseIndexKey(spec)
+				if err != nil {
+					return err
+				}
+				result[i] = key
+				continue
+			}
+			if err := json.Unmarshal(item, &result[i]); err != nil {
+				return err
+			}
+		}
+		*keys = result
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	var result IndexKeys
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		if number, ok := value.(json.Number); ok {
+			order, err := number.Int64()
+			if err != nil {
+				return fmt.Errorf("invalid order %s for field '%v'", number, token)
+			}
+			value = order
+		}
+		result = append(result, IndexKeyFromValue(fmt.Sprint(token), value))
+	}
+	*keys = result
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler, encoding the keys as a mapping in field order
+func (keys IndexKeys) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range keys {
+		var value yaml.Node
+		if err := value.Encode(key.Value()); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.Field}, &value)
+	}
+	return node, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. Keys are read from a mapping in field order
+// or from a list of IndexKey objects or field:value strings.
+func (keys *IndexKeys) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		result := make(IndexKeys, len(node.Content))
+		for i, item := range node.Content {
+			if item.Kind == yaml.ScalarNode {
+				key, err := ParseIndexKey(item.Value)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", item.Line, err)
+				}
+				result[i] = key
+				continue
+			}
+			if err := item.Decode(&result[i]); err != nil {
+				return err
+			}
+		}
+		*keys = result
+	case yaml.MappingNode:
+		result := make(IndexKeys, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var value interface{}
+			if err := node.Content[i+1].Decode(&value); err != nil {
+				return err
+			}
+			result = append(result, IndexKeyFromValue(node.Content[i].Value, value))
+		}
+		*keys = result
+	default:
+		return fmt.Errorf("line %d: index keys must be a mapping or a list", node.Line)
+	}
+	return nil
+}
+
+// String formats the keys as space-separated field:value pairs
+func (keys IndexKeys) String() string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// DefaultName returns the name MongoDB gives an index created without one, such as
+// email_1_createdAt_-1
+func (keys IndexKeys) DefaultName() string {
+	parts := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		parts = append(parts, key.Field, fmt.Sprint(key.Value()))
+	}
+	return strings.Join(parts, "_")
+}
+
+// IndexKeyFromValue builds a key from a field and its value in a MongoDB key pattern
+func IndexKeyFromValue(field string, value interface{}) IndexKey {
+	switch v := value.(type) {
+	case string:
+		return IndexKey{Field: field, Type: v}
+	case int:
+		return IndexKey{Field: field, Order: v}
+	case int32:
+		return IndexKey{Field: field, Order: int(v)}
+	case int64:
+		return IndexKey{Field: field, Order: int(v)}
+	case float64:
+		if v == float64(int(v)) {
+			return IndexKey{Field: field, Order: int(v)}
+		}
+		return IndexKey{Field: field, Type: fmt.Sprint(v)}
+	default:
+		return IndexKey{Field: field, Type: fmt.Sprint(v)}
+	}
+}
+
+// ParseIndexKey parses field:value, where value is 1, -1 or an index type. The field is
+// everything before the last colon.
+func ParseIndexKey(spec string) (IndexKey, error) {
+	i := strings.LastIndex(spec, ":")
+	if i <= 0 || i == len(spec)-1 {
+		return IndexKey{}, fmt.Errorf("invalid key specification '%s': expected format 'field:order' or 'field:type'", spec)
+	}
+	field, value := spec[:i], spec[i+1:]
+	if order, err := strconv.Atoi(value); err == nil {
+		return IndexKey{Field: field, Order: order}, nil
+	}
+	return IndexKey{Field: field, Type: value}, nil
+}
+
+// IndexCollation is the collation of an index
+type IndexCollation struct {
+	Locale          string `json:"locale" yaml:"locale"`
+	CaseLevel       bool   `json:"caseLevel,omitempty" yaml:"caseLevel,omitempty"`
+	CaseFirst       string `json:"caseFirst,omitempty" yaml:"caseFirst,omitempty"`
+	Strength        int    `json:"strength,omitempty" yaml:"strength,omitempty"`
+	NumericOrdering bool   `json:"numericOrdering,omitempty" yaml:"numericOrdering,omitempty"`
+	Alternate       string `json:"alternate,omitempty" yaml:"alternate,omitempty"`
+	MaxVariable     string `json:"maxVariable,omitempty" yaml:"maxVariable,omitempty"`
+	Backwards       bool   `json:"backwards,omitempty" yaml:"backwards,omitempty"`
+}
+
+// IndexOptions are the options an index is created with
+type IndexOptions struct {
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"`
+	Unique     bool   `json:"unique,omitempty" yaml:"unique,omitempty"`
+	Sparse     bool   `json:"sparse,omitempty" yaml:"sparse,omitempty"`
+	Background bool   `json:"background,omitempty" yaml:"background,omitempty"`
+	Hidden     bool   `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	// PartialFilterExpression limits the index to documents matching the filter
+	PartialFilterExpression map[string]interface{} `json:"partialFilterExpression,omitempty" yaml:"partialFilterExpression,omitempty"`
+	// ExpireAfterSeconds makes a TTL index on a single date field
+	ExpireAfterSeconds *int32          `json:"expireAfterSeconds,omitempty" yaml:"expireAfterSeconds,omitempty"`
+	Collation          *IndexCollation `json:"collation,omitempty" yaml:"collation,omitempty"`
+	// WildcardProjection includes or excludes fields from a $** wildcard index
+	WildcardProjection map[string]interface{} `json:"wildcardProjection,omitempty" yaml:"wildcardProjection,omitempty"`
+	// CommitQuorum is a build option: the number of data-bearing voting members, "majority",
+	// "votingMembers" or a replica set tag that must finish the build before it commits
+	CommitQuorum string `json:"commitQuorum,omitempty" yaml:"commitQuorum,omitempty"`
+}
+
+// ValidateIndex checks that an index key pattern and its options are consistent
+func ValidateIndex(keys []IndexKey, opts *IndexOptions) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("at least one index key is required")
+	}
+	if opts == nil {
+		opts = &IndexOptions{}
+	}
+
+	fields := make(map[string]bool, len(keys))
+	var hashed, wildcard, text int
+	for _, key := range keys {
+		if key.Field == "" {
+			return fmt.Errorf("index key field is required")
+		}
+		if fields[key.Field] {
+			return fmt.Errorf("field '%s' appears more than once in the index key", key.Field)
+		}
+		fields[key.Field] = true
+
+		switch key.Type {
+		case "":
+			if key.Order != 1 && key.Order != -1 {
+				return fmt.Errorf("invalid order %d for field '%s': must be 1 (ascending) or -1 (descending)", key.Order, key.Field)
+			}
+		case IndexTypeText:
+			text++
+		case IndexTypeHashed:
+			hashed++
+		case IndexType2DSphere, IndexType2D:
+		default:
+			return fmt.Errorf("invalid index type '%s' for field '%s': must be 1, -1, %s, %s, %s or %s",
+				key.Type, key.Field, IndexTypeText, IndexType2DSphere, IndexType2D, IndexTypeHashed)
+		}
+		if key.Type != "" && key.Order != 0 {
+			return fmt.Errorf("field '%s' cannot have both an order and a type", key.Field)
+		}
+		if key.IsWildcard() {
+			if key.Type != "" {
+				return fmt.Errorf("wildcard key '%s' must be ascending or descending", key.Field)
+			}
+			wildcard++
+		}
+	}
+
+	switch {
+	case hashed > 1:
+		return fmt.Errorf("an index can contain only one hashed field")
+	case wildcard > 1:
+		return fmt.Errorf("an index can contain only one wildcard key")
+	case opts.WildcardProjection != nil && !fields[IndexWildcardField]:
+		return fmt.Errorf("wildcardProjection requires a %s key", IndexWildcardField)
+	case opts.ExpireAfterSeconds != nil && (len(keys) != 1 || keys[0].Type != "" || wildcard > 0):
+		return fmt.Errorf("expireAfterSeconds (TTL) requires a single ascending or descending field")
+	case opts.ExpireAfterSeconds != nil && *opts.ExpireAfterSeconds < 0:
+		return fmt.Errorf("expireAfterSeconds must not be negative")
+	case opts.Unique && (hashed > 0 || wildcard > 0 || text > 0):
+		return fmt.Errorf("unique indexes cannot contain text, hashed or wildcard keys")
+	case opts.Sparse && opts.PartialFilterExpression != nil:
+		return fmt.Errorf("sparse and partialFilterExpression cannot be combined")
+	case opts.Collation != nil && opts.Collation.Locale == "":
+		return fmt.Errorf("collation locale is required")
+	case strings.HasPrefix(opts.CommitQuorum, "-"):
+		return fmt.Errorf("commitQuorum must not be negative")
+	}
+	return nil
+}
+
+// IndexBuild is an index build in progress, as reported by $currentOp
+type IndexBuild struct {
+	Host        string   `json:"host,omitempty"`
+	Namespace   string   `json:"namespace"`
+	Indexes     []string `json:"indexes"`
+	OpID        string   `json:"opid,omitempty"`
+	Phase       string   `json:"phase,omitempty"`
+	Done        int64    `json:"done"`
+	Total       int64    `json:"total"`
+	SecsRunning int64    `json:"secsRunning"`
+}
+
+// Percent returns how far the current phase of the build has progressed, from 0 to 100
+func (b IndexBuild) Percent() float64 {
+	if b.Total <= 0 {
+		return 0
+	}
+	percent := float64(b.Done) / float64(b.Total) * 100
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// Builds reports whether the build creates the named index
+func (b IndexBuild) Builds(indexName string) bool {
+	for _, name := range b.Indexes {
+		if name == indexName {
+			return true
+		}
+	}
+	return false
+}
+
+// Collection validation levels and actions
+const (
+	ValidationLevelOff      = "off"
+	ValidationLevelStrict   = "strict"
+	ValidationLevelModerate = "moderate"
+	ValidationActionError   = "error"
+	ValidationActionWarn    = "warn"
+)
+
+// CollectionOptions are the options a collection is created with. Field names follow the
+// create command and listCollections output.
+type CollectionOptions struct {
+	Capped bool  `json:"capped,omitempty" yaml:"capped,omitempty"`
+	Size   int64 `json:"size,omitempty" yaml:"size,omitempty"`
+	Max    int64 `json:"max,omitempty" yaml:"max,omitempty"`
+	// Validator is a query filter, usually {$jsonSchema: ...}, documents must match
+	Validator        map[string]interface{} `json:"validator,omitempty" yaml:"validator,omitempty"`
+	ValidationLevel  string                 `json:"validationLevel,omitempty" yaml:"validationLevel,omitempty"`
+	ValidationAction string                 `json:"validationAction,omitempty" yaml:"validationAction,omitempty"`
+	TimeSeries       *TimeSeriesOptions     `json:"timeseries,omitempty" yaml:"timeseries,omitempty"`
+	// ClusteredIndex makes a clustered collection ordered by _id
+	ClusteredIndex *ClusteredIndexOptions `json:"clusteredIndex,omitempty" yaml:"clusteredIndex,omitempty"`
+	// ExpireAfterSeconds removes documents of time series and clustered collections
+	ExpireAfterSeconds *int64          `json:"expireAfterSeconds,omitempty" yaml:"expireAfterSeconds,omitempty"`
+	Collation          *IndexCollation `json:"collation,omitempty" yaml:"collation,omitempty"`
+}
+
+// TimeSeriesOptions are the options of a time series collection. Buckets are sized by
+// granularity or by bucketMaxSpanSeconds and bucketRoundingSeconds, which must be equal.
+type TimeSeriesOptions struct {
+	TimeField             string `json:"timeField" yaml:"timeField"`
+	MetaField             string `json:"metaField,omitempty" yaml:"metaField,omitempty"`
+	Granularity           string `json:"granularity,omitempty" yaml:"granularity,omitempty"` // seconds, minutes, hours
+	BucketMaxSpanSeconds  *int64 `json:"bucketMaxSpanSeconds,omitempty" yaml:"bucketMaxSpanSeconds,omitempty"`
+	BucketRoundingSeconds *int64 `json:"bucketRoundingSeconds,omitempty" yaml:"bucketRoundingSeconds,omitempty"`
+}
+
+// ClusteredIndexOptions are the options of the clustered index, which is always a unique
+// index on _id
+type ClusteredIndexOptions struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// ParseCollectionOptions reads the options document of a listCollections entry
+func ParseCollectionOptions(raw map[string]interface{}) (*CollectionOptions, error) {
+	opts := &CollectionOptions{}
+	if len(raw) == 0 {
+		return opts, nil
+	}
+	// Time series buckets report clusteredIndex as a flag rather than an index document
+	if _, ok := raw["clusteredIndex"].(bool); ok {
+		trimmed := make(map[string]interface{}, len(raw))
+		for k, v := range raw {
+			if k != "clusteredIndex" {
+				trimmed[k] = v
+			}
+		}
+		raw = trimmed
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode collection options: %w", err)
+	}
+	if err := json.Unmarshal(data, opts); err != nil {
+		return nil, fmt.Errorf("failed to decode collection options: %w", err)
+	}
+	return opts, nil
+}
+
+// ValidateCollectionOptions checks options for combinations MongoDB rejects
+func ValidateCollectionOptions(opts *CollectionOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if ts := opts.TimeSeries; ts != nil {
+		switch {
+		case ts.TimeField == "":
+			return fmt.Errorf("timeseries.timeField is required")
+		case ts.Granularity != "" && ts.Granularity != "seconds" && ts.Granularity != "minutes" && ts.Granularity != "hours":
+			return fmt.Errorf("invalid timeseries granularity '%s': must be seconds, minutes or hours", ts.Granularity)
+		case ts.Granularity != "" && (ts.BucketMaxSpanSeconds != nil || ts.BucketRoundingSeconds != nil):
+			return fmt.Errorf("timeseries granularity cannot be combined with bucketMaxSpanSeconds or bucketRoundingSeconds")
+		case (ts.BucketMaxSpanSeconds == nil) != (ts.BucketRoundingSeconds == nil),
+			ts.BucketMaxSpanSeconds != nil && *ts.BucketMaxSpanSeconds != *ts.BucketRoundingSeconds:
+			return fmt.Errorf("timeseries bucketMaxSpanSeconds and bucketRoundingSeconds must be set together to the same value")
+		}
+	}
+	switch {
+	case opts.Capped && opts.Size <= 0:
+		return fmt.Errorf("capped collections require a size")
+	case !opts.Capped && (opts.Size != 0 || opts.Max != 0):
+		return fmt.Errorf("size and max only apply to capped collections")
+	case opts.Capped && (opts.TimeSeries != nil || opts.ClusteredIndex != nil):
+		return fmt.Errorf("capped collections cannot be time series or clustered")
+	case opts.TimeSeries != nil && opts.ClusteredIndex != nil:
+		return fmt.Errorf("time series collections cannot be clustered")
+	case opts.ExpireAfterSeconds != nil && opts.TimeSeries == nil && opts.ClusteredIndex == nil:
+		return fmt.Errorf("expireAfterSeconds requires a time series or clustered collection")
+	case opts.ExpireAfterSeconds != nil && *opts.ExpireAfterSeconds < 0:
+		return fmt.Errorf("expireAfterSeconds must not be negative")
+	case opts.ValidationLevel != "" && opts.ValidationLevel != ValidationLevelOff && opts.ValidationLevel != ValidationLevelStrict && opts.ValidationLevel != ValidationLevelModerate:
+		return fmt.Errorf("invalid validationLevel '%s': must be off, strict or moderate", opts.ValidationLevel)
+	case opts.ValidationAction != "" && opts.ValidationAction != ValidationActionError && opts.ValidationAction != ValidationActionWarn:
+		return fmt.Errorf("invalid validationAction '%s': must be error or warn", opts.ValidationAction)
+	case opts.Collation != nil && opts.Collation.Locale == "":
+		return fmt.Errorf("collation locale is required")
+	}
+	return nil
+}
+
+// CollectionValidator is the schema validation configuration of a collection
+type CollectionValidator struct {
+	// Validator is the query filter documents must match, usually {$jsonSchema: ...}
+	Validator        map[string]interface{} `json:"validator,omitempty" yaml:"validator,omitempty"`
+	ValidationLevel  string                 `json:"validationLevel" yaml:"validationLevel"`
+	ValidationAction string                 `json:"validationAction" yaml:"validationAction"`
+}
+
+// ValidatorTestResult reports how many sampled documents a validator would reject
+type ValidatorTestResult struct {
+	Sampled  int64 `json:"sampled" yaml:"sampled"`
+	Rejected int64 `json:"rejected" yaml:"rejected"`
+	// RejectedDocuments holds up to the requested number of rejected documents
+	RejectedDocuments []map[string]interface{} `json:"rejectedDocuments,omitempty" yaml:"rejectedDocuments,omitempty"`
+}
+
+// DatabaseSnapshot is the schema of the databases of a deployment: their collections,
+// views, validators and indexes, without data or statistics
+type DatabaseSnapshot struct {
+	Databases []DatabaseSchema `json:"databases" yaml:"databases"`
+}
+
+// DatabaseSchema is the schema of one database, with collections ordered by name
+type DatabaseSchema struct {
+	Name        string             `json:"name" yaml:"name"`
+	Collections []CollectionSchema `json:"collections" yaml:"collections"`
+}
+
+// CollectionSchema is a collection or view with its options and indexes
+type CollectionSchema struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is collection, timeseries or view
+	Type    string            `json:"type" yaml:"type"`
+	Options CollectionOptions `json:"options" yaml:"options"`
+	// ViewOn and Pipeline define a view
+	ViewOn   string        `json:"viewOn,omitempty" yaml:"viewOn,omitempty"`
+	Pipeline []interface{} `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
+	// Indexes are ordered by name; views have none
+	Indexes []IndexInfo `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+}
+
+// View types
+const (
+	// ViewTypeStandard is a read-only view, which runs its pipeline whenever it is read
+	ViewTypeStandard = "view"
+	// ViewTypeMaterialized is an on-demand materialized view: a collection the results of
+	// its pipeline are merged into each time it is refreshed
+	ViewTypeMaterialized = "materialized"
+)
+
+// ViewInfo describes a view or an on-demand materialized view of a database
+type ViewInfo struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is view or materialized
+	Type string `json:"type" yaml:"type"`
+	// Source is the collection or view the pipeline reads
+	Source    string          `json:"source" yaml:"source"`
+	Pipeline  []interface{}   `json:"pipeline" yaml:"pipeline"`
+	Collation *IndexCollation `json:"collation,omitempty" yaml:"collation,omitempty"`
+	// Merge and RefreshedAt are only set for materialized views
+	Merge       *MergeOptions `json:"merge,omitempty" yaml:"merge,omitempty"`
+	RefreshedAt *time.Time    `json:"refreshedAt,omitempty" yaml:"refreshedAt,omitempty"`
+}
+
+// IsView reports whether a listCollections entry is a view rather than a collection
+func (c CollectionInfo) IsView() bool {
+	return c.Type == ViewTypeStandard
+}
+
+// $merge actions of on-demand materialized views
+const (
+	MergeWhenMatchedReplace      = "replace"
+	MergeWhenMatchedKeepExisting = "keepExisting"
+	MergeWhenMatchedMerge        = "merge"
+	MergeWhenMatchedFail         = "fail"
+	MergeWhenNotMatchedInsert    = "insert"
+	MergeWhenNotMatchedDiscard   = "discard"
+	MergeWhenNotMatchedFail      = "fail"
+)
+
+// MergeOptions control how the $merge stage of a materialized view writes its results
+type MergeOptions struct {
+	// On lists the fields that identify a result in the materialized view (default _id).
+	// Other fields require a unique index on them in the materialized view.
+	On []string `json:"on,omitempty" yaml:"on,omitempty"`
+	// WhenMatched is replace, keepExisting, merge (default) or fail
+	WhenMatched string `json:"whenMatched,omitempty" yaml:"whenMatched,omitempty"`
+	// WhenNotMatched is insert (default), discard or fail
+	WhenNotMatched string `json:"whenNotMatched,omitempty" yaml:"whenNotMatched,omitempty"`
+}
+
+// WithDefaults returns the options with the actions MongoDB uses when they are omitted
+func (m *MergeOptions) WithDefaults() MergeOptions {
+	merged := MergeOptions{}
+	if m != nil {
+		merged = *m
+	}
+	if merged.WhenMatched == "" {
+		merged.WhenMatched = MergeWhenMatchedMerge
+	}
+	if merged.WhenNotMatched == "" {
+		merged.WhenNotMatched = MergeWhenNotMatchedInsert
+	}
+	return merged
+}
+
+// ValidateMergeOptions checks the $merge actions of a materialized view
+func ValidateMergeOptions(opts *MergeOptions) error {
+	if opts == nil {
+		return nil
+	}
+	switch opts.WhenMatched {
+	case "", MergeWhenMatchedReplace, MergeWhenMatchedKeepExisting, MergeWhenMatchedMerge, MergeWhenMatchedFail:
+	default:
+		return fmt.Errorf("invalid whenMatched '%s': must be replace, keepExisting, merge or fail", opts.WhenMatched)
+	}
+	switch opts.WhenNotMatched {
+	case "", MergeWhenNotMatchedInsert, MergeWhenNotMatchedDiscard, MergeWhenNotMatchedFail:
+	default:
+		return fmt.Errorf("invalid whenNotMatched '%s': must be insert, discard or fail", opts.WhenNotMatched)
+	}
+	for _, field := range opts.On {
+		if strings.TrimSpace(field) == "" {
+			return fmt.Errorf("merge.on fields must not be empty")
+		}
+	}
+	return nil
+}
+
+// Explain verbosities supported for aggregations
+const (
+	ExplainQueryPlanner   = "queryPlanner"
+	ExplainExecutionStats = "executionStats"
+)
+
+// ExplainSummary condenses the explain output of an aggregation: the plan that reads
+// documents from the collection and the pipeline stages run on them. Counts are only
+// reported with executionStats verbosity.
+type ExplainSummary struct {
+	// Shard is set on the per-shard summaries of a sharded collection
+	Shard     string `json:"shard,omitempty" yaml:"shard,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Verbosity string `json:"verbosity" yaml:"verbosity"`
+	// Plan is the winning query plan, or nil when the pipeline doesn't read a collection
+	Plan *ExplainStage `json:"plan,omitempty" yaml:"plan,omitempty"`
+	// Stages are the pipeline stages run after the query, in order
+	Stages         []ExplainStage   `json:"stages,omitempty" yaml:"stages,omitempty"`
+	IndexesUsed    []string         `json:"indexesUsed,omitempty" yaml:"indexesUsed,omitempty"`
+	CollectionScan bool             `json:"collectionScan" yaml:"collectionScan"`
+	Returned       *int64           `json:"returned,omitempty" yaml:"returned,omitempty"`
+	DocsExamined   *int64           `json:"docsExamined,omitempty" yaml:"docsExamined,omitempty"`
+	KeysExamined   *int64           `json:"keysExamined,omitempty" yaml:"keysExamined,omitempty"`
+	ExecutionMS    *int64           `json:"executionTimeMillis,omitempty" yaml:"executionTimeMillis,omitempty"`
+	Shards         []ExplainSummary `json:"shards,omitempty" yaml:"shards,omitempty"`
+	Warnings       []string         `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// ExplainStage is a stage of a query plan, such as IXSCAN or FETCH, or a pipeline stage
+// such as $group
+type ExplainStage struct {
+	Stage        string         `json:"stage" yaml:"stage"`
+	IndexName    string         `json:"indexName,omitempty" yaml:"indexName,omitempty"`
+	Returned     *int64         `json:"returned,omitempty" yaml:"returned,omitempty"`
+	DocsExamined *int64         `json:"docsExamined,omitempty" yaml:"docsExamined,omitempty"`
+	KeysExamined *int64         `json:"keysExamined,omitempty" yaml:"keysExamined,omitempty"`
+	Inputs       []ExplainStage `json:"inputs,omitempty" yaml:"inputs,omitempty"`
 }
 
 // ConnectionInfo represents connection details for a MongoDB instance
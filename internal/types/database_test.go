@@ -0,0 +1,162 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseIndexKey(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    IndexKey
+		wantErr bool
+	}{
+		{spec: "email:1", want: IndexKey{Field: "email", Order: 1}},
+		{spec: "createdAt:-1", want: IndexKey{Field: "createdAt", Order: -1}},
+		{spec: "location:2dsphere", want: IndexKey{Field: "location", Type: IndexType2DSphere}},
+		{spec: "$**:1", want: IndexKey{Field: "$**", Order: 1}},
+		{spec: "a:b:hashed", want: IndexKey{Field: "a:b", Type: IndexTypeHashed}},
+		{spec: "email", wantErr: true},
+		{spec: ":1", wantErr: true},
+		{spec: "email:", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			key, err := ParseIndexKey(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, key)
+			assert.Equal(t, tt.spec, key.String())
+		})
+	}
+}
+
+func TestValidateIndex(t *testing.T) {
+	ttl := int32(3600)
+	negative := int32(-1)
+	asc := func(field string) IndexKey { return IndexKey{Field: field, Order: 1} }
+	typed := func(field, indexType string) IndexKey { return IndexKey{Field: field, Type: indexType} }
+
+	tests := []struct {
+		name    string
+		keys    []IndexKey
+		opts    *IndexOptions
+		wantErr string
+	}{
+		{name: "compound", keys: []IndexKey{asc("a"), {Field: "b", Order: -1}}},
+		{name: "text", keys: []IndexKey{typed("title", IndexTypeText), typed("body", IndexTypeText)}},
+		{name: "compound geo", keys: []IndexKey{typed("loc", IndexType2DSphere), asc("category")}},
+		{name: "ttl", keys: []IndexKey{asc("createdAt")}, opts: &IndexOptions{ExpireAfterSeconds: &ttl}},
+		{name: "partial", keys: []IndexKey{asc("email")}, opts: &IndexOptions{Unique: true, PartialFilterExpression: map[string]interface{}{"active": true}}},
+		{name: "wildcard projection", keys: []IndexKey{asc("$**")}, opts: &IndexOptions{WildcardProjection: map[string]interface{}{"secret": 0}}},
+		{name: "collation", keys: []IndexKey{asc("name")}, opts: &IndexOptions{Collation: &IndexCollation{Locale: "en", Strength: 2}}},
+		{name: "no keys", wantErr: "at least one index key"},
+		{name: "bad order", keys: []IndexKey{{Field: "a", Order: 2}}, wantErr: "must be 1 (ascending) or -1"},
+		{name: "bad type", keys: []IndexKey{typed("a", "fulltext")}, wantErr: "invalid index type"},
+		{name: "order and type", keys: []IndexKey{{Field: "a", Order: 1, Type: IndexTypeHashed}}, wantErr: "both an order and a type"},
+		{name: "duplicate field", keys: []IndexKey{asc("a"), asc("a")}, wantErr: "more than once"},
+		{name: "two hashed", keys: []IndexKey{typed("a", IndexTypeHashed), typed("b", IndexTypeHashed)}, wantErr: "only one hashed"},
+		{name: "typed wildcard", keys: []IndexKey{typed("attrs.$**", IndexTypeText)}, wantErr: "must be ascending or descending"},
+		{name: "projection without wildcard", keys: []IndexKey{asc("a")}, opts: &IndexOptions{WildcardProjection: map[string]interface{}{"a": 1}}, wantErr: "requires a $** key"},
+		{name: "compound ttl", keys: []IndexKey{asc("a"), asc("b")}, opts: &IndexOptions{ExpireAfterSeconds: &ttl}, wantErr: "single ascending or descending field"},
+		{name: "negative ttl", keys: []IndexKey{asc("a")}, opts: &IndexOptions{ExpireAfterSeconds: &negative}, wantErr: "must not be negative"},
+		{name: "unique hashed", keys: []IndexKey{typed("a", IndexTypeHashed)}, opts: &IndexOptions{Unique: true}, wantErr: "unique indexes cannot"},
+		{name: "sparse partial", keys: []IndexKey{asc("a")}, opts: &IndexOptions{Sparse: true, PartialFilterExpression: map[string]interface{}{"a": 1}}, wantErr: "cannot be combined"},
+		{name: "collation without locale", keys: []IndexKey{asc("a")}, opts: &IndexOptions{Collation: &IndexCollation{Strength: 2}}, wantErr: "locale is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateIndex(tt.keys, tt.opts)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestIndexKeys_KeepOrder(t *testing.T) {
+	keys := IndexKeys{{Field: "z", Order: 1}, {Field: "a", Order: -1}, {Field: "loc", Type: IndexType2DSphere}}
+
+	data, err := json.Marshal(IndexInfo{Name: "z_1_a_-1_loc_2dsphere", Keys: keys})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"key":{"z":1,"a":-1,"loc":"2dsphere"}`)
+
+	var decoded IndexInfo
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, keys, decoded.Keys)
+
+	out, err := yaml.Marshal(keys)
+	require.NoError(t, err)
+	assert.Equal(t, "z: 1\na: -1\nloc: 2dsphere\n", string(out))
+
+	var fromMapping, fromList IndexKeys
+	require.NoError(t, yaml.Unmarshal(out, &fromMapping))
+	assert.Equal(t, keys, fromMapping)
+	require.NoError(t, yaml.Unmarshal([]byte("- {field: z, order: 1}\n- {field: a, order: -1}\n- {field: loc, type: 2dsphere}\n"), &fromList))
+	assert.Equal(t, keys, fromList)
+
+	assert.Equal(t, "z:1 a:-1 loc:2dsphere", keys.String())
+	assert.True(t, IndexKey{Field: "attrs.$**", Order: 1}.IsWildcard())
+}
+
+func TestIndexKeys_StringListAndDefaultName(t *testing.T) {
+	var fromYAML, fromJSON IndexKeys
+	require.NoError(t, yaml.Unmarshal([]byte(`["email:1", "createdAt:-1", "loc:2dsphere"]`), &fromYAML))
+	require.NoError(t, json.Unmarshal([]byte(`["email:1", "createdAt:-1", "loc:2dsphere"]`), &fromJSON))
+	assert.Equal(t, fromYAML, fromJSON)
+	assert.Equal(t, "email_1_createdAt_-1_loc_2dsphere", fromYAML.DefaultName())
+
+	assert.Error(t, json.Unmarshal([]byte(`["email"]`), &fromJSON))
+}
+
+func TestValidateCollectionOptions(t *testing.T) {
+	span := int64(600)
+	ttl := int64(86400)
+	tests := []struct {
+		name    string
+		opts    CollectionOptions
+		wantErr string
+	}{
+		{"validator", CollectionOptions{Validator: map[string]interface{}{"a": 1}, ValidationLevel: ValidationLevelModerate}, ""},
+		{"time series", CollectionOptions{TimeSeries: &TimeSeriesOptions{TimeField: "ts", Granularity: "minutes"}, ExpireAfterSeconds: &ttl}, ""},
+		{"custom buckets", CollectionOptions{TimeSeries: &TimeSeriesOptions{TimeField: "ts", BucketMaxSpanSeconds: &span, BucketRoundingSeconds: &span}}, ""},
+		{"clustered", CollectionOptions{ClusteredIndex: &ClusteredIndexOptions{}, ExpireAfterSeconds: &ttl}, ""},
+		{"missing time field", CollectionOptions{TimeSeries: &TimeSeriesOptions{}}, "timeField is required"},
+		{"granularity and buckets", CollectionOptions{TimeSeries: &TimeSeriesOptions{TimeField: "ts", Granularity: "hours", BucketMaxSpanSeconds: &span, BucketRoundingSeconds: &span}}, "cannot be combined"},
+		{"capped without size", CollectionOptions{Capped: true}, "size"},
+		{"capped time series", CollectionOptions{Capped: true, Size: 1024, TimeSeries: &TimeSeriesOptions{TimeField: "ts"}}, "capped"},
+		{"expiry without clustering", CollectionOptions{ExpireAfterSeconds: &ttl}, "expireAfterSeconds"},
+		{"invalid level", CollectionOptions{ValidationLevel: "lenient"}, "validationLevel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCollectionOptions(&tt.opts)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	opts, err := ParseCollectionOptions(map[string]interface{}{
+		"timeseries":     map[string]interface{}{"timeField": "ts", "granularity": "seconds", "bucketMaxSpanSeconds": int32(3600)},
+		"clusteredIndex": true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ts", opts.TimeSeries.TimeField)
+	assert.Nil(t, opts.ClusteredIndex)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -59,6 +61,19 @@ func (p *ProgressIndicator) StopSpinnerWithError(errorMessage string) {
 	}
 }
 
+// UpdateProgress shows the percent complete of the running operation on the spinner line
+func (p *ProgressIndicator) UpdateProgress(message string, percent float64) {
+	if p.spinner == nil {
+		return
+	}
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	p.spinner.SetMessage(fmt.Sprintf("%s [%s] %.1f%%", message, renderBar(percent/100, 20), percent))
+}
+
 // Print prints a message respecting quiet/verbose settings
 func (p *ProgressIndicator) Print(message string) {
 	if !p.quiet {
@@ -76,6 +91,7 @@ func (p *ProgressIndicator) PrintVerbose(message string) {
 // Spinner provides a spinning animation for operations
 type Spinner struct {
 	output   io.Writer
+	mu       sync.Mutex
 	message  string
 	frames   []string
 	active   bool
@@ -92,6 +108,13 @@ func NewSpinner(output io.Writer, message string) *Spinner {
 	}
 }
 
+// SetMessage replaces the message shown next to the spinner
+func (s *Spinner) SetMessage(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = message
+}
+
 // Start begins the spinner animation
 func (s *Spinner) Start() {
 	if s.active {
@@ -142,7 +165,10 @@ func (s *Spinner) animate() {
 		case <-ticker.C:
 			if s.active {
 				frame := s.frames[frameIndex%len(s.frames)]
-				_, _ = fmt.Fprintf(s.output, "\r%s %s", frame, s.message)
+				s.mu.Lock()
+				message := s.message
+				s.mu.Unlock()
+				_, _ = fmt.Fprintf(s.output, "\r\033[K%s %s", frame, message)
 				frameIndex++
 			}
 		}
@@ -198,17 +224,12 @@ func (pb *ProgressBar) render() {
 		percent = 1
 	}
 
-	filled := int(percent * float64(pb.width))
-	bar := ""
-
-	for i := 0; i < pb.width; i++ {
-		if i < filled {
-			bar += "█"
-		} else {
-			bar += "░"
-		}
-	}
-
 	_, _ = fmt.Fprintf(pb.output, "\r%s [%s] %d/%d (%.1f%%)",
-		pb.prefix, bar, pb.current, pb.total, percent*100)
+		pb.prefix, renderBar(percent, pb.width), pb.current, pb.total, percent*100)
+}
+
+// renderBar draws a bar of width cells filled to fraction (0 to 1)
+func renderBar(fraction float64, width int) string {
+	filled := int(fraction * float64(width))
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
 }
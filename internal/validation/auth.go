@@ -0,0 +1,168 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Database user authentication types accepted in CLI flags and YAML (authType).
+const (
+	AuthTypeSCRAM         = "SCRAM"
+	AuthTypeX509Managed   = "X509_MANAGED"
+	AuthTypeX509Customer  = "X509_CUSTOMER"
+	AuthTypeLDAPUser      = "LDAP_USER"
+	AuthTypeLDAPGroup     = "LDAP_GROUP"
+	AuthTypeAWSIAMUser    = "AWS_IAM_USER"
+	AuthTypeAWSIAMRole    = "AWS_IAM_ROLE"
+	AuthTypeOIDCWorkforce = "OIDC_WORKFORCE"
+	AuthTypeOIDCWorkload  = "OIDC_WORKLOAD"
+)
+
+// ExternalAuthDatabase is the authentication database Atlas requires for externally authenticated users.
+const ExternalAuthDatabase = "$external"
+
+// ValidAuthTypes lists all supported database user authentication types.
+var ValidAuthTypes = []string{
+	AuthTypeSCRAM,
+	AuthTypeX509Managed,
+	AuthTypeX509Customer,
+	AuthTypeLDAPUser,
+	AuthTypeLDAPGroup,
+	AuthTypeAWSIAMUser,
+	AuthTypeAWSIAMRole,
+	AuthTypeOIDCWorkforce,
+	AuthTypeOIDCWorkload,
+}
+
+var (
+	// awsIAMUserARNRegex matches IAM user ARNs (arn:aws:iam::123456789012:user/name)
+	awsIAMUserARNRegex = regexp.MustCompile(`^arn:aws(-[a-z]+)*:iam::[0-9]{12}:user/.+$`)
+
+	// awsIAMRoleARNRegex matches IAM role ARNs (arn:aws:iam::123456789012:role/name)
+	awsIAMRoleARNRegex = regexp.MustCompile(`^arn:aws(-[a-z]+)*:iam::[0-9]{12}:role/.+$`)
+
+	// oidcUsernameRegex matches OIDC principals in the form <idp-id>/<name>
+	oidcUsernameRegex = regexp.MustCompile(`^[^/\s]+/[^\s]+$`)
+
+	// distinguishedNameRegex requires at least one attribute=value pair (RFC 2253)
+	distinguishedNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9.-]*=[^,]+(,\s*[A-Za-z][A-Za-z0-9.-]*=[^,]+)*$`)
+)
+
+// NormalizeAuthType returns the canonical auth type, treating an empty value as SCRAM.
+func NormalizeAuthType(authType string) string {
+	authType = strings.ToUpper(strings.TrimSpace(authType))
+	if authType == "" {
+		return AuthTypeSCRAM
+	}
+	return authType
+}
+
+// ValidateAuthType validates a database user authentication type. An empty value means SCRAM.
+func ValidateAuthType(authType string) error {
+	normalized := NormalizeAuthType(authType)
+	for _, allowed := range ValidAuthTypes {
+		if normalized == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("authType must be one of: %s", strings.Join(ValidAuthTypes, ", "))
+}
+
+// IsExternalAuthType reports whether the auth type authenticates outside of Atlas (no password).
+func IsExternalAuthType(authType string) bool {
+	return NormalizeAuthType(authType) != AuthTypeSCRAM
+}
+
+// AuthDatabaseForType returns the authentication database Atlas requires for the auth type.
+// LDAP groups and OIDC workforce groups are created in admin; other external users in $external.
+func AuthDatabaseForType(authType string) string {
+	switch NormalizeAuthType(authType) {
+	case AuthTypeSCRAM, AuthTypeLDAPGroup, AuthTypeOIDCWorkforce:
+		return "admin"
+	default:
+		return ExternalAuthDatabase
+	}
+}
+
+// ValidateDatabaseUserAuth validates the username, password and authentication database
+// of a database user against its authentication type.
+func ValidateDatabaseUserAuth(authType, username, password, authDatabase string) error {
+	if err := ValidateAuthType(authType); err != nil {
+		return err
+	}
+	normalized := NormalizeAuthType(authType)
+
+	if normalized != AuthTypeSCRAM && password != "" {
+		return fmt.Errorf("password is not supported for %s users", normalized)
+	}
+
+	if authDatabase != "" {
+		if expected := AuthDatabaseForType(normalized); authDatabase != expected {
+			return fmt.Errorf("%s users must use authentication database %q (got %q)", normalized, expected, authDatabase)
+		}
+	}
+
+	return ValidateUsernameForAuthType(username, normalized)
+}
+
+// ValidateUsernameForAuthType validates the username format expected by the auth type:
+// plain names for SCRAM and managed X.509, distinguished names for customer X.509 and LDAP,
+// IAM ARNs for AWS IAM and <idp-id>/<name> for OIDC.
+func ValidateUsernameForAuthType(username, authType string) error {
+	normalized := NormalizeAuthType(authType)
+	if strings.TrimSpace(username) == "" {
+		return fmt.Errorf("username cannot be empty")
+	}
+	if len(username) > 1024 {
+		return fmt.Errorf("username cannot exceed 1024 characters")
+	}
+
+	switch normalized {
+	case AuthTypeSCRAM, AuthTypeX509Managed:
+		return ValidateUsername(username)
+	case AuthTypeX509Customer, AuthTypeLDAPUser, AuthTypeLDAPGroup:
+		if err := ValidateDistinguishedName(username, normalized+" username"); err != nil {
+			return err
+		}
+	case AuthTypeAWSIAMUser:
+		if !awsIAMUserARNRegex.MatchString(username) {
+			return fmt.Errorf("AWS_IAM_USER username must be an IAM user ARN (e.g., arn:aws:iam::123456789012:user/app)")
+		}
+	case AuthTypeAWSIAMRole:
+		if !awsIAMRoleARNRegex.MatchString(username) {
+			return fmt.Errorf("AWS_IAM_ROLE username must be an IAM role ARN (e.g., arn:aws:iam::123456789012:role/app)")
+		}
+	case AuthTypeOIDCWorkforce, AuthTypeOIDCWorkload:
+		if !oidcUsernameRegex.MatchString(username) {
+			return fmt.Errorf("%s username must be in the form <identity-provider-id>/<name>", normalized)
+		}
+	}
+	return nil
+}
+
+// ValidateDatabaseUsername accepts a username in any format supported by one of the
+// auth types. Use it to look up existing users whose auth type is unknown.
+func ValidateDatabaseUsername(username string) error {
+	scramErr := ValidateUsername(username)
+	if scramErr == nil {
+		return nil
+	}
+	for _, authType := range ValidAuthTypes {
+		if ValidateUsernameForAuthType(username, authType) == nil {
+			return nil
+		}
+	}
+	return scramErr
+}
+
+// ValidateDistinguishedName validates an RFC 2253 distinguished name such as CN=app,DC=example,DC=com.
+func ValidateDistinguishedName(dn, fieldName string) error {
+	if strings.TrimSpace(dn) == "" {
+		return fmt.Errorf("%s cannot be empty", fieldName)
+	}
+	if !distinguishedNameRegex.MatchString(dn) {
+		return fmt.Errorf("%s must be a distinguished name (e.g., CN=app,OU=users,DC=example,DC=com)", fieldName)
+	}
+	return nil
+}
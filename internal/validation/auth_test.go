@@ -0,0 +1,113 @@
+package validation
+
+import "testing"
+
+func TestValidateAuthType(t *testing.T) {
+	tests := []struct {
+		name        string
+		authType    string
+		expectError bool
+	}{
+		{"empty defaults to SCRAM", "", false},
+		{"SCRAM", "SCRAM", false},
+		{"lowercase is normalized", "x509_managed", false},
+		{"X509 customer", "X509_CUSTOMER", false},
+		{"LDAP group", "LDAP_GROUP", false},
+		{"AWS IAM role", "AWS_IAM_ROLE", false},
+		{"OIDC workload", "OIDC_WORKLOAD", false},
+		{"unknown", "KERBEROS", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAuthType(tt.authType)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error for %s, got nil", tt.name)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error for %s, got %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestAuthDatabaseForType(t *testing.T) {
+	tests := map[string]string{
+		"":                    "admin",
+		AuthTypeSCRAM:         "admin",
+		AuthTypeX509Managed:   ExternalAuthDatabase,
+		AuthTypeX509Customer:  ExternalAuthDatabase,
+		AuthTypeLDAPUser:      ExternalAuthDatabase,
+		AuthTypeLDAPGroup:     "admin",
+		AuthTypeAWSIAMUser:    ExternalAuthDatabase,
+		AuthTypeAWSIAMRole:    ExternalAuthDatabase,
+		AuthTypeOIDCWorkforce: "admin",
+		AuthTypeOIDCWorkload:  ExternalAuthDatabase,
+	}
+
+	for authType, expected := range tests {
+		if got := AuthDatabaseForType(authType); got != expected {
+			t.Errorf("AuthDatabaseForType(%q) = %q, want %q", authType, got, expected)
+		}
+	}
+}
+
+func TestValidateDatabaseUserAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		authType     string
+		username     string
+		password     string
+		authDatabase string
+		expectError  bool
+	}{
+		{"SCRAM with password", "", "app-user", "s3cretpass", "admin", false},
+		{"SCRAM rejects DN username", "SCRAM", "CN=app,OU=users", "", "", true},
+		{"managed X509", "X509_MANAGED", "app-user", "", "$external", false},
+		{"X509 with password", "X509_MANAGED", "app-user", "s3cretpass", "", true},
+		{"X509 with admin database", "X509_MANAGED", "app-user", "", "admin", true},
+		{"customer X509 DN", "X509_CUSTOMER", "CN=app,OU=users,DC=example,DC=com", "", "", false},
+		{"customer X509 plain name", "X509_CUSTOMER", "app-user", "", "", true},
+		{"LDAP user DN", "LDAP_USER", "cn=jane,ou=people,dc=example,dc=com", "", "$external", false},
+		{"LDAP group DN in admin", "LDAP_GROUP", "cn=dbas,ou=groups,dc=example,dc=com", "", "admin", false},
+		{"LDAP group in $external", "LDAP_GROUP", "cn=dbas,ou=groups,dc=example,dc=com", "", "$external", true},
+		{"AWS IAM role ARN", "AWS_IAM_ROLE", "arn:aws:iam::123456789012:role/app", "", "", false},
+		{"AWS IAM role with user ARN", "AWS_IAM_ROLE", "arn:aws:iam::123456789012:user/app", "", "", true},
+		{"AWS IAM user ARN", "AWS_IAM_USER", "arn:aws:iam::123456789012:user/app", "", "", false},
+		{"OIDC workforce group", "OIDC_WORKFORCE", "0oa1b2c3d4/engineering", "", "admin", false},
+		{"OIDC workload without idp", "OIDC_WORKLOAD", "service-account", "", "", true},
+		{"unknown auth type", "KERBEROS", "app-user", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDatabaseUserAuth(tt.authType, tt.username, tt.password, tt.authDatabase)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error for %s, got nil", tt.name)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error for %s, got %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestValidateDatabaseUsername(t *testing.T) {
+	valid := []string{
+		"app-user",
+		"CN=app,OU=users,DC=example,DC=com",
+		"arn:aws:iam::123456789012:role/app",
+		"0oa1b2c3d4/engineering",
+	}
+	for _, username := range valid {
+		if err := ValidateDatabaseUsername(username); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", username, err)
+		}
+	}
+
+	for _, username := range []string{"", "bad user!"} {
+		if err := ValidateDatabaseUsername(username); err == nil {
+			t.Errorf("Expected %q to be invalid", username)
+		}
+	}
+}
@@ -409,8 +409,12 @@ func (dv *DependencyValidator) validateNetworkAccessDependencies(config *types.P
 	// Check for expiration dependencies
 	now := time.Now()
 	for i, netAccess := range config.NetworkAccess {
-		if netAccess.DeleteAfterDate != "" {
-			if expiry, err := time.Parse(time.RFC3339, netAccess.DeleteAfterDate); err == nil {
+		expiresAt := netAccess.DeleteAfterDate
+		if expiresAt == "" {
+			expiresAt = netAccess.ExpiresAt
+		}
+		if expiresAt != "" {
+			if expiry, err := time.Parse(time.RFC3339, expiresAt); err == nil {
 				if expiry.Before(now) {
 					issues = append(issues, DependencyIssue{
 						SourceResource: fmt.Sprintf("network:%d", i),